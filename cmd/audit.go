@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"k8s-hpa-manager/internal/audit"
+
+	"github.com/spf13/cobra"
+)
+
+// ExitAuditInvalid verify encontrou problemas na cadeia de hashes
+const ExitAuditInvalid = 6
+
+var (
+	auditConfigPath  string
+	auditCheckpoints []string
+	auditOutput      string
+	auditFormat      string
+	auditFile        string
+	auditSince       string
+	auditUntil       string
+	auditActor       string
+	auditSource      string
+	auditAction      string
+	auditCluster     string
+	auditResult      string
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Verificar, exportar e aplicar retenção no audit log de alterações",
+	Long: `O audit log registra toda operação mutável feita pela TUI, interface web, CLI e scheduler
+(quem, origem, cluster, recurso, antes/depois e resultado) em segmentos JSON Lines
+append-only com cadeia de hashes SHA-256.
+
+Configuração em ~/.k8s-hpa-manager/audit.yaml:
+  dir: ~/.k8s-hpa-manager/audit   # diretório dos segmentos
+  retention: 400d                 # remove segmentos mensais mais antigos ("0" = manter tudo)`,
+}
+
+var auditVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Recalcular a cadeia de hashes e apontar entradas alteradas, removidas ou reordenadas",
+	Long: `Confere hash, prev_hash e seq de cada entrada e o anchor da retenção.
+
+A cadeia sozinha não impede que alguém com acesso ao disco reescreva o log inteiro:
+guarde o "head" (seq:hash) impresso pelo verify fora do servidor (ex: no ticket da mudança)
+e informe-o depois com --checkpoint.
+
+Sai com código 6 quando encontra problemas.`,
+	Example: `  k8s-hpa-manager audit verify
+  k8s-hpa-manager audit verify --checkpoint 1532:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+  k8s-hpa-manager audit verify -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var checkpoints []audit.Checkpoint
+		for _, value := range auditCheckpoints {
+			checkpoint, err := audit.ParseCheckpoint(value)
+			if err != nil {
+				return err
+			}
+			checkpoints = append(checkpoints, checkpoint)
+		}
+
+		log, err := openAuditLog()
+		if err != nil {
+			return err
+		}
+		report, err := log.Verify(checkpoints...)
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		switch auditOutput {
+		case outputFormatJSON:
+			encoder := json.NewEncoder(out)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(report); err != nil {
+				return err
+			}
+		case outputFormatText:
+			printVerifyReport(out, log.Dir(), report)
+		default:
+			return fmt.Errorf("invalid output format %q (use text or json)", auditOutput)
+		}
+
+		if !report.Valid {
+			return &exitError{ExitAuditInvalid, fmt.Errorf("audit log has %d problem(s)", len(report.Problems))}
+		}
+		return nil
+	},
+}
+
+var auditExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exportar entradas do audit log em JSON Lines ou CSV",
+	Long: `Exporta as entradas em ordem da cadeia. Em JSON Lines as linhas são copiadas sem alteração,
+então uma exportação sem filtros pode ser verificada em outra máquina. CSV é para planilhas.
+
+Datas aceitam RFC3339 ou YYYY-MM-DD (--until inclui o dia inteiro).`,
+	Example: `  # Tudo do último trimestre em JSON Lines
+  k8s-hpa-manager audit export --since 2026-07-01 -o audit-q3.jsonl
+
+  # Falhas em um cluster, em CSV
+  k8s-hpa-manager audit export --format csv --cluster akspriv-prd --result failed -o falhas.csv`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := audit.ParseFormat(auditFormat)
+		if err != nil {
+			return err
+		}
+		filter := audit.Filter{
+			Actor:   auditActor,
+			Source:  audit.Source(auditSource),
+			Action:  auditAction,
+			Cluster: auditCluster,
+			Result:  audit.Result(auditResult),
+		}
+		if auditSince != "" {
+			if filter.Since, err = audit.ParseTime(auditSince, false); err != nil {
+				return fmt.Errorf("--since: %w", err)
+			}
+		}
+		if auditUntil != "" {
+			if filter.Until, err = audit.ParseTime(auditUntil, true); err != nil {
+				return fmt.Errorf("--until: %w", err)
+			}
+		}
+
+		log, err := openAuditLog()
+		if err != nil {
+			return err
+		}
+
+		var out io.Writer = cmd.OutOrStdout()
+		if auditFile != "" && auditFile != "-" {
+			file, err := os.Create(auditFile)
+			if err != nil {
+				return fmt.Errorf("falha ao criar %s: %w", auditFile, err)
+			}
+			defer file.Close()
+			out = file
+		}
+
+		count, err := log.Export(out, format, filter)
+		if err != nil {
+			return err
+		}
+		if out != cmd.OutOrStdout() {
+			fmt.Fprintf(cmd.ErrOrStderr(), "📄 %d entrada(s) exportada(s) para %s\n", count, auditFile)
+		}
+		return nil
+	},
+}
+
+var auditPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Aplicar a retenção configurada (o servidor web também aplica a cada 24h)",
+	Long: `Remove segmentos mensais mais antigos que a retenção de audit.yaml. A remoção é registrada
+na própria cadeia (entrada audit_prune) e em anchor.json, então o verify continua válido.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log, err := openAuditLog()
+		if err != nil {
+			return err
+		}
+		result, err := log.Prune(time.Now())
+		if err != nil {
+			return err
+		}
+		if len(result.Removed) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "Nenhum segmento fora da retenção")
+			return nil
+		}
+		for _, segment := range result.Removed {
+			fmt.Fprintf(cmd.OutOrStdout(), "🗑️  %s\n", segment)
+		}
+		if result.Anchor != nil {
+			fmt.Fprintf(cmd.OutOrStdout(), "⚓ Anchor: %s\n", audit.Checkpoint{Seq: result.Anchor.Seq, Hash: result.Anchor.Hash})
+		}
+		return nil
+	},
+}
+
+// openAuditLog abre o audit log descrito por --config (default ~/.k8s-hpa-manager/audit.yaml)
+func openAuditLog() (*audit.Log, error) {
+	path := auditConfigPath
+	if path == "" {
+		path = audit.DefaultConfigPath()
+	}
+	cfg, err := audit.LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return audit.Open(cfg)
+}
+
+func printVerifyReport(w io.Writer, dir string, report *audit.VerifyReport) {
+	fmt.Fprintf(w, "Diretório: %s\n", dir)
+	fmt.Fprintf(w, "Segmentos: %d | Entradas: %d\n", len(report.Segments), report.Entries)
+	if report.Anchor != nil {
+		fmt.Fprintf(w, "Anchor (retenção): %s\n", audit.Checkpoint{Seq: report.Anchor.Seq, Hash: report.Anchor.Hash})
+	}
+	if report.Entries > 0 {
+		fmt.Fprintf(w, "Head: %s\n", report.Head)
+	}
+	if report.Valid {
+		fmt.Fprintln(w, "✅ Cadeia íntegra")
+		return
+	}
+	fmt.Fprintf(w, "🚨 %d problema(s):\n", len(report.Problems))
+	for _, problem := range report.Problems {
+		fmt.Fprintf(w, "   ❌ %s\n", problem)
+	}
+}
+
+func init() {
+	auditCmd.PersistentFlags().StringVar(&auditConfigPath, "config", "",
+		"Audit configuration file (default: ~/.k8s-hpa-manager/audit.yaml)")
+
+	auditVerifyCmd.Flags().StringArrayVar(&auditCheckpoints, "checkpoint", nil,
+		"Expected <seq>:<hash> recorded outside the server (repeatable)")
+	auditVerifyCmd.Flags().StringVarP(&auditOutput, "output", "o", outputFormatText,
+		"Output format: text or json")
+
+	auditExportCmd.Flags().StringVarP(&auditFormat, "format", "f", string(audit.FormatJSONL),
+		"Export format: jsonl or csv")
+	auditExportCmd.Flags().StringVarP(&auditFile, "output", "o", "",
+		"Output file (default: stdout)")
+	auditExportCmd.Flags().StringVar(&auditSince, "since", "", "Only entries at or after this time")
+	auditExportCmd.Flags().StringVar(&auditUntil, "until", "", "Only entries before this time (dates include the whole day)")
+	auditExportCmd.Flags().StringVar(&auditActor, "actor", "", "Filter by actor")
+	auditExportCmd.Flags().StringVar(&auditSource, "source", "", "Filter by source: tui, web, cli, scheduler or system")
+	auditExportCmd.Flags().StringVar(&auditAction, "action", "", "Filter by action (e.g. update_hpa, apply_session)")
+	auditExportCmd.Flags().StringVar(&auditCluster, "cluster", "", "Filter by cluster")
+	auditExportCmd.Flags().StringVar(&auditResult, "result", "", "Filter by result: success, failed or denied")
+
+	for _, sub := range []*cobra.Command{auditVerifyCmd, auditExportCmd, auditPruneCmd} {
+		sub.SilenceUsage = true
+		sub.SilenceErrors = true // main.go imprime o erro e define o código de saída
+		auditCmd.AddCommand(sub)
+	}
+	rootCmd.AddCommand(auditCmd)
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"k8s-hpa-manager/internal/web/auth/oidctest"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	mockIdPAddr     string
+	mockIdPClientID string
+	mockIdPUser     string
+	mockIdPGroups   []string
+)
+
+var mockIdPCmd = &cobra.Command{
+	Use:   "mock-idp",
+	Short: "IdP OIDC local para testar o login do servidor web",
+	Long: `Inicia um provedor OpenID Connect mínimo que aprova automaticamente o usuário
+informado. Serve para testar o login OIDC e os papéis do servidor web sem um IdP real.
+
+Não use em produção: qualquer pessoa com acesso à porta obtém um login válido.`,
+	Example: `  # Terminal 1: IdP com usuário do grupo sre
+  k8s-hpa-manager mock-idp --user ops@empresa.com --groups sre
+
+  # Terminal 2: servidor web com ~/.k8s-hpa-manager/auth.yaml apontando para o IdP
+  k8s-hpa-manager web -f`,
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		user := oidctest.User{
+			Subject:           mockIdPUser,
+			Email:             mockIdPUser,
+			PreferredUsername: strings.SplitN(mockIdPUser, "@", 2)[0],
+			Groups:            mockIdPGroups,
+		}
+		idp, err := oidctest.Listen(mockIdPAddr, mockIdPClientID, user)
+		if err != nil {
+			return fmt.Errorf("falha ao iniciar mock IdP em %s: %w", mockIdPAddr, err)
+		}
+		defer idp.Close()
+
+		fmt.Printf("🔐 Mock IdP em %s (usuário %s, grupos: %s)\n", idp.Issuer(), mockIdPUser, strings.Join(mockIdPGroups, ", "))
+		fmt.Println("📝 Exemplo de ~/.k8s-hpa-manager/auth.yaml:")
+		fmt.Printf("   oidc:\n     issuer: %s\n     client_id: %s\n     redirect_url: http://localhost:%d/auth/callback\n",
+			idp.Issuer(), mockIdPClientID, webPort)
+		fmt.Println("   bindings:")
+		fmt.Println("     - {subjects: [\"group:sre\"], role: admin}")
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		<-sigChan
+		return nil
+	},
+}
+
+func init() {
+	mockIdPCmd.Flags().StringVar(&mockIdPAddr, "addr", "127.0.0.1:5556", "Listen address")
+	mockIdPCmd.Flags().StringVar(&mockIdPClientID, "client-id", "k8s-hpa-manager", "OIDC client_id accepted by the IdP")
+	mockIdPCmd.Flags().StringVar(&mockIdPUser, "user", "dev@localhost", "User (email) approved on every login")
+	mockIdPCmd.Flags().StringSliceVar(&mockIdPGroups, "groups", []string{"sre"}, "Groups claim of the user")
+	rootCmd.AddCommand(mockIdPCmd)
+}
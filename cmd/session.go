@@ -0,0 +1,510 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"k8s-hpa-manager/internal/audit"
+	"k8s-hpa-manager/internal/azure"
+	"k8s-hpa-manager/internal/config"
+	kubeclient "k8s-hpa-manager/internal/kubernetes"
+	"k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/nodepool"
+	"k8s-hpa-manager/internal/session"
+
+	"github.com/spf13/cobra"
+)
+
+// Códigos de saída dos subcomandos de sessão
+const (
+	ExitOK           = 0
+	ExitError        = 1 // erro de uso, sessão não encontrada, kubeconfig inválido
+	ExitPlanInvalid  = 2 // dry-run encontrou itens inválidos
+	ExitApplyFailed  = 3 // um ou mais itens falharam na aplicação/rollback
+	ExitAborted      = 4 // confirmação negada ou ausente
+	ExitConflict     = 5 // itens alterados no cluster desde que a sessão foi salva, sem decisão
+	outputFormatJSON = "json"
+	outputFormatText = "text"
+)
+
+var (
+	sessionFolder            string
+	sessionAssumeYes         bool
+	sessionOutput            string
+	sessionRollbackOnFailure bool
+	sessionOnConflict        string
+	sessionResolve           []string
+	sessionInverseName       string
+	sessionInverseFolder     string
+	sessionInverseRefresh    bool
+)
+
+// exitError erro com código de saída específico
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// ExitCode retorna o código de saída associado ao erro (1 para erros genéricos)
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return ExitError
+}
+
+var sessionCmd = &cobra.Command{
+	Use:   "session",
+	Short: "Planejar, aplicar e desfazer sessões salvas sem interface interativa",
+	Long: `Executa sessões salvas (as mesmas criadas na TUI e na interface web) de forma headless,
+para uso em runbooks e pipelines de janela de mudança.
+
+Códigos de saída:
+  0  sucesso
+  1  erro (sessão não encontrada, kubeconfig inválido, flags inválidas)
+  2  plano inválido (dry-run rejeitado)
+  3  falha ao aplicar ou desfazer um ou mais itens
+  4  operação abortada (confirmação negada)
+  5  conflito: itens alterados no cluster desde que a sessão foi salva
+     (use --on-conflict ou --resolve para decidir skip, force ou rebase)`,
+	Example: `  # Dry-run de uma sessão
+  k8s-hpa-manager session plan black-friday --folder HPA-Upscale
+
+  # Aplicar sem confirmação, com saída JSON
+  k8s-hpa-manager session apply black-friday --folder HPA-Upscale --yes -o json
+
+  # Sessão antiga: manter alterações feitas no cluster nos campos que a sessão não muda
+  k8s-hpa-manager session apply black-friday --on-conflict rebase --resolve hpa:aks-prd-admin/api/web=skip
+
+  # Desfazer (aplicar os valores originais)
+  k8s-hpa-manager session rollback black-friday --yes
+
+  # Gerar a sessão de downscale correspondente (salva em HPA-Downscale)
+  k8s-hpa-manager session inverse black-friday --folder HPA-Upscale --refresh`,
+}
+
+var sessionPlanCmd = &cobra.Command{
+	Use:   "plan <name>",
+	Short: "Executar dry-run de uma sessão (server-side dry-run + validação de node pools)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := sessionContext()
+		defer cancel()
+
+		sess, _, applier, _, err := loadSessionForCLI(args[0])
+		if err != nil {
+			return err
+		}
+
+		plan := applier.Plan(ctx, sess)
+		if err := writeSessionOutput(cmd.OutOrStdout(), plan, func(w io.Writer) { printPlan(w, plan) }); err != nil {
+			return err
+		}
+
+		if !plan.Valid {
+			return &exitError{ExitPlanInvalid, fmt.Errorf("plan for session %s is invalid", sess.Name)}
+		}
+		return nil
+	},
+}
+
+var sessionApplyCmd = &cobra.Command{
+	Use:   "apply <name>",
+	Short: "Aplicar uma sessão (plano + confirmação + aplicação ordenada)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := sessionContext()
+		defer cancel()
+
+		sess, folder, applier, manager, err := loadSessionForCLI(args[0])
+		if err != nil {
+			return err
+		}
+
+		opts, err := cliApplyOptions()
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		plan := applier.Plan(ctx, sess)
+		if !plan.Valid {
+			if err := writeSessionOutput(out, map[string]interface{}{"plan": plan}, func(w io.Writer) { printPlan(w, plan) }); err != nil {
+				return err
+			}
+			return &exitError{ExitPlanInvalid, fmt.Errorf("plan for session %s is invalid, nothing applied", sess.Name)}
+		}
+
+		if unresolved := unresolvedConflicts(plan, opts); len(unresolved) > 0 {
+			if err := writeSessionOutput(out, map[string]interface{}{"plan": plan}, func(w io.Writer) { printPlan(w, plan) }); err != nil {
+				return err
+			}
+			return &exitError{ExitConflict, fmt.Errorf("%d items changed in the cluster since session %s was saved, nothing applied: %s",
+				len(unresolved), sess.Name, strings.Join(unresolved, ", "))}
+		}
+
+		if sessionOutput != outputFormatJSON {
+			printPlan(out, plan)
+		}
+		if err := confirmSession(cmd, fmt.Sprintf("Aplicar a sessão %s?", sess.Name)); err != nil {
+			return err
+		}
+
+		result := applier.Apply(ctx, sess, opts)
+		if err := manager.SaveSessionToFolder(sess, folder); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Falha ao salvar estado da sessão %s: %v\n", sess.Name, err)
+		}
+
+		if err := writeSessionOutput(out, map[string]interface{}{"plan": plan, "result": result}, func(w io.Writer) { printResult(w, result) }); err != nil {
+			return err
+		}
+		if result.Conflicts > 0 {
+			return &exitError{ExitConflict, result.Err()}
+		}
+		if err := result.Err(); err != nil {
+			return &exitError{ExitApplyFailed, err}
+		}
+		return nil
+	},
+}
+
+// cliApplyOptions monta as opções de aplicação a partir das flags
+func cliApplyOptions() (session.ApplyOptions, error) {
+	opts := session.ApplyOptions{RollbackOnFailure: sessionRollbackOnFailure}
+	if sessionOnConflict != "" {
+		resolution, err := session.ParseConflictResolution(sessionOnConflict)
+		if err != nil {
+			return opts, err
+		}
+		opts.DefaultResolution = resolution
+	}
+	for _, entry := range sessionResolve {
+		i := strings.LastIndex(entry, "=")
+		if i <= 0 {
+			return opts, fmt.Errorf("invalid --resolve %q: use <item-key>=skip|force|rebase", entry)
+		}
+		resolution, err := session.ParseConflictResolution(entry[i+1:])
+		if err != nil {
+			return opts, err
+		}
+		if opts.Resolutions == nil {
+			opts.Resolutions = make(map[string]session.ConflictResolution)
+		}
+		opts.Resolutions[entry[:i]] = resolution
+	}
+	return opts, nil
+}
+
+// unresolvedConflicts retorna as chaves dos itens em conflito sem decisão
+func unresolvedConflicts(plan *session.Plan, opts session.ApplyOptions) []string {
+	var keys []string
+	for _, item := range plan.Items {
+		if item.Conflict != nil && opts.Resolutions[item.Key()] == "" && opts.DefaultResolution == "" {
+			keys = append(keys, item.Key())
+		}
+	}
+	return keys
+}
+
+var sessionRollbackCmd = &cobra.Command{
+	Use:   "rollback <name>",
+	Short: "Desfazer uma sessão aplicando os valores originais dos itens aplicados",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := sessionContext()
+		defer cancel()
+
+		sess, folder, applier, manager, err := loadSessionForCLI(args[0])
+		if err != nil {
+			return err
+		}
+
+		opts, err := cliApplyOptions()
+		if err != nil {
+			return err
+		}
+
+		if err := confirmSession(cmd, fmt.Sprintf("Desfazer a sessão %s (aplicar valores originais)?", sess.Name)); err != nil {
+			return err
+		}
+
+		result, err := applier.Rollback(ctx, sess, opts)
+		if err != nil {
+			return err
+		}
+		if err := manager.SaveSessionToFolder(sess, folder); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Falha ao salvar estado da sessão %s: %v\n", sess.Name, err)
+		}
+
+		if err := writeSessionOutput(cmd.OutOrStdout(), result, func(w io.Writer) { printResult(w, result) }); err != nil {
+			return err
+		}
+		if result.Conflicts > 0 {
+			return &exitError{ExitConflict, result.Err()}
+		}
+		if err := result.Err(); err != nil {
+			return &exitError{ExitApplyFailed, err}
+		}
+		return nil
+	},
+}
+
+var sessionInverseCmd = &cobra.Command{
+	Use:   "inverse <name>",
+	Short: "Gerar a sessão inversa (valores originais e novos trocados) na pasta oposta ou em Rollback",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := sessionContext()
+		defer cancel()
+
+		sess, folder, applier, manager, err := loadSessionForCLI(args[0])
+		if err != nil {
+			return err
+		}
+
+		opts := session.InverseOptions{Name: sessionInverseName, Folder: session.SessionFolder(sessionInverseFolder)}
+		if opts.Folder != "" && !validSessionFolder(manager, opts.Folder) {
+			return fmt.Errorf("invalid folder %q", sessionInverseFolder)
+		}
+		if sessionInverseRefresh {
+			opts.Live = applier
+		}
+
+		inverse, targetFolder, err := manager.GenerateInverseSession(ctx, sess, folder, opts)
+		if err != nil {
+			return err
+		}
+
+		return writeSessionOutput(cmd.OutOrStdout(), inverse, func(w io.Writer) {
+			fmt.Fprintf(w, "🔄 Sessão inversa de %s salva: %s/%s (%d itens)\n",
+				sess.Name, targetFolder, inverse.Name, inverse.Metadata.TotalChanges)
+		})
+	},
+}
+
+// sessionContext cria um contexto cancelado por SIGINT/SIGTERM
+func sessionContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// loadSessionForCLI carrega a sessão e monta o Applier com kubeconfig e provider de node pools
+func loadSessionForCLI(name string) (*models.Session, session.SessionFolder, *session.Applier, *session.Manager, error) {
+	if sessionOutput != outputFormatText && sessionOutput != outputFormatJSON {
+		return nil, "", nil, nil, fmt.Errorf("invalid output format %q (use text or json)", sessionOutput)
+	}
+
+	manager, err := session.NewManager()
+	if err != nil {
+		return nil, "", nil, nil, fmt.Errorf("failed to initialize session manager: %w", err)
+	}
+
+	var folder session.SessionFolder
+	if sessionFolder != "" {
+		folder = session.SessionFolder(sessionFolder)
+		if !validSessionFolder(manager, folder) {
+			return nil, "", nil, nil, fmt.Errorf("invalid folder %q", sessionFolder)
+		}
+	}
+
+	sess, foundFolder, err := manager.FindSession(name, folder)
+	if err != nil {
+		return nil, "", nil, nil, err
+	}
+
+	kubeManager, err := config.NewKubeConfigManager(kubeconfig)
+	if err != nil {
+		return nil, "", nil, nil, fmt.Errorf("erro ao carregar kubeconfig: %w", err)
+	}
+
+	applier := session.NewApplier(session.ApplierOptions{
+		Clients: func(cluster string) (session.KubeClient, error) {
+			client, err := kubeManager.GetClient(cluster)
+			if err != nil {
+				return nil, err
+			}
+			return kubeclient.NewClient(client, cluster), nil
+		},
+		NodePools: nodepool.NewDefaultRegistry(azure.NewAuthManager()),
+		ResolveCluster: func(change *models.NodePoolChange) nodepool.ClusterRef {
+			if cfg, ok := kubeManager.FindClusterConfig(change.Cluster); ok {
+				return nodepool.ClusterRefFromConfig(&models.ClusterConfig{
+					ClusterName:   cfg.Name,
+					ResourceGroup: cfg.ResourceGroup,
+					Subscription:  cfg.Subscription,
+					Provider:      cfg.Provider,
+					Region:        cfg.Region,
+					Project:       cfg.Project,
+				})
+			}
+			return nodepool.ClusterRef{
+				Provider:      nodepool.ProviderAKS,
+				Name:          strings.TrimSuffix(change.Cluster, "-admin"),
+				ResourceGroup: change.ResourceGroup,
+				Subscription:  change.Subscription,
+			}
+		},
+		Manager: manager,
+		Progress: func(item session.ItemResult) {
+			// Progresso sempre em stderr para não misturar com a saída JSON
+			fmt.Fprintf(os.Stderr, "%s %s\n", statusIcon(item.Status), item.String())
+		},
+		Audit: audit.NewRecorder(audit.Default(), audit.SourceCLI, audit.LocalActor()),
+	})
+
+	return sess, foundFolder, applier, manager, nil
+}
+
+func validSessionFolder(manager *session.Manager, folder session.SessionFolder) bool {
+	for _, candidate := range manager.ListSessionFolders() {
+		if candidate == folder {
+			return true
+		}
+	}
+	return false
+}
+
+// confirmSession pede confirmação no terminal (ignorado com --yes)
+func confirmSession(cmd *cobra.Command, question string) error {
+	if sessionAssumeYes {
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", question)
+	answer, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && answer == "" {
+		return &exitError{ExitAborted, errors.New("confirmation required (use --yes for non-interactive runs)")}
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes", "s", "sim":
+		return nil
+	}
+	return &exitError{ExitAborted, errors.New("aborted by user")}
+}
+
+// writeSessionOutput escreve a saída em JSON ou no formato texto
+func writeSessionOutput(w io.Writer, data interface{}, text func(io.Writer)) error {
+	if sessionOutput == outputFormatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	}
+	text(w)
+	return nil
+}
+
+func printPlan(w io.Writer, plan *session.Plan) {
+	fmt.Fprintf(w, "📋 Plano da sessão %s\n", plan.Session)
+	for _, item := range plan.Items {
+		fmt.Fprintf(w, "  %s [%s] %s\n", statusIcon(item.Status), item.Status, item.String())
+		for _, change := range item.Changes {
+			fmt.Fprintf(w, "      • %s\n", change)
+		}
+		if item.Error != "" {
+			fmt.Fprintf(w, "      ❌ %s\n", item.Error)
+		}
+		if item.Conflict != nil {
+			printConflict(w, item.Conflict)
+		}
+	}
+	if plan.Conflicts > 0 {
+		fmt.Fprintf(w, "⚠️  %d itens alterados no cluster desde que a sessão foi salva\n", plan.Conflicts)
+	}
+	if plan.Valid {
+		fmt.Fprintln(w, "✅ Plano válido")
+	} else {
+		fmt.Fprintln(w, "❌ Plano inválido")
+	}
+}
+
+func printResult(w io.Writer, result *session.Result) {
+	fmt.Fprintf(w, "\n📊 Sessão %s: %d aplicados, %d falhas (%s)\n", result.Session, result.Applied, result.Failed, result.Duration)
+	for _, item := range result.Items {
+		if item.Status == session.StatusFailed || item.Status == session.StatusRollbackFailed {
+			fmt.Fprintf(w, "  ❌ %s: %s\n", item.String(), item.Error)
+		}
+	}
+	if result.RolledBack {
+		fmt.Fprintln(w, "↩️  Itens aplicados foram revertidos")
+	}
+	if result.RollbackSession != "" {
+		fmt.Fprintf(w, "💾 Sessão de rollback salva: %s/%s\n", session.FolderRollback, result.RollbackSession)
+	}
+}
+
+// printConflict imprime o conflito de três vias de um item
+func printConflict(w io.Writer, conflict *session.Conflict) {
+	fmt.Fprintf(w, "      ⚠️  conflito (%s)", conflict.Key)
+	if conflict.Resolution != "" {
+		fmt.Fprintf(w, " → %s", conflict.Resolution)
+	}
+	fmt.Fprintln(w)
+	for _, field := range conflict.Fields {
+		fmt.Fprintf(w, "         %s: sessão %s | cluster %s | alvo %s\n", field.Field, field.Original, field.Live, field.Target)
+	}
+}
+
+func statusIcon(status session.ItemStatus) string {
+	switch status {
+	case session.StatusPlanned:
+		return "📝"
+	case session.StatusApplied:
+		return "✅"
+	case session.StatusSkipped:
+		return "⏭️"
+	case session.StatusRolledBack:
+		return "↩️"
+	case session.StatusConflict:
+		return "⚠️"
+	default:
+		return "❌"
+	}
+}
+
+func init() {
+	sessionCmd.PersistentFlags().StringVar(&sessionFolder, "folder", "",
+		"Session folder (HPA-Upscale, HPA-Downscale, Node-Upscale, Node-Downscale, Rollback); default: search all")
+	sessionCmd.PersistentFlags().StringVarP(&sessionOutput, "output", "o", outputFormatText,
+		"Output format: text or json")
+	sessionCmd.PersistentFlags().BoolVarP(&sessionAssumeYes, "yes", "y", false,
+		"Skip confirmation prompt")
+	sessionApplyCmd.Flags().BoolVar(&sessionRollbackOnFailure, "rollback-on-failure", false,
+		"Stop on the first failure and roll back already applied items")
+	sessionApplyCmd.Flags().StringVar(&sessionOnConflict, "on-conflict", "",
+		"Resolution for items changed in the cluster since the session was saved: skip, force or rebase")
+	sessionApplyCmd.Flags().StringArrayVar(&sessionResolve, "resolve", nil,
+		"Per-item conflict resolution as <item-key>=skip|force|rebase (repeatable, overrides --on-conflict)")
+	sessionRollbackCmd.Flags().StringVar(&sessionOnConflict, "on-conflict", "",
+		"Resolution for items changed in the cluster since the session was applied: skip, force or rebase")
+	sessionRollbackCmd.Flags().StringArrayVar(&sessionResolve, "resolve", nil,
+		"Per-item conflict resolution as <item-key>=skip|force|rebase (repeatable, overrides --on-conflict)")
+
+	sessionInverseCmd.Flags().StringVar(&sessionInverseName, "name", "",
+		"Name of the inverse session (default: Inverse_<name>_<timestamp>)")
+	sessionInverseCmd.Flags().StringVar(&sessionInverseFolder, "to", "",
+		"Target folder (default: opposite folder, e.g. HPA-Upscale -> HPA-Downscale, otherwise Rollback)")
+	sessionInverseCmd.Flags().BoolVar(&sessionInverseRefresh, "refresh", false,
+		"Read the current values from the cluster instead of assuming the session was applied")
+
+	for _, sub := range []*cobra.Command{sessionPlanCmd, sessionApplyCmd, sessionRollbackCmd, sessionInverseCmd} {
+		sub.SilenceUsage = true
+		sub.SilenceErrors = true // main.go imprime o erro e define o código de saída
+		sessionCmd.AddCommand(sub)
+	}
+	rootCmd.AddCommand(sessionCmd)
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"k8s-hpa-manager/internal/monitoring/report"
+	"k8s-hpa-manager/internal/monitoring/storage"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	stressReportFormat  string
+	stressReportOutput  string
+	stressReportSection string
+	stressReportDB      string
+)
+
+var stressReportCmd = &cobra.Command{
+	Use:   "stress-report [test-id]",
+	Short: "Exportar relatório de stress test (HTML, Markdown, JSON ou CSV)",
+	Long: `Gera o relatório de um stress test concluído a partir do banco de monitoramento
+(~/.k8s-hpa-manager/monitoring.db), sem precisar do servidor web.
+
+Sem test-id lista os testes salvos. O test-id aceita prefixo único (ex: os 8 primeiros caracteres).
+
+Formatos:
+  html      página autocontida com gráficos inline (default)
+  markdown  tabelas GFM para wikis
+  json      relatório completo estruturado
+  csv       uma tabela por vez (--section hpas, series, issues, timeline, recommendations)`,
+	Example: `  # Listar testes
+  k8s-hpa-manager stress-report
+
+  # HTML para compartilhar
+  k8s-hpa-manager stress-report 3f2a9c1e -o relatorio.html
+
+  # Markdown no stdout e série temporal em CSV
+  k8s-hpa-manager stress-report 3f2a9c1e --format md
+  k8s-hpa-manager stress-report 3f2a9c1e --format csv --section series -o series.csv`,
+	Args:          cobra.MaximumNArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true, // main.go imprime o erro e define o código de saída
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := report.ParseFormat(stressReportFormat)
+		if err != nil {
+			return err
+		}
+		section, err := report.ParseCSVSection(stressReportSection)
+		if err != nil {
+			return err
+		}
+
+		config := storage.DefaultPersistenceConfig()
+		config.AutoCleanup = false // somente leitura
+		if stressReportDB != "" {
+			config.DBPath = stressReportDB
+		}
+		if _, err := os.Stat(config.DBPath); err != nil {
+			return fmt.Errorf("banco de monitoramento não encontrado em %s: %w", config.DBPath, err)
+		}
+		persistence, err := storage.NewPersistence(config)
+		if err != nil {
+			return err
+		}
+		defer persistence.Close()
+
+		tests, err := persistence.ListStressTests()
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			printStressTests(cmd.OutOrStdout(), tests)
+			return nil
+		}
+
+		testID, err := resolveStressTestID(args[0], tests)
+		if err != nil {
+			return err
+		}
+		rep, err := report.Load(persistence, testID)
+		if err != nil {
+			return err
+		}
+
+		var out io.Writer = cmd.OutOrStdout()
+		if stressReportOutput != "" && stressReportOutput != "-" {
+			file, err := os.Create(stressReportOutput)
+			if err != nil {
+				return fmt.Errorf("falha ao criar %s: %w", stressReportOutput, err)
+			}
+			defer file.Close()
+			out = file
+		}
+
+		if format == report.FormatCSV {
+			err = report.RenderCSV(out, rep, section)
+		} else {
+			err = report.Render(out, rep, format)
+		}
+		if err != nil {
+			return err
+		}
+		if out != cmd.OutOrStdout() {
+			fmt.Fprintf(cmd.ErrOrStderr(), "📄 Relatório salvo em %s\n", stressReportOutput)
+		}
+		return nil
+	},
+}
+
+// resolveStressTestID aceita o ID completo ou um prefixo único dos testes listados
+func resolveStressTestID(id string, tests []map[string]interface{}) (string, error) {
+	var matches []string
+	for _, test := range tests {
+		testID, _ := test["test_id"].(string)
+		if testID == id {
+			return id, nil
+		}
+		if strings.HasPrefix(testID, id) {
+			matches = append(matches, testID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		// Testes fora dos 50 mais recentes só com ID completo
+		return id, nil
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("prefixo %q ambíguo: %s", id, strings.Join(matches, ", "))
+}
+
+func printStressTests(w io.Writer, tests []map[string]interface{}) {
+	if len(tests) == 0 {
+		fmt.Fprintln(w, "Nenhum stress test salvo")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TEST ID\tNOME\tINÍCIO\tDURAÇÃO\tSTATUS\tRESULTADO")
+	for _, test := range tests {
+		start, _ := test["start_time"].(time.Time)
+		end, _ := test["end_time"].(time.Time)
+		duration := "-"
+		if !end.IsZero() {
+			duration = end.Sub(start).Round(time.Second).String()
+		}
+		result, _ := test["test_result"].(string)
+		if result == "" {
+			result = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", test["test_id"], test["test_name"],
+			start.Local().Format("2006-01-02 15:04"), duration, test["status"], result)
+	}
+	tw.Flush()
+}
+
+func init() {
+	stressReportCmd.Flags().StringVarP(&stressReportFormat, "format", "f", string(report.FormatHTML),
+		"Report format: html, markdown (md), json or csv")
+	stressReportCmd.Flags().StringVarP(&stressReportOutput, "output", "o", "",
+		"Output file (default: stdout)")
+	stressReportCmd.Flags().StringVar(&stressReportSection, "section", string(report.SectionHPAs),
+		"CSV table: hpas, series, issues, timeline or recommendations")
+	stressReportCmd.Flags().StringVar(&stressReportDB, "db", "",
+		"Monitoring database path (default: ~/.k8s-hpa-manager/monitoring.db)")
+	rootCmd.AddCommand(stressReportCmd)
+}
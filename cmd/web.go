@@ -10,6 +10,8 @@ import (
 	"syscall"
 	"time"
 
+	"k8s-hpa-manager/internal/azure"
+	"k8s-hpa-manager/internal/config"
 	"k8s-hpa-manager/internal/web"
 
 	"github.com/spf13/cobra"
@@ -19,6 +21,16 @@ var (
 	webPort    int
 	noBrowser  bool
 	foreground bool
+
+	// Modo servidor (Deployment no cluster)
+	inCluster                 bool
+	clusterContext            string
+	kubeconfigDir             string
+	kubeconfigSecrets         string
+	kubeconfigSecretNamespace string
+	workloadIdentity          bool
+	shutdownDelay             time.Duration
+	shutdownTimeout           time.Duration
 )
 
 // runInBackground executes the web server as a background process
@@ -157,6 +169,18 @@ Example usage:
   # Foreground with debug logging
   k8s-hpa-manager web --debug -f
 
+  # Shared always-on instance running as a Deployment (implies --foreground and --no-browser)
+  k8s-hpa-manager web --in-cluster --cluster-context akspriv-mgmt-admin \
+    --kubeconfig-dir /etc/k8s-hpa-manager/kubeconfigs \
+    --kubeconfig-secrets k8s-hpa-manager.io/kubeconfig=true
+
+In-cluster mode:
+  The home cluster uses the pod's ServiceAccount. Remote clusters come from kubeconfig
+  files in --kubeconfig-dir and/or Secrets matching --kubeconfig-secrets (key "kubeconfig",
+  "value" or "config"). With Azure workload identity (AZURE_FEDERATED_TOKEN_FILE set by the
+  webhook), kubelogin users are switched to --login workloadidentity. There is no inactivity
+  shutdown; Kubernetes probes use /livez and /readyz, and SIGTERM drains in-flight requests.
+
 Authentication:
   Set K8S_HPA_WEB_TOKEN environment variable to define your access token.
   If not set, a default token 'poc-token-123' will be used.
@@ -166,6 +190,7 @@ Authentication:
 
 API Endpoints:
   GET  /health                          - Health check (no auth)
+  GET  /livez, /readyz                  - Liveness and readiness probes (no auth)
   GET  /api/v1/clusters                 - List clusters
   GET  /api/v1/clusters/:name/test      - Test cluster connection
   GET  /api/v1/namespaces?cluster=X     - List namespaces
@@ -173,13 +198,36 @@ API Endpoints:
   PUT  /api/v1/hpas/:cluster/:ns/:name  - Update HPA
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		// Modo servidor: processo principal do container, sem navegador
+		if inCluster {
+			foreground = true
+			noBrowser = true
+		}
+
 		// Se não for foreground, executar em background
 		if !foreground {
 			return runInBackground()
 		}
 
+		opts := web.Options{
+			Kubeconfig:      kubeconfig,
+			Port:            webPort,
+			Debug:           debug,
+			ShutdownDelay:   shutdownDelay,
+			ShutdownTimeout: shutdownTimeout,
+		}
+		if inCluster {
+			opts.InCluster = &config.InClusterOptions{
+				Context:          clusterContext,
+				KubeconfigDir:    kubeconfigDir,
+				SecretSelector:   kubeconfigSecrets,
+				SecretNamespace:  kubeconfigSecretNamespace,
+				WorkloadIdentity: workloadIdentity,
+			}
+		}
+
 		// Criar servidor web
-		server, err := web.NewServer(kubeconfig, webPort, debug)
+		server, err := web.NewServerWithOptions(opts)
 		if err != nil {
 			return fmt.Errorf("failed to create web server: %w", err)
 		}
@@ -206,22 +254,23 @@ API Endpoints:
 			}()
 		}
 
-		// Goroutine para capturar sinais e fazer graceful shutdown
+		// Iniciar servidor; ao receber um sinal, o graceful shutdown drena as requisições
+		// e termina antes de o processo sair
+		errChan := make(chan error, 1)
 		go func() {
-			sig := <-sigChan
+			errChan <- server.Start()
+		}()
+
+		select {
+		case err := <-errChan:
+			return err
+		case sig := <-sigChan:
 			fmt.Printf("\n⚠️  Recebido sinal: %v\n", sig)
-			
-			// Fazer graceful shutdown
 			if err := server.Shutdown(); err != nil {
-				fmt.Printf("❌ Erro durante shutdown: %v\n", err)
-				os.Exit(1)
+				return fmt.Errorf("erro durante shutdown: %w", err)
 			}
-			
-			os.Exit(0)
-		}()
-
-		// Iniciar servidor (bloqueante)
-		return server.Start()
+			return nil
+		}
 	},
 }
 
@@ -233,4 +282,22 @@ func init() {
 	webCmd.Flags().IntVar(&webPort, "port", 8080, "Port for web server")
 	webCmd.Flags().BoolVar(&noBrowser, "no-browser", false, "Don't open browser automatically")
 	webCmd.Flags().BoolVarP(&foreground, "foreground", "f", false, "Run server in foreground (default: background)")
+
+	// Modo servidor
+	webCmd.Flags().BoolVar(&inCluster, "in-cluster", false,
+		"Run as an in-cluster Deployment: ServiceAccount credentials, no inactivity shutdown, probes at /livez and /readyz")
+	webCmd.Flags().StringVar(&clusterContext, "cluster-context", config.DefaultInClusterContext,
+		"Context name for the cluster the pod runs in (use the name saved sessions refer to)")
+	webCmd.Flags().StringVar(&kubeconfigDir, "kubeconfig-dir", "",
+		"Directory with kubeconfig files for remote clusters (e.g. a mounted Secret)")
+	webCmd.Flags().StringVar(&kubeconfigSecrets, "kubeconfig-secrets", "",
+		"Label selector for Secrets holding remote cluster kubeconfigs (e.g. "+config.KubeconfigSecretLabel+"=true)")
+	webCmd.Flags().StringVar(&kubeconfigSecretNamespace, "kubeconfig-secrets-namespace", "",
+		"Namespace of the kubeconfig Secrets (default: the pod's namespace)")
+	webCmd.Flags().BoolVar(&workloadIdentity, "workload-identity", azure.WorkloadIdentityEnabled(),
+		"Switch kubelogin users to Azure workload identity (default: true when AZURE_FEDERATED_TOKEN_FILE is set)")
+	webCmd.Flags().DurationVar(&shutdownDelay, "shutdown-delay", 0,
+		"Wait after failing readiness before closing the listener (e.g. 5s in-cluster)")
+	webCmd.Flags().DurationVar(&shutdownTimeout, "shutdown-timeout", 30*time.Second,
+		"Maximum time to wait for in-flight requests during graceful shutdown")
 }
@@ -0,0 +1,351 @@
+// Package audit implementa o audit log de alterações (compliance/change management).
+//
+// Diferente do history.HistoryTracker (histórico pessoal, em memória, apagável pela UI),
+// o audit log é append-only: cada operação mutável da TUI, da interface web, da CLI e do
+// scheduler vira uma linha JSON em segmentos mensais (audit-YYYY-MM.jsonl). Cada entrada
+// carrega o hash SHA-256 da anterior (prev_hash), então remover, reordenar ou editar uma
+// linha quebra a cadeia e é detectado por Verify. A retenção remove segmentos inteiros e
+// registra a remoção na própria cadeia (entrada audit_prune + anchor.json).
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Source origem da operação
+type Source string
+
+const (
+	SourceTUI       Source = "tui"
+	SourceWeb       Source = "web"
+	SourceCLI       Source = "cli"
+	SourceScheduler Source = "scheduler"
+	SourceSystem    Source = "system" // manutenção do próprio audit log (retenção)
+)
+
+// Result resultado da operação
+type Result string
+
+const (
+	ResultSuccess Result = "success"
+	ResultFailed  Result = "failed"
+	ResultDenied  Result = "denied" // bloqueada por permissão (403)
+)
+
+// Ações registradas fora do middleware HTTP (que usa "MÉTODO /rota")
+const (
+	ActionUpdateHPA       = "update_hpa"
+	ActionApplyNodePool   = "apply_nodepool"
+	ActionApplyResource   = "apply_resource"
+	ActionApplyConfigMap  = "apply_configmap"
+	ActionUpdateCronJob   = "update_cronjob"
+	ActionRollout         = "rollout"
+	ActionApplySession    = "apply_session"
+	ActionRollbackSession = "rollback_session"
+	ActionPrune           = "audit_prune"
+)
+
+const (
+	segmentPrefix = "audit-"
+	segmentSuffix = ".jsonl"
+	anchorFile    = "anchor.json"
+	lockFileName  = ".lock"
+	maxLineSize   = 16 * 1024 * 1024
+)
+
+// Entry linha do audit log. Hash cobre todos os campos (inclusive PrevHash) serializados
+// com Hash vazio.
+type Entry struct {
+	Seq       int64           `json:"seq"`
+	ID        string          `json:"id"`
+	Timestamp time.Time       `json:"timestamp"`
+	Actor     string          `json:"actor"`
+	Source    Source          `json:"source"`
+	Action    string          `json:"action"`
+	Cluster   string          `json:"cluster,omitempty"`
+	Resource  string          `json:"resource,omitempty"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	Result    Result          `json:"result"`
+	Error     string          `json:"error,omitempty"`
+	Session   string          `json:"session,omitempty"`
+	RequestID string          `json:"request_id,omitempty"`
+	PrevHash  string          `json:"prev_hash"`
+	Hash      string          `json:"hash"`
+}
+
+// computeHash SHA-256 (hex) da entrada serializada com Hash vazio
+func (e Entry) computeHash() (string, error) {
+	e.Hash = ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Log audit log em disco. Seguro para uso concorrente no processo e entre processos
+// (TUI, servidor web e CLI no mesmo diretório), via lock de arquivo.
+type Log struct {
+	dir       string
+	retention time.Duration
+	now       func() time.Time
+
+	mu   sync.Mutex
+	head *head // cache da última entrada escrita
+}
+
+// head posição da última entrada (invalidada quando outro processo escreve)
+type head struct {
+	segment string
+	size    int64
+	seq     int64
+	hash    string
+}
+
+// Open abre (criando se necessário) o audit log descrito pela configuração
+func Open(cfg *Config) (*Log, error) {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+	dir := cfg.Dir
+	if dir == "" {
+		dir = DefaultDir()
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create audit directory: %w", err)
+	}
+	retention, err := cfg.retention()
+	if err != nil {
+		return nil, err
+	}
+	return &Log{dir: dir, retention: retention, now: time.Now}, nil
+}
+
+// Dir diretório dos segmentos
+func (l *Log) Dir() string {
+	return l.dir
+}
+
+// Append grava a entrada no fim da cadeia, preenchendo Seq, ID, Timestamp, PrevHash e Hash.
+// A escrita é síncrona (fsync) para que a operação auditada não termine sem registro.
+func (l *Log) Append(entry Entry) (Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	unlock, err := lockFile(filepath.Join(l.dir, lockFileName))
+	if err != nil {
+		return entry, fmt.Errorf("failed to lock audit log: %w", err)
+	}
+	defer unlock()
+
+	return l.appendLocked(entry)
+}
+
+func (l *Log) appendLocked(entry Entry) (Entry, error) {
+	last, err := l.headLocked()
+	if err != nil {
+		return entry, err
+	}
+
+	entry.Seq = last.seq + 1
+	entry.PrevHash = last.hash
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	entry.Timestamp = l.now().UTC()
+	if entry.Result == "" {
+		entry.Result = ResultSuccess
+	}
+	if entry.Hash, err = entry.computeHash(); err != nil {
+		return entry, fmt.Errorf("failed to hash audit entry: %w", err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return entry, fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	// Relógio atrasado não reabre um mês anterior: a ordem dos segmentos é a ordem da cadeia
+	segment := segmentName(entry.Timestamp)
+	if last.segment > segment {
+		segment = last.segment
+	}
+	path := filepath.Join(l.dir, segment)
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return entry, fmt.Errorf("failed to open audit segment: %w", err)
+	}
+	defer file.Close()
+
+	// Linha truncada por uma queda no meio da escrita fica isolada (Verify aponta)
+	if info, err := file.Stat(); err == nil && info.Size() > 0 && !endsWithNewline(path, info.Size()) {
+		data = append([]byte("\n"), data...)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return entry, fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return entry, fmt.Errorf("failed to sync audit segment: %w", err)
+	}
+
+	if info, err := file.Stat(); err == nil {
+		l.head = &head{segment: segment, size: info.Size(), seq: entry.Seq, hash: entry.Hash}
+	} else {
+		l.head = nil
+	}
+	return entry, nil
+}
+
+// headLocked retorna a última entrada válida da cadeia (ou o anchor da retenção, ou vazio)
+func (l *Log) headLocked() (head, error) {
+	segments, err := l.segments()
+	if err != nil {
+		return head{}, err
+	}
+	for i := len(segments) - 1; i >= 0; i-- {
+		path := filepath.Join(l.dir, segments[i])
+		info, err := os.Stat(path)
+		if err != nil {
+			return head{}, fmt.Errorf("failed to stat audit segment: %w", err)
+		}
+		if l.head != nil && l.head.segment == segments[i] && l.head.size == info.Size() {
+			return *l.head, nil
+		}
+
+		var last *Entry
+		err = scanSegment(path, func(line int, raw []byte) error {
+			var entry Entry
+			if json.Unmarshal(raw, &entry) == nil && entry.Hash != "" {
+				last = &entry
+			}
+			return nil
+		})
+		if err != nil {
+			return head{}, err
+		}
+		if last != nil {
+			return head{segment: segments[i], size: info.Size(), seq: last.Seq, hash: last.Hash}, nil
+		}
+	}
+
+	anchor, err := l.readAnchor()
+	if err != nil || anchor == nil {
+		return head{}, err
+	}
+	return head{seq: anchor.Seq, hash: anchor.Hash}, nil
+}
+
+// segments nomes dos segmentos em ordem cronológica
+func (l *Log) segments() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(l.dir, segmentPrefix+"*"+segmentSuffix))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(matches))
+	for _, match := range matches {
+		names = append(names, filepath.Base(match))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// each percorre as linhas de todos os segmentos em ordem
+func (l *Log) each(fn func(segment string, line int, raw []byte) error) ([]string, error) {
+	segments, err := l.segments()
+	if err != nil {
+		return nil, err
+	}
+	for _, segment := range segments {
+		err := scanSegment(filepath.Join(l.dir, segment), func(line int, raw []byte) error {
+			return fn(segment, line, raw)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return segments, nil
+}
+
+func segmentName(t time.Time) string {
+	return segmentPrefix + t.UTC().Format("2006-01") + segmentSuffix
+}
+
+// segmentEnd início do mês seguinte ao do segmento (zero se o nome for inválido)
+func segmentEnd(name string) time.Time {
+	month, err := time.Parse("2006-01", strings.TrimSuffix(strings.TrimPrefix(name, segmentPrefix), segmentSuffix))
+	if err != nil {
+		return time.Time{}
+	}
+	return month.AddDate(0, 1, 0)
+}
+
+// scanSegment chama fn para cada linha não vazia (line começa em 1)
+func scanSegment(path string, fn func(line int, raw []byte) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open audit segment: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		if err := fn(line, raw); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read audit segment %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+func endsWithNewline(path string, size int64) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return true
+	}
+	defer file.Close()
+	buf := make([]byte, 1)
+	if _, err := file.ReadAt(buf, size-1); err != nil {
+		return true
+	}
+	return buf[0] == '\n'
+}
+
+// LocalActor identifica o operador local (TUI/CLI): usuário do sistema operacional e host
+func LocalActor() string {
+	name := os.Getenv("USER")
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		name = current.Username
+	}
+	if name == "" {
+		name = "unknown"
+	}
+	if host, err := os.Hostname(); err == nil && host != "" {
+		return name + "@" + host
+	}
+	return name
+}
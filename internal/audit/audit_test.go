@@ -0,0 +1,257 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestLog audit log em diretório temporário com relógio controlado
+func newTestLog(t *testing.T, dir string, now *time.Time) *Log {
+	t.Helper()
+	log, err := Open(&Config{Dir: dir, Retention: "90d"})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	log.now = func() time.Time { return *now }
+	return log
+}
+
+func TestAppendAndVerify(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 31, 23, 59, 0, 0, time.UTC)
+	log := newTestLog(t, dir, &now)
+
+	web := NewRecorder(log, SourceWeb, "dev@empresa.com")
+	web.Record(Event{
+		Action:   ActionUpdateHPA,
+		Cluster:  "aks-prd-admin",
+		Resource: "pagamentos/api",
+		Before:   map[string]interface{}{"min_replicas": 2, "target": "<70%>"},
+		After:    map[string]interface{}{"min_replicas": 4, "target": "<70%>"},
+	})
+	now = now.Add(2 * time.Minute) // vira o mês: novo segmento continua a cadeia
+	NewRecorder(log, SourceCLI, LocalActor()).Record(Event{Action: ActionApplySession, Cluster: "aks-hlg", Err: errors.New("quota exceeded")})
+
+	// Outra instância (outro processo) continua a mesma cadeia
+	other := newTestLog(t, dir, &now)
+	NewRecorder(other, SourceTUI, "ops").Record(Event{Action: ActionApplyNodePool, Cluster: "aks-hlg", Resource: "user1"})
+	web.Record(Event{Action: "PUT /api/v1/hpas/:cluster/:namespace/:name", Result: ResultDenied})
+
+	report, err := log.Verify()
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !report.Valid || report.Entries != 4 || len(report.Segments) != 2 || report.Head.Seq != 4 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	entries, err := log.Entries(Filter{Cluster: "aks-hlg-admin"})
+	if err != nil {
+		t.Fatalf("Entries failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Source != SourceTUI || entries[1].Result != ResultFailed || entries[1].Error != "quota exceeded" {
+		t.Errorf("unexpected filtered entries: %+v", entries)
+	}
+	if latest, _ := log.Entries(Filter{Limit: 1}); len(latest) != 1 || latest[0].Result != ResultDenied {
+		t.Errorf("expected latest denied entry, got %+v", latest)
+	}
+
+	// Checkpoint externo
+	if report, _ := log.Verify(report.Head); !report.Valid {
+		t.Errorf("head checkpoint should verify: %+v", report.Problems)
+	}
+	if report, _ := log.Verify(Checkpoint{Seq: 2, Hash: strings.Repeat("0", 64)}); report.Valid {
+		t.Error("wrong checkpoint should fail")
+	}
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(lines []string) []string
+		want   string
+	}{
+		{"edited", func(lines []string) []string {
+			lines[1] = strings.Replace(lines[1], `"min":2`, `"min":9`, 1)
+			return lines
+		}, "hash mismatch"},
+		{"removed", func(lines []string) []string {
+			return append(lines[:1], lines[2:]...)
+		}, "prev_hash does not match"},
+		{"reordered", func(lines []string) []string {
+			lines[0], lines[1] = lines[1], lines[0]
+			return lines
+		}, "prev_hash does not match"},
+		{"truncated", func(lines []string) []string {
+			lines[2] = lines[2][:len(lines[2])/2]
+			return lines
+		}, "unreadable entry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+			log := newTestLog(t, dir, &now)
+			recorder := NewRecorder(log, SourceWeb, "dev")
+			for i := 1; i <= 3; i++ {
+				recorder.Record(Event{Action: ActionUpdateHPA, Resource: "ns/api", After: map[string]int{"min": i}})
+			}
+
+			path := filepath.Join(dir, "audit-2026-05.jsonl")
+			data, _ := os.ReadFile(path)
+			lines := tt.tamper(strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"))
+			os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0600)
+
+			report, err := log.Verify()
+			if err != nil {
+				t.Fatalf("Verify failed: %v", err)
+			}
+			if report.Valid || !strings.Contains(report.Problems[0].String(), tt.want) {
+				t.Errorf("expected problem %q, got %+v", tt.want, report.Problems)
+			}
+
+			// Append depois da linha truncada não se mistura com ela
+			if tt.name == "truncated" {
+				recorder.Record(Event{Action: ActionUpdateHPA})
+				if report, _ := log.Verify(); report.Entries != 3 || len(report.Problems) != 1 {
+					t.Errorf("expected only the truncated line to be reported, got %+v", report)
+				}
+			}
+		})
+	}
+}
+
+func TestPruneKeepsChainVerifiable(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	log := newTestLog(t, dir, &now)
+	recorder := NewRecorder(log, SourceWeb, "dev")
+	for month := 0; month < 5; month++ {
+		recorder.Record(Event{Action: ActionUpdateHPA, Resource: "ns/api"})
+		recorder.Record(Event{Action: ActionUpdateHPA, Resource: "ns/worker"})
+		now = now.AddDate(0, 1, 0)
+	}
+
+	// 2026-06-15 com retenção de 90 dias: jan e fev expiram, mar (termina 01/04) fica
+	result, err := log.Prune(now)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if strings.Join(result.Removed, ",") != "audit-2026-01.jsonl,audit-2026-02.jsonl" || result.Anchor.Seq != 4 {
+		t.Fatalf("unexpected prune result: %+v", result)
+	}
+
+	report, err := log.Verify()
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !report.Valid || report.FirstSeq != 5 || report.Entries != 7 {
+		t.Fatalf("chain should stay valid after prune: %+v", report)
+	}
+	if pruned, _ := log.Entries(Filter{Action: ActionPrune}); len(pruned) != 1 || pruned[0].Source != SourceSystem {
+		t.Errorf("expected audit_prune entry, got %+v", pruned)
+	}
+
+	// Remover um segmento sem passar pela retenção é detectado
+	os.Remove(filepath.Join(dir, "audit-2026-03.jsonl"))
+	if report, _ := log.Verify(); report.Valid {
+		t.Error("manual removal of a segment should break the chain")
+	}
+}
+
+func TestExport(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 7, 1, 8, 0, 0, 0, time.UTC)
+	log := newTestLog(t, dir, &now)
+	recorder := NewRecorder(log, SourceScheduler, "scheduler")
+	recorder.Record(Event{Action: ActionApplySession, Cluster: "aks-prd", Resource: "hpa:ns/api", Session: "Black Friday", After: map[string]string{"note": "a, \"b\""}})
+	recorder.Record(Event{Action: ActionRollbackSession, Cluster: "aks-hlg", Resource: "hpa:ns/api"})
+
+	// JSON Lines completo é uma cópia verificável dos segmentos
+	var jsonl bytes.Buffer
+	if n, err := log.Export(&jsonl, FormatJSONL, Filter{}); err != nil || n != 2 {
+		t.Fatalf("Export jsonl failed: %d %v", n, err)
+	}
+	copyDir := t.TempDir()
+	os.WriteFile(filepath.Join(copyDir, "audit-2026-07.jsonl"), jsonl.Bytes(), 0600)
+	copied, _ := Open(&Config{Dir: copyDir})
+	if report, err := copied.Verify(); err != nil || !report.Valid {
+		t.Errorf("exported JSONL should verify: %+v %v", report, err)
+	}
+
+	var out bytes.Buffer
+	if n, err := log.Export(&out, FormatCSV, Filter{Cluster: "aks-prd-admin"}); err != nil || n != 1 {
+		t.Fatalf("Export csv failed: %d %v", n, err)
+	}
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(rows) != 2 || rows[0][0] != "seq" || rows[1][9] != "Black Friday" || rows[1][12] != `{"note":"a, \"b\""}` {
+		t.Errorf("unexpected CSV rows: %q", rows)
+	}
+
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestConcurrentAppend(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)
+	logs := []*Log{newTestLog(t, dir, &now), newTestLog(t, dir, &now)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			NewRecorder(logs[i%2], SourceWeb, "dev").Record(Event{Action: ActionUpdateHPA})
+		}(i)
+	}
+	wg.Wait()
+
+	report, err := logs[0].Verify()
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !report.Valid || report.Entries != 40 {
+		t.Errorf("concurrent appends should form one chain: %+v", report)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	valid := map[string]time.Duration{
+		"":                 defaultRetention,
+		"retention: 30d":   30 * 24 * time.Hour,
+		"retention: 8760h": 8760 * time.Hour,
+		"retention: \"0\"": 0,
+		"dir: /tmp/x":      defaultRetention,
+	}
+	for data, want := range valid {
+		cfg, err := ParseConfig([]byte(data))
+		if err != nil {
+			t.Errorf("ParseConfig(%q) failed: %v", data, err)
+			continue
+		}
+		if got, _ := cfg.retention(); got != want {
+			t.Errorf("ParseConfig(%q) retention = %v, want %v", data, got, want)
+		}
+	}
+	for _, data := range []string{"retention: 1h", "retention: 0d", "retention: forever", "keep: 1d"} {
+		if _, err := ParseConfig([]byte(data)); err == nil {
+			t.Errorf("expected error for %q", data)
+		}
+	}
+	if _, err := ParseCheckpoint("12:" + strings.Repeat("a", 64)); err != nil {
+		t.Errorf("ParseCheckpoint failed: %v", err)
+	}
+}
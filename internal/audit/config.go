@@ -0,0 +1,110 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Config formato do arquivo do audit log (YAML, opcional)
+//
+//	dir: /var/lib/k8s-hpa-manager/audit   # default: ~/.k8s-hpa-manager/audit
+//	retention: 400d                       # default: 400d; "0" = manter para sempre
+//
+// A retenção remove apenas segmentos mensais inteiros cujo mês terminou antes do limite;
+// o segmento atual nunca é removido.
+type Config struct {
+	Dir       string `json:"dir,omitempty"`
+	Retention string `json:"retention,omitempty"` // Go duration ou dias (ex: 400d, 8760h)
+}
+
+// DefaultConfig configuração usada sem audit.yaml
+func DefaultConfig() *Config {
+	return &Config{Dir: DefaultDir()}
+}
+
+// DefaultDir retorna ~/.k8s-hpa-manager/audit
+func DefaultDir() string {
+	return filepath.Join(baseDir(), "audit")
+}
+
+// DefaultConfigPath retorna ~/.k8s-hpa-manager/audit.yaml
+func DefaultConfigPath() string {
+	return filepath.Join(baseDir(), "audit.yaml")
+}
+
+func baseDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	return filepath.Join(home, ".k8s-hpa-manager")
+}
+
+// LoadConfig lê e valida o arquivo; retorna DefaultConfig quando o arquivo não existe
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultConfig(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit config: %w", err)
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig valida a configuração YAML
+func ParseConfig(data []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid audit config: %w", err)
+	}
+	if _, err := cfg.retention(); err != nil {
+		return nil, err
+	}
+	if cfg.Dir == "" {
+		cfg.Dir = DefaultDir()
+	}
+	return &cfg, nil
+}
+
+// retention período de retenção (0 = para sempre)
+func (c *Config) retention() (time.Duration, error) {
+	if c.Retention == "" {
+		return defaultRetention, nil
+	}
+	retention, err := ParseRetention(c.Retention)
+	if err != nil {
+		return 0, fmt.Errorf("invalid audit retention: %w", err)
+	}
+	return retention, nil
+}
+
+// defaultRetention 400 dias: um ano completo mais a margem de uma auditoria anual
+const defaultRetention = 400 * 24 * time.Hour
+
+// ParseRetention aceita dias ("400d"), Go durations ("8760h") e "0" (sem retenção)
+func ParseRetention(value string) (time.Duration, error) {
+	if value == "0" {
+		return 0, nil
+	}
+	var days int
+	if n, err := fmt.Sscanf(value, "%dd", &days); err == nil && n == 1 && fmt.Sprintf("%dd", days) == value {
+		if days < 1 {
+			return 0, fmt.Errorf("retention must be at least 1d (or 0 to keep forever)")
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	retention, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a duration (use e.g. 400d or 8760h)", value)
+	}
+	if retention < 24*time.Hour {
+		return 0, fmt.Errorf("retention must be at least 24h (or 0 to keep forever)")
+	}
+	return retention, nil
+}
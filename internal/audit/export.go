@@ -0,0 +1,190 @@
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter critérios de consulta/exportação (campos vazios não filtram)
+type Filter struct {
+	Since   time.Time
+	Until   time.Time
+	Actor   string
+	Source  Source
+	Action  string
+	Cluster string // ignora o sufixo -admin dos contextos kubectl
+	Result  Result
+	Limit   int // consulta: mantém as N entradas mais recentes
+}
+
+// Matches indica se a entrada atende ao filtro
+func (f Filter) Matches(entry Entry) bool {
+	if !f.Since.IsZero() && entry.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !entry.Timestamp.Before(f.Until) {
+		return false
+	}
+	if f.Actor != "" && !strings.EqualFold(f.Actor, entry.Actor) {
+		return false
+	}
+	if f.Source != "" && f.Source != entry.Source {
+		return false
+	}
+	if f.Action != "" && f.Action != entry.Action {
+		return false
+	}
+	if f.Cluster != "" && strings.TrimSuffix(f.Cluster, "-admin") != strings.TrimSuffix(entry.Cluster, "-admin") {
+		return false
+	}
+	if f.Result != "" && f.Result != entry.Result {
+		return false
+	}
+	return true
+}
+
+// ParseTime aceita RFC3339 ou YYYY-MM-DD no fuso local (endOfDay inclui o dia inteiro,
+// para uso como Until)
+func ParseTime(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q (use RFC3339 or YYYY-MM-DD)", value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// Entries retorna as entradas que atendem ao filtro, da mais recente para a mais antiga
+func (l *Log) Entries(filter Filter) ([]Entry, error) {
+	var entries []Entry
+	err := l.scan(filter, func(entry Entry, raw []byte) error {
+		entries = append(entries, entry)
+		if filter.Limit > 0 && len(entries) > filter.Limit {
+			entries = entries[1:]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
+
+// scan percorre as entradas legíveis que atendem ao filtro, em ordem da cadeia
+func (l *Log) scan(filter Filter, fn func(entry Entry, raw []byte) error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, err := l.each(func(segment string, line int, raw []byte) error {
+		var entry Entry
+		if json.Unmarshal(raw, &entry) != nil || !filter.Matches(entry) {
+			return nil
+		}
+		return fn(entry, raw)
+	})
+	return err
+}
+
+// Format formato de exportação
+type Format string
+
+const (
+	FormatJSONL Format = "jsonl"
+	FormatCSV   Format = "csv"
+)
+
+// ParseFormat valida o formato de exportação
+func ParseFormat(value string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "jsonl", "ndjson", "json-lines":
+		return FormatJSONL, nil
+	case "csv":
+		return FormatCSV, nil
+	}
+	return "", fmt.Errorf("unsupported audit export format %q (use jsonl or csv)", value)
+}
+
+// ContentType retorna o Content-Type HTTP do formato
+func (f Format) ContentType() string {
+	if f == FormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/x-ndjson"
+}
+
+// Extension extensão de arquivo do formato
+func (f Format) Extension() string {
+	if f == FormatCSV {
+		return "csv"
+	}
+	return "jsonl"
+}
+
+// csvHeader colunas da exportação CSV (before/after em JSON)
+var csvHeader = []string{
+	"seq", "timestamp", "actor", "source", "action", "cluster", "resource", "result", "error",
+	"session", "request_id", "before", "after", "prev_hash", "hash",
+}
+
+// Export escreve as entradas filtradas em ordem da cadeia. Em JSON Lines as linhas são
+// copiadas byte a byte, então um export completo pode ser verificado fora do servidor.
+// Retorna o número de entradas exportadas.
+func (l *Log) Export(w io.Writer, format Format, filter Filter) (int, error) {
+	filter.Limit = 0
+	count := 0
+
+	if format == FormatCSV {
+		writer := csv.NewWriter(w)
+		if err := writer.Write(csvHeader); err != nil {
+			return 0, err
+		}
+		err := l.scan(filter, func(entry Entry, raw []byte) error {
+			count++
+			return writer.Write([]string{
+				strconv.FormatInt(entry.Seq, 10),
+				entry.Timestamp.Format(time.RFC3339Nano),
+				entry.Actor,
+				string(entry.Source),
+				entry.Action,
+				entry.Cluster,
+				entry.Resource,
+				string(entry.Result),
+				entry.Error,
+				entry.Session,
+				entry.RequestID,
+				string(entry.Before),
+				string(entry.After),
+				entry.PrevHash,
+				entry.Hash,
+			})
+		})
+		if err != nil {
+			return count, err
+		}
+		writer.Flush()
+		return count, writer.Error()
+	}
+
+	err := l.scan(filter, func(entry Entry, raw []byte) error {
+		count++
+		if _, err := w.Write(raw); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "\n")
+		return err
+	})
+	return count, err
+}
@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Event operação a registrar. Before/After são serializados em JSON (nil = omitido).
+type Event struct {
+	Action   string
+	Cluster  string
+	Resource string // namespace/nome, ou kind:namespace/nome em sessões
+	Before   interface{}
+	After    interface{}
+	Err      error  // nil = sucesso
+	Result   Result // sobrescreve o resultado derivado de Err (ex: ResultDenied)
+	Session  string
+}
+
+// Recorder grava eventos com a origem e o ator de quem executa a operação.
+// Um Recorder nil (ou sem Log) não registra nada.
+type Recorder struct {
+	Log       *Log
+	Source    Source
+	Actor     string
+	RequestID string
+}
+
+// NewRecorder cria um Recorder para a origem e o ator informados
+func NewRecorder(log *Log, source Source, actor string) *Recorder {
+	if log == nil {
+		return nil
+	}
+	return &Recorder{Log: log, Source: source, Actor: actor}
+}
+
+// Record grava o evento. A operação auditada já aconteceu, então falhas de escrita são
+// reportadas no log do processo e não interrompem o chamador.
+func (r *Recorder) Record(event Event) {
+	if r == nil || r.Log == nil {
+		return
+	}
+
+	entry := Entry{
+		Actor:     r.Actor,
+		Source:    r.Source,
+		Action:    event.Action,
+		Cluster:   event.Cluster,
+		Resource:  event.Resource,
+		Result:    event.Result,
+		Session:   event.Session,
+		RequestID: r.RequestID,
+	}
+	if event.Err != nil {
+		entry.Error = event.Err.Error()
+		if entry.Result == "" {
+			entry.Result = ResultFailed
+		}
+	}
+	var err error
+	if entry.Before, err = rawJSON(event.Before); err == nil {
+		entry.After, err = rawJSON(event.After)
+	}
+	if err == nil {
+		_, err = r.Log.Append(entry)
+	}
+	if err != nil {
+		fmt.Printf("⚠️  Warning: failed to write audit entry (%s %s): %v\n", event.Action, event.Resource, err)
+	}
+}
+
+// rawJSON serializa o valor (json.RawMessage é apenas validado)
+func rawJSON(value interface{}) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if raw, ok := value.(json.RawMessage); ok {
+		if len(raw) == 0 {
+			return nil, nil
+		}
+		if !json.Valid(raw) {
+			return nil, fmt.Errorf("invalid JSON payload")
+		}
+		value = raw
+	}
+	data, err := json.Marshal(value)
+	if err != nil || string(data) == "null" {
+		return nil, err
+	}
+	return data, nil
+}
+
+var (
+	defaultOnce sync.Once
+	defaultLog  *Log
+)
+
+// Default audit log do processo (audit.yaml ou ~/.k8s-hpa-manager/audit), aberto uma vez.
+// Retorna nil se não for possível abrir; o motivo é impresso no log do processo.
+func Default() *Log {
+	defaultOnce.Do(func() {
+		cfg, err := LoadConfig(DefaultConfigPath())
+		if err == nil {
+			defaultLog, err = Open(cfg)
+		}
+		if err != nil {
+			fmt.Printf("⚠️  Warning: audit log disabled: %v\n", err)
+		}
+	})
+	return defaultLog
+}
@@ -0,0 +1,145 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Anchor última entrada removida pela retenção: o primeiro segmento mantido encadeia
+// a partir dela. A entrada audit_prune correspondente atesta o anchor dentro da cadeia.
+type Anchor struct {
+	Seq      int64     `json:"seq"`
+	Hash     string    `json:"hash"`
+	Segments []string  `json:"segments"` // segmentos removidos nesta execução
+	PrunedAt time.Time `json:"pruned_at"`
+}
+
+// PruneResult resultado de uma execução da retenção
+type PruneResult struct {
+	Removed []string `json:"removed"`
+	Anchor  *Anchor  `json:"anchor,omitempty"`
+}
+
+// Prune remove os segmentos cujo mês terminou antes de now-retenção. O segmento mais
+// recente nunca é removido. Sem retenção configurada, não faz nada.
+func (l *Log) Prune(now time.Time) (*PruneResult, error) {
+	result := &PruneResult{}
+	if l.retention <= 0 {
+		return result, nil
+	}
+	cutoff := now.Add(-l.retention)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	unlock, err := lockFile(filepath.Join(l.dir, lockFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock audit log: %w", err)
+	}
+	defer unlock()
+
+	segments, err := l.segments()
+	if err != nil {
+		return nil, err
+	}
+	var expired []string
+	for i, segment := range segments {
+		end := segmentEnd(segment)
+		if i == len(segments)-1 || end.IsZero() || end.After(cutoff) {
+			break
+		}
+		expired = append(expired, segment)
+	}
+	if len(expired) == 0 {
+		return result, nil
+	}
+
+	// Anchor = última entrada válida dos segmentos removidos
+	anchor := &Anchor{Segments: expired, PrunedAt: now.UTC()}
+	for _, segment := range expired {
+		err := scanSegment(filepath.Join(l.dir, segment), func(line int, raw []byte) error {
+			var entry Entry
+			if json.Unmarshal(raw, &entry) == nil && entry.Hash != "" {
+				anchor.Seq, anchor.Hash = entry.Seq, entry.Hash
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if anchor.Hash == "" {
+		previous, err := l.readAnchor()
+		if err != nil {
+			return nil, err
+		}
+		if previous != nil {
+			anchor.Seq, anchor.Hash = previous.Seq, previous.Hash
+		}
+	}
+
+	// 1. registrar a remoção na cadeia, 2. gravar o anchor, 3. remover os arquivos
+	after, _ := json.Marshal(map[string]interface{}{
+		"segments":    expired,
+		"anchor_seq":  anchor.Seq,
+		"anchor_hash": anchor.Hash,
+		"retention":   l.retention.String(),
+	})
+	if _, err := l.appendLocked(Entry{
+		Actor:  "retention",
+		Source: SourceSystem,
+		Action: ActionPrune,
+		After:  after,
+		Result: ResultSuccess,
+	}); err != nil {
+		return nil, err
+	}
+	if err := l.writeAnchor(anchor); err != nil {
+		return nil, err
+	}
+	for _, segment := range expired {
+		if err := os.Remove(filepath.Join(l.dir, segment)); err != nil {
+			return nil, fmt.Errorf("failed to remove audit segment %s: %w", segment, err)
+		}
+		result.Removed = append(result.Removed, segment)
+	}
+	result.Anchor = anchor
+	return result, nil
+}
+
+// readAnchor retorna nil quando nenhuma retenção foi executada
+func (l *Log) readAnchor() (*Anchor, error) {
+	data, err := os.ReadFile(filepath.Join(l.dir, anchorFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit anchor: %w", err)
+	}
+	var anchor Anchor
+	if err := json.Unmarshal(data, &anchor); err != nil {
+		return nil, fmt.Errorf("invalid audit anchor: %w", err)
+	}
+	return &anchor, nil
+}
+
+// writeAnchor grava o anchor de forma atômica (arquivo temporário + rename)
+func (l *Log) writeAnchor(anchor *Anchor) error {
+	data, err := json.MarshalIndent(anchor, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(l.dir, anchorFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write audit anchor: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write audit anchor: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Problem inconsistência encontrada na verificação
+type Problem struct {
+	Segment string `json:"segment,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Seq     int64  `json:"seq,omitempty"`
+	Message string `json:"message"`
+}
+
+// String formata o problema para a CLI
+func (p Problem) String() string {
+	var where []string
+	if p.Segment != "" {
+		where = append(where, fmt.Sprintf("%s:%d", p.Segment, p.Line))
+	}
+	if p.Seq > 0 {
+		where = append(where, fmt.Sprintf("seq %d", p.Seq))
+	}
+	if len(where) == 0 {
+		return p.Message
+	}
+	return strings.Join(where, " ") + ": " + p.Message
+}
+
+// Checkpoint hash de uma entrada guardado fora do servidor (ex: ticket de mudança).
+// A cadeia sozinha não impede que alguém com acesso ao disco a reescreva inteira;
+// conferir um checkpoint externo detecta isso.
+type Checkpoint struct {
+	Seq  int64  `json:"seq"`
+	Hash string `json:"hash"`
+}
+
+// String formato seq:hash aceito por ParseCheckpoint
+func (c Checkpoint) String() string {
+	return fmt.Sprintf("%d:%s", c.Seq, c.Hash)
+}
+
+// ParseCheckpoint lê "seq:hash"
+func ParseCheckpoint(value string) (Checkpoint, error) {
+	seq, hash, ok := strings.Cut(strings.TrimSpace(value), ":")
+	n, err := strconv.ParseInt(seq, 10, 64)
+	if !ok || err != nil || n < 1 || len(hash) != 64 {
+		return Checkpoint{}, fmt.Errorf("invalid checkpoint %q (use <seq>:<sha256-hex>)", value)
+	}
+	return Checkpoint{Seq: n, Hash: strings.ToLower(hash)}, nil
+}
+
+// VerifyReport resultado da verificação da cadeia
+type VerifyReport struct {
+	Valid    bool       `json:"valid"`
+	Segments []string   `json:"segments"`
+	Entries  int        `json:"entries"`
+	FirstSeq int64      `json:"first_seq,omitempty"`
+	Head     Checkpoint `json:"head"` // última entrada: guarde fora do servidor para verificar depois
+	Anchor   *Anchor    `json:"anchor,omitempty"`
+	Problems []Problem  `json:"problems,omitempty"`
+}
+
+// Verify recalcula o hash de cada entrada e confere o encadeamento (prev_hash e seq),
+// o anchor da retenção e os checkpoints informados
+func (l *Log) Verify(checkpoints ...Checkpoint) (*VerifyReport, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	anchor, err := l.readAnchor()
+	if err != nil {
+		return nil, err
+	}
+	report := &VerifyReport{Anchor: anchor}
+	var prev Checkpoint
+	if anchor != nil {
+		prev = Checkpoint{Seq: anchor.Seq, Hash: anchor.Hash}
+	}
+
+	expected := make(map[int64]string, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		expected[checkpoint.Seq] = checkpoint.Hash
+	}
+	attested := anchor == nil
+
+	report.Segments, err = l.each(func(segment string, line int, raw []byte) error {
+		problem := func(seq int64, format string, args ...interface{}) {
+			report.Problems = append(report.Problems, Problem{Segment: segment, Line: line, Seq: seq, Message: fmt.Sprintf(format, args...)})
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(raw, &entry); err != nil || entry.Hash == "" {
+			problem(0, "unreadable entry (truncated or edited line)")
+			return nil
+		}
+		report.Entries++
+		if report.FirstSeq == 0 {
+			report.FirstSeq = entry.Seq
+		}
+
+		if hash, err := entry.computeHash(); err != nil || hash != entry.Hash {
+			problem(entry.Seq, "hash mismatch: entry was modified")
+		}
+		if entry.PrevHash != prev.Hash {
+			problem(entry.Seq, "prev_hash does not match the previous entry: entries were removed, inserted or reordered")
+		}
+		if entry.Seq != prev.Seq+1 {
+			problem(entry.Seq, "sequence gap: expected seq %d", prev.Seq+1)
+		}
+		if hash, ok := expected[entry.Seq]; ok {
+			if hash != entry.Hash {
+				problem(entry.Seq, "checkpoint mismatch: chain was rewritten")
+			}
+			delete(expected, entry.Seq)
+		}
+		if anchor != nil && entry.Action == ActionPrune && entry.Source == SourceSystem {
+			var pruned struct {
+				AnchorHash string `json:"anchor_hash"`
+			}
+			if json.Unmarshal(entry.After, &pruned) == nil && pruned.AnchorHash == anchor.Hash {
+				attested = true
+			}
+		}
+
+		prev = Checkpoint{Seq: entry.Seq, Hash: entry.Hash}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !attested {
+		report.Problems = append(report.Problems, Problem{Message: "anchor.json is not attested by any audit_prune entry"})
+	}
+	for _, checkpoint := range checkpoints {
+		if _, missing := expected[checkpoint.Seq]; missing {
+			report.Problems = append(report.Problems, Problem{Seq: checkpoint.Seq, Message: "checkpoint entry not found (pruned or removed)"})
+		}
+	}
+	report.Head = prev
+	report.Valid = len(report.Problems) == 0
+	return report, nil
+}
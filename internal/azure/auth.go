@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"sync"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
@@ -14,6 +15,7 @@ import (
 
 // AuthManager gerencia a autenticação com Azure AD
 type AuthManager struct {
+	mu         sync.Mutex
 	credential azcore.TokenCredential
 	tenantID   string
 	clientID   string
@@ -131,6 +133,31 @@ func (a *AuthManager) GetCredential() azcore.TokenCredential {
 	return a.credential
 }
 
+// EnsureCredential retorna a credencial atual ou cria uma credencial não interativa.
+// A cadeia padrão do azidentity cobre variáveis de ambiente, workload identity,
+// managed identity e a sessão do Azure CLI (az login).
+func (a *AuthManager) EnsureCredential() (azcore.TokenCredential, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.credential != nil {
+		return a.credential, nil
+	}
+
+	options := &azidentity.DefaultAzureCredentialOptions{}
+	if a.tenantID != "" && a.tenantID != "common" {
+		options.TenantID = a.tenantID
+	}
+
+	cred, err := azidentity.NewDefaultAzureCredential(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create default azure credential: %w", err)
+	}
+
+	a.credential = cred
+	return cred, nil
+}
+
 // IsAuthenticated verifica se já está autenticado
 func (a *AuthManager) IsAuthenticated() bool {
 	return a.credential != nil
@@ -0,0 +1,32 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+// WorkloadIdentityEnabled indica se o pod recebeu as variáveis do webhook de workload identity
+// (modo servidor). Nesse caso não há Azure CLI nem navegador para login interativo.
+func WorkloadIdentityEnabled() bool {
+	return os.Getenv("AZURE_FEDERATED_TOKEN_FILE") != "" &&
+		os.Getenv("AZURE_CLIENT_ID") != "" &&
+		os.Getenv("AZURE_TENANT_ID") != ""
+}
+
+// ValidateWorkloadIdentity obtém um token do Azure Resource Manager com a identidade do pod
+func ValidateWorkloadIdentity(ctx context.Context) error {
+	cred, err := azidentity.NewWorkloadIdentityCredential(nil)
+	if err != nil {
+		return fmt.Errorf("failed to create workload identity credential: %w", err)
+	}
+	if _, err := cred.GetToken(ctx, policy.TokenRequestOptions{
+		Scopes: []string{"https://management.azure.com/.default"},
+	}); err != nil {
+		return fmt.Errorf("workload identity token request failed: %w", err)
+	}
+	return nil
+}
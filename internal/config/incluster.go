@@ -0,0 +1,338 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Modo servidor: o web server roda como Deployment em um cluster de gerenciamento.
+// O cluster local vem da ServiceAccount do pod e os remotos de kubeconfigs em Secrets
+// ou em um diretório montado. Tudo é mesclado em um único kubeconfig gravado em disco,
+// usado pelo KubeConfigManager e pelos comandos kubectl (port-forward, top).
+
+const (
+	// DefaultInClusterContext nome do contexto do cluster onde o pod roda
+	DefaultInClusterContext = "in-cluster"
+
+	// KubeconfigSecretLabel label sugerida para os Secrets com kubeconfig de clusters remotos
+	KubeconfigSecretLabel = "k8s-hpa-manager.io/kubeconfig"
+
+	serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// kubeconfigSecretKeys chaves aceitas nos Secrets ("value" é o formato do Cluster API)
+var kubeconfigSecretKeys = []string{"kubeconfig", "value", "config"}
+
+// InClusterOptions origens dos clusters no modo servidor
+type InClusterOptions struct {
+	Context          string // contexto do cluster local (default "in-cluster"; use o mesmo nome das sessões salvas)
+	KubeconfigDir    string // diretório com kubeconfigs de clusters remotos (ex: volume de Secret)
+	SecretSelector   string // label selector dos Secrets com kubeconfig ("" = não ler Secrets)
+	SecretNamespace  string // namespace dos Secrets ("" = namespace do pod)
+	WorkloadIdentity bool   // kubelogin usa a workload identity do pod (AZURE_CLIENT_ID, AZURE_FEDERATED_TOKEN_FILE)
+	OutputPath       string // kubeconfig mesclado (default: $TMPDIR/k8s-hpa-manager/kubeconfig)
+}
+
+// InClusterResult resultado do carregamento dos kubeconfigs no modo servidor
+type InClusterResult struct {
+	Path             string   // kubeconfig mesclado (exportar como KUBECONFIG)
+	Contexts         []string // contextos disponíveis, em ordem alfabética
+	Sources          []string // origens carregadas (ex: "secret hpa-manager/aks-prd")
+	Warnings         []string // arquivos inválidos e contextos ignorados por conflito
+	WorkloadIdentity int      // usuários convertidos para workload identity
+}
+
+// kubeconfigSource kubeconfig lido de um arquivo ou Secret
+type kubeconfigSource struct {
+	name   string
+	config *api.Config
+}
+
+// NewInClusterKubeConfigManager cria o gerenciador a partir da ServiceAccount do pod e dos
+// kubeconfigs de clusters remotos. Diferente do modo estação de trabalho, todos os contextos
+// carregados são listados (sem o filtro akspriv-): o operador já escolheu quais montar.
+func NewInClusterKubeConfigManager(ctx context.Context, opts InClusterOptions) (*KubeConfigManager, *InClusterResult, error) {
+	restConfig, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("in-cluster config unavailable (is the server running in a pod?): %w", err)
+	}
+
+	contextName := opts.Context
+	if contextName == "" {
+		contextName = DefaultInClusterContext
+	}
+	merged := inClusterKubeconfig(contextName, restConfig)
+	result := &InClusterResult{Sources: []string{"service account (" + contextName + ")"}}
+
+	var sources []kubeconfigSource
+	if opts.KubeconfigDir != "" {
+		dirSources, warnings, err := loadKubeconfigDir(opts.KubeconfigDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		sources = append(sources, dirSources...)
+		result.Warnings = append(result.Warnings, warnings...)
+	}
+
+	if opts.SecretSelector != "" {
+		namespace := opts.SecretNamespace
+		if namespace == "" {
+			namespace = podNamespace()
+		}
+		client, err := kubernetes.NewForConfig(restConfig)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create in-cluster client: %w", err)
+		}
+		secretSources, warnings, err := loadKubeconfigSecrets(ctx, client, namespace, opts.SecretSelector)
+		if err != nil {
+			return nil, nil, err
+		}
+		sources = append(sources, secretSources...)
+		result.Warnings = append(result.Warnings, warnings...)
+	}
+
+	for _, source := range sources {
+		result.Sources = append(result.Sources, source.name)
+		result.Warnings = append(result.Warnings, mergeKubeconfig(merged, source.config, source.name)...)
+	}
+
+	if opts.WorkloadIdentity {
+		result.WorkloadIdentity = useWorkloadIdentity(merged)
+	}
+
+	result.Path = opts.OutputPath
+	if result.Path == "" {
+		result.Path = filepath.Join(os.TempDir(), "k8s-hpa-manager", "kubeconfig")
+	}
+	// WriteToFile grava com permissão 0600 (o arquivo contém as credenciais dos Secrets)
+	if err := clientcmd.WriteToFile(*merged, result.Path); err != nil {
+		return nil, nil, fmt.Errorf("failed to write merged kubeconfig: %w", err)
+	}
+
+	manager, err := NewKubeConfigManager(result.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	manager.discoverAll = true
+
+	for name := range merged.Contexts {
+		result.Contexts = append(result.Contexts, name)
+	}
+	sort.Strings(result.Contexts)
+
+	return manager, result, nil
+}
+
+// inClusterKubeconfig representa a ServiceAccount do pod como um contexto de kubeconfig.
+// O token é referenciado por arquivo para acompanhar a rotação do token projetado.
+func inClusterKubeconfig(contextName string, restConfig *rest.Config) *api.Config {
+	clusterName := strings.TrimSuffix(contextName, "-admin")
+
+	cfg := api.NewConfig()
+	cfg.Clusters[clusterName] = &api.Cluster{
+		Server:               restConfig.Host,
+		CertificateAuthority: restConfig.TLSClientConfig.CAFile,
+	}
+	cfg.AuthInfos[contextName] = &api.AuthInfo{TokenFile: restConfig.BearerTokenFile}
+	cfg.Contexts[contextName] = &api.Context{Cluster: clusterName, AuthInfo: contextName}
+	cfg.CurrentContext = contextName
+	return cfg
+}
+
+// loadKubeconfigDir lê os kubeconfigs de um diretório. Entradas ocultas são ignoradas
+// (volumes de Secret/ConfigMap criam "..data" e diretórios com timestamp).
+func loadKubeconfigDir(dir string) ([]kubeconfigSource, []string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read kubeconfig dir: %w", err)
+	}
+
+	var sources []kubeconfigSource
+	var warnings []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") || entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		cfg, err := clientcmd.LoadFromFile(path)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		sources = append(sources, kubeconfigSource{name: "file " + path, config: cfg})
+	}
+	return sources, warnings, nil
+}
+
+// loadKubeconfigSecrets lê os kubeconfigs dos Secrets selecionados por label
+func loadKubeconfigSecrets(ctx context.Context, client kubernetes.Interface, namespace, selector string) ([]kubeconfigSource, []string, error) {
+	secrets, err := client.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list kubeconfig secrets in %s: %w", namespace, err)
+	}
+	sort.Slice(secrets.Items, func(i, j int) bool { return secrets.Items[i].Name < secrets.Items[j].Name })
+
+	var sources []kubeconfigSource
+	var warnings []string
+	for _, secret := range secrets.Items {
+		name := "secret " + namespace + "/" + secret.Name
+		data := secretKubeconfig(secret.Data)
+		if data == nil {
+			warnings = append(warnings, fmt.Sprintf("%s: no kubeconfig key (expected one of %s)", name, strings.Join(kubeconfigSecretKeys, ", ")))
+			continue
+		}
+		cfg, err := clientcmd.Load(data)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		sources = append(sources, kubeconfigSource{name: name, config: cfg})
+	}
+	return sources, warnings, nil
+}
+
+// secretKubeconfig escolhe a chave do Secret com o kubeconfig (ou a única chave existente)
+func secretKubeconfig(data map[string][]byte) []byte {
+	for _, key := range kubeconfigSecretKeys {
+		if value, ok := data[key]; ok {
+			return value
+		}
+	}
+	if len(data) == 1 {
+		for _, value := range data {
+			return value
+		}
+	}
+	return nil
+}
+
+// mergeKubeconfig copia os contextos de src para dst. Contextos já carregados são ignorados;
+// clusters e usuários com o mesmo nome e conteúdo diferente recebem o sufixo "@<contexto>".
+func mergeKubeconfig(dst, src *api.Config, source string) []string {
+	names := make([]string, 0, len(src.Contexts))
+	for name := range src.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var warnings []string
+	for _, name := range names {
+		if _, exists := dst.Contexts[name]; exists {
+			warnings = append(warnings, fmt.Sprintf("%s: context %q already loaded, ignored", source, name))
+			continue
+		}
+		kubeContext := src.Contexts[name].DeepCopy()
+		cluster, ok := src.Clusters[kubeContext.Cluster]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("%s: context %q references missing cluster %q", source, name, kubeContext.Cluster))
+			continue
+		}
+		authInfo, ok := src.AuthInfos[kubeContext.AuthInfo]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("%s: context %q references missing user %q", source, name, kubeContext.AuthInfo))
+			continue
+		}
+
+		kubeContext.Cluster = mergeEntry(dst.Clusters, kubeContext.Cluster, name, cluster.DeepCopy())
+		kubeContext.AuthInfo = mergeEntry(dst.AuthInfos, kubeContext.AuthInfo, name, authInfo.DeepCopy())
+		kubeContext.LocationOfOrigin = ""
+		dst.Contexts[name] = kubeContext
+	}
+	return warnings
+}
+
+// mergeEntry grava value em entries e retorna o nome usado (renomeia em caso de conflito)
+func mergeEntry[T any](entries map[string]*T, name, contextName string, value *T) string {
+	existing, exists := entries[name]
+	if !exists {
+		entries[name] = value
+		return name
+	}
+	if reflect.DeepEqual(existing, value) {
+		return name
+	}
+	renamed := name + "@" + contextName
+	entries[renamed] = value
+	return renamed
+}
+
+// useWorkloadIdentity troca o modo de login do kubelogin (e do auth-provider "azure" legado)
+// para workloadidentity. O kubelogin lê AZURE_CLIENT_ID, AZURE_TENANT_ID e
+// AZURE_FEDERATED_TOKEN_FILE, injetadas no pod pelo webhook de workload identity.
+func useWorkloadIdentity(cfg *api.Config) int {
+	converted := 0
+	for _, authInfo := range cfg.AuthInfos {
+		switch {
+		case authInfo.Exec != nil && isKubelogin(authInfo.Exec.Command):
+			authInfo.Exec.Args = workloadIdentityArgs(authInfo.Exec.Args)
+			authInfo.Exec.InteractiveMode = api.NeverExecInteractiveMode
+			converted++
+		case authInfo.AuthProvider != nil && authInfo.AuthProvider.Name == "azure":
+			args := []string{"get-token", "--login", "workloadidentity"}
+			if serverID := authInfo.AuthProvider.Config["apiserver-id"]; serverID != "" {
+				args = append(args, "--server-id", serverID)
+			}
+			if environment := authInfo.AuthProvider.Config["environment"]; environment != "" {
+				args = append(args, "--environment", environment)
+			}
+			authInfo.AuthProvider = nil
+			authInfo.Exec = &api.ExecConfig{
+				APIVersion:      "client.authentication.k8s.io/v1beta1",
+				Command:         "kubelogin",
+				Args:            args,
+				InteractiveMode: api.NeverExecInteractiveMode,
+			}
+			converted++
+		}
+	}
+	return converted
+}
+
+func isKubelogin(command string) bool {
+	return strings.TrimSuffix(filepath.Base(command), ".exe") == "kubelogin"
+}
+
+// workloadIdentityArgs mantém apenas --server-id e --environment dos argumentos do kubelogin;
+// client-id/tenant-id de outros modos de login não valem para a identidade do pod
+func workloadIdentityArgs(args []string) []string {
+	result := []string{"get-token", "--login", "workloadidentity"}
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		if !strings.HasPrefix(name, "-") {
+			continue // subcomando get-token
+		}
+		if !hasValue && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			value = args[i+1]
+			i++
+		}
+		switch name {
+		case "--server-id", "--environment", "-e":
+			result = append(result, name, value)
+		}
+	}
+	return result
+}
+
+// podNamespace namespace do pod (arquivo da ServiceAccount ou POD_NAMESPACE)
+func podNamespace() string {
+	if data, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+		if namespace := strings.TrimSpace(string(data)); namespace != "" {
+			return namespace
+		}
+	}
+	if namespace := os.Getenv("POD_NAMESPACE"); namespace != "" {
+		return namespace
+	}
+	return "default"
+}
@@ -0,0 +1,188 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: akspriv-prd
+  cluster:
+    server: https://prd.example.com
+contexts:
+- name: akspriv-prd-admin
+  context:
+    cluster: akspriv-prd
+    user: clusterAdmin_rg-prd_akspriv-prd
+users:
+- name: clusterAdmin_rg-prd_akspriv-prd
+  user:
+    token: prd-token
+`
+
+func TestInClusterKubeconfig(t *testing.T) {
+	cfg := inClusterKubeconfig("akspriv-mgmt-admin", &rest.Config{
+		Host:            "https://10.0.0.1:443",
+		BearerTokenFile: "/var/run/secrets/kubernetes.io/serviceaccount/token",
+		TLSClientConfig: rest.TLSClientConfig{CAFile: "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"},
+	})
+
+	kubeContext := cfg.Contexts["akspriv-mgmt-admin"]
+	if kubeContext == nil || kubeContext.Cluster != "akspriv-mgmt" || cfg.CurrentContext != "akspriv-mgmt-admin" {
+		t.Fatalf("unexpected contexts: %+v (current %q)", cfg.Contexts, cfg.CurrentContext)
+	}
+	if cluster := cfg.Clusters["akspriv-mgmt"]; cluster.Server != "https://10.0.0.1:443" || cluster.CertificateAuthority == "" {
+		t.Errorf("unexpected cluster: %+v", cluster)
+	}
+	if user := cfg.AuthInfos["akspriv-mgmt-admin"]; user.TokenFile == "" || user.Token != "" {
+		t.Errorf("token must be referenced by file, got %+v", user)
+	}
+}
+
+func TestMergeKubeconfig(t *testing.T) {
+	dst := inClusterKubeconfig("akspriv-mgmt-admin", &rest.Config{Host: "https://10.0.0.1:443"})
+
+	src, err := clientcmd.Load([]byte(testKubeconfig))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if warnings := mergeKubeconfig(dst, src, "file prd"); len(warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+
+	// Mesmo contexto em outra origem: ignorado
+	if warnings := mergeKubeconfig(dst, src, "secret prd"); len(warnings) != 1 {
+		t.Errorf("expected duplicate context warning, got %v", warnings)
+	}
+
+	// Outro contexto com cluster de mesmo nome e servidor diferente: cluster renomeado
+	other := src.DeepCopy()
+	other.Contexts["akspriv-prd-readonly"] = other.Contexts["akspriv-prd-admin"]
+	delete(other.Contexts, "akspriv-prd-admin")
+	other.Clusters["akspriv-prd"].Server = "https://prd-private.example.com"
+	mergeKubeconfig(dst, other, "secret readonly")
+
+	readonly := dst.Contexts["akspriv-prd-readonly"]
+	if readonly == nil || readonly.Cluster != "akspriv-prd@akspriv-prd-readonly" {
+		t.Fatalf("expected renamed cluster, got %+v", readonly)
+	}
+	if dst.Clusters["akspriv-prd"].Server != "https://prd.example.com" {
+		t.Errorf("original cluster overwritten: %+v", dst.Clusters["akspriv-prd"])
+	}
+	if readonly.AuthInfo != "clusterAdmin_rg-prd_akspriv-prd" {
+		t.Errorf("identical user should be shared, got %q", readonly.AuthInfo)
+	}
+}
+
+func TestLoadKubeconfigDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "akspriv-prd"), []byte(testKubeconfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken"), []byte("clusters: ["), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// Estrutura criada por volumes de Secret
+	if err := os.Mkdir(filepath.Join(dir, "..data"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	sources, warnings, err := loadKubeconfigDir(dir)
+	if err != nil {
+		t.Fatalf("loadKubeconfigDir failed: %v", err)
+	}
+	if len(sources) != 1 || sources[0].config.Contexts["akspriv-prd-admin"] == nil {
+		t.Errorf("expected akspriv-prd kubeconfig, got %+v", sources)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("expected warning for broken file, got %v", warnings)
+	}
+}
+
+func TestLoadKubeconfigSecrets(t *testing.T) {
+	labels := map[string]string{KubeconfigSecretLabel: "true"}
+	client := fake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "aks-prd", Namespace: "hpa-manager", Labels: labels},
+			Data:       map[string][]byte{"value": []byte(testKubeconfig)},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "aks-empty", Namespace: "hpa-manager", Labels: labels},
+			Data:       map[string][]byte{"a": nil, "b": nil},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "hpa-manager"},
+			Data:       map[string][]byte{"kubeconfig": []byte(testKubeconfig)},
+		},
+	)
+
+	sources, warnings, err := loadKubeconfigSecrets(context.Background(), client, "hpa-manager", KubeconfigSecretLabel+"=true")
+	if err != nil {
+		t.Fatalf("loadKubeconfigSecrets failed: %v", err)
+	}
+	if len(sources) != 1 || sources[0].name != "secret hpa-manager/aks-prd" {
+		t.Errorf("expected only aks-prd, got %+v", sources)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("expected warning for aks-empty, got %v", warnings)
+	}
+}
+
+func TestUseWorkloadIdentity(t *testing.T) {
+	cfg := api.NewConfig()
+	cfg.AuthInfos["devicecode"] = &api.AuthInfo{Exec: &api.ExecConfig{
+		Command: "kubelogin",
+		Args: []string{"get-token", "--login", "devicecode", "--server-id", "6dae42f8",
+			"--client-id=80faf920", "--tenant-id", "tenant", "--environment", "AzurePublicCloud"},
+	}}
+	cfg.AuthInfos["legacy"] = &api.AuthInfo{AuthProvider: &api.AuthProviderConfig{
+		Name:   "azure",
+		Config: map[string]string{"apiserver-id": "6dae42f8", "client-id": "80faf920"},
+	}}
+	cfg.AuthInfos["admin"] = &api.AuthInfo{Token: "admin-token"}
+
+	if converted := useWorkloadIdentity(cfg); converted != 2 {
+		t.Fatalf("expected 2 converted users, got %d", converted)
+	}
+
+	want := []string{"get-token", "--login", "workloadidentity", "--server-id", "6dae42f8", "--environment", "AzurePublicCloud"}
+	if got := cfg.AuthInfos["devicecode"].Exec.Args; !reflect.DeepEqual(got, want) {
+		t.Errorf("devicecode args = %v, want %v", got, want)
+	}
+	legacy := cfg.AuthInfos["legacy"]
+	if legacy.AuthProvider != nil || legacy.Exec == nil ||
+		!reflect.DeepEqual(legacy.Exec.Args, []string{"get-token", "--login", "workloadidentity", "--server-id", "6dae42f8"}) {
+		t.Errorf("unexpected legacy conversion: %+v", legacy)
+	}
+	if cfg.AuthInfos["admin"].Exec != nil {
+		t.Errorf("token user must not be converted")
+	}
+}
+
+func TestDiscoverAllContexts(t *testing.T) {
+	cfg := inClusterKubeconfig("mgmt", &rest.Config{Host: "https://10.0.0.1:443"})
+	manager := &KubeConfigManager{config: cfg}
+
+	if clusters := manager.DiscoverClusters(); len(clusters) != 0 {
+		t.Errorf("workstation mode should keep the akspriv- filter, got %+v", clusters)
+	}
+	manager.discoverAll = true
+	if clusters := manager.DiscoverClusters(); len(clusters) != 1 || clusters[0].Context != "mgmt" {
+		t.Errorf("expected mgmt cluster, got %+v", clusters)
+	}
+	if err := manager.ValidateConfig(); err != nil {
+		t.Errorf("ValidateConfig: %v", err)
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 
@@ -28,6 +29,9 @@ type ClusterConfig struct {
 	Name          string `json:"clusterName"` // Mudado para "clusterName" para coincidir com o formato original
 	ResourceGroup string `json:"resourceGroup"`
 	Subscription  string `json:"subscription"`
+	Provider      string `json:"provider,omitempty"` // aks (padrão se vazio), eks ou gke
+	Region        string `json:"region,omitempty"`
+	Project       string `json:"project,omitempty"`
 }
 
 // KubeConfigManager gerencia a configuração do Kubernetes
@@ -36,6 +40,7 @@ type KubeConfigManager struct {
 	config      *api.Config
 	clients     map[string]kubernetes.Interface
 	clientMutex sync.RWMutex // Protege acesso concorrente aos clients
+	discoverAll bool         // Modo servidor: lista todos os contextos, não só akspriv-*
 }
 
 // NewKubeConfigManager cria um novo gerenciador de kubeconfig
@@ -52,7 +57,7 @@ func NewKubeConfigManager(configPath string) (*KubeConfigManager, error) {
 	}, nil
 }
 
-// DiscoverClusters descobre clusters do kubeconfig que começam com "akspriv-" em ordem alfabética
+// DiscoverClusters descobre clusters do kubeconfig que começam com "akspriv-" (no modo servidor, todos) em ordem alfabética
 func (k *KubeConfigManager) DiscoverClusters() []models.Cluster {
 	var clusters []models.Cluster
 
@@ -62,7 +67,7 @@ func (k *KubeConfigManager) DiscoverClusters() []models.Cluster {
 	// Coletar clusters que começam com "akspriv-" e mapear para seus contexts
 	for contextName, context := range k.config.Contexts {
 		clusterName := context.Cluster
-		if strings.HasPrefix(clusterName, "akspriv-") {
+		if strings.HasPrefix(clusterName, "akspriv-") || k.discoverAll {
 			// Armazenar mapeamento cluster -> context
 			clusterToContext[clusterName] = contextName
 		}
@@ -107,6 +112,17 @@ func (k *KubeConfigManager) loadClustersFromConfig() []ClusterConfig {
 	return clusters
 }
 
+// FindClusterConfig busca um cluster no clusters-config.json (aceita o nome com ou sem sufixo -admin)
+func (k *KubeConfigManager) FindClusterConfig(clusterName string) (*ClusterConfig, bool) {
+	searchName := strings.TrimSuffix(clusterName, "-admin")
+	for _, cluster := range k.loadClustersFromConfig() {
+		if strings.TrimSuffix(cluster.Name, "-admin") == searchName {
+			return &cluster, true
+		}
+	}
+	return nil, false
+}
+
 // TestClusterConnection testa a conectividade com um cluster
 func (k *KubeConfigManager) TestClusterConnection(ctx context.Context, clusterName string) models.ConnectionStatus {
 	// Usar defer recover para capturar panics e converter em erro
@@ -164,6 +180,47 @@ func (k *KubeConfigManager) getClient(clusterName string) (kubernetes.Interface,
 		return client, nil
 	}
 
+	restConfig, err := k.restConfig(clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Configurar timeouts
+	restConfig.Timeout = 30 * time.Second
+	restConfig.QPS = 50
+	restConfig.Burst = 100
+
+	// Criar cliente Kubernetes
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client for %s: %w", clusterName, err)
+	}
+
+	// Armazenar cliente para reuso
+	k.clients[clusterName] = client
+
+	return client, nil
+}
+
+// GetWatchClient cria um cliente sem timeout de requisição para watches de longa duração
+// (informers). Não é cacheado: cada chamador é dono do cliente que recebe.
+func (k *KubeConfigManager) GetWatchClient(clusterName string) (kubernetes.Interface, error) {
+	restConfig, err := k.restConfig(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	restConfig.QPS = 50
+	restConfig.Burst = 100
+
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client for %s: %w", clusterName, err)
+	}
+	return client, nil
+}
+
+// restConfig monta a configuração REST do contexto a partir do kubeconfig
+func (k *KubeConfigManager) restConfig(clusterName string) (*rest.Config, error) {
 	// Verificar se o arquivo kubeconfig existe e é válido
 	if k.configPath == "" {
 		return nil, fmt.Errorf("kubeconfig path is empty")
@@ -192,22 +249,7 @@ func (k *KubeConfigManager) getClient(clusterName string) (kubernetes.Interface,
 		}
 		return nil, fmt.Errorf("failed to create client config for %s: %w", clusterName, err)
 	}
-
-	// Configurar timeouts
-	restConfig.Timeout = 30 * time.Second
-	restConfig.QPS = 50
-	restConfig.Burst = 100
-
-	// Criar cliente Kubernetes
-	client, err := kubernetes.NewForConfig(restConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create kubernetes client for %s: %w", clusterName, err)
-	}
-
-	// Armazenar cliente para reuso
-	k.clients[clusterName] = client
-
-	return client, nil
+	return restConfig, nil
 }
 
 // ListContexts retorna todos os contextos disponíveis
@@ -234,6 +276,10 @@ func (k *KubeConfigManager) ValidateConfig() error {
 		return fmt.Errorf("no contexts found in kubeconfig")
 	}
 
+	if k.discoverAll {
+		return nil
+	}
+
 	// Verificar se existem clusters akspriv-*
 	hasAksprivClusters := false
 	for contextName := range k.config.Contexts {
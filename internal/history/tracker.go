@@ -26,6 +26,7 @@ type HistoryEntry struct {
 	ErrorMsg    string                 `json:"error_msg,omitempty"`
 	Duration    int64                  `json:"duration_ms"`  // Tempo de execução em ms
 	SessionName string                 `json:"session_name,omitempty"`
+	User        string                 `json:"user,omitempty"` // usuário autenticado (ou scheduler)
 }
 
 // HistoryTracker gerencia o histórico de alterações
@@ -34,6 +35,8 @@ type HistoryTracker struct {
 	mutex        sync.RWMutex
 	historyDir   string
 	maxEntries   int // Limite de entradas em memória
+
+	listener func(HistoryEntry) // Chamado a cada Log (stream de eventos da interface web)
 }
 
 // NewHistoryTracker cria um novo tracker
@@ -90,9 +93,21 @@ func (ht *HistoryTracker) Log(entry HistoryEntry) error {
 		}
 	}()
 
+	if ht.listener != nil {
+		ht.listener(entry)
+	}
+
 	return nil
 }
 
+// SetListener registra uma função chamada a cada nova entrada. É chamada com o lock
+// do tracker adquirido, portanto não deve bloquear nem acessar o tracker.
+func (ht *HistoryTracker) SetListener(listener func(HistoryEntry)) {
+	ht.mutex.Lock()
+	defer ht.mutex.Unlock()
+	ht.listener = listener
+}
+
 // GetAll retorna todas as entradas do histórico
 func (ht *HistoryTracker) GetAll() []HistoryEntry {
 	ht.mutex.RLock()
@@ -288,6 +303,10 @@ const (
 	ActionDeleteSession     = "delete_session"
 	ActionApplyBatch        = "apply_batch"
 	ActionSnapshotCluster   = "snapshot_cluster"
+	ActionApplySession      = "apply_session"
+	ActionRollbackSession   = "rollback_session"
+	ActionScheduledApply    = "scheduled_apply"
+	ActionScheduledRollback = "scheduled_rollback"
 )
 
 // Status constants
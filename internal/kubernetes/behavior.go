@@ -0,0 +1,187 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s-hpa-manager/internal/models"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+)
+
+// Limites do autoscaling/v2 para behavior
+const (
+	maxStabilizationWindowSeconds = 3600
+	maxPolicyPeriodSeconds        = 1800
+)
+
+// BehaviorFromKubernetes converte spec.behavior do Kubernetes para o modelo interno
+func BehaviorFromKubernetes(behavior *autoscalingv2.HorizontalPodAutoscalerBehavior) *models.HPABehavior {
+	if behavior == nil {
+		return nil
+	}
+
+	return &models.HPABehavior{
+		ScaleUp:   scalingRulesFromKubernetes(behavior.ScaleUp),
+		ScaleDown: scalingRulesFromKubernetes(behavior.ScaleDown),
+	}
+}
+
+// BehaviorToKubernetes converte o modelo interno para spec.behavior do Kubernetes.
+// Retorna nil para behavior vazio (remove o behavior customizado do HPA).
+func BehaviorToKubernetes(behavior *models.HPABehavior) *autoscalingv2.HorizontalPodAutoscalerBehavior {
+	if behavior == nil || (behavior.ScaleUp == nil && behavior.ScaleDown == nil) {
+		return nil
+	}
+
+	return &autoscalingv2.HorizontalPodAutoscalerBehavior{
+		ScaleUp:   scalingRulesToKubernetes(behavior.ScaleUp),
+		ScaleDown: scalingRulesToKubernetes(behavior.ScaleDown),
+	}
+}
+
+func scalingRulesFromKubernetes(rules *autoscalingv2.HPAScalingRules) *models.HPAScalingRules {
+	if rules == nil {
+		return nil
+	}
+
+	result := &models.HPAScalingRules{}
+	if rules.StabilizationWindowSeconds != nil {
+		window := *rules.StabilizationWindowSeconds
+		result.StabilizationWindowSeconds = &window
+	}
+	if rules.SelectPolicy != nil {
+		selectPolicy := string(*rules.SelectPolicy)
+		result.SelectPolicy = &selectPolicy
+	}
+	for _, policy := range rules.Policies {
+		result.Policies = append(result.Policies, models.HPAScalingPolicy{
+			Type:          string(policy.Type),
+			Value:         policy.Value,
+			PeriodSeconds: policy.PeriodSeconds,
+		})
+	}
+	return result
+}
+
+func scalingRulesToKubernetes(rules *models.HPAScalingRules) *autoscalingv2.HPAScalingRules {
+	if rules == nil {
+		return nil
+	}
+
+	result := &autoscalingv2.HPAScalingRules{}
+	if rules.StabilizationWindowSeconds != nil {
+		window := *rules.StabilizationWindowSeconds
+		result.StabilizationWindowSeconds = &window
+	}
+	if rules.SelectPolicy != nil {
+		selectPolicy := autoscalingv2.ScalingPolicySelect(*rules.SelectPolicy)
+		result.SelectPolicy = &selectPolicy
+	}
+	for _, policy := range rules.Policies {
+		result.Policies = append(result.Policies, autoscalingv2.HPAScalingPolicy{
+			Type:          autoscalingv2.HPAScalingPolicyType(policy.Type),
+			Value:         policy.Value,
+			PeriodSeconds: policy.PeriodSeconds,
+		})
+	}
+	return result
+}
+
+// ValidateBehavior valida o behavior com as mesmas regras do apiserver
+func ValidateBehavior(behavior *models.HPABehavior) error {
+	if behavior == nil {
+		return nil
+	}
+	if err := validateScalingRules("scaleUp", behavior.ScaleUp); err != nil {
+		return err
+	}
+	return validateScalingRules("scaleDown", behavior.ScaleDown)
+}
+
+func validateScalingRules(direction string, rules *models.HPAScalingRules) error {
+	if rules == nil {
+		return nil
+	}
+
+	if rules.StabilizationWindowSeconds != nil {
+		window := *rules.StabilizationWindowSeconds
+		if window < 0 || window > maxStabilizationWindowSeconds {
+			return fmt.Errorf("%s.stabilizationWindowSeconds must be between 0 and %d, got %d", direction, maxStabilizationWindowSeconds, window)
+		}
+	}
+
+	if rules.SelectPolicy != nil {
+		switch *rules.SelectPolicy {
+		case models.HPASelectPolicyMax, models.HPASelectPolicyMin, models.HPASelectPolicyDisabled:
+		default:
+			return fmt.Errorf("%s.selectPolicy must be Max, Min or Disabled, got %q", direction, *rules.SelectPolicy)
+		}
+	}
+
+	for i, policy := range rules.Policies {
+		switch policy.Type {
+		case models.HPAScalingPolicyPods, models.HPAScalingPolicyPercent:
+		default:
+			return fmt.Errorf("%s.policies[%d].type must be Pods or Percent, got %q", direction, i, policy.Type)
+		}
+		if policy.Value <= 0 {
+			return fmt.Errorf("%s.policies[%d].value must be > 0, got %d", direction, i, policy.Value)
+		}
+		if policy.PeriodSeconds <= 0 || policy.PeriodSeconds > maxPolicyPeriodSeconds {
+			return fmt.Errorf("%s.policies[%d].periodSeconds must be between 1 and %d, got %d", direction, i, maxPolicyPeriodSeconds, policy.PeriodSeconds)
+		}
+	}
+
+	return nil
+}
+
+// FormatScalingPolicies formata policies no formato de edição "Tipo:Valor:Período" separado por vírgula
+func FormatScalingPolicies(policies []models.HPAScalingPolicy) string {
+	parts := make([]string, 0, len(policies))
+	for _, policy := range policies {
+		parts = append(parts, fmt.Sprintf("%s:%d:%d", policy.Type, policy.Value, policy.PeriodSeconds))
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseScalingPolicies interpreta o formato "Pods:4:60,Percent:100:15" (vazio = sem policies)
+func ParseScalingPolicies(value string) ([]models.HPAScalingPolicy, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	var policies []models.HPAScalingPolicy
+	for _, item := range strings.Split(value, ",") {
+		fields := strings.Split(strings.TrimSpace(item), ":")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid policy %q: expected Type:Value:PeriodSeconds", item)
+		}
+
+		policyType := strings.TrimSpace(fields[0])
+		switch strings.ToLower(policyType) {
+		case "pods":
+			policyType = models.HPAScalingPolicyPods
+		case "percent":
+			policyType = models.HPAScalingPolicyPercent
+		}
+
+		policyValue, err := strconv.ParseInt(strings.TrimSpace(fields[1]), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid policy value in %q: %w", item, err)
+		}
+		period, err := strconv.ParseInt(strings.TrimSpace(fields[2]), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid policy period in %q: %w", item, err)
+		}
+
+		policies = append(policies, models.HPAScalingPolicy{
+			Type:          policyType,
+			Value:         int32(policyValue),
+			PeriodSeconds: int32(period),
+		})
+	}
+	return policies, nil
+}
@@ -0,0 +1,123 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"k8s-hpa-manager/internal/models"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func int32Ptr(v int32) *int32 { return &v }
+
+func stringPtr(v string) *string { return &v }
+
+// TestParseScalingPolicies valida o formato de edição de policies usado pela TUI
+func TestParseScalingPolicies(t *testing.T) {
+	policies, err := ParseScalingPolicies("pods:4:60, Percent:100:15")
+	if err != nil {
+		t.Fatalf("ParseScalingPolicies() erro inesperado: %v", err)
+	}
+	if len(policies) != 2 || policies[0].Type != models.HPAScalingPolicyPods || policies[1].Value != 100 || policies[1].PeriodSeconds != 15 {
+		t.Fatalf("policies inesperadas: %+v", policies)
+	}
+	if got := FormatScalingPolicies(policies); got != "Pods:4:60,Percent:100:15" {
+		t.Errorf("FormatScalingPolicies() = %q", got)
+	}
+
+	if policies, err := ParseScalingPolicies(""); err != nil || policies != nil {
+		t.Errorf("esperado nil para valor vazio, obtido %+v, %v", policies, err)
+	}
+	if _, err := ParseScalingPolicies("Pods:4"); err == nil {
+		t.Error("esperado erro para policy incompleta")
+	}
+}
+
+// TestValidateBehavior valida os limites aceitos pelo apiserver
+func TestValidateBehavior(t *testing.T) {
+	valid := &models.HPABehavior{
+		ScaleDown: &models.HPAScalingRules{
+			StabilizationWindowSeconds: int32Ptr(300),
+			SelectPolicy:               stringPtr(models.HPASelectPolicyMin),
+			Policies:                   []models.HPAScalingPolicy{{Type: models.HPAScalingPolicyPercent, Value: 10, PeriodSeconds: 60}},
+		},
+	}
+	if err := ValidateBehavior(valid); err != nil {
+		t.Errorf("behavior válido rejeitado: %v", err)
+	}
+
+	invalid := []*models.HPABehavior{
+		{ScaleUp: &models.HPAScalingRules{StabilizationWindowSeconds: int32Ptr(3601)}},
+		{ScaleUp: &models.HPAScalingRules{SelectPolicy: stringPtr("Any")}},
+		{ScaleDown: &models.HPAScalingRules{Policies: []models.HPAScalingPolicy{{Type: "Nodes", Value: 1, PeriodSeconds: 60}}}},
+		{ScaleDown: &models.HPAScalingRules{Policies: []models.HPAScalingPolicy{{Type: "Pods", Value: 1, PeriodSeconds: 1801}}}},
+	}
+	for i, behavior := range invalid {
+		if err := ValidateBehavior(behavior); err == nil {
+			t.Errorf("caso %d: esperado erro de validação", i)
+		}
+	}
+}
+
+// TestUpdateHPABehavior valida leitura, alteração e remoção de spec.behavior
+func TestUpdateHPABehavior(t *testing.T) {
+	selectMax := autoscalingv2.MaxChangePolicySelect
+	clientset := fake.NewSimpleClientset(&autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			MinReplicas: int32Ptr(2),
+			MaxReplicas: 10,
+			Behavior: &autoscalingv2.HorizontalPodAutoscalerBehavior{
+				ScaleUp: &autoscalingv2.HPAScalingRules{
+					SelectPolicy: &selectMax,
+					Policies:     []autoscalingv2.HPAScalingPolicy{{Type: autoscalingv2.PodsScalingPolicy, Value: 4, PeriodSeconds: 15}},
+				},
+			},
+		},
+	})
+	client := NewClient(clientset, "test-cluster")
+	ctx := context.Background()
+
+	hpa, err := client.GetHPA(ctx, "default", "api")
+	if err != nil {
+		t.Fatalf("GetHPA() erro inesperado: %v", err)
+	}
+	if hpa.Behavior == nil || hpa.Behavior.ScaleUp == nil || *hpa.Behavior.ScaleUp.SelectPolicy != "Max" {
+		t.Fatalf("behavior não convertido: %+v", hpa.Behavior)
+	}
+	if hpa.OriginalValues.Behavior == hpa.Behavior {
+		t.Error("OriginalValues.Behavior não deve compartilhar ponteiro com Behavior")
+	}
+
+	// Alterar apenas o scaleDown mantendo o scaleUp
+	hpa.Behavior.ScaleDown = &models.HPAScalingRules{StabilizationWindowSeconds: int32Ptr(600)}
+	if err := client.UpdateHPA(ctx, hpa); err != nil {
+		t.Fatalf("UpdateHPA() erro inesperado: %v", err)
+	}
+
+	updated, _ := clientset.AutoscalingV2().HorizontalPodAutoscalers("default").Get(ctx, "api", metav1.GetOptions{})
+	behavior := updated.Spec.Behavior
+	if behavior == nil || behavior.ScaleDown == nil || *behavior.ScaleDown.StabilizationWindowSeconds != 600 ||
+		behavior.ScaleUp == nil || len(behavior.ScaleUp.Policies) != 1 {
+		t.Fatalf("spec.behavior inesperado: %+v", behavior)
+	}
+
+	// Behavior inválido não deve ser enviado ao cluster
+	hpa.Behavior.ScaleDown.StabilizationWindowSeconds = int32Ptr(-1)
+	if err := client.UpdateHPA(ctx, hpa); err == nil {
+		t.Error("esperado erro de validação")
+	}
+
+	// Behavior vazio remove o behavior customizado
+	hpa.Behavior = &models.HPABehavior{}
+	if err := client.UpdateHPA(ctx, hpa); err != nil {
+		t.Fatalf("UpdateHPA() erro inesperado: %v", err)
+	}
+	updated, _ = clientset.AutoscalingV2().HorizontalPodAutoscalers("default").Get(ctx, "api", metav1.GetOptions{})
+	if updated.Spec.Behavior != nil {
+		t.Errorf("esperado spec.behavior removido, obtido %+v", updated.Spec.Behavior)
+	}
+}
@@ -342,6 +342,22 @@ func (c *Client) CountHPAs(ctx context.Context, namespace string) (int, error) {
 
 // UpdateHPA aplica mudanças em um HPA específico
 func (c *Client) UpdateHPA(ctx context.Context, hpa models.HPA) error {
+	return c.updateHPA(ctx, hpa, false)
+}
+
+// DryRunUpdateHPA valida as mudanças do HPA (e dos recursos do deployment) via server-side dry-run.
+// Nada é persistido e nenhum rollout é executado.
+func (c *Client) DryRunUpdateHPA(ctx context.Context, hpa models.HPA) error {
+	return c.updateHPA(ctx, hpa, true)
+}
+
+// updateHPA implementa UpdateHPA/DryRunUpdateHPA
+func (c *Client) updateHPA(ctx context.Context, hpa models.HPA, dryRun bool) error {
+	updateOptions := metav1.UpdateOptions{}
+	if dryRun {
+		updateOptions.DryRun = []string{metav1.DryRunAll}
+	}
+
 	// Obter o HPA atual do cluster
 	currentHPA, err := c.clientset.AutoscalingV2().HorizontalPodAutoscalers(hpa.Namespace).Get(ctx, hpa.Name, metav1.GetOptions{})
 	if err != nil {
@@ -354,66 +370,21 @@ func (c *Client) UpdateHPA(ctx context.Context, hpa models.HPA) error {
 	}
 	currentHPA.Spec.MaxReplicas = hpa.MaxReplicas
 
-	// Aplicar mudanças de CPU target se especificado
-	if hpa.TargetCPU != nil {
-		// Encontrar ou criar métrica de CPU
-		found := false
-		for i, metric := range currentHPA.Spec.Metrics {
-			if metric.Type == autoscalingv2.ResourceMetricSourceType &&
-				metric.Resource != nil &&
-				metric.Resource.Name == "cpu" {
-				currentHPA.Spec.Metrics[i].Resource.Target.AverageUtilization = hpa.TargetCPU
-				found = true
-				break
-			}
-		}
-		if !found {
-			// Adicionar métrica de CPU se não existir
-			cpuMetric := autoscalingv2.MetricSpec{
-				Type: autoscalingv2.ResourceMetricSourceType,
-				Resource: &autoscalingv2.ResourceMetricSource{
-					Name: "cpu",
-					Target: autoscalingv2.MetricTarget{
-						Type:               autoscalingv2.UtilizationMetricType,
-						AverageUtilization: hpa.TargetCPU,
-					},
-				},
-			}
-			currentHPA.Spec.Metrics = append(currentHPA.Spec.Metrics, cpuMetric)
-		}
+	// Aplicar métricas (lista genérica + targets de CPU/Memory)
+	if err := c.updateHPAMetrics(currentHPA, &hpa); err != nil {
+		return fmt.Errorf("invalid metrics for HPA %s/%s: %w", hpa.Namespace, hpa.Name, err)
 	}
 
-	// Aplicar mudanças de Memory target se especificado
-	if hpa.TargetMemory != nil {
-		// Encontrar ou criar métrica de Memory
-		found := false
-		for i, metric := range currentHPA.Spec.Metrics {
-			if metric.Type == autoscalingv2.ResourceMetricSourceType &&
-				metric.Resource != nil &&
-				metric.Resource.Name == "memory" {
-				currentHPA.Spec.Metrics[i].Resource.Target.AverageUtilization = hpa.TargetMemory
-				found = true
-				break
-			}
-		}
-		if !found {
-			// Adicionar métrica de Memory se não existir
-			memoryMetric := autoscalingv2.MetricSpec{
-				Type: autoscalingv2.ResourceMetricSourceType,
-				Resource: &autoscalingv2.ResourceMetricSource{
-					Name: "memory",
-					Target: autoscalingv2.MetricTarget{
-						Type:               autoscalingv2.UtilizationMetricType,
-						AverageUtilization: hpa.TargetMemory,
-					},
-				},
-			}
-			currentHPA.Spec.Metrics = append(currentHPA.Spec.Metrics, memoryMetric)
+	// Aplicar behavior se especificado (behavior vazio remove o behavior customizado)
+	if hpa.Behavior != nil {
+		if err := ValidateBehavior(hpa.Behavior); err != nil {
+			return fmt.Errorf("invalid behavior for HPA %s/%s: %w", hpa.Namespace, hpa.Name, err)
 		}
+		currentHPA.Spec.Behavior = BehaviorToKubernetes(hpa.Behavior)
 	}
 
 	// Aplicar as mudanças no cluster
-	_, err = c.clientset.AutoscalingV2().HorizontalPodAutoscalers(hpa.Namespace).Update(ctx, currentHPA, metav1.UpdateOptions{})
+	_, err = c.clientset.AutoscalingV2().HorizontalPodAutoscalers(hpa.Namespace).Update(ctx, currentHPA, updateOptions)
 	if err != nil {
 		return fmt.Errorf("failed to update HPA %s/%s in cluster %s: %w", hpa.Namespace, hpa.Name, c.cluster, err)
 	}
@@ -477,7 +448,7 @@ func (c *Client) UpdateHPA(ctx context.Context, hpa models.HPA) error {
 				}
 
 				// Atualizar deployment
-				_, err = c.clientset.AppsV1().Deployments(hpa.Namespace).Update(ctx, deployment, metav1.UpdateOptions{})
+				_, err = c.clientset.AppsV1().Deployments(hpa.Namespace).Update(ctx, deployment, updateOptions)
 				if err != nil {
 					return fmt.Errorf("failed to update deployment resources %s/%s: %w", hpa.Namespace, deploymentName, err)
 				}
@@ -485,6 +456,10 @@ func (c *Client) UpdateHPA(ctx context.Context, hpa models.HPA) error {
 		}
 	}
 
+	if dryRun {
+		return nil
+	}
+
 	// Executar rollout de Deployment se solicitado
 	if err := c.TriggerRollout(ctx, hpa); err != nil {
 		// Log warning but don't fail the update
@@ -719,7 +694,12 @@ func (c *Client) TestConnection(ctx context.Context) error {
 	return err
 }
 
-// CountHPAs conta o número de HPAs em um namespace
+// HPAFromKubernetes converte um HPA para o modelo da API, sem os recursos do deployment
+// (usado pelos informers do stream de eventos)
+func HPAFromKubernetes(cluster string, hpa *autoscalingv2.HorizontalPodAutoscaler) models.HPA {
+	return (&Client{cluster: cluster}).convertHPAToModel(hpa)
+}
+
 // convertHPAToModel converte um HPA do Kubernetes para o modelo interno
 func (c *Client) convertHPAToModel(hpa *autoscalingv2.HorizontalPodAutoscaler) models.HPA {
 	modelHPA := models.HPA{
@@ -729,6 +709,8 @@ func (c *Client) convertHPAToModel(hpa *autoscalingv2.HorizontalPodAutoscaler) m
 		MinReplicas:     hpa.Spec.MinReplicas,
 		MaxReplicas:     hpa.Spec.MaxReplicas,
 		CurrentReplicas: hpa.Status.CurrentReplicas,
+		Metrics:         MetricsFromKubernetes(hpa.Spec.Metrics, hpa.Status.CurrentMetrics),
+		Behavior:        BehaviorFromKubernetes(hpa.Spec.Behavior),
 		LastUpdated:     time.Now(), // HPA doesn't have LastUpdateTime field
 	}
 
@@ -754,59 +736,27 @@ func (c *Client) convertHPAToModel(hpa *autoscalingv2.HorizontalPodAutoscaler) m
 		MaxReplicas:  modelHPA.MaxReplicas,
 		TargetCPU:    modelHPA.TargetCPU,
 		TargetMemory: modelHPA.TargetMemory,
+		Metrics:      models.CloneHPAMetrics(modelHPA.Metrics),
+		Behavior:     modelHPA.Behavior.Clone(),
 	}
 
 	return modelHPA
 }
 
-// updateHPAMetrics atualiza as métricas de um HPA
-func (c *Client) updateHPAMetrics(hpa *autoscalingv2.HorizontalPodAutoscaler, model *models.HPA) {
-	// Atualizar ou criar métricas
-	for i, metric := range hpa.Spec.Metrics {
-		if metric.Type == autoscalingv2.ResourceMetricSourceType && metric.Resource != nil {
-			switch metric.Resource.Name {
-			case corev1.ResourceCPU:
-				if model.TargetCPU != nil {
-					hpa.Spec.Metrics[i].Resource.Target.AverageUtilization = model.TargetCPU
-				}
-			case corev1.ResourceMemory:
-				if model.TargetMemory != nil {
-					hpa.Spec.Metrics[i].Resource.Target.AverageUtilization = model.TargetMemory
-				}
-			}
+// updateHPAMetrics atualiza as métricas de um HPA.
+// A lista Metrics (quando informada) substitui spec.metrics; TargetCPU/TargetMemory prevalecem para cpu/memory.
+func (c *Client) updateHPAMetrics(hpa *autoscalingv2.HorizontalPodAutoscaler, model *models.HPA) error {
+	if model.Metrics != nil {
+		specs, err := MetricsToKubernetes(model.Metrics)
+		if err != nil {
+			return err
 		}
+		hpa.Spec.Metrics = specs
 	}
 
-	// Se não existem métricas, criar novas
-	if len(hpa.Spec.Metrics) == 0 {
-		if model.TargetCPU != nil {
-			cpuMetric := autoscalingv2.MetricSpec{
-				Type: autoscalingv2.ResourceMetricSourceType,
-				Resource: &autoscalingv2.ResourceMetricSource{
-					Name: corev1.ResourceCPU,
-					Target: autoscalingv2.MetricTarget{
-						Type:               autoscalingv2.UtilizationMetricType,
-						AverageUtilization: model.TargetCPU,
-					},
-				},
-			}
-			hpa.Spec.Metrics = append(hpa.Spec.Metrics, cpuMetric)
-		}
-
-		if model.TargetMemory != nil {
-			memoryMetric := autoscalingv2.MetricSpec{
-				Type: autoscalingv2.ResourceMetricSourceType,
-				Resource: &autoscalingv2.ResourceMetricSource{
-					Name: corev1.ResourceMemory,
-					Target: autoscalingv2.MetricTarget{
-						Type:               autoscalingv2.UtilizationMetricType,
-						AverageUtilization: model.TargetMemory,
-					},
-				},
-			}
-			hpa.Spec.Metrics = append(hpa.Spec.Metrics, memoryMetric)
-		}
-	}
+	updateResourceUtilization(hpa, corev1.ResourceCPU, model.TargetCPU)
+	updateResourceUtilization(hpa, corev1.ResourceMemory, model.TargetMemory)
+	return nil
 }
 
 // DiscoverClusterResources descobre recursos do cluster em todos os namespaces
@@ -1216,20 +1166,29 @@ func isPrometheusRelated(name, namespace string) bool {
 
 // ApplyResourceChanges aplica mudanças nos recursos do cluster
 func (c *Client) ApplyResourceChanges(resource *models.ClusterResource) error {
+	return c.applyResourceChanges(resource, metav1.UpdateOptions{})
+}
+
+// DryRunResourceChanges valida as mudanças de recursos do workload via server-side dry-run
+func (c *Client) DryRunResourceChanges(resource *models.ClusterResource) error {
+	return c.applyResourceChanges(resource, metav1.UpdateOptions{DryRun: []string{metav1.DryRunAll}})
+}
+
+func (c *Client) applyResourceChanges(resource *models.ClusterResource, opts metav1.UpdateOptions) error {
 	switch resource.WorkloadType {
 	case "Deployment":
-		return c.updateDeploymentResources(resource)
+		return c.updateDeploymentResources(resource, opts)
 	case "StatefulSet":
-		return c.updateStatefulSetResources(resource)
+		return c.updateStatefulSetResources(resource, opts)
 	case "DaemonSet":
-		return c.updateDaemonSetResources(resource)
+		return c.updateDaemonSetResources(resource, opts)
 	default:
 		return fmt.Errorf("unsupported workload type: %s", resource.WorkloadType)
 	}
 }
 
 // updateDeploymentResources atualiza recursos de um Deployment
-func (c *Client) updateDeploymentResources(clusterResource *models.ClusterResource) error {
+func (c *Client) updateDeploymentResources(clusterResource *models.ClusterResource, opts metav1.UpdateOptions) error {
 	deployment, err := c.clientset.AppsV1().Deployments(clusterResource.Namespace).Get(
 		context.Background(), clusterResource.Name, metav1.GetOptions{})
 	if err != nil {
@@ -1282,12 +1241,12 @@ func (c *Client) updateDeploymentResources(clusterResource *models.ClusterResour
 	}
 
 	_, err = c.clientset.AppsV1().Deployments(clusterResource.Namespace).Update(
-		context.Background(), deployment, metav1.UpdateOptions{})
+		context.Background(), deployment, opts)
 	return err
 }
 
 // updateStatefulSetResources atualiza recursos de um StatefulSet
-func (c *Client) updateStatefulSetResources(clusterResource *models.ClusterResource) error {
+func (c *Client) updateStatefulSetResources(clusterResource *models.ClusterResource, opts metav1.UpdateOptions) error {
 	sts, err := c.clientset.AppsV1().StatefulSets(clusterResource.Namespace).Get(
 		context.Background(), clusterResource.Name, metav1.GetOptions{})
 	if err != nil {
@@ -1340,12 +1299,12 @@ func (c *Client) updateStatefulSetResources(clusterResource *models.ClusterResou
 	}
 
 	_, err = c.clientset.AppsV1().StatefulSets(clusterResource.Namespace).Update(
-		context.Background(), sts, metav1.UpdateOptions{})
+		context.Background(), sts, opts)
 	return err
 }
 
 // updateDaemonSetResources atualiza recursos de um DaemonSet
-func (c *Client) updateDaemonSetResources(clusterResource *models.ClusterResource) error {
+func (c *Client) updateDaemonSetResources(clusterResource *models.ClusterResource, opts metav1.UpdateOptions) error {
 	ds, err := c.clientset.AppsV1().DaemonSets(clusterResource.Namespace).Get(
 		context.Background(), clusterResource.Name, metav1.GetOptions{})
 	if err != nil {
@@ -1393,7 +1352,7 @@ func (c *Client) updateDaemonSetResources(clusterResource *models.ClusterResourc
 	}
 
 	_, err = c.clientset.AppsV1().DaemonSets(clusterResource.Namespace).Update(
-		context.Background(), ds, metav1.UpdateOptions{})
+		context.Background(), ds, opts)
 	return err
 }
 
@@ -1647,6 +1606,23 @@ func (c *Client) RolloutDaemonSet(ctx context.Context, namespace, daemonSetName
 // Node Pool Cordon/Drain Operations
 // ===========================
 
+// nodePoolLabelKeys labels que identificam o node pool de um node em cada provedor
+var nodePoolLabelKeys = []string{
+	"agentpool",                     // AKS
+	"eks.amazonaws.com/nodegroup",   // EKS managed node groups
+	"cloud.google.com/gke-nodepool", // GKE
+}
+
+// NodePoolName node pool do node pelos labels do provedor ("" se não identificado)
+func NodePoolName(node *corev1.Node) string {
+	for _, labelKey := range nodePoolLabelKeys {
+		if pool, ok := node.Labels[labelKey]; ok {
+			return pool
+		}
+	}
+	return ""
+}
+
 // GetNodesInNodePool retorna lista de nodes de um node pool específico
 func (c *Client) GetNodesInNodePool(ctx context.Context, nodePoolName string) ([]string, error) {
 	// Listar todos os nodes do cluster
@@ -1655,11 +1631,14 @@ func (c *Client) GetNodesInNodePool(ctx context.Context, nodePoolName string) ([
 		return nil, fmt.Errorf("failed to list nodes in cluster %s: %w", c.cluster, err)
 	}
 
-	// Filtrar nodes pelo label agentpool=<nodePoolName>
+	// Filtrar nodes pelo label de node pool (AKS, EKS ou GKE)
 	var nodeNames []string
 	for _, node := range nodes.Items {
-		if agentpool, ok := node.Labels["agentpool"]; ok && agentpool == nodePoolName {
-			nodeNames = append(nodeNames, node.Name)
+		for _, labelKey := range nodePoolLabelKeys {
+			if pool, ok := node.Labels[labelKey]; ok && pool == nodePoolName {
+				nodeNames = append(nodeNames, node.Name)
+				break
+			}
 		}
 	}
 
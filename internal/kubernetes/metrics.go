@@ -0,0 +1,479 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s-hpa-manager/internal/models"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MetricsFromKubernetes converte spec.metrics (e os valores atuais do status) para o modelo interno
+func MetricsFromKubernetes(specs []autoscalingv2.MetricSpec, current []autoscalingv2.MetricStatus) []models.HPAMetric {
+	if specs == nil {
+		return nil
+	}
+
+	metrics := make([]models.HPAMetric, 0, len(specs))
+	for _, spec := range specs {
+		metric := models.HPAMetric{Type: string(spec.Type)}
+		var target autoscalingv2.MetricTarget
+
+		switch spec.Type {
+		case autoscalingv2.ResourceMetricSourceType:
+			if spec.Resource == nil {
+				continue
+			}
+			metric.Name = string(spec.Resource.Name)
+			target = spec.Resource.Target
+		case autoscalingv2.ContainerResourceMetricSourceType:
+			if spec.ContainerResource == nil {
+				continue
+			}
+			metric.Name = string(spec.ContainerResource.Name)
+			metric.Container = spec.ContainerResource.Container
+			target = spec.ContainerResource.Target
+		case autoscalingv2.PodsMetricSourceType:
+			if spec.Pods == nil {
+				continue
+			}
+			metric.Name = spec.Pods.Metric.Name
+			metric.Selector = formatSelector(spec.Pods.Metric.Selector)
+			target = spec.Pods.Target
+		case autoscalingv2.ObjectMetricSourceType:
+			if spec.Object == nil {
+				continue
+			}
+			metric.Name = spec.Object.Metric.Name
+			metric.Selector = formatSelector(spec.Object.Metric.Selector)
+			metric.DescribedObject = &models.HPAMetricObjectRef{
+				APIVersion: spec.Object.DescribedObject.APIVersion,
+				Kind:       spec.Object.DescribedObject.Kind,
+				Name:       spec.Object.DescribedObject.Name,
+			}
+			target = spec.Object.Target
+		case autoscalingv2.ExternalMetricSourceType:
+			if spec.External == nil {
+				continue
+			}
+			metric.Name = spec.External.Metric.Name
+			metric.Selector = formatSelector(spec.External.Metric.Selector)
+			target = spec.External.Target
+		default:
+			continue
+		}
+
+		metric.TargetType = string(target.Type)
+		switch target.Type {
+		case autoscalingv2.UtilizationMetricType:
+			if target.AverageUtilization != nil {
+				utilization := *target.AverageUtilization
+				metric.TargetUtilization = &utilization
+			}
+		case autoscalingv2.ValueMetricType:
+			if target.Value != nil {
+				metric.TargetValue = target.Value.String()
+			}
+		case autoscalingv2.AverageValueMetricType:
+			if target.AverageValue != nil {
+				metric.TargetValue = target.AverageValue.String()
+			}
+		}
+
+		metric.Current = currentMetricValue(&metric, current)
+		metrics = append(metrics, metric)
+	}
+	return metrics
+}
+
+// MetricsToKubernetes converte a lista de métricas do modelo interno para spec.metrics
+func MetricsToKubernetes(metrics []models.HPAMetric) ([]autoscalingv2.MetricSpec, error) {
+	if err := ValidateMetrics(metrics); err != nil {
+		return nil, err
+	}
+
+	specs := make([]autoscalingv2.MetricSpec, 0, len(metrics))
+	for _, metric := range metrics {
+		target, err := metricTargetToKubernetes(&metric)
+		if err != nil {
+			return nil, err
+		}
+
+		spec := autoscalingv2.MetricSpec{Type: autoscalingv2.MetricSourceType(metric.Type)}
+		switch metric.Type {
+		case models.HPAMetricResource:
+			spec.Resource = &autoscalingv2.ResourceMetricSource{
+				Name:   corev1.ResourceName(metric.Name),
+				Target: target,
+			}
+		case models.HPAMetricContainerResource:
+			spec.ContainerResource = &autoscalingv2.ContainerResourceMetricSource{
+				Name:      corev1.ResourceName(metric.Name),
+				Container: metric.Container,
+				Target:    target,
+			}
+		case models.HPAMetricPods, models.HPAMetricObject, models.HPAMetricExternal:
+			identifier := autoscalingv2.MetricIdentifier{Name: metric.Name}
+			if metric.Selector != "" {
+				selector, err := metav1.ParseToLabelSelector(metric.Selector)
+				if err != nil {
+					return nil, fmt.Errorf("invalid selector for metric %s: %w", metric.Name, err)
+				}
+				identifier.Selector = selector
+			}
+
+			switch metric.Type {
+			case models.HPAMetricPods:
+				spec.Pods = &autoscalingv2.PodsMetricSource{Metric: identifier, Target: target}
+			case models.HPAMetricObject:
+				spec.Object = &autoscalingv2.ObjectMetricSource{
+					DescribedObject: autoscalingv2.CrossVersionObjectReference{
+						APIVersion: metric.DescribedObject.APIVersion,
+						Kind:       metric.DescribedObject.Kind,
+						Name:       metric.DescribedObject.Name,
+					},
+					Metric: identifier,
+					Target: target,
+				}
+			case models.HPAMetricExternal:
+				spec.External = &autoscalingv2.ExternalMetricSource{Metric: identifier, Target: target}
+			}
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// ValidateMetrics valida tipos, targets e campos obrigatórios de cada métrica
+func ValidateMetrics(metrics []models.HPAMetric) error {
+	for i, metric := range metrics {
+		if metric.Name == "" {
+			return fmt.Errorf("metrics[%d]: name is required", i)
+		}
+
+		var allowedTargets []string
+		switch metric.Type {
+		case models.HPAMetricResource:
+			allowedTargets = []string{models.HPAMetricTargetUtilization, models.HPAMetricTargetAverageValue}
+		case models.HPAMetricContainerResource:
+			if metric.Container == "" {
+				return fmt.Errorf("metrics[%d]: container is required for ContainerResource", i)
+			}
+			allowedTargets = []string{models.HPAMetricTargetUtilization, models.HPAMetricTargetAverageValue}
+		case models.HPAMetricPods:
+			allowedTargets = []string{models.HPAMetricTargetAverageValue}
+		case models.HPAMetricObject:
+			if metric.DescribedObject == nil || metric.DescribedObject.Kind == "" || metric.DescribedObject.Name == "" {
+				return fmt.Errorf("metrics[%d]: described object kind and name are required for Object", i)
+			}
+			allowedTargets = []string{models.HPAMetricTargetValue, models.HPAMetricTargetAverageValue}
+		case models.HPAMetricExternal:
+			allowedTargets = []string{models.HPAMetricTargetValue, models.HPAMetricTargetAverageValue}
+		default:
+			return fmt.Errorf("metrics[%d]: invalid type %q (expected Resource, ContainerResource, Pods, Object or External)", i, metric.Type)
+		}
+
+		allowed := false
+		for _, targetType := range allowedTargets {
+			if metric.TargetType == targetType {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("metrics[%d]: target type %q not allowed for %s (expected %s)", i, metric.TargetType, metric.Type, strings.Join(allowedTargets, " or "))
+		}
+
+		if metric.TargetType == models.HPAMetricTargetUtilization {
+			if metric.TargetUtilization == nil || *metric.TargetUtilization <= 0 {
+				return fmt.Errorf("metrics[%d]: target utilization must be > 0", i)
+			}
+			continue
+		}
+
+		quantity, err := resource.ParseQuantity(metric.TargetValue)
+		if err != nil {
+			return fmt.Errorf("metrics[%d]: invalid target value %q: %w", i, metric.TargetValue, err)
+		}
+		if quantity.Sign() <= 0 {
+			return fmt.Errorf("metrics[%d]: target value must be > 0", i)
+		}
+
+		if metric.Selector != "" {
+			if _, err := metav1.ParseToLabelSelector(metric.Selector); err != nil {
+				return fmt.Errorf("metrics[%d]: invalid selector %q: %w", i, metric.Selector, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ResourceUtilization retorna o target de utilização de um recurso (cpu/memory) na lista, se existir
+func ResourceUtilization(metrics []models.HPAMetric, resourceName string) *int32 {
+	for _, metric := range metrics {
+		if metric.Type == models.HPAMetricResource && metric.Name == resourceName &&
+			metric.TargetType == models.HPAMetricTargetUtilization && metric.TargetUtilization != nil {
+			utilization := *metric.TargetUtilization
+			return &utilization
+		}
+	}
+	return nil
+}
+
+// SetResourceUtilization define o target de utilização de um recurso na lista (adiciona se não existir)
+func SetResourceUtilization(metrics []models.HPAMetric, resourceName string, utilization int32) []models.HPAMetric {
+	for i := range metrics {
+		if metrics[i].Type == models.HPAMetricResource && metrics[i].Name == resourceName {
+			metrics[i].TargetType = models.HPAMetricTargetUtilization
+			metrics[i].TargetValue = ""
+			metrics[i].TargetUtilization = &utilization
+			return metrics
+		}
+	}
+	return append(metrics, models.HPAMetric{
+		Type:              models.HPAMetricResource,
+		Name:              resourceName,
+		TargetType:        models.HPAMetricTargetUtilization,
+		TargetUtilization: &utilization,
+	})
+}
+
+// FormatMetrics formata a lista no formato de edição da TUI, separando métricas por ";".
+// Ex: "Resource:cpu=Utilization:70;External:queue_depth{queue=orders}=AverageValue:30"
+func FormatMetrics(metrics []models.HPAMetric) string {
+	parts := make([]string, 0, len(metrics))
+	for _, metric := range metrics {
+		var b strings.Builder
+		b.WriteString(metric.Type + ":" + metric.Name)
+		if metric.Container != "" {
+			b.WriteString("/" + metric.Container)
+		}
+		if metric.Selector != "" {
+			b.WriteString("{" + metric.Selector + "}")
+		}
+		if metric.DescribedObject != nil {
+			b.WriteString("@")
+			if metric.DescribedObject.APIVersion != "" {
+				b.WriteString(metric.DescribedObject.APIVersion + "/")
+			}
+			b.WriteString(metric.DescribedObject.Kind + "/" + metric.DescribedObject.Name)
+		}
+
+		b.WriteString("=" + metric.TargetType + ":")
+		if metric.TargetType == models.HPAMetricTargetUtilization && metric.TargetUtilization != nil {
+			b.WriteString(strconv.Itoa(int(*metric.TargetUtilization)))
+		} else {
+			b.WriteString(metric.TargetValue)
+		}
+		parts = append(parts, b.String())
+	}
+	return strings.Join(parts, ";")
+}
+
+// ParseMetrics interpreta o formato gerado por FormatMetrics.
+// Formato por métrica: Tipo:nome[/container][{selector}][@[apiVersion/]Kind/nome]=TipoAlvo:valor
+func ParseMetrics(value string) ([]models.HPAMetric, error) {
+	metrics := []models.HPAMetric{}
+	for _, item := range strings.Split(value, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		typeSep := strings.Index(item, ":")
+		targetSep := strings.LastIndex(item, "=")
+		if typeSep <= 0 || targetSep < typeSep {
+			return nil, fmt.Errorf("invalid metric %q: expected Type:name=TargetType:value", item)
+		}
+
+		metric := models.HPAMetric{Type: normalizeMetricType(item[:typeSep])}
+
+		target := strings.SplitN(item[targetSep+1:], ":", 2)
+		if len(target) != 2 {
+			return nil, fmt.Errorf("invalid target in metric %q: expected TargetType:value", item)
+		}
+		metric.TargetType = normalizeTargetType(target[0])
+		if metric.TargetType == models.HPAMetricTargetUtilization {
+			utilization, err := strconv.ParseInt(strings.TrimSpace(target[1]), 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid utilization in metric %q: %w", item, err)
+			}
+			u := int32(utilization)
+			metric.TargetUtilization = &u
+		} else {
+			metric.TargetValue = strings.TrimSpace(target[1])
+		}
+
+		name := item[typeSep+1 : targetSep]
+		if at := strings.Index(name, "@"); at >= 0 {
+			ref := strings.Split(name[at+1:], "/")
+			if len(ref) < 2 {
+				return nil, fmt.Errorf("invalid described object in metric %q: expected Kind/name", item)
+			}
+			metric.DescribedObject = &models.HPAMetricObjectRef{
+				APIVersion: strings.Join(ref[:len(ref)-2], "/"),
+				Kind:       ref[len(ref)-2],
+				Name:       ref[len(ref)-1],
+			}
+			name = name[:at]
+		}
+		if open := strings.Index(name, "{"); open >= 0 {
+			if !strings.HasSuffix(name, "}") {
+				return nil, fmt.Errorf("invalid selector in metric %q: missing '}'", item)
+			}
+			metric.Selector = name[open+1 : len(name)-1]
+			name = name[:open]
+		}
+		if metric.Type == models.HPAMetricContainerResource {
+			if slash := strings.Index(name, "/"); slash >= 0 {
+				metric.Container = name[slash+1:]
+				name = name[:slash]
+			}
+		}
+		metric.Name = strings.TrimSpace(name)
+
+		metrics = append(metrics, metric)
+	}
+
+	if err := ValidateMetrics(metrics); err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// updateResourceUtilization define o target de utilização de um recurso em spec.metrics (cria se não existir)
+func updateResourceUtilization(hpa *autoscalingv2.HorizontalPodAutoscaler, resourceName corev1.ResourceName, utilization *int32) {
+	if utilization == nil {
+		return
+	}
+
+	target := autoscalingv2.MetricTarget{
+		Type:               autoscalingv2.UtilizationMetricType,
+		AverageUtilization: utilization,
+	}
+	for i, metric := range hpa.Spec.Metrics {
+		if metric.Type == autoscalingv2.ResourceMetricSourceType && metric.Resource != nil && metric.Resource.Name == resourceName {
+			hpa.Spec.Metrics[i].Resource.Target = target
+			return
+		}
+	}
+
+	hpa.Spec.Metrics = append(hpa.Spec.Metrics, autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name:   resourceName,
+			Target: target,
+		},
+	})
+}
+
+// metricTargetToKubernetes converte o target de uma métrica já validada
+func metricTargetToKubernetes(metric *models.HPAMetric) (autoscalingv2.MetricTarget, error) {
+	target := autoscalingv2.MetricTarget{Type: autoscalingv2.MetricTargetType(metric.TargetType)}
+
+	switch metric.TargetType {
+	case models.HPAMetricTargetUtilization:
+		utilization := *metric.TargetUtilization
+		target.AverageUtilization = &utilization
+	case models.HPAMetricTargetValue, models.HPAMetricTargetAverageValue:
+		quantity, err := resource.ParseQuantity(metric.TargetValue)
+		if err != nil {
+			return target, fmt.Errorf("invalid target value %q for metric %s: %w", metric.TargetValue, metric.Name, err)
+		}
+		if metric.TargetType == models.HPAMetricTargetValue {
+			target.Value = &quantity
+		} else {
+			target.AverageValue = &quantity
+		}
+	}
+	return target, nil
+}
+
+// currentMetricValue procura no status o valor atual correspondente à métrica
+func currentMetricValue(metric *models.HPAMetric, statuses []autoscalingv2.MetricStatus) string {
+	for _, status := range statuses {
+		if string(status.Type) != metric.Type {
+			continue
+		}
+
+		var current autoscalingv2.MetricValueStatus
+		switch status.Type {
+		case autoscalingv2.ResourceMetricSourceType:
+			if status.Resource == nil || string(status.Resource.Name) != metric.Name {
+				continue
+			}
+			current = status.Resource.Current
+		case autoscalingv2.ContainerResourceMetricSourceType:
+			if status.ContainerResource == nil || string(status.ContainerResource.Name) != metric.Name ||
+				status.ContainerResource.Container != metric.Container {
+				continue
+			}
+			current = status.ContainerResource.Current
+		case autoscalingv2.PodsMetricSourceType:
+			if status.Pods == nil || status.Pods.Metric.Name != metric.Name {
+				continue
+			}
+			current = status.Pods.Current
+		case autoscalingv2.ObjectMetricSourceType:
+			if status.Object == nil || status.Object.Metric.Name != metric.Name ||
+				metric.DescribedObject == nil || status.Object.DescribedObject.Name != metric.DescribedObject.Name {
+				continue
+			}
+			current = status.Object.Current
+		case autoscalingv2.ExternalMetricSourceType:
+			if status.External == nil || status.External.Metric.Name != metric.Name {
+				continue
+			}
+			current = status.External.Current
+		default:
+			continue
+		}
+
+		switch {
+		case metric.TargetType == models.HPAMetricTargetUtilization && current.AverageUtilization != nil:
+			return fmt.Sprintf("%d%%", *current.AverageUtilization)
+		case current.AverageValue != nil:
+			return current.AverageValue.String()
+		case current.Value != nil:
+			return current.Value.String()
+		}
+		return ""
+	}
+	return ""
+}
+
+// formatSelector converte um label selector em string (vazio quando não há selector)
+func formatSelector(selector *metav1.LabelSelector) string {
+	if selector == nil || (len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0) {
+		return ""
+	}
+	return metav1.FormatLabelSelector(selector)
+}
+
+// normalizeMetricType aceita o tipo da métrica em qualquer caixa
+func normalizeMetricType(value string) string {
+	value = strings.TrimSpace(value)
+	for _, metricType := range []string{models.HPAMetricResource, models.HPAMetricContainerResource,
+		models.HPAMetricPods, models.HPAMetricObject, models.HPAMetricExternal} {
+		if strings.EqualFold(value, metricType) {
+			return metricType
+		}
+	}
+	return value
+}
+
+// normalizeTargetType aceita o tipo do target em qualquer caixa
+func normalizeTargetType(value string) string {
+	value = strings.TrimSpace(value)
+	for _, targetType := range []string{models.HPAMetricTargetUtilization, models.HPAMetricTargetValue, models.HPAMetricTargetAverageValue} {
+		if strings.EqualFold(value, targetType) {
+			return targetType
+		}
+	}
+	return value
+}
@@ -0,0 +1,150 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"k8s-hpa-manager/internal/models"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestParseMetricsRoundTrip valida o formato de edição de métricas usado pela TUI
+func TestParseMetricsRoundTrip(t *testing.T) {
+	input := "Resource:cpu=Utilization:70;" +
+		"ContainerResource:memory/app=AverageValue:512Mi;" +
+		"Pods:http_requests=AverageValue:100;" +
+		"Object:requests-per-second@networking.k8s.io/v1/Ingress/main-route=Value:10k;" +
+		"External:queue_depth{queue=orders,env in (prod)}=AverageValue:30"
+
+	metrics, err := ParseMetrics(input)
+	if err != nil {
+		t.Fatalf("ParseMetrics() erro inesperado: %v", err)
+	}
+	if len(metrics) != 5 {
+		t.Fatalf("esperado 5 métricas, obtido %d", len(metrics))
+	}
+	if metrics[1].Container != "app" || metrics[1].Name != "memory" {
+		t.Errorf("ContainerResource inesperado: %+v", metrics[1])
+	}
+	object := metrics[3].DescribedObject
+	if object == nil || object.APIVersion != "networking.k8s.io/v1" || object.Kind != "Ingress" || object.Name != "main-route" {
+		t.Errorf("Object inesperado: %+v", object)
+	}
+	if metrics[4].Selector != "queue=orders,env in (prod)" || metrics[4].TargetValue != "30" {
+		t.Errorf("External inesperado: %+v", metrics[4])
+	}
+
+	if got := FormatMetrics(metrics); got != input {
+		t.Errorf("FormatMetrics() = %q, esperado %q", got, input)
+	}
+
+	invalid := []string{
+		"Pods:http_requests=Utilization:50",    // Pods só aceita AverageValue
+		"ContainerResource:cpu=Utilization:70", // sem container
+		"Object:rps=Value:10",                  // sem described object
+		"External:queue=AverageValue:abc",      // quantity inválida
+		"Custom:queue=Value:1",                 // tipo inválido
+	}
+	for _, value := range invalid {
+		if _, err := ParseMetrics(value); err == nil {
+			t.Errorf("ParseMetrics(%q): esperado erro", value)
+		}
+	}
+}
+
+// TestUpdateHPAMetrics valida o round-trip de métricas genéricas via UpdateHPA
+func TestUpdateHPAMetrics(t *testing.T) {
+	cpuTarget := int32(70)
+	queueTarget := resource.MustParse("30")
+	queueCurrent := resource.MustParse("12")
+	clientset := fake.NewSimpleClientset(&autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "default"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			MinReplicas: int32Ptr(1),
+			MaxReplicas: 20,
+			Metrics: []autoscalingv2.MetricSpec{
+				{
+					Type: autoscalingv2.ResourceMetricSourceType,
+					Resource: &autoscalingv2.ResourceMetricSource{
+						Name:   "cpu",
+						Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: &cpuTarget},
+					},
+				},
+				{
+					Type: autoscalingv2.ExternalMetricSourceType,
+					External: &autoscalingv2.ExternalMetricSource{
+						Metric: autoscalingv2.MetricIdentifier{
+							Name:     "queue_depth",
+							Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"queue": "orders"}},
+						},
+						Target: autoscalingv2.MetricTarget{Type: autoscalingv2.AverageValueMetricType, AverageValue: &queueTarget},
+					},
+				},
+			},
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+			CurrentMetrics: []autoscalingv2.MetricStatus{
+				{
+					Type: autoscalingv2.ExternalMetricSourceType,
+					External: &autoscalingv2.ExternalMetricStatus{
+						Metric:  autoscalingv2.MetricIdentifier{Name: "queue_depth"},
+						Current: autoscalingv2.MetricValueStatus{AverageValue: &queueCurrent},
+					},
+				},
+			},
+		},
+	})
+	client := NewClient(clientset, "test-cluster")
+	ctx := context.Background()
+
+	hpa, err := client.GetHPA(ctx, "default", "worker")
+	if err != nil {
+		t.Fatalf("GetHPA() erro inesperado: %v", err)
+	}
+	if len(hpa.Metrics) != 2 || hpa.Metrics[1].Selector != "queue=orders" || hpa.Metrics[1].Current != "12" {
+		t.Fatalf("métricas não convertidas: %+v", hpa.Metrics)
+	}
+	if hpa.TargetCPU == nil || *hpa.TargetCPU != 70 {
+		t.Errorf("TargetCPU = %v, esperado 70", hpa.TargetCPU)
+	}
+
+	// Alterar a métrica externa e adicionar uma métrica de Pods; TargetCPU prevalece para cpu
+	hpa.Metrics[1].TargetValue = "50"
+	hpa.Metrics = append(hpa.Metrics, models.HPAMetric{
+		Type:        models.HPAMetricPods,
+		Name:        "http_requests",
+		TargetType:  models.HPAMetricTargetAverageValue,
+		TargetValue: "100",
+	})
+	newCPU := int32(60)
+	hpa.TargetCPU = &newCPU
+
+	if err := client.UpdateHPA(ctx, hpa); err != nil {
+		t.Fatalf("UpdateHPA() erro inesperado: %v", err)
+	}
+
+	updated, _ := clientset.AutoscalingV2().HorizontalPodAutoscalers("default").Get(ctx, "worker", metav1.GetOptions{})
+	if len(updated.Spec.Metrics) != 3 {
+		t.Fatalf("esperado 3 métricas, obtido %d", len(updated.Spec.Metrics))
+	}
+	if got := *updated.Spec.Metrics[0].Resource.Target.AverageUtilization; got != 60 {
+		t.Errorf("cpu target = %d, esperado 60", got)
+	}
+	external := updated.Spec.Metrics[1].External
+	if external.Target.AverageValue.String() != "50" || external.Metric.Selector.MatchLabels["queue"] != "orders" {
+		t.Errorf("métrica externa inesperada: %+v", external)
+	}
+	if updated.Spec.Metrics[2].Pods == nil || updated.Spec.Metrics[2].Pods.Metric.Name != "http_requests" {
+		t.Errorf("métrica de Pods inesperada: %+v", updated.Spec.Metrics[2])
+	}
+
+	// Métricas inválidas não devem ser enviadas ao cluster
+	hpa.Metrics = []models.HPAMetric{{Type: models.HPAMetricPods, Name: "rps", TargetType: models.HPAMetricTargetValue, TargetValue: "1"}}
+	if err := client.UpdateHPA(ctx, hpa); err == nil {
+		t.Error("esperado erro de validação")
+	}
+}
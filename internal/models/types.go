@@ -197,6 +197,13 @@ const (
 	FieldRollout            = "rollout"
 	FieldDaemonSetRollout   = "daemonset_rollout"
 	FieldStatefulSetRollout = "statefulset_rollout"
+	FieldMetrics            = "metrics"
+	FieldScaleUpWindow      = "scale_up_stabilization"
+	FieldScaleUpSelect      = "scale_up_select_policy"
+	FieldScaleUpPolicies    = "scale_up_policies"
+	FieldScaleDownWindow    = "scale_down_stabilization"
+	FieldScaleDownSelect    = "scale_down_select_policy"
+	FieldScaleDownPolicies  = "scale_down_policies"
 	FieldDeploymentCPUReq   = "deployment_cpu_request"
 	FieldDeploymentCPULimit = "deployment_cpu_limit"
 	FieldDeploymentMemReq   = "deployment_memory_request"
@@ -273,21 +280,23 @@ type Namespace struct {
 
 // HPA representa um Horizontal Pod Autoscaler
 type HPA struct {
-	Name                      string     `json:"name"`
-	Namespace                 string     `json:"namespace"`
-	Cluster                   string     `json:"cluster"`
-	MinReplicas               *int32     `json:"min_replicas"`
-	MaxReplicas               int32      `json:"max_replicas"`
-	CurrentReplicas           int32      `json:"current_replicas"`
-	TargetCPU                 *int32     `json:"target_cpu,omitempty"`
-	TargetMemory              *int32     `json:"target_memory,omitempty"`
-	PerformRollout            bool       `json:"perform_rollout"`
-	PerformDaemonSetRollout   bool       `json:"perform_daemonset_rollout"`
-	PerformStatefulSetRollout bool       `json:"perform_statefulset_rollout"`
-	Selected                  bool       `json:"selected"`
-	Modified                  bool       `json:"modified"`
-	OriginalValues            *HPAValues `json:"original_values"`
-	LastUpdated               time.Time  `json:"last_updated"`
+	Name                      string       `json:"name"`
+	Namespace                 string       `json:"namespace"`
+	Cluster                   string       `json:"cluster"`
+	MinReplicas               *int32       `json:"min_replicas"`
+	MaxReplicas               int32        `json:"max_replicas"`
+	CurrentReplicas           int32        `json:"current_replicas"`
+	TargetCPU                 *int32       `json:"target_cpu,omitempty"`
+	TargetMemory              *int32       `json:"target_memory,omitempty"`
+	Metrics                   []HPAMetric  `json:"metrics,omitempty"`
+	Behavior                  *HPABehavior `json:"behavior,omitempty"`
+	PerformRollout            bool         `json:"perform_rollout"`
+	PerformDaemonSetRollout   bool         `json:"perform_daemonset_rollout"`
+	PerformStatefulSetRollout bool         `json:"perform_statefulset_rollout"`
+	Selected                  bool         `json:"selected"`
+	Modified                  bool         `json:"modified"`
+	OriginalValues            *HPAValues   `json:"original_values"`
+	LastUpdated               time.Time    `json:"last_updated"`
 
 	// Application Tracking (not saved in session JSON)
 	AppliedCount  int        `json:"-"` // Contador de aplicações na sessão atual
@@ -314,6 +323,12 @@ type HPAValues struct {
 	TargetCPU    *int32 `json:"target_cpu,omitempty"`
 	TargetMemory *int32 `json:"target_memory,omitempty"`
 
+	// Lista completa de métricas (nil = não alterar; TargetCPU/TargetMemory prevalecem para cpu/memory)
+	Metrics []HPAMetric `json:"metrics,omitempty"`
+
+	// Scaling Behavior (nil = não alterar)
+	Behavior *HPABehavior `json:"behavior,omitempty"`
+
 	// Rollout Options
 	PerformRollout            bool `json:"perform_rollout"`
 	PerformDaemonSetRollout   bool `json:"perform_daemonset_rollout"`
@@ -327,6 +342,127 @@ type HPAValues struct {
 	MemoryLimit    string `json:"memory_limit,omitempty"`
 }
 
+// Tipos de métrica do HPA (autoscaling/v2)
+const (
+	HPAMetricResource          = "Resource"
+	HPAMetricContainerResource = "ContainerResource"
+	HPAMetricPods              = "Pods"
+	HPAMetricObject            = "Object"
+	HPAMetricExternal          = "External"
+)
+
+// Tipos de target de métrica do HPA (autoscaling/v2)
+const (
+	HPAMetricTargetUtilization  = "Utilization"
+	HPAMetricTargetValue        = "Value"
+	HPAMetricTargetAverageValue = "AverageValue"
+)
+
+// HPAMetric representa uma métrica genérica do HPA (Resource, ContainerResource, Pods, Object ou External)
+type HPAMetric struct {
+	Type              string              `json:"type"`
+	Name              string              `json:"name"`                         // Recurso (cpu/memory) ou nome da métrica
+	Container         string              `json:"container,omitempty"`          // ContainerResource
+	Selector          string              `json:"selector,omitempty"`           // Label selector da métrica (Pods/Object/External)
+	DescribedObject   *HPAMetricObjectRef `json:"described_object,omitempty"`   // Object
+	TargetType        string              `json:"target_type"`                  // Utilization, Value ou AverageValue
+	TargetValue       string              `json:"target_value,omitempty"`       // Quantity para Value/AverageValue
+	TargetUtilization *int32              `json:"target_utilization,omitempty"` // Porcentagem para Utilization
+	Current           string              `json:"current,omitempty"`            // Valor atual do status (somente leitura)
+}
+
+// HPAMetricObjectRef identifica o objeto descrito por uma métrica do tipo Object
+type HPAMetricObjectRef struct {
+	APIVersion string `json:"api_version,omitempty"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+}
+
+// CloneHPAMetrics retorna uma cópia profunda da lista de métricas (nil continua nil)
+func CloneHPAMetrics(metrics []HPAMetric) []HPAMetric {
+	if metrics == nil {
+		return nil
+	}
+	clone := make([]HPAMetric, len(metrics))
+	for i, metric := range metrics {
+		clone[i] = metric
+		if metric.DescribedObject != nil {
+			object := *metric.DescribedObject
+			clone[i].DescribedObject = &object
+		}
+		if metric.TargetUtilization != nil {
+			utilization := *metric.TargetUtilization
+			clone[i].TargetUtilization = &utilization
+		}
+	}
+	return clone
+}
+
+// Tipos de policy de scaling do HPA (autoscaling/v2)
+const (
+	HPAScalingPolicyPods    = "Pods"
+	HPAScalingPolicyPercent = "Percent"
+)
+
+// Valores de selectPolicy do HPA (autoscaling/v2)
+const (
+	HPASelectPolicyMax      = "Max"
+	HPASelectPolicyMin      = "Min"
+	HPASelectPolicyDisabled = "Disabled"
+)
+
+// HPAScalingPolicy representa uma policy de scaling (ex: até 4 Pods a cada 60s)
+type HPAScalingPolicy struct {
+	Type          string `json:"type"`           // Pods ou Percent
+	Value         int32  `json:"value"`          // Quantidade de pods ou porcentagem
+	PeriodSeconds int32  `json:"period_seconds"` // Janela da policy (1-1800)
+}
+
+// HPAScalingRules representa as regras de uma direção de scaling (scaleUp ou scaleDown)
+type HPAScalingRules struct {
+	StabilizationWindowSeconds *int32             `json:"stabilization_window_seconds,omitempty"` // 0-3600
+	SelectPolicy               *string            `json:"select_policy,omitempty"`                // Max, Min ou Disabled
+	Policies                   []HPAScalingPolicy `json:"policies,omitempty"`
+}
+
+// HPABehavior representa o spec.behavior do HPA.
+// Direção nil mantém o padrão do Kubernetes; HPABehavior vazio remove o behavior customizado.
+type HPABehavior struct {
+	ScaleUp   *HPAScalingRules `json:"scale_up,omitempty"`
+	ScaleDown *HPAScalingRules `json:"scale_down,omitempty"`
+}
+
+// Clone retorna uma cópia profunda do behavior (evita compartilhar ponteiros com OriginalValues)
+func (b *HPABehavior) Clone() *HPABehavior {
+	if b == nil {
+		return nil
+	}
+	return &HPABehavior{
+		ScaleUp:   b.ScaleUp.Clone(),
+		ScaleDown: b.ScaleDown.Clone(),
+	}
+}
+
+// Clone retorna uma cópia profunda das regras de scaling
+func (r *HPAScalingRules) Clone() *HPAScalingRules {
+	if r == nil {
+		return nil
+	}
+	clone := &HPAScalingRules{}
+	if r.StabilizationWindowSeconds != nil {
+		window := *r.StabilizationWindowSeconds
+		clone.StabilizationWindowSeconds = &window
+	}
+	if r.SelectPolicy != nil {
+		selectPolicy := *r.SelectPolicy
+		clone.SelectPolicy = &selectPolicy
+	}
+	if r.Policies != nil {
+		clone.Policies = append([]HPAScalingPolicy(nil), r.Policies...)
+	}
+	return clone
+}
+
 // HPAChange representa uma mudança em um HPA
 type HPAChange struct {
 	Cluster                     string     `json:"cluster"`
@@ -427,6 +563,14 @@ type Session struct {
 	NodePoolChanges []NodePoolChange        `json:"node_pool_changes"`
 	ResourceChanges []ClusterResourceChange `json:"resource_changes"`
 	RollbackData    *RollbackData           `json:"rollback_data"`
+	SourceSession   *SessionReference       `json:"source_session,omitempty"` // sessão da qual esta foi derivada (ex: inversa)
+}
+
+// SessionReference referência a outra sessão salva
+type SessionReference struct {
+	Name     string `json:"name"`
+	Folder   string `json:"folder,omitempty"`
+	Relation string `json:"relation"` // inverse
 }
 
 // SessionMetadata contém metadados da sessão
@@ -756,8 +900,9 @@ type NodePool struct {
 	SequenceStatus string `json:"sequence_status"` // pending, executing, completed, failed
 
 	// Cluster info
+	Provider      string `json:"provider,omitempty"` // aks, eks ou gke
 	ClusterName   string `json:"cluster_name"`
-	ResourceGroup string `json:"resource_group"`
+	ResourceGroup string `json:"resource_group"` // AKS: resource group / EKS e GKE: região
 	Subscription  string `json:"subscription"`
 
 	// Valores originais para rollback
@@ -994,6 +1139,9 @@ type ClusterConfig struct {
 	ClusterName   string `json:"clusterName"`
 	ResourceGroup string `json:"resourceGroup"`
 	Subscription  string `json:"subscription"`
+	Provider      string `json:"provider,omitempty"` // aks (padrão se vazio), eks ou gke
+	Region        string `json:"region,omitempty"`   // EKS: região AWS / GKE: região ou zona do cluster
+	Project       string `json:"project,omitempty"`  // GKE: project ID
 }
 
 // CronJob representa um CronJob do Kubernetes
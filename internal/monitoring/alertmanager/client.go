@@ -0,0 +1,69 @@
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Alert alerta retornado por GET /api/v2/alerts (gettableAlert)
+type Alert struct {
+	Fingerprint  string            `json:"fingerprint"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	UpdatedAt    time.Time         `json:"updatedAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Status       AlertStatus       `json:"status"`
+}
+
+// AlertStatus status de um alerta no Alertmanager
+type AlertStatus struct {
+	State       string   `json:"state"` // active, suppressed, unprocessed
+	SilencedBy  []string `json:"silencedBy"`
+	InhibitedBy []string `json:"inhibitedBy"`
+}
+
+// Client client HTTP mínimo para a API v2 do Alertmanager
+type Client struct {
+	endpoint   string
+	httpClient *http.Client
+}
+
+// NewClient cria um client para o Alertmanager em endpoint (ex: http://localhost:9093)
+func NewClient(endpoint string) *Client {
+	return &Client{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// GetAlerts retorna os alertas ativos, silenciados e inibidos
+func (c *Client) GetAlerts(ctx context.Context) ([]Alert, error) {
+	url := c.endpoint + "/api/v2/alerts?active=true&silenced=true&inhibited=true&unprocessed=false"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alertmanager: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("alertmanager returned status %d", resp.StatusCode)
+	}
+
+	var alerts []Alert
+	if err := json.NewDecoder(resp.Body).Decode(&alerts); err != nil {
+		return nil, fmt.Errorf("failed to decode alerts: %w", err)
+	}
+	return alerts, nil
+}
@@ -0,0 +1,325 @@
+package alertmanager
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"k8s-hpa-manager/internal/monitoring/models"
+)
+
+// Forwarder abre acesso ao Alertmanager de um cluster sem endpoint configurado
+// (implementado por portforward.PortForwardManager)
+type Forwarder interface {
+	StartAlertmanager(cluster string) (string, error)
+	StopAlertmanager(cluster string) error
+}
+
+// Config configuração do collector
+type Config struct {
+	// Endpoints cluster -> URL do Alertmanager (ex: http://alertmanager.monitoring:9093)
+	Endpoints map[string]string
+	// AutoDiscover usa port-forward para clusters sem endpoint configurado
+	AutoDiscover bool
+	// SyncInterval intervalo entre consultas (default: 60s)
+	SyncInterval time.Duration
+	// Clusters clusters monitorados, consultados via port-forward com AutoDiscover
+	Clusters func() []string
+	// ResolveHPA mapeia o workload de um alerta para o nome do HPA (nil = mesmo nome)
+	ResolveHPA func(cluster, namespace, workload string) string
+}
+
+// ConfigFromWatchdog cria a configuração a partir do WatchdogConfig
+func ConfigFromWatchdog(cfg *models.WatchdogConfig, clusters func() []string) Config {
+	cfg.RLock()
+	defer cfg.RUnlock()
+
+	endpoints := make(map[string]string, len(cfg.AlertmanagerEndpoints))
+	for cluster, endpoint := range cfg.AlertmanagerEndpoints {
+		endpoints[cluster] = endpoint
+	}
+	return Config{
+		Endpoints:    endpoints,
+		AutoDiscover: cfg.AlertmanagerAutoDiscover,
+		SyncInterval: time.Duration(cfg.AlertmanagerSyncInterval) * time.Second,
+		Clusters:     clusters,
+	}
+}
+
+// ClusterStatus resultado da última sincronização de um cluster
+type ClusterStatus struct {
+	Cluster  string    `json:"cluster"`
+	Endpoint string    `json:"endpoint,omitempty"`
+	LastSync time.Time `json:"last_sync"`
+	Alerts   int       `json:"alerts"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Collector consulta periodicamente o Alertmanager de cada cluster e mantém os alertas
+// mapeados para HPAs como models.UnifiedAlert
+type Collector struct {
+	config    Config
+	forwarder Forwarder
+
+	mu     sync.RWMutex
+	alerts map[string][]models.UnifiedAlert // cluster -> alertas da última sincronização
+	status map[string]ClusterStatus
+}
+
+// NewCollector cria um collector; forwarder pode ser nil quando todos os endpoints são configurados
+func NewCollector(cfg Config, forwarder Forwarder) *Collector {
+	if cfg.SyncInterval <= 0 {
+		cfg.SyncInterval = 60 * time.Second
+	}
+	return &Collector{
+		config:    cfg,
+		forwarder: forwarder,
+		alerts:    make(map[string][]models.UnifiedAlert),
+		status:    make(map[string]ClusterStatus),
+	}
+}
+
+// Run sincroniza todos os clusters a cada SyncInterval até o contexto ser cancelado
+func (c *Collector) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.config.SyncInterval)
+	defer ticker.Stop()
+
+	c.SyncAll(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.SyncAll(ctx)
+		}
+	}
+}
+
+// SyncAll sincroniza os clusters configurados e monitorados (um por vez, por causa do port-forward)
+func (c *Collector) SyncAll(ctx context.Context) {
+	clusters := c.clusters()
+	for _, cluster := range clusters {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := c.SyncCluster(ctx, cluster); err != nil {
+			log.Warn().Err(err).Str("cluster", cluster).Msg("Falha ao sincronizar alertas do Alertmanager")
+		}
+	}
+
+	// Clusters que deixaram de ser monitorados não mantêm alertas antigos
+	active := make(map[string]bool, len(clusters))
+	for _, cluster := range clusters {
+		active[normalizeCluster(cluster)] = true
+	}
+	c.mu.Lock()
+	for cluster := range c.alerts {
+		if !active[cluster] {
+			delete(c.alerts, cluster)
+			delete(c.status, cluster)
+		}
+	}
+	c.mu.Unlock()
+}
+
+// SyncCluster consulta o Alertmanager de um cluster e substitui os alertas conhecidos.
+// Em caso de erro os alertas da sincronização anterior são mantidos.
+func (c *Collector) SyncCluster(ctx context.Context, cluster string) error {
+	key := normalizeCluster(cluster)
+	status := ClusterStatus{Cluster: key, LastSync: time.Now()}
+
+	alerts, endpoint, err := c.fetch(ctx, cluster)
+	status.Endpoint = endpoint
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		status.Error = err.Error()
+		status.Alerts = len(c.alerts[key])
+		c.status[key] = status
+		return err
+	}
+
+	unified := make([]models.UnifiedAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		if ua, ok := c.toUnifiedAlert(key, alert); ok {
+			unified = append(unified, ua)
+		}
+	}
+	c.alerts[key] = unified
+	status.Alerts = len(unified)
+	c.status[key] = status
+	return nil
+}
+
+func (c *Collector) fetch(ctx context.Context, cluster string) ([]Alert, string, error) {
+	endpoint := c.endpointFor(cluster)
+	if endpoint == "" {
+		if !c.config.AutoDiscover || c.forwarder == nil {
+			return nil, "", fmt.Errorf("no alertmanager endpoint configured for cluster %s", cluster)
+		}
+		url, err := c.forwarder.StartAlertmanager(cluster)
+		if err != nil {
+			return nil, "", err
+		}
+		defer c.forwarder.StopAlertmanager(cluster)
+		endpoint = url
+	}
+
+	alerts, err := NewClient(endpoint).GetAlerts(ctx)
+	return alerts, endpoint, err
+}
+
+// Alerts retorna os alertas de todos os clusters, mais recentes primeiro
+func (c *Collector) Alerts() []models.UnifiedAlert {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var all []models.UnifiedAlert
+	for _, alerts := range c.alerts {
+		all = append(all, alerts...)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if !all[i].Timestamp.Equal(all[j].Timestamp) {
+			return all[i].Timestamp.After(all[j].Timestamp)
+		}
+		return all[i].ID < all[j].ID
+	})
+	return all
+}
+
+// Status retorna o resultado da última sincronização de cada cluster
+func (c *Collector) Status() []ClusterStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	statuses := make([]ClusterStatus, 0, len(c.status))
+	for _, status := range c.status {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Cluster < statuses[j].Cluster })
+	return statuses
+}
+
+// clusters une os clusters com endpoint configurado e os monitorados, sem duplicatas
+func (c *Collector) clusters() []string {
+	seen := make(map[string]bool)
+	var clusters []string
+	add := func(cluster string) {
+		if key := normalizeCluster(cluster); !seen[key] {
+			seen[key] = true
+			clusters = append(clusters, cluster)
+		}
+	}
+
+	for cluster := range c.config.Endpoints {
+		add(cluster)
+	}
+	sort.Strings(clusters)
+	if c.config.AutoDiscover && c.config.Clusters != nil {
+		for _, cluster := range c.config.Clusters() {
+			add(cluster)
+		}
+	}
+	return clusters
+}
+
+func (c *Collector) endpointFor(cluster string) string {
+	if endpoint, ok := c.config.Endpoints[cluster]; ok {
+		return endpoint
+	}
+	key := normalizeCluster(cluster)
+	for configured, endpoint := range c.config.Endpoints {
+		if normalizeCluster(configured) == key {
+			return endpoint
+		}
+	}
+	return ""
+}
+
+// toUnifiedAlert converte um alerta do Alertmanager; alertas sem namespace não são associados a HPAs
+func (c *Collector) toUnifiedAlert(cluster string, alert Alert) (models.UnifiedAlert, bool) {
+	namespace := alert.Labels["namespace"]
+	if namespace == "" {
+		return models.UnifiedAlert{}, false
+	}
+
+	hpaName := ""
+	if workload := workloadFromLabels(alert.Labels); workload != "" {
+		hpaName = workload
+		if c.config.ResolveHPA != nil {
+			hpaName = c.config.ResolveHPA(cluster, namespace, workload)
+		}
+	}
+
+	status := alert.Status.State
+	if status == "" {
+		status = "active"
+	}
+
+	return models.UnifiedAlert{
+		ID:           "alertmanager-" + cluster + "-" + alert.Fingerprint,
+		Source:       models.AlertSourceAlertmanager,
+		Severity:     severityFromLabel(alert.Labels["severity"]),
+		Type:         models.AnomalyExternalAlert,
+		Cluster:      cluster,
+		Namespace:    namespace,
+		HPAName:      hpaName,
+		Timestamp:    alert.StartsAt,
+		Summary:      firstNonEmpty(alert.Annotations["summary"], alert.Annotations["message"], alert.Labels["alertname"]),
+		Description:  alert.Annotations["description"],
+		Fingerprint:  alert.Fingerprint,
+		GeneratorURL: alert.GeneratorURL,
+		Status:       status,
+		SilencedBy:   alert.Status.SilencedBy,
+		AlertName:    alert.Labels["alertname"],
+		Labels:       alert.Labels,
+	}, true
+}
+
+// podHashSuffix sufixo de pods de Deployment (<replicaset-hash>-<pod-hash>) ou StatefulSet (-N)
+var podHashSuffix = regexp.MustCompile(`(-[a-z0-9]{8,10}-[a-z0-9]{5}|-[0-9]+)$`)
+
+// workloadFromLabels extrai o HPA/workload dos labels mais comuns (kube-state-metrics e regras customizadas)
+func workloadFromLabels(labels map[string]string) string {
+	for _, key := range []string{"horizontalpodautoscaler", "hpa", "deployment", "statefulset", "workload"} {
+		if value := labels[key]; value != "" {
+			return value
+		}
+	}
+	if pod := labels["pod"]; pod != "" {
+		return podHashSuffix.ReplaceAllString(pod, "")
+	}
+	return firstNonEmpty(labels["app_kubernetes_io_name"], labels["app"])
+}
+
+func severityFromLabel(severity string) models.AlertSeverity {
+	switch strings.ToLower(severity) {
+	case "critical", "error", "page":
+		return models.SeverityCritical
+	case "warning", "warn":
+		return models.SeverityWarning
+	default:
+		return models.SeverityInfo
+	}
+}
+
+// normalizeCluster remove o sufixo -admin (mesmo formato dos snapshots no SQLite)
+func normalizeCluster(cluster string) string {
+	return strings.TrimSuffix(cluster, "-admin")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
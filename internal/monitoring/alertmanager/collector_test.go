@@ -0,0 +1,120 @@
+package alertmanager
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s-hpa-manager/internal/monitoring/models"
+)
+
+const stubAlerts = `[
+  {
+    "fingerprint": "a1",
+    "labels": {"alertname": "KubeHpaMaxedOut", "severity": "warning", "namespace": "api", "horizontalpodautoscaler": "web"},
+    "annotations": {"summary": "HPA api/web no máximo de réplicas"},
+    "startsAt": "2026-10-17T10:00:00Z",
+    "status": {"state": "active", "silencedBy": [], "inhibitedBy": []}
+  },
+  {
+    "fingerprint": "b2",
+    "labels": {"alertname": "KubePodCrashLooping", "severity": "critical", "namespace": "api", "pod": "worker-7d9f8b6c5d-x2k4p"},
+    "annotations": {"description": "pod reiniciando"},
+    "startsAt": "2026-10-17T11:00:00Z",
+    "status": {"state": "suppressed", "silencedBy": ["s1"], "inhibitedBy": []}
+  },
+  {
+    "fingerprint": "c3",
+    "labels": {"alertname": "Watchdog", "severity": "none"},
+    "startsAt": "2026-10-17T09:00:00Z",
+    "status": {"state": "active"}
+  }
+]`
+
+// fakeForwarder simula o port-forward apontando para o servidor stub
+type fakeForwarder struct {
+	url     string
+	err     error
+	started []string
+	stopped []string
+}
+
+func (f *fakeForwarder) StartAlertmanager(cluster string) (string, error) {
+	f.started = append(f.started, cluster)
+	return f.url, f.err
+}
+
+func (f *fakeForwarder) StopAlertmanager(cluster string) error {
+	f.stopped = append(f.stopped, cluster)
+	return nil
+}
+
+func newStubAlertmanager(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/alerts" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(stubAlerts))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestCollectorSyncCluster valida o mapeamento dos alertas para HPAs
+func TestCollectorSyncCluster(t *testing.T) {
+	server := newStubAlertmanager(t)
+	collector := NewCollector(Config{Endpoints: map[string]string{"aks-prd-admin": server.URL}}, nil)
+
+	collector.SyncAll(context.Background())
+
+	alerts := collector.Alerts()
+	if len(alerts) != 2 {
+		t.Fatalf("esperado 2 alertas com namespace, obtido %+v", alerts)
+	}
+
+	crash, maxed := alerts[0], alerts[1]
+	if crash.HPAName != "worker" || crash.Severity != models.SeverityCritical || crash.Status != "suppressed" || crash.SilencedBy[0] != "s1" {
+		t.Errorf("alerta de pod inesperado: %+v", crash)
+	}
+	if maxed.Cluster != "aks-prd" || maxed.HPAName != "web" || maxed.Source != models.AlertSourceAlertmanager ||
+		maxed.Summary != "HPA api/web no máximo de réplicas" || maxed.AlertName != "KubeHpaMaxedOut" {
+		t.Errorf("alerta de HPA inesperado: %+v", maxed)
+	}
+
+	status := collector.Status()
+	if len(status) != 1 || status[0].Alerts != 2 || status[0].Error != "" {
+		t.Errorf("status inesperado: %+v", status)
+	}
+}
+
+// TestCollectorAutoDiscover valida o uso do port-forward e a manutenção dos alertas em caso de falha
+func TestCollectorAutoDiscover(t *testing.T) {
+	server := newStubAlertmanager(t)
+	forwarder := &fakeForwarder{url: server.URL}
+	collector := NewCollector(Config{
+		AutoDiscover: true,
+		Clusters:     func() []string { return []string{"aks-hml"} },
+		ResolveHPA:   func(cluster, namespace, workload string) string { return workload + "-hpa" },
+	}, forwarder)
+
+	collector.SyncAll(context.Background())
+	if len(forwarder.started) != 1 || len(forwarder.stopped) != 1 {
+		t.Fatalf("port-forward deveria ser aberto e fechado: %+v", forwarder)
+	}
+	if alerts := collector.Alerts(); len(alerts) != 2 || alerts[1].HPAName != "web-hpa" {
+		t.Fatalf("alertas inesperados: %+v", alerts)
+	}
+
+	forwarder.err = errors.New("service not found")
+	if err := collector.SyncCluster(context.Background(), "aks-hml"); err == nil {
+		t.Fatal("esperado erro do port-forward")
+	}
+	if len(collector.Alerts()) != 2 || collector.Status()[0].Error == "" {
+		t.Error("falha deveria manter os alertas anteriores e registrar o erro")
+	}
+}
@@ -2,17 +2,20 @@ package analyzer
 
 import (
 	"fmt"
+	"math"
 	"time"
 
 	"k8s-hpa-manager/internal/monitoring/models"
 	"k8s-hpa-manager/internal/monitoring/storage"
 	"github.com/rs/zerolog/log"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // Detector detecta anomalias em HPAs
 type Detector struct {
 	cache  *storage.TimeSeriesCache
 	config *DetectorConfig
+	rules  *RuleStore // overrides por cluster/namespace/HPA e regras customizadas (opcional)
 }
 
 // DetectorConfig configuração do detector
@@ -33,6 +36,11 @@ type DetectorConfig struct {
 	NotReadyThreshold    float64       // % mínimo de pods ready
 	NotReadyMinDuration  time.Duration // Tempo mínimo com pods not ready
 
+	// OOMKilled / CrashLoop
+	OOMKilledWindow           time.Duration // Janela em que uma terminação OOMKilled gera alerta
+	CrashLoopRestartThreshold int32         // Restarts na janela para considerar crash loop
+	CrashLoopWindow           time.Duration // Janela de contagem de restarts
+
 	// Sudden Changes (Variações bruscas)
 	CPUSpikeThreshold     float64 // % de aumento de CPU para alertar (default: 50%)
 	ReplicaSpikeThreshold int32   // Número de replicas de aumento para alertar (default: 3)
@@ -42,6 +50,9 @@ type DetectorConfig struct {
 
 	// Cooldown para evitar alertas duplicados
 	AlertCooldown time.Duration // Tempo entre alertas do mesmo tipo
+
+	// Tipos de anomalia desligados (via regras)
+	Disabled []AnomalyType
 }
 
 // DefaultDetectorConfig retorna configuração padrão
@@ -63,6 +74,12 @@ func DefaultDetectorConfig() *DetectorConfig {
 		NotReadyThreshold:    70.0,
 		NotReadyMinDuration:  3 * time.Minute,
 
+		// OOMKilled: terminação por OOM nos últimos 10min
+		// CrashLoop: pod em CrashLoopBackOff ou 3+ restarts em 5min
+		OOMKilledWindow:           10 * time.Minute,
+		CrashLoopRestartThreshold: 3,
+		CrashLoopWindow:           5 * time.Minute,
+
 		// Sudden Changes (Variações bruscas)
 		CPUSpikeThreshold:     50.0, // CPU aumentou 50%+ em 1 scan
 		ReplicaSpikeThreshold: 3,    // Replicas aumentaram 3+ em 1 scan
@@ -94,6 +111,23 @@ func NewDetector(cache *storage.TimeSeriesCache, config *DetectorConfig) *Detect
 	}
 }
 
+// SetRules habilita overrides e regras customizadas do RuleStore
+func (d *Detector) SetRules(rules *RuleStore) {
+	d.rules = rules
+}
+
+// forHPA retorna um detector com a configuração efetiva do HPA (base + overrides)
+func (d *Detector) forHPA(latest *models.HPASnapshot) *Detector {
+	if d.rules == nil {
+		return d
+	}
+	return &Detector{
+		cache:  d.cache,
+		config: d.rules.RuleSet().ConfigFor(d.config, latest.Cluster, latest.Namespace, latest.Name),
+		rules:  d.rules,
+	}
+}
+
 // DetectionResult resultado da detecção
 type DetectionResult struct {
 	Anomalies []Anomaly
@@ -114,6 +148,7 @@ type Anomaly struct {
 	Snapshot    *models.HPASnapshot
 	Stats       *models.HPAStats
 	Actions     []string
+	Rule        string // nome da regra customizada (AnomalyTypeCustomRule)
 }
 
 // AnomalyType tipos de anomalias
@@ -126,6 +161,7 @@ const (
 	AnomalyTypeOOMKilled     AnomalyType = "OOM_KILLED"
 	AnomalyTypePodsNotReady  AnomalyType = "PODS_NOT_READY"
 	AnomalyTypeHighErrorRate AnomalyType = "HIGH_ERROR_RATE"
+	AnomalyTypeCrashLoop     AnomalyType = "CRASH_LOOP"
 
 	// Phase 2 - Sudden Changes (Variações bruscas)
 	AnomalyTypeCPUSpike      AnomalyType = "CPU_SPIKE"       // CPU aumentou >50% em 1 scan
@@ -156,6 +192,9 @@ func (d *Detector) Detect() *DetectionResult {
 			continue
 		}
 
+		// Configuração efetiva do HPA (overrides do arquivo de regras)
+		d := d.forHPA(latest)
+
 		// Fase 1 - MVP: 5 anomalias críticas
 		anomalies := []Anomaly{}
 
@@ -169,8 +208,15 @@ func (d *Detector) Detect() *DetectionResult {
 			anomalies = append(anomalies, *anomaly)
 		}
 
-		// 3. OOMKilled (detectado via K8s events - implementar depois)
-		// TODO: Integrar com K8s events
+		// 3. OOMKilled (last termination state dos containers)
+		if anomaly := d.detectOOMKilled(ts, latest); anomaly != nil {
+			anomalies = append(anomalies, *anomaly)
+		}
+
+		// 3b. Crash Loop
+		if anomaly := d.detectCrashLoop(ts, latest); anomaly != nil {
+			anomalies = append(anomalies, *anomaly)
+		}
 
 		// 4. Pods Not Ready
 		if anomaly := d.detectPodsNotReady(ts, latest); anomaly != nil {
@@ -211,6 +257,12 @@ func (d *Detector) Detect() *DetectionResult {
 			}
 		}
 
+		// Tipos desligados por override
+		anomalies = d.filterDisabled(anomalies)
+
+		// Regras customizadas
+		anomalies = append(anomalies, d.evaluateCustomRules(ts, latest)...)
+
 		result.Anomalies = append(result.Anomalies, anomalies...)
 
 		if len(anomalies) > 0 {
@@ -410,6 +462,178 @@ func (d *Detector) detectHighErrorRate(ts *models.TimeSeriesData, latest *models
 	}
 }
 
+// detectOOMKilled detecta containers mortos por OOM (terminação OOMKilled nos últimos 10min).
+// Usa o último OOMKilled e não a última terminação: um Error posterior em outro container
+// não esconde o OOM.
+func (d *Detector) detectOOMKilled(ts *models.TimeSeriesData, latest *models.HPASnapshot) *Anomaly {
+	if latest.OOMKilledCount == 0 || latest.LastOOMKilledTime == nil {
+		return nil
+	}
+
+	// OOM antigo (pods já estabilizados) não gera alerta
+	if latest.Timestamp.Sub(*latest.LastOOMKilledTime) > d.config.OOMKilledWindow {
+		return nil
+	}
+
+	memory := d.memoryContext(ts, latest)
+
+	return &Anomaly{
+		Type:      AnomalyTypeOOMKilled,
+		Severity:  models.SeverityCritical,
+		Cluster:   latest.Cluster,
+		Namespace: latest.Namespace,
+		HPAName:   latest.Name,
+		Timestamp: time.Now(),
+		Message: fmt.Sprintf("Containers mortos por OOM: %d (%s)",
+			latest.OOMKilledCount, memory.summary()),
+		Description: fmt.Sprintf(
+			"%d container(s) tiveram a última terminação por OOMKilled (última em %s, %d restarts no total). "+
+				"%s. O workload provavelmente está com memória subdimensionada.",
+			latest.OOMKilledCount,
+			latest.LastOOMKilledTime.Format("15:04:05"),
+			latest.RestartCount,
+			memory.summary(),
+		),
+		Snapshot: latest,
+		Stats:    &ts.Stats,
+		Actions: []string{
+			memory.increaseAction(),
+			"Verificar memory leaks: kubectl top pod -n " + latest.Namespace,
+			"Revisar consumo de memória sob carga (heap, caches, buffers)",
+			"Considerar memory target no HPA para escalar antes de atingir o limit",
+		},
+	}
+}
+
+// detectCrashLoop detecta pods em CrashLoopBackOff ou reiniciando com frequência (3+ restarts em 5min)
+func (d *Detector) detectCrashLoop(ts *models.TimeSeriesData, latest *models.HPASnapshot) *Anomaly {
+	restarts := d.restartsInWindow(ts, latest, d.config.CrashLoopWindow)
+	if latest.CrashLoopPods == 0 && restarts < d.config.CrashLoopRestartThreshold {
+		return nil
+	}
+
+	reason := latest.LastTerminationReason
+	if reason == "" {
+		reason = "desconhecido"
+	}
+
+	memory := d.memoryContext(ts, latest)
+	actions := []string{
+		"Verificar logs do container anterior: kubectl logs -n " + latest.Namespace + " <pod> --previous",
+		"Verificar eventos: kubectl describe pod -n " + latest.Namespace + " <pod>",
+		"Verificar liveness probe e tempo de inicialização",
+	}
+	if latest.LastTerminationReason == models.ReasonOOMKilled {
+		actions = append([]string{memory.increaseAction()}, actions...)
+	}
+
+	return &Anomaly{
+		Type:      AnomalyTypeCrashLoop,
+		Severity:  models.SeverityCritical,
+		Cluster:   latest.Cluster,
+		Namespace: latest.Namespace,
+		HPAName:   latest.Name,
+		Timestamp: time.Now(),
+		Message: fmt.Sprintf("Pods em crash loop: %d em CrashLoopBackOff, %d restarts em %v (motivo: %s)",
+			latest.CrashLoopPods, restarts, d.config.CrashLoopWindow, reason),
+		Description: fmt.Sprintf(
+			"%d pod(s) em CrashLoopBackOff e %d restarts nos últimos %v (limite: %d, total: %d). "+
+				"Última terminação: %s. %s.",
+			latest.CrashLoopPods,
+			restarts,
+			d.config.CrashLoopWindow,
+			d.config.CrashLoopRestartThreshold,
+			latest.RestartCount,
+			reason,
+			memory.summary(),
+		),
+		Snapshot: latest,
+		Stats:    &ts.Stats,
+		Actions:  actions,
+	}
+}
+
+// restartsInWindow calcula quantos restarts ocorreram na janela (RestartCount é cumulativo)
+func (d *Detector) restartsInWindow(ts *models.TimeSeriesData, latest *models.HPASnapshot, window time.Duration) int32 {
+	cutoff := latest.Timestamp.Add(-window)
+
+	var oldest *models.HPASnapshot
+	for i := len(ts.Snapshots) - 1; i >= 0; i-- {
+		snapshot := &ts.Snapshots[i]
+		if snapshot.Timestamp.Before(cutoff) {
+			break
+		}
+		oldest = snapshot
+	}
+
+	// Pods recriados zeram o contador - não conta como restart
+	if oldest == nil || latest.RestartCount <= oldest.RestartCount {
+		return 0
+	}
+	return latest.RestartCount - oldest.RestartCount
+}
+
+// memoryUsage limite de memória vs pico de uso observado
+type memoryUsage struct {
+	limit       string
+	request     string
+	peakPercent float64 // % do request (mesma base de MemoryCurrent)
+	limitBytes  float64
+	peakBytes   float64
+}
+
+// memoryContext calcula o pico de memória do cache e do histórico do Prometheus
+func (d *Detector) memoryContext(ts *models.TimeSeriesData, latest *models.HPASnapshot) memoryUsage {
+	usage := memoryUsage{limit: latest.MemoryLimit, request: latest.MemoryRequest}
+
+	for i := range ts.Snapshots {
+		usage.peakPercent = math.Max(usage.peakPercent, ts.Snapshots[i].MemoryCurrent)
+	}
+	for _, value := range latest.MemoryHistory {
+		usage.peakPercent = math.Max(usage.peakPercent, value)
+	}
+
+	if limit, err := resource.ParseQuantity(latest.MemoryLimit); err == nil {
+		usage.limitBytes = limit.AsApproximateFloat64()
+	}
+	if request, err := resource.ParseQuantity(latest.MemoryRequest); err == nil && usage.peakPercent > 0 {
+		usage.peakBytes = request.AsApproximateFloat64() * usage.peakPercent / 100
+	}
+
+	return usage
+}
+
+// summary descreve o limite atual e o pico de uso
+func (m memoryUsage) summary() string {
+	switch {
+	case m.limit == "" && m.peakPercent == 0:
+		return "sem memory limit definido"
+	case m.limit == "":
+		return fmt.Sprintf("sem memory limit definido, pico de uso %.1f%% do request", m.peakPercent)
+	case m.peakPercent == 0:
+		return fmt.Sprintf("memory limit %s, sem métricas de uso de memória", m.limit)
+	case m.peakBytes > 0 && m.limitBytes > 0:
+		return fmt.Sprintf("memory limit %s, pico de uso ~%s (%.1f%% do limit, %.1f%% do request %s)",
+			m.limit, formatMi(m.peakBytes), m.peakBytes/m.limitBytes*100, m.peakPercent, m.request)
+	default:
+		return fmt.Sprintf("memory limit %s, pico de uso %.1f%% do request", m.limit, m.peakPercent)
+	}
+}
+
+// increaseAction sugere novo memory limit (+50% sobre o maior entre limit e pico)
+func (m memoryUsage) increaseAction() string {
+	if m.limitBytes == 0 {
+		return "Definir/aumentar memory limit do container"
+	}
+	suggested := math.Max(m.limitBytes, m.peakBytes) * 1.5
+	return fmt.Sprintf("Aumentar memory limit de %s para ~%s", m.limit, formatMi(suggested))
+}
+
+// formatMi formata bytes em Mi
+func formatMi(bytes float64) string {
+	return fmt.Sprintf("%.0fMi", bytes/(1024*1024))
+}
+
 // detectCPUSpike detecta aumento brusco de CPU (>50% em 1 scan)
 func (d *Detector) detectCPUSpike(ts *models.TimeSeriesData, latest *models.HPASnapshot) *Anomaly {
 	// Precisa de CPU atual
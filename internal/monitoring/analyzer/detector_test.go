@@ -1,6 +1,7 @@
 package analyzer
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -399,3 +400,195 @@ func TestMinDuration(t *testing.T) {
 		}
 	}
 }
+
+func TestDetectOOMKilled(t *testing.T) {
+	cache := storage.NewTimeSeriesCache(nil)
+	detector := NewDetector(cache, nil)
+
+	now := time.Now()
+	terminated := now.Add(-2 * time.Minute)
+	cache.Add(&models.HPASnapshot{
+		Timestamp:     now.Add(-30 * time.Second),
+		Cluster:       "test-cluster",
+		Namespace:     "default",
+		Name:          "test-hpa",
+		MemoryRequest: "512Mi",
+		MemoryLimit:   "1Gi",
+		MemoryCurrent: 150.0,
+	})
+	cache.Add(&models.HPASnapshot{
+		Timestamp:             now,
+		Cluster:               "test-cluster",
+		Namespace:             "default",
+		Name:                  "test-hpa",
+		MemoryRequest:         "512Mi",
+		MemoryLimit:           "1Gi",
+		MemoryCurrent:         40.0,
+		MemoryHistory:         []float64{120.0, 195.0},
+		RestartCount:          2,
+		OOMKilledCount:        2,
+		LastTerminationReason: "OOMKilled",
+		LastTerminationTime:   &terminated,
+		LastOOMKilledTime:     &terminated,
+	})
+
+	result := detector.Detect()
+
+	var oom *Anomaly
+	for i := range result.Anomalies {
+		if result.Anomalies[i].Type == AnomalyTypeOOMKilled {
+			oom = &result.Anomalies[i]
+		}
+	}
+	if oom == nil {
+		t.Fatalf("expected OOM_KILLED anomaly, got %+v", result.Anomalies)
+	}
+	if oom.Severity != models.SeverityCritical {
+		t.Errorf("expected Critical severity, got %v", oom.Severity)
+	}
+
+	// Pico de 195% do request (512Mi) = ~998Mi, 97.5% do limit de 1Gi
+	want := "memory limit 1Gi, pico de uso ~998Mi (97.5% do limit, 195.0% do request 512Mi)"
+	if !strings.Contains(oom.Message, want) {
+		t.Errorf("expected memory context %q in message, got %q", want, oom.Message)
+	}
+	if oom.Actions[0] != "Aumentar memory limit de 1Gi para ~1536Mi" {
+		t.Errorf("unexpected first action: %q", oom.Actions[0])
+	}
+}
+
+func TestDetectOOMKilled_OldTermination(t *testing.T) {
+	cache := storage.NewTimeSeriesCache(nil)
+	detector := NewDetector(cache, nil)
+
+	now := time.Now()
+	terminated := now.Add(-time.Hour)
+	cache.Add(&models.HPASnapshot{
+		Timestamp:             now,
+		Cluster:               "test-cluster",
+		Namespace:             "default",
+		Name:                  "test-hpa",
+		OOMKilledCount:        1,
+		LastTerminationReason: "OOMKilled",
+		LastTerminationTime:   &terminated,
+		LastOOMKilledTime:     &terminated,
+	})
+
+	for _, anomaly := range detector.Detect().Anomalies {
+		if anomaly.Type == AnomalyTypeOOMKilled {
+			t.Error("should not detect OOM_KILLED for termination outside the window")
+		}
+	}
+}
+
+func TestDetectOOMKilled_LaterTerminationWithOtherReason(t *testing.T) {
+	cache := storage.NewTimeSeriesCache(nil)
+	detector := NewDetector(cache, nil)
+
+	now := time.Now()
+	oomKilled := now.Add(-3 * time.Minute)
+	errored := now.Add(-time.Minute)
+	cache.Add(&models.HPASnapshot{
+		Timestamp:             now,
+		Cluster:               "test-cluster",
+		Namespace:             "default",
+		Name:                  "test-hpa",
+		OOMKilledCount:        1,
+		LastTerminationReason: "Error",
+		LastTerminationTime:   &errored,
+		LastOOMKilledTime:     &oomKilled,
+	})
+
+	for _, anomaly := range detector.Detect().Anomalies {
+		if anomaly.Type == AnomalyTypeOOMKilled {
+			return
+		}
+	}
+	t.Error("expected OOM_KILLED when a later termination of another container has a different reason")
+}
+
+func TestDetectOOMKilled_OldOOMWithRecentTermination(t *testing.T) {
+	cache := storage.NewTimeSeriesCache(nil)
+	detector := NewDetector(cache, nil)
+
+	// Última terminação recente, mas o OOMKilled é antigo: não deve alertar
+	now := time.Now()
+	oomKilled := now.Add(-time.Hour)
+	errored := now.Add(-time.Minute)
+	cache.Add(&models.HPASnapshot{
+		Timestamp:             now,
+		Cluster:               "test-cluster",
+		Namespace:             "default",
+		Name:                  "test-hpa",
+		OOMKilledCount:        1,
+		LastTerminationReason: "Error",
+		LastTerminationTime:   &errored,
+		LastOOMKilledTime:     &oomKilled,
+	})
+
+	for _, anomaly := range detector.Detect().Anomalies {
+		if anomaly.Type == AnomalyTypeOOMKilled {
+			t.Error("should not detect OOM_KILLED when the OOM termination is outside the window")
+		}
+	}
+}
+
+func TestDetectCrashLoop(t *testing.T) {
+	cache := storage.NewTimeSeriesCache(&storage.CacheConfig{
+		MaxDuration:  10 * time.Minute,
+		ScanInterval: 30 * time.Second,
+	})
+	detector := NewDetector(cache, nil)
+
+	// Restarts cumulativos: 1 → 5 em 2min (4 restarts na janela de 5min)
+	now := time.Now().Add(-2 * time.Minute)
+	for i, restarts := range []int32{1, 2, 3, 4, 5} {
+		cache.Add(&models.HPASnapshot{
+			Timestamp:             now.Add(time.Duration(i) * 30 * time.Second),
+			Cluster:               "test-cluster",
+			Namespace:             "default",
+			Name:                  "test-hpa",
+			MemoryLimit:           "256Mi",
+			RestartCount:          restarts,
+			LastTerminationReason: "Error",
+		})
+	}
+
+	result := detector.Detect()
+
+	found := false
+	for _, anomaly := range result.Anomalies {
+		if anomaly.Type == AnomalyTypeCrashLoop {
+			found = true
+			if !strings.Contains(anomaly.Message, "4 restarts") {
+				t.Errorf("expected restart count in message, got %q", anomaly.Message)
+			}
+			if !strings.Contains(anomaly.Description, "memory limit 256Mi") {
+				t.Errorf("expected memory limit in description, got %q", anomaly.Description)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected to find CRASH_LOOP anomaly")
+	}
+}
+
+func TestDetectCrashLoop_BackOffState(t *testing.T) {
+	cache := storage.NewTimeSeriesCache(nil)
+	detector := NewDetector(cache, nil)
+
+	// Um único snapshot basta quando o pod já está em CrashLoopBackOff
+	cache.Add(&models.HPASnapshot{
+		Timestamp:     time.Now(),
+		Cluster:       "test-cluster",
+		Namespace:     "default",
+		Name:          "test-hpa",
+		RestartCount:  12,
+		CrashLoopPods: 1,
+	})
+
+	counts := detector.Detect().GetAnomalyCount()
+	if counts[AnomalyTypeCrashLoop] != 1 {
+		t.Errorf("expected 1 CRASH_LOOP anomaly, got %d", counts[AnomalyTypeCrashLoop])
+	}
+}
@@ -0,0 +1,338 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	appmodels "k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/monitoring/models"
+	"k8s-hpa-manager/internal/monitoring/storage"
+	"k8s-hpa-manager/internal/nodepool"
+)
+
+// ForecastConfig parâmetros da previsão de réplicas (Holt-Winters aditivo com tendência amortecida)
+type ForecastConfig struct {
+	History  time.Duration // Histórico usado no ajuste (default: 28 dias)
+	Horizon  time.Duration // Período previsto (default: 7 dias)
+	Step     time.Duration // Resolução da série (default: 1h, pico de réplicas por bucket)
+	Alpha    float64       // Suavização do nível (default: 0.3)
+	Beta     float64       // Suavização da tendência (default: 0.05)
+	Gamma    float64       // Suavização da sazonalidade (default: 0.2)
+	Damping  float64       // Amortecimento da tendência no horizonte (default: 0.98)
+	Z        float64       // Desvios do erro para o limite superior (default: 1.28 ≈ p90)
+	Headroom float64       // Folga sobre o limite superior em maxReplicas/nodes (default: 0.1)
+}
+
+// DefaultForecastConfig retorna configuração padrão
+func DefaultForecastConfig() ForecastConfig {
+	return ForecastConfig{
+		History:  28 * 24 * time.Hour,
+		Horizon:  7 * 24 * time.Hour,
+		Step:     time.Hour,
+		Alpha:    0.3,
+		Beta:     0.05,
+		Gamma:    0.2,
+		Damping:  0.98,
+		Z:        1.28,
+		Headroom: 0.1,
+	}
+}
+
+// Modelos usados conforme o histórico disponível
+const (
+	ForecastModelWeekly = "holt-winters-weekly" // 2+ semanas de histórico
+	ForecastModelDaily  = "holt-winters-daily"  // 2+ dias de histórico
+	ForecastModelTrend  = "holt-linear"         // sem sazonalidade
+)
+
+// ForecastPoint valor previsto em um instante
+type ForecastPoint struct {
+	Time     time.Time `json:"time"`
+	Replicas float64   `json:"replicas"`
+	Upper    float64   `json:"upper"`
+}
+
+// ReplicaForecast previsão de réplicas de um HPA
+type ReplicaForecast struct {
+	Cluster         string          `json:"cluster"`
+	Namespace       string          `json:"namespace"`
+	HPAName         string          `json:"hpa_name"`
+	Model           string          `json:"model"`
+	Points          int             `json:"history_points"`
+	MinReplicas     int32           `json:"min_replicas"`
+	MaxReplicas     int32           `json:"max_replicas"`
+	CurrentReplicas int32           `json:"current_replicas"`
+	ObservedPeak    float64         `json:"observed_peak"`
+	PredictedPeak   float64         `json:"predicted_peak"`
+	PeakAt          time.Time       `json:"peak_at"`
+	UpperBound      int32           `json:"upper_bound"` // Pico + Z × RMSE, arredondado para cima
+	RMSE            float64         `json:"rmse"`
+	ExceedsMax      bool            `json:"exceeds_max"` // Limite superior acima de maxReplicas
+	CPURequest      string          `json:"cpu_request,omitempty"`
+	MemoryRequest   string          `json:"memory_request,omitempty"`
+	Forecast        []ForecastPoint `json:"forecast"`
+}
+
+// ForecastReplicas prevê as réplicas de um HPA a partir dos snapshots (ordenados por timestamp).
+// A série usa o pico de réplicas de cada Step (replicas_max nos snapshots de rollup).
+func ForecastReplicas(snapshots []models.HPASnapshot, config ForecastConfig, now time.Time) (*ReplicaForecast, error) {
+	series, start := replicaSeries(snapshots, config.Step)
+	if len(series) < 3 {
+		return nil, fmt.Errorf("not enough history to forecast (%d points)", len(series))
+	}
+
+	last := snapshots[len(snapshots)-1]
+	forecast := &ReplicaForecast{
+		Cluster:         last.Cluster,
+		Namespace:       last.Namespace,
+		HPAName:         last.Name,
+		Points:          len(series),
+		MinReplicas:     last.MinReplicas,
+		MaxReplicas:     last.MaxReplicas,
+		CurrentReplicas: last.CurrentReplicas,
+		CPURequest:      last.CPURequest,
+		MemoryRequest:   last.MemoryRequest,
+	}
+	for _, v := range series {
+		forecast.ObservedPeak = math.Max(forecast.ObservedPeak, v)
+	}
+
+	season := 0
+	switch {
+	case len(series) >= 2*int(7*24*time.Hour/config.Step):
+		season, forecast.Model = int(7*24*time.Hour/config.Step), ForecastModelWeekly
+	case len(series) >= 2*int(24*time.Hour/config.Step):
+		season, forecast.Model = int(24*time.Hour/config.Step), ForecastModelDaily
+	default:
+		forecast.Model = ForecastModelTrend
+	}
+
+	horizon := int(config.Horizon / config.Step)
+	// A série termina no bucket atual: o horizonte começa no próximo Step
+	lastBucket := start.Add(time.Duration(len(series)-1) * config.Step)
+	skip := 0
+	if next := now.Truncate(config.Step); next.After(lastBucket) {
+		skip = int(next.Sub(lastBucket) / config.Step)
+	}
+
+	values, rmse := holtWinters(series, season, horizon+skip, config)
+	forecast.RMSE = rmse
+	for h := skip; h < len(values); h++ {
+		value := math.Max(values[h], float64(forecast.MinReplicas))
+		point := ForecastPoint{
+			Time:     lastBucket.Add(time.Duration(h+1) * config.Step),
+			Replicas: math.Round(value*100) / 100,
+			Upper:    math.Round((value+config.Z*rmse)*100) / 100,
+		}
+		forecast.Forecast = append(forecast.Forecast, point)
+		if point.Replicas > forecast.PredictedPeak {
+			forecast.PredictedPeak, forecast.PeakAt = point.Replicas, point.Time
+		}
+	}
+
+	forecast.UpperBound = int32(math.Ceil(forecast.PredictedPeak + config.Z*rmse - 1e-9))
+	if forecast.UpperBound < forecast.MinReplicas {
+		forecast.UpperBound = forecast.MinReplicas
+	}
+	forecast.ExceedsMax = forecast.MaxReplicas > 0 && forecast.UpperBound > forecast.MaxReplicas
+	return forecast, nil
+}
+
+// replicaSeries agrega os snapshots em uma série regular (pico por step) interpolando lacunas
+func replicaSeries(snapshots []models.HPASnapshot, step time.Duration) ([]float64, time.Time) {
+	if len(snapshots) == 0 {
+		return nil, time.Time{}
+	}
+
+	start := snapshots[0].Timestamp.Truncate(step)
+	end := snapshots[len(snapshots)-1].Timestamp.Truncate(step)
+	series := make([]float64, int(end.Sub(start)/step)+1)
+	filled := make([]bool, len(series))
+	for i := range snapshots {
+		s := &snapshots[i]
+		value := float64(s.CurrentReplicas)
+		if peak, ok := s.AdditionalMetrics["replicas_max"].(float64); ok && isRollup(s) {
+			value = peak
+		}
+		idx := int(s.Timestamp.Truncate(step).Sub(start) / step)
+		if !filled[idx] || value > series[idx] {
+			series[idx], filled[idx] = value, true
+		}
+	}
+
+	// Interpolação linear das lacunas (scanner parado, cluster sem coleta)
+	prev := 0
+	for i := 1; i < len(series); i++ {
+		if !filled[i] {
+			continue
+		}
+		for j := prev + 1; j < i; j++ {
+			series[j] = series[prev] + (series[i]-series[prev])*float64(j-prev)/float64(i-prev)
+		}
+		prev = i
+	}
+	return series, start
+}
+
+// holtWinters ajusta o modelo aditivo (season = 0: Holt linear) e retorna horizon previsões
+// e o RMSE das previsões um passo à frente
+func holtWinters(series []float64, season, horizon int, config ForecastConfig) ([]float64, float64) {
+	alpha, beta, gamma, phi := config.Alpha, config.Beta, config.Gamma, config.Damping
+
+	level, trend := series[0], 0.0
+	seasonal := make([]float64, season)
+	first := 1
+	if season > 0 {
+		level = mean(series[:season])
+		trend = (mean(series[season:2*season]) - level) / float64(season)
+		for i := 0; i < season; i++ {
+			seasonal[i] = series[i] - level
+		}
+		first = season
+	} else {
+		trend = series[1] - series[0]
+	}
+
+	sse, n := 0.0, 0
+	for t := first; t < len(series); t++ {
+		s := 0.0
+		if season > 0 {
+			s = seasonal[t%season]
+		}
+		predicted := level + phi*trend + s
+		sse += (series[t] - predicted) * (series[t] - predicted)
+		n++
+
+		prevLevel := level
+		level = alpha*(series[t]-s) + (1-alpha)*(prevLevel+phi*trend)
+		trend = beta*(level-prevLevel) + (1-beta)*phi*trend
+		if season > 0 {
+			seasonal[t%season] = gamma*(series[t]-level) + (1-gamma)*s
+		}
+	}
+
+	forecast := make([]float64, horizon)
+	damped := 0.0
+	for h := 1; h <= horizon; h++ {
+		damped += math.Pow(phi, float64(h))
+		value := level + damped*trend
+		if season > 0 {
+			value += seasonal[(len(series)+h-1)%season]
+		}
+		forecast[h-1] = math.Max(0, value)
+	}
+
+	rmse := 0.0
+	if n > 0 {
+		rmse = math.Sqrt(sse / float64(n))
+	}
+	return forecast, rmse
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// ForecastFromPersistence prevê as réplicas dos HPAs com snapshots persistidos no histórico
+// (cluster/namespace vazios = todos). HPAs com histórico insuficiente são ignorados.
+func ForecastFromPersistence(persistence *storage.Persistence, cluster, namespace string, config ForecastConfig) ([]*ReplicaForecast, error) {
+	if persistence == nil {
+		return nil, fmt.Errorf("persistence not available")
+	}
+
+	now := time.Now()
+	since := now.Add(-config.History)
+	hpas, err := persistence.ListSnapshotHPAs(cluster, namespace, since)
+	if err != nil {
+		return nil, err
+	}
+
+	forecasts := make([]*ReplicaForecast, 0, len(hpas))
+	for _, ref := range hpas {
+		snapshots, err := persistence.LoadSnapshots(ref.Cluster, ref.Namespace, ref.Name, since)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load snapshots of %s/%s/%s: %w", ref.Cluster, ref.Namespace, ref.Name, err)
+		}
+		forecast, err := ForecastReplicas(snapshots, config, now)
+		if err != nil {
+			continue
+		}
+		forecasts = append(forecasts, forecast)
+	}
+	return forecasts, nil
+}
+
+// NodePoolPlan dimensionamento do node pool para o pico previsto
+type NodePoolPlan struct {
+	NodePool          string              `json:"node_pool"`
+	VMSize            string              `json:"vm_size"`
+	Capacity          nodepool.VMCapacity `json:"capacity"`
+	Allocatable       nodepool.VMCapacity `json:"allocatable"`
+	CPURequired       float64             `json:"cpu_required"`        // vCPUs (requests × limite superior de réplicas)
+	MemoryRequiredGiB float64             `json:"memory_required_gib"` // GiB
+	NodesForCPU       int32               `json:"nodes_for_cpu"`
+	NodesForMemory    int32               `json:"nodes_for_memory"`
+	RecommendedNodes  int32               `json:"recommended_nodes"` // Maior dos dois + Headroom
+	CurrentNodes      int32               `json:"current_nodes"`
+	MaxNodes          int32               `json:"max_nodes"`
+	Shortfall         int32               `json:"shortfall"`    // Nodes faltando além do máximo atual (ou do count sem autoscaling)
+	UnsizedHPAs       []string            `json:"unsized_hpas"` // HPAs sem requests (não entram no cálculo)
+}
+
+// PlanNodePool calcula quantos nodes do pool (capacidade de VMSize) comportam o limite superior
+// de réplicas previsto, somando os requests de CPU/memória dos pods
+func PlanNodePool(forecasts []*ReplicaForecast, pool appmodels.NodePool, config ForecastConfig) (*NodePoolPlan, error) {
+	capacity, err := nodepool.ParseVMCapacity(pool.VMSize)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &NodePoolPlan{
+		NodePool:     pool.Name,
+		VMSize:       pool.VMSize,
+		Capacity:     capacity,
+		Allocatable:  capacity.Allocatable(),
+		CurrentNodes: pool.NodeCount,
+		MaxNodes:     pool.NodeCount,
+	}
+	if pool.AutoscalingEnabled {
+		plan.MaxNodes = pool.MaxNodeCount
+	}
+
+	for _, forecast := range forecasts {
+		cpu, cpuErr := resource.ParseQuantity(forecast.CPURequest)
+		memory, memErr := resource.ParseQuantity(forecast.MemoryRequest)
+		if cpuErr != nil && memErr != nil {
+			plan.UnsizedHPAs = append(plan.UnsizedHPAs, forecast.Namespace+"/"+forecast.HPAName)
+			continue
+		}
+		replicas := float64(forecast.UpperBound)
+		if cpuErr == nil {
+			plan.CPURequired += replicas * float64(cpu.MilliValue()) / 1000
+		}
+		if memErr == nil {
+			plan.MemoryRequiredGiB += replicas * float64(memory.Value()) / (1024 * 1024 * 1024)
+		}
+	}
+
+	plan.CPURequired = math.Round(plan.CPURequired*100) / 100
+	plan.MemoryRequiredGiB = math.Round(plan.MemoryRequiredGiB*100) / 100
+	plan.NodesForCPU = int32(math.Ceil(plan.CPURequired / plan.Allocatable.VCPU))
+	plan.NodesForMemory = int32(math.Ceil(plan.MemoryRequiredGiB / plan.Allocatable.MemoryGiB))
+	nodes := math.Max(float64(plan.NodesForCPU), float64(plan.NodesForMemory))
+	plan.RecommendedNodes = int32(math.Ceil(nodes * (1 + config.Headroom)))
+	if plan.RecommendedNodes < 1 {
+		plan.RecommendedNodes = 1
+	}
+	if plan.RecommendedNodes > plan.MaxNodes {
+		plan.Shortfall = plan.RecommendedNodes - plan.MaxNodes
+	}
+	return plan, nil
+}
@@ -0,0 +1,158 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	appmodels "k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/session"
+)
+
+// ForecastTemplate TemplateUsed das sessões geradas por BuildForecastSession
+const ForecastTemplate = "forecast"
+
+// ForecastSessionOptions controla a geração da sessão de upscale a partir da previsão
+type ForecastSessionOptions struct {
+	// Name nome da sessão (vazio = Forecast_<cluster>_<timestamp>)
+	Name string
+	// ClusterContext converte o cluster dos snapshots (sem -admin) no contexto do kubeconfig
+	// usado pela sessão (nil = cluster dos snapshots)
+	ClusterContext func(cluster string) string
+	// NodePool e Plan adicionam a mudança do node pool (nil = só HPAs)
+	NodePool *appmodels.NodePool
+	Plan     *NodePoolPlan
+	// PreScale também eleva minReplicas ao pico previsto e minNodeCount aos nodes recomendados
+	// (capacidade pronta antes do evento, sem depender do autoscaler)
+	PreScale bool
+	// Headroom folga sobre o limite superior ao elevar maxReplicas (ex: 0.1 = +10%)
+	Headroom float64
+	// Now horário de criação (zero = time.Now())
+	Now time.Time
+}
+
+// BuildForecastSession gera uma sessão de upscale para o pico previsto: eleva maxReplicas dos HPAs
+// cujo limite superior passa do máximo atual e o node pool para os nodes recomendados pelo plano.
+// A pasta é HPA-Upscale, ou Node-Upscale quando só o node pool muda.
+func BuildForecastSession(forecasts []*ReplicaForecast, opts ForecastSessionOptions) (*appmodels.Session, session.SessionFolder, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	clusterContext := opts.ClusterContext
+	if clusterContext == nil {
+		clusterContext = func(cluster string) string { return cluster }
+	}
+
+	sess := &appmodels.Session{
+		CreatedAt:    now,
+		TemplateUsed: ForecastTemplate,
+		RollbackData: &appmodels.RollbackData{OriginalStateCaptured: true, CanRollback: true},
+	}
+
+	var clusters []string
+	seen := make(map[string]bool)
+	for _, forecast := range forecasts {
+		if forecast == nil {
+			continue
+		}
+
+		minReplicas, maxReplicas := forecast.MinReplicas, forecast.MaxReplicas
+		if forecast.ExceedsMax {
+			maxReplicas = int32(math.Ceil(float64(forecast.UpperBound) * (1 + opts.Headroom)))
+		}
+		if opts.PreScale {
+			peak := int32(math.Ceil(forecast.PredictedPeak))
+			if peak > minReplicas {
+				minReplicas = min(peak, maxReplicas)
+			}
+		}
+		if minReplicas == forecast.MinReplicas && maxReplicas == forecast.MaxReplicas {
+			continue
+		}
+
+		if !seen[forecast.Cluster] {
+			seen[forecast.Cluster] = true
+			clusters = append(clusters, forecast.Cluster)
+		}
+		originalMin := forecast.MinReplicas
+		sess.Changes = append(sess.Changes, appmodels.HPAChange{
+			Cluster:   clusterContext(forecast.Cluster),
+			Namespace: forecast.Namespace,
+			HPAName:   forecast.HPAName,
+			OriginalValues: &appmodels.HPAValues{
+				MinReplicas: &originalMin,
+				MaxReplicas: forecast.MaxReplicas,
+			},
+			NewValues: &appmodels.HPAValues{
+				MinReplicas: &minReplicas,
+				MaxReplicas: maxReplicas,
+			},
+		})
+	}
+
+	if change, ok := forecastNodePoolChange(opts); ok {
+		if len(clusters) == 0 && opts.NodePool.ClusterName != "" {
+			clusters = append(clusters, opts.NodePool.ClusterName)
+		}
+		sess.NodePoolChanges = append(sess.NodePoolChanges, change)
+	}
+
+	if len(sess.Changes) == 0 && len(sess.NodePoolChanges) == 0 {
+		return nil, "", fmt.Errorf("forecast fits current capacity, no changes to apply")
+	}
+
+	sess.Name = opts.Name
+	if sess.Name == "" {
+		base := "multi"
+		if len(clusters) == 1 {
+			base = clusters[0]
+		}
+		sess.Name = session.DerivedSessionName("Forecast_", base, now)
+	}
+	sess.Description = fmt.Sprintf("Upscale para o pico previsto: %d HPAs, %d node pools", len(sess.Changes), len(sess.NodePoolChanges))
+	sess.Metadata = session.GenerateMetadata(sess)
+
+	folder := session.FolderHPAUpscale
+	if len(sess.Changes) == 0 {
+		folder = session.FolderNodeUpscale
+	}
+	return sess, folder, nil
+}
+
+// forecastNodePoolChange eleva o node pool aos nodes recomendados (maxNodeCount com autoscaling,
+// nodeCount sem); nunca reduz a capacidade atual
+func forecastNodePoolChange(opts ForecastSessionOptions) (appmodels.NodePoolChange, bool) {
+	pool, plan := opts.NodePool, opts.Plan
+	if pool == nil || plan == nil {
+		return appmodels.NodePoolChange{}, false
+	}
+
+	original := appmodels.NodePoolValues{
+		NodeCount:          pool.NodeCount,
+		MinNodeCount:       pool.MinNodeCount,
+		MaxNodeCount:       pool.MaxNodeCount,
+		AutoscalingEnabled: pool.AutoscalingEnabled,
+	}
+	target := original
+	if pool.AutoscalingEnabled {
+		target.MaxNodeCount = max(original.MaxNodeCount, plan.RecommendedNodes)
+		if opts.PreScale {
+			target.MinNodeCount = max(original.MinNodeCount, plan.RecommendedNodes)
+		}
+	} else {
+		target.NodeCount = max(original.NodeCount, plan.RecommendedNodes)
+	}
+	if target == original {
+		return appmodels.NodePoolChange{}, false
+	}
+
+	return appmodels.NodePoolChange{
+		Cluster:        pool.ClusterName,
+		ResourceGroup:  pool.ResourceGroup,
+		Subscription:   pool.Subscription,
+		NodePoolName:   pool.Name,
+		OriginalValues: original,
+		NewValues:      target,
+	}, true
+}
@@ -0,0 +1,158 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	appmodels "k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/monitoring/models"
+	"k8s-hpa-manager/internal/session"
+)
+
+// seasonalSnapshots gera 28 dias de snapshots (a cada 10min) com carga diária e pico semanal
+// de 12 réplicas às sextas, 18h-21h
+func seasonalSnapshots(now time.Time) []models.HPASnapshot {
+	start := now.Add(-28 * 24 * time.Hour)
+	var snapshots []models.HPASnapshot
+	for ts := start; !ts.After(now); ts = ts.Add(10 * time.Minute) {
+		replicas := int32(3)
+		if hour := ts.Hour(); hour >= 9 && hour < 18 {
+			replicas = 5
+		}
+		if ts.Weekday() == time.Friday && ts.Hour() >= 18 && ts.Hour() < 21 {
+			replicas = 12
+		}
+		snapshots = append(snapshots, models.HPASnapshot{
+			Timestamp:       ts,
+			Cluster:         "aks-prd",
+			Namespace:       "api",
+			Name:            "checkout",
+			MinReplicas:     3,
+			MaxReplicas:     10,
+			CurrentReplicas: replicas,
+			CPURequest:      "500m",
+			MemoryRequest:   "1Gi",
+		})
+	}
+	return snapshots
+}
+
+func TestForecastReplicasWeekly(t *testing.T) {
+	now := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC) // segunda-feira
+	forecast, err := ForecastReplicas(seasonalSnapshots(now), DefaultForecastConfig(), now)
+	if err != nil {
+		t.Fatalf("ForecastReplicas failed: %v", err)
+	}
+
+	if forecast.Model != ForecastModelWeekly || forecast.ObservedPeak != 12 {
+		t.Errorf("unexpected model/observed peak: %s/%v", forecast.Model, forecast.ObservedPeak)
+	}
+	if len(forecast.Forecast) != 7*24 || !forecast.Forecast[0].Time.After(now) {
+		t.Fatalf("unexpected forecast horizon: %d points starting %v", len(forecast.Forecast), forecast.Forecast[0].Time)
+	}
+	if math.Abs(forecast.PredictedPeak-12) > 1 {
+		t.Errorf("expected predicted peak near 12, got %.2f", forecast.PredictedPeak)
+	}
+	if forecast.PeakAt.Weekday() != time.Friday || forecast.PeakAt.Hour() < 18 || forecast.PeakAt.Hour() >= 21 {
+		t.Errorf("expected peak on friday evening, got %v", forecast.PeakAt)
+	}
+	if !forecast.ExceedsMax || forecast.UpperBound < 12 {
+		t.Errorf("expected upper bound above maxReplicas, got %d", forecast.UpperBound)
+	}
+	// Madrugada volta ao mínimo
+	if first := forecast.Forecast[0]; math.Abs(first.Replicas-3) > 0.5 {
+		t.Errorf("expected ~3 replicas at night, got %.2f", first.Replicas)
+	}
+}
+
+func TestForecastReplicasShortHistory(t *testing.T) {
+	now := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	snapshots := seasonalSnapshots(now)
+	config := DefaultForecastConfig()
+
+	// 3 dias: só sazonalidade diária
+	forecast, err := ForecastReplicas(snapshots[len(snapshots)-3*24*6:], config, now)
+	if err != nil || forecast.Model != ForecastModelDaily {
+		t.Errorf("expected daily model, got %+v (err=%v)", forecast, err)
+	}
+
+	// 6 horas: tendência linear
+	forecast, err = ForecastReplicas(snapshots[len(snapshots)-6*6:], config, now)
+	if err != nil || forecast.Model != ForecastModelTrend {
+		t.Errorf("expected linear model, got %+v (err=%v)", forecast, err)
+	}
+
+	if _, err := ForecastReplicas(snapshots[:2], config, now); err == nil {
+		t.Error("expected error without enough history")
+	}
+}
+
+func TestPlanNodePoolAndSession(t *testing.T) {
+	forecasts := []*ReplicaForecast{
+		{Cluster: "aks-prd", Namespace: "api", HPAName: "checkout", MinReplicas: 3, MaxReplicas: 10,
+			PredictedPeak: 18.4, UpperBound: 20, ExceedsMax: true, CPURequest: "2", MemoryRequest: "4Gi"},
+		{Cluster: "aks-prd", Namespace: "api", HPAName: "catalog", MinReplicas: 2, MaxReplicas: 8,
+			PredictedPeak: 4, UpperBound: 5, CPURequest: "500m", MemoryRequest: "512Mi"},
+		{Cluster: "aks-prd", Namespace: "api", HPAName: "legacy", MinReplicas: 1, MaxReplicas: 4, UpperBound: 2},
+	}
+	pool := appmodels.NodePool{
+		Name: "apps", VMSize: "Standard_D8s_v3", ClusterName: "aks-prd-admin",
+		NodeCount: 3, MinNodeCount: 2, MaxNodeCount: 5, AutoscalingEnabled: true,
+	}
+
+	plan, err := PlanNodePool(forecasts, pool, DefaultForecastConfig())
+	if err != nil {
+		t.Fatalf("PlanNodePool failed: %v", err)
+	}
+	// CPU: 20×2 + 5×0.5 = 42.5 vCPU / 7.91 alocável → 6 nodes; memória: 82.5GiB / ~28.3 → 3
+	if plan.CPURequired != 42.5 || plan.MemoryRequiredGiB != 82.5 {
+		t.Errorf("unexpected requirements: %.2f vCPU / %.2f GiB", plan.CPURequired, plan.MemoryRequiredGiB)
+	}
+	if plan.NodesForCPU != 6 || plan.NodesForMemory != 3 || plan.RecommendedNodes != 7 || plan.Shortfall != 2 {
+		t.Errorf("unexpected plan: %+v", plan)
+	}
+	if len(plan.UnsizedHPAs) != 1 || plan.UnsizedHPAs[0] != "api/legacy" {
+		t.Errorf("unexpected unsized HPAs: %v", plan.UnsizedHPAs)
+	}
+
+	sess, folder, err := BuildForecastSession(forecasts, ForecastSessionOptions{
+		ClusterContext: func(cluster string) string { return cluster + "-admin" },
+		NodePool:       &pool,
+		Plan:           plan,
+		PreScale:       true,
+		Headroom:       0.1,
+	})
+	if err != nil {
+		t.Fatalf("BuildForecastSession failed: %v", err)
+	}
+	if folder != session.FolderHPAUpscale || sess.TemplateUsed != ForecastTemplate {
+		t.Errorf("unexpected folder/template: %s/%s", folder, sess.TemplateUsed)
+	}
+	// checkout: max 20×1.1 = 22, min ao pico 19; catalog: min ao pico 4; legacy sem mudança
+	if len(sess.Changes) != 2 {
+		t.Fatalf("expected 2 HPA changes, got %d", len(sess.Changes))
+	}
+	checkout := sess.Changes[0]
+	if checkout.Cluster != "aks-prd-admin" || checkout.NewValues.MaxReplicas != 22 || *checkout.NewValues.MinReplicas != 19 {
+		t.Errorf("unexpected checkout change: %+v", checkout.NewValues)
+	}
+	if catalog := sess.Changes[1]; catalog.NewValues.MaxReplicas != 8 || *catalog.NewValues.MinReplicas != 4 {
+		t.Errorf("unexpected catalog change: %+v", catalog.NewValues)
+	}
+	nodeChange := sess.NodePoolChanges[0]
+	if nodeChange.NewValues.MaxNodeCount != 7 || nodeChange.NewValues.MinNodeCount != 7 || nodeChange.OriginalValues.MaxNodeCount != 5 {
+		t.Errorf("unexpected node pool change: %+v", nodeChange)
+	}
+
+	// Só o node pool (sem autoscaling) → Node-Upscale com nodeCount
+	pool.AutoscalingEnabled = false
+	sess, folder, err = BuildForecastSession(nil, ForecastSessionOptions{NodePool: &pool, Plan: plan})
+	if err != nil || folder != session.FolderNodeUpscale || sess.NodePoolChanges[0].NewValues.NodeCount != 7 {
+		t.Errorf("expected node-only upscale, got %s (err=%v)", folder, err)
+	}
+
+	if _, _, err := BuildForecastSession(forecasts[2:], ForecastSessionOptions{}); err == nil {
+		t.Error("expected error when forecast fits current capacity")
+	}
+}
@@ -0,0 +1,463 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"k8s-hpa-manager/internal/monitoring/models"
+	"k8s-hpa-manager/internal/monitoring/storage"
+)
+
+// RightSizingConfig parâmetros do cálculo de right-sizing
+type RightSizingConfig struct {
+	Window            time.Duration // Período analisado (default: 7 dias)
+	MinSamples        int           // Amostras para confiança total (default: 500)
+	ReplicaHeadroom   float64       // Folga sobre o p99 da demanda para maxReplicas (default: 0.3)
+	SaturationPercent float64       // % do tempo em maxReplicas que indica HPA saturado (default: 5)
+	RequestHeadroom   float64       // Folga sobre o uso p95 (CPU) / p99 (memória) para requests (default: 0.15)
+	MinChangePercent  float64       // Diferença mínima para recomendar novo request/limit (default: 10)
+}
+
+// DefaultRightSizingConfig retorna configuração padrão
+func DefaultRightSizingConfig() RightSizingConfig {
+	return RightSizingConfig{
+		Window:            7 * 24 * time.Hour,
+		MinSamples:        500,
+		ReplicaHeadroom:   0.3,
+		SaturationPercent: 5,
+		RequestHeadroom:   0.15,
+		MinChangePercent:  10,
+	}
+}
+
+// Níveis de confiança da recomendação
+const (
+	ConfidenceHigh   = "high"
+	ConfidenceMedium = "medium"
+	ConfidenceLow    = "low"
+)
+
+// HPASizing configuração de um HPA (atual ou recomendada).
+// Requests/limits vazios = não informado (atual) ou sem alteração (recomendado).
+type HPASizing struct {
+	MinReplicas   int32  `json:"min_replicas"`
+	MaxReplicas   int32  `json:"max_replicas"`
+	CPUTarget     int32  `json:"cpu_target,omitempty"`
+	MemoryTarget  int32  `json:"memory_target,omitempty"`
+	CPURequest    string `json:"cpu_request,omitempty"`
+	CPULimit      string `json:"cpu_limit,omitempty"`
+	MemoryRequest string `json:"memory_request,omitempty"`
+	MemoryLimit   string `json:"memory_limit,omitempty"`
+}
+
+// UsageStats distribuição de uso no período analisado
+type UsageStats struct {
+	CPUP50       float64 `json:"cpu_p50"` // % do request
+	CPUP95       float64 `json:"cpu_p95"`
+	CPUP99       float64 `json:"cpu_p99"`
+	MemoryP50    float64 `json:"memory_p50"`
+	MemoryP95    float64 `json:"memory_p95"`
+	MemoryP99    float64 `json:"memory_p99"`
+	MemoryMax    float64 `json:"memory_max"`
+	ReplicasP05  float64 `json:"replicas_p05"`
+	ReplicasP50  float64 `json:"replicas_p50"`
+	ReplicasP95  float64 `json:"replicas_p95"`
+	ReplicasP99  float64 `json:"replicas_p99"`
+	TimeAtMinPct float64 `json:"time_at_min_percent"`
+	TimeAtMaxPct float64 `json:"time_at_max_percent"`
+	DemandP05    float64 `json:"demand_replicas_p05"` // Réplicas necessárias no target/request recomendados
+	DemandP99    float64 `json:"demand_replicas_p99"`
+}
+
+// RightSizing recomendação de dimensionamento de um HPA
+type RightSizing struct {
+	Cluster     string        `json:"cluster"`
+	Namespace   string        `json:"namespace"`
+	HPAName     string        `json:"hpa_name"`
+	Samples     int           `json:"samples"`
+	From        time.Time     `json:"from"`
+	To          time.Time     `json:"to"`
+	Current     HPASizing     `json:"current"`
+	Recommended HPASizing     `json:"recommended"`
+	Usage       UsageStats    `json:"usage"`
+	Confidence  float64       `json:"confidence"` // 0-1
+	Level       string        `json:"confidence_level"`
+	Saturated   bool          `json:"saturated"` // Tempo em maxReplicas acima de SaturationPercent
+	Rationale   []string      `json:"rationale"`
+	Window      time.Duration `json:"-"`
+}
+
+// Key retorna a chave cluster/namespace/hpa
+func (r *RightSizing) Key() string {
+	return fmt.Sprintf("%s/%s/%s", r.Cluster, r.Namespace, r.HPAName)
+}
+
+// HasChanges indica se a recomendação difere da configuração atual
+func (r *RightSizing) HasChanges() bool {
+	rec, cur := r.Recommended, r.Current
+	return rec.MinReplicas != cur.MinReplicas || rec.MaxReplicas != cur.MaxReplicas ||
+		rec.CPUTarget != cur.CPUTarget || rec.MemoryTarget != cur.MemoryTarget ||
+		(rec.CPURequest != "" && rec.CPURequest != cur.CPURequest) ||
+		(rec.CPULimit != "" && rec.CPULimit != cur.CPULimit) ||
+		(rec.MemoryRequest != "" && rec.MemoryRequest != cur.MemoryRequest) ||
+		(rec.MemoryLimit != "" && rec.MemoryLimit != cur.MemoryLimit)
+}
+
+// RecommendRightSizing calcula a recomendação de um HPA a partir dos snapshots do período
+// (ordenados por timestamp; aceita snapshots de rollup, usando o p95 do bucket para CPU/memória).
+//
+//   - Targets: folga proporcional à rajada (p99/p50) do uso, entre 50% e 85%
+//   - Requests: uso p95 (CPU) / p99 (memória) + RequestHeadroom; limits mantêm a proporção atual
+//     (memory limit nunca abaixo do pico observado + 10%)
+//   - Réplicas: demanda = réplicas × uso / target recomendado (no request recomendado);
+//     minReplicas = p05 da demanda, maxReplicas = p99 da demanda + ReplicaHeadroom
+//     (ou maxReplicas atual + ReplicaHeadroom se o HPA ficou saturado no máximo)
+func RecommendRightSizing(snapshots []models.HPASnapshot, config RightSizingConfig) (*RightSizing, error) {
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("no snapshots to analyze")
+	}
+
+	last := snapshots[len(snapshots)-1]
+	rec := &RightSizing{
+		Cluster:   last.Cluster,
+		Namespace: last.Namespace,
+		HPAName:   last.Name,
+		From:      snapshots[0].Timestamp,
+		To:        last.Timestamp,
+		Window:    config.Window,
+		Current: HPASizing{
+			MinReplicas:   last.MinReplicas,
+			MaxReplicas:   last.MaxReplicas,
+			CPUTarget:     last.CPUTarget,
+			MemoryTarget:  last.MemoryTarget,
+			CPURequest:    last.CPURequest,
+			CPULimit:      last.CPULimit,
+			MemoryRequest: last.MemoryRequest,
+			MemoryLimit:   last.MemoryLimit,
+		},
+	}
+	rec.Recommended = HPASizing{
+		MinReplicas:  last.MinReplicas,
+		MaxReplicas:  last.MaxReplicas,
+		CPUTarget:    last.CPUTarget,
+		MemoryTarget: last.MemoryTarget,
+	}
+
+	cpu := make([]float64, 0, len(snapshots))
+	memory := make([]float64, 0, len(snapshots))
+	replicas := make([]float64, 0, len(snapshots))
+	atMin, atMax := 0, 0
+	for i := range snapshots {
+		s := &snapshots[i]
+		rec.Samples += sampleCount(s)
+		cpu = append(cpu, bucketValue(s, "cpu", s.CPUCurrent))
+		memory = append(memory, bucketValue(s, "memory", s.MemoryCurrent))
+		replicas = append(replicas, float64(s.CurrentReplicas))
+		if s.CurrentReplicas <= s.MinReplicas {
+			atMin++
+		}
+		if s.MaxReplicas > 0 && s.CurrentReplicas >= s.MaxReplicas {
+			atMax++
+		}
+	}
+
+	usage := &rec.Usage
+	usage.CPUP50, usage.CPUP95, usage.CPUP99 = quantile(cpu, 50), quantile(cpu, 95), quantile(cpu, 99)
+	usage.MemoryP50, usage.MemoryP95, usage.MemoryP99 = quantile(memory, 50), quantile(memory, 95), quantile(memory, 99)
+	usage.MemoryMax = quantile(memory, 100)
+	usage.ReplicasP05, usage.ReplicasP50 = quantile(replicas, 5), quantile(replicas, 50)
+	usage.ReplicasP95, usage.ReplicasP99 = quantile(replicas, 95), quantile(replicas, 99)
+	usage.TimeAtMinPct = float64(atMin) / float64(len(snapshots)) * 100
+	usage.TimeAtMaxPct = float64(atMax) / float64(len(snapshots)) * 100
+
+	// Targets
+	if rec.Current.CPUTarget > 0 && usage.CPUP50 > 0 {
+		rec.Recommended.CPUTarget = burstTarget(usage.CPUP50, usage.CPUP99)
+		if rec.Recommended.CPUTarget != rec.Current.CPUTarget {
+			rec.Rationale = append(rec.Rationale, fmt.Sprintf(
+				"Target de CPU %d%% → %d%%: rajada p99/p50 de %.1fx (p50 %.0f%%, p99 %.0f%%)",
+				rec.Current.CPUTarget, rec.Recommended.CPUTarget, usage.CPUP99/usage.CPUP50, usage.CPUP50, usage.CPUP99))
+		}
+	}
+	if rec.Current.MemoryTarget > 0 && usage.MemoryP50 > 0 {
+		rec.Recommended.MemoryTarget = burstTarget(usage.MemoryP50, usage.MemoryP99)
+		if rec.Recommended.MemoryTarget != rec.Current.MemoryTarget {
+			rec.Rationale = append(rec.Rationale, fmt.Sprintf(
+				"Target de memória %d%% → %d%%: rajada p99/p50 de %.1fx",
+				rec.Current.MemoryTarget, rec.Recommended.MemoryTarget, usage.MemoryP99/usage.MemoryP50))
+		}
+	}
+
+	// Requests/limits (fator = request atual / recomendado, usado na demanda de réplicas)
+	cpuFactor := rec.recommendCPU(config)
+	memoryFactor := rec.recommendMemory(config)
+
+	// Réplicas pela demanda no target/request recomendados
+	demand := make([]float64, len(snapshots))
+	for i := range snapshots {
+		demand[i] = replicas[i]
+		if rec.Recommended.CPUTarget > 0 {
+			demand[i] = replicas[i] * cpu[i] * cpuFactor / float64(rec.Recommended.CPUTarget)
+		}
+		if rec.Recommended.MemoryTarget > 0 {
+			demand[i] = math.Max(demand[i], replicas[i]*memory[i]*memoryFactor/float64(rec.Recommended.MemoryTarget))
+		}
+	}
+	usage.DemandP05, usage.DemandP99 = quantile(demand, 5), quantile(demand, 99)
+	rec.recommendReplicas(config)
+
+	rec.Confidence, rec.Level = confidence(rec.To.Sub(rec.From), rec.Samples, config)
+	if rec.Level == ConfidenceLow {
+		rec.Rationale = append(rec.Rationale, fmt.Sprintf(
+			"Confiança baixa: %d amostras em %s (esperado %d em %s)",
+			rec.Samples, rec.To.Sub(rec.From).Round(time.Minute), config.MinSamples, config.Window))
+	}
+	if !rec.HasChanges() {
+		rec.Rationale = append(rec.Rationale, "Configuração atual adequada ao uso observado")
+	}
+	return rec, nil
+}
+
+// RecommendFromPersistence calcula recomendações para os HPAs com snapshots persistidos na janela
+// (cluster/namespace/hpa vazios = todos). Janelas além de MaxAge usam os rollups.
+func RecommendFromPersistence(persistence *storage.Persistence, cluster, namespace, hpa string, config RightSizingConfig) ([]*RightSizing, error) {
+	if persistence == nil {
+		return nil, fmt.Errorf("persistence not available")
+	}
+
+	since := time.Now().Add(-config.Window)
+	hpas, err := persistence.ListSnapshotHPAs(cluster, namespace, since)
+	if err != nil {
+		return nil, err
+	}
+
+	recs := make([]*RightSizing, 0, len(hpas))
+	for _, ref := range hpas {
+		if hpa != "" && ref.Name != hpa {
+			continue
+		}
+		snapshots, err := persistence.LoadSnapshots(ref.Cluster, ref.Namespace, ref.Name, since)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load snapshots of %s/%s/%s: %w", ref.Cluster, ref.Namespace, ref.Name, err)
+		}
+		if len(snapshots) == 0 {
+			continue
+		}
+		rec, err := RecommendRightSizing(snapshots, config)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, rec)
+	}
+	return recs, nil
+}
+
+func (r *RightSizing) recommendReplicas(config RightSizingConfig) {
+	usage := r.Usage
+	demandP05, demandP99 := usage.DemandP05, usage.DemandP99
+
+	// HA: HPAs com 2+ réplicas mínimas não descem para 1
+	floor := int32(1)
+	if r.Current.MinReplicas >= 2 {
+		floor = 2
+	}
+	minReplicas := int32(math.Ceil(demandP05 - 1e-9))
+	if minReplicas < floor {
+		minReplicas = floor
+	}
+
+	maxReplicas := int32(math.Ceil(demandP99 * (1 + config.ReplicaHeadroom)))
+	if usage.TimeAtMaxPct >= config.SaturationPercent {
+		r.Saturated = true
+		saturated := int32(math.Ceil(float64(r.Current.MaxReplicas) * (1 + config.ReplicaHeadroom)))
+		if saturated > maxReplicas {
+			maxReplicas = saturated
+		}
+		r.Rationale = append(r.Rationale, fmt.Sprintf(
+			"HPA em maxReplicas %.1f%% do tempo (limite %.0f%%): capacidade insuficiente nos picos",
+			usage.TimeAtMaxPct, config.SaturationPercent))
+	}
+	if maxReplicas <= minReplicas {
+		maxReplicas = minReplicas + 1
+	}
+
+	if minReplicas != r.Current.MinReplicas {
+		r.Rationale = append(r.Rationale, fmt.Sprintf(
+			"minReplicas %d → %d: demanda p05 de %.1f réplicas (%.0f%% do tempo no mínimo)",
+			r.Current.MinReplicas, minReplicas, demandP05, usage.TimeAtMinPct))
+	}
+	if maxReplicas != r.Current.MaxReplicas {
+		r.Rationale = append(r.Rationale, fmt.Sprintf(
+			"maxReplicas %d → %d: demanda p99 de %.1f réplicas + %.0f%% de folga",
+			r.Current.MaxReplicas, maxReplicas, demandP99, config.ReplicaHeadroom*100))
+	}
+	r.Recommended.MinReplicas = minReplicas
+	r.Recommended.MaxReplicas = maxReplicas
+}
+
+// recommendCPU recomenda request/limit de CPU; retorna request atual / recomendado (1 = sem alteração)
+func (r *RightSizing) recommendCPU(config RightSizingConfig) float64 {
+	request, err := resource.ParseQuantity(r.Current.CPURequest)
+	if err != nil || request.MilliValue() == 0 {
+		return 1
+	}
+
+	current := float64(request.MilliValue())
+	usage := r.Usage.CPUP95 / 100 * current
+	recommended := roundUp(usage*(1+config.RequestHeadroom), 10)
+	if recommended < 10 || !significant(current, recommended, config.MinChangePercent) {
+		return 1
+	}
+
+	r.Recommended.CPURequest = fmt.Sprintf("%dm", int64(recommended))
+	if limit, err := resource.ParseQuantity(r.Current.CPULimit); err == nil && limit.MilliValue() > 0 {
+		r.Recommended.CPULimit = fmt.Sprintf("%dm", int64(roundUp(recommended*float64(limit.MilliValue())/current, 10)))
+	}
+	r.Rationale = append(r.Rationale, fmt.Sprintf(
+		"CPU request %s → %s: uso p95 de %.0fm (%.0f%% do request) + %.0f%% de folga",
+		r.Current.CPURequest, r.Recommended.CPURequest, usage, r.Usage.CPUP95, config.RequestHeadroom*100))
+	return current / recommended
+}
+
+// recommendMemory recomenda request/limit de memória; retorna request atual / recomendado (1 = sem alteração)
+func (r *RightSizing) recommendMemory(config RightSizingConfig) float64 {
+	request, err := resource.ParseQuantity(r.Current.MemoryRequest)
+	if err != nil || request.Value() == 0 {
+		return 1
+	}
+
+	const mi = 1024 * 1024
+	current := float64(request.Value()) / mi
+	usage := r.Usage.MemoryP99 / 100 * current
+	recommended := roundUp(usage*(1+config.RequestHeadroom), 16)
+	if recommended < 16 || !significant(current, recommended, config.MinChangePercent) {
+		return 1
+	}
+
+	r.Recommended.MemoryRequest = fmt.Sprintf("%dMi", int64(recommended))
+	if limit, err := resource.ParseQuantity(r.Current.MemoryLimit); err == nil && limit.Value() > 0 {
+		// Proporção atual, nunca abaixo do pico observado + 10% (evita OOMKilled)
+		newLimit := recommended * float64(limit.Value()) / mi / current
+		peak := r.Usage.MemoryMax / 100 * current * 1.1
+		r.Recommended.MemoryLimit = fmt.Sprintf("%dMi", int64(roundUp(math.Max(newLimit, math.Max(peak, recommended)), 16)))
+	}
+	r.Rationale = append(r.Rationale, fmt.Sprintf(
+		"Memory request %s → %s: uso p99 de %.0fMi (%.0f%% do request) + %.0f%% de folga",
+		r.Current.MemoryRequest, r.Recommended.MemoryRequest, usage, r.Usage.MemoryP99, config.RequestHeadroom*100))
+	return current / recommended
+}
+
+// burstTarget target de utilização com folga para a rajada p99/p50 enquanto o HPA escala
+func burstTarget(p50, p99 float64) int32 {
+	ratio := math.Max(1, p99/p50)
+	target := math.Round(90/ratio/5) * 5
+	return int32(math.Min(85, math.Max(50, target)))
+}
+
+// confidence combina cobertura do período e quantidade de amostras
+func confidence(span time.Duration, samples int, config RightSizingConfig) (float64, string) {
+	coverage := 1.0
+	if config.Window > 0 {
+		coverage = math.Min(1, span.Seconds()/config.Window.Seconds())
+	}
+	density := 1.0
+	if config.MinSamples > 0 {
+		density = math.Min(1, float64(samples)/float64(config.MinSamples))
+	}
+
+	value := math.Round(coverage*density*100) / 100
+	switch {
+	case value >= 0.75:
+		return value, ConfidenceHigh
+	case value >= 0.4:
+		return value, ConfidenceMedium
+	default:
+		return value, ConfidenceLow
+	}
+}
+
+// bucketValue usa o p95 do bucket para snapshots de rollup (conservador) e o valor bruto nos demais
+func bucketValue(s *models.HPASnapshot, metric string, raw float64) float64 {
+	if v, ok := s.AdditionalMetrics[metric+"_p95"].(float64); ok && isRollup(s) {
+		return v
+	}
+	return raw
+}
+
+// sampleCount amostras representadas pelo snapshot (rollups agregam várias)
+func sampleCount(s *models.HPASnapshot) int {
+	switch v := s.AdditionalMetrics["rollup_samples"].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 1
+}
+
+func isRollup(s *models.HPASnapshot) bool {
+	_, ok := s.AdditionalMetrics["rollup_samples"]
+	return ok
+}
+
+// quantile nearest-rank (p em 0-100) sem alterar values
+func quantile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func roundUp(value, step float64) float64 {
+	return math.Ceil(value/step) * step
+}
+
+// significant indica se recommended difere de current em pelo menos minPercent
+func significant(current, recommended, minPercent float64) bool {
+	return math.Abs(recommended-current)/current*100 >= minPercent
+}
+
+// Recommendations converte em recomendações no formato dos relatórios de stress test
+func (r *RightSizing) Recommendations() []models.Recommendation {
+	if !r.HasChanges() {
+		return nil
+	}
+
+	priority := models.PriorityMedium
+	switch {
+	case r.Saturated:
+		priority = models.PriorityHigh
+	case r.Level == ConfidenceLow:
+		priority = models.PriorityLow
+	}
+
+	category := models.CategoryScaling
+	if r.Recommended.CPURequest != "" || r.Recommended.MemoryRequest != "" {
+		category = models.CategoryResources
+	}
+
+	rec, cur := r.Recommended, r.Current
+	return []models.Recommendation{{
+		Priority: priority,
+		Category: category,
+		Target:   r.Key(),
+		Title:    "Right-sizing do HPA",
+		Description: fmt.Sprintf("Réplicas %d-%d → %d-%d, targets CPU %d%% → %d%%, memória %d%% → %d%%",
+			cur.MinReplicas, cur.MaxReplicas, rec.MinReplicas, rec.MaxReplicas,
+			cur.CPUTarget, rec.CPUTarget, cur.MemoryTarget, rec.MemoryTarget),
+		Action:    "Aplicar a sessão de right-sizing gerada",
+		Rationale: strings.Join(r.Rationale, "; "),
+		Impact:    fmt.Sprintf("Confiança %s (%.0f%%, %d amostras)", r.Level, r.Confidence*100, r.Samples),
+	}}
+}
@@ -0,0 +1,109 @@
+package analyzer
+
+import (
+	"fmt"
+	"time"
+
+	appmodels "k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/session"
+)
+
+// RightSizingTemplate TemplateUsed das sessões geradas por BuildRightSizingSession
+const RightSizingTemplate = "rightsizing"
+
+// RightSizingSessionOptions controla a geração da sessão de right-sizing
+type RightSizingSessionOptions struct {
+	// Name nome da sessão (vazio = RightSizing_<cluster>_<timestamp>)
+	Name string
+	// ClusterContext converte o cluster dos snapshots (sem -admin) no contexto do kubeconfig
+	// usado pela sessão (nil = cluster dos snapshots)
+	ClusterContext func(cluster string) string
+	// Now horário de criação (zero = time.Now())
+	Now time.Time
+}
+
+// BuildRightSizingSession gera uma sessão pronta para aplicar com as recomendações que têm mudanças.
+// A pasta é HPA-Upscale quando a capacidade total (min+max réplicas) aumenta, senão HPA-Downscale.
+func BuildRightSizingSession(recs []*RightSizing, opts RightSizingSessionOptions) (*appmodels.Session, session.SessionFolder, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	clusterContext := opts.ClusterContext
+	if clusterContext == nil {
+		clusterContext = func(cluster string) string { return cluster }
+	}
+
+	sess := &appmodels.Session{
+		CreatedAt:    now,
+		TemplateUsed: RightSizingTemplate,
+		RollbackData: &appmodels.RollbackData{OriginalStateCaptured: true, CanRollback: true},
+	}
+
+	var clusters []string
+	seen := make(map[string]bool)
+	capacityDelta := int32(0)
+	for _, rec := range recs {
+		if rec == nil || !rec.HasChanges() {
+			continue
+		}
+		if !seen[rec.Cluster] {
+			seen[rec.Cluster] = true
+			clusters = append(clusters, rec.Cluster)
+		}
+
+		cur, target := rec.Current, rec.Recommended
+		capacityDelta += (target.MinReplicas - cur.MinReplicas) + (target.MaxReplicas - cur.MaxReplicas)
+		sess.Changes = append(sess.Changes, appmodels.HPAChange{
+			Cluster:        clusterContext(rec.Cluster),
+			Namespace:      rec.Namespace,
+			HPAName:        rec.HPAName,
+			OriginalValues: sizingValues(cur),
+			NewValues:      sizingValues(target),
+		})
+	}
+	if len(sess.Changes) == 0 {
+		return nil, "", fmt.Errorf("no right-sizing changes to apply")
+	}
+
+	sess.Name = opts.Name
+	if sess.Name == "" {
+		base := "multi"
+		if len(clusters) == 1 {
+			base = clusters[0]
+		}
+		sess.Name = session.DerivedSessionName("RightSizing_", base, now)
+	}
+	sess.Description = fmt.Sprintf("Right-sizing de %d HPAs a partir do histórico de snapshots", len(sess.Changes))
+	sess.Metadata = session.GenerateMetadata(sess)
+
+	folder := session.FolderHPADownscale
+	if capacityDelta > 0 {
+		folder = session.FolderHPAUpscale
+	}
+	return sess, folder, nil
+}
+
+// sizingValues converte em HPAValues; targets zerados e requests/limits vazios ficam ausentes
+// (a alteração de resources no deployment já dispara o rollout dos pods)
+func sizingValues(sizing HPASizing) *appmodels.HPAValues {
+	minReplicas := sizing.MinReplicas
+	values := &appmodels.HPAValues{
+		MinReplicas: &minReplicas,
+		MaxReplicas: sizing.MaxReplicas,
+	}
+	if sizing.CPUTarget > 0 {
+		target := sizing.CPUTarget
+		values.TargetCPU = &target
+	}
+	if sizing.MemoryTarget > 0 {
+		target := sizing.MemoryTarget
+		values.TargetMemory = &target
+	}
+
+	values.CPURequest = sizing.CPURequest
+	values.CPULimit = sizing.CPULimit
+	values.MemoryRequest = sizing.MemoryRequest
+	values.MemoryLimit = sizing.MemoryLimit
+	return values
+}
@@ -0,0 +1,167 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"k8s-hpa-manager/internal/monitoring/models"
+	"k8s-hpa-manager/internal/session"
+)
+
+// sizingSnapshots gera um snapshot por minuto durante days com cpu/replicas definidos por minuto
+func sizingSnapshots(days int, fill func(i int, s *models.HPASnapshot)) []models.HPASnapshot {
+	start := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+	count := days * 24 * 60
+	snapshots := make([]models.HPASnapshot, count)
+	for i := range snapshots {
+		snapshots[i] = models.HPASnapshot{
+			Timestamp:     start.Add(time.Duration(i) * time.Minute),
+			Cluster:       "aks-prd",
+			Namespace:     "api",
+			Name:          "web",
+			MinReplicas:   4,
+			MaxReplicas:   10,
+			CPUTarget:     70,
+			CPURequest:    "1000m",
+			CPULimit:      "2000m",
+			MemoryRequest: "1Gi",
+			MemoryLimit:   "2Gi",
+		}
+		fill(i, &snapshots[i])
+	}
+	return snapshots
+}
+
+func TestRecommendRightSizingOverProvisioned(t *testing.T) {
+	// Sempre no mínimo com CPU ~20% e memória ~30% do request
+	snapshots := sizingSnapshots(7, func(i int, s *models.HPASnapshot) {
+		s.CurrentReplicas = 4
+		s.CPUCurrent = 18 + float64(i%5)
+		s.MemoryCurrent = 30
+	})
+
+	rec, err := RecommendRightSizing(snapshots, DefaultRightSizingConfig())
+	if err != nil {
+		t.Fatalf("RecommendRightSizing failed: %v", err)
+	}
+
+	if rec.Level != ConfidenceHigh || rec.Saturated {
+		t.Errorf("unexpected confidence/saturation: %s %v", rec.Level, rec.Saturated)
+	}
+	if rec.Usage.TimeAtMinPct != 100 || rec.Usage.CPUP95 != 22 {
+		t.Errorf("unexpected usage: %+v", rec.Usage)
+	}
+	// 22% de 1000m + 15% = 253m → 260m; limit mantém proporção 2x
+	if rec.Recommended.CPURequest != "260m" || rec.Recommended.CPULimit != "520m" {
+		t.Errorf("unexpected cpu resources: %s/%s", rec.Recommended.CPURequest, rec.Recommended.CPULimit)
+	}
+	// 30% de 1Gi = 307Mi + 15% → 368Mi; limit 2x
+	if rec.Recommended.MemoryRequest != "368Mi" || rec.Recommended.MemoryLimit != "736Mi" {
+		t.Errorf("unexpected memory resources: %s/%s", rec.Recommended.MemoryRequest, rec.Recommended.MemoryLimit)
+	}
+	// Pods menores no target de 80%: a demanda continua em 4 réplicas
+	if rec.Recommended.CPUTarget != 80 || rec.Recommended.MinReplicas != 4 {
+		t.Errorf("unexpected target/minReplicas: %d/%d", rec.Recommended.CPUTarget, rec.Recommended.MinReplicas)
+	}
+	if !rec.HasChanges() || len(rec.Rationale) == 0 {
+		t.Error("expected changes with rationale")
+	}
+
+	// Sem requests conhecidos, a sobra vira redução de réplicas (mínimo de 2 para HA)
+	for i := range snapshots {
+		snapshots[i].CPURequest, snapshots[i].MemoryRequest = "", ""
+	}
+	replicasOnly, err := RecommendRightSizing(snapshots, DefaultRightSizingConfig())
+	if err != nil || replicasOnly.Recommended.MinReplicas != 2 {
+		t.Errorf("expected minReplicas 2 without requests, got %+v (err=%v)", replicasOnly.Recommended, err)
+	}
+
+	sess, folder, err := BuildRightSizingSession([]*RightSizing{rec}, RightSizingSessionOptions{
+		ClusterContext: func(cluster string) string { return cluster + "-admin" },
+	})
+	if err != nil {
+		t.Fatalf("BuildRightSizingSession failed: %v", err)
+	}
+	if folder != session.FolderHPADownscale {
+		t.Errorf("expected HPA-Downscale folder, got %s", folder)
+	}
+	change := sess.Changes[0]
+	if change.Cluster != "aks-prd-admin" || *change.OriginalValues.MinReplicas != 4 || change.OriginalValues.CPURequest != "1000m" {
+		t.Errorf("unexpected change: %+v", change)
+	}
+	if *change.NewValues.MinReplicas != rec.Recommended.MinReplicas || change.NewValues.CPURequest != "260m" {
+		t.Errorf("unexpected new values: %+v", change.NewValues)
+	}
+	if sess.Metadata.HPACount != 1 || sess.TemplateUsed != RightSizingTemplate {
+		t.Errorf("unexpected session metadata: %+v", sess.Metadata)
+	}
+}
+
+func TestRecommendRightSizingSaturated(t *testing.T) {
+	// Metade do tempo em maxReplicas com CPU acima do target
+	snapshots := sizingSnapshots(7, func(i int, s *models.HPASnapshot) {
+		s.CPURequest, s.CPULimit, s.MemoryRequest, s.MemoryLimit = "", "", "", ""
+		if (i/60)%2 == 0 {
+			s.CurrentReplicas, s.CPUCurrent = 10, 110
+		} else {
+			s.CurrentReplicas, s.CPUCurrent = 6, 65
+		}
+	})
+
+	rec, err := RecommendRightSizing(snapshots, DefaultRightSizingConfig())
+	if err != nil {
+		t.Fatalf("RecommendRightSizing failed: %v", err)
+	}
+	if !rec.Saturated || rec.Usage.TimeAtMaxPct != 50 {
+		t.Fatalf("expected saturated HPA, got %+v", rec.Usage)
+	}
+	if rec.Recommended.MaxReplicas < 13 {
+		t.Errorf("expected maxReplicas >= 13, got %d", rec.Recommended.MaxReplicas)
+	}
+	if rec.Recommended.CPURequest != "" || rec.Recommended.MemoryRequest != "" {
+		t.Error("resources should not be recommended without current requests")
+	}
+
+	recs := rec.Recommendations()
+	if len(recs) != 1 || recs[0].Priority != models.PriorityHigh || recs[0].Target != "aks-prd/api/web" {
+		t.Errorf("unexpected recommendations: %+v", recs)
+	}
+
+	_, folder, err := BuildRightSizingSession([]*RightSizing{rec}, RightSizingSessionOptions{})
+	if err != nil || folder != session.FolderHPAUpscale {
+		t.Errorf("expected HPA-Upscale folder, got %s (err=%v)", folder, err)
+	}
+}
+
+func TestRecommendRightSizingConfidence(t *testing.T) {
+	snapshots := sizingSnapshots(1, func(i int, s *models.HPASnapshot) {
+		s.CurrentReplicas, s.CPUCurrent = 4, 60
+	})[:100]
+
+	rec, err := RecommendRightSizing(snapshots, DefaultRightSizingConfig())
+	if err != nil {
+		t.Fatalf("RecommendRightSizing failed: %v", err)
+	}
+	if rec.Level != ConfidenceLow || rec.Confidence >= 0.4 {
+		t.Errorf("expected low confidence, got %s (%.2f)", rec.Level, rec.Confidence)
+	}
+
+	if _, _, err := BuildRightSizingSession(nil, RightSizingSessionOptions{}); err == nil {
+		t.Error("expected error for session without changes")
+	}
+}
+
+func TestBurstTarget(t *testing.T) {
+	for _, tc := range []struct {
+		p50, p99 float64
+		want     int32
+	}{
+		{50, 52, 85}, // carga estável
+		{40, 60, 60}, // rajada 1.5x
+		{20, 80, 50}, // rajada 4x (limite inferior)
+	} {
+		if got := burstTarget(tc.p50, tc.p99); got != tc.want {
+			t.Errorf("burstTarget(%v, %v) = %d, want %d", tc.p50, tc.p99, got, tc.want)
+		}
+	}
+}
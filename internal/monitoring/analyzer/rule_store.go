@@ -0,0 +1,194 @@
+package analyzer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RuleStore mantém o RuleSet carregado de um arquivo YAML, recarregando quando o arquivo muda.
+// Também guarda o estado de cooldown das regras customizadas (sobrevive a recargas).
+type RuleStore struct {
+	path string
+
+	mu       sync.RWMutex
+	set      *RuleSet
+	raw      []byte
+	modTime  time.Time
+	loadedAt time.Time
+	lastErr  error
+
+	cooldownMu sync.Mutex
+	lastFired  map[string]time.Time // regra/cluster/namespace/hpa -> último alerta
+}
+
+// DefaultRulesPath retorna ~/.k8s-hpa-manager/anomaly-rules.yaml
+func DefaultRulesPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	return filepath.Join(home, ".k8s-hpa-manager", "anomaly-rules.yaml")
+}
+
+// NewRuleStore cria o store e carrega o arquivo (inexistente = sem regras).
+// Erros de validação são registrados em LastError e o store segue sem regras.
+func NewRuleStore(path string) *RuleStore {
+	store := &RuleStore{
+		path:      path,
+		set:       &RuleSet{},
+		lastFired: make(map[string]time.Time),
+	}
+	if _, err := store.Reload(); err != nil {
+		log.Warn().Err(err).Str("path", path).Msg("Falha ao carregar regras de anomalias, usando thresholds padrão")
+	}
+	return store
+}
+
+// Path caminho do arquivo de regras
+func (s *RuleStore) Path() string {
+	return s.path
+}
+
+// RuleSet regras atualmente ativas
+func (s *RuleStore) RuleSet() *RuleSet {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.set
+}
+
+// Raw conteúdo YAML das regras ativas
+func (s *RuleStore) Raw() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return string(s.raw)
+}
+
+// LoadedAt momento da última carga bem-sucedida
+func (s *RuleStore) LoadedAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.loadedAt
+}
+
+// LastError erro da última tentativa de carga (nil = regras ativas correspondem ao arquivo)
+func (s *RuleStore) LastError() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastErr
+}
+
+// Reload relê o arquivo se ele mudou desde a última carga. Em caso de erro as regras
+// anteriores continuam ativas. Retorna true quando um novo RuleSet foi aplicado.
+func (s *RuleStore) Reload() (bool, error) {
+	info, err := os.Stat(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s.apply(nil, time.Time{})
+	}
+	if err != nil {
+		return false, s.fail(fmt.Errorf("failed to stat rules file: %w", err))
+	}
+
+	s.mu.RLock()
+	unchanged := info.ModTime().Equal(s.modTime) && !s.loadedAt.IsZero()
+	s.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return false, s.fail(fmt.Errorf("failed to read rules file: %w", err))
+	}
+	return s.apply(data, info.ModTime())
+}
+
+// Save valida e grava o YAML, ativando as novas regras imediatamente
+func (s *RuleStore) Save(data []byte) (*RuleSet, error) {
+	set, err := ParseRules(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create rules directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write rules file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return nil, fmt.Errorf("failed to write rules file: %w", err)
+	}
+
+	var modTime time.Time
+	if info, err := os.Stat(s.path); err == nil {
+		modTime = info.ModTime()
+	}
+
+	s.mu.Lock()
+	s.set = set
+	s.raw = data
+	s.modTime = modTime
+	s.loadedAt = time.Now()
+	s.lastErr = nil
+	s.mu.Unlock()
+
+	return set, nil
+}
+
+func (s *RuleStore) apply(data []byte, modTime time.Time) (bool, error) {
+	s.mu.RLock()
+	same := bytes.Equal(data, s.raw) && !s.loadedAt.IsZero()
+	s.mu.RUnlock()
+	if same {
+		s.mu.Lock()
+		s.modTime = modTime
+		s.lastErr = nil
+		s.mu.Unlock()
+		return false, nil
+	}
+
+	set, err := ParseRules(data)
+	if err != nil {
+		s.mu.Lock()
+		s.modTime = modTime // não tenta de novo até o arquivo mudar
+		s.lastErr = err
+		s.mu.Unlock()
+		return false, err
+	}
+
+	s.mu.Lock()
+	s.set = set
+	s.raw = data
+	s.modTime = modTime
+	s.loadedAt = time.Now()
+	s.lastErr = nil
+	s.mu.Unlock()
+	return true, nil
+}
+
+func (s *RuleStore) fail(err error) error {
+	s.mu.Lock()
+	s.lastErr = err
+	s.mu.Unlock()
+	return err
+}
+
+// allowFire aplica o cooldown de uma regra para um HPA; registra o disparo quando permitido
+func (s *RuleStore) allowFire(key string, cooldown time.Duration, now time.Time) bool {
+	s.cooldownMu.Lock()
+	defer s.cooldownMu.Unlock()
+
+	if last, ok := s.lastFired[key]; ok && now.Sub(last) < cooldown {
+		return false
+	}
+	s.lastFired[key] = now
+	return true
+}
@@ -0,0 +1,593 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s-hpa-manager/internal/monitoring/models"
+	"sigs.k8s.io/yaml"
+)
+
+// AnomalyTypeCustomRule anomalia gerada por regra definida pelo usuário
+const AnomalyTypeCustomRule AnomalyType = "CUSTOM_RULE"
+
+// RuleFile formato do arquivo de regras (YAML)
+//
+//	defaults:                  # sobrescreve DefaultDetectorConfig() para todos os HPAs
+//	  error_rate_threshold: 3
+//	overrides:                 # aplicados na ordem do arquivo (o último que casar prevalece)
+//	  - match: {cluster: "*-prd*", namespace: "payments"}
+//	    thresholds:
+//	      cpu_spike_threshold: 80
+//	      disabled: [CPU_DROP]
+//	rules:                     # regras customizadas sobre campos do HPASnapshot
+//	  - name: memoria-alta
+//	    match: {cluster: "*-prd*"}
+//	    condition: "memory_current > 90 && replicas_current >= replicas_max"
+//	    min_duration: 2m
+//	    severity: critical
+//	    cooldown: 10m
+type RuleFile struct {
+	Defaults  *Thresholds  `json:"defaults,omitempty"`
+	Overrides []Override   `json:"overrides,omitempty"`
+	Rules     []CustomRule `json:"rules,omitempty"`
+}
+
+// Match seleciona HPAs por cluster/namespace/nome (glob; vazio = qualquer)
+type Match struct {
+	Cluster   string `json:"cluster,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	HPA       string `json:"hpa,omitempty"`
+}
+
+// Override sobrescreve thresholds para os HPAs selecionados
+type Override struct {
+	Match      Match      `json:"match"`
+	Thresholds Thresholds `json:"thresholds"`
+}
+
+// Thresholds campos opcionais de DetectorConfig (nil = mantém o valor herdado)
+type Thresholds struct {
+	OscillationMaxChanges     *int          `json:"oscillation_max_changes,omitempty"`
+	OscillationWindow         *Duration     `json:"oscillation_window,omitempty"`
+	MaxedOutCPUDeviation      *float64      `json:"maxed_out_cpu_deviation,omitempty"`
+	MaxedOutMinDuration       *Duration     `json:"maxed_out_min_duration,omitempty"`
+	ErrorRateThreshold        *float64      `json:"error_rate_threshold,omitempty"`
+	ErrorRateMinDuration      *Duration     `json:"error_rate_min_duration,omitempty"`
+	NotReadyThreshold         *float64      `json:"not_ready_threshold,omitempty"`
+	NotReadyMinDuration       *Duration     `json:"not_ready_min_duration,omitempty"`
+	OOMKilledWindow           *Duration     `json:"oom_killed_window,omitempty"`
+	CrashLoopRestartThreshold *int32        `json:"crash_loop_restart_threshold,omitempty"`
+	CrashLoopWindow           *Duration     `json:"crash_loop_window,omitempty"`
+	CPUSpikeThreshold         *float64      `json:"cpu_spike_threshold,omitempty"`
+	ReplicaSpikeThreshold     *int32        `json:"replica_spike_threshold,omitempty"`
+	ErrorSpikeThreshold       *float64      `json:"error_spike_threshold,omitempty"`
+	LatencySpikeThreshold     *float64      `json:"latency_spike_threshold,omitempty"`
+	CPUDropThreshold          *float64      `json:"cpu_drop_threshold,omitempty"`
+	AlertCooldown             *Duration     `json:"alert_cooldown,omitempty"`
+	Disabled                  []AnomalyType `json:"disabled,omitempty"` // tipos de anomalia desligados
+}
+
+// CustomRule regra definida pelo usuário
+type CustomRule struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Match       Match    `json:"match,omitempty"`
+	Condition   string   `json:"condition"`
+	MinDuration Duration `json:"min_duration,omitempty"` // condição precisa persistir (0 = último snapshot)
+	Severity    string   `json:"severity,omitempty"`     // info, warning, critical (default: warning)
+	Cooldown    Duration `json:"cooldown,omitempty"`     // 0 = AlertCooldown do detector
+	Message     string   `json:"message,omitempty"`
+	Actions     []string `json:"actions,omitempty"`
+}
+
+// Duration time.Duration serializado como "2m", "30s" (aceita também número em segundos)
+type Duration time.Duration
+
+// UnmarshalJSON implementa json.Unmarshaler
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case float64:
+		*d = Duration(time.Duration(v * float64(time.Second)))
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", v, err)
+		}
+		*d = Duration(parsed)
+	default:
+		return fmt.Errorf("invalid duration: %s", string(data))
+	}
+	return nil
+}
+
+// MarshalJSON implementa json.Marshaler
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// RuleSet regras validadas e prontas para avaliação
+type RuleSet struct {
+	File  RuleFile
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	CustomRule
+	severity  models.AlertSeverity
+	condition *Condition
+}
+
+// ParseRules valida o YAML de regras (conteúdo vazio = nenhuma regra)
+func ParseRules(data []byte) (*RuleSet, error) {
+	set := &RuleSet{}
+	if err := yaml.UnmarshalStrict(data, &set.File); err != nil {
+		return nil, fmt.Errorf("invalid rules file: %w", err)
+	}
+
+	for i, override := range set.File.Overrides {
+		if err := override.Match.validate(); err != nil {
+			return nil, fmt.Errorf("override %d: %w", i+1, err)
+		}
+	}
+
+	names := make(map[string]bool)
+	for _, rule := range set.File.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rule without name")
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("duplicate rule name %q", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if err := rule.Match.validate(); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+		condition, err := ParseCondition(rule.Condition)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+		severity, err := parseSeverity(rule.Severity)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+		set.rules = append(set.rules, compiledRule{CustomRule: rule, severity: severity, condition: condition})
+	}
+
+	return set, nil
+}
+
+// ConfigFor retorna a configuração efetiva de um HPA: base + defaults + overrides que casam
+func (rs *RuleSet) ConfigFor(base *DetectorConfig, cluster, namespace, hpaName string) *DetectorConfig {
+	config := *base
+	config.Disabled = append([]AnomalyType(nil), base.Disabled...)
+	if rs == nil {
+		return &config
+	}
+
+	if rs.File.Defaults != nil {
+		rs.File.Defaults.apply(&config)
+	}
+	for _, override := range rs.File.Overrides {
+		if override.Match.matches(cluster, namespace, hpaName) {
+			override.Thresholds.apply(&config)
+		}
+	}
+	return &config
+}
+
+// apply copia os thresholds definidos para config
+func (t *Thresholds) apply(config *DetectorConfig) {
+	setInt := func(dst *int, src *int) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	setInt32 := func(dst *int32, src *int32) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	setFloat := func(dst *float64, src *float64) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	setDuration := func(dst *time.Duration, src *Duration) {
+		if src != nil {
+			*dst = time.Duration(*src)
+		}
+	}
+
+	setInt(&config.OscillationMaxChanges, t.OscillationMaxChanges)
+	setDuration(&config.OscillationWindow, t.OscillationWindow)
+	setFloat(&config.MaxedOutCPUDeviation, t.MaxedOutCPUDeviation)
+	setDuration(&config.MaxedOutMinDuration, t.MaxedOutMinDuration)
+	setFloat(&config.ErrorRateThreshold, t.ErrorRateThreshold)
+	setDuration(&config.ErrorRateMinDuration, t.ErrorRateMinDuration)
+	setFloat(&config.NotReadyThreshold, t.NotReadyThreshold)
+	setDuration(&config.NotReadyMinDuration, t.NotReadyMinDuration)
+	setDuration(&config.OOMKilledWindow, t.OOMKilledWindow)
+	setInt32(&config.CrashLoopRestartThreshold, t.CrashLoopRestartThreshold)
+	setDuration(&config.CrashLoopWindow, t.CrashLoopWindow)
+	setFloat(&config.CPUSpikeThreshold, t.CPUSpikeThreshold)
+	setInt32(&config.ReplicaSpikeThreshold, t.ReplicaSpikeThreshold)
+	setFloat(&config.ErrorSpikeThreshold, t.ErrorSpikeThreshold)
+	setFloat(&config.LatencySpikeThreshold, t.LatencySpikeThreshold)
+	setFloat(&config.CPUDropThreshold, t.CPUDropThreshold)
+	setDuration(&config.AlertCooldown, t.AlertCooldown)
+
+	if t.Disabled != nil {
+		config.Disabled = append([]AnomalyType(nil), t.Disabled...)
+	}
+}
+
+func (m Match) validate() error {
+	for _, pattern := range []string{m.Cluster, m.Namespace, m.HPA} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid match pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// matches compara com glob; cluster ignora o sufixo -admin dos contextos
+func (m Match) matches(cluster, namespace, hpaName string) bool {
+	return matchGlob(m.Cluster, strings.TrimSuffix(cluster, "-admin")) &&
+		matchGlob(m.Namespace, namespace) &&
+		matchGlob(m.HPA, hpaName)
+}
+
+func matchGlob(pattern, value string) bool {
+	if pattern == "" || pattern == "*" {
+		return true
+	}
+	ok, _ := path.Match(strings.TrimSuffix(pattern, "-admin"), value)
+	return ok
+}
+
+func parseSeverity(value string) (models.AlertSeverity, error) {
+	switch strings.ToLower(value) {
+	case "", "warning", "warn":
+		return models.SeverityWarning, nil
+	case "critical":
+		return models.SeverityCritical, nil
+	case "info":
+		return models.SeverityInfo, nil
+	default:
+		return 0, fmt.Errorf("invalid severity %q (use info, warning or critical)", value)
+	}
+}
+
+// Condition expressão de comparações sobre campos do snapshot.
+// Formato: "<campo> <op> <valor|campo>" unidos por && / and e || / or (&& tem precedência).
+// Campos de AdditionalMetrics são acessados com o prefixo "metrics." (ex: metrics.cpu_throttling).
+type Condition struct {
+	expr string
+	any  [][]comparison // OR de ANDs
+}
+
+type comparison struct {
+	field      string
+	op         string
+	value      float64
+	valueField string // comparação entre campos (ex: replicas_current >= replicas_max)
+}
+
+var (
+	comparisonPattern = regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_.]*)\s*(>=|<=|==|!=|>|<)\s*(\S+)$`)
+	orPattern         = regexp.MustCompile(`(?i)\s+or\s+`)
+	andPattern        = regexp.MustCompile(`(?i)\s+and\s+`)
+)
+
+// ParseCondition valida e compila uma condição
+func ParseCondition(expr string) (*Condition, error) {
+	normalized := strings.TrimSpace(expr)
+	if normalized == "" {
+		return nil, fmt.Errorf("empty condition")
+	}
+	normalized = orPattern.ReplaceAllString(normalized, " || ")
+	normalized = andPattern.ReplaceAllString(normalized, " && ")
+
+	condition := &Condition{expr: expr}
+	for _, group := range strings.Split(normalized, "||") {
+		var all []comparison
+		for _, term := range strings.Split(group, "&&") {
+			parts := comparisonPattern.FindStringSubmatch(strings.TrimSpace(term))
+			if parts == nil {
+				return nil, fmt.Errorf("invalid condition term %q (expected <field> <op> <value>)", strings.TrimSpace(term))
+			}
+			if !isRuleField(parts[1]) {
+				return nil, fmt.Errorf("unknown field %q", parts[1])
+			}
+			cmp := comparison{field: parts[1], op: parts[2]}
+			if isRuleField(parts[3]) {
+				cmp.valueField = parts[3]
+			} else {
+				value, err := parseConditionValue(parts[3])
+				if err != nil {
+					return nil, err
+				}
+				cmp.value = value
+			}
+			all = append(all, cmp)
+		}
+		condition.any = append(condition.any, all)
+	}
+	return condition, nil
+}
+
+func parseConditionValue(raw string) (float64, error) {
+	switch strings.ToLower(raw) {
+	case "true":
+		return 1, nil
+	case "false":
+		return 0, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q (expected number, true/false or field)", raw)
+	}
+	return value, nil
+}
+
+// Eval avalia a condição; campos ausentes (ex: métrica não coletada) tornam a comparação falsa
+func (c *Condition) Eval(snapshot *models.HPASnapshot) bool {
+	for _, all := range c.any {
+		matched := true
+		for _, cmp := range all {
+			if !cmp.eval(snapshot) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Values retorna os valores atuais dos campos usados na condição (para descrição do alerta)
+func (c *Condition) Values(snapshot *models.HPASnapshot) string {
+	seen := make(map[string]bool)
+	var parts []string
+	for _, all := range c.any {
+		for _, cmp := range all {
+			for _, field := range []string{cmp.field, cmp.valueField} {
+				if field == "" || seen[field] {
+					continue
+				}
+				seen[field] = true
+				if value, ok := fieldValue(snapshot, field); ok {
+					parts = append(parts, fmt.Sprintf("%s=%.2f", field, value))
+				} else {
+					parts = append(parts, field+"=n/a")
+				}
+			}
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// String retorna a expressão original
+func (c *Condition) String() string {
+	return c.expr
+}
+
+func (cmp comparison) eval(snapshot *models.HPASnapshot) bool {
+	value, ok := fieldValue(snapshot, cmp.field)
+	if !ok {
+		return false
+	}
+	expected := cmp.value
+	if cmp.valueField != "" {
+		if expected, ok = fieldValue(snapshot, cmp.valueField); !ok {
+			return false
+		}
+	}
+
+	switch cmp.op {
+	case ">":
+		return value > expected
+	case ">=":
+		return value >= expected
+	case "<":
+		return value < expected
+	case "<=":
+		return value <= expected
+	case "==":
+		return value == expected
+	case "!=":
+		return value != expected
+	}
+	return false
+}
+
+// snapshotFields campos do HPASnapshot disponíveis em condições
+var snapshotFields = map[string]func(s *models.HPASnapshot) float64{
+	"replicas_min":       func(s *models.HPASnapshot) float64 { return float64(s.MinReplicas) },
+	"replicas_max":       func(s *models.HPASnapshot) float64 { return float64(s.MaxReplicas) },
+	"replicas_current":   func(s *models.HPASnapshot) float64 { return float64(s.CurrentReplicas) },
+	"replicas_desired":   func(s *models.HPASnapshot) float64 { return float64(s.DesiredReplicas) },
+	"cpu_target":         func(s *models.HPASnapshot) float64 { return float64(s.CPUTarget) },
+	"memory_target":      func(s *models.HPASnapshot) float64 { return float64(s.MemoryTarget) },
+	"cpu_current":        func(s *models.HPASnapshot) float64 { return s.CPUCurrent },
+	"memory_current":     func(s *models.HPASnapshot) float64 { return s.MemoryCurrent },
+	"request_rate":       func(s *models.HPASnapshot) float64 { return s.RequestRate },
+	"error_rate":         func(s *models.HPASnapshot) float64 { return s.ErrorRate },
+	"p95_latency":        func(s *models.HPASnapshot) float64 { return s.P95Latency },
+	"p99_latency":        func(s *models.HPASnapshot) float64 { return s.P99Latency },
+	"network_rx_bytes":   func(s *models.HPASnapshot) float64 { return s.NetworkRxBytes },
+	"network_tx_bytes":   func(s *models.HPASnapshot) float64 { return s.NetworkTxBytes },
+	"restart_count":      func(s *models.HPASnapshot) float64 { return float64(s.RestartCount) },
+	"oom_killed_count":   func(s *models.HPASnapshot) float64 { return float64(s.OOMKilledCount) },
+	"crash_loop_pods":    func(s *models.HPASnapshot) float64 { return float64(s.CrashLoopPods) },
+	"ready":              func(s *models.HPASnapshot) float64 { return boolToFloat(s.Ready) },
+	"scaling_active":     func(s *models.HPASnapshot) float64 { return boolToFloat(s.ScalingActive) },
+	"cpu_target_delta":   func(s *models.HPASnapshot) float64 { return s.CPUCurrent - float64(s.CPUTarget) },
+	"replicas_max_ratio": replicasMaxRatio,
+}
+
+// RuleFields lista os campos suportados em condições (ordenados)
+func RuleFields() []string {
+	fields := make([]string, 0, len(snapshotFields)+1)
+	for field := range snapshotFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return append(fields, "metrics.<nome>")
+}
+
+func isRuleField(name string) bool {
+	if strings.HasPrefix(name, "metrics.") {
+		return len(name) > len("metrics.")
+	}
+	_, ok := snapshotFields[name]
+	return ok
+}
+
+func fieldValue(snapshot *models.HPASnapshot, field string) (float64, bool) {
+	if key, ok := strings.CutPrefix(field, "metrics."); ok {
+		return additionalMetric(snapshot.AdditionalMetrics[key])
+	}
+	getter, ok := snapshotFields[field]
+	if !ok {
+		return 0, false
+	}
+	return getter(snapshot), true
+}
+
+func additionalMetric(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case bool:
+		return boolToFloat(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// replicasMaxRatio % de réplicas atuais em relação ao máximo
+func replicasMaxRatio(s *models.HPASnapshot) float64 {
+	if s.MaxReplicas == 0 {
+		return 0
+	}
+	return float64(s.CurrentReplicas) / float64(s.MaxReplicas) * 100
+}
+
+func boolToFloat(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// IsDisabled indica se o tipo de anomalia foi desligado
+func (c *DetectorConfig) IsDisabled(anomalyType AnomalyType) bool {
+	for _, disabled := range c.Disabled {
+		if disabled == anomalyType {
+			return true
+		}
+	}
+	return false
+}
+
+// filterDisabled remove anomalias de tipos desligados na configuração do HPA
+func (d *Detector) filterDisabled(anomalies []Anomaly) []Anomaly {
+	if len(d.config.Disabled) == 0 {
+		return anomalies
+	}
+
+	filtered := anomalies[:0]
+	for _, anomaly := range anomalies {
+		if !d.config.IsDisabled(anomaly.Type) {
+			filtered = append(filtered, anomaly)
+		}
+	}
+	return filtered
+}
+
+// evaluateCustomRules avalia as regras customizadas que casam com o HPA
+func (d *Detector) evaluateCustomRules(ts *models.TimeSeriesData, latest *models.HPASnapshot) []Anomaly {
+	if d.rules == nil || d.config.IsDisabled(AnomalyTypeCustomRule) {
+		return nil
+	}
+
+	var anomalies []Anomaly
+	for _, rule := range d.rules.RuleSet().rules {
+		if !rule.Match.matches(latest.Cluster, latest.Namespace, latest.Name) {
+			continue
+		}
+		if !rule.condition.Eval(latest) {
+			continue
+		}
+		minDuration := time.Duration(rule.MinDuration)
+		if minDuration > 0 && !d.checkMinDuration(ts, minDuration, rule.condition.Eval) {
+			continue
+		}
+
+		cooldown := time.Duration(rule.Cooldown)
+		if cooldown == 0 {
+			cooldown = d.config.AlertCooldown
+		}
+		key := rule.Name + "/" + latest.Cluster + "/" + latest.Namespace + "/" + latest.Name
+		if !d.rules.allowFire(key, cooldown, latest.Timestamp) {
+			continue
+		}
+
+		message := rule.Message
+		if message == "" {
+			message = fmt.Sprintf("Regra %s: %s", rule.Name, rule.condition)
+		}
+		description := fmt.Sprintf("Condição %q verdadeira (%s)", rule.condition, rule.condition.Values(latest))
+		if minDuration > 0 {
+			description += fmt.Sprintf(" há pelo menos %v", minDuration)
+		}
+		if rule.Description != "" {
+			description = rule.Description + ". " + description
+		}
+
+		anomalies = append(anomalies, Anomaly{
+			Type:        AnomalyTypeCustomRule,
+			Severity:    rule.severity,
+			Cluster:     latest.Cluster,
+			Namespace:   latest.Namespace,
+			HPAName:     latest.Name,
+			Timestamp:   time.Now(),
+			Message:     message,
+			Description: description,
+			Snapshot:    latest,
+			Stats:       &ts.Stats,
+			Actions:     rule.Actions,
+			Rule:        rule.Name,
+		})
+	}
+	return anomalies
+}
@@ -0,0 +1,225 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s-hpa-manager/internal/monitoring/models"
+	"k8s-hpa-manager/internal/monitoring/storage"
+)
+
+const testRulesYAML = `
+defaults:
+  error_rate_threshold: 3
+overrides:
+  - match: {cluster: "*-prd"}
+    thresholds:
+      cpu_spike_threshold: 80
+      alert_cooldown: 1m
+  - match: {cluster: "*-prd", namespace: "batch", hpa: "worker-*"}
+    thresholds:
+      cpu_spike_threshold: 200
+      disabled: [CPU_DROP]
+rules:
+  - name: memoria-alta
+    match: {cluster: "*-prd"}
+    condition: "memory_current > 90 and replicas_current >= replicas_max"
+    min_duration: 1m
+    severity: critical
+    cooldown: 10m
+    actions: ["Aumentar maxReplicas"]
+  - name: throttling
+    condition: "metrics.cpu_throttling >= 25 || ready == false"
+`
+
+func writeRules(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "anomaly-rules.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write rules: %v", err)
+	}
+	return path
+}
+
+func TestParseRulesInvalid(t *testing.T) {
+	tests := map[string]string{
+		"campo desconhecido":  "rules:\n  - name: x\n    condition: \"cpu > 1\"\n",
+		"operador inválido":   "rules:\n  - name: x\n    condition: \"cpu_current => 1\"\n",
+		"severidade inválida": "rules:\n  - name: x\n    condition: \"cpu_current > 1\"\n    severity: page\n",
+		"nome duplicado":      "rules:\n  - {name: x, condition: \"cpu_current > 1\"}\n  - {name: x, condition: \"cpu_current > 2\"}\n",
+		"chave desconhecida":  "defaults:\n  cpu_spike: 10\n",
+		"duração inválida":    "defaults:\n  oscillation_window: 5 minutos\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseRules([]byte(content)); err == nil {
+				t.Errorf("expected error for %q", content)
+			}
+		})
+	}
+
+	if set, err := ParseRules(nil); err != nil || len(set.File.Rules) != 0 {
+		t.Errorf("empty content should be a valid empty rule set, got %v", err)
+	}
+}
+
+func TestRuleSetConfigFor(t *testing.T) {
+	set, err := ParseRules([]byte(testRulesYAML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	base := DefaultDetectorConfig()
+
+	hlg := set.ConfigFor(base, "aks-hlg-admin", "api", "web")
+	if hlg.ErrorRateThreshold != 3 || hlg.CPUSpikeThreshold != 50 {
+		t.Errorf("HLG should only get defaults, got error=%.0f cpu_spike=%.0f", hlg.ErrorRateThreshold, hlg.CPUSpikeThreshold)
+	}
+
+	prd := set.ConfigFor(base, "aks-prd-admin", "api", "web")
+	if prd.CPUSpikeThreshold != 80 || prd.AlertCooldown != time.Minute || prd.IsDisabled(AnomalyTypeCPUDrop) {
+		t.Errorf("unexpected PRD config: %+v", prd)
+	}
+
+	worker := set.ConfigFor(base, "aks-prd", "batch", "worker-reports")
+	if worker.CPUSpikeThreshold != 200 || !worker.IsDisabled(AnomalyTypeCPUDrop) || worker.AlertCooldown != time.Minute {
+		t.Errorf("most specific override should win, got %+v", worker)
+	}
+
+	if base.CPUSpikeThreshold != 50 || base.ErrorRateThreshold != 5 {
+		t.Error("ConfigFor must not modify the base config")
+	}
+}
+
+func TestConditionEval(t *testing.T) {
+	condition, err := ParseCondition("metrics.cpu_throttling >= 25 || ready == false")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	snapshot := &models.HPASnapshot{Ready: true, AdditionalMetrics: map[string]interface{}{"cpu_throttling": 30.0}}
+	if !condition.Eval(snapshot) {
+		t.Error("expected condition to match throttling")
+	}
+
+	snapshot.AdditionalMetrics = nil
+	if condition.Eval(snapshot) {
+		t.Error("missing metric must not match")
+	}
+	if got := condition.Values(snapshot); got != "metrics.cpu_throttling=n/a, ready=1.00" {
+		t.Errorf("unexpected values: %q", got)
+	}
+
+	snapshot.Ready = false
+	if !condition.Eval(snapshot) {
+		t.Error("expected condition to match not ready")
+	}
+}
+
+func TestDetectorOverridesAndCustomRules(t *testing.T) {
+	cache := storage.NewTimeSeriesCache(&storage.CacheConfig{
+		MaxDuration:  10 * time.Minute,
+		ScanInterval: 30 * time.Second,
+	})
+	detector := NewDetector(cache, nil)
+	detector.SetRules(NewRuleStore(writeRules(t, testRulesYAML)))
+
+	// worker-reports: CPU caiu 60% (CPU_DROP desligado) e memória alta no máximo de réplicas há 1m30s
+	// web em HLG: mesma queda de CPU, sem override
+	now := time.Now().Add(-90 * time.Second)
+	for i, cpu := range []float64{80, 80, 80, 32} {
+		for _, target := range []struct{ cluster, namespace, name string }{
+			{"aks-prd", "batch", "worker-reports"},
+			{"aks-hlg", "api", "web"},
+		} {
+			cache.Add(&models.HPASnapshot{
+				Timestamp:       now.Add(time.Duration(i) * 30 * time.Second),
+				Cluster:         target.cluster,
+				Namespace:       target.namespace,
+				Name:            target.name,
+				CurrentReplicas: 10,
+				MaxReplicas:     10,
+				CPUCurrent:      cpu,
+				MemoryCurrent:   95,
+				Ready:           true,
+			})
+		}
+	}
+
+	result := detector.Detect()
+
+	found := map[string]bool{}
+	for _, anomaly := range result.Anomalies {
+		found[anomaly.HPAName+"/"+string(anomaly.Type)+"/"+anomaly.Rule] = true
+		if anomaly.Rule == "memoria-alta" {
+			if anomaly.Severity != models.SeverityCritical || len(anomaly.Actions) != 1 {
+				t.Errorf("unexpected custom anomaly: %+v", anomaly)
+			}
+			if !strings.Contains(anomaly.Description, "memory_current=95.00") {
+				t.Errorf("expected field values in description, got %q", anomaly.Description)
+			}
+		}
+	}
+
+	if found["worker-reports/CPU_DROP/"] {
+		t.Error("CPU_DROP should be disabled for worker-* in PRD")
+	}
+	if !found["web/CPU_DROP/"] {
+		t.Error("expected CPU_DROP for HLG HPA without override")
+	}
+	if !found["worker-reports/CUSTOM_RULE/memoria-alta"] {
+		t.Error("expected custom rule memoria-alta for PRD HPA")
+	}
+	if found["web/CUSTOM_RULE/memoria-alta"] {
+		t.Error("custom rule should only match PRD clusters")
+	}
+
+	// Cooldown de 10m: nova detecção não repete a regra
+	for _, anomaly := range detector.Detect().Anomalies {
+		if anomaly.Rule == "memoria-alta" {
+			t.Error("custom rule should respect cooldown")
+		}
+	}
+}
+
+func TestRuleStoreReload(t *testing.T) {
+	path := writeRules(t, "defaults:\n  cpu_spike_threshold: 70\n")
+	store := NewRuleStore(path)
+
+	config := store.RuleSet().ConfigFor(DefaultDetectorConfig(), "c", "ns", "hpa")
+	if config.CPUSpikeThreshold != 70 {
+		t.Fatalf("expected threshold from file, got %.0f", config.CPUSpikeThreshold)
+	}
+
+	if reloaded, err := store.Reload(); reloaded || err != nil {
+		t.Errorf("unchanged file should not reload (reloaded=%v, err=%v)", reloaded, err)
+	}
+
+	// Arquivo inválido mantém as regras anteriores
+	future := time.Now().Add(time.Minute)
+	os.WriteFile(path, []byte("defaults:\n  cpu_spike_threshold: alto\n"), 0644)
+	os.Chtimes(path, future, future)
+	if _, err := store.Reload(); err == nil || store.LastError() == nil {
+		t.Error("expected error for invalid file")
+	}
+	if store.RuleSet().File.Defaults == nil || *store.RuleSet().File.Defaults.CPUSpikeThreshold != 70 {
+		t.Error("invalid file must keep previous rules")
+	}
+
+	// Save grava e ativa imediatamente
+	if _, err := store.Save([]byte("defaults:\n  cpu_spike_threshold: 90\n")); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+	config = store.RuleSet().ConfigFor(DefaultDetectorConfig(), "c", "ns", "hpa")
+	if config.CPUSpikeThreshold != 90 || store.LastError() != nil {
+		t.Errorf("expected saved rules active, got %.0f (err %v)", config.CPUSpikeThreshold, store.LastError())
+	}
+
+	// Arquivo removido = sem regras
+	os.Remove(path)
+	if reloaded, err := store.Reload(); !reloaded || err != nil || store.RuleSet().File.Defaults != nil {
+		t.Errorf("removed file should clear rules (reloaded=%v, err=%v)", reloaded, err)
+	}
+}
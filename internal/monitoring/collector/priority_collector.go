@@ -40,8 +40,13 @@ type PriorityCollector struct {
 	pfManager   *portforward.PortForwardManager
 	kubeManager *config.KubeConfigManager
 
+	// Intervalo entre scans (alterável em execução via SetInterval)
+	interval   time.Duration
+	intervalCh chan time.Duration
+
 	// Controle
 	running  bool
+	paused   bool // scans suspensos, port-forwards mantidos
 	stopCh   chan struct{}
 	mu       sync.RWMutex
 	wg       sync.WaitGroup
@@ -61,6 +66,9 @@ type PriorityHPA struct {
 	BaselineReady bool      // Baseline de 3 dias já foi carregado
 }
 
+// DefaultPriorityInterval intervalo padrão entre scans dos HPAs prioritários
+const DefaultPriorityInterval = 30 * time.Second
+
 // NewPriorityCollector cria novo collector com sistema de prioridades
 func NewPriorityCollector(
 	persistence *storage.Persistence,
@@ -76,6 +84,8 @@ func NewPriorityCollector(
 		persistence:  persistence,
 		pfManager:    pfManager,
 		kubeManager:  kubeManager,
+		interval:     DefaultPriorityInterval,
+		intervalCh:   make(chan time.Duration, 1),
 		stopCh:       make(chan struct{}),
 		ctx:          ctx,
 		cancel:       cancel,
@@ -90,10 +100,30 @@ func (c *PriorityCollector) Start() error {
 		return fmt.Errorf("collector já está rodando")
 	}
 	c.running = true
+	c.paused = false
+	// Reinício após Stop: novo contexto e port-forwards dos clusters que continuam monitorados
+	restarted := c.ctx.Err() != nil
+	if restarted {
+		c.ctx, c.cancel = context.WithCancel(context.Background())
+		c.stopCh = make(chan struct{})
+	}
 	c.mu.Unlock()
 
+	if restarted {
+		for cluster, port := range c.GetPortMapping() {
+			if err := c.startDedicatedPortForward(cluster, port); err != nil {
+				log.Error().
+					Err(err).
+					Str("cluster", cluster).
+					Int("port", port).
+					Msg("Falha ao recriar port-forward dedicado")
+			}
+		}
+	}
+
 	log.Info().
 		Int("available_ports", len(c.availPorts)).
+		Dur("interval", c.Interval()).
 		Msg("🔄 PriorityCollector iniciado")
 
 	// Inicia loop de scans prioritários
@@ -538,14 +568,17 @@ func (c *PriorityCollector) collectHistoricalData(ctx context.Context, promClien
 	return snapshots, nil
 }
 
-// priorityScanLoop loop de scans para HPAs prioritários (30s interval)
+// priorityScanLoop loop de scans para HPAs prioritários (a cada Interval)
 func (c *PriorityCollector) priorityScanLoop() {
 	defer c.wg.Done()
 
-	ticker := time.NewTicker(30 * time.Second)
+	interval := c.Interval()
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	log.Info().Msg("🔄 Loop de scans prioritários iniciado (30s interval)")
+	log.Info().
+		Dur("interval", interval).
+		Msg("🔄 Loop de scans prioritários iniciado")
 
 	// Executa scan imediato ao iniciar
 	c.executePriorityScans()
@@ -558,12 +591,58 @@ func (c *PriorityCollector) priorityScanLoop() {
 		case <-c.stopCh:
 			log.Info().Msg("Loop de scans prioritários encerrado (stopCh)")
 			return
+		case interval := <-c.intervalCh:
+			ticker.Reset(interval)
+			log.Info().
+				Dur("interval", interval).
+				Msg("Intervalo de scans prioritários alterado")
 		case <-ticker.C:
 			c.executePriorityScans()
 		}
 	}
 }
 
+// Interval intervalo atual entre scans
+func (c *PriorityCollector) Interval() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.interval
+}
+
+// SetInterval altera o intervalo entre scans (aplicado imediatamente se o loop estiver rodando)
+func (c *PriorityCollector) SetInterval(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interval = interval
+
+	// Mantém só o valor mais recente pendente no canal
+	select {
+	case <-c.intervalCh:
+	default:
+	}
+	select {
+	case c.intervalCh <- interval:
+	default:
+	}
+}
+
+// SetPaused suspende/retoma os scans sem derrubar os port-forwards
+func (c *PriorityCollector) SetPaused(paused bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paused = paused
+}
+
+// IsPaused indica se os scans estão suspensos
+func (c *PriorityCollector) IsPaused() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.paused
+}
+
 // ensurePortForward garante que port-forward está ativo (reconciliação KISS)
 // Testa conexão Prometheus, recria port-forward se falhar
 func (c *PriorityCollector) ensurePortForward(ctx context.Context, hpa *PriorityHPA) error {
@@ -618,6 +697,11 @@ func (c *PriorityCollector) ensurePortForward(ctx context.Context, hpa *Priority
 
 // executePriorityScans executa scan de TODOS os HPAs prioritários
 func (c *PriorityCollector) executePriorityScans() {
+	if c.IsPaused() {
+		log.Debug().Msg("Scans prioritários pausados")
+		return
+	}
+
 	c.priorityMu.RLock()
 	hpas := make([]*PriorityHPA, 0, len(c.priorityHPAs))
 	for _, hpa := range c.priorityHPAs {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/rs/zerolog/log"
 	"k8s-hpa-manager/internal/monitoring/models"
+	"k8s-hpa-manager/internal/monitoring/monitor"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -61,6 +62,7 @@ func (c *RotatingCollector) enrichSnapshotWithK8sData(
 	targetName := hpa.Spec.ScaleTargetRef.Name
 
 	var containers []corev1.Container
+	var podSelector *metav1.LabelSelector
 
 	switch targetKind {
 	case "Deployment":
@@ -75,6 +77,7 @@ func (c *RotatingCollector) enrichSnapshotWithK8sData(
 			return
 		}
 		containers = deployment.Spec.Template.Spec.Containers
+		podSelector = deployment.Spec.Selector
 
 	case "StatefulSet":
 		statefulset, err := clientset.AppsV1().StatefulSets(snapshot.Namespace).Get(ctx, targetName, metav1.GetOptions{})
@@ -88,6 +91,7 @@ func (c *RotatingCollector) enrichSnapshotWithK8sData(
 			return
 		}
 		containers = statefulset.Spec.Template.Spec.Containers
+		podSelector = statefulset.Spec.Selector
 
 	case "DaemonSet":
 		daemonset, err := clientset.AppsV1().DaemonSets(snapshot.Namespace).Get(ctx, targetName, metav1.GetOptions{})
@@ -101,6 +105,7 @@ func (c *RotatingCollector) enrichSnapshotWithK8sData(
 			return
 		}
 		containers = daemonset.Spec.Template.Spec.Containers
+		podSelector = daemonset.Spec.Selector
 
 	default:
 		log.Debug().
@@ -137,10 +142,22 @@ func (c *RotatingCollector) enrichSnapshotWithK8sData(
 		}
 	}
 
+	// 6. Restarts e última terminação dos pods (OOMKilled/CrashLoopBackOff)
+	if err := monitor.CollectPodRestarts(ctx, clientset, snapshot, podSelector); err != nil {
+		log.Debug().
+			Err(err).
+			Str("cluster", snapshot.Cluster).
+			Str("namespace", snapshot.Namespace).
+			Str("hpa", snapshot.Name).
+			Msg("Failed to collect pod restarts")
+	}
+
 	log.Debug().
 		Str("cluster", snapshot.Cluster).
 		Str("namespace", snapshot.Namespace).
 		Str("hpa", snapshot.Name).
+		Int32("restart_count", snapshot.RestartCount).
+		Int32("oom_killed", snapshot.OOMKilledCount).
 		Str("cpu_request", snapshot.CPURequest).
 		Str("cpu_limit", snapshot.CPULimit).
 		Str("memory_request", snapshot.MemoryRequest).
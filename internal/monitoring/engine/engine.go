@@ -29,8 +29,16 @@ type ScanEngine struct {
 	cache             *storage.TimeSeriesCache
 	persistence       *storage.Persistence
 	detector          *analyzer.Detector
+	rules             *analyzer.RuleStore          // Regras de anomalias (recarregadas a cada rulesReloadInterval)
+	notifier          AnomalyNotifier              // Notificações externas (opcional)
+	anomalyListener   AnomalyListener              // Stream de eventos da interface web (opcional)
+	alertPolicy       storage.AlertPolicy          // Ciclo de vida dos alertas persistidos
 	priorityCollector *collector.PriorityCollector // Sistema com prioridades para HPAs escolhidos
 
+	// Estatísticas de scan por cluster (exportadas em /metrics)
+	statsMu   sync.Mutex
+	scanStats map[string]*ClusterScanStats
+
 	// Stress Test (apenas em ScanModeStressTest)
 	baselineCollector *monitor.BaselineCollector
 	stressComparator  *analyzer.StressComparator
@@ -52,8 +60,19 @@ type ScanEngine struct {
 	mu       sync.RWMutex
 	wg       sync.WaitGroup
 	stopChan chan struct{}
+
+	// Estado (HPAs, intervalo, running/paused) gravado no SQLite após RestoreState
+	persistState bool
+}
+
+// AnomalyNotifier recebe as anomalias detectadas (implementado por notifier.Notifier)
+type AnomalyNotifier interface {
+	Notify(anomaly analyzer.Anomaly)
 }
 
+// AnomalyListener recebe as anomalias novas (alerta aberto neste scan); alert é nil sem persistência
+type AnomalyListener func(anomaly analyzer.Anomaly, alert *storage.Alert)
+
 // New cria novo scan engine
 func New(cfg *scanner.ScanConfig, snapshotChan chan *models.HPASnapshot, anomalyChan chan analyzer.Anomaly, stressResultChan chan *models.StressTestMetrics) *ScanEngine {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -74,6 +93,10 @@ func New(cfg *scanner.ScanConfig, snapshotChan chan *models.HPASnapshot, anomaly
 
 	detector := analyzer.NewDetector(cache, nil)
 
+	// Regras de anomalias (~/.k8s-hpa-manager/anomaly-rules.yaml)
+	rules := analyzer.NewRuleStore(analyzer.DefaultRulesPath())
+	detector.SetRules(rules)
+
 	// Cria KubeConfigManager
 	kubeconfigPath := os.Getenv("KUBECONFIG")
 	if kubeconfigPath == "" {
@@ -104,6 +127,9 @@ func New(cfg *scanner.ScanConfig, snapshotChan chan *models.HPASnapshot, anomaly
 		cache:             cache,
 		persistence:       persistence,
 		detector:          detector,
+		rules:             rules,
+		alertPolicy:       storage.DefaultAlertPolicy(),
+		scanStats:         make(map[string]*ClusterScanStats),
 		priorityCollector: priorityCollector,
 		snapshotChan:     snapshotChan,
 		anomalyChan:      anomalyChan,
@@ -123,6 +149,10 @@ func (e *ScanEngine) Start() error {
 	}
 	e.running = true
 	e.paused = false
+	// Reinício após Stop: goroutines usam um novo contexto
+	if e.ctx.Err() != nil {
+		e.ctx, e.cancel = context.WithCancel(context.Background())
+	}
 	e.mu.Unlock()
 
 	log.Info().
@@ -140,6 +170,16 @@ func (e *ScanEngine) Start() error {
 		}
 	}
 
+	// Hot-reload das regras de anomalias
+	e.wg.Add(1)
+	go e.watchRules()
+
+	// Compactação dos snapshots em rollups (histórico longo)
+	if e.persistence != nil {
+		e.wg.Add(1)
+		go e.runCompactor()
+	}
+
 	// NOVA ARQUITETURA: Inicia PriorityCollector
 	if e.priorityCollector != nil {
 		// Inicia coleta
@@ -153,11 +193,39 @@ func (e *ScanEngine) Start() error {
 		log.Info().Msg("✅ PriorityCollector iniciado - aguardando HPAs serem adicionados via web interface")
 	}
 
+	e.saveState()
 	return nil
 }
 
-// Stop para scan engine
+// Stop para scan engine (pode ser iniciado de novo com Start; a persistência continua aberta)
 func (e *ScanEngine) Stop() error {
+	if err := e.halt(); err != nil {
+		return err
+	}
+	e.saveState()
+	return nil
+}
+
+// Close para o engine sem alterar o estado salvo (restaurado no próximo startup)
+// e fecha a persistência. Usado no shutdown do servidor.
+func (e *ScanEngine) Close() error {
+	err := e.halt()
+
+	// Cleanup e fecha persistência
+	if e.persistence != nil {
+		if err := e.persistence.Cleanup(); err != nil {
+			log.Warn().Err(err).Msg("Erro ao limpar dados antigos")
+		}
+		if err := e.persistence.Close(); err != nil {
+			log.Warn().Err(err).Msg("Erro ao fechar banco de dados")
+		}
+		log.Info().Msg("Persistência SQLite fechada")
+	}
+	return err
+}
+
+// halt para collector, port-forwards e goroutines
+func (e *ScanEngine) halt() error {
 	e.mu.Lock()
 	if !e.running {
 		e.mu.Unlock()
@@ -204,17 +272,6 @@ func (e *ScanEngine) Stop() error {
 		}
 	}
 
-	// Cleanup e fecha persistência
-	if e.persistence != nil {
-		if err := e.persistence.Cleanup(); err != nil {
-			log.Warn().Err(err).Msg("Erro ao limpar dados antigos")
-		}
-		if err := e.persistence.Close(); err != nil {
-			log.Warn().Err(err).Msg("Erro ao fechar banco de dados")
-		}
-		log.Info().Msg("Persistência SQLite fechada")
-	}
-
 	log.Info().Msg("Scan engine parado")
 	return nil
 }
@@ -226,7 +283,11 @@ func (e *ScanEngine) Pause() {
 
 	if e.running && !e.paused {
 		e.paused = true
+		if e.priorityCollector != nil {
+			e.priorityCollector.SetPaused(true)
+		}
 		log.Info().Msg("Scan pausado")
+		e.saveStateLocked()
 	}
 }
 
@@ -237,7 +298,11 @@ func (e *ScanEngine) Resume() {
 
 	if e.running && e.paused {
 		e.paused = false
+		if e.priorityCollector != nil {
+			e.priorityCollector.SetPaused(false)
+		}
 		log.Info().Msg("Scan retomado")
+		e.saveStateLocked()
 	}
 }
 
@@ -265,6 +330,39 @@ func (e *ScanEngine) GetPersistence() *storage.Persistence {
 	return e.persistence
 }
 
+// GetPortForwardManager retorna o gerenciador de port-forwards (usado pelo collector do Alertmanager)
+func (e *ScanEngine) GetPortForwardManager() *portforward.PortForwardManager {
+	return e.pfManager
+}
+
+// SetNotifier configura o envio de anomalias para sinks externos (webhook, Slack, Teams, email)
+func (e *ScanEngine) SetNotifier(notifier AnomalyNotifier) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.notifier = notifier
+}
+
+// SetAnomalyListener registra quem recebe as anomalias novas (ver AnomalyListener).
+// Diferente do anomalyChan, anomalias repetidas de um alerta já aberto não são reenviadas.
+func (e *ScanEngine) SetAnomalyListener(listener AnomalyListener) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.anomalyListener = listener
+}
+
+// SetAlertPolicy configura o ciclo de vida dos alertas; o servidor web usa
+// storage.AlertPolicyFromWatchdog com ~/.k8s-hpa-manager/watchdog.yaml
+func (e *ScanEngine) SetAlertPolicy(policy storage.AlertPolicy) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.alertPolicy = policy
+}
+
+// GetRules retorna o store de regras de anomalias
+func (e *ScanEngine) GetRules() *analyzer.RuleStore {
+	return e.rules
+}
+
 // GetPriorityCollector retorna o PriorityCollector para acesso direto
 func (e *ScanEngine) GetPriorityCollector() *collector.PriorityCollector {
 	return e.priorityCollector
@@ -360,9 +458,14 @@ func (e *ScanEngine) RemoveTarget(cluster string) {
 		Str("cluster", cluster).
 		Msg("Target removido")
 
-	// NOVA ARQUITETURA: Remove do PriorityCollector
-	if e.running && e.priorityCollector != nil {
-		e.priorityCollector.RemoveTarget(cluster)
+	// NOVA ARQUITETURA: Remove do PriorityCollector (HPAs restaurados ficam nele mesmo com o engine parado)
+	if e.priorityCollector != nil {
+		e.priorityCollector.RemoveTarget(strings.TrimSuffix(cluster, "-admin"))
+	}
+	if e.persistState && e.persistence != nil {
+		if err := e.persistence.DeleteMonitoredHPA(cluster, "", ""); err != nil {
+			log.Warn().Err(err).Str("cluster", cluster).Msg("Falha ao remover HPAs monitorados salvos")
+		}
 	}
 
 	// Para port-forward do cluster removido (se houver)
@@ -452,6 +555,72 @@ func (e *ScanEngine) scanLoop() {
 	}
 }
 
+// rulesReloadInterval intervalo de verificação do arquivo de regras
+const rulesReloadInterval = 10 * time.Second
+
+// watchRules recarrega o arquivo de regras quando ele muda (até o engine parar)
+func (e *ScanEngine) watchRules() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(rulesReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := e.rules.Reload()
+			if err != nil {
+				log.Warn().
+					Err(err).
+					Str("path", e.rules.Path()).
+					Msg("Arquivo de regras inválido, mantendo regras anteriores")
+				continue
+			}
+			if reloaded {
+				set := e.rules.RuleSet()
+				log.Info().
+					Str("path", e.rules.Path()).
+					Int("overrides", len(set.File.Overrides)).
+					Int("rules", len(set.File.Rules)).
+					Msg("Regras de anomalias recarregadas")
+			}
+		}
+	}
+}
+
+// rollupCleanupInterval intervalo da retenção (snapshots brutos e tiers de rollup) durante a execução
+const rollupCleanupInterval = time.Hour
+
+// runCompactor mantém os rollups atualizados e aplica a retenção periodicamente (até o engine parar)
+func (e *ScanEngine) runCompactor() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(storage.DefaultCompactionInterval)
+	defer ticker.Stop()
+	lastCleanup := time.Now()
+
+	for {
+		select {
+		case <-e.ctx.Done():
+			return
+		case <-ticker.C:
+			if time.Since(lastCleanup) >= rollupCleanupInterval {
+				// Cleanup compacta antes de remover dados antigos
+				lastCleanup = time.Now()
+				if err := e.persistence.Cleanup(); err != nil {
+					log.Warn().Err(err).Msg("Erro ao aplicar retenção dos snapshots")
+				}
+				continue
+			}
+			if err := e.persistence.Compact(); err != nil {
+				log.Warn().Err(err).Msg("Erro ao compactar snapshots em rollups")
+			}
+		}
+	}
+}
+
 // runScanForTarget executa scan de um target específico
 func (e *ScanEngine) runScanForTarget(target scanner.ScanTarget) {
 	log.Info().
@@ -459,6 +628,14 @@ func (e *ScanEngine) runScanForTarget(target scanner.ScanTarget) {
 		Strs("namespaces", target.Namespaces).
 		Msg("Escaneando cluster")
 
+	// Estatísticas do scan (exportadas em /metrics)
+	start := time.Now()
+	scanErrors := 0
+	var result *monitor.ScanResult
+	defer func() {
+		e.recordScan(target.Cluster, time.Since(start), scanErrors, result)
+	}()
+
 	// Cria contexto com timeout para o scan
 	ctx, cancel := context.WithTimeout(e.ctx, 2*time.Minute)
 	defer cancel()
@@ -469,6 +646,7 @@ func (e *ScanEngine) runScanForTarget(target scanner.ScanTarget) {
 		log.Warn().
 			Str("cluster", target.Cluster).
 			Msg("Port-forward não disponível, pulando cluster")
+		scanErrors++
 		return
 	}
 
@@ -489,24 +667,28 @@ func (e *ScanEngine) runScanForTarget(target scanner.ScanTarget) {
 		ScanInterval:      e.config.Interval,
 		ExcludeNamespaces: []string{},
 		EnablePrometheus:  true,
+		Rules:             e.rules,
 	})
 	if err != nil {
 		log.Error().
 			Err(err).
 			Str("cluster", target.Cluster).
 			Msg("Falha ao criar collector")
+		scanErrors++
 		return
 	}
 
 	// Executa scan do cluster
-	result, err := collector.Scan(ctx)
+	result, err = collector.Scan(ctx)
 	if err != nil {
 		log.Error().
 			Err(err).
 			Str("cluster", target.Cluster).
 			Msg("Falha ao executar scan")
+		scanErrors++
 		return
 	}
+	scanErrors += len(result.Errors)
 
 	// Envia snapshots coletados para canal da TUI
 	snapshots := collector.GetCache().GetAll()
@@ -576,8 +758,28 @@ func (e *ScanEngine) runScanForTarget(target scanner.ScanTarget) {
 		e.compareWithBaseline(snapshotList)
 	}
 
-	// Envia anomalias detectadas para canal da TUI
-	for _, anomaly := range result.Anomalies {
+	e.mu.RLock()
+	notifier := e.notifier
+	listener := e.anomalyListener
+	e.mu.RUnlock()
+
+	// Persiste alertas (ack/silence/resolução) antes de notificar
+	alerts := e.syncAlerts(target.Cluster, result.Anomalies)
+
+	// Envia anomalias detectadas para canal da TUI (e para os sinks de notificação)
+	for i, anomaly := range result.Anomalies {
+		// Alertas já reconhecidos ou silenciados não geram nova notificação
+		if notifier != nil && (alerts == nil || alerts[i].State == storage.AlertStateOpen) {
+			notifier.Notify(anomaly)
+		}
+		if listener != nil {
+			if alerts == nil {
+				listener(anomaly, nil)
+			} else if alerts[i].Occurrences == 1 {
+				listener(anomaly, &alerts[i])
+			}
+		}
+
 		select {
 		case e.anomalyChan <- anomaly:
 		default:
@@ -595,6 +797,45 @@ func (e *ScanEngine) runScanForTarget(target scanner.ScanTarget) {
 		Msg("Cluster escaneado com sucesso")
 }
 
+// syncAlerts registra as anomalias do scan no SQLite e avança o ciclo de vida dos alertas do cluster.
+// Retorna o alerta de cada anomalia (mesma ordem) ou nil sem persistência.
+func (e *ScanEngine) syncAlerts(cluster string, anomalies []analyzer.Anomaly) []storage.Alert {
+	if e.persistence == nil {
+		return nil
+	}
+
+	e.mu.RLock()
+	policy := e.alertPolicy
+	e.mu.RUnlock()
+
+	detected := make([]storage.Alert, 0, len(anomalies))
+	for _, anomaly := range anomalies {
+		detected = append(detected, storage.Alert{
+			Cluster:     anomaly.Cluster,
+			Namespace:   anomaly.Namespace,
+			HPAName:     anomaly.HPAName,
+			Type:        string(anomaly.Type),
+			Rule:        anomaly.Rule,
+			Severity:    anomaly.Severity,
+			Message:     anomaly.Message,
+			Description: anomaly.Description,
+		})
+	}
+
+	alerts, err := e.persistence.SyncAlerts(cluster, detected, policy, time.Now())
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("cluster", cluster).
+			Msg("Falha ao persistir alertas")
+		return nil
+	}
+	if len(alerts) != len(anomalies) {
+		return nil
+	}
+	return alerts
+}
+
 // runScan executa um scan completo (DEPRECATED - mantido para compatibilidade)
 func (e *ScanEngine) runScan() {
 	log.Info().Msg("Executando scan...")
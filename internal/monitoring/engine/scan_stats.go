@@ -0,0 +1,57 @@
+package engine
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"k8s-hpa-manager/internal/monitoring/monitor"
+)
+
+// ClusterScanStats estatísticas acumuladas dos scans de um cluster
+type ClusterScanStats struct {
+	Cluster       string
+	Scans         int64         // Scans executados
+	Errors        int64         // Falhas (port-forward, collector, scan) + erros parciais do scan
+	TotalDuration time.Duration // Soma das durações (rate(total)/rate(scans) = duração média)
+	LastDuration  time.Duration
+	LastScan      time.Time
+	LastSnapshots int
+	LastAnomalies int
+}
+
+// recordScan acumula o resultado de um scan (result nil = scan falhou)
+func (e *ScanEngine) recordScan(cluster string, duration time.Duration, errors int, result *monitor.ScanResult) {
+	cluster = strings.TrimSuffix(cluster, "-admin")
+
+	e.statsMu.Lock()
+	defer e.statsMu.Unlock()
+
+	stats, ok := e.scanStats[cluster]
+	if !ok {
+		stats = &ClusterScanStats{Cluster: cluster}
+		e.scanStats[cluster] = stats
+	}
+	stats.Scans++
+	stats.Errors += int64(errors)
+	stats.TotalDuration += duration
+	stats.LastDuration = duration
+	stats.LastScan = time.Now()
+	if result != nil {
+		stats.LastSnapshots = result.SnapshotsCount
+		stats.LastAnomalies = len(result.Anomalies)
+	}
+}
+
+// ScanStats retorna uma cópia das estatísticas de scan por cluster (ordenadas por cluster)
+func (e *ScanEngine) ScanStats() []ClusterScanStats {
+	e.statsMu.Lock()
+	defer e.statsMu.Unlock()
+
+	stats := make([]ClusterScanStats, 0, len(e.scanStats))
+	for _, s := range e.scanStats {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Cluster < stats[j].Cluster })
+	return stats
+}
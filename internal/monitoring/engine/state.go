@@ -0,0 +1,198 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"k8s-hpa-manager/internal/monitoring/collector"
+	"k8s-hpa-manager/internal/monitoring/storage"
+)
+
+// Limites do intervalo de scan configurável pelo operador
+const (
+	MinScanInterval = 10 * time.Second
+	MaxScanInterval = time.Hour
+)
+
+// RestoreResult resultado de RestoreState
+type RestoreResult struct {
+	HPAs     int                  // HPAs restaurados no PriorityCollector
+	Failed   int                  // HPAs que falharam (ex: port-forward); continuam salvos
+	Status   storage.EngineStatus // Estado aplicado ao engine
+	Interval time.Duration
+}
+
+// RestoreState recarrega do SQLite os HPAs monitorados, o intervalo e o estado
+// running/paused/stopped, e passa a gravar toda alteração desses dados. Sem estado
+// salvo (primeira execução), o engine fica parado até o operador escolher HPAs.
+func (e *ScanEngine) RestoreState() (*RestoreResult, error) {
+	if e.persistence == nil || e.priorityCollector == nil {
+		return nil, fmt.Errorf("monitoring persistence not available")
+	}
+
+	state, err := e.persistence.LoadEngineState()
+	if err != nil {
+		return nil, err
+	}
+	hpas, err := e.persistence.ListMonitoredHPAs()
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	e.persistState = true
+	e.mu.Unlock()
+
+	result := &RestoreResult{Status: storage.EngineStatusStopped, Interval: e.priorityCollector.Interval()}
+	if state != nil {
+		result.Status = state.Status
+		if state.Interval > 0 {
+			e.priorityCollector.SetInterval(clampInterval(state.Interval))
+			result.Interval = e.priorityCollector.Interval()
+		}
+	}
+
+	for _, hpa := range hpas {
+		if err := e.priorityCollector.AddPriorityHPA(hpa.Cluster, hpa.Namespace, hpa.Name); err != nil {
+			log.Warn().
+				Err(err).
+				Str("cluster", hpa.Cluster).
+				Str("namespace", hpa.Namespace).
+				Str("hpa", hpa.Name).
+				Msg("Falha ao restaurar HPA monitorado")
+			result.Failed++
+			continue
+		}
+		result.HPAs++
+	}
+
+	switch result.Status {
+	case storage.EngineStatusRunning, storage.EngineStatusPaused:
+		if err := e.Start(); err != nil {
+			return result, fmt.Errorf("failed to start restored engine: %w", err)
+		}
+		if result.Status == storage.EngineStatusPaused {
+			e.Pause()
+		}
+	}
+	return result, nil
+}
+
+// AddMonitoredHPA inclui o HPA no monitoramento prioritário e o grava para o próximo startup
+func (e *ScanEngine) AddMonitoredHPA(cluster, namespace, name, addedBy string) error {
+	if e.priorityCollector == nil {
+		return fmt.Errorf("PriorityCollector not available")
+	}
+	cluster = strings.TrimSuffix(cluster, "-admin")
+	if err := e.priorityCollector.AddPriorityHPA(cluster, namespace, name); err != nil {
+		return err
+	}
+	if !e.statePersisted() {
+		return nil
+	}
+	return e.persistence.SaveMonitoredHPA(storage.MonitoredHPA{
+		Cluster:   cluster,
+		Namespace: namespace,
+		Name:      name,
+		AddedBy:   addedBy,
+	})
+}
+
+// RemoveMonitoredHPA retira o HPA do monitoramento (e do estado salvo)
+func (e *ScanEngine) RemoveMonitoredHPA(cluster, namespace, name string) error {
+	cluster = strings.TrimSuffix(cluster, "-admin")
+	if e.priorityCollector != nil {
+		e.priorityCollector.RemovePriorityHPA(cluster, namespace, name)
+	}
+	if !e.statePersisted() {
+		return nil
+	}
+	return e.persistence.DeleteMonitoredHPA(cluster, namespace, name)
+}
+
+// MonitoredHPAs HPAs monitorados (estado salvo; sem persistência, os do PriorityCollector)
+func (e *ScanEngine) MonitoredHPAs() ([]storage.MonitoredHPA, error) {
+	if e.statePersisted() {
+		return e.persistence.ListMonitoredHPAs()
+	}
+	if e.priorityCollector == nil {
+		return nil, nil
+	}
+
+	var hpas []storage.MonitoredHPA
+	for _, hpa := range e.priorityCollector.GetPriorityHPAs() {
+		hpas = append(hpas, storage.MonitoredHPA{
+			Cluster:   hpa.Cluster,
+			Namespace: hpa.Namespace,
+			Name:      hpa.Name,
+			AddedAt:   hpa.AddedAt,
+		})
+	}
+	sort.Slice(hpas, func(i, j int) bool { return hpas[i].AddedAt.Before(hpas[j].AddedAt) })
+	return hpas, nil
+}
+
+// ScanInterval intervalo entre scans dos HPAs monitorados
+func (e *ScanEngine) ScanInterval() time.Duration {
+	if e.priorityCollector == nil {
+		return collector.DefaultPriorityInterval
+	}
+	return e.priorityCollector.Interval()
+}
+
+// SetScanInterval altera o intervalo entre scans (entre MinScanInterval e MaxScanInterval)
+func (e *ScanEngine) SetScanInterval(interval time.Duration) error {
+	if interval < MinScanInterval || interval > MaxScanInterval {
+		return fmt.Errorf("scan interval must be between %s and %s", MinScanInterval, MaxScanInterval)
+	}
+	if e.priorityCollector == nil {
+		return fmt.Errorf("PriorityCollector not available")
+	}
+	e.priorityCollector.SetInterval(interval)
+	e.saveState()
+	return nil
+}
+
+func (e *ScanEngine) statePersisted() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.persistState && e.persistence != nil
+}
+
+// saveState grava running/paused/stopped e o intervalo (após RestoreState)
+func (e *ScanEngine) saveState() {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	e.saveStateLocked()
+}
+
+// saveStateLocked igual a saveState, com e.mu já adquirido
+func (e *ScanEngine) saveStateLocked() {
+	if !e.persistState || e.persistence == nil {
+		return
+	}
+
+	status := storage.EngineStatusStopped
+	if e.running {
+		status = storage.EngineStatusRunning
+		if e.paused {
+			status = storage.EngineStatusPaused
+		}
+	}
+	if err := e.persistence.SaveEngineState(storage.EngineState{Status: status, Interval: e.ScanInterval()}); err != nil {
+		log.Warn().Err(err).Msg("Falha ao salvar estado do monitoring engine")
+	}
+}
+
+func clampInterval(interval time.Duration) time.Duration {
+	if interval < MinScanInterval {
+		return MinScanInterval
+	}
+	if interval > MaxScanInterval {
+		return MaxScanInterval
+	}
+	return interval
+}
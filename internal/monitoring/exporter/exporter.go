@@ -0,0 +1,205 @@
+// Package exporter expõe em formato Prometheus o que o monitoring engine enxerga
+// (HPAs monitorados, alertas ativos, scans e port-forwards) para scrape central.
+//
+// Scrape config (GET /metrics exige o mesmo token da API):
+//
+//	scrape_configs:
+//	  - job_name: k8s-hpa-manager
+//	    authorization: {credentials: "<K8S_HPA_WEB_TOKEN>"}
+//	    static_configs: [{targets: ["hpa-manager:8080"]}]
+package exporter
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog/log"
+
+	"k8s-hpa-manager/internal/monitoring/engine"
+	"k8s-hpa-manager/internal/monitoring/models"
+	"k8s-hpa-manager/internal/monitoring/portforward"
+	"k8s-hpa-manager/internal/monitoring/storage"
+)
+
+const namespace = "k8s_hpa_manager"
+
+// Source dados expostos em /metrics (implementado por engine.ScanEngine)
+type Source interface {
+	GetCache() *storage.TimeSeriesCache
+	GetPersistence() *storage.Persistence
+	GetPortForwardManager() *portforward.PortForwardManager
+	ScanStats() []engine.ClusterScanStats
+}
+
+var (
+	hpaLabels = []string{"cluster", "namespace", "hpa"}
+
+	hpaCurrentReplicas = newDesc("hpa_current_replicas", "Réplicas atuais do HPA", hpaLabels)
+	hpaDesiredReplicas = newDesc("hpa_desired_replicas", "Réplicas desejadas do HPA", hpaLabels)
+	hpaMinReplicas     = newDesc("hpa_min_replicas", "minReplicas configurado no HPA", hpaLabels)
+	hpaMaxReplicas     = newDesc("hpa_max_replicas", "maxReplicas configurado no HPA", hpaLabels)
+	hpaCPUCurrent      = newDesc("hpa_cpu_utilization_percent", "Utilização de CPU atual (% do request)", hpaLabels)
+	hpaCPUTarget       = newDesc("hpa_cpu_target_percent", "Target de CPU do HPA (%)", hpaLabels)
+	hpaMemoryCurrent   = newDesc("hpa_memory_utilization_percent", "Utilização de memória atual (% do request)", hpaLabels)
+	hpaMemoryTarget    = newDesc("hpa_memory_target_percent", "Target de memória do HPA (%)", hpaLabels)
+	hpaBaselineReady   = newDesc("hpa_baseline_ready", "1 se o baseline histórico do HPA já foi coletado", hpaLabels)
+	hpaLastSnapshot    = newDesc("hpa_last_snapshot_timestamp_seconds", "Horário do último snapshot coletado", hpaLabels)
+
+	alertsActive = newDesc("alerts_active", "Alertas ativos por tipo, severidade e estado",
+		[]string{"cluster", "type", "severity", "state"})
+
+	scansTotal        = newDesc("scans_total", "Scans executados por cluster", []string{"cluster"})
+	scanErrorsTotal   = newDesc("scan_errors_total", "Erros de scan por cluster (falhas e erros parciais)", []string{"cluster"})
+	scanDurationTotal = newDesc("scan_duration_seconds_total", "Soma da duração dos scans por cluster", []string{"cluster"})
+	scanDuration      = newDesc("scan_last_duration_seconds", "Duração do último scan do cluster", []string{"cluster"})
+	scanLast          = newDesc("scan_last_timestamp_seconds", "Horário do último scan do cluster", []string{"cluster"})
+	scanSnapshots     = newDesc("scan_last_snapshots", "Snapshots coletados no último scan do cluster", []string{"cluster"})
+
+	portForwardUp = newDesc("portforward_up", "1 se o port-forward está ativo e respondendo",
+		[]string{"cluster", "kind", "port"})
+)
+
+func newDesc(name, help string, labels []string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help, labels, nil)
+}
+
+// Collector coleta as métricas a cada scrape a partir do estado atual do engine
+type Collector struct {
+	source Source
+}
+
+// NewCollector cria o collector para o engine
+func NewCollector(source Source) *Collector {
+	return &Collector{source: source}
+}
+
+// Handler retorna o handler HTTP de /metrics com um registry dedicado
+func Handler(source Source) http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewCollector(source))
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError})
+}
+
+// Describe implementa prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		hpaCurrentReplicas, hpaDesiredReplicas, hpaMinReplicas, hpaMaxReplicas,
+		hpaCPUCurrent, hpaCPUTarget, hpaMemoryCurrent, hpaMemoryTarget,
+		hpaBaselineReady, hpaLastSnapshot, alertsActive,
+		scansTotal, scanErrorsTotal, scanDurationTotal, scanDuration, scanLast, scanSnapshots,
+		portForwardUp,
+	} {
+		ch <- desc
+	}
+}
+
+// Collect implementa prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.collectHPAs(ch)
+	c.collectAlerts(ch)
+	c.collectScans(ch)
+	c.collectPortForwards(ch)
+}
+
+func (c *Collector) collectHPAs(ch chan<- prometheus.Metric) {
+	cache := c.source.GetCache()
+	if cache == nil {
+		return
+	}
+
+	// O mesmo HPA pode estar no cache com e sem sufixo -admin: exporta o snapshot mais recente
+	latestByHPA := make(map[string]*models.HPASnapshot)
+	for _, ts := range cache.GetAll() {
+		latest := ts.GetLatest()
+		if latest == nil {
+			continue
+		}
+		key := strings.TrimSuffix(latest.Cluster, "-admin") + "/" + latest.Namespace + "/" + latest.Name
+		if current, ok := latestByHPA[key]; !ok || latest.Timestamp.After(current.Timestamp) {
+			latestByHPA[key] = latest
+		}
+	}
+
+	for _, latest := range latestByHPA {
+		labels := []string{strings.TrimSuffix(latest.Cluster, "-admin"), latest.Namespace, latest.Name}
+		gauge(ch, hpaCurrentReplicas, float64(latest.CurrentReplicas), labels...)
+		gauge(ch, hpaDesiredReplicas, float64(latest.DesiredReplicas), labels...)
+		gauge(ch, hpaMinReplicas, float64(latest.MinReplicas), labels...)
+		gauge(ch, hpaMaxReplicas, float64(latest.MaxReplicas), labels...)
+		gauge(ch, hpaCPUCurrent, latest.CPUCurrent, labels...)
+		gauge(ch, hpaMemoryCurrent, latest.MemoryCurrent, labels...)
+		if latest.CPUTarget > 0 {
+			gauge(ch, hpaCPUTarget, float64(latest.CPUTarget), labels...)
+		}
+		if latest.MemoryTarget > 0 {
+			gauge(ch, hpaMemoryTarget, float64(latest.MemoryTarget), labels...)
+		}
+		gauge(ch, hpaBaselineReady, boolValue(latest.BaselineReady), labels...)
+		gauge(ch, hpaLastSnapshot, float64(latest.Timestamp.Unix()), labels...)
+	}
+}
+
+func (c *Collector) collectAlerts(ch chan<- prometheus.Metric) {
+	persistence := c.source.GetPersistence()
+	if persistence == nil {
+		return
+	}
+
+	alerts, err := persistence.ListAlerts(storage.AlertFilter{
+		States: []storage.AlertState{storage.AlertStateOpen, storage.AlertStateAcked, storage.AlertStateSilenced},
+		Limit:  -1,
+	})
+	if err != nil {
+		log.Warn().Err(err).Msg("Falha ao listar alertas para /metrics")
+		return
+	}
+
+	type alertKey struct{ cluster, alertType, severity, state string }
+	counts := make(map[alertKey]int)
+	for _, alert := range alerts {
+		counts[alertKey{alert.Cluster, alert.Type, strings.ToLower(alert.Severity.String()), string(alert.State)}]++
+	}
+	for key, count := range counts {
+		gauge(ch, alertsActive, float64(count), key.cluster, key.alertType, key.severity, key.state)
+	}
+}
+
+func (c *Collector) collectScans(ch chan<- prometheus.Metric) {
+	for _, stats := range c.source.ScanStats() {
+		counter(ch, scansTotal, float64(stats.Scans), stats.Cluster)
+		counter(ch, scanErrorsTotal, float64(stats.Errors), stats.Cluster)
+		counter(ch, scanDurationTotal, stats.TotalDuration.Seconds(), stats.Cluster)
+		gauge(ch, scanDuration, stats.LastDuration.Seconds(), stats.Cluster)
+		gauge(ch, scanLast, float64(stats.LastScan.Unix()), stats.Cluster)
+		gauge(ch, scanSnapshots, float64(stats.LastSnapshots), stats.Cluster)
+	}
+}
+
+func (c *Collector) collectPortForwards(ch chan<- prometheus.Metric) {
+	manager := c.source.GetPortForwardManager()
+	if manager == nil {
+		return
+	}
+
+	for _, status := range manager.Status() {
+		gauge(ch, portForwardUp, boolValue(status.Healthy), status.Cluster, status.Kind, strconv.Itoa(status.LocalPort))
+	}
+}
+
+func gauge(ch chan<- prometheus.Metric, desc *prometheus.Desc, value float64, labels ...string) {
+	ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labels...)
+}
+
+func counter(ch chan<- prometheus.Metric, desc *prometheus.Desc, value float64, labels ...string) {
+	ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value, labels...)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,109 @@
+package exporter
+
+import (
+	"io"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s-hpa-manager/internal/monitoring/engine"
+	"k8s-hpa-manager/internal/monitoring/models"
+	"k8s-hpa-manager/internal/monitoring/portforward"
+	"k8s-hpa-manager/internal/monitoring/storage"
+)
+
+type fakeSource struct {
+	cache       *storage.TimeSeriesCache
+	persistence *storage.Persistence
+	stats       []engine.ClusterScanStats
+}
+
+func (f *fakeSource) GetCache() *storage.TimeSeriesCache   { return f.cache }
+func (f *fakeSource) GetPersistence() *storage.Persistence { return f.persistence }
+func (f *fakeSource) ScanStats() []engine.ClusterScanStats { return f.stats }
+func (f *fakeSource) GetPortForwardManager() *portforward.PortForwardManager {
+	return portforward.NewManager()
+}
+
+func TestHandler(t *testing.T) {
+	persistence, err := storage.NewPersistence(&storage.PersistenceConfig{
+		Enabled: true,
+		DBPath:  filepath.Join(t.TempDir(), "monitoring.db"),
+		MaxAge:  time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create persistence: %v", err)
+	}
+	defer persistence.Close()
+
+	now := time.Now()
+	_, err = persistence.SyncAlerts("aks-prd", []storage.Alert{
+		{Cluster: "aks-prd-admin", Namespace: "api", HPAName: "web", Type: "OOM_KILLED", Severity: models.SeverityCritical, Message: "oom"},
+		{Cluster: "aks-prd-admin", Namespace: "api", HPAName: "worker", Type: "OOM_KILLED", Severity: models.SeverityCritical, Message: "oom"},
+	}, storage.DefaultAlertPolicy(), now)
+	if err != nil {
+		t.Fatalf("SyncAlerts failed: %v", err)
+	}
+
+	cache := storage.NewTimeSeriesCache(nil)
+	cache.Add(&models.HPASnapshot{
+		Timestamp:       now,
+		Cluster:         "aks-prd-admin",
+		Namespace:       "api",
+		Name:            "web",
+		MinReplicas:     2,
+		MaxReplicas:     10,
+		CurrentReplicas: 4,
+		DesiredReplicas: 5,
+		CPUTarget:       70,
+		CPUCurrent:      82.5,
+		BaselineReady:   true,
+	})
+
+	source := &fakeSource{
+		cache:       cache,
+		persistence: persistence,
+		stats: []engine.ClusterScanStats{{
+			Cluster:       "aks-prd",
+			Scans:         3,
+			Errors:        1,
+			TotalDuration: 6 * time.Second,
+			LastDuration:  1500 * time.Millisecond,
+			LastScan:      now,
+			LastSnapshots: 12,
+		}},
+	}
+
+	recorder := httptest.NewRecorder()
+	Handler(source).ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(recorder.Body)
+	if recorder.Code != 200 {
+		t.Fatalf("unexpected status %d: %s", recorder.Code, body)
+	}
+
+	for _, want := range []string{
+		`k8s_hpa_manager_hpa_current_replicas{cluster="aks-prd",hpa="web",namespace="api"} 4`,
+		`k8s_hpa_manager_hpa_desired_replicas{cluster="aks-prd",hpa="web",namespace="api"} 5`,
+		`k8s_hpa_manager_hpa_max_replicas{cluster="aks-prd",hpa="web",namespace="api"} 10`,
+		`k8s_hpa_manager_hpa_cpu_utilization_percent{cluster="aks-prd",hpa="web",namespace="api"} 82.5`,
+		`k8s_hpa_manager_hpa_cpu_target_percent{cluster="aks-prd",hpa="web",namespace="api"} 70`,
+		`k8s_hpa_manager_hpa_baseline_ready{cluster="aks-prd",hpa="web",namespace="api"} 1`,
+		`k8s_hpa_manager_alerts_active{cluster="aks-prd",severity="critical",state="open",type="OOM_KILLED"} 2`,
+		`# TYPE k8s_hpa_manager_scans_total counter`,
+		`k8s_hpa_manager_scans_total{cluster="aks-prd"} 3`,
+		`k8s_hpa_manager_scan_errors_total{cluster="aks-prd"} 1`,
+		`k8s_hpa_manager_scan_duration_seconds_total{cluster="aks-prd"} 6`,
+		`k8s_hpa_manager_scan_last_duration_seconds{cluster="aks-prd"} 1.5`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("expected %q in output:\n%s", want, body)
+		}
+	}
+
+	// Sem target de memória configurado a métrica de target não é exportada
+	if strings.Contains(string(body), "hpa_memory_target_percent{") {
+		t.Error("memory target should be omitted when not configured")
+	}
+}
@@ -5,6 +5,12 @@ import (
 	"time"
 )
 
+// Motivos de terminação/espera de containers (ContainerStateTerminated/Waiting.Reason)
+const (
+	ReasonOOMKilled        = "OOMKilled"        // Container morto por falta de memória
+	ReasonCrashLoopBackOff = "CrashLoopBackOff" // Container reiniciando em loop
+)
+
 // HPASnapshot representa o estado de um HPA em um momento específico
 type HPASnapshot struct {
 	Timestamp time.Time
@@ -34,6 +40,14 @@ type HPASnapshot struct {
 	ScalingActive bool
 	LastScaleTime *time.Time
 
+	// Pod Restarts (K8s API - pods do scale target)
+	RestartCount          int32      `json:"restart_count"`                     // Soma de restartCount de todos os containers
+	OOMKilledCount        int32      `json:"oom_killed_count"`                  // Containers cuja última terminação foi OOMKilled
+	CrashLoopPods         int32      `json:"crash_loop_pods"`                   // Pods com container em CrashLoopBackOff
+	LastTerminationReason string     `json:"last_termination_reason,omitempty"` // Motivo da terminação mais recente (ex: OOMKilled, Error)
+	LastTerminationTime   *time.Time `json:"last_termination_time,omitempty"`
+	LastOOMKilledTime     *time.Time `json:"last_oom_killed_time,omitempty"` // Terminação OOMKilled mais recente (mesmo que não seja a última)
+
 	// === Prometheus Metrics (Real-time & Historical) ===
 	// Current Metrics (Prometheus)
 	CPUCurrent    float64 // % atual (mais preciso que K8s API)
@@ -193,6 +207,8 @@ type UnifiedAlert struct {
 	GeneratorURL string
 	Status       string // "active", "suppressed"
 	SilencedBy   []string
+	AlertName    string            // label alertname
+	Labels       map[string]string // labels originais do alerta
 
 	// Enrichment (from Prometheus + Watchdog)
 	Snapshot    *HPASnapshot  // Estado atual do HPA
@@ -237,6 +253,7 @@ const (
 	AnomalyScalingStuck                          // HPA não consegue escalar
 	AnomalyTargetMiss                            // Current muito acima/abaixo do target
 	AnomalyReplicaOscillation                    // Réplicas mudando rapidamente
+	AnomalyExternalAlert                         // Alerta de fonte externa (Alertmanager)
 )
 
 func (a AnomalyType) String() string {
@@ -259,6 +276,8 @@ func (a AnomalyType) String() string {
 		return "TargetMiss"
 	case AnomalyReplicaOscillation:
 		return "ReplicaOscillation"
+	case AnomalyExternalAlert:
+		return "ExternalAlert"
 	default:
 		return "Unknown"
 	}
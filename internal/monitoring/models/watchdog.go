@@ -0,0 +1,111 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/yaml"
+)
+
+// watchdogFile formato do arquivo do watchdog (YAML); campos omitidos mantêm o default
+//
+//	alerts:
+//	  max_active: 100          # acima do limite, os ativos mais antigos são resolvidos (0 = sem limite)
+//	  auto_ack_resolved: true  # resolvidos sem ack recebem ack automático
+//	alertmanager:
+//	  enabled: true
+//	  auto_discover: true      # port-forward para clusters sem endpoint configurado
+//	  sync_interval: 60        # segundos entre consultas
+//	  endpoints:
+//	    akspriv-prd-admin: http://alertmanager.monitoring:9093
+type watchdogFile struct {
+	Alerts       watchdogAlerts       `json:"alerts,omitempty"`
+	Alertmanager watchdogAlertmanager `json:"alertmanager,omitempty"`
+}
+
+type watchdogAlerts struct {
+	MaxActive       int  `json:"max_active"`
+	AutoAckResolved bool `json:"auto_ack_resolved"`
+}
+
+type watchdogAlertmanager struct {
+	Enabled      bool              `json:"enabled"`
+	AutoDiscover bool              `json:"auto_discover"`
+	SyncInterval int               `json:"sync_interval"`
+	Endpoints    map[string]string `json:"endpoints,omitempty"`
+}
+
+// DefaultWatchdogConfig retorna a configuração usada quando não há arquivo
+func DefaultWatchdogConfig() *WatchdogConfig {
+	return &WatchdogConfig{
+		AlertmanagerEnabled:      true,
+		AlertmanagerAutoDiscover: true,
+		AlertmanagerSyncInterval: 60,
+		AlertmanagerEndpoints:    map[string]string{},
+		MaxActiveAlerts:          100,
+		AutoAckResolvedAlerts:    false,
+	}
+}
+
+// DefaultWatchdogConfigPath retorna ~/.k8s-hpa-manager/watchdog.yaml
+func DefaultWatchdogConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	return filepath.Join(home, ".k8s-hpa-manager", "watchdog.yaml")
+}
+
+// LoadWatchdogConfig lê o arquivo; retorna DefaultWatchdogConfig quando o arquivo não existe
+func LoadWatchdogConfig(path string) (*WatchdogConfig, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultWatchdogConfig(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watchdog config: %w", err)
+	}
+	return ParseWatchdogConfig(data)
+}
+
+// ParseWatchdogConfig valida o YAML e aplica sobre os defaults
+func ParseWatchdogConfig(data []byte) (*WatchdogConfig, error) {
+	cfg := DefaultWatchdogConfig()
+	file := watchdogFile{
+		Alerts: watchdogAlerts{
+			MaxActive:       cfg.MaxActiveAlerts,
+			AutoAckResolved: cfg.AutoAckResolvedAlerts,
+		},
+		Alertmanager: watchdogAlertmanager{
+			Enabled:      cfg.AlertmanagerEnabled,
+			AutoDiscover: cfg.AlertmanagerAutoDiscover,
+			SyncInterval: cfg.AlertmanagerSyncInterval,
+		},
+	}
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid watchdog config: %w", err)
+	}
+	if file.Alerts.MaxActive < 0 {
+		return nil, fmt.Errorf("invalid watchdog config: alerts.max_active must be >= 0")
+	}
+	if file.Alertmanager.SyncInterval <= 0 {
+		return nil, fmt.Errorf("invalid watchdog config: alertmanager.sync_interval must be > 0")
+	}
+	for cluster, endpoint := range file.Alertmanager.Endpoints {
+		if endpoint == "" {
+			return nil, fmt.Errorf("invalid watchdog config: empty alertmanager endpoint for %q", cluster)
+		}
+	}
+
+	cfg.MaxActiveAlerts = file.Alerts.MaxActive
+	cfg.AutoAckResolvedAlerts = file.Alerts.AutoAckResolved
+	cfg.AlertmanagerEnabled = file.Alertmanager.Enabled
+	cfg.AlertmanagerAutoDiscover = file.Alertmanager.AutoDiscover
+	cfg.AlertmanagerSyncInterval = file.Alertmanager.SyncInterval
+	for cluster, endpoint := range file.Alertmanager.Endpoints {
+		cfg.AlertmanagerEndpoints[cluster] = endpoint
+	}
+	return cfg, nil
+}
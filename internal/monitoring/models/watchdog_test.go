@@ -0,0 +1,64 @@
+package models
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadWatchdogConfigMissingFileUsesDefaults(t *testing.T) {
+	cfg, err := LoadWatchdogConfig(filepath.Join(t.TempDir(), "watchdog.yaml"))
+	if err != nil {
+		t.Fatalf("LoadWatchdogConfig: %v", err)
+	}
+	if cfg.MaxActiveAlerts != 100 || cfg.AutoAckResolvedAlerts {
+		t.Errorf("expected defaults, got max=%d autoAck=%v", cfg.MaxActiveAlerts, cfg.AutoAckResolvedAlerts)
+	}
+	if !cfg.AlertmanagerEnabled || !cfg.AlertmanagerAutoDiscover || cfg.AlertmanagerSyncInterval != 60 {
+		t.Errorf("expected alertmanager defaults, got enabled=%v autoDiscover=%v sync=%d",
+			cfg.AlertmanagerEnabled, cfg.AlertmanagerAutoDiscover, cfg.AlertmanagerSyncInterval)
+	}
+}
+
+func TestParseWatchdogConfig(t *testing.T) {
+	content := `
+alerts:
+  auto_ack_resolved: true
+alertmanager:
+  auto_discover: false
+  endpoints:
+    aks-prd-admin: http://alertmanager.monitoring:9093
+`
+	cfg, err := ParseWatchdogConfig([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseWatchdogConfig: %v", err)
+	}
+	if cfg.MaxActiveAlerts != 100 {
+		t.Errorf("omitted max_active should keep the default, got %d", cfg.MaxActiveAlerts)
+	}
+	if !cfg.AutoAckResolvedAlerts {
+		t.Error("expected auto_ack_resolved from file")
+	}
+	if !cfg.AlertmanagerEnabled || cfg.AlertmanagerAutoDiscover || cfg.AlertmanagerSyncInterval != 60 {
+		t.Errorf("unexpected alertmanager settings: enabled=%v autoDiscover=%v sync=%d",
+			cfg.AlertmanagerEnabled, cfg.AlertmanagerAutoDiscover, cfg.AlertmanagerSyncInterval)
+	}
+	if cfg.AlertmanagerEndpoints["aks-prd-admin"] != "http://alertmanager.monitoring:9093" {
+		t.Errorf("unexpected endpoints: %v", cfg.AlertmanagerEndpoints)
+	}
+}
+
+func TestParseWatchdogConfigInvalid(t *testing.T) {
+	tests := map[string]string{
+		"limite negativo":    "alerts: {max_active: -1}",
+		"chave desconhecida": "alerts: {max: 10}",
+		"intervalo zerado":   "alertmanager: {sync_interval: 0}",
+		"endpoint vazio":     "alertmanager: {endpoints: {aks-prd: \"\"}}",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseWatchdogConfig([]byte(content)); err == nil {
+				t.Errorf("expected error for %q", content)
+			}
+		})
+	}
+}
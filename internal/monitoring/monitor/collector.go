@@ -31,6 +31,7 @@ type CollectorConfig struct {
 	EnablePrometheus  bool          // Enable Prometheus enrichment
 	DetectorConfig    *analyzer.DetectorConfig
 	CacheConfig       *storage.CacheConfig
+	Rules             *analyzer.RuleStore // Overrides e regras customizadas (opcional)
 }
 
 // DefaultCollectorConfig retorna configuração padrão
@@ -69,6 +70,9 @@ func NewCollector(cluster *models.ClusterInfo, prometheusEndpoint string, config
 
 	// Cria detector
 	detector := analyzer.NewDetector(cache, config.DetectorConfig)
+	if config.Rules != nil {
+		detector.SetRules(config.Rules)
+	}
 
 	collector := &Collector{
 		k8sClient: k8sClient,
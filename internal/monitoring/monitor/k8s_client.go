@@ -207,6 +207,16 @@ func (k *K8sClient) CollectHPASnapshot(ctx context.Context, hpa *autoscalingv2.H
 					}
 				}
 			}
+
+			// Restarts e última terminação dos pods (OOMKilled/CrashLoopBackOff)
+			if err := CollectPodRestarts(ctx, k.Clientset, snapshot, deployment.Spec.Selector); err != nil {
+				log.Warn().
+					Err(err).
+					Str("cluster", k.cluster.Name).
+					Str("namespace", hpa.Namespace).
+					Str("hpa", hpa.Name).
+					Msg("Failed to collect pod restarts for HPA")
+			}
 		}
 	}
 
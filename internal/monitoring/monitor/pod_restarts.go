@@ -0,0 +1,88 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s-hpa-manager/internal/monitoring/models"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// CollectPodRestarts lista os pods do scale target (via selector) e preenche
+// RestartCount, OOMKilledCount, CrashLoopPods, a última terminação e o último OOMKilled no snapshot
+func CollectPodRestarts(
+	ctx context.Context,
+	clientset kubernetes.Interface,
+	snapshot *models.HPASnapshot,
+	selector *metav1.LabelSelector,
+) error {
+	if selector == nil {
+		return fmt.Errorf("scale target of %s/%s has no pod selector", snapshot.Namespace, snapshot.Name)
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return fmt.Errorf("invalid pod selector for %s/%s: %w", snapshot.Namespace, snapshot.Name, err)
+	}
+
+	pods, err := clientset.CoreV1().Pods(snapshot.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list pods for %s/%s: %w", snapshot.Namespace, snapshot.Name, err)
+	}
+
+	ApplyPodRestarts(snapshot, pods.Items)
+	return nil
+}
+
+// ApplyPodRestarts agrega os status dos containers dos pods no snapshot
+func ApplyPodRestarts(snapshot *models.HPASnapshot, pods []corev1.Pod) {
+	snapshot.RestartCount = 0
+	snapshot.OOMKilledCount = 0
+	snapshot.CrashLoopPods = 0
+	snapshot.LastTerminationReason = ""
+	snapshot.LastTerminationTime = nil
+	snapshot.LastOOMKilledTime = nil
+
+	var lastTermination, lastOOMKilled time.Time
+	for _, pod := range pods {
+		crashLooping := false
+		for _, status := range pod.Status.ContainerStatuses {
+			snapshot.RestartCount += status.RestartCount
+
+			if status.State.Waiting != nil && status.State.Waiting.Reason == models.ReasonCrashLoopBackOff {
+				crashLooping = true
+			}
+
+			terminated := status.LastTerminationState.Terminated
+			if terminated == nil {
+				continue
+			}
+			finishedAt := terminated.FinishedAt.Time
+			if terminated.Reason == models.ReasonOOMKilled {
+				snapshot.OOMKilledCount++
+				if finishedAt.After(lastOOMKilled) {
+					lastOOMKilled = finishedAt
+				}
+			}
+			if finishedAt.After(lastTermination) {
+				lastTermination = finishedAt
+				snapshot.LastTerminationReason = terminated.Reason
+			}
+		}
+		if crashLooping {
+			snapshot.CrashLoopPods++
+		}
+	}
+
+	if !lastTermination.IsZero() {
+		snapshot.LastTerminationTime = &lastTermination
+	}
+	if !lastOOMKilled.IsZero() {
+		snapshot.LastOOMKilledTime = &lastOOMKilled
+	}
+}
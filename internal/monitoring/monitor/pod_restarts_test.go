@@ -0,0 +1,77 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s-hpa-manager/internal/monitoring/models"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testPod(name string, labels map[string]string, statuses ...corev1.ContainerStatus) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "api", Labels: labels},
+		Status:     corev1.PodStatus{ContainerStatuses: statuses},
+	}
+}
+
+func terminatedStatus(restarts int32, reason string, finishedAt time.Time) corev1.ContainerStatus {
+	return corev1.ContainerStatus{
+		Name:         "app",
+		RestartCount: restarts,
+		LastTerminationState: corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{Reason: reason, FinishedAt: metav1.NewTime(finishedAt)},
+		},
+	}
+}
+
+// TestCollectPodRestarts testa a agregação de restarts dos pods do scale target
+func TestCollectPodRestarts(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	labels := map[string]string{"app": "web"}
+
+	crashLooping := terminatedStatus(7, models.ReasonOOMKilled, now.Add(-time.Minute))
+	crashLooping.State.Waiting = &corev1.ContainerStateWaiting{Reason: models.ReasonCrashLoopBackOff}
+
+	clientset := fake.NewSimpleClientset(
+		testPod("web-1", labels, crashLooping),
+		testPod("web-2", labels, terminatedStatus(2, "Error", now.Add(-10*time.Minute)), corev1.ContainerStatus{Name: "sidecar"}),
+		testPod("other-1", map[string]string{"app": "other"}, terminatedStatus(50, models.ReasonOOMKilled, now)),
+	)
+
+	snapshot := &models.HPASnapshot{Namespace: "api", Name: "web"}
+	err := CollectPodRestarts(context.Background(), clientset, snapshot, &metav1.LabelSelector{MatchLabels: labels})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if snapshot.RestartCount != 9 {
+		t.Errorf("expected RestartCount 9, got %d", snapshot.RestartCount)
+	}
+	if snapshot.OOMKilledCount != 1 || snapshot.CrashLoopPods != 1 {
+		t.Errorf("expected 1 OOMKilled and 1 crash loop pod, got %d/%d", snapshot.OOMKilledCount, snapshot.CrashLoopPods)
+	}
+	if snapshot.LastTerminationReason != models.ReasonOOMKilled || !snapshot.LastTerminationTime.Equal(now.Add(-time.Minute)) {
+		t.Errorf("unexpected last termination: %s at %v", snapshot.LastTerminationReason, snapshot.LastTerminationTime)
+	}
+	if snapshot.LastOOMKilledTime == nil || !snapshot.LastOOMKilledTime.Equal(now.Add(-time.Minute)) {
+		t.Errorf("unexpected last OOMKilled time: %v", snapshot.LastOOMKilledTime)
+	}
+
+	// Error posterior em outro container não apaga o horário do OOMKilled
+	ApplyPodRestarts(snapshot, []corev1.Pod{
+		*testPod("web-1", labels, terminatedStatus(1, models.ReasonOOMKilled, now.Add(-5*time.Minute))),
+		*testPod("web-2", labels, terminatedStatus(1, "Error", now)),
+	})
+	if snapshot.LastTerminationReason != "Error" || snapshot.LastOOMKilledTime == nil ||
+		!snapshot.LastOOMKilledTime.Equal(now.Add(-5*time.Minute)) {
+		t.Errorf("unexpected terminations: last %s, OOMKilled at %v", snapshot.LastTerminationReason, snapshot.LastOOMKilledTime)
+	}
+
+	if err := CollectPodRestarts(context.Background(), clientset, snapshot, nil); err == nil {
+		t.Error("expected error for nil selector")
+	}
+}
@@ -0,0 +1,220 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"k8s-hpa-manager/internal/monitoring/analyzer"
+	"k8s-hpa-manager/internal/monitoring/models"
+	"sigs.k8s.io/yaml"
+)
+
+// Tipos de sink suportados
+const (
+	SinkWebhook = "webhook" // JSON genérico
+	SinkSlack   = "slack"   // Slack incoming webhook (compatível com Mattermost/Rocket.Chat)
+	SinkTeams   = "teams"   // Microsoft Teams incoming webhook (MessageCard)
+	SinkSMTP    = "smtp"    // Email
+)
+
+// Config formato do arquivo de notificações (YAML)
+//
+//	cooldown: 5m       # dedup por HPA+tipo (default: AlertCooldown do detector)
+//	group_wait: 30s    # agrupa anomalias do mesmo cluster antes de enviar
+//	sinks:
+//	  - {name: ops, type: slack, url: "https://hooks.slack.com/services/..."}
+//	  - {name: oncall, type: smtp, smtp: {host: smtp.empresa.com, port: 587, from: hpa@empresa.com, to: [oncall@empresa.com]}}
+//	routes:            # sem rotas = todas as anomalias para todos os sinks
+//	  - match: {severities: [critical], clusters: ["*-prd"]}
+//	    sinks: [ops, oncall]
+//	  - match: {types: [OOM_KILLED, CRASH_LOOP]}
+//	    sinks: [ops]
+type Config struct {
+	Cooldown  analyzer.Duration `json:"cooldown,omitempty"`
+	GroupWait analyzer.Duration `json:"group_wait,omitempty"`
+	Sinks     []SinkConfig      `json:"sinks"`
+	Routes    []Route           `json:"routes,omitempty"`
+}
+
+// SinkConfig destino de notificações
+type SinkConfig struct {
+	Name    string            `json:"name"`
+	Type    string            `json:"type"`
+	URL     string            `json:"url,omitempty"`     // webhook, slack, teams
+	Headers map[string]string `json:"headers,omitempty"` // webhook (ex: Authorization)
+	SMTP    *SMTPConfig       `json:"smtp,omitempty"`
+}
+
+// SMTPConfig configuração do sink de email
+type SMTPConfig struct {
+	Host     string   `json:"host"`
+	Port     int      `json:"port,omitempty"` // default: 25
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+}
+
+// Route envia as anomalias que casam com Match para os sinks listados
+type Route struct {
+	Match RouteMatch `json:"match,omitempty"`
+	Sinks []string   `json:"sinks"`
+}
+
+// RouteMatch filtros de uma rota (vazio = qualquer)
+type RouteMatch struct {
+	Severities []string               `json:"severities,omitempty"` // info, warning, critical
+	Clusters   []string               `json:"clusters,omitempty"`   // glob, sem sufixo -admin
+	Types      []analyzer.AnomalyType `json:"types,omitempty"`
+}
+
+// DefaultConfigPath retorna ~/.k8s-hpa-manager/notifications.yaml
+func DefaultConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	return filepath.Join(home, ".k8s-hpa-manager", "notifications.yaml")
+}
+
+// LoadConfig lê e valida o arquivo; retorna nil (sem erro) quando o arquivo não existe
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notifications config: %w", err)
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig valida a configuração YAML
+func ParseConfig(data []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid notifications config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate verifica nomes, tipos e referências das rotas
+func (c *Config) Validate() error {
+	names := make(map[string]bool, len(c.Sinks))
+	for _, sink := range c.Sinks {
+		if sink.Name == "" {
+			return fmt.Errorf("sink without name")
+		}
+		if names[sink.Name] {
+			return fmt.Errorf("duplicate sink name %q", sink.Name)
+		}
+		names[sink.Name] = true
+
+		switch sink.Type {
+		case SinkWebhook, SinkSlack, SinkTeams:
+			if sink.URL == "" {
+				return fmt.Errorf("sink %q: url is required", sink.Name)
+			}
+		case SinkSMTP:
+			if sink.SMTP == nil || sink.SMTP.Host == "" || sink.SMTP.From == "" || len(sink.SMTP.To) == 0 {
+				return fmt.Errorf("sink %q: smtp host, from and to are required", sink.Name)
+			}
+		default:
+			return fmt.Errorf("sink %q: unknown type %q (use webhook, slack, teams or smtp)", sink.Name, sink.Type)
+		}
+	}
+
+	for i, route := range c.Routes {
+		if len(route.Sinks) == 0 {
+			return fmt.Errorf("route %d: no sinks", i+1)
+		}
+		for _, name := range route.Sinks {
+			if !names[name] {
+				return fmt.Errorf("route %d: unknown sink %q", i+1, name)
+			}
+		}
+		for _, severity := range route.Match.Severities {
+			if _, ok := parseSeverity(severity); !ok {
+				return fmt.Errorf("route %d: invalid severity %q", i+1, severity)
+			}
+		}
+		for _, pattern := range route.Match.Clusters {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("route %d: invalid cluster pattern %q", i+1, pattern)
+			}
+		}
+	}
+	return nil
+}
+
+// matches verifica se a anomalia casa com a rota
+func (m RouteMatch) matches(anomaly analyzer.Anomaly) bool {
+	if len(m.Severities) > 0 {
+		found := false
+		for _, value := range m.Severities {
+			severity, _ := parseSeverity(value)
+			found = found || severity == anomaly.Severity
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(m.Clusters) > 0 {
+		cluster := strings.TrimSuffix(anomaly.Cluster, "-admin")
+		found := false
+		for _, pattern := range m.Clusters {
+			ok, _ := path.Match(strings.TrimSuffix(pattern, "-admin"), cluster)
+			found = found || ok
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(m.Types) > 0 {
+		found := false
+		for _, anomalyType := range m.Types {
+			found = found || anomalyType == anomaly.Type
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func parseSeverity(value string) (models.AlertSeverity, bool) {
+	switch strings.ToLower(value) {
+	case "critical":
+		return models.SeverityCritical, true
+	case "warning", "warn":
+		return models.SeverityWarning, true
+	case "info":
+		return models.SeverityInfo, true
+	default:
+		return 0, false
+	}
+}
+
+func (c *Config) cooldown() time.Duration {
+	if c.Cooldown > 0 {
+		return time.Duration(c.Cooldown)
+	}
+	return analyzer.DefaultDetectorConfig().AlertCooldown
+}
+
+func (c *Config) groupWait() time.Duration {
+	if c.GroupWait > 0 {
+		return time.Duration(c.GroupWait)
+	}
+	return 30 * time.Second
+}
@@ -0,0 +1,264 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"k8s-hpa-manager/internal/monitoring/analyzer"
+	"k8s-hpa-manager/internal/monitoring/models"
+)
+
+// Sink destino de notificações
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, batch Batch) error
+}
+
+// Batch anomalias agrupadas de um cluster enviadas em uma única notificação
+type Batch struct {
+	Cluster   string
+	Anomalies []analyzer.Anomaly
+}
+
+// Severity maior severidade do grupo
+func (b Batch) Severity() models.AlertSeverity {
+	severity := models.SeverityInfo
+	for _, anomaly := range b.Anomalies {
+		if anomaly.Severity > severity {
+			severity = anomaly.Severity
+		}
+	}
+	return severity
+}
+
+// Title título da notificação (ex: "[Critical] 3 anomalias em aks-prd")
+func (b Batch) Title() string {
+	if len(b.Anomalies) == 1 {
+		anomaly := b.Anomalies[0]
+		return fmt.Sprintf("[%s] %s em %s/%s (%s)",
+			anomaly.Severity, anomaly.Type, anomaly.Namespace, anomaly.HPAName, b.Cluster)
+	}
+	return fmt.Sprintf("[%s] %d anomalias em %s", b.Severity(), len(b.Anomalies), b.Cluster)
+}
+
+// Notifier roteia anomalias para sinks com deduplicação (cooldown) e agrupamento por cluster
+type Notifier struct {
+	config *Config
+	sinks  map[string]Sink
+	order  []string // ordem dos sinks no arquivo (envio determinístico)
+
+	mu       sync.Mutex
+	pending  map[string]*pendingBatch // sink/cluster -> anomalias aguardando group_wait
+	lastSent map[string]time.Time     // cluster/namespace/hpa/tipo -> último envio
+	now      func() time.Time
+}
+
+type pendingBatch struct {
+	sink    string
+	first   time.Time
+	batch   Batch
+	members map[string]bool
+}
+
+// New cria o notifier com os sinks da configuração
+func New(cfg *Config) (*Notifier, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	n := &Notifier{
+		config:   cfg,
+		sinks:    make(map[string]Sink, len(cfg.Sinks)),
+		pending:  make(map[string]*pendingBatch),
+		lastSent: make(map[string]time.Time),
+		now:      time.Now,
+	}
+	for _, sinkConfig := range cfg.Sinks {
+		sink, err := NewSink(sinkConfig)
+		if err != nil {
+			return nil, err
+		}
+		n.sinks[sinkConfig.Name] = sink
+		n.order = append(n.order, sinkConfig.Name)
+	}
+	return n, nil
+}
+
+// NewSink cria o sink correspondente ao tipo configurado
+func NewSink(cfg SinkConfig) (Sink, error) {
+	switch cfg.Type {
+	case SinkWebhook:
+		return NewWebhookSink(cfg.Name, cfg.URL, cfg.Headers), nil
+	case SinkSlack:
+		return NewSlackSink(cfg.Name, cfg.URL), nil
+	case SinkTeams:
+		return NewTeamsSink(cfg.Name, cfg.URL), nil
+	case SinkSMTP:
+		return NewSMTPSink(cfg.Name, *cfg.SMTP), nil
+	default:
+		return nil, fmt.Errorf("unknown sink type %q", cfg.Type)
+	}
+}
+
+// Notify enfileira a anomalia para os sinks das rotas que casam.
+// Anomalias repetidas (mesmo HPA e tipo) dentro do cooldown são descartadas.
+func (n *Notifier) Notify(anomaly analyzer.Anomaly) {
+	sinks := n.route(anomaly)
+	if len(sinks) == 0 {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := n.now()
+	key := dedupKey(anomaly)
+	if last, ok := n.lastSent[key]; ok && now.Sub(last) < n.config.cooldown() {
+		return
+	}
+	n.lastSent[key] = now
+
+	cluster := strings.TrimSuffix(anomaly.Cluster, "-admin")
+	for _, sink := range sinks {
+		groupKey := sink + "/" + cluster
+		group, ok := n.pending[groupKey]
+		if !ok {
+			group = &pendingBatch{
+				sink:    sink,
+				first:   now,
+				batch:   Batch{Cluster: cluster},
+				members: make(map[string]bool),
+			}
+			n.pending[groupKey] = group
+		}
+		if !group.members[key] {
+			group.members[key] = true
+			group.batch.Anomalies = append(group.batch.Anomalies, anomaly)
+		}
+	}
+}
+
+// Run envia os grupos cujo group_wait expirou até o contexto ser cancelado (pendentes são enviados ao sair)
+func (n *Notifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			n.Flush(flushCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			n.send(ctx, n.due(false))
+		}
+	}
+}
+
+// Flush envia imediatamente todos os grupos pendentes
+func (n *Notifier) Flush(ctx context.Context) {
+	n.send(ctx, n.due(true))
+}
+
+// due remove e retorna os grupos prontos para envio
+func (n *Notifier) due(all bool) []*pendingBatch {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := n.now()
+	var ready []*pendingBatch
+	for key, group := range n.pending {
+		if all || now.Sub(group.first) >= n.config.groupWait() {
+			ready = append(ready, group)
+			delete(n.pending, key)
+		}
+	}
+
+	// Cooldown expirado não precisa ficar em memória
+	for key, last := range n.lastSent {
+		if now.Sub(last) >= n.config.cooldown() {
+			delete(n.lastSent, key)
+		}
+	}
+
+	sort.Slice(ready, func(i, j int) bool {
+		if ready[i].sink != ready[j].sink {
+			return n.sinkIndex(ready[i].sink) < n.sinkIndex(ready[j].sink)
+		}
+		return ready[i].batch.Cluster < ready[j].batch.Cluster
+	})
+	return ready
+}
+
+func (n *Notifier) send(ctx context.Context, groups []*pendingBatch) {
+	for _, group := range groups {
+		sendCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		err := n.sinks[group.sink].Send(sendCtx, group.batch)
+		cancel()
+
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("sink", group.sink).
+				Str("cluster", group.batch.Cluster).
+				Int("anomalies", len(group.batch.Anomalies)).
+				Msg("Falha ao enviar notificação")
+			continue
+		}
+		log.Info().
+			Str("sink", group.sink).
+			Str("cluster", group.batch.Cluster).
+			Int("anomalies", len(group.batch.Anomalies)).
+			Msg("Notificação enviada")
+	}
+}
+
+// route retorna os sinks de todas as rotas que casam (sem rotas = todos os sinks)
+func (n *Notifier) route(anomaly analyzer.Anomaly) []string {
+	if len(n.config.Routes) == 0 {
+		return n.order
+	}
+
+	selected := make(map[string]bool)
+	for _, route := range n.config.Routes {
+		if route.Match.matches(anomaly) {
+			for _, sink := range route.Sinks {
+				selected[sink] = true
+			}
+		}
+	}
+
+	var sinks []string
+	for _, name := range n.order {
+		if selected[name] {
+			sinks = append(sinks, name)
+		}
+	}
+	return sinks
+}
+
+func (n *Notifier) sinkIndex(name string) int {
+	for i, sink := range n.order {
+		if sink == name {
+			return i
+		}
+	}
+	return len(n.order)
+}
+
+func dedupKey(anomaly analyzer.Anomaly) string {
+	return strings.Join([]string{
+		strings.TrimSuffix(anomaly.Cluster, "-admin"),
+		anomaly.Namespace,
+		anomaly.HPAName,
+		string(anomaly.Type),
+		anomaly.Rule,
+	}, "/")
+}
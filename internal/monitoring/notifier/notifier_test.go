@@ -0,0 +1,305 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s-hpa-manager/internal/monitoring/analyzer"
+	"k8s-hpa-manager/internal/monitoring/models"
+)
+
+const testConfigYAML = `
+cooldown: 5m
+group_wait: 30s
+sinks:
+  - {name: ops, type: slack, url: "http://slack.invalid"}
+  - {name: oncall, type: webhook, url: "http://hook.invalid"}
+routes:
+  - match: {severities: [critical], clusters: ["*-prd"]}
+    sinks: [ops, oncall]
+  - match: {types: [CPU_SPIKE]}
+    sinks: [ops]
+`
+
+// recordingSink guarda os grupos recebidos
+type recordingSink struct {
+	name    string
+	mu      sync.Mutex
+	batches []Batch
+}
+
+func (s *recordingSink) Name() string { return s.name }
+
+func (s *recordingSink) Send(ctx context.Context, batch Batch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, batch)
+	return nil
+}
+
+func newTestNotifier(t *testing.T, clock *time.Time) (*Notifier, map[string]*recordingSink) {
+	t.Helper()
+	cfg, err := ParseConfig([]byte(testConfigYAML))
+	if err != nil {
+		t.Fatalf("unexpected config error: %v", err)
+	}
+	n, err := New(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sinks := map[string]*recordingSink{}
+	for name := range n.sinks {
+		sinks[name] = &recordingSink{name: name}
+		n.sinks[name] = sinks[name]
+	}
+	n.now = func() time.Time { return *clock }
+	return n, sinks
+}
+
+func testAnomaly(cluster, hpa string, anomalyType analyzer.AnomalyType, severity models.AlertSeverity) analyzer.Anomaly {
+	return analyzer.Anomaly{
+		Type:      anomalyType,
+		Severity:  severity,
+		Cluster:   cluster,
+		Namespace: "api",
+		HPAName:   hpa,
+		Timestamp: time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC),
+		Message:   string(anomalyType) + " em " + hpa,
+		Actions:   []string{"Verificar logs"},
+	}
+}
+
+func TestParseConfigInvalid(t *testing.T) {
+	tests := map[string]string{
+		"tipo desconhecido":   "sinks: [{name: a, type: pager, url: x}]",
+		"sem url":             "sinks: [{name: a, type: slack}]",
+		"smtp incompleto":     "sinks: [{name: a, type: smtp, smtp: {host: localhost}}]",
+		"sink inexistente":    "sinks: [{name: a, type: slack, url: x}]\nroutes: [{sinks: [b]}]",
+		"severidade inválida": "sinks: [{name: a, type: slack, url: x}]\nroutes: [{match: {severities: [page]}, sinks: [a]}]",
+		"chave desconhecida":  "sinks: [{name: a, type: slack, url: x, channel: ops}]",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseConfig([]byte(content)); err == nil {
+				t.Errorf("expected error for %q", content)
+			}
+		})
+	}
+}
+
+func TestNotifierRoutingGroupingAndDedup(t *testing.T) {
+	clock := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	n, sinks := newTestNotifier(t, &clock)
+
+	n.Notify(testAnomaly("aks-prd-admin", "web", analyzer.AnomalyTypeOOMKilled, models.SeverityCritical))
+	n.Notify(testAnomaly("aks-prd", "worker", analyzer.AnomalyTypeCrashLoop, models.SeverityCritical))
+	n.Notify(testAnomaly("aks-hlg", "web", analyzer.AnomalyTypeCPUSpike, models.SeverityWarning))
+	n.Notify(testAnomaly("aks-hlg", "web", analyzer.AnomalyTypeOOMKilled, models.SeverityCritical)) // sem rota
+	n.Notify(testAnomaly("aks-prd", "web", analyzer.AnomalyTypeOOMKilled, models.SeverityCritical)) // duplicada
+
+	// Antes do group_wait nada é enviado
+	clock = clock.Add(10 * time.Second)
+	n.send(context.Background(), n.due(false))
+	if len(sinks["ops"].batches) != 0 {
+		t.Fatalf("nothing should be sent before group_wait, got %+v", sinks["ops"].batches)
+	}
+
+	clock = clock.Add(30 * time.Second)
+	n.send(context.Background(), n.due(false))
+
+	ops := sinks["ops"].batches
+	if len(ops) != 2 || ops[0].Cluster != "aks-hlg" || ops[1].Cluster != "aks-prd" {
+		t.Fatalf("expected one batch per cluster for ops, got %+v", ops)
+	}
+	if len(ops[1].Anomalies) != 2 || ops[1].Title() != "[Critical] 2 anomalias em aks-prd" {
+		t.Errorf("unexpected PRD batch: %s (%d)", ops[1].Title(), len(ops[1].Anomalies))
+	}
+	if ops[0].Title() != "[Warning] CPU_SPIKE em api/web (aks-hlg)" {
+		t.Errorf("unexpected HLG title: %s", ops[0].Title())
+	}
+
+	oncall := sinks["oncall"].batches
+	if len(oncall) != 1 || oncall[0].Cluster != "aks-prd" || len(oncall[0].Anomalies) != 2 {
+		t.Errorf("oncall should only receive critical PRD anomalies, got %+v", oncall)
+	}
+
+	// Cooldown: repetida em 4min é descartada, após 5min volta a notificar
+	clock = clock.Add(4 * time.Minute)
+	n.Notify(testAnomaly("aks-prd", "web", analyzer.AnomalyTypeOOMKilled, models.SeverityCritical))
+	n.Flush(context.Background())
+	if len(sinks["oncall"].batches) != 1 {
+		t.Error("anomaly inside cooldown should be dropped")
+	}
+
+	clock = clock.Add(2 * time.Minute)
+	n.Notify(testAnomaly("aks-prd", "web", analyzer.AnomalyTypeOOMKilled, models.SeverityCritical))
+	n.Flush(context.Background())
+	if len(sinks["oncall"].batches) != 2 {
+		t.Error("anomaly after cooldown should be notified again")
+	}
+}
+
+func TestHTTPSinks(t *testing.T) {
+	var mu sync.Mutex
+	bodies := map[string]map[string]interface{}{}
+	headers := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		bodies[r.URL.Path] = body
+		headers[r.URL.Path] = r.Header.Get("Authorization")
+		mu.Unlock()
+		if r.URL.Path == "/fail" {
+			http.Error(w, "invalid_token", http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	anomaly := testAnomaly("aks-prd", "web", analyzer.AnomalyTypeCustomRule, models.SeverityCritical)
+	anomaly.Rule = "memoria-alta"
+	batch := Batch{Cluster: "aks-prd", Anomalies: []analyzer.Anomaly{anomaly}}
+	ctx := context.Background()
+
+	if err := NewWebhookSink("hook", server.URL+"/hook", map[string]string{"Authorization": "Bearer x"}).Send(ctx, batch); err != nil {
+		t.Fatalf("webhook: %v", err)
+	}
+	if err := NewSlackSink("slack", server.URL+"/slack").Send(ctx, batch); err != nil {
+		t.Fatalf("slack: %v", err)
+	}
+	if err := NewTeamsSink("teams", server.URL+"/teams").Send(ctx, batch); err != nil {
+		t.Fatalf("teams: %v", err)
+	}
+	if err := NewSlackSink("slack", server.URL+"/fail").Send(ctx, batch); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("expected status error, got %v", err)
+	}
+
+	hook := bodies["/hook"]
+	if hook["severity"] != "critical" || hook["count"] != float64(1) || headers["/hook"] != "Bearer x" {
+		t.Errorf("unexpected webhook payload: %+v (auth %q)", hook, headers["/hook"])
+	}
+	if first := hook["anomalies"].([]interface{})[0].(map[string]interface{}); first["rule"] != "memoria-alta" || first["hpa_name"] != "web" {
+		t.Errorf("unexpected webhook anomaly: %+v", first)
+	}
+
+	slack := bodies["/slack"]
+	attachment := slack["attachments"].([]interface{})[0].(map[string]interface{})
+	if !strings.HasPrefix(slack["text"].(string), "*[Critical]") || attachment["color"] != "#D32F2F" ||
+		attachment["title"] != "CUSTOM_RULE (memoria-alta) — api/web" {
+		t.Errorf("unexpected slack payload: %+v", slack)
+	}
+
+	teams := bodies["/teams"]
+	if teams["@type"] != "MessageCard" || teams["themeColor"] != "D32F2F" || len(teams["sections"].([]interface{})) != 1 {
+		t.Errorf("unexpected teams payload: %+v", teams)
+	}
+}
+
+// startSMTPStub servidor SMTP mínimo que guarda a mensagem recebida
+func startSMTPStub(t *testing.T) (string, int, <-chan string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP stub")
+
+		var data strings.Builder
+		inData := false
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					messages <- data.String()
+					reply("250 OK")
+					continue
+				}
+				data.WriteString(line)
+				continue
+			}
+
+			switch command := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case command == "DATA":
+				inData = true
+				reply("354 End data with <CR><LF>.<CR><LF>")
+			case command == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, messages
+}
+
+func TestSMTPSink(t *testing.T) {
+	host, port, messages := startSMTPStub(t)
+	sink := NewSMTPSink("email", SMTPConfig{
+		Host: host,
+		Port: port,
+		From: "hpa@empresa.com",
+		To:   []string{"oncall@empresa.com", "sre@empresa.com"},
+	})
+
+	batch := Batch{Cluster: "aks-prd", Anomalies: []analyzer.Anomaly{
+		testAnomaly("aks-prd", "web", analyzer.AnomalyTypeOOMKilled, models.SeverityCritical),
+	}}
+	if err := sink.Send(context.Background(), batch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case message := <-messages:
+		for _, want := range []string{
+			"Subject: [Critical] OOM_KILLED em api/web (aks-prd)",
+			"To: oncall@empresa.com, sre@empresa.com",
+			"OOM_KILLED em web",
+			"  - Verificar logs",
+		} {
+			if !strings.Contains(message, want) {
+				t.Errorf("expected %q in message:\n%s", want, message)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("smtp stub did not receive message")
+	}
+
+	// Porta sem servidor
+	closed, _ := net.Listen("tcp", "127.0.0.1:0")
+	closedPort := closed.Addr().(*net.TCPAddr).Port
+	closed.Close()
+	unreachable := NewSMTPSink("email", SMTPConfig{Host: "127.0.0.1", Port: closedPort, From: "a@b", To: []string{"c@d"}})
+	if err := unreachable.Send(context.Background(), batch); err == nil {
+		t.Error("expected error for unreachable server")
+	}
+}
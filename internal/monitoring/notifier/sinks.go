@@ -0,0 +1,213 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s-hpa-manager/internal/monitoring/analyzer"
+	"k8s-hpa-manager/internal/monitoring/models"
+)
+
+// WebhookSink envia o grupo como JSON genérico
+type WebhookSink struct {
+	name       string
+	url        string
+	headers    map[string]string
+	httpClient *http.Client
+}
+
+// WebhookPayload corpo enviado pelo WebhookSink
+type WebhookPayload struct {
+	Title     string           `json:"title"`
+	Cluster   string           `json:"cluster"`
+	Severity  string           `json:"severity"`
+	Count     int              `json:"count"`
+	Anomalies []WebhookAnomaly `json:"anomalies"`
+}
+
+// WebhookAnomaly anomalia no payload do webhook
+type WebhookAnomaly struct {
+	Type        string   `json:"type"`
+	Severity    string   `json:"severity"`
+	Cluster     string   `json:"cluster"`
+	Namespace   string   `json:"namespace"`
+	HPAName     string   `json:"hpa_name"`
+	Rule        string   `json:"rule,omitempty"`
+	Message     string   `json:"message"`
+	Description string   `json:"description,omitempty"`
+	Actions     []string `json:"actions,omitempty"`
+	DetectedAt  string   `json:"detected_at"`
+}
+
+// NewWebhookSink cria um sink de webhook JSON (headers opcionais, ex: Authorization)
+func NewWebhookSink(name, url string, headers map[string]string) *WebhookSink {
+	return &WebhookSink{name: name, url: url, headers: headers, httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Name nome do sink
+func (s *WebhookSink) Name() string { return s.name }
+
+// Send envia o grupo
+func (s *WebhookSink) Send(ctx context.Context, batch Batch) error {
+	payload := WebhookPayload{
+		Title:    batch.Title(),
+		Cluster:  batch.Cluster,
+		Severity: severityName(batch.Severity()),
+		Count:    len(batch.Anomalies),
+	}
+	for _, anomaly := range batch.Anomalies {
+		payload.Anomalies = append(payload.Anomalies, WebhookAnomaly{
+			Type:        string(anomaly.Type),
+			Severity:    severityName(anomaly.Severity),
+			Cluster:     anomaly.Cluster,
+			Namespace:   anomaly.Namespace,
+			HPAName:     anomaly.HPAName,
+			Rule:        anomaly.Rule,
+			Message:     anomaly.Message,
+			Description: anomaly.Description,
+			Actions:     anomaly.Actions,
+			DetectedAt:  anomaly.Timestamp.Format(time.RFC3339),
+		})
+	}
+	return postJSON(ctx, s.httpClient, s.url, s.headers, payload)
+}
+
+// SlackSink envia no formato de incoming webhook do Slack (text + attachments)
+type SlackSink struct {
+	name       string
+	url        string
+	httpClient *http.Client
+}
+
+// NewSlackSink cria um sink Slack-compatible
+func NewSlackSink(name, url string) *SlackSink {
+	return &SlackSink{name: name, url: url, httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Name nome do sink
+func (s *SlackSink) Name() string { return s.name }
+
+// Send envia o grupo
+func (s *SlackSink) Send(ctx context.Context, batch Batch) error {
+	attachments := make([]map[string]interface{}, 0, len(batch.Anomalies))
+	for _, anomaly := range batch.Anomalies {
+		text := anomaly.Message
+		if len(anomaly.Actions) > 0 {
+			text += "\n• " + strings.Join(anomaly.Actions, "\n• ")
+		}
+		attachments = append(attachments, map[string]interface{}{
+			"color":  severityColor(anomaly.Severity),
+			"title":  fmt.Sprintf("%s — %s/%s", anomalyLabel(anomaly), anomaly.Namespace, anomaly.HPAName),
+			"text":   text,
+			"footer": "k8s-hpa-manager • " + batch.Cluster,
+			"ts":     anomaly.Timestamp.Unix(),
+		})
+	}
+
+	return postJSON(ctx, s.httpClient, s.url, nil, map[string]interface{}{
+		"text":        "*" + batch.Title() + "*",
+		"attachments": attachments,
+	})
+}
+
+// TeamsSink envia no formato MessageCard do incoming webhook do Microsoft Teams
+type TeamsSink struct {
+	name       string
+	url        string
+	httpClient *http.Client
+}
+
+// NewTeamsSink cria um sink do Teams
+func NewTeamsSink(name, url string) *TeamsSink {
+	return &TeamsSink{name: name, url: url, httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Name nome do sink
+func (s *TeamsSink) Name() string { return s.name }
+
+// Send envia o grupo
+func (s *TeamsSink) Send(ctx context.Context, batch Batch) error {
+	sections := make([]map[string]interface{}, 0, len(batch.Anomalies))
+	for _, anomaly := range batch.Anomalies {
+		facts := []map[string]string{
+			{"name": "Severidade", "value": anomaly.Severity.String()},
+			{"name": "Cluster", "value": anomaly.Cluster},
+			{"name": "HPA", "value": anomaly.Namespace + "/" + anomaly.HPAName},
+			{"name": "Detectado", "value": anomaly.Timestamp.Format("02/01/2006 15:04:05")},
+		}
+		if len(anomaly.Actions) > 0 {
+			facts = append(facts, map[string]string{"name": "Ações", "value": strings.Join(anomaly.Actions, "; ")})
+		}
+		sections = append(sections, map[string]interface{}{
+			"activityTitle": anomalyLabel(anomaly),
+			"text":          anomaly.Message,
+			"facts":         facts,
+		})
+	}
+
+	return postJSON(ctx, s.httpClient, s.url, nil, map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"themeColor": strings.TrimPrefix(severityColor(batch.Severity()), "#"),
+		"summary":    batch.Title(),
+		"title":      batch.Title(),
+		"sections":   sections,
+	})
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("notification endpoint returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// anomalyLabel tipo da anomalia (ou nome da regra customizada)
+func anomalyLabel(anomaly analyzer.Anomaly) string {
+	if anomaly.Rule != "" {
+		return string(anomaly.Type) + " (" + anomaly.Rule + ")"
+	}
+	return string(anomaly.Type)
+}
+
+func severityName(severity models.AlertSeverity) string {
+	return strings.ToLower(severity.String())
+}
+
+func severityColor(severity models.AlertSeverity) string {
+	switch severity {
+	case models.SeverityCritical:
+		return "#D32F2F"
+	case models.SeverityWarning:
+		return "#F9A825"
+	default:
+		return "#1976D2"
+	}
+}
@@ -0,0 +1,82 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPSink envia o grupo por email (texto simples).
+// Usa STARTTLS quando o servidor anuncia e autenticação PLAIN quando Username é informado.
+type SMTPSink struct {
+	name   string
+	config SMTPConfig
+}
+
+// NewSMTPSink cria um sink de email
+func NewSMTPSink(name string, cfg SMTPConfig) *SMTPSink {
+	if cfg.Port == 0 {
+		cfg.Port = 25
+	}
+	return &SMTPSink{name: name, config: cfg}
+}
+
+// Name nome do sink
+func (s *SMTPSink) Name() string { return s.name }
+
+// Send envia o grupo
+func (s *SMTPSink) Send(ctx context.Context, batch Batch) error {
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, s.config.From, s.config.To, s.message(batch))
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to send email via %s: %w", addr, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to send email via %s: %w", addr, ctx.Err())
+	}
+}
+
+// message monta o email (RFC 5322)
+func (s *SMTPSink) message(batch Batch) []byte {
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s\r\n\r\n", batch.Title())
+	for _, anomaly := range batch.Anomalies {
+		fmt.Fprintf(&body, "[%s] %s - %s/%s\r\n", anomaly.Severity, anomalyLabel(anomaly), anomaly.Namespace, anomaly.HPAName)
+		fmt.Fprintf(&body, "%s\r\n", anomaly.Message)
+		if anomaly.Description != "" {
+			fmt.Fprintf(&body, "%s\r\n", anomaly.Description)
+		}
+		for _, action := range anomaly.Actions {
+			fmt.Fprintf(&body, "  - %s\r\n", action)
+		}
+		fmt.Fprintf(&body, "Detectado em %s\r\n\r\n", anomaly.Timestamp.Format("02/01/2006 15:04:05"))
+	}
+
+	headers := []string{
+		"From: " + s.config.From,
+		"To: " + strings.Join(s.config.To, ", "),
+		"Subject: " + batch.Title(),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"Content-Transfer-Encoding: 8bit",
+	}
+	return []byte(strings.Join(headers, "\r\n") + "\r\n\r\n" + body.String())
+}
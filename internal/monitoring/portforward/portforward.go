@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"os/exec"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -14,20 +15,22 @@ import (
 
 // PortForward gerencia port-forward para Prometheus
 type PortForward struct {
-	cluster   string
-	namespace string
-	service   string
-	localPort int
-	cmd       *exec.Cmd
-	cancel    context.CancelFunc
+	cluster    string
+	namespace  string
+	service    string
+	localPort  int
+	remotePort int
+	cmd        *exec.Cmd
+	cancel     context.CancelFunc
 }
 
 // Config configuração do port-forward
 type Config struct {
-	Cluster   string
-	Namespace string // Default: "monitoring"
-	Service   string // Default: "prometheus-k8s" ou "prometheus-server"
-	LocalPort int    // Default: 9090
+	Cluster    string
+	Namespace  string // Default: "monitoring"
+	Service    string // Default: "prometheus-k8s" ou "prometheus-server"
+	LocalPort  int    // Default: 9090
+	RemotePort int    // Default: 9090 (Alertmanager: 9093)
 }
 
 // New cria novo port-forward
@@ -42,12 +45,16 @@ func New(cfg Config) *PortForward {
 	if cfg.LocalPort == 0 {
 		cfg.LocalPort = 9090
 	}
+	if cfg.RemotePort == 0 {
+		cfg.RemotePort = 9090
+	}
 
 	return &PortForward{
-		cluster:   cfg.Cluster,
-		namespace: cfg.Namespace,
-		service:   cfg.Service,
-		localPort: cfg.LocalPort,
+		cluster:    cfg.Cluster,
+		namespace:  cfg.Namespace,
+		service:    cfg.Service,
+		localPort:  cfg.LocalPort,
+		remotePort: cfg.RemotePort,
 	}
 }
 
@@ -58,7 +65,7 @@ func (pf *PortForward) Start() error {
 		Str("namespace", pf.namespace).
 		Str("service", pf.service).
 		Int("port", pf.localPort).
-		Msg("Iniciando port-forward para Prometheus")
+		Msg("Iniciando port-forward")
 
 	ctx, cancel := context.WithCancel(context.Background())
 	pf.cancel = cancel
@@ -68,7 +75,7 @@ func (pf *PortForward) Start() error {
 		"kubectl",
 		"port-forward",
 		fmt.Sprintf("svc/%s", pf.service),
-		fmt.Sprintf("%d:9090", pf.localPort),
+		fmt.Sprintf("%d:%d", pf.localPort, pf.remotePort),
 		"-n", pf.namespace,
 		"--context", pf.cluster,
 	)
@@ -712,3 +719,124 @@ func (m *PortForwardManager) StopBaselinePort(cluster string, port int) error {
 
 	return nil
 }
+
+// alertmanagerPort porta local do port-forward TEMPORÁRIO para Alertmanager (um cluster por vez)
+const alertmanagerPort = 55559
+
+// discoverAlertmanagerService tenta descobrir o serviço do Alertmanager
+func (m *PortForwardManager) discoverAlertmanagerService(context string) (string, error) {
+	commonNames := []string{
+		"alertmanager-operated",                   // Prometheus Operator (statefulset)
+		"alertmanager-main",                       // Kube-Prometheus
+		"prometheus-alertmanager",                 // Helm Chart prometheus
+		"kube-prometheus-stack-alertmanager",      // Helm Chart kube-prometheus-stack
+		"prometheus-kube-prometheus-alertmanager", // kube-prometheus-stack com release "prometheus"
+		"alertmanager",                            // Nome simples
+	}
+
+	for _, serviceName := range commonNames {
+		cmd := exec.Command("kubectl",
+			"get", "svc", serviceName,
+			"-n", "monitoring",
+			"--context", context,
+			"--no-headers",
+		)
+		if err := cmd.Run(); err == nil {
+			log.Info().
+				Str("cluster", context).
+				Str("service", serviceName).
+				Msg("Serviço Alertmanager descoberto")
+			return serviceName, nil
+		}
+	}
+
+	return "", fmt.Errorf("serviço Alertmanager não encontrado no namespace monitoring (%s)", context)
+}
+
+// StartAlertmanager inicia port-forward TEMPORÁRIO para o Alertmanager do cluster
+// e retorna a URL local. Usa uma única porta: o chamador deve parar antes do próximo cluster.
+func (m *PortForwardManager) StartAlertmanager(cluster string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := "alertmanager:" + cluster
+	if pf, exists := m.forwards[key]; exists && pf.IsRunning() {
+		return pf.GetURL(), nil
+	}
+	m.releasePortForCluster(alertmanagerPort)
+
+	context := cluster
+	if !strings.HasSuffix(cluster, "-admin") {
+		context = cluster + "-admin"
+	}
+
+	serviceName, err := m.discoverAlertmanagerService(context)
+	if err != nil {
+		return "", err
+	}
+
+	pf := New(Config{
+		Cluster:    context,
+		Service:    serviceName,
+		LocalPort:  alertmanagerPort,
+		RemotePort: 9093,
+	})
+	if err := pf.Start(); err != nil {
+		return "", fmt.Errorf("falha ao criar port-forward para Alertmanager: %w", err)
+	}
+
+	m.forwards[key] = pf
+	return pf.GetURL(), nil
+}
+
+// StopAlertmanager para o port-forward do Alertmanager do cluster
+func (m *PortForwardManager) StopAlertmanager(cluster string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := "alertmanager:" + cluster
+	pf, exists := m.forwards[key]
+	if !exists {
+		return nil
+	}
+	delete(m.forwards, key)
+	return pf.Stop()
+}
+
+// ForwardStatus estado de um port-forward gerenciado
+type ForwardStatus struct {
+	Cluster   string
+	Kind      string // prometheus, dedicated (porta fixa por HPA/baseline) ou alertmanager
+	LocalPort int
+	Healthy   bool // Processo ativo e endpoint /-/ready respondendo
+}
+
+// Status verifica a saúde de todos os port-forwards ativos
+func (m *PortForwardManager) Status() []ForwardStatus {
+	m.mu.RLock()
+	forwards := make(map[string]*PortForward, len(m.forwards))
+	for key, pf := range m.forwards {
+		forwards[key] = pf
+	}
+	m.mu.RUnlock()
+
+	// Health check (HTTP) fora do lock
+	statuses := make([]ForwardStatus, 0, len(forwards))
+	for key, pf := range forwards {
+		status := ForwardStatus{Cluster: key, Kind: "prometheus", LocalPort: pf.localPort, Healthy: pf.IsRunning()}
+		if cluster, ok := strings.CutPrefix(key, "alertmanager:"); ok {
+			status.Cluster, status.Kind = cluster, "alertmanager"
+		} else if i := strings.LastIndex(key, ":"); i > 0 {
+			status.Cluster, status.Kind = key[:i], "dedicated"
+		}
+		status.Cluster = strings.TrimSuffix(status.Cluster, "-admin")
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Cluster != statuses[j].Cluster {
+			return statuses[i].Cluster < statuses[j].Cluster
+		}
+		return statuses[i].LocalPort < statuses[j].LocalPort
+	})
+	return statuses
+}
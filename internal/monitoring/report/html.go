@@ -0,0 +1,173 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// chartLine série desenhada no gráfico SVG
+type chartLine struct {
+	label  string
+	color  string
+	values []float64
+}
+
+// RenderHTML escreve o relatório como HTML autocontido (CSS e gráficos SVG inline, sem assets externos)
+func RenderHTML(w io.Writer, r *Report) error {
+	data := struct {
+		*Report
+		SummaryRows  [][2]string
+		ResultLabel  string
+		ReplicaChart template.HTML
+		CPUChart     template.HTML
+	}{
+		Report:      r,
+		SummaryRows: summaryRows(r),
+		ResultLabel: resultLabel(r.Summary.Result),
+	}
+
+	if len(r.Series) > 1 {
+		data.ReplicaChart = lineChart(r.Series, "réplicas", []chartLine{
+			{"Réplicas totais", "#2563eb", seriesValues(r.Series, func(p SeriesPoint) float64 { return float64(p.TotalReplicas) })},
+		})
+		data.CPUChart = lineChart(r.Series, "%", []chartLine{
+			{"CPU máxima", "#dc2626", seriesValues(r.Series, func(p SeriesPoint) float64 { return p.MaxCPU })},
+			{"CPU média", "#f59e0b", seriesValues(r.Series, func(p SeriesPoint) float64 { return p.AvgCPU })},
+			{"Memória média", "#16a34a", seriesValues(r.Series, func(p SeriesPoint) float64 { return p.AvgMemory })},
+		})
+	}
+
+	if err := htmlTemplate.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return nil
+}
+
+// lineChart gera um gráfico de linhas SVG com eixo Y de 0 ao máximo e horários no eixo X
+func lineChart(series []SeriesPoint, unit string, lines []chartLine) template.HTML {
+	const width, height, left, right, top, bottom = 860.0, 240.0, 48.0, 12.0, 12.0, 28.0
+	plotW, plotH := width-left-right, height-top-bottom
+
+	high := 0.0
+	for _, line := range lines {
+		for _, v := range line.values {
+			high = max(high, v)
+		}
+	}
+	if high == 0 {
+		high = 1
+	}
+	high *= 1.1
+
+	x := func(i int) float64 { return left + plotW*float64(i)/float64(len(series)-1) }
+	y := func(v float64) float64 { return top + plotH - plotH*v/high }
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg viewBox="0 0 %.0f %.0f" class="chart" role="img">`, width, height)
+	for i := 0; i <= 4; i++ {
+		v := high * float64(i) / 4
+		fmt.Fprintf(&b, `<line x1="%.0f" x2="%.0f" y1="%.1f" y2="%.1f" class="grid"/>`, left, width-right, y(v), y(v))
+		fmt.Fprintf(&b, `<text x="%.0f" y="%.1f" class="axis" text-anchor="end">%.0f</text>`, left-6, y(v)+4, v)
+	}
+	for _, i := range []int{0, len(series) / 2, len(series) - 1} {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.0f" class="axis" text-anchor="middle">%s</text>`,
+			x(i), height-8, series[i].Time.Format("15:04"))
+	}
+	fmt.Fprintf(&b, `<text x="4" y="%.0f" class="axis">%s</text>`, top+4, template.HTMLEscapeString(unit))
+
+	for _, line := range lines {
+		points := make([]string, len(line.values))
+		for i, v := range line.values {
+			points[i] = fmt.Sprintf("%.1f,%.1f", x(i), y(v))
+		}
+		fmt.Fprintf(&b, `<polyline fill="none" stroke="%s" stroke-width="2" points="%s"><title>%s</title></polyline>`,
+			line.color, strings.Join(points, " "), template.HTMLEscapeString(line.label))
+	}
+	b.WriteString(`</svg><div class="legend">`)
+	for _, line := range lines {
+		fmt.Fprintf(&b, `<span><i style="background:%s"></i>%s</span>`, line.color, template.HTMLEscapeString(line.label))
+	}
+	b.WriteString(`</div>`)
+	return template.HTML(b.String())
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"ts":  timestamp,
+	"pct": func(v float64) string { return fmt.Sprintf("%.1f%%", v) },
+}).Parse(`<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Summary.TestName}} - Relatório de Stress Test</title>
+<style>
+body{font-family:-apple-system,Segoe UI,Roboto,sans-serif;margin:0;padding:24px 32px;color:#1f2937;background:#f9fafb}
+h1{margin:0 0 4px;font-size:24px}h2{font-size:18px;margin:32px 0 12px;border-bottom:1px solid #e5e7eb;padding-bottom:6px}
+.meta{color:#6b7280;font-size:13px}.badge{display:inline-block;padding:2px 10px;border-radius:12px;font-weight:600;font-size:13px;background:#e5e7eb}
+.PASS{background:#dcfce7;color:#166534}.FAIL{background:#fee2e2;color:#991b1b}
+table{border-collapse:collapse;width:100%;background:#fff;font-size:13px}th,td{border:1px solid #e5e7eb;padding:6px 8px;text-align:left}
+th{background:#f3f4f6}td.num{text-align:right;font-variant-numeric:tabular-nums}
+.chart{width:100%;max-width:860px;background:#fff;border:1px solid #e5e7eb}.grid{stroke:#e5e7eb}.axis{font-size:11px;fill:#6b7280}
+.legend{font-size:12px;margin:4px 0 16px}.legend span{margin-right:16px}.legend i{display:inline-block;width:10px;height:10px;margin-right:4px;border-radius:2px}
+.critical,.Critical,.maxed_out,.failed{color:#b91c1c;font-weight:600}.warning,.Warning{color:#b45309}.healthy{color:#15803d}
+footer{margin-top:32px;color:#9ca3af;font-size:12px}
+</style>
+</head>
+<body>
+<h1>{{.Summary.TestName}}</h1>
+<div class="meta">
+<span class="badge {{.Summary.Result}}">{{.ResultLabel}}</span>
+status {{.Summary.Status}} · início {{ts .Summary.StartTime}} · fim {{ts .Summary.EndTime}} · duração {{.Summary.Duration}} ·
+{{.Summary.TotalScans}} scans a cada {{.Summary.ScanInterval}} · test id <code>{{.TestID}}</code>
+</div>
+
+<h2>Resumo</h2>
+<table>{{range .SummaryRows}}<tr><th>{{index . 0}}</th><td>{{index . 1}}</td></tr>{{end}}</table>
+
+{{if .ReplicaChart}}
+<h2>Réplicas</h2>
+{{.ReplicaChart}}
+<h2>CPU e memória (% do request)</h2>
+{{.CPUChart}}
+{{end}}
+
+{{if .HPAs}}
+<h2>HPAs ({{len .HPAs}})</h2>
+<table>
+<tr><th>Cluster</th><th>Namespace</th><th>HPA</th><th>Min/Max</th><th>Pré</th><th>Pico</th><th>Pós</th><th>Mudanças</th><th>CPU pico</th><th>Memória pico</th><th>Problemas</th><th>Status</th></tr>
+{{range .HPAs}}<tr><td>{{.Cluster}}</td><td>{{.Namespace}}</td><td>{{.Name}}</td><td class="num">{{.MinReplicas}}/{{.MaxReplicas}}</td>
+<td class="num">{{.ReplicasPre}}</td><td class="num">{{.ReplicasPeak}}</td><td class="num">{{.ReplicasPost}}</td><td class="num">{{.ReplicaChanges}}</td>
+<td class="num">{{pct .PeakCPU}}</td><td class="num">{{pct .PeakMemory}}</td><td class="num">{{.Issues}}</td><td class="{{.Status}}">{{.Status}}</td></tr>
+{{end}}</table>
+{{end}}
+
+{{if .Issues}}
+<h2>Problemas ({{len .Issues}})</h2>
+<table>
+<tr><th>Horário</th><th>Severidade</th><th>Tipo</th><th>HPA</th><th>Descrição</th><th>Recomendação</th></tr>
+{{range .Issues}}<tr><td>{{ts .DetectedAt}}</td><td class="{{.Severity}}">{{.Severity}}</td><td>{{.Type}}</td><td>{{.Target}}</td><td>{{.Description}}</td><td>{{.Recommendation}}</td></tr>
+{{end}}</table>
+{{end}}
+
+{{if .Recommendations}}
+<h2>Recomendações ({{len .Recommendations}})</h2>
+<table>
+<tr><th>Prioridade</th><th>Categoria</th><th>Alvo</th><th>Recomendação</th><th>Ação</th><th>Justificativa</th><th>Impacto</th></tr>
+{{range .Recommendations}}<tr><td>{{.Priority}}</td><td>{{.Category}}</td><td>{{.Target}}</td><td><strong>{{.Title}}</strong><br>{{.Description}}</td><td>{{.Action}}</td><td>{{.Rationale}}</td><td>{{.Impact}}</td></tr>
+{{end}}</table>
+{{end}}
+
+{{if .Timeline}}
+<h2>Timeline ({{len .Timeline}})</h2>
+<table>
+<tr><th>Horário</th><th>Evento</th><th>Severidade</th><th>HPA</th><th>Descrição</th></tr>
+{{range .Timeline}}<tr><td>{{ts .Time}}</td><td>{{.Type}}</td><td class="{{.Severity}}">{{.Severity}}</td><td>{{.Target}}</td><td>{{.Description}}</td></tr>
+{{end}}</table>
+{{end}}
+
+<footer>Gerado em {{ts .GeneratedAt}} pelo k8s-hpa-manager</footer>
+</body>
+</html>
+`))
@@ -0,0 +1,318 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format formato de saída do relatório
+type Format string
+
+const (
+	FormatHTML     Format = "html"
+	FormatMarkdown Format = "markdown"
+	FormatJSON     Format = "json"
+	FormatCSV      Format = "csv"
+)
+
+// ParseFormat aceita html, markdown (md), json e csv (vazio = html)
+func ParseFormat(value string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "html", "htm":
+		return FormatHTML, nil
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	case "json":
+		return FormatJSON, nil
+	case "csv":
+		return FormatCSV, nil
+	}
+	return "", fmt.Errorf("unsupported report format %q (use html, markdown, json or csv)", value)
+}
+
+// ContentType retorna o Content-Type HTTP do formato
+func (f Format) ContentType() string {
+	switch f {
+	case FormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	case FormatJSON:
+		return "application/json; charset=utf-8"
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	}
+	return "text/html; charset=utf-8"
+}
+
+// Extension retorna a extensão de arquivo do formato
+func (f Format) Extension() string {
+	if f == FormatMarkdown {
+		return "md"
+	}
+	return string(f)
+}
+
+// CSVSection tabela exportada no formato CSV
+type CSVSection string
+
+const (
+	SectionHPAs            CSVSection = "hpas"
+	SectionSeries          CSVSection = "series"
+	SectionIssues          CSVSection = "issues"
+	SectionTimeline        CSVSection = "timeline"
+	SectionRecommendations CSVSection = "recommendations"
+)
+
+// ParseCSVSection valida a tabela do CSV (vazio = hpas)
+func ParseCSVSection(value string) (CSVSection, error) {
+	section := CSVSection(strings.ToLower(strings.TrimSpace(value)))
+	switch section {
+	case "":
+		return SectionHPAs, nil
+	case SectionHPAs, SectionSeries, SectionIssues, SectionTimeline, SectionRecommendations:
+		return section, nil
+	}
+	return "", fmt.Errorf("unsupported CSV section %q (use hpas, series, issues, timeline or recommendations)", value)
+}
+
+// Filename nome sugerido para download (stress-test-<id>.<ext>)
+func (r *Report) Filename(format Format) string {
+	id := r.TestID
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	return fmt.Sprintf("stress-test-%s.%s", id, format.Extension())
+}
+
+// CSVFilename nome sugerido para o CSV de uma tabela (stress-test-<id>-<tabela>.csv)
+func (r *Report) CSVFilename(section CSVSection) string {
+	return strings.TrimSuffix(r.Filename(FormatCSV), ".csv") + "-" + string(section) + ".csv"
+}
+
+// Render escreve o relatório no formato pedido (CSV exporta a tabela de HPAs)
+func Render(w io.Writer, r *Report, format Format) error {
+	switch format {
+	case FormatHTML:
+		return RenderHTML(w, r)
+	case FormatMarkdown:
+		return RenderMarkdown(w, r)
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case FormatCSV:
+		return RenderCSV(w, r, SectionHPAs)
+	}
+	return fmt.Errorf("unsupported report format %q", format)
+}
+
+// RenderCSV escreve uma das tabelas do relatório em CSV
+func RenderCSV(w io.Writer, r *Report, section CSVSection) error {
+	cw := csv.NewWriter(w)
+	var records [][]string
+
+	switch section {
+	case SectionHPAs:
+		records = append(records, []string{"cluster", "namespace", "hpa", "min_replicas", "max_replicas", "cpu_target",
+			"replicas_pre", "replicas_peak", "replicas_post", "replica_changes", "peak_cpu_percent",
+			"peak_memory_percent", "maxed_out", "issues", "status"})
+		for _, h := range r.HPAs {
+			records = append(records, []string{h.Cluster, h.Namespace, h.Name, itoa(h.MinReplicas), itoa(h.MaxReplicas),
+				itoa(h.CPUTarget), itoa(h.ReplicasPre), itoa(h.ReplicasPeak), itoa(h.ReplicasPost),
+				strconv.Itoa(h.ReplicaChanges), ftoa(h.PeakCPU), ftoa(h.PeakMemory),
+				strconv.FormatBool(h.MaxedOut), strconv.Itoa(h.Issues), h.Status})
+		}
+	case SectionSeries:
+		records = append(records, []string{"time", "hpas", "total_replicas", "avg_cpu_percent", "max_cpu_percent", "avg_memory_percent"})
+		for _, p := range r.Series {
+			records = append(records, []string{timestamp(p.Time), strconv.Itoa(p.HPAs), strconv.Itoa(p.TotalReplicas),
+				ftoa(p.AvgCPU), ftoa(p.MaxCPU), ftoa(p.AvgMemory)})
+		}
+	case SectionIssues:
+		records = append(records, []string{"detected_at", "severity", "type", "target", "description", "recommendation", "resolved"})
+		for _, i := range r.Issues {
+			records = append(records, []string{timestamp(i.DetectedAt), i.Severity, i.Type, i.Target, i.Description,
+				i.Recommendation, strconv.FormatBool(i.Resolved)})
+		}
+	case SectionTimeline:
+		records = append(records, []string{"time", "type", "severity", "target", "description"})
+		for _, e := range r.Timeline {
+			records = append(records, []string{timestamp(e.Time), e.Type, e.Severity, e.Target, e.Description})
+		}
+	case SectionRecommendations:
+		records = append(records, []string{"priority", "category", "target", "title", "description", "action", "rationale", "impact"})
+		for _, rec := range r.Recommendations {
+			records = append(records, []string{rec.Priority, rec.Category, rec.Target, rec.Title, rec.Description,
+				rec.Action, rec.Rationale, rec.Impact})
+		}
+	default:
+		return fmt.Errorf("unsupported CSV section %q", section)
+	}
+
+	if err := cw.WriteAll(records); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// RenderMarkdown escreve o relatório em Markdown (tabelas GFM, para wikis)
+func RenderMarkdown(w io.Writer, r *Report) error {
+	s := r.Summary
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", mdEscape(s.TestName))
+	fmt.Fprintf(&b, "**Resultado:** %s · **Status:** %s · **Test ID:** `%s`\n\n", resultLabel(s.Result), s.Status, r.TestID)
+	fmt.Fprintf(&b, "Início %s · Fim %s · Duração %s · %d scans a cada %s\n\n",
+		timestamp(s.StartTime), timestamp(s.EndTime), s.Duration.Round(time.Second), s.TotalScans, s.ScanInterval)
+
+	b.WriteString("## Resumo\n\n| Métrica | Valor |\n|---|---|\n")
+	for _, row := range summaryRows(r) {
+		fmt.Fprintf(&b, "| %s | %s |\n", row[0], mdEscape(row[1]))
+	}
+
+	if len(r.Series) > 0 {
+		b.WriteString("\n## Evolução\n\n")
+		fmt.Fprintf(&b, "- Réplicas: `%s` (%d → %d)\n", sparkline(seriesValues(r.Series, func(p SeriesPoint) float64 { return float64(p.TotalReplicas) })),
+			r.Series[0].TotalReplicas, r.Series[len(r.Series)-1].TotalReplicas)
+		fmt.Fprintf(&b, "- CPU média: `%s`\n", sparkline(seriesValues(r.Series, func(p SeriesPoint) float64 { return p.AvgCPU })))
+		fmt.Fprintf(&b, "- CPU máxima: `%s`\n", sparkline(seriesValues(r.Series, func(p SeriesPoint) float64 { return p.MaxCPU })))
+	}
+
+	if len(r.HPAs) > 0 {
+		b.WriteString("\n## HPAs\n\n| HPA | Min/Max | Réplicas pré → pico → pós | CPU pico | Memória pico | Status |\n|---|---|---|---|---|---|\n")
+		for _, h := range r.HPAs {
+			status := h.Status
+			if h.MaxedOut {
+				status += " ⚠️"
+			}
+			fmt.Fprintf(&b, "| %s | %d/%d | %d → %d → %d | %.1f%% | %.1f%% | %s |\n",
+				mdEscape(target(h.Cluster, h.Namespace, h.Name)), h.MinReplicas, h.MaxReplicas,
+				h.ReplicasPre, h.ReplicasPeak, h.ReplicasPost, h.PeakCPU, h.PeakMemory, status)
+		}
+	}
+
+	if len(r.Issues) > 0 {
+		b.WriteString("\n## Problemas\n\n| Horário | Severidade | Tipo | HPA | Descrição |\n|---|---|---|---|---|\n")
+		for _, i := range r.Issues {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", timestamp(i.DetectedAt), i.Severity,
+				mdEscape(i.Type), mdEscape(i.Target), mdEscape(i.Description))
+		}
+	}
+
+	if len(r.Recommendations) > 0 {
+		b.WriteString("\n## Recomendações\n\n")
+		for _, rec := range r.Recommendations {
+			fmt.Fprintf(&b, "- **[%s] %s** (%s): %s", rec.Priority, mdEscape(rec.Title), mdEscape(rec.Target), mdEscape(rec.Action))
+			if rec.Rationale != "" {
+				fmt.Fprintf(&b, " _%s_", mdEscape(rec.Rationale))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if len(r.Timeline) > 0 {
+		b.WriteString("\n## Timeline\n\n| Horário | Evento | HPA | Descrição |\n|---|---|---|---|\n")
+		for _, e := range r.Timeline {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", timestamp(e.Time), e.Type, mdEscape(e.Target), mdEscape(e.Description))
+		}
+	}
+
+	fmt.Fprintf(&b, "\n_Gerado em %s pelo k8s-hpa-manager_\n", timestamp(r.GeneratedAt))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// summaryRows linhas da tabela de resumo (compartilhadas entre Markdown e HTML)
+func summaryRows(r *Report) [][2]string {
+	s := r.Summary
+	return [][2]string{
+		{"Clusters", strconv.Itoa(s.TotalClusters)},
+		{"HPAs monitorados", fmt.Sprintf("%d de %d", s.HPAsMonitored, s.TotalHPAs)},
+		{"HPAs com problemas", strconv.Itoa(s.HPAsWithIssues)},
+		{"Saúde", fmt.Sprintf("%.1f%%", s.HealthPercent)},
+		{"Problemas (crítico/aviso/info)", fmt.Sprintf("%d / %d / %d", s.CriticalIssues, s.WarningIssues, s.InfoIssues)},
+		{"Réplicas pré → pico → pós", fmt.Sprintf("%d → %d → %d", s.ReplicasPre, s.ReplicasPeak, s.ReplicasPost)},
+		{"Aumento de réplicas", fmt.Sprintf("%+d (%.1f%%)", s.ReplicaIncrease, s.ReplicaIncreasePct)},
+		{"Pico de CPU", peakLabel(s.PeakCPU, s.PeakCPUHPA, s.PeakCPUAt)},
+		{"Pico de memória", peakLabel(s.PeakMemory, s.PeakMemoryHPA, s.PeakMemoryAt)},
+		{"HPAs no maxReplicas", strconv.Itoa(s.MaxedOutHPAs)},
+	}
+}
+
+func peakLabel(value float64, hpa string, at time.Time) string {
+	if hpa == "" {
+		return fmt.Sprintf("%.1f%%", value)
+	}
+	return fmt.Sprintf("%.1f%% (%s às %s)", value, hpa, at.Format("15:04:05"))
+}
+
+func resultLabel(result string) string {
+	switch result {
+	case "PASS":
+		return "✅ PASS"
+	case "FAIL":
+		return "❌ FAIL"
+	}
+	return "—"
+}
+
+func seriesValues(series []SeriesPoint, value func(SeriesPoint) float64) []float64 {
+	values := make([]float64, len(series))
+	for i, p := range series {
+		values[i] = value(p)
+	}
+	return values
+}
+
+// sparkline resume a série em até 60 blocos unicode
+func sparkline(values []float64) string {
+	const blocks = "▁▂▃▄▅▆▇█"
+	runes := []rune(blocks)
+	if len(values) > 60 {
+		sampled := make([]float64, 60)
+		for i := range sampled {
+			from, to := i*len(values)/60, (i+1)*len(values)/60
+			for _, v := range values[from:to] {
+				sampled[i] = max(sampled[i], v)
+			}
+		}
+		values = sampled
+	}
+
+	low, high := values[0], values[0]
+	for _, v := range values {
+		low, high = min(low, v), max(high, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		idx := 0
+		if high > low {
+			idx = int((v - low) / (high - low) * float64(len(runes)-1))
+		}
+		b.WriteRune(runes[idx])
+	}
+	return b.String()
+}
+
+func mdEscape(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
+
+func timestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func itoa(v int32) string {
+	return strconv.Itoa(int(v))
+}
+
+func ftoa(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
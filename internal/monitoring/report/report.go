@@ -0,0 +1,371 @@
+// Package report gera relatórios de stress tests concluídos a partir do SQLite
+// (stress_test_results + stress_test_snapshots) em HTML autocontido, Markdown, JSON e CSV.
+//
+//	rep, _ := report.Load(persistence, testID)
+//	report.Render(os.Stdout, rep, report.FormatMarkdown)
+package report
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s-hpa-manager/internal/monitoring/models"
+	"k8s-hpa-manager/internal/monitoring/storage"
+)
+
+// Report relatório consolidado de um stress test
+type Report struct {
+	TestID          string              `json:"test_id"`
+	GeneratedAt     time.Time           `json:"generated_at"`
+	Summary         Summary             `json:"summary"`
+	HPAs            []HPARow            `json:"hpas"`
+	Series          []SeriesPoint       `json:"series"`
+	Issues          []IssueRow          `json:"issues"`
+	Timeline        []TimelineRow       `json:"timeline"`
+	Recommendations []RecommendationRow `json:"recommendations"`
+}
+
+// Summary visão geral do teste
+type Summary struct {
+	TestName           string        `json:"test_name"`
+	Status             string        `json:"status"`
+	Result             string        `json:"result"` // PASS / FAIL
+	StartTime          time.Time     `json:"start_time"`
+	EndTime            time.Time     `json:"end_time"`
+	Duration           time.Duration `json:"duration_ns"`
+	ScanInterval       time.Duration `json:"scan_interval_ns"`
+	TotalScans         int           `json:"total_scans"`
+	TotalClusters      int           `json:"total_clusters"`
+	TotalHPAs          int           `json:"total_hpas"`
+	HPAsMonitored      int           `json:"hpas_monitored"`
+	HPAsWithIssues     int           `json:"hpas_with_issues"`
+	HealthPercent      float64       `json:"health_percent"`
+	CriticalIssues     int           `json:"critical_issues"`
+	WarningIssues      int           `json:"warning_issues"`
+	InfoIssues         int           `json:"info_issues"`
+	ReplicasPre        int           `json:"replicas_pre"`
+	ReplicasPeak       int           `json:"replicas_peak"`
+	ReplicasPost       int           `json:"replicas_post"`
+	ReplicaIncrease    int           `json:"replica_increase"`
+	ReplicaIncreasePct float64       `json:"replica_increase_percent"`
+	PeakCPU            float64       `json:"peak_cpu_percent"`
+	PeakCPUHPA         string        `json:"peak_cpu_hpa"`
+	PeakCPUAt          time.Time     `json:"peak_cpu_at"`
+	PeakMemory         float64       `json:"peak_memory_percent"`
+	PeakMemoryHPA      string        `json:"peak_memory_hpa"`
+	PeakMemoryAt       time.Time     `json:"peak_memory_at"`
+	MaxedOutHPAs       int           `json:"maxed_out_hpas"`
+}
+
+// HPARow comportamento de um HPA durante o teste
+type HPARow struct {
+	Cluster        string  `json:"cluster"`
+	Namespace      string  `json:"namespace"`
+	Name           string  `json:"name"`
+	MinReplicas    int32   `json:"min_replicas"`
+	MaxReplicas    int32   `json:"max_replicas"`
+	CPUTarget      int32   `json:"cpu_target"`
+	ReplicasPre    int32   `json:"replicas_pre"`
+	ReplicasPeak   int32   `json:"replicas_peak"`
+	ReplicasPost   int32   `json:"replicas_post"`
+	ReplicaChanges int     `json:"replica_changes"`
+	PeakCPU        float64 `json:"peak_cpu_percent"`
+	PeakMemory     float64 `json:"peak_memory_percent"`
+	MaxedOut       bool    `json:"maxed_out"`
+	Issues         int     `json:"issues"`
+	Status         string  `json:"status"`
+}
+
+// SeriesPoint agregado de um scan (todos os HPAs) usado nos gráficos
+type SeriesPoint struct {
+	Time          time.Time `json:"time"`
+	HPAs          int       `json:"hpas"`
+	TotalReplicas int       `json:"total_replicas"`
+	AvgCPU        float64   `json:"avg_cpu_percent"`
+	MaxCPU        float64   `json:"max_cpu_percent"`
+	AvgMemory     float64   `json:"avg_memory_percent"`
+}
+
+// IssueRow problema detectado
+type IssueRow struct {
+	DetectedAt     time.Time `json:"detected_at"`
+	Severity       string    `json:"severity"`
+	Type           string    `json:"type"`
+	Target         string    `json:"target"`
+	Description    string    `json:"description"`
+	Recommendation string    `json:"recommendation"`
+	Resolved       bool      `json:"resolved"`
+}
+
+// TimelineRow evento da timeline
+type TimelineRow struct {
+	Time        time.Time `json:"time"`
+	Type        string    `json:"type"`
+	Severity    string    `json:"severity"`
+	Target      string    `json:"target"`
+	Description string    `json:"description"`
+}
+
+// RecommendationRow recomendação do teste
+type RecommendationRow struct {
+	Priority    string `json:"priority"`
+	Category    string `json:"category"`
+	Target      string `json:"target"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Action      string `json:"action"`
+	Rationale   string `json:"rationale"`
+	Impact      string `json:"impact"`
+}
+
+// Load monta o relatório de um teste salvo (test_id de ListStressTests)
+func Load(persistence *storage.Persistence, testID string) (*Report, error) {
+	if persistence == nil {
+		return nil, fmt.Errorf("persistence not available")
+	}
+
+	metrics, err := persistence.LoadStressTestResult(testID)
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := persistence.LoadStressTestSnapshots(testID)
+	if err != nil {
+		return nil, err
+	}
+	return Build(testID, metrics, snapshots, time.Now()), nil
+}
+
+// Build consolida as métricas do teste com os snapshots coletados durante a execução.
+// HPAs sem entrada em HPAMetrics são derivados dos snapshots (réplicas pré/pico/pós, picos de uso).
+func Build(testID string, metrics *models.StressTestMetrics, snapshots []models.HPASnapshot, now time.Time) *Report {
+	r := &Report{
+		TestID:      testID,
+		GeneratedAt: now,
+		Summary:     buildSummary(metrics),
+	}
+
+	r.HPAs = buildHPARows(metrics, snapshots)
+	for _, hpa := range r.HPAs {
+		if hpa.MaxedOut {
+			r.Summary.MaxedOutHPAs++
+		}
+	}
+	r.Series = buildSeries(snapshots, metrics.ScanInterval)
+
+	for _, group := range [][]models.StressTestIssue{metrics.CriticalIssues, metrics.WarningIssues, metrics.InfoIssues} {
+		for _, issue := range group {
+			r.Issues = append(r.Issues, IssueRow{
+				DetectedAt:     issue.DetectedAt,
+				Severity:       issue.Severity.String(),
+				Type:           issue.Type,
+				Target:         target(issue.Cluster, issue.Namespace, issue.HPAName),
+				Description:    issue.Description,
+				Recommendation: issue.Recommendation,
+				Resolved:       issue.Resolved,
+			})
+		}
+	}
+	sort.SliceStable(r.Issues, func(i, j int) bool { return r.Issues[i].DetectedAt.Before(r.Issues[j].DetectedAt) })
+
+	for _, event := range metrics.Timeline {
+		r.Timeline = append(r.Timeline, TimelineRow{
+			Time:        event.Timestamp,
+			Type:        string(event.Type),
+			Severity:    event.Severity.String(),
+			Target:      target(event.Cluster, event.Namespace, event.HPAName),
+			Description: event.Description,
+		})
+	}
+
+	for _, rec := range metrics.Recommendations {
+		r.Recommendations = append(r.Recommendations, RecommendationRow{
+			Priority:    string(rec.Priority),
+			Category:    string(rec.Category),
+			Target:      rec.Target,
+			Title:       rec.Title,
+			Description: rec.Description,
+			Action:      rec.Action,
+			Rationale:   rec.Rationale,
+			Impact:      rec.Impact,
+		})
+	}
+	priorityOrder := map[string]int{
+		string(models.PriorityImmediate): 0, string(models.PriorityHigh): 1,
+		string(models.PriorityMedium): 2, string(models.PriorityLow): 3,
+	}
+	sort.SliceStable(r.Recommendations, func(i, j int) bool {
+		return priorityOrder[r.Recommendations[i].Priority] < priorityOrder[r.Recommendations[j].Priority]
+	})
+
+	return r
+}
+
+func buildSummary(m *models.StressTestMetrics) Summary {
+	peak := m.PeakMetrics
+	s := Summary{
+		TestName:           m.TestName,
+		Status:             string(m.Status),
+		StartTime:          m.StartTime,
+		EndTime:            m.EndTime,
+		Duration:           m.Duration,
+		ScanInterval:       m.ScanInterval,
+		TotalScans:         m.TotalScans,
+		TotalClusters:      m.TotalClusters,
+		TotalHPAs:          m.TotalHPAs,
+		HPAsMonitored:      m.TotalHPAsMonitored,
+		HPAsWithIssues:     m.TotalHPAsWithIssues,
+		HealthPercent:      m.GetHealthPercentage(),
+		CriticalIssues:     len(m.CriticalIssues),
+		WarningIssues:      len(m.WarningIssues),
+		InfoIssues:         len(m.InfoIssues),
+		ReplicasPre:        peak.TotalReplicasPre,
+		ReplicasPeak:       peak.TotalReplicasPeak,
+		ReplicasPost:       peak.TotalReplicasPost,
+		ReplicaIncrease:    peak.ReplicaIncrease,
+		ReplicaIncreasePct: peak.ReplicaIncreaseP,
+		PeakCPU:            peak.MaxCPUPercent,
+		PeakCPUHPA:         peak.MaxCPUHPA,
+		PeakCPUAt:          peak.MaxCPUTime,
+		PeakMemory:         peak.MaxMemoryPercent,
+		PeakMemoryHPA:      peak.MaxMemoryHPA,
+		PeakMemoryAt:       peak.MaxMemoryTime,
+	}
+	if m.TotalHPAsMonitored > 0 {
+		s.Result = m.GetTestResult()
+	}
+	return s
+}
+
+// buildHPARows usa HPAMetrics quando presente e completa com o histórico de snapshots de cada HPA
+func buildHPARows(m *models.StressTestMetrics, snapshots []models.HPASnapshot) []HPARow {
+	rows := make(map[string]*HPARow)
+	for key, hm := range m.HPAMetrics {
+		rows[key] = &HPARow{
+			Cluster:        hm.Cluster,
+			Namespace:      hm.Namespace,
+			Name:           hm.Name,
+			MinReplicas:    hm.MinReplicas,
+			MaxReplicas:    hm.MaxReplicas,
+			CPUTarget:      hm.TargetCPU,
+			ReplicasPre:    hm.ReplicasPre,
+			ReplicasPeak:   hm.ReplicasPeak,
+			ReplicasPost:   hm.ReplicasPost,
+			ReplicaChanges: hm.ReplicaChanges,
+			PeakCPU:        hm.PeakCPU,
+			PeakMemory:     hm.PeakMemory,
+			MaxedOut:       hm.MaxedOut,
+			Issues:         len(hm.Issues),
+			Status:         string(hm.Status),
+		}
+	}
+
+	derived := make(map[string]bool)
+	for _, s := range snapshots {
+		key := target(s.Cluster, s.Namespace, s.Name)
+		row, ok := rows[key]
+		if !ok {
+			row = &HPARow{Cluster: s.Cluster, Namespace: s.Namespace, Name: s.Name, ReplicasPre: s.CurrentReplicas}
+			rows[key] = row
+			derived[key] = true
+		}
+		if !derived[key] {
+			continue
+		}
+		if row.ReplicasPost != s.CurrentReplicas && row.ReplicasPost != 0 {
+			row.ReplicaChanges++
+		}
+		row.MinReplicas, row.MaxReplicas, row.CPUTarget = s.MinReplicas, s.MaxReplicas, s.CPUTarget
+		row.ReplicasPost = s.CurrentReplicas
+		row.ReplicasPeak = max(row.ReplicasPeak, s.CurrentReplicas)
+		row.PeakCPU = max(row.PeakCPU, s.CPUCurrent)
+		row.PeakMemory = max(row.PeakMemory, s.MemoryCurrent)
+		if s.MaxReplicas > 0 && s.CurrentReplicas >= s.MaxReplicas {
+			row.MaxedOut = true
+		}
+	}
+
+	issuesByHPA := make(map[string]int)
+	for _, group := range [][]models.StressTestIssue{m.CriticalIssues, m.WarningIssues} {
+		for _, issue := range group {
+			issuesByHPA[target(issue.Cluster, issue.Namespace, issue.HPAName)]++
+		}
+	}
+
+	result := make([]HPARow, 0, len(rows))
+	for key, row := range rows {
+		if derived[key] {
+			row.Issues = issuesByHPA[key]
+			row.Status = string(derivedStatus(row))
+		}
+		result = append(result, *row)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return result
+}
+
+func derivedStatus(row *HPARow) models.HPAStressStatus {
+	switch {
+	case row.MaxedOut:
+		return models.HPAStatusMaxedOut
+	case row.Issues > 0:
+		return models.HPAStatusWarning
+	default:
+		return models.HPAStatusHealthy
+	}
+}
+
+// buildSeries agrega os snapshots por scan (bucket de scanInterval) para os gráficos
+func buildSeries(snapshots []models.HPASnapshot, scanInterval time.Duration) []SeriesPoint {
+	if scanInterval <= 0 {
+		scanInterval = time.Minute
+	}
+
+	var series []SeriesPoint
+	var cpuSum, memSum float64
+	for _, s := range snapshots {
+		bucket := s.Timestamp.Truncate(scanInterval)
+		if len(series) == 0 || !series[len(series)-1].Time.Equal(bucket) {
+			if len(series) > 0 {
+				finishPoint(&series[len(series)-1], cpuSum, memSum)
+			}
+			series = append(series, SeriesPoint{Time: bucket})
+			cpuSum, memSum = 0, 0
+		}
+		point := &series[len(series)-1]
+		point.HPAs++
+		point.TotalReplicas += int(s.CurrentReplicas)
+		point.MaxCPU = max(point.MaxCPU, s.CPUCurrent)
+		cpuSum += s.CPUCurrent
+		memSum += s.MemoryCurrent
+	}
+	if len(series) > 0 {
+		finishPoint(&series[len(series)-1], cpuSum, memSum)
+	}
+	return series
+}
+
+func finishPoint(point *SeriesPoint, cpuSum, memSum float64) {
+	point.AvgCPU = round2(cpuSum / float64(point.HPAs))
+	point.AvgMemory = round2(memSum / float64(point.HPAs))
+}
+
+func round2(v float64) float64 {
+	return float64(int64(v*100+0.5)) / 100
+}
+
+func target(cluster, namespace, hpa string) string {
+	if cluster == "" && namespace == "" && hpa == "" {
+		return ""
+	}
+	return cluster + "/" + namespace + "/" + hpa
+}
@@ -0,0 +1,154 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s-hpa-manager/internal/monitoring/models"
+	"k8s-hpa-manager/internal/monitoring/storage"
+)
+
+// savedStressTest grava um teste com 3 scans de 2 HPAs (api escala até o máximo)
+func savedStressTest(t *testing.T) (*storage.Persistence, string) {
+	t.Helper()
+	p, err := storage.NewPersistence(&storage.PersistenceConfig{
+		Enabled: true,
+		DBPath:  filepath.Join(t.TempDir(), "report.db"),
+	})
+	if err != nil {
+		t.Fatalf("NewPersistence failed: %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+
+	start := time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC)
+	metrics := models.NewStressTestMetrics("Stress Test Black Friday", start, time.Minute)
+	metrics.TotalHPAsMonitored, metrics.TotalHPAs, metrics.TotalClusters, metrics.TotalScans = 2, 2, 1, 3
+	metrics.PeakMetrics.TotalReplicasPre, metrics.PeakMetrics.TotalReplicasPeak = 4, 12
+	metrics.AddIssue(models.StressTestIssue{
+		Cluster: "aks-prd", Namespace: "shop", HPAName: "api", Type: "MaxedOut",
+		Severity: models.SeverityCritical, DetectedAt: start.Add(2 * time.Minute),
+		Description: "api | no limite de 10 réplicas",
+	})
+	metrics.AddRecommendation(models.Recommendation{Priority: models.PriorityLow, Title: "Revisar worker"})
+	metrics.AddRecommendation(models.Recommendation{Priority: models.PriorityImmediate, Title: "Aumentar maxReplicas", Target: "aks-prd/shop/api"})
+	metrics.Complete()
+
+	testID := "3f2a9c1e-0000-4000-8000-000000000001"
+	for i, replicas := range []int32{2, 6, 10} {
+		for _, s := range []*models.HPASnapshot{
+			{Name: "api", CurrentReplicas: replicas, CPUCurrent: 40 + float64(i)*30},
+			{Name: "worker", CurrentReplicas: 2, CPUCurrent: 20},
+		} {
+			s.Timestamp = start.Add(time.Duration(i)*time.Minute + time.Duration(len(s.Name))*time.Second)
+			s.Cluster, s.Namespace, s.MinReplicas, s.MaxReplicas, s.CPUTarget = "aks-prd", "shop", 2, 10, 70
+			if err := p.SaveStressTestSnapshot(testID, s); err != nil {
+				t.Fatalf("SaveStressTestSnapshot failed: %v", err)
+			}
+		}
+	}
+	if err := p.SaveStressTestResult(testID, metrics); err != nil {
+		t.Fatalf("SaveStressTestResult failed: %v", err)
+	}
+	return p, testID
+}
+
+func TestLoadReport(t *testing.T) {
+	p, testID := savedStressTest(t)
+
+	tests, err := p.ListStressTests()
+	if err != nil || len(tests) != 1 || tests[0]["test_result"] != "FAIL" || tests[0]["test_name"] != "Stress Test Black Friday" {
+		t.Fatalf("unexpected stress test list: %v (err=%v)", tests, err)
+	}
+
+	rep, err := Load(p, testID)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if rep.Summary.Result != "FAIL" || rep.Summary.CriticalIssues != 1 || rep.Summary.MaxedOutHPAs != 1 {
+		t.Errorf("unexpected summary: %+v", rep.Summary)
+	}
+
+	// HPAs derivados dos snapshots (HPAMetrics vazio)
+	if len(rep.HPAs) != 2 {
+		t.Fatalf("expected 2 HPAs, got %d", len(rep.HPAs))
+	}
+	api := rep.HPAs[0]
+	if api.Name != "api" || api.ReplicasPre != 2 || api.ReplicasPeak != 10 || api.ReplicasPost != 10 ||
+		api.ReplicaChanges != 2 || api.PeakCPU != 100 || !api.MaxedOut || api.Issues != 1 || api.Status != "maxed_out" {
+		t.Errorf("unexpected api row: %+v", api)
+	}
+	if worker := rep.HPAs[1]; worker.Status != "healthy" || worker.ReplicaChanges != 0 {
+		t.Errorf("unexpected worker row: %+v", worker)
+	}
+
+	// Um ponto por scan
+	if len(rep.Series) != 3 || rep.Series[2].TotalReplicas != 12 || rep.Series[2].MaxCPU != 100 || rep.Series[2].AvgCPU != 60 {
+		t.Errorf("unexpected series: %+v", rep.Series)
+	}
+	if rep.Recommendations[0].Priority != string(models.PriorityImmediate) {
+		t.Errorf("recommendations should be sorted by priority: %+v", rep.Recommendations)
+	}
+
+	if _, err := Load(p, "missing"); err != storage.ErrStressTestNotFound {
+		t.Errorf("expected ErrStressTestNotFound, got %v", err)
+	}
+}
+
+func TestRenderFormats(t *testing.T) {
+	p, testID := savedStressTest(t)
+	rep, err := Load(p, testID)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	var html bytes.Buffer
+	if err := Render(&html, rep, FormatHTML); err != nil {
+		t.Fatalf("HTML render failed: %v", err)
+	}
+	for _, want := range []string{"<svg", "<polyline", "Stress Test Black Friday", "api | no limite", "❌ FAIL"} {
+		if !strings.Contains(html.String(), want) {
+			t.Errorf("HTML report missing %q", want)
+		}
+	}
+	if strings.Contains(html.String(), "<script") || strings.Contains(html.String(), "http://") {
+		t.Error("HTML report should be self-contained")
+	}
+
+	var md bytes.Buffer
+	if err := Render(&md, rep, FormatMarkdown); err != nil {
+		t.Fatalf("Markdown render failed: %v", err)
+	}
+	if !strings.Contains(md.String(), "| aks-prd/shop/api | 2/10 | 2 → 10 → 10 |") || !strings.Contains(md.String(), `api \| no limite`) {
+		t.Errorf("unexpected markdown:\n%s", md.String())
+	}
+
+	var js bytes.Buffer
+	if err := Render(&js, rep, FormatJSON); err != nil {
+		t.Fatalf("JSON render failed: %v", err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil || decoded.TestID != testID || len(decoded.HPAs) != 2 {
+		t.Errorf("unexpected JSON report (err=%v): %+v", err, decoded.Summary)
+	}
+
+	var series bytes.Buffer
+	if err := RenderCSV(&series, rep, SectionSeries); err != nil {
+		t.Fatalf("CSV render failed: %v", err)
+	}
+	records, err := csv.NewReader(&series).ReadAll()
+	if err != nil || len(records) != 4 || records[0][2] != "total_replicas" || records[3][2] != "12" {
+		t.Errorf("unexpected CSV series (err=%v): %v", err, records)
+	}
+
+	if rep.Filename(FormatMarkdown) != "stress-test-3f2a9c1e.md" || rep.CSVFilename(SectionIssues) != "stress-test-3f2a9c1e-issues.csv" {
+		t.Errorf("unexpected filenames: %s %s", rep.Filename(FormatMarkdown), rep.CSVFilename(SectionIssues))
+	}
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
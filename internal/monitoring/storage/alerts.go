@@ -0,0 +1,653 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"k8s-hpa-manager/internal/monitoring/models"
+)
+
+// AlertState estado do alerta no ciclo de vida
+type AlertState string
+
+const (
+	AlertStateOpen     AlertState = "open"     // Ativo, aguardando alguém
+	AlertStateAcked    AlertState = "acked"    // Ativo, alguém está cuidando
+	AlertStateSilenced AlertState = "silenced" // Ativo, coberto por um silence
+	AlertStateResolved AlertState = "resolved" // Condição deixou de ser detectada
+)
+
+// Motivos de resolução
+const (
+	ResolveReasonCleared = "cleared" // Não foi mais detectado dentro de ResolveAfter
+	ResolveReasonEvicted = "evicted" // Removido da fila por MaxActiveAlerts
+)
+
+// AutoAckUser usuário registrado no ack automático de alertas resolvidos
+const AutoAckUser = "auto"
+
+// ErrAlertNotFound alerta ou silence inexistente
+var ErrAlertNotFound = errors.New("alert not found")
+
+// Alert alerta persistido (uma ocorrência contínua de uma anomalia)
+type Alert struct {
+	ID            string
+	Fingerprint   string
+	Cluster       string
+	Namespace     string
+	HPAName       string
+	Type          string
+	Rule          string
+	Severity      models.AlertSeverity
+	Message       string
+	Description   string
+	State         AlertState
+	FirstSeen     time.Time
+	LastSeen      time.Time
+	Occurrences   int
+	AckedBy       string
+	AckedAt       *time.Time
+	ResolvedAt    *time.Time
+	ResolveReason string
+	SilenceID     string
+}
+
+// Active indica se o alerta ainda não foi resolvido
+func (a *Alert) Active() bool {
+	return a.State != AlertStateResolved
+}
+
+// AlertFingerprint identifica o alerta (cluster sem -admin/namespace/hpa/tipo/regra)
+func AlertFingerprint(cluster, namespace, hpaName, alertType, rule string) string {
+	return strings.Join([]string{strings.TrimSuffix(cluster, "-admin"), namespace, hpaName, alertType, rule}, "/")
+}
+
+// AlertPolicy regras do ciclo de vida dos alertas
+type AlertPolicy struct {
+	MaxActiveAlerts int           // Máximo de alertas ativos; os mais antigos são resolvidos (0 = sem limite)
+	AutoAckResolved bool          // Alertas resolvidos sem ack recebem ack automático
+	ResolveAfter    time.Duration // Tempo sem nova detecção até resolver
+}
+
+// DefaultAlertPolicy retorna a política padrão
+func DefaultAlertPolicy() AlertPolicy {
+	return AlertPolicy{
+		MaxActiveAlerts: 100,
+		AutoAckResolved: false,
+		ResolveAfter:    5 * time.Minute,
+	}
+}
+
+// AlertPolicyFromWatchdog cria a política a partir do WatchdogConfig (MaxActiveAlerts, AutoAckResolvedAlerts)
+func AlertPolicyFromWatchdog(cfg *models.WatchdogConfig) AlertPolicy {
+	cfg.RLock()
+	defer cfg.RUnlock()
+
+	policy := DefaultAlertPolicy()
+	policy.MaxActiveAlerts = cfg.MaxActiveAlerts
+	policy.AutoAckResolved = cfg.AutoAckResolvedAlerts
+	if cfg.ScanIntervalSeconds > 0 {
+		// Pelo menos dois scans sem detecção antes de resolver
+		if interval := 2 * time.Duration(cfg.ScanIntervalSeconds) * time.Second; interval > policy.ResolveAfter {
+			policy.ResolveAfter = interval
+		}
+	}
+	return policy
+}
+
+// SilenceMatcher filtros do silence (glob; vazio = qualquer)
+type SilenceMatcher struct {
+	Cluster   string `json:"cluster,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	HPA       string `json:"hpa,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Rule      string `json:"rule,omitempty"`
+}
+
+// Validate verifica os padrões glob
+func (m SilenceMatcher) Validate() error {
+	if m == (SilenceMatcher{}) {
+		return fmt.Errorf("silence needs at least one matcher")
+	}
+	for _, pattern := range []string{m.Cluster, m.Namespace, m.HPA, m.Type, m.Rule} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid matcher pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+// Matches verifica se o alerta casa com todos os filtros
+func (m SilenceMatcher) Matches(alert *Alert) bool {
+	return globMatch(strings.TrimSuffix(m.Cluster, "-admin"), strings.TrimSuffix(alert.Cluster, "-admin")) &&
+		globMatch(m.Namespace, alert.Namespace) &&
+		globMatch(m.HPA, alert.HPAName) &&
+		globMatch(m.Type, alert.Type) &&
+		globMatch(m.Rule, alert.Rule)
+}
+
+func globMatch(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, value)
+	return ok
+}
+
+// Silence suprime alertas que casam com Matcher entre StartsAt e ExpiresAt
+type Silence struct {
+	ID        string
+	Matcher   SilenceMatcher
+	Comment   string
+	CreatedBy string
+	CreatedAt time.Time
+	StartsAt  time.Time
+	ExpiresAt time.Time
+}
+
+// ActiveAt indica se o silence vale no instante informado
+func (s *Silence) ActiveAt(t time.Time) bool {
+	return !t.Before(s.StartsAt) && t.Before(s.ExpiresAt)
+}
+
+// AlertFilter filtros de ListAlerts
+type AlertFilter struct {
+	Cluster string       // Sem sufixo -admin (vazio = todos)
+	States  []AlertState // Vazio = todos
+	Limit   int          // Default: 500 (negativo = sem limite)
+}
+
+const alertColumns = `
+	id, fingerprint, cluster, namespace, hpa_name, type, rule, severity, message, description,
+	state, first_seen, last_seen, occurrences, acked_by, acked_at, resolved_at, resolve_reason, silence_id`
+
+// SyncAlerts registra as anomalias detectadas no scan de um cluster e avança o ciclo de vida:
+// cria/atualiza alertas ativos, aplica silences, resolve os que não foram detectados
+// dentro de ResolveAfter e aplica MaxActiveAlerts. Retorna o alerta de cada anomalia (mesma ordem).
+func (p *Persistence) SyncAlerts(cluster string, detected []Alert, policy AlertPolicy, now time.Time) ([]Alert, error) {
+	if !p.config.Enabled || p.db == nil {
+		return nil, nil
+	}
+
+	now = now.UTC()
+	cluster = strings.TrimSuffix(cluster, "-admin")
+
+	tx, err := p.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	silences, err := p.syncSilences(tx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Alert, 0, len(detected))
+	for _, alert := range detected {
+		alert.Cluster = strings.TrimSuffix(alert.Cluster, "-admin")
+		alert.Fingerprint = AlertFingerprint(alert.Cluster, alert.Namespace, alert.HPAName, alert.Type, alert.Rule)
+
+		existing, err := scanAlert(tx.QueryRow(`SELECT `+alertColumns+` FROM alerts
+			WHERE fingerprint = ? AND state != ? ORDER BY first_seen DESC LIMIT 1`,
+			alert.Fingerprint, AlertStateResolved))
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			alert.ID = uuid.New().String()
+			alert.State = AlertStateOpen
+			alert.FirstSeen = now
+			alert.LastSeen = now
+			alert.Occurrences = 1
+			applySilences(&alert, silences)
+
+			if _, err := tx.Exec(`
+				INSERT INTO alerts (
+					id, fingerprint, cluster, namespace, hpa_name, type, rule, severity, message,
+					description, state, first_seen, last_seen, occurrences, silence_id
+				) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`, alert.ID, alert.Fingerprint, alert.Cluster, alert.Namespace, alert.HPAName, alert.Type, alert.Rule,
+				alert.Severity, alert.Message, alert.Description, alert.State, now, now, 1, alert.SilenceID); err != nil {
+				return nil, fmt.Errorf("failed to insert alert: %w", err)
+			}
+		case err != nil:
+			return nil, fmt.Errorf("failed to query alert: %w", err)
+		default:
+			existing.Severity = alert.Severity
+			existing.Message = alert.Message
+			existing.Description = alert.Description
+			existing.LastSeen = now
+			existing.Occurrences++
+			alert = *existing
+
+			if _, err := tx.Exec(`
+				UPDATE alerts SET severity = ?, message = ?, description = ?, last_seen = ?, occurrences = ?
+				WHERE id = ?
+			`, alert.Severity, alert.Message, alert.Description, now, alert.Occurrences, alert.ID); err != nil {
+				return nil, fmt.Errorf("failed to update alert: %w", err)
+			}
+		}
+		result = append(result, alert)
+	}
+
+	// Condição deixou de ser detectada
+	rows, err := tx.Query(`SELECT id, acked_at FROM alerts WHERE cluster = ? AND state != ? AND last_seen < ?`,
+		cluster, AlertStateResolved, now.Add(-policy.ResolveAfter))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stale alerts: %w", err)
+	}
+	cleared, err := collectAlertIDs(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveAlerts(tx, cleared, ResolveReasonCleared, policy, now); err != nil {
+		return nil, err
+	}
+
+	// Fila limitada: resolve os ativos mais antigos (last_seen) acima do limite
+	if policy.MaxActiveAlerts > 0 {
+		rows, err := tx.Query(`SELECT id, acked_at FROM alerts WHERE state != ?
+			ORDER BY last_seen DESC, first_seen DESC LIMIT -1 OFFSET ?`,
+			AlertStateResolved, policy.MaxActiveAlerts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query active alerts: %w", err)
+		}
+		evicted, err := collectAlertIDs(rows)
+		if err != nil {
+			return nil, err
+		}
+		if err := resolveAlerts(tx, evicted, ResolveReasonEvicted, policy, now); err != nil {
+			return nil, err
+		}
+		if len(evicted) > 0 {
+			log.Warn().
+				Int("evicted", len(evicted)).
+				Int("max_active_alerts", policy.MaxActiveAlerts).
+				Msg("Limite de alertas ativos atingido, resolvendo os mais antigos")
+		}
+
+		evictedIDs := make(map[string]bool, len(evicted))
+		for _, candidate := range evicted {
+			evictedIDs[candidate.id] = true
+		}
+		for i := range result {
+			if evictedIDs[result[i].ID] {
+				result[i].State = AlertStateResolved
+				result[i].ResolveReason = ResolveReasonEvicted
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit alerts: %w", err)
+	}
+
+	if len(cleared) > 0 {
+		log.Info().
+			Str("cluster", cluster).
+			Int("resolved", len(cleared)).
+			Msg("Alertas resolvidos automaticamente")
+	}
+	return result, nil
+}
+
+type alertRef struct {
+	id    string
+	acked bool
+}
+
+func collectAlertIDs(rows *sql.Rows) ([]alertRef, error) {
+	defer rows.Close()
+
+	var refs []alertRef
+	for rows.Next() {
+		var ref alertRef
+		var ackedAt sql.NullTime
+		if err := rows.Scan(&ref.id, &ackedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan alert: %w", err)
+		}
+		ref.acked = ackedAt.Valid
+		refs = append(refs, ref)
+	}
+	return refs, rows.Err()
+}
+
+// resolveAlerts marca os alertas como resolvidos (com ack automático se a política pedir)
+func resolveAlerts(tx *sql.Tx, refs []alertRef, reason string, policy AlertPolicy, now time.Time) error {
+	for _, ref := range refs {
+		var err error
+		if policy.AutoAckResolved && !ref.acked {
+			_, err = tx.Exec(`UPDATE alerts SET state = ?, resolved_at = ?, resolve_reason = ?, acked_by = ?, acked_at = ?
+				WHERE id = ?`, AlertStateResolved, now, reason, AutoAckUser, now, ref.id)
+		} else {
+			_, err = tx.Exec(`UPDATE alerts SET state = ?, resolved_at = ?, resolve_reason = ? WHERE id = ?`,
+				AlertStateResolved, now, reason, ref.id)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to resolve alert %s: %w", ref.id, err)
+		}
+	}
+	return nil
+}
+
+// applySilences marca o alerta como silenciado se algum silence ativo casar
+func applySilences(alert *Alert, silences []Silence) {
+	for _, silence := range silences {
+		if silence.Matcher.Matches(alert) {
+			if alert.State == AlertStateOpen {
+				alert.State = AlertStateSilenced
+			}
+			alert.SilenceID = silence.ID
+			return
+		}
+	}
+}
+
+// syncSilences retorna os silences ativos e reabre alertas cujo silence expirou
+func (p *Persistence) syncSilences(tx *sql.Tx, now time.Time) ([]Silence, error) {
+	silences, err := querySilences(tx, `SELECT id, matchers, comment, created_by, created_at, starts_at, expires_at
+		FROM alert_silences WHERE expires_at > ? ORDER BY created_at`, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var active []Silence
+	activeIDs := make(map[string]bool)
+	for _, silence := range silences {
+		if silence.ActiveAt(now) {
+			active = append(active, silence)
+			activeIDs[silence.ID] = true
+		}
+	}
+
+	rows, err := tx.Query(`SELECT `+alertColumns+` FROM alerts WHERE state != ?`, AlertStateResolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query active alerts: %w", err)
+	}
+	var alerts []*Alert
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan alert: %w", err)
+		}
+		alerts = append(alerts, alert)
+	}
+	rows.Close()
+
+	for _, alert := range alerts {
+		if alert.SilenceID != "" && activeIDs[alert.SilenceID] {
+			continue
+		}
+
+		// Silence expirado (ou removido): volta para open/acked; depois tenta outro silence ativo
+		state, silenceID := alert.State, alert.SilenceID
+		if alert.State == AlertStateSilenced {
+			alert.State = AlertStateOpen
+		}
+		alert.SilenceID = ""
+		applySilences(alert, active)
+
+		if alert.State == state && alert.SilenceID == silenceID {
+			continue
+		}
+		if _, err := tx.Exec(`UPDATE alerts SET state = ?, silence_id = ? WHERE id = ?`,
+			alert.State, alert.SilenceID, alert.ID); err != nil {
+			return nil, fmt.Errorf("failed to update silenced alert: %w", err)
+		}
+	}
+	return active, nil
+}
+
+// ApplySilences reavalia os silences sobre os alertas ativos (após criar/expirar silences)
+func (p *Persistence) ApplySilences(now time.Time) error {
+	if !p.config.Enabled || p.db == nil {
+		return nil
+	}
+
+	tx, err := p.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := p.syncSilences(tx, now.UTC()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListAlerts lista alertas (mais recentes primeiro)
+func (p *Persistence) ListAlerts(filter AlertFilter) ([]Alert, error) {
+	if !p.config.Enabled || p.db == nil {
+		return nil, fmt.Errorf("persistence not enabled")
+	}
+
+	query := `SELECT ` + alertColumns + ` FROM alerts WHERE 1 = 1`
+	var args []interface{}
+	if filter.Cluster != "" {
+		query += ` AND cluster = ?`
+		args = append(args, strings.TrimSuffix(filter.Cluster, "-admin"))
+	}
+	if len(filter.States) > 0 {
+		query += ` AND state IN (?` + strings.Repeat(`, ?`, len(filter.States)-1) + `)`
+		for _, state := range filter.States {
+			args = append(args, state)
+		}
+	}
+	limit := filter.Limit
+	if limit == 0 {
+		limit = 500
+	}
+	query += ` ORDER BY last_seen DESC, first_seen DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := p.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alerts: %w", err)
+	}
+	defer rows.Close()
+
+	alerts := make([]Alert, 0)
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert: %w", err)
+		}
+		alerts = append(alerts, *alert)
+	}
+	return alerts, rows.Err()
+}
+
+// GetAlert busca um alerta pelo ID
+func (p *Persistence) GetAlert(id string) (*Alert, error) {
+	if !p.config.Enabled || p.db == nil {
+		return nil, fmt.Errorf("persistence not enabled")
+	}
+
+	alert, err := scanAlert(p.db.QueryRow(`SELECT `+alertColumns+` FROM alerts WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAlertNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert: %w", err)
+	}
+	return alert, nil
+}
+
+// AckAlert registra o ack (alertas ativos passam para acked; resolvidos mantêm o estado)
+func (p *Persistence) AckAlert(id, by string, now time.Time) (*Alert, error) {
+	alert, err := p.GetAlert(id)
+	if err != nil {
+		return nil, err
+	}
+
+	state := alert.State
+	if alert.Active() {
+		state = AlertStateAcked
+	}
+	if _, err := p.db.Exec(`UPDATE alerts SET state = ?, acked_by = ?, acked_at = ? WHERE id = ?`,
+		state, by, now.UTC(), id); err != nil {
+		return nil, fmt.Errorf("failed to ack alert: %w", err)
+	}
+	return p.GetAlert(id)
+}
+
+// UnackAlert remove o ack (acked volta para open, ou silenced se ainda houver silence)
+func (p *Persistence) UnackAlert(id string) (*Alert, error) {
+	alert, err := p.GetAlert(id)
+	if err != nil {
+		return nil, err
+	}
+
+	state := alert.State
+	if state == AlertStateAcked {
+		state = AlertStateOpen
+		if alert.SilenceID != "" {
+			state = AlertStateSilenced
+		}
+	}
+	if _, err := p.db.Exec(`UPDATE alerts SET state = ?, acked_by = '', acked_at = NULL WHERE id = ?`,
+		state, id); err != nil {
+		return nil, fmt.Errorf("failed to unack alert: %w", err)
+	}
+	return p.GetAlert(id)
+}
+
+// CreateSilence salva o silence e aplica aos alertas ativos que casam
+func (p *Persistence) CreateSilence(silence Silence) (*Silence, error) {
+	if !p.config.Enabled || p.db == nil {
+		return nil, fmt.Errorf("persistence not enabled")
+	}
+	if err := silence.Matcher.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if silence.StartsAt.IsZero() {
+		silence.StartsAt = now
+	}
+	if !silence.ExpiresAt.After(silence.StartsAt) {
+		return nil, fmt.Errorf("silence must expire after it starts")
+	}
+	silence.ID = uuid.New().String()
+	silence.CreatedAt = now
+	silence.StartsAt = silence.StartsAt.UTC()
+	silence.ExpiresAt = silence.ExpiresAt.UTC()
+
+	matchers, err := json.Marshal(silence.Matcher)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode matchers: %w", err)
+	}
+	if _, err := p.db.Exec(`
+		INSERT INTO alert_silences (id, matchers, comment, created_by, created_at, starts_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, silence.ID, string(matchers), silence.Comment, silence.CreatedBy, silence.CreatedAt, silence.StartsAt, silence.ExpiresAt); err != nil {
+		return nil, fmt.Errorf("failed to save silence: %w", err)
+	}
+
+	if err := p.ApplySilences(now); err != nil {
+		return nil, err
+	}
+	return &silence, nil
+}
+
+// ExpireSilence encerra o silence agora e reabre os alertas cobertos por ele
+func (p *Persistence) ExpireSilence(id string) error {
+	if !p.config.Enabled || p.db == nil {
+		return fmt.Errorf("persistence not enabled")
+	}
+
+	now := time.Now().UTC()
+	result, err := p.db.Exec(`UPDATE alert_silences SET expires_at = ? WHERE id = ? AND expires_at > ?`, now, id, now)
+	if err != nil {
+		return fmt.Errorf("failed to expire silence: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrAlertNotFound
+	}
+	return p.ApplySilences(now)
+}
+
+// ListSilences lista silences (includeExpired=false retorna apenas os não expirados)
+func (p *Persistence) ListSilences(includeExpired bool) ([]Silence, error) {
+	if !p.config.Enabled || p.db == nil {
+		return nil, fmt.Errorf("persistence not enabled")
+	}
+
+	query := `SELECT id, matchers, comment, created_by, created_at, starts_at, expires_at FROM alert_silences`
+	var args []interface{}
+	if !includeExpired {
+		query += ` WHERE expires_at > ?`
+		args = append(args, time.Now().UTC())
+	}
+	return querySilences(p.db, query+` ORDER BY created_at DESC`, args...)
+}
+
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func querySilences(q queryer, query string, args ...interface{}) ([]Silence, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query silences: %w", err)
+	}
+	defer rows.Close()
+
+	silences := make([]Silence, 0)
+	for rows.Next() {
+		var silence Silence
+		var matchers string
+		if err := rows.Scan(&silence.ID, &matchers, &silence.Comment, &silence.CreatedBy,
+			&silence.CreatedAt, &silence.StartsAt, &silence.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan silence: %w", err)
+		}
+		if err := json.Unmarshal([]byte(matchers), &silence.Matcher); err != nil {
+			log.Warn().Err(err).Str("silence", silence.ID).Msg("Silence com matchers inválidos ignorado")
+			continue
+		}
+		silences = append(silences, silence)
+	}
+	return silences, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAlert(row rowScanner) (*Alert, error) {
+	var alert Alert
+	var ackedAt, resolvedAt sql.NullTime
+	if err := row.Scan(&alert.ID, &alert.Fingerprint, &alert.Cluster, &alert.Namespace, &alert.HPAName,
+		&alert.Type, &alert.Rule, &alert.Severity, &alert.Message, &alert.Description, &alert.State,
+		&alert.FirstSeen, &alert.LastSeen, &alert.Occurrences, &alert.AckedBy, &ackedAt, &resolvedAt,
+		&alert.ResolveReason, &alert.SilenceID); err != nil {
+		return nil, err
+	}
+	if ackedAt.Valid {
+		alert.AckedAt = &ackedAt.Time
+	}
+	if resolvedAt.Valid {
+		alert.ResolvedAt = &resolvedAt.Time
+	}
+	return &alert, nil
+}
+
+// cleanupAlerts remove alertas resolvidos e silences expirados antes do cutoff
+func (p *Persistence) cleanupAlerts(cutoff time.Time) error {
+	if _, err := p.db.Exec(`DELETE FROM alerts WHERE state = ? AND resolved_at < ?`,
+		AlertStateResolved, cutoff.UTC()); err != nil {
+		return fmt.Errorf("failed to cleanup alerts: %w", err)
+	}
+	if _, err := p.db.Exec(`DELETE FROM alert_silences WHERE expires_at < ?`, cutoff.UTC()); err != nil {
+		return fmt.Errorf("failed to cleanup silences: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s-hpa-manager/internal/monitoring/models"
+)
+
+func newTestPersistence(t *testing.T) *Persistence {
+	t.Helper()
+	p, err := NewPersistence(&PersistenceConfig{
+		Enabled: true,
+		DBPath:  filepath.Join(t.TempDir(), "alerts.db"),
+		MaxAge:  24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create persistence: %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+func detectedAlert(hpa, alertType string) Alert {
+	return Alert{
+		Cluster:   "aks-prd-admin",
+		Namespace: "api",
+		HPAName:   hpa,
+		Type:      alertType,
+		Severity:  models.SeverityCritical,
+		Message:   alertType + " em " + hpa,
+	}
+}
+
+func TestSyncAlertsLifecycle(t *testing.T) {
+	p := newTestPersistence(t)
+	policy := AlertPolicy{ResolveAfter: 5 * time.Minute}
+	now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+
+	result, err := p.SyncAlerts("aks-prd", []Alert{detectedAlert("web", "OOM_KILLED"), detectedAlert("worker", "CRASH_LOOP")}, policy, now)
+	if err != nil {
+		t.Fatalf("SyncAlerts failed: %v", err)
+	}
+	if len(result) != 2 || result[0].State != AlertStateOpen || result[0].Cluster != "aks-prd" || result[0].ID == "" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	webID := result[0].ID
+
+	// Nova detecção atualiza o mesmo alerta
+	now = now.Add(time.Minute)
+	result, _ = p.SyncAlerts("aks-prd", []Alert{detectedAlert("web", "OOM_KILLED")}, policy, now)
+	if result[0].ID != webID || result[0].Occurrences != 2 {
+		t.Errorf("expected same alert with 2 occurrences, got %+v", result[0])
+	}
+
+	if _, err := p.AckAlert(webID, "maria", now); err != nil {
+		t.Fatalf("AckAlert failed: %v", err)
+	}
+
+	// worker não é detectado há mais de 5min -> resolvido (sem ack automático)
+	now = now.Add(5 * time.Minute)
+	p.SyncAlerts("aks-prd", []Alert{detectedAlert("web", "OOM_KILLED")}, policy, now)
+
+	alerts, err := p.ListAlerts(AlertFilter{Cluster: "aks-prd-admin"})
+	if err != nil {
+		t.Fatalf("ListAlerts failed: %v", err)
+	}
+	states := map[string]*Alert{}
+	for i := range alerts {
+		states[alerts[i].HPAName] = &alerts[i]
+	}
+	if web := states["web"]; web.State != AlertStateAcked || web.AckedBy != "maria" || web.AckedAt == nil {
+		t.Errorf("web should stay acked, got %+v", web)
+	}
+	if worker := states["worker"]; worker.State != AlertStateResolved || worker.ResolveReason != ResolveReasonCleared || worker.AckedAt != nil {
+		t.Errorf("worker should be resolved without ack, got %+v", worker)
+	}
+
+	// Voltou a ocorrer depois de resolvido -> novo alerta
+	result, _ = p.SyncAlerts("aks-prd", []Alert{detectedAlert("worker", "CRASH_LOOP")}, policy, now)
+	if result[0].ID == states["worker"].ID || result[0].State != AlertStateOpen {
+		t.Errorf("expected new open alert after resolution, got %+v", result[0])
+	}
+
+	// Outro cluster não resolve alertas deste
+	now = now.Add(time.Hour)
+	p.SyncAlerts("aks-hlg", nil, policy, now)
+	if open, _ := p.ListAlerts(AlertFilter{States: []AlertState{AlertStateOpen, AlertStateAcked}}); len(open) != 2 {
+		t.Errorf("scan of another cluster should not resolve alerts, got %d active", len(open))
+	}
+
+	if _, err := p.AckAlert("missing", "maria", now); err != ErrAlertNotFound {
+		t.Errorf("expected ErrAlertNotFound, got %v", err)
+	}
+}
+
+func TestSyncAlertsPolicy(t *testing.T) {
+	p := newTestPersistence(t)
+	policy := AlertPolicyFromWatchdog(&models.WatchdogConfig{MaxActiveAlerts: 2, AutoAckResolvedAlerts: true})
+	now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+
+	for i, hpa := range []string{"a", "b", "c"} {
+		result, err := p.SyncAlerts("aks-prd", []Alert{detectedAlert(hpa, "CPU_SPIKE")}, policy, now.Add(time.Duration(i)*time.Second))
+		if err != nil {
+			t.Fatalf("SyncAlerts failed: %v", err)
+		}
+		if result[0].State != AlertStateOpen {
+			t.Errorf("newest alert should stay open, got %s", result[0].State)
+		}
+	}
+
+	alerts, _ := p.ListAlerts(AlertFilter{})
+	active := 0
+	for _, alert := range alerts {
+		if alert.Active() {
+			active++
+			continue
+		}
+		if alert.HPAName != "a" || alert.ResolveReason != ResolveReasonEvicted || alert.AckedBy != AutoAckUser {
+			t.Errorf("oldest alert should be evicted and auto-acked, got %+v", alert)
+		}
+	}
+	if active != 2 {
+		t.Errorf("expected 2 active alerts, got %d", active)
+	}
+}
+
+func TestSilences(t *testing.T) {
+	p := newTestPersistence(t)
+	policy := DefaultAlertPolicy()
+	now := time.Now().UTC()
+
+	result, _ := p.SyncAlerts("aks-prd", []Alert{detectedAlert("web", "CPU_SPIKE"), detectedAlert("worker", "CPU_SPIKE")}, policy, now)
+	webID := result[0].ID
+
+	if _, err := p.CreateSilence(Silence{Matcher: SilenceMatcher{}, ExpiresAt: now.Add(time.Hour)}); err == nil {
+		t.Error("expected error for silence without matchers")
+	}
+	if _, err := p.CreateSilence(Silence{Matcher: SilenceMatcher{HPA: "web"}, ExpiresAt: now.Add(-time.Hour)}); err == nil {
+		t.Error("expected error for silence already expired")
+	}
+
+	silence, err := p.CreateSilence(Silence{
+		Matcher:   SilenceMatcher{Cluster: "aks-prd-admin", HPA: "w*b"},
+		Comment:   "stress test",
+		CreatedBy: "maria",
+		ExpiresAt: now.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("CreateSilence failed: %v", err)
+	}
+
+	web, _ := p.GetAlert(webID)
+	if web.State != AlertStateSilenced || web.SilenceID != silence.ID {
+		t.Errorf("existing alert should be silenced, got %+v", web)
+	}
+
+	// Nova detecção continua silenciada; worker não casa
+	result, _ = p.SyncAlerts("aks-prd", []Alert{detectedAlert("web", "CPU_SPIKE"), detectedAlert("worker", "CPU_SPIKE")}, policy, now.Add(time.Minute))
+	if result[0].State != AlertStateSilenced || result[1].State != AlertStateOpen {
+		t.Errorf("unexpected states: %s, %s", result[0].State, result[1].State)
+	}
+
+	silences, _ := p.ListSilences(false)
+	if len(silences) != 1 || silences[0].Matcher.HPA != "w*b" || silences[0].Comment != "stress test" {
+		t.Errorf("unexpected silences: %+v", silences)
+	}
+
+	if err := p.ExpireSilence(silence.ID); err != nil {
+		t.Fatalf("ExpireSilence failed: %v", err)
+	}
+	if err := p.ExpireSilence(silence.ID); err != ErrAlertNotFound {
+		t.Errorf("expected ErrAlertNotFound for expired silence, got %v", err)
+	}
+
+	web, _ = p.GetAlert(webID)
+	if web.State != AlertStateOpen || web.SilenceID != "" {
+		t.Errorf("alert should reopen after silence expires, got %+v", web)
+	}
+	if silences, _ := p.ListSilences(false); len(silences) != 0 {
+		t.Errorf("expired silence should not be listed, got %+v", silences)
+	}
+}
@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EngineStatus estado do monitoring engine escolhido pelo operador
+type EngineStatus string
+
+const (
+	EngineStatusRunning EngineStatus = "running"
+	EngineStatusPaused  EngineStatus = "paused"
+	EngineStatusStopped EngineStatus = "stopped"
+)
+
+// Chaves do estado do engine na tabela metadata
+const (
+	metadataEngineStatus   = "monitoring_engine_status"
+	metadataEngineInterval = "monitoring_scan_interval"
+)
+
+// MonitoredHPA HPA escolhido para monitoramento prioritário (restaurado no startup do servidor)
+type MonitoredHPA struct {
+	Cluster   string    `json:"cluster"` // sem o sufixo -admin
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"hpa"`
+	AddedBy   string    `json:"added_by,omitempty"`
+	AddedAt   time.Time `json:"added_at"`
+}
+
+// Key identifica o HPA (cluster/namespace/nome)
+func (h MonitoredHPA) Key() string {
+	return h.Cluster + "/" + h.Namespace + "/" + h.Name
+}
+
+// EngineState estado persistido do engine (nil em LoadEngineState = nunca salvo)
+type EngineState struct {
+	Status   EngineStatus
+	Interval time.Duration // 0 = intervalo padrão do collector
+}
+
+// monitoringStateSchema HPAs monitorados (status e intervalo ficam em metadata)
+func monitoringStateSchema() string {
+	return `
+	CREATE TABLE IF NOT EXISTS monitored_hpas (
+		cluster TEXT NOT NULL,
+		namespace TEXT NOT NULL,
+		hpa_name TEXT NOT NULL,
+		added_by TEXT NOT NULL DEFAULT '',
+		added_at DATETIME NOT NULL,
+
+		PRIMARY KEY (cluster, namespace, hpa_name)
+	);
+	`
+}
+
+// SaveMonitoredHPA registra o HPA (mantém added_at/added_by se já existir)
+func (p *Persistence) SaveMonitoredHPA(hpa MonitoredHPA) error {
+	if !p.config.Enabled || p.db == nil {
+		return nil
+	}
+	if hpa.AddedAt.IsZero() {
+		hpa.AddedAt = time.Now()
+	}
+	_, err := p.db.Exec(`INSERT OR IGNORE INTO monitored_hpas (cluster, namespace, hpa_name, added_by, added_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		strings.TrimSuffix(hpa.Cluster, "-admin"), hpa.Namespace, hpa.Name, hpa.AddedBy, hpa.AddedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to save monitored HPA: %w", err)
+	}
+	return nil
+}
+
+// DeleteMonitoredHPA remove o HPA (namespace e nome vazios removem o cluster inteiro)
+func (p *Persistence) DeleteMonitoredHPA(cluster, namespace, name string) error {
+	if !p.config.Enabled || p.db == nil {
+		return nil
+	}
+	cluster = strings.TrimSuffix(cluster, "-admin")
+
+	var err error
+	if namespace == "" && name == "" {
+		_, err = p.db.Exec(`DELETE FROM monitored_hpas WHERE cluster = ?`, cluster)
+	} else {
+		_, err = p.db.Exec(`DELETE FROM monitored_hpas WHERE cluster = ? AND namespace = ? AND hpa_name = ?`,
+			cluster, namespace, name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete monitored HPA: %w", err)
+	}
+	return nil
+}
+
+// ListMonitoredHPAs lista os HPAs monitorados em ordem de inclusão
+func (p *Persistence) ListMonitoredHPAs() ([]MonitoredHPA, error) {
+	if !p.config.Enabled || p.db == nil {
+		return nil, nil
+	}
+	rows, err := p.db.Query(`SELECT cluster, namespace, hpa_name, added_by, added_at FROM monitored_hpas
+		ORDER BY added_at, cluster, namespace, hpa_name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query monitored HPAs: %w", err)
+	}
+	defer rows.Close()
+
+	var hpas []MonitoredHPA
+	for rows.Next() {
+		var hpa MonitoredHPA
+		if err := rows.Scan(&hpa.Cluster, &hpa.Namespace, &hpa.Name, &hpa.AddedBy, &hpa.AddedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan monitored HPA: %w", err)
+		}
+		hpas = append(hpas, hpa)
+	}
+	return hpas, rows.Err()
+}
+
+// SaveEngineState grava status e intervalo do engine
+func (p *Persistence) SaveEngineState(state EngineState) error {
+	if !p.config.Enabled || p.db == nil {
+		return nil
+	}
+	tx, err := p.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for key, value := range map[string]string{
+		metadataEngineStatus:   string(state.Status),
+		metadataEngineInterval: state.Interval.String(),
+	} {
+		if _, err := tx.Exec(`INSERT OR REPLACE INTO metadata (key, value, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)`,
+			key, value); err != nil {
+			return fmt.Errorf("failed to save engine state: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// LoadEngineState lê o estado salvo por SaveEngineState (nil se nunca foi salvo)
+func (p *Persistence) LoadEngineState() (*EngineState, error) {
+	if !p.config.Enabled || p.db == nil {
+		return nil, nil
+	}
+	var status, interval string
+	err := p.db.QueryRow(`SELECT value FROM metadata WHERE key = ?`, metadataEngineStatus).Scan(&status)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load engine state: %w", err)
+	}
+
+	state := &EngineState{Status: EngineStatus(status)}
+	switch state.Status {
+	case EngineStatusRunning, EngineStatusPaused, EngineStatusStopped:
+	default:
+		return nil, fmt.Errorf("invalid engine status %q", status)
+	}
+	if err := p.db.QueryRow(`SELECT value FROM metadata WHERE key = ?`, metadataEngineInterval).Scan(&interval); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to load scan interval: %w", err)
+	}
+	if interval != "" {
+		if state.Interval, err = time.ParseDuration(interval); err != nil {
+			return nil, fmt.Errorf("invalid scan interval %q: %w", interval, err)
+		}
+	}
+	return state, nil
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMonitoredHPAs(t *testing.T) {
+	p := newTestPersistence(t)
+	added := time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC)
+
+	hpas, err := p.ListMonitoredHPAs()
+	if err != nil || len(hpas) != 0 {
+		t.Fatalf("expected empty list, got %v (%v)", hpas, err)
+	}
+
+	for i, hpa := range []MonitoredHPA{
+		{Cluster: "aks-prd-admin", Namespace: "api", Name: "web", AddedBy: "maria", AddedAt: added},
+		{Cluster: "aks-prd", Namespace: "api", Name: "worker", AddedAt: added.Add(time.Minute)},
+		{Cluster: "aks-hlg", Namespace: "api", Name: "web", AddedAt: added.Add(2 * time.Minute)},
+	} {
+		if err := p.SaveMonitoredHPA(hpa); err != nil {
+			t.Fatalf("SaveMonitoredHPA %d failed: %v", i, err)
+		}
+	}
+
+	// Duplicado (cluster com -admin) mantém added_by/added_at originais
+	if err := p.SaveMonitoredHPA(MonitoredHPA{Cluster: "aks-prd", Namespace: "api", Name: "web", AddedBy: "joao"}); err != nil {
+		t.Fatalf("SaveMonitoredHPA duplicate failed: %v", err)
+	}
+
+	hpas, err = p.ListMonitoredHPAs()
+	if err != nil {
+		t.Fatalf("ListMonitoredHPAs failed: %v", err)
+	}
+	if len(hpas) != 3 {
+		t.Fatalf("expected 3 HPAs, got %+v", hpas)
+	}
+	if hpas[0].Key() != "aks-prd/api/web" || hpas[0].AddedBy != "maria" || !hpas[0].AddedAt.Equal(added) {
+		t.Errorf("unexpected first HPA: %+v", hpas[0])
+	}
+
+	if err := p.DeleteMonitoredHPA("aks-prd-admin", "api", "web"); err != nil {
+		t.Fatalf("DeleteMonitoredHPA failed: %v", err)
+	}
+	if err := p.DeleteMonitoredHPA("aks-hlg", "", ""); err != nil {
+		t.Fatalf("DeleteMonitoredHPA cluster failed: %v", err)
+	}
+	hpas, _ = p.ListMonitoredHPAs()
+	if len(hpas) != 1 || hpas[0].Key() != "aks-prd/api/worker" {
+		t.Errorf("expected only aks-prd/api/worker, got %+v", hpas)
+	}
+}
+
+func TestEngineState(t *testing.T) {
+	p := newTestPersistence(t)
+
+	state, err := p.LoadEngineState()
+	if err != nil || state != nil {
+		t.Fatalf("expected no saved state, got %+v (%v)", state, err)
+	}
+
+	if err := p.SaveEngineState(EngineState{Status: EngineStatusPaused, Interval: 2 * time.Minute}); err != nil {
+		t.Fatalf("SaveEngineState failed: %v", err)
+	}
+	if err := p.SaveEngineState(EngineState{Status: EngineStatusRunning, Interval: 45 * time.Second}); err != nil {
+		t.Fatalf("SaveEngineState failed: %v", err)
+	}
+
+	state, err = p.LoadEngineState()
+	if err != nil {
+		t.Fatalf("LoadEngineState failed: %v", err)
+	}
+	if state.Status != EngineStatusRunning || state.Interval != 45*time.Second {
+		t.Errorf("unexpected state: %+v", state)
+	}
+}
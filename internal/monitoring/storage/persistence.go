@@ -20,6 +20,7 @@ type PersistenceConfig struct {
 	MaxAge      time.Duration // Máximo tempo de retenção (default: 24h)
 	BatchSize   int           // Tamanho do batch para insert (default: 100)
 	AutoCleanup bool          // Limpeza automática de dados antigos
+	RollupTiers []RollupTier  // Tiers de downsampling para histórico longo (vazio = desabilitado)
 }
 
 // DefaultPersistenceConfig retorna configuração padrão
@@ -33,6 +34,7 @@ func DefaultPersistenceConfig() *PersistenceConfig {
 		MaxAge:      72 * time.Hour, // 3 dias de histórico para análise de stress test
 		BatchSize:   100,
 		AutoCleanup: true,
+		RollupTiers: DefaultRollupTiers(),
 	}
 }
 
@@ -321,6 +323,46 @@ func (p *Persistence) initSchema() error {
 
 	CREATE INDEX IF NOT EXISTS idx_test_recommendations_test_id ON stress_test_recommendations(test_id);
 	CREATE INDEX IF NOT EXISTS idx_test_recommendations_priority ON stress_test_recommendations(priority);
+
+	-- Alertas (ciclo de vida: open -> acked/silenced -> resolved)
+	CREATE TABLE IF NOT EXISTS alerts (
+		id TEXT PRIMARY KEY,
+		fingerprint TEXT NOT NULL,  -- cluster/namespace/hpa/tipo/regra
+		cluster TEXT NOT NULL,
+		namespace TEXT NOT NULL,
+		hpa_name TEXT NOT NULL,
+		type TEXT NOT NULL,
+		rule TEXT NOT NULL DEFAULT '',
+		severity INTEGER NOT NULL,
+		message TEXT NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		state TEXT NOT NULL,
+		first_seen DATETIME NOT NULL,
+		last_seen DATETIME NOT NULL,
+		occurrences INTEGER NOT NULL DEFAULT 1,
+		acked_by TEXT NOT NULL DEFAULT '',
+		acked_at DATETIME,
+		resolved_at DATETIME,
+		resolve_reason TEXT NOT NULL DEFAULT '',
+		silence_id TEXT NOT NULL DEFAULT ''
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_alerts_fingerprint ON alerts(fingerprint, state);
+	CREATE INDEX IF NOT EXISTS idx_alerts_cluster_state ON alerts(cluster, state);
+	CREATE INDEX IF NOT EXISTS idx_alerts_last_seen ON alerts(last_seen);
+
+	-- Silences (matchers glob + expiração)
+	CREATE TABLE IF NOT EXISTS alert_silences (
+		id TEXT PRIMARY KEY,
+		matchers TEXT NOT NULL,  -- JSON do SilenceMatcher
+		comment TEXT NOT NULL DEFAULT '',
+		created_by TEXT NOT NULL DEFAULT '',
+		created_at DATETIME NOT NULL,
+		starts_at DATETIME NOT NULL,
+		expires_at DATETIME NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_alert_silences_expires ON alert_silences(expires_at);
 	`
 
 	_, err := p.db.Exec(schema)
@@ -328,6 +370,14 @@ func (p *Persistence) initSchema() error {
 		return fmt.Errorf("failed to create schema: %w", err)
 	}
 
+	if _, err := p.db.Exec(rollupSchema()); err != nil {
+		return fmt.Errorf("failed to create rollup schema: %w", err)
+	}
+
+	if _, err := p.db.Exec(monitoringStateSchema()); err != nil {
+		return fmt.Errorf("failed to create monitoring state schema: %w", err)
+	}
+
 	// FASE 6: Migration para adicionar campos de baseline se não existirem
 	// Verifica se colunas já existem antes de adicionar
 	var columnExists int
@@ -479,12 +529,17 @@ func (p *Persistence) SaveSnapshots(snapshots []*models.HPASnapshot) error {
 	return nil
 }
 
-// LoadSnapshots carrega snapshots de um HPA específico
+// LoadSnapshots carrega snapshots de um HPA específico.
+// Períodos além de MaxAge são servidos pelos rollups (ver ResolutionFor).
 func (p *Persistence) LoadSnapshots(cluster, namespace, name string, since time.Time) ([]models.HPASnapshot, error) {
 	if !p.config.Enabled || p.db == nil {
 		return nil, nil
 	}
 
+	if tier := p.tierFor(since); tier != nil {
+		return p.loadRollupSnapshots(tier, cluster, namespace, name, since)
+	}
+
 	rows, err := p.db.Query(`
 		SELECT cluster, namespace, hpa_name, timestamp,
 			cpu_current, cpu_target, memory_current, memory_target,
@@ -580,6 +635,44 @@ func (p *Persistence) LoadSnapshots(cluster, namespace, name string, since time.
 	return snapshots, nil
 }
 
+// SnapshotHPA identifica um HPA com snapshots persistidos
+type SnapshotHPA struct {
+	Cluster   string
+	Namespace string
+	Name      string
+}
+
+// ListSnapshotHPAs lista os HPAs com snapshots desde since (cluster/namespace vazios = todos).
+// Inclui HPAs presentes apenas nos rollups.
+func (p *Persistence) ListSnapshotHPAs(cluster, namespace string, since time.Time) ([]SnapshotHPA, error) {
+	if !p.config.Enabled || p.db == nil {
+		return nil, nil
+	}
+
+	rows, err := p.db.Query(`
+		SELECT cluster, namespace, hpa_name FROM hpa_snapshots
+		WHERE timestamp >= ? AND (? = '' OR cluster = ?) AND (? = '' OR namespace = ?)
+		UNION
+		SELECT cluster, namespace, hpa_name FROM hpa_snapshot_rollups
+		WHERE bucket_start >= ? AND (? = '' OR cluster = ?) AND (? = '' OR namespace = ?)
+		ORDER BY 1, 2, 3
+	`, since, cluster, cluster, namespace, namespace, since, cluster, cluster, namespace, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list HPAs: %w", err)
+	}
+	defer rows.Close()
+
+	var hpas []SnapshotHPA
+	for rows.Next() {
+		var hpa SnapshotHPA
+		if err := rows.Scan(&hpa.Cluster, &hpa.Namespace, &hpa.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan HPA: %w", err)
+		}
+		hpas = append(hpas, hpa)
+	}
+	return hpas, rows.Err()
+}
+
 // LoadSnapshotsYesterday busca dados de ontem no mesmo período de tempo
 // Para comparação D-1 (yesterday vs today)
 // Exemplo: se duration = 5m, busca snapshots de ontem no intervalo [agora-24h-5m, agora-24h]
@@ -705,18 +798,29 @@ func hydrateSnapshotFromJSON(snapshot *models.HPASnapshot, metricsJSON string) {
 	snapshot.P99Latency = extended.P99Latency
 	snapshot.NetworkRxBytes = extended.NetworkRxBytes
 	snapshot.NetworkTxBytes = extended.NetworkTxBytes
+	snapshot.RestartCount = extended.RestartCount
+	snapshot.OOMKilledCount = extended.OOMKilledCount
+	snapshot.CrashLoopPods = extended.CrashLoopPods
+	snapshot.LastTerminationReason = extended.LastTerminationReason
+	snapshot.LastTerminationTime = extended.LastTerminationTime
+	snapshot.LastOOMKilledTime = extended.LastOOMKilledTime
 
 	if len(extended.AdditionalMetrics) > 0 {
 		snapshot.AdditionalMetrics = extended.AdditionalMetrics
 	}
 }
 
-// LoadAll carrega todos os snapshots recentes (últimos MaxAge)
+// LoadAll carrega todos os snapshots recentes (últimos MaxAge).
+// Períodos além de MaxAge são servidos pelos rollups (ver ResolutionFor).
 func (p *Persistence) LoadAll(since time.Time) (map[string][]models.HPASnapshot, error) {
 	if !p.config.Enabled || p.db == nil {
 		return nil, nil
 	}
 
+	if tier := p.tierFor(since); tier != nil {
+		return p.loadAllRollups(tier, since)
+	}
+
 	rows, err := p.db.Query(`
 		SELECT cluster, namespace, hpa_name, data FROM snapshots
 		WHERE timestamp >= ?
@@ -781,6 +885,14 @@ func (p *Persistence) Cleanup() error {
 			Msg("Cleanup: removed old snapshots")
 	}
 
+	if err := p.cleanupAlerts(cutoff); err != nil {
+		log.Warn().Err(err).Msg("Failed to cleanup alerts")
+	}
+
+	if err := p.cleanupRollups(cutoff); err != nil {
+		log.Warn().Err(err).Msg("Failed to cleanup rollups")
+	}
+
 	// VACUUM para reduzir tamanho do arquivo
 	if rows > 1000 {
 		if _, err := p.db.Exec("VACUUM"); err != nil {
@@ -934,12 +1046,34 @@ func (p *Persistence) SaveStressTestResult(testID string, result interface{}) er
 		return fmt.Errorf("failed to marshal result: %w", err)
 	}
 
-	// TODO: Extrair campos do result para colunas individuais
-	_, err = p.db.Exec(`
-		INSERT INTO stress_test_results (
-			test_id, test_name, start_time, status, result_data
-		) VALUES (?, ?, ?, ?, ?)
-	`, testID, "Stress Test", time.Now(), "completed", string(resultJSON))
+	metrics, ok := result.(*models.StressTestMetrics)
+	if !ok {
+		_, err = p.db.Exec(`
+			INSERT INTO stress_test_results (
+				test_id, test_name, start_time, status, result_data
+			) VALUES (?, ?, ?, ?, ?)
+		`, testID, "Stress Test", time.Now(), "completed", string(resultJSON))
+	} else {
+		// Colunas individuais alimentam ListStressTests sem decodificar result_data
+		peak := metrics.PeakMetrics
+		_, err = p.db.Exec(`
+			INSERT INTO stress_test_results (
+				test_id, test_name, start_time, end_time, duration_seconds, status,
+				scan_interval_seconds, total_scans, total_clusters, total_hpas,
+				total_hpas_monitored, total_hpas_with_issues, health_percentage,
+				critical_issues_count, warning_issues_count, info_issues_count,
+				peak_cpu_percent, peak_cpu_hpa, peak_memory_percent, peak_memory_hpa,
+				total_replicas_pre, total_replicas_peak, total_replicas_post,
+				replica_increase, replica_increase_percent, test_result, result_data
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, testID, metrics.TestName, metrics.StartTime, metrics.EndTime, int64(metrics.Duration.Seconds()),
+			string(metrics.Status), int64(metrics.ScanInterval.Seconds()), metrics.TotalScans,
+			metrics.TotalClusters, metrics.TotalHPAs, metrics.TotalHPAsMonitored, metrics.TotalHPAsWithIssues,
+			metrics.GetHealthPercentage(), len(metrics.CriticalIssues), len(metrics.WarningIssues),
+			len(metrics.InfoIssues), peak.MaxCPUPercent, peak.MaxCPUHPA, peak.MaxMemoryPercent,
+			peak.MaxMemoryHPA, peak.TotalReplicasPre, peak.TotalReplicasPeak, peak.TotalReplicasPost,
+			peak.ReplicaIncrease, peak.ReplicaIncreaseP, metrics.GetTestResult(), string(resultJSON))
+	}
 
 	if err != nil {
 		return fmt.Errorf("failed to save stress test result: %w", err)
@@ -0,0 +1,575 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"k8s-hpa-manager/internal/monitoring/models"
+)
+
+// RollupTier nível de downsampling dos snapshots (agregados min/avg/max/p95 por bucket)
+type RollupTier struct {
+	Name       string        // Ex: "5m"
+	Resolution time.Duration // Tamanho do bucket
+	Retention  time.Duration // Tempo mantido no banco
+}
+
+// DefaultRollupTiers retorna os tiers padrão: 5m por 14 dias e 1h por 90 dias
+func DefaultRollupTiers() []RollupTier {
+	return []RollupTier{
+		{Name: "5m", Resolution: 5 * time.Minute, Retention: 14 * 24 * time.Hour},
+		{Name: "1h", Resolution: time.Hour, Retention: 90 * 24 * time.Hour},
+	}
+}
+
+// DefaultCompactionInterval intervalo do compactor em background
+const DefaultCompactionInterval = 5 * time.Minute
+
+// ResolutionRaw resolução retornada por ResolutionFor quando a consulta usa os snapshots brutos
+const ResolutionRaw = "raw"
+
+// rollupMetric métrica agregada nos rollups (colunas <name>_min/_avg/_max/_p95)
+type rollupMetric struct {
+	name string
+	get  func(*models.HPASnapshot) float64
+	set  func(*models.HPASnapshot, float64)
+}
+
+var rollupMetrics = []rollupMetric{
+	{"cpu", func(s *models.HPASnapshot) float64 { return s.CPUCurrent }, func(s *models.HPASnapshot, v float64) { s.CPUCurrent = v }},
+	{"memory", func(s *models.HPASnapshot) float64 { return s.MemoryCurrent }, func(s *models.HPASnapshot, v float64) { s.MemoryCurrent = v }},
+	{"replicas", func(s *models.HPASnapshot) float64 { return float64(s.CurrentReplicas) }, func(s *models.HPASnapshot, v float64) { s.CurrentReplicas = int32(math.Round(v)) }},
+	{"desired_replicas", func(s *models.HPASnapshot) float64 { return float64(s.DesiredReplicas) }, func(s *models.HPASnapshot, v float64) { s.DesiredReplicas = int32(math.Round(v)) }},
+	{"request_rate", func(s *models.HPASnapshot) float64 { return s.RequestRate }, func(s *models.HPASnapshot, v float64) { s.RequestRate = v }},
+	{"error_rate", func(s *models.HPASnapshot) float64 { return s.ErrorRate }, func(s *models.HPASnapshot, v float64) { s.ErrorRate = v }},
+	{"p95_latency", func(s *models.HPASnapshot) float64 { return s.P95Latency }, func(s *models.HPASnapshot, v float64) { s.P95Latency = v }},
+}
+
+// Configuração do HPA no fim do bucket (último snapshot)
+const rollupConfigColumns = `cpu_target, memory_target, min_replicas, max_replicas,
+	cpu_request, cpu_limit, memory_request, memory_limit`
+
+// rollupSchema tabela única para todos os tiers (resolution_seconds identifica o tier)
+func rollupSchema() string {
+	var columns strings.Builder
+	for _, metric := range rollupMetrics {
+		fmt.Fprintf(&columns, "\t\t%[1]s_min REAL, %[1]s_avg REAL, %[1]s_max REAL, %[1]s_p95 REAL,\n", metric.name)
+	}
+
+	return `
+	CREATE TABLE IF NOT EXISTS hpa_snapshot_rollups (
+		resolution_seconds INTEGER NOT NULL,
+		cluster TEXT NOT NULL,
+		namespace TEXT NOT NULL,
+		hpa_name TEXT NOT NULL,
+		bucket_start DATETIME NOT NULL,
+		samples INTEGER NOT NULL,
+` + columns.String() + `
+		cpu_target INTEGER,
+		memory_target INTEGER,
+		min_replicas INTEGER,
+		max_replicas INTEGER,
+		cpu_request TEXT,
+		cpu_limit TEXT,
+		memory_request TEXT,
+		memory_limit TEXT,
+
+		PRIMARY KEY (resolution_seconds, cluster, namespace, hpa_name, bucket_start)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_rollups_cleanup
+		ON hpa_snapshot_rollups(resolution_seconds, bucket_start);
+	`
+}
+
+func rollupMetricColumns() []string {
+	columns := make([]string, 0, len(rollupMetrics)*4)
+	for _, metric := range rollupMetrics {
+		columns = append(columns, metric.name+"_min", metric.name+"_avg", metric.name+"_max", metric.name+"_p95")
+	}
+	return columns
+}
+
+// metricStats agregados de uma métrica no bucket
+type metricStats struct {
+	Min, Avg, Max, P95 float64
+}
+
+// rollupBucket agregados de um HPA em um bucket
+type rollupBucket struct {
+	Start   time.Time
+	Samples int
+	Metrics []metricStats      // Mesma ordem de rollupMetrics
+	Last    models.HPASnapshot // Último snapshot (configuração do HPA)
+}
+
+// aggregateSnapshots agrupa snapshots de um HPA (ordenados por timestamp) em buckets da resolução
+func aggregateSnapshots(snapshots []models.HPASnapshot, resolution time.Duration) []rollupBucket {
+	var buckets []rollupBucket
+	for start := 0; start < len(snapshots); {
+		bucketStart := snapshots[start].Timestamp.Truncate(resolution)
+		end := start
+		for end < len(snapshots) && snapshots[end].Timestamp.Truncate(resolution).Equal(bucketStart) {
+			end++
+		}
+		buckets = append(buckets, newRollupBucket(bucketStart, snapshots[start:end]))
+		start = end
+	}
+	return buckets
+}
+
+func newRollupBucket(start time.Time, snapshots []models.HPASnapshot) rollupBucket {
+	bucket := rollupBucket{
+		Start:   start,
+		Samples: len(snapshots),
+		Metrics: make([]metricStats, len(rollupMetrics)),
+		Last:    snapshots[len(snapshots)-1],
+	}
+
+	values := make([]float64, len(snapshots))
+	for i, metric := range rollupMetrics {
+		sum := 0.0
+		for j := range snapshots {
+			values[j] = metric.get(&snapshots[j])
+			sum += values[j]
+		}
+		sort.Float64s(values)
+		bucket.Metrics[i] = metricStats{
+			Min: values[0],
+			Avg: sum / float64(len(values)),
+			Max: values[len(values)-1],
+			P95: percentile(values, 95),
+		}
+	}
+	return bucket
+}
+
+// percentile nearest-rank de valores ordenados
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// snapshot converte o bucket em HPASnapshot: métricas principais com a média e
+// min/max/p95 em AdditionalMetrics (<métrica>_min, <métrica>_max, <métrica>_p95)
+func (b rollupBucket) snapshot(resolution time.Duration) models.HPASnapshot {
+	last := b.Last
+	snapshot := models.HPASnapshot{
+		Timestamp:     b.Start,
+		Cluster:       last.Cluster,
+		Namespace:     last.Namespace,
+		Name:          last.Name,
+		MinReplicas:   last.MinReplicas,
+		MaxReplicas:   last.MaxReplicas,
+		CPUTarget:     last.CPUTarget,
+		MemoryTarget:  last.MemoryTarget,
+		CPURequest:    last.CPURequest,
+		CPULimit:      last.CPULimit,
+		MemoryRequest: last.MemoryRequest,
+		MemoryLimit:   last.MemoryLimit,
+		AdditionalMetrics: map[string]interface{}{
+			"rollup_samples":            b.Samples,
+			"rollup_resolution_seconds": int(resolution.Seconds()),
+		},
+	}
+	for i, metric := range rollupMetrics {
+		stats := b.Metrics[i]
+		metric.set(&snapshot, stats.Avg)
+		snapshot.AdditionalMetrics[metric.name+"_min"] = stats.Min
+		snapshot.AdditionalMetrics[metric.name+"_max"] = stats.Max
+		snapshot.AdditionalMetrics[metric.name+"_p95"] = stats.P95
+	}
+	return snapshot
+}
+
+// tierFor escolhe o tier para consultas a partir de since (nil = snapshots brutos).
+// Usa o tier mais fino cuja retenção cobre o período; além disso, o mais grosso.
+func (p *Persistence) tierFor(since time.Time) *RollupTier {
+	tiers := p.config.RollupTiers
+	age := time.Since(since)
+	if len(tiers) == 0 || age <= p.config.MaxAge {
+		return nil
+	}
+	for i := range tiers {
+		if age <= tiers[i].Retention {
+			return &tiers[i]
+		}
+	}
+	return &tiers[len(tiers)-1]
+}
+
+// ResolutionFor retorna a resolução usada por LoadSnapshots/LoadAll para since ("raw" ou nome do tier)
+func (p *Persistence) ResolutionFor(since time.Time) string {
+	if tier := p.tierFor(since); tier != nil {
+		return tier.Name
+	}
+	return ResolutionRaw
+}
+
+const rawSnapshotColumns = `cluster, namespace, hpa_name, timestamp,
+	cpu_current, cpu_target, memory_current, memory_target,
+	current_replicas, desired_replicas, min_replicas, max_replicas,
+	cpu_request, cpu_limit, memory_request, memory_limit,
+	metrics_json`
+
+// queryRawSnapshots lê hpa_snapshots (ordenados por HPA e timestamp)
+func (p *Persistence) queryRawSnapshots(where string, args ...interface{}) ([]models.HPASnapshot, error) {
+	rows, err := p.db.Query(`SELECT `+rawSnapshotColumns+` FROM hpa_snapshots WHERE `+where+`
+		ORDER BY cluster, namespace, hpa_name, timestamp ASC`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := make([]models.HPASnapshot, 0)
+	for rows.Next() {
+		var snapshot models.HPASnapshot
+		var metricsJSON, cpuRequest, cpuLimit, memoryRequest, memoryLimit sql.NullString
+		var cpuCurrent, memoryCurrent sql.NullFloat64
+		var currentReplicas, desiredReplicas, minReplicas, maxReplicas, cpuTarget, memoryTarget sql.NullInt32
+
+		if err := rows.Scan(
+			&snapshot.Cluster, &snapshot.Namespace, &snapshot.Name, &snapshot.Timestamp,
+			&cpuCurrent, &cpuTarget, &memoryCurrent, &memoryTarget,
+			&currentReplicas, &desiredReplicas, &minReplicas, &maxReplicas,
+			&cpuRequest, &cpuLimit, &memoryRequest, &memoryLimit,
+			&metricsJSON,
+		); err != nil {
+			log.Warn().Err(err).Msg("Failed to scan snapshot")
+			continue
+		}
+
+		snapshot.CPUCurrent = cpuCurrent.Float64
+		snapshot.MemoryCurrent = memoryCurrent.Float64
+		snapshot.CPUTarget = cpuTarget.Int32
+		snapshot.MemoryTarget = memoryTarget.Int32
+		snapshot.CurrentReplicas = currentReplicas.Int32
+		snapshot.DesiredReplicas = desiredReplicas.Int32
+		snapshot.MinReplicas = minReplicas.Int32
+		snapshot.MaxReplicas = maxReplicas.Int32
+		snapshot.CPURequest = cpuRequest.String
+		snapshot.CPULimit = cpuLimit.String
+		snapshot.MemoryRequest = memoryRequest.String
+		snapshot.MemoryLimit = memoryLimit.String
+		hydrateSnapshotFromJSON(&snapshot, metricsJSON.String)
+
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, rows.Err()
+}
+
+// groupByHPA separa snapshots ordenados por HPA (chave cluster/namespace/hpa)
+func groupByHPA(snapshots []models.HPASnapshot) map[string][]models.HPASnapshot {
+	groups := make(map[string][]models.HPASnapshot)
+	for _, snapshot := range snapshots {
+		key := fmt.Sprintf("%s/%s/%s", snapshot.Cluster, snapshot.Namespace, snapshot.Name)
+		groups[key] = append(groups[key], snapshot)
+	}
+	return groups
+}
+
+// Compact atualiza os rollups dos buckets que receberam snapshots desde a última execução
+// (inclui baselines históricos gravados com timestamps antigos). Buckets são recalculados
+// a partir dos snapshots brutos, então a execução é idempotente.
+func (p *Persistence) Compact() error {
+	if !p.config.Enabled || p.db == nil || len(p.config.RollupTiers) == 0 {
+		return nil
+	}
+
+	runStart := time.Now().UTC()
+	var lastRun string
+	if err := p.db.QueryRow(`SELECT value FROM metadata WHERE key = 'rollup_last_run'`).Scan(&lastRun); err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to read compactor state: %w", err)
+	}
+
+	// created_at usa CURRENT_TIMESTAMP (UTC, precisão de segundos)
+	dirty, err := p.db.Query(`SELECT cluster, namespace, hpa_name, timestamp FROM hpa_snapshots WHERE created_at >= ?`, lastRun)
+	if err != nil {
+		return fmt.Errorf("failed to query new snapshots: %w", err)
+	}
+	type hpaRange struct {
+		cluster, namespace, name string
+		from, to                 time.Time
+	}
+	ranges := make(map[string]*hpaRange)
+	for dirty.Next() {
+		var cluster, namespace, name string
+		var timestamp time.Time
+		if err := dirty.Scan(&cluster, &namespace, &name, &timestamp); err != nil {
+			dirty.Close()
+			return fmt.Errorf("failed to scan new snapshot: %w", err)
+		}
+		key := cluster + "/" + namespace + "/" + name
+		r, ok := ranges[key]
+		if !ok {
+			ranges[key] = &hpaRange{cluster: cluster, namespace: namespace, name: name, from: timestamp, to: timestamp}
+			continue
+		}
+		if timestamp.Before(r.from) {
+			r.from = timestamp
+		}
+		if timestamp.After(r.to) {
+			r.to = timestamp
+		}
+	}
+	dirty.Close()
+
+	coarsest := p.config.RollupTiers[0].Resolution
+	for _, tier := range p.config.RollupTiers {
+		if tier.Resolution > coarsest {
+			coarsest = tier.Resolution
+		}
+	}
+
+	buckets := 0
+	for _, r := range ranges {
+		// Janela alinhada ao tier mais grosso cobre os buckets de todos os tiers
+		from := r.from.Truncate(coarsest)
+		to := r.to.Truncate(coarsest).Add(coarsest)
+		snapshots, err := p.queryRawSnapshots(`cluster = ? AND namespace = ? AND hpa_name = ? AND timestamp >= ? AND timestamp < ?`,
+			r.cluster, r.namespace, r.name, from, to)
+		if err != nil {
+			return err
+		}
+
+		count, err := p.saveRollups(snapshots, runStart)
+		if err != nil {
+			return err
+		}
+		buckets += count
+	}
+
+	if _, err := p.db.Exec(`INSERT OR REPLACE INTO metadata (key, value, updated_at) VALUES ('rollup_last_run', ?, CURRENT_TIMESTAMP)`,
+		runStart.Add(-time.Second).Format("2006-01-02 15:04:05")); err != nil {
+		return fmt.Errorf("failed to save compactor state: %w", err)
+	}
+
+	if buckets > 0 {
+		log.Debug().
+			Int("hpas", len(ranges)).
+			Int("buckets", buckets).
+			Dur("duration", time.Since(runStart)).
+			Msg("Rollups de snapshots atualizados")
+	}
+	return nil
+}
+
+// saveRollups grava os buckets de todos os tiers para os snapshots (de um HPA)
+func (p *Persistence) saveRollups(snapshots []models.HPASnapshot, now time.Time) (int, error) {
+	if len(snapshots) == 0 {
+		return 0, nil
+	}
+
+	columns := append([]string{"resolution_seconds", "cluster", "namespace", "hpa_name", "bucket_start", "samples"}, rollupMetricColumns()...)
+	columns = append(columns, "cpu_target", "memory_target", "min_replicas", "max_replicas",
+		"cpu_request", "cpu_limit", "memory_request", "memory_limit")
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+
+	tx, err := p.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT OR REPLACE INTO hpa_snapshot_rollups (` + strings.Join(columns, ", ") + `) VALUES (` + placeholders + `)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare rollup statement: %w", err)
+	}
+	defer stmt.Close()
+
+	count := 0
+	for _, tier := range p.config.RollupTiers {
+		for _, bucket := range aggregateSnapshots(snapshots, tier.Resolution) {
+			if bucket.Start.Before(now.Add(-tier.Retention)) {
+				continue
+			}
+
+			last := bucket.Last
+			values := []interface{}{int64(tier.Resolution.Seconds()), last.Cluster, last.Namespace, last.Name, bucket.Start, bucket.Samples}
+			for _, stats := range bucket.Metrics {
+				values = append(values, stats.Min, stats.Avg, stats.Max, stats.P95)
+			}
+			values = append(values, last.CPUTarget, last.MemoryTarget, last.MinReplicas, last.MaxReplicas,
+				last.CPURequest, last.CPULimit, last.MemoryRequest, last.MemoryLimit)
+
+			if _, err := stmt.Exec(values...); err != nil {
+				return 0, fmt.Errorf("failed to save rollup: %w", err)
+			}
+			count++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit rollups: %w", err)
+	}
+	return count, nil
+}
+
+// loadRollups lê os buckets do tier (where sobre cluster/namespace/hpa_name) desde since
+func (p *Persistence) loadRollups(tier *RollupTier, since time.Time, where string, args ...interface{}) (map[string][]models.HPASnapshot, error) {
+	query := `SELECT cluster, namespace, hpa_name, bucket_start, samples, ` + strings.Join(rollupMetricColumns(), ", ") + `,
+		` + rollupConfigColumns + `
+		FROM hpa_snapshot_rollups
+		WHERE resolution_seconds = ? AND bucket_start >= ?`
+	if where != "" {
+		query += ` AND ` + where
+	}
+	query += ` ORDER BY cluster, namespace, hpa_name, bucket_start ASC`
+
+	rows, err := p.db.Query(query, append([]interface{}{int64(tier.Resolution.Seconds()), since.Truncate(tier.Resolution)}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rollups: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string][]models.HPASnapshot)
+	for rows.Next() {
+		var bucket rollupBucket
+		last := &bucket.Last
+		stats := make([]sql.NullFloat64, len(rollupMetrics)*4)
+		var cpuTarget, memoryTarget, minReplicas, maxReplicas sql.NullInt32
+		var cpuRequest, cpuLimit, memoryRequest, memoryLimit sql.NullString
+
+		dest := []interface{}{&last.Cluster, &last.Namespace, &last.Name, &bucket.Start, &bucket.Samples}
+		for i := range stats {
+			dest = append(dest, &stats[i])
+		}
+		dest = append(dest, &cpuTarget, &memoryTarget, &minReplicas, &maxReplicas,
+			&cpuRequest, &cpuLimit, &memoryRequest, &memoryLimit)
+		if err := rows.Scan(dest...); err != nil {
+			log.Warn().Err(err).Msg("Failed to scan rollup")
+			continue
+		}
+
+		for i := range rollupMetrics {
+			bucket.Metrics = append(bucket.Metrics, metricStats{
+				Min: stats[i*4].Float64,
+				Avg: stats[i*4+1].Float64,
+				Max: stats[i*4+2].Float64,
+				P95: stats[i*4+3].Float64,
+			})
+		}
+		last.CPUTarget, last.MemoryTarget = cpuTarget.Int32, memoryTarget.Int32
+		last.MinReplicas, last.MaxReplicas = minReplicas.Int32, maxReplicas.Int32
+		last.CPURequest, last.CPULimit = cpuRequest.String, cpuLimit.String
+		last.MemoryRequest, last.MemoryLimit = memoryRequest.String, memoryLimit.String
+
+		key := fmt.Sprintf("%s/%s/%s", last.Cluster, last.Namespace, last.Name)
+		result[key] = append(result[key], bucket.snapshot(tier.Resolution))
+	}
+	return result, rows.Err()
+}
+
+// appendRawTail agrega os snapshots brutos posteriores ao último bucket compactado de cada HPA
+// (dados mais recentes que a última execução do compactor)
+func (p *Persistence) appendRawTail(tier *RollupTier, result map[string][]models.HPASnapshot, since time.Time, raw []models.HPASnapshot) {
+	for key, snapshots := range groupByHPA(raw) {
+		tailStart := since
+		if existing := result[key]; len(existing) > 0 {
+			tailStart = existing[len(existing)-1].Timestamp.Add(tier.Resolution)
+		}
+
+		first := sort.Search(len(snapshots), func(i int) bool { return !snapshots[i].Timestamp.Before(tailStart) })
+		for _, bucket := range aggregateSnapshots(snapshots[first:], tier.Resolution) {
+			result[key] = append(result[key], bucket.snapshot(tier.Resolution))
+		}
+	}
+}
+
+// loadRollupSnapshots consulta de um HPA roteada para o tier (LoadSnapshots)
+func (p *Persistence) loadRollupSnapshots(tier *RollupTier, cluster, namespace, name string, since time.Time) ([]models.HPASnapshot, error) {
+	result, err := p.loadRollups(tier, since, `cluster = ? AND namespace = ? AND hpa_name = ?`, cluster, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s/%s/%s", cluster, namespace, name)
+	tailStart := since
+	if existing := result[key]; len(existing) > 0 {
+		tailStart = existing[len(existing)-1].Timestamp.Add(tier.Resolution)
+	}
+	raw, err := p.queryRawSnapshots(`cluster = ? AND namespace = ? AND hpa_name = ? AND timestamp >= ?`,
+		cluster, namespace, name, tailStart)
+	if err != nil {
+		return nil, err
+	}
+	p.appendRawTail(tier, result, since, raw)
+
+	if result[key] == nil {
+		return []models.HPASnapshot{}, nil
+	}
+	return result[key], nil
+}
+
+// loadAllRollups consulta de todos os HPAs roteada para o tier (LoadAll)
+func (p *Persistence) loadAllRollups(tier *RollupTier, since time.Time) (map[string][]models.HPASnapshot, error) {
+	result, err := p.loadRollups(tier, since, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Cauda: a partir do bucket compactado mais antigo entre os mais recentes de cada HPA
+	tailStart := time.Now().Add(-tier.Resolution).Truncate(tier.Resolution)
+	for _, snapshots := range result {
+		if end := snapshots[len(snapshots)-1].Timestamp.Add(tier.Resolution); end.Before(tailStart) {
+			tailStart = end
+		}
+	}
+	if tailStart.Before(since) || len(result) == 0 {
+		tailStart = since
+	}
+
+	raw, err := p.queryRawSnapshots(`timestamp >= ?`, tailStart)
+	if err != nil {
+		return nil, err
+	}
+	p.appendRawTail(tier, result, since, raw)
+	return result, nil
+}
+
+// cleanupRollups aplica a retenção de cada tier e remove snapshots brutos além de MaxAge
+// (já compactados nos rollups)
+func (p *Persistence) cleanupRollups(rawCutoff time.Time) error {
+	if len(p.config.RollupTiers) == 0 {
+		return nil
+	}
+
+	if err := p.Compact(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, tier := range p.config.RollupTiers {
+		result, err := p.db.Exec(`DELETE FROM hpa_snapshot_rollups WHERE resolution_seconds = ? AND bucket_start < ?`,
+			int64(tier.Resolution.Seconds()), now.Add(-tier.Retention))
+		if err != nil {
+			return fmt.Errorf("failed to cleanup %s rollups: %w", tier.Name, err)
+		}
+		if rows, _ := result.RowsAffected(); rows > 0 {
+			log.Info().
+				Str("tier", tier.Name).
+				Int64("removed", rows).
+				Msg("Cleanup: removed old rollups")
+		}
+	}
+
+	result, err := p.db.Exec(`DELETE FROM hpa_snapshots WHERE timestamp < ?`, rawCutoff)
+	if err != nil {
+		return fmt.Errorf("failed to cleanup raw snapshots: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows > 0 {
+		log.Info().
+			Int64("removed", rows).
+			Time("cutoff", rawCutoff).
+			Msg("Cleanup: removed raw snapshots (kept in rollups)")
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s-hpa-manager/internal/monitoring/models"
+)
+
+func newRollupTestPersistence(t *testing.T) *Persistence {
+	t.Helper()
+	p, err := NewPersistence(&PersistenceConfig{
+		Enabled:     true,
+		DBPath:      filepath.Join(t.TempDir(), "rollup.db"),
+		MaxAge:      24 * time.Hour,
+		RollupTiers: DefaultRollupTiers(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create persistence: %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+// saveMinuteSnapshots grava um snapshot por minuto a partir de start (cpu = índice do minuto)
+func saveMinuteSnapshots(t *testing.T, p *Persistence, start time.Time, minutes int) {
+	t.Helper()
+	snapshots := make([]*models.HPASnapshot, 0, minutes)
+	for i := 0; i < minutes; i++ {
+		snapshots = append(snapshots, &models.HPASnapshot{
+			Timestamp:       start.Add(time.Duration(i) * time.Minute),
+			Cluster:         "aks-prd",
+			Namespace:       "api",
+			Name:            "web",
+			MinReplicas:     2,
+			MaxReplicas:     10,
+			CPUTarget:       70,
+			CPUCurrent:      float64(i % 60),
+			CurrentReplicas: 3,
+		})
+	}
+	if err := p.SaveSnapshots(snapshots); err != nil {
+		t.Fatalf("SaveSnapshots failed: %v", err)
+	}
+}
+
+func TestAggregateSnapshots(t *testing.T) {
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	snapshots := make([]models.HPASnapshot, 0, 25)
+	for i := 0; i < 20; i++ {
+		snapshots = append(snapshots, models.HPASnapshot{Timestamp: start.Add(time.Duration(i) * 10 * time.Second), CPUCurrent: float64(i + 1)})
+	}
+	// Segundo bucket com uma amostra
+	snapshots = append(snapshots, models.HPASnapshot{Timestamp: start.Add(7 * time.Minute), CPUCurrent: 50, MaxReplicas: 8})
+
+	buckets := aggregateSnapshots(snapshots, 5*time.Minute)
+	if len(buckets) != 2 {
+		t.Fatalf("expected 2 buckets, got %d", len(buckets))
+	}
+
+	cpu := buckets[0].Metrics[0]
+	if buckets[0].Samples != 20 || cpu.Min != 1 || cpu.Max != 20 || cpu.Avg != 10.5 || cpu.P95 != 19 {
+		t.Errorf("unexpected cpu aggregates: samples=%d %+v", buckets[0].Samples, cpu)
+	}
+
+	snapshot := buckets[1].snapshot(5 * time.Minute)
+	if !snapshot.Timestamp.Equal(start.Add(5*time.Minute)) || snapshot.CPUCurrent != 50 || snapshot.MaxReplicas != 8 {
+		t.Errorf("unexpected bucket snapshot: %+v", snapshot)
+	}
+	if snapshot.AdditionalMetrics["cpu_p95"] != 50.0 || snapshot.AdditionalMetrics["rollup_samples"] != 1 {
+		t.Errorf("unexpected aggregates: %v", snapshot.AdditionalMetrics)
+	}
+}
+
+func TestRollupRouting(t *testing.T) {
+	p := newRollupTestPersistence(t)
+	now := time.Now()
+
+	// 2h de dados há 3 dias (fora de MaxAge) + 30min recentes
+	old := now.Add(-72 * time.Hour).Truncate(time.Hour)
+	saveMinuteSnapshots(t, p, old, 120)
+	saveMinuteSnapshots(t, p, now.Add(-30*time.Minute), 30)
+
+	if err := p.Compact(); err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+
+	cases := []struct {
+		since      time.Time
+		resolution string
+	}{
+		{now.Add(-time.Hour), ResolutionRaw},
+		{now.Add(-7 * 24 * time.Hour), "5m"},
+		{now.Add(-30 * 24 * time.Hour), "1h"},
+		{now.Add(-365 * 24 * time.Hour), "1h"},
+	}
+	for _, tc := range cases {
+		if got := p.ResolutionFor(tc.since); got != tc.resolution {
+			t.Errorf("ResolutionFor(%s) = %s, want %s", now.Sub(tc.since), got, tc.resolution)
+		}
+	}
+
+	raw, err := p.LoadSnapshots("aks-prd", "api", "web", now.Add(-time.Hour))
+	if err != nil || len(raw) != 30 {
+		t.Fatalf("expected 30 raw snapshots, got %d (err=%v)", len(raw), err)
+	}
+
+	fiveMin, err := p.LoadSnapshots("aks-prd", "api", "web", now.Add(-7*24*time.Hour))
+	if err != nil {
+		t.Fatalf("LoadSnapshots failed: %v", err)
+	}
+	// 24 buckets antigos + 6 ou 7 buckets recentes (depende do alinhamento)
+	if len(fiveMin) < 30 || len(fiveMin) > 31 {
+		t.Fatalf("expected ~30 buckets of 5m, got %d", len(fiveMin))
+	}
+	first := fiveMin[0]
+	if !first.Timestamp.Equal(old) || first.CPUCurrent != 2 || first.AdditionalMetrics["cpu_max"] != 4.0 || first.MaxReplicas != 10 {
+		t.Errorf("unexpected first 5m bucket: %+v", first)
+	}
+
+	hourly, err := p.LoadAll(now.Add(-30 * 24 * time.Hour))
+	if err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	buckets := hourly["aks-prd/api/web"]
+	if len(buckets) < 3 || len(buckets) > 4 {
+		t.Fatalf("expected 3-4 hourly buckets, got %d", len(buckets))
+	}
+	if buckets[0].CPUCurrent != 29.5 || buckets[0].AdditionalMetrics["rollup_samples"] != 60 {
+		t.Errorf("unexpected first hourly bucket: %+v", buckets[0])
+	}
+}
+
+func TestRollupTailAndRetention(t *testing.T) {
+	p := newRollupTestPersistence(t)
+	now := time.Now()
+
+	// 20 dias atrás: além da retenção do tier 5m, dentro do tier 1h
+	ancient := now.Add(-20 * 24 * time.Hour).Truncate(time.Hour)
+	saveMinuteSnapshots(t, p, ancient, 60)
+	old := now.Add(-72 * time.Hour).Truncate(time.Hour)
+	saveMinuteSnapshots(t, p, old, 60)
+
+	if err := p.Compact(); err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+
+	// Snapshots gravados após a compactação vêm dos dados brutos (cauda)
+	saveMinuteSnapshots(t, p, now.Add(-10*time.Minute), 10)
+	fiveMin, err := p.LoadSnapshots("aks-prd", "api", "web", now.Add(-7*24*time.Hour))
+	if err != nil {
+		t.Fatalf("LoadSnapshots failed: %v", err)
+	}
+	if len(fiveMin) < 14 || len(fiveMin) > 15 {
+		t.Fatalf("expected 12 compacted + 2-3 tail buckets, got %d", len(fiveMin))
+	}
+
+	if err := p.Cleanup(); err != nil {
+		t.Fatalf("Cleanup failed: %v", err)
+	}
+
+	var rawCount, fiveMinCount int
+	p.db.QueryRow(`SELECT COUNT(*) FROM hpa_snapshots`).Scan(&rawCount)
+	p.db.QueryRow(`SELECT COUNT(*) FROM hpa_snapshot_rollups WHERE resolution_seconds = 300 AND bucket_start < ?`,
+		now.Add(-14*24*time.Hour)).Scan(&fiveMinCount)
+	if rawCount != 10 {
+		t.Errorf("expected only recent raw snapshots after cleanup, got %d", rawCount)
+	}
+	if fiveMinCount != 0 {
+		t.Errorf("expected no 5m rollups beyond retention, got %d", fiveMinCount)
+	}
+
+	// Histórico continua disponível pelos rollups após remover os dados brutos
+	hourly, err := p.LoadSnapshots("aks-prd", "api", "web", now.Add(-30*24*time.Hour))
+	if err != nil {
+		t.Fatalf("LoadSnapshots failed: %v", err)
+	}
+	if len(hourly) < 3 || !hourly[0].Timestamp.Equal(ancient) {
+		t.Fatalf("expected hourly history starting at %s, got %d buckets", ancient, len(hourly))
+	}
+	if hourly[0].AdditionalMetrics["rollup_samples"] != 60 {
+		t.Errorf("unexpected ancient bucket: %v", hourly[0].AdditionalMetrics)
+	}
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"k8s-hpa-manager/internal/monitoring/models"
+)
+
+// ErrStressTestNotFound teste inexistente em stress_test_results
+var ErrStressTestNotFound = errors.New("stress test not found")
+
+// LoadStressTestResult carrega o resultado completo salvo por SaveStressTestResult
+func (p *Persistence) LoadStressTestResult(testID string) (*models.StressTestMetrics, error) {
+	if !p.config.Enabled || p.db == nil {
+		return nil, fmt.Errorf("persistence not enabled")
+	}
+
+	var data string
+	err := p.db.QueryRow(`SELECT result_data FROM stress_test_results WHERE test_id = ?`, testID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrStressTestNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load stress test result: %w", err)
+	}
+
+	var metrics models.StressTestMetrics
+	if err := json.Unmarshal([]byte(data), &metrics); err != nil {
+		return nil, fmt.Errorf("failed to decode stress test result: %w", err)
+	}
+	return &metrics, nil
+}
+
+// LoadStressTestSnapshots carrega os snapshots coletados durante o teste, ordenados por timestamp
+func (p *Persistence) LoadStressTestSnapshots(testID string) ([]models.HPASnapshot, error) {
+	if !p.config.Enabled || p.db == nil {
+		return nil, fmt.Errorf("persistence not enabled")
+	}
+
+	rows, err := p.db.Query(`
+		SELECT snapshot_data FROM stress_test_snapshots
+		WHERE test_id = ?
+		ORDER BY timestamp ASC, id ASC
+	`, testID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stress test snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []models.HPASnapshot
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var snapshot models.HPASnapshot
+		if err := json.Unmarshal([]byte(data), &snapshot); err != nil {
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, rows.Err()
+}
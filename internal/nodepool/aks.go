@@ -0,0 +1,271 @@
+package nodepool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v2"
+
+	"k8s-hpa-manager/internal/azure"
+	"k8s-hpa-manager/internal/models"
+)
+
+// defaultPollFrequency intervalo entre consultas de operações de longa duração
+const defaultPollFrequency = 10 * time.Second
+
+// AKSOptions opções de criação do AKSProvider
+type AKSOptions struct {
+	// ClientOptions permite trocar cloud, transport e retry (ex: apontar para um servidor de teste)
+	ClientOptions *arm.ClientOptions
+	// PollFrequency intervalo entre consultas de operações assíncronas (padrão 10s)
+	PollFrequency time.Duration
+}
+
+// AKSProvider implementa NodePoolProvider com o AgentPoolsClient do armcontainerservice
+type AKSProvider struct {
+	credential    func() (azcore.TokenCredential, error)
+	clientOptions *arm.ClientOptions
+	pollFrequency time.Duration
+
+	mu      sync.Mutex
+	clients map[string]*armcontainerservice.AgentPoolsClient // por subscription
+}
+
+// NewAKSProvider cria um provider AKS que obtém a credencial do AuthManager
+func NewAKSProvider(auth *azure.AuthManager, opts *AKSOptions) *AKSProvider {
+	return newAKSProvider(auth.EnsureCredential, opts)
+}
+
+// NewAKSProviderWithCredential cria um provider AKS com uma credencial já construída
+func NewAKSProviderWithCredential(cred azcore.TokenCredential, opts *AKSOptions) *AKSProvider {
+	return newAKSProvider(func() (azcore.TokenCredential, error) { return cred, nil }, opts)
+}
+
+func newAKSProvider(credential func() (azcore.TokenCredential, error), opts *AKSOptions) *AKSProvider {
+	if opts == nil {
+		opts = &AKSOptions{}
+	}
+
+	pollFrequency := opts.PollFrequency
+	if pollFrequency <= 0 {
+		pollFrequency = defaultPollFrequency
+	}
+
+	return &AKSProvider{
+		credential:    credential,
+		clientOptions: opts.ClientOptions,
+		pollFrequency: pollFrequency,
+		clients:       make(map[string]*armcontainerservice.AgentPoolsClient),
+	}
+}
+
+// List retorna todos os node pools do cluster AKS
+func (p *AKSProvider) List(ctx context.Context, cluster ClusterRef) ([]models.NodePool, error) {
+	client, err := p.getClient(cluster)
+	if err != nil {
+		return nil, p.wrapError("list", cluster, "", err)
+	}
+
+	var nodePools []models.NodePool
+	pager := client.NewListPager(cluster.ResourceGroup, cluster.Name, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, p.wrapError("list", cluster, "", err)
+		}
+		for _, pool := range page.Value {
+			if pool != nil {
+				nodePools = append(nodePools, agentPoolToModel(pool, cluster))
+			}
+		}
+	}
+
+	return nodePools, nil
+}
+
+// Get retorna um node pool específico do cluster AKS
+func (p *AKSProvider) Get(ctx context.Context, cluster ClusterRef, name string) (*models.NodePool, error) {
+	client, err := p.getClient(cluster)
+	if err != nil {
+		return nil, p.wrapError("get", cluster, name, err)
+	}
+
+	resp, err := client.Get(ctx, cluster.ResourceGroup, cluster.Name, name, nil)
+	if err != nil {
+		return nil, p.wrapError("get", cluster, name, err)
+	}
+
+	nodePool := agentPoolToModel(&resp.AgentPool, cluster)
+	return &nodePool, nil
+}
+
+// Scale altera o número de nodes de um pool (equivalente a az aks nodepool scale)
+func (p *AKSProvider) Scale(ctx context.Context, cluster ClusterRef, name string, count int32) error {
+	if count < 0 {
+		return p.wrapError("scale", cluster, name, fmt.Errorf("invalid node count: %d", count))
+	}
+
+	return p.update(ctx, "scale", cluster, name, func(props *armcontainerservice.ManagedClusterAgentPoolProfileProperties) {
+		props.Count = &count
+	})
+}
+
+// UpdateAutoscaler habilita, desabilita ou ajusta min/max do cluster autoscaler
+// (equivalente a az aks nodepool update --enable/--disable/--update-cluster-autoscaler)
+func (p *AKSProvider) UpdateAutoscaler(ctx context.Context, cluster ClusterRef, name string, settings AutoscalerSettings) error {
+	if settings.Enabled && (settings.MinCount < 0 || settings.MaxCount < settings.MinCount) {
+		return p.wrapError("update_autoscaler", cluster, name,
+			fmt.Errorf("invalid autoscaler limits: min=%d max=%d", settings.MinCount, settings.MaxCount))
+	}
+
+	return p.update(ctx, "update_autoscaler", cluster, name, func(props *armcontainerservice.ManagedClusterAgentPoolProfileProperties) {
+		props.EnableAutoScaling = &settings.Enabled
+		if settings.Enabled {
+			props.MinCount = &settings.MinCount
+			props.MaxCount = &settings.MaxCount
+		} else {
+			props.MinCount = nil
+			props.MaxCount = nil
+		}
+	})
+}
+
+// update lê o agentPool atual, aplica a mutação e envia o PUT (BeginCreateOrUpdate)
+// aguardando a operação de longa duração terminar
+func (p *AKSProvider) update(ctx context.Context, op string, cluster ClusterRef, name string, mutate func(props *armcontainerservice.ManagedClusterAgentPoolProfileProperties)) error {
+	client, err := p.getClient(cluster)
+	if err != nil {
+		return p.wrapError(op, cluster, name, err)
+	}
+
+	// 1. Ler estado atual (PUT do ARM substitui o recurso inteiro)
+	current, err := client.Get(ctx, cluster.ResourceGroup, cluster.Name, name, nil)
+	if err != nil {
+		return p.wrapError(op, cluster, name, err)
+	}
+	props := current.Properties
+	if props == nil {
+		props = &armcontainerservice.ManagedClusterAgentPoolProfileProperties{}
+	}
+
+	// Propriedades somente leitura são serializadas pelo modelo do SDK: não enviar no PUT
+	props.ProvisioningState = nil
+	props.NodeImageVersion = nil
+	props.CurrentOrchestratorVersion = nil
+	mutate(props)
+
+	// 2. Enviar PUT e aguardar a operação (Azure-AsyncOperation / provisioningState)
+	poller, err := client.BeginCreateOrUpdate(ctx, cluster.ResourceGroup, cluster.Name, name,
+		armcontainerservice.AgentPool{Properties: props}, nil)
+	if err != nil {
+		return p.wrapError(op, cluster, name, err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: p.pollFrequency}); err != nil {
+		return p.wrapError(op, cluster, name, err)
+	}
+
+	return nil
+}
+
+// getClient retorna o AgentPoolsClient da subscription do cluster (a credencial só é
+// resolvida no primeiro uso)
+func (p *AKSProvider) getClient(cluster ClusterRef) (*armcontainerservice.AgentPoolsClient, error) {
+	if err := validateClusterRef(cluster); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if client, ok := p.clients[cluster.Subscription]; ok {
+		return client, nil
+	}
+
+	cred, err := p.credential()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get azure credential: %w", err)
+	}
+
+	client, err := armcontainerservice.NewAgentPoolsClient(cluster.Subscription, cred, p.clientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create agent pools client: %w", err)
+	}
+
+	p.clients[cluster.Subscription] = client
+	return client, nil
+}
+
+// wrapError converte erros do ARM em *Error estruturado
+func (p *AKSProvider) wrapError(op string, cluster ClusterRef, name string, err error) error {
+	npErr := &Error{
+		Op:       op,
+		Cluster:  cluster.Name,
+		NodePool: name,
+		Err:      err,
+	}
+
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) {
+		npErr.StatusCode = respErr.StatusCode
+		npErr.Code = respErr.ErrorCode
+	}
+
+	return npErr
+}
+
+// agentPoolToModel converte um agentPool do SDK para o modelo da aplicação
+func agentPoolToModel(pool *armcontainerservice.AgentPool, cluster ClusterRef) models.NodePool {
+	props := pool.Properties
+	if props == nil {
+		props = &armcontainerservice.ManagedClusterAgentPoolProfileProperties{}
+	}
+
+	nodePool := models.NodePool{
+		Name:               stringValue(pool.Name),
+		VMSize:             stringValue(props.VMSize),
+		NodeCount:          int32Value(props.Count),
+		MinNodeCount:       int32Value(props.MinCount),
+		MaxNodeCount:       int32Value(props.MaxCount),
+		AutoscalingEnabled: props.EnableAutoScaling != nil && *props.EnableAutoScaling,
+		Status:             stringValue(props.ProvisioningState),
+		IsSystemPool:       props.Mode != nil && *props.Mode == armcontainerservice.AgentPoolModeSystem,
+		Provider:           ProviderAKS,
+		ClusterName:        cluster.Name,
+		ResourceGroup:      cluster.ResourceGroup,
+		Subscription:       cluster.Subscription,
+	}
+
+	// Definir valores originais
+	nodePool.OriginalValues = ValuesOf(&nodePool)
+
+	return nodePool
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func int32Value(value *int32) int32 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+// validateClusterRef garante que o cluster tem os campos exigidos pela API ARM
+func validateClusterRef(cluster ClusterRef) error {
+	if cluster.Subscription == "" || cluster.ResourceGroup == "" || cluster.Name == "" {
+		return fmt.Errorf("subscription, resource group and cluster name are required")
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package nodepool
+
+import (
+	"context"
+	"fmt"
+
+	"k8s-hpa-manager/internal/models"
+)
+
+// StepAction tipo de operação executada em um passo de aplicação
+type StepAction string
+
+const (
+	StepScale             StepAction = "scale"
+	StepDisableAutoscaler StepAction = "disable_autoscaler"
+	StepEnableAutoscaler  StepAction = "enable_autoscaler"
+	StepUpdateAutoscaler  StepAction = "update_autoscaler"
+)
+
+// Step representa uma operação individual necessária para levar o node pool ao estado desejado
+type Step struct {
+	Action     StepAction         `json:"action"`
+	NodeCount  int32              `json:"node_count,omitempty"`
+	Autoscaler AutoscalerSettings `json:"autoscaler,omitempty"`
+}
+
+// Description retorna uma descrição legível do passo
+func (s Step) Description() string {
+	switch s.Action {
+	case StepScale:
+		return fmt.Sprintf("scale para %d nodes", s.NodeCount)
+	case StepDisableAutoscaler:
+		return "desabilitar cluster autoscaler"
+	case StepEnableAutoscaler:
+		return fmt.Sprintf("habilitar cluster autoscaler (min=%d, max=%d)", s.Autoscaler.MinCount, s.Autoscaler.MaxCount)
+	case StepUpdateAutoscaler:
+		return fmt.Sprintf("atualizar cluster autoscaler (min=%d, max=%d)", s.Autoscaler.MinCount, s.Autoscaler.MaxCount)
+	}
+	return string(s.Action)
+}
+
+// ProgressFunc recebe notificações de progresso durante a execução dos passos
+type ProgressFunc func(step, total int, description string)
+
+// Plan calcula os passos necessários para ir de original até target.
+// IMPORTANTE: Ordem correta para evitar conflitos no provedor:
+// 1. Se mudou de auto→manual: PRIMEIRO desabilita autoscaling, DEPOIS faz scale
+// 2. Se mudou de manual→auto: PRIMEIRO faz scale (se necessário), DEPOIS habilita autoscaling
+// 3. Se permaneceu auto: Atualiza min/max
+// 4. Se permaneceu manual: Faz scale
+func Plan(original, target models.NodePoolValues) []Step {
+	var steps []Step
+
+	autoscaler := AutoscalerSettings{
+		Enabled:  true,
+		MinCount: target.MinNodeCount,
+		MaxCount: target.MaxNodeCount,
+	}
+
+	switch {
+	case original.AutoscalingEnabled && !target.AutoscalingEnabled:
+		steps = append(steps, Step{Action: StepDisableAutoscaler})
+		if target.NodeCount != original.NodeCount {
+			steps = append(steps, Step{Action: StepScale, NodeCount: target.NodeCount})
+		}
+
+	case !original.AutoscalingEnabled && target.AutoscalingEnabled:
+		if target.NodeCount != original.NodeCount {
+			steps = append(steps, Step{Action: StepScale, NodeCount: target.NodeCount})
+		}
+		steps = append(steps, Step{Action: StepEnableAutoscaler, Autoscaler: autoscaler})
+
+	case original.AutoscalingEnabled && target.AutoscalingEnabled:
+		if target.MinNodeCount != original.MinNodeCount || target.MaxNodeCount != original.MaxNodeCount {
+			steps = append(steps, Step{Action: StepUpdateAutoscaler, Autoscaler: autoscaler})
+		}
+
+	default:
+		if target.NodeCount != original.NodeCount {
+			steps = append(steps, Step{Action: StepScale, NodeCount: target.NodeCount})
+		}
+	}
+
+	return steps
+}
+
+// Execute executa os passos em ordem, interrompendo no primeiro erro
+func Execute(ctx context.Context, provider NodePoolProvider, cluster ClusterRef, name string, steps []Step, progress ProgressFunc) error {
+	for i, step := range steps {
+		if progress != nil {
+			progress(i+1, len(steps), step.Description())
+		}
+
+		var err error
+		switch step.Action {
+		case StepScale:
+			err = provider.Scale(ctx, cluster, name, step.NodeCount)
+		case StepDisableAutoscaler:
+			err = provider.UpdateAutoscaler(ctx, cluster, name, AutoscalerSettings{Enabled: false})
+		case StepEnableAutoscaler, StepUpdateAutoscaler:
+			err = provider.UpdateAutoscaler(ctx, cluster, name, step.Autoscaler)
+		default:
+			err = fmt.Errorf("unknown step action: %s", step.Action)
+		}
+
+		if err != nil {
+			return fmt.Errorf("step %d/%d (%s) failed: %w", i+1, len(steps), step.Description(), err)
+		}
+	}
+
+	return nil
+}
+
+// Apply calcula e executa os passos para levar o node pool de original até target
+func Apply(ctx context.Context, provider NodePoolProvider, cluster ClusterRef, name string, original, target models.NodePoolValues, progress ProgressFunc) error {
+	return Execute(ctx, provider, cluster, name, Plan(original, target), progress)
+}
+
+// ApplyFromLive lê o estado atual do node pool no provedor e aplica target a partir dele
+func ApplyFromLive(ctx context.Context, provider NodePoolProvider, cluster ClusterRef, name string, target models.NodePoolValues, progress ProgressFunc) error {
+	current, err := provider.Get(ctx, cluster, name)
+	if err != nil {
+		return err
+	}
+
+	return Apply(ctx, provider, cluster, name, ValuesOf(current), target, progress)
+}
+
+// ValuesOf extrai os valores configuráveis de um node pool
+func ValuesOf(pool *models.NodePool) models.NodePoolValues {
+	return models.NodePoolValues{
+		NodeCount:          pool.NodeCount,
+		MinNodeCount:       pool.MinNodeCount,
+		MaxNodeCount:       pool.MaxNodeCount,
+		AutoscalingEnabled: pool.AutoscalingEnabled,
+	}
+}
@@ -0,0 +1,162 @@
+package nodepool
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// VMCapacity capacidade de um node (vCPUs e memória em GiB)
+type VMCapacity struct {
+	VCPU      float64 `json:"vcpu"`
+	MemoryGiB float64 `json:"memory_gib"`
+}
+
+var (
+	// Standard_D8s_v3, Standard_E8-4ds_v5 (vCPU restrito), Standard_B2ms
+	azureSizePattern = regexp.MustCompile(`^(?:standard_|basic_)?([a-z]+?)(\d+)(?:-(\d+))?([a-z]*)(?:_v\d+)?$`)
+	// m5.xlarge, c6i.2xlarge, t3.medium
+	awsSizePattern = regexp.MustCompile(`^([a-z]+)\d+[a-z\-]*\.(\d*)(micro|small|medium|large|xlarge)$`)
+	// e2-standard-4, n2-highmem-8, n2-custom-8-32768, e2-medium
+	gcpSizePattern = regexp.MustCompile(`^([a-z0-9]+)-(standard|highmem|highcpu|custom|medium|small|micro)(?:-(\d+))?(?:-(\d+))?$`)
+)
+
+// Memória por vCPU (GiB) das famílias Azure
+var azureMemoryPerVCPU = map[string]float64{
+	"a": 2, "b": 4, "d": 4, "dc": 4, "e": 8, "ec": 8, "f": 2, "fx": 21, "h": 7, "hb": 4,
+	"l": 8, "m": 14, "nc": 7, "nd": 7, "nv": 7,
+}
+
+// Memória por vCPU (GiB) das famílias AWS
+var awsMemoryPerVCPU = map[string]float64{
+	"a": 2, "c": 2, "g": 4, "i": 8, "inf": 2, "m": 4, "p": 8, "r": 8, "x": 16, "z": 8,
+}
+
+// Instâncias AWS burstable (t2/t3/t4g): vCPU e memória por tamanho
+var awsBurstable = map[string]VMCapacity{
+	"micro": {2, 1}, "small": {2, 2}, "medium": {2, 4}, "large": {2, 8}, "xlarge": {4, 16}, "2xlarge": {8, 32},
+}
+
+// ParseVMCapacity deduz vCPUs e memória do tamanho de VM (models.NodePool.VMSize) de AKS, EKS ou GKE.
+// EKS com vários instance types ("m5.xlarge,m5a.xlarge") usa o primeiro.
+func ParseVMCapacity(vmSize string) (VMCapacity, error) {
+	size := strings.ToLower(strings.TrimSpace(strings.Split(vmSize, ",")[0]))
+
+	if m := awsSizePattern.FindStringSubmatch(size); m != nil {
+		return parseAWSSize(m)
+	}
+	if m := gcpSizePattern.FindStringSubmatch(size); m != nil {
+		if capacity, ok := parseGCPSize(m); ok {
+			return capacity, nil
+		}
+	}
+	if m := azureSizePattern.FindStringSubmatch(size); m != nil {
+		vcpu, _ := strconv.Atoi(m[2])
+		perVCPU, ok := azureMemoryPerVCPU[m[1]]
+		if !ok {
+			perVCPU, ok = azureMemoryPerVCPU[m[1][:1]]
+		}
+		if ok && vcpu > 0 {
+			capacity := VMCapacity{VCPU: float64(vcpu), MemoryGiB: float64(vcpu) * perVCPU}
+			if m[3] != "" {
+				// vCPU restrito: memória do tamanho base, menos vCPUs ativos
+				constrained, _ := strconv.Atoi(m[3])
+				capacity.VCPU = float64(constrained)
+			}
+			return capacity, nil
+		}
+	}
+
+	return VMCapacity{}, fmt.Errorf("unknown VM size %q", vmSize)
+}
+
+func parseAWSSize(m []string) (VMCapacity, error) {
+	family, multiplier, size := m[1], m[2], m[3]
+	if family == "t" {
+		if capacity, ok := awsBurstable[multiplier+size]; ok {
+			return capacity, nil
+		}
+	}
+
+	var vcpu float64
+	switch size {
+	case "medium":
+		vcpu = 1
+	case "large":
+		vcpu = 2
+	case "xlarge":
+		vcpu = 4
+		if multiplier != "" {
+			n, _ := strconv.Atoi(multiplier)
+			vcpu = float64(4 * n)
+		}
+	}
+	perVCPU, ok := awsMemoryPerVCPU[family]
+	if !ok {
+		perVCPU, ok = awsMemoryPerVCPU[family[:1]]
+	}
+	if !ok || vcpu == 0 {
+		return VMCapacity{}, fmt.Errorf("unknown instance type %s.%s%s", family, multiplier, size)
+	}
+	return VMCapacity{VCPU: vcpu, MemoryGiB: vcpu * perVCPU}, nil
+}
+
+func parseGCPSize(m []string) (VMCapacity, bool) {
+	series, kind := m[1], m[2]
+	vcpu, _ := strconv.Atoi(m[3])
+
+	switch kind {
+	case "micro":
+		return VMCapacity{2, 1}, true
+	case "small":
+		return VMCapacity{2, 2}, true
+	case "medium":
+		return VMCapacity{2, 4}, true
+	case "custom":
+		memoryMB, _ := strconv.Atoi(m[4])
+		return VMCapacity{VCPU: float64(vcpu), MemoryGiB: float64(memoryMB) / 1024}, vcpu > 0 && memoryMB > 0
+	}
+	if vcpu == 0 {
+		return VMCapacity{}, false
+	}
+
+	perVCPU := map[string]float64{"standard": 4, "highmem": 8, "highcpu": 1}[kind]
+	if series == "n1" {
+		perVCPU = map[string]float64{"standard": 3.75, "highmem": 6.5, "highcpu": 0.9}[kind]
+	}
+	return VMCapacity{VCPU: float64(vcpu), MemoryGiB: float64(vcpu) * perVCPU}, true
+}
+
+// Allocatable estima a capacidade alocável para pods descontando as reservas do kubelet
+// (mesma tabela de kube-reserved de AKS e GKE, mais 100Mi de eviction threshold)
+func (c VMCapacity) Allocatable() VMCapacity {
+	cpuReserved := tiered(c.VCPU, []tier{{1, 0.06}, {1, 0.01}, {2, 0.005}, {0, 0.0025}})
+	memoryReserved := tiered(c.MemoryGiB, []tier{{4, 0.25}, {4, 0.2}, {8, 0.1}, {112, 0.06}, {0, 0.02}}) + 100.0/1024
+
+	return VMCapacity{
+		VCPU:      c.VCPU - cpuReserved,
+		MemoryGiB: c.MemoryGiB - memoryReserved,
+	}
+}
+
+// tier faixa da tabela de reservas (size 0 = restante)
+type tier struct {
+	size, rate float64
+}
+
+func tiered(total float64, tiers []tier) float64 {
+	reserved, remaining := 0.0, total
+	for _, t := range tiers {
+		if remaining <= 0 {
+			break
+		}
+		amount := remaining
+		if t.size > 0 && amount > t.size {
+			amount = t.size
+		}
+		reserved += amount * t.rate
+		remaining -= amount
+	}
+	return reserved
+}
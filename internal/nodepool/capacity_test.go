@@ -0,0 +1,58 @@
+package nodepool
+
+import (
+	"math"
+	"testing"
+)
+
+// TestParseVMCapacity valida os tamanhos de VM de AKS, EKS e GKE
+func TestParseVMCapacity(t *testing.T) {
+	tests := []struct {
+		vmSize string
+		want   VMCapacity
+	}{
+		{"Standard_D8s_v3", VMCapacity{8, 32}},
+		{"Standard_E8-4ds_v5", VMCapacity{4, 64}},
+		{"Standard_F16s_v2", VMCapacity{16, 32}},
+		{"m5.xlarge", VMCapacity{4, 16}},
+		{"c6i.2xlarge", VMCapacity{8, 16}},
+		{"r5.large,r5a.large", VMCapacity{2, 16}},
+		{"t3.medium", VMCapacity{2, 4}},
+		{"e2-standard-4", VMCapacity{4, 16}},
+		{"n1-standard-2", VMCapacity{2, 7.5}},
+		{"n2-custom-8-32768", VMCapacity{8, 32}},
+		{"e2-medium", VMCapacity{2, 4}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseVMCapacity(tt.vmSize)
+		if err != nil {
+			t.Errorf("ParseVMCapacity(%q) error: %v", tt.vmSize, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseVMCapacity(%q) = %+v, want %+v", tt.vmSize, got, tt.want)
+		}
+	}
+
+	for _, vmSize := range []string{"", "bogus", "Standard_Q4_v3"} {
+		if _, err := ParseVMCapacity(vmSize); err == nil {
+			t.Errorf("ParseVMCapacity(%q) expected error", vmSize)
+		}
+	}
+}
+
+// TestAllocatable valida as reservas do kubelet em um node de 8 vCPUs / 32GiB
+func TestAllocatable(t *testing.T) {
+	got := VMCapacity{VCPU: 8, MemoryGiB: 32}.Allocatable()
+
+	// CPU: 60m + 10m + 2×5m + 4×2.5m = 90m
+	if math.Abs(got.VCPU-7.91) > 1e-9 {
+		t.Errorf("allocatable vCPU = %v, want 7.91", got.VCPU)
+	}
+	// Memória: 1 + 0.8 + 0.8 + 16×0.06 = 3.56GiB + 100Mi
+	want := 32 - 3.56 - 100.0/1024
+	if math.Abs(got.MemoryGiB-want) > 1e-9 {
+		t.Errorf("allocatable memory = %v, want %v", got.MemoryGiB, want)
+	}
+}
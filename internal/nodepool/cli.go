@@ -0,0 +1,99 @@
+package nodepool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+)
+
+// commandRunner executa um comando de CLI e retorna o stdout (substituível em testes)
+type commandRunner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+// cliError erro de execução de CLI com stderr capturado
+type cliError struct {
+	command string
+	stderr  string
+	err     error
+}
+
+func (e *cliError) Error() string {
+	if e.stderr != "" {
+		return fmt.Sprintf("%s failed: %s", e.command, e.stderr)
+	}
+	return fmt.Sprintf("%s failed: %v", e.command, e.err)
+}
+
+func (e *cliError) Unwrap() error {
+	return e.err
+}
+
+// execRunner executa o comando real via os/exec
+func execRunner(ctx context.Context, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, &cliError{
+			command: name + " " + strings.Join(args, " "),
+			stderr:  strings.TrimSpace(stderr.String()),
+			err:     err,
+		}
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// Trechos de stderr usados para classificar erros das CLIs aws/gcloud
+var (
+	cliAuthErrors = []string{
+		"ExpiredToken", "Unable to locate credentials", "AccessDenied", "UnrecognizedClientException",
+		"could not be refreshed", "Reauthentication", "gcloud auth login", "PERMISSION_DENIED", "UNAUTHENTICATED",
+	}
+	cliNotFoundErrors = []string{"ResourceNotFoundException", "NOT_FOUND", "was not found"}
+	cliConflictErrors = []string{"ResourceInUseException", "FAILED_PRECONDITION", "already in progress"}
+)
+
+// wrapCLIError converte erros de CLI em *Error estruturado, inferindo o status pelo stderr
+func wrapCLIError(op string, cluster ClusterRef, name string, err error) error {
+	npErr := &Error{
+		Op:       op,
+		Cluster:  cluster.Name,
+		NodePool: name,
+		Err:      err,
+	}
+
+	message := err.Error()
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		// CLI ausente do PATH: problema de instalação do servidor, não um node pool inexistente
+		npErr.Code = CodeConfiguration
+	case containsAny(message, cliAuthErrors):
+		npErr.StatusCode = http.StatusUnauthorized
+		npErr.Code = "AuthenticationFailed"
+	case containsAny(message, cliNotFoundErrors):
+		npErr.StatusCode = http.StatusNotFound
+		npErr.Code = "NotFound"
+	case containsAny(message, cliConflictErrors):
+		npErr.StatusCode = http.StatusConflict
+		npErr.Code = "Conflict"
+	}
+
+	return npErr
+}
+
+// containsAny verifica se s contém algum dos trechos
+func containsAny(s string, substrings []string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,194 @@
+package nodepool
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s-hpa-manager/internal/models"
+)
+
+// scriptedRunner simula a CLI respondendo por prefixo de comando
+type scriptedRunner struct {
+	responses map[string]string // "subcomando" -> stdout
+	errs      map[string]error
+	calls     []string
+}
+
+func (r *scriptedRunner) run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	command := name + " " + strings.Join(args, " ")
+	r.calls = append(r.calls, command)
+
+	for prefix, err := range r.errs {
+		if strings.HasPrefix(command, prefix) {
+			return nil, err
+		}
+	}
+	for prefix, output := range r.responses {
+		if strings.HasPrefix(command, prefix) {
+			return []byte(output), nil
+		}
+	}
+	return nil, errors.New("unexpected command: " + command)
+}
+
+// TestRegistryDispatch valida o roteamento pelo campo provider
+func TestRegistryDispatch(t *testing.T) {
+	aks := NewFakeProvider()
+	eks := NewFakeProvider()
+
+	aksCluster := ClusterRef{Name: "aks-prod", ResourceGroup: "rg", Subscription: "sub"}
+	eksCluster := ClusterRef{Provider: "EKS", Name: "eks-prod", Region: "us-east-1"}
+	aks.AddNodePool(aksCluster, models.NodePool{Name: "system"})
+	eks.AddNodePool(eksCluster, models.NodePool{Name: "ng-1"})
+
+	registry := NewRegistry()
+	registry.Register(ProviderAKS, aks)
+	registry.Register(ProviderEKS, eks)
+
+	// Provider vazio = AKS (compatibilidade com clusters-config.json antigo)
+	pools, err := registry.List(context.Background(), aksCluster)
+	if err != nil || len(pools) != 1 || pools[0].Name != "system" {
+		t.Fatalf("List(aks) = %+v, %v", pools, err)
+	}
+
+	pools, err = registry.List(context.Background(), eksCluster)
+	if err != nil || len(pools) != 1 || pools[0].Name != "ng-1" {
+		t.Fatalf("List(eks) = %+v, %v", pools, err)
+	}
+
+	if _, err := registry.List(context.Background(), ClusterRef{Provider: "gke", Name: "gke-prod"}); err == nil {
+		t.Error("esperado erro para provedor não registrado")
+	}
+
+	ref := ClusterRefFromConfig(&models.ClusterConfig{ClusterName: "eks-prod-admin", Provider: "eks", Region: "us-east-1"})
+	if ref.Provider != ProviderEKS || ref.Name != "eks-prod" || ref.Region != "us-east-1" {
+		t.Errorf("ClusterRefFromConfig() = %+v", ref)
+	}
+}
+
+// TestEKSProviderScaleAndAutoscaler valida o mapeamento de scalingConfig e o polling de updates
+func TestEKSProviderScaleAndAutoscaler(t *testing.T) {
+	runner := &scriptedRunner{
+		responses: map[string]string{
+			"aws eks list-nodegroups":         `{"nodegroups":["ng-1"]}`,
+			"aws eks describe-nodegroup":      `{"nodegroup":{"nodegroupName":"ng-1","status":"ACTIVE","instanceTypes":["m5.large"],"scalingConfig":{"minSize":2,"maxSize":6,"desiredSize":3}}}`,
+			"aws eks update-nodegroup-config": `{"update":{"id":"u-1","status":"InProgress"}}`,
+			"aws eks describe-update":         `{"update":{"id":"u-1","status":"Successful"}}`,
+		},
+	}
+
+	provider := NewEKSProvider(&EKSOptions{PollFrequency: time.Millisecond})
+	provider.run = runner.run
+	cluster := ClusterRef{Provider: ProviderEKS, Name: "eks-prod", Region: "us-east-1"}
+
+	pools, err := provider.List(context.Background(), cluster)
+	if err != nil {
+		t.Fatalf("List() erro inesperado: %v", err)
+	}
+	if len(pools) != 1 || !pools[0].AutoscalingEnabled || pools[0].MinNodeCount != 2 || pools[0].NodeCount != 3 {
+		t.Fatalf("conversão inesperada: %+v", pools)
+	}
+
+	// Desabilitar autoscaling fixa min = max = desired atual
+	if err := provider.UpdateAutoscaler(context.Background(), cluster, "ng-1", AutoscalerSettings{Enabled: false}); err != nil {
+		t.Fatalf("UpdateAutoscaler() erro inesperado: %v", err)
+	}
+
+	var updateCall string
+	for _, call := range runner.calls {
+		if strings.HasPrefix(call, "aws eks update-nodegroup-config") {
+			updateCall = call
+		}
+	}
+	if !strings.Contains(updateCall, "minSize=3,maxSize=3,desiredSize=3") {
+		t.Errorf("scaling-config inesperado: %s", updateCall)
+	}
+	if !strings.Contains(runner.calls[len(runner.calls)-1], "describe-update") {
+		t.Error("esperado polling via describe-update")
+	}
+
+	// Scale fora dos limites do autoscaling deve falhar
+	if err := provider.Scale(context.Background(), cluster, "ng-1", 10); err == nil {
+		t.Error("esperado erro ao fazer scale fora dos limites")
+	}
+
+	// Erro de credencial é classificado como erro de autenticação
+	runner.errs = map[string]error{"aws eks list-nodegroups": errors.New("ExpiredTokenException: token expired")}
+	if _, err := provider.List(context.Background(), cluster); !IsAuthError(err) {
+		t.Errorf("esperado erro de autenticação, obtido: %v", err)
+	}
+}
+
+// TestCLIErrorClassification valida que CLI ausente é erro de configuração, não node pool inexistente
+func TestCLIErrorClassification(t *testing.T) {
+	runner := &scriptedRunner{errs: map[string]error{
+		"aws eks list-nodegroups": &cliError{
+			command: "aws eks list-nodegroups",
+			err:     &exec.Error{Name: "aws", Err: exec.ErrNotFound},
+		},
+	}}
+	provider := NewEKSProvider(nil)
+	provider.run = runner.run
+	cluster := ClusterRef{Provider: ProviderEKS, Name: "eks-prod", Region: "us-east-1"}
+
+	_, err := provider.List(context.Background(), cluster)
+	if !IsConfigError(err) {
+		t.Errorf("esperado erro de configuração, obtido: %v", err)
+	}
+	if IsNotFound(err) {
+		t.Errorf("CLI ausente não deve ser classificada como node pool inexistente: %v", err)
+	}
+
+	// "not found" genérico no stderr não indica recurso inexistente
+	runner.errs = map[string]error{"aws eks list-nodegroups": errors.New("profile prod not found in config")}
+	if _, err := provider.List(context.Background(), cluster); IsNotFound(err) || IsConfigError(err) {
+		t.Errorf("erro genérico classificado incorretamente: %v", err)
+	}
+
+	runner.errs = map[string]error{"aws eks list-nodegroups": errors.New("ResourceNotFoundException: No cluster found for name: eks-prod")}
+	if _, err := provider.List(context.Background(), cluster); !IsNotFound(err) {
+		t.Errorf("esperado NotFound, obtido: %v", err)
+	}
+}
+
+// TestGKEProviderList valida a contagem de nodes via instance groups e o update de autoscaling
+func TestGKEProviderList(t *testing.T) {
+	runner := &scriptedRunner{
+		responses: map[string]string{
+			"gcloud container node-pools list": `[{"name":"default-pool","status":"RUNNING","config":{"machineType":"e2-standard-4"},
+				"initialNodeCount":1,"autoscaling":{"enabled":true,"minNodeCount":1,"maxNodeCount":5},
+				"instanceGroupUrls":["https://www.googleapis.com/compute/v1/projects/p/zones/us-central1-a/instanceGroupManagers/gke-a",
+				"https://www.googleapis.com/compute/v1/projects/p/zones/us-central1-b/instanceGroupManagers/gke-b"]}]`,
+			"gcloud compute instance-groups managed describe": `{"targetSize":2}`,
+			"gcloud container node-pools update":              `{}`,
+		},
+	}
+
+	provider := NewGKEProvider(nil)
+	provider.run = runner.run
+	cluster := ClusterRef{Provider: ProviderGKE, Name: "gke-prod", Region: "us-central1", Project: "p"}
+
+	pools, err := provider.List(context.Background(), cluster)
+	if err != nil {
+		t.Fatalf("List() erro inesperado: %v", err)
+	}
+	if len(pools) != 1 || pools[0].NodeCount != 4 || pools[0].MaxNodeCount != 5 || pools[0].VMSize != "e2-standard-4" {
+		t.Fatalf("conversão inesperada: %+v", pools)
+	}
+
+	if err := provider.UpdateAutoscaler(context.Background(), cluster, "default-pool", AutoscalerSettings{Enabled: true, MinCount: 2, MaxCount: 8}); err != nil {
+		t.Fatalf("UpdateAutoscaler() erro inesperado: %v", err)
+	}
+	last := runner.calls[len(runner.calls)-1]
+	if !strings.Contains(last, "--enable-autoscaling --min-nodes 2 --max-nodes 8") || !strings.Contains(last, "--location us-central1") {
+		t.Errorf("comando inesperado: %s", last)
+	}
+
+	if _, err := provider.List(context.Background(), ClusterRef{Provider: ProviderGKE, Name: "gke-prod"}); err == nil {
+		t.Error("esperado erro sem project/location")
+	}
+}
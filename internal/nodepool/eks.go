@@ -0,0 +1,300 @@
+package nodepool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s-hpa-manager/internal/models"
+)
+
+// EKSOptions opções de criação do EKSProvider
+type EKSOptions struct {
+	// Profile perfil do AWS CLI (vazio = AWS_PROFILE / padrão)
+	Profile string
+	// PollFrequency intervalo entre consultas do status de um update (padrão 10s)
+	PollFrequency time.Duration
+}
+
+// EKSProvider implementa NodePoolProvider para managed node groups do EKS via AWS CLI.
+//
+// O EKS não tem um liga/desliga de autoscaler por node group: o estado é derivado do
+// scalingConfig. minSize == maxSize é tratado como manual; minSize < maxSize como autoscaling.
+// Como o EKS exige maxSize >= 1, um scale manual para 0 resulta em min=0/max=1.
+type EKSProvider struct {
+	run           commandRunner
+	profile       string
+	pollFrequency time.Duration
+}
+
+// NewEKSProvider cria um provider EKS
+func NewEKSProvider(opts *EKSOptions) *EKSProvider {
+	if opts == nil {
+		opts = &EKSOptions{}
+	}
+
+	pollFrequency := opts.PollFrequency
+	if pollFrequency <= 0 {
+		pollFrequency = defaultPollFrequency
+	}
+
+	return &EKSProvider{
+		run:           execRunner,
+		profile:       opts.Profile,
+		pollFrequency: pollFrequency,
+	}
+}
+
+// eksNodegroup campos de describe-nodegroup usados pela aplicação
+type eksNodegroup struct {
+	NodegroupName string   `json:"nodegroupName"`
+	Status        string   `json:"status"`
+	InstanceTypes []string `json:"instanceTypes"`
+	ScalingConfig struct {
+		MinSize     int32 `json:"minSize"`
+		MaxSize     int32 `json:"maxSize"`
+		DesiredSize int32 `json:"desiredSize"`
+	} `json:"scalingConfig"`
+	Labels map[string]string `json:"labels"`
+}
+
+// eksUpdate resposta de update-nodegroup-config / describe-update
+type eksUpdate struct {
+	Update struct {
+		ID     string `json:"id"`
+		Status string `json:"status"` // InProgress, Successful, Failed, Cancelled
+		Errors []struct {
+			ErrorCode    string `json:"errorCode"`
+			ErrorMessage string `json:"errorMessage"`
+		} `json:"errors"`
+	} `json:"update"`
+}
+
+// List retorna os managed node groups do cluster EKS
+func (p *EKSProvider) List(ctx context.Context, cluster ClusterRef) ([]models.NodePool, error) {
+	if err := validateEKSRef(cluster); err != nil {
+		return nil, wrapCLIError("list", cluster, "", err)
+	}
+
+	output, err := p.aws(ctx, cluster, "eks", "list-nodegroups", "--cluster-name", cluster.Name)
+	if err != nil {
+		return nil, wrapCLIError("list", cluster, "", err)
+	}
+
+	var result struct {
+		Nodegroups []string `json:"nodegroups"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, wrapCLIError("list", cluster, "", fmt.Errorf("failed to parse AWS CLI output: %w", err))
+	}
+
+	nodePools := make([]models.NodePool, 0, len(result.Nodegroups))
+	for _, name := range result.Nodegroups {
+		nodegroup, err := p.describe(ctx, cluster, name)
+		if err != nil {
+			return nil, wrapCLIError("list", cluster, name, err)
+		}
+		nodePools = append(nodePools, nodegroup.toModel(cluster))
+	}
+
+	return nodePools, nil
+}
+
+// Get retorna um managed node group específico
+func (p *EKSProvider) Get(ctx context.Context, cluster ClusterRef, name string) (*models.NodePool, error) {
+	if err := validateEKSRef(cluster); err != nil {
+		return nil, wrapCLIError("get", cluster, name, err)
+	}
+
+	nodegroup, err := p.describe(ctx, cluster, name)
+	if err != nil {
+		return nil, wrapCLIError("get", cluster, name, err)
+	}
+
+	nodePool := nodegroup.toModel(cluster)
+	return &nodePool, nil
+}
+
+// Scale altera o desiredSize do node group (em modo manual min/max acompanham o valor)
+func (p *EKSProvider) Scale(ctx context.Context, cluster ClusterRef, name string, count int32) error {
+	if count < 0 {
+		return wrapCLIError("scale", cluster, name, fmt.Errorf("invalid node count: %d", count))
+	}
+
+	return p.updateScaling(ctx, "scale", cluster, name, func(ng *eksNodegroup) (int32, int32, int32, error) {
+		sc := ng.ScalingConfig
+		if sc.MinSize == sc.MaxSize || (sc.MinSize == 0 && sc.MaxSize == 1 && sc.DesiredSize == 0) {
+			// Modo manual: min = max = desired
+			return count, maxInt32(count, 1), count, nil
+		}
+
+		if count < sc.MinSize || count > sc.MaxSize {
+			return 0, 0, 0, fmt.Errorf("node count %d outside autoscaling limits [%d, %d]", count, sc.MinSize, sc.MaxSize)
+		}
+		return sc.MinSize, sc.MaxSize, count, nil
+	})
+}
+
+// UpdateAutoscaler ajusta min/max do node group (desabilitar fixa min = max = desired atual)
+func (p *EKSProvider) UpdateAutoscaler(ctx context.Context, cluster ClusterRef, name string, settings AutoscalerSettings) error {
+	if settings.Enabled && (settings.MinCount < 0 || settings.MaxCount < 1 || settings.MaxCount < settings.MinCount) {
+		return wrapCLIError("update_autoscaler", cluster, name,
+			fmt.Errorf("invalid autoscaler limits: min=%d max=%d", settings.MinCount, settings.MaxCount))
+	}
+
+	return p.updateScaling(ctx, "update_autoscaler", cluster, name, func(ng *eksNodegroup) (int32, int32, int32, error) {
+		desired := ng.ScalingConfig.DesiredSize
+		if !settings.Enabled {
+			return desired, maxInt32(desired, 1), desired, nil
+		}
+
+		// Manter desired dentro dos novos limites
+		if desired < settings.MinCount {
+			desired = settings.MinCount
+		}
+		if desired > settings.MaxCount {
+			desired = settings.MaxCount
+		}
+		return settings.MinCount, settings.MaxCount, desired, nil
+	})
+}
+
+// updateScaling lê o node group, calcula o novo scalingConfig e aguarda o update terminar
+func (p *EKSProvider) updateScaling(ctx context.Context, op string, cluster ClusterRef, name string,
+	compute func(ng *eksNodegroup) (minSize, maxSize, desired int32, err error)) error {
+
+	if err := validateEKSRef(cluster); err != nil {
+		return wrapCLIError(op, cluster, name, err)
+	}
+
+	nodegroup, err := p.describe(ctx, cluster, name)
+	if err != nil {
+		return wrapCLIError(op, cluster, name, err)
+	}
+
+	minSize, maxSize, desired, err := compute(nodegroup)
+	if err != nil {
+		return wrapCLIError(op, cluster, name, err)
+	}
+
+	output, err := p.aws(ctx, cluster, "eks", "update-nodegroup-config",
+		"--cluster-name", cluster.Name,
+		"--nodegroup-name", name,
+		"--scaling-config", fmt.Sprintf("minSize=%d,maxSize=%d,desiredSize=%d", minSize, maxSize, desired))
+	if err != nil {
+		return wrapCLIError(op, cluster, name, err)
+	}
+
+	var update eksUpdate
+	if err := json.Unmarshal(output, &update); err != nil {
+		return wrapCLIError(op, cluster, name, fmt.Errorf("failed to parse AWS CLI output: %w", err))
+	}
+
+	if err := p.waitForUpdate(ctx, cluster, name, update); err != nil {
+		return wrapCLIError(op, cluster, name, err)
+	}
+	return nil
+}
+
+// waitForUpdate consulta describe-update até o update terminar
+func (p *EKSProvider) waitForUpdate(ctx context.Context, cluster ClusterRef, name string, update eksUpdate) error {
+	for {
+		switch update.Update.Status {
+		case "Successful":
+			return nil
+		case "Failed", "Cancelled":
+			messages := make([]string, 0, len(update.Update.Errors))
+			for _, e := range update.Update.Errors {
+				messages = append(messages, fmt.Sprintf("%s: %s", e.ErrorCode, e.ErrorMessage))
+			}
+			return fmt.Errorf("update %s %s: %s", update.Update.ID, strings.ToLower(update.Update.Status), strings.Join(messages, "; "))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(p.pollFrequency):
+		}
+
+		output, err := p.aws(ctx, cluster, "eks", "describe-update",
+			"--name", cluster.Name,
+			"--nodegroup-name", name,
+			"--update-id", update.Update.ID)
+		if err != nil {
+			return err
+		}
+
+		update = eksUpdate{}
+		if err := json.Unmarshal(output, &update); err != nil {
+			return fmt.Errorf("failed to parse AWS CLI output: %w", err)
+		}
+	}
+}
+
+// describe executa describe-nodegroup
+func (p *EKSProvider) describe(ctx context.Context, cluster ClusterRef, name string) (*eksNodegroup, error) {
+	output, err := p.aws(ctx, cluster, "eks", "describe-nodegroup", "--cluster-name", cluster.Name, "--nodegroup-name", name)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Nodegroup eksNodegroup `json:"nodegroup"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse AWS CLI output: %w", err)
+	}
+
+	return &result.Nodegroup, nil
+}
+
+// aws executa o AWS CLI com região, perfil e saída JSON
+func (p *EKSProvider) aws(ctx context.Context, cluster ClusterRef, args ...string) ([]byte, error) {
+	args = append(args, "--region", cluster.Region, "--output", "json")
+	if p.profile != "" {
+		args = append(args, "--profile", p.profile)
+	}
+	return p.run(ctx, "aws", args...)
+}
+
+// toModel converte um node group EKS para o modelo da aplicação
+func (ng *eksNodegroup) toModel(cluster ClusterRef) models.NodePool {
+	sc := ng.ScalingConfig
+	autoscaling := sc.MinSize != sc.MaxSize && !(sc.MinSize == 0 && sc.MaxSize == 1 && sc.DesiredSize == 0)
+
+	nodePool := models.NodePool{
+		Name:               ng.NodegroupName,
+		VMSize:             strings.Join(ng.InstanceTypes, ","),
+		NodeCount:          sc.DesiredSize,
+		AutoscalingEnabled: autoscaling,
+		Status:             ng.Status,
+		IsSystemPool:       ng.Labels["role"] == "system",
+		Provider:           ProviderEKS,
+		ClusterName:        cluster.Name,
+		ResourceGroup:      cluster.Region,
+	}
+	if autoscaling {
+		nodePool.MinNodeCount = sc.MinSize
+		nodePool.MaxNodeCount = sc.MaxSize
+	}
+
+	nodePool.OriginalValues = ValuesOf(&nodePool)
+	return nodePool
+}
+
+// validateEKSRef garante que o cluster tem os campos exigidos pelo AWS CLI
+func validateEKSRef(cluster ClusterRef) error {
+	if cluster.Name == "" || cluster.Region == "" {
+		return fmt.Errorf("cluster name and region are required for EKS")
+	}
+	return nil
+}
+
+func maxInt32(a, b int32) int32 {
+	if a > b {
+		return a
+	}
+	return b
+}
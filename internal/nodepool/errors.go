@@ -0,0 +1,77 @@
+package nodepool
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// CodeConfiguration código de Error para falhas de configuração local (ex: CLI aws/gcloud ausente)
+const CodeConfiguration = "ConfigurationError"
+
+// Error representa uma falha estruturada em uma operação de node pool
+type Error struct {
+	Op         string // Operação executada (list, get, scale, update_autoscaler)
+	Cluster    string
+	NodePool   string
+	StatusCode int    // Status HTTP retornado pelo provedor (0 se não houve resposta)
+	Code       string // Código de erro retornado pelo provedor (ex: ResourceNotFound)
+	Err        error
+}
+
+// Error implementa a interface error
+func (e *Error) Error() string {
+	target := e.Cluster
+	if e.NodePool != "" {
+		target = fmt.Sprintf("%s/%s", e.Cluster, e.NodePool)
+	}
+
+	if e.Code != "" && e.StatusCode == 0 {
+		return fmt.Sprintf("node pool %s %s failed (%s): %v", e.Op, target, e.Code, e.Err)
+	}
+	if e.Code != "" {
+		return fmt.Sprintf("node pool %s %s failed (%d %s): %v", e.Op, target, e.StatusCode, e.Code, e.Err)
+	}
+	return fmt.Sprintf("node pool %s %s failed: %v", e.Op, target, e.Err)
+}
+
+// Unwrap permite usar errors.Is/errors.As com o erro original
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// IsNotFound indica se o erro representa um cluster ou node pool inexistente
+func IsNotFound(err error) bool {
+	var npErr *Error
+	if errors.As(err, &npErr) {
+		return npErr.StatusCode == http.StatusNotFound
+	}
+	return false
+}
+
+// IsAuthError indica se o erro foi causado por falha de autenticação ou autorização
+func IsAuthError(err error) bool {
+	var npErr *Error
+	if errors.As(err, &npErr) {
+		return npErr.StatusCode == http.StatusUnauthorized || npErr.StatusCode == http.StatusForbidden
+	}
+	return false
+}
+
+// IsConflict indica se o node pool está ocupado com outra operação
+func IsConflict(err error) bool {
+	var npErr *Error
+	if errors.As(err, &npErr) {
+		return npErr.StatusCode == http.StatusConflict
+	}
+	return false
+}
+
+// IsConfigError indica se o erro vem da configuração local e não do provedor
+func IsConfigError(err error) bool {
+	var npErr *Error
+	if errors.As(err, &npErr) {
+		return npErr.Code == CodeConfiguration
+	}
+	return false
+}
@@ -0,0 +1,194 @@
+package nodepool
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"k8s-hpa-manager/internal/models"
+)
+
+// FakeCall registra uma chamada recebida pelo FakeProvider
+type FakeCall struct {
+	Op         string
+	Cluster    ClusterRef
+	NodePool   string
+	NodeCount  int32
+	Autoscaler AutoscalerSettings
+}
+
+// FakeProvider implementação em memória de NodePoolProvider para testes
+type FakeProvider struct {
+	mu    sync.Mutex
+	pools map[string]map[string]models.NodePool // cluster -> nome -> pool
+	calls []FakeCall
+
+	// Errors permite forçar erro em uma operação ("list", "get", "scale", "update_autoscaler")
+	Errors map[string]error
+}
+
+// NewFakeProvider cria um FakeProvider vazio
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{
+		pools:  make(map[string]map[string]models.NodePool),
+		Errors: make(map[string]error),
+	}
+}
+
+// AddNodePool adiciona (ou substitui) um node pool no cluster informado
+func (f *FakeProvider) AddNodePool(cluster ClusterRef, pool models.NodePool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.pools[cluster.Name] == nil {
+		f.pools[cluster.Name] = make(map[string]models.NodePool)
+	}
+
+	pool.Provider = NormalizeProvider(cluster.Provider)
+	pool.ClusterName = cluster.Name
+	pool.ResourceGroup = cluster.ResourceGroup
+	pool.Subscription = cluster.Subscription
+	f.pools[cluster.Name][pool.Name] = pool
+}
+
+// Calls retorna uma cópia das chamadas registradas
+func (f *FakeProvider) Calls() []FakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	calls := make([]FakeCall, len(f.calls))
+	copy(calls, f.calls)
+	return calls
+}
+
+// List implementa NodePoolProvider
+func (f *FakeProvider) List(ctx context.Context, cluster ClusterRef) ([]models.NodePool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, FakeCall{Op: "list", Cluster: cluster})
+	if err := f.Errors["list"]; err != nil {
+		return nil, &Error{Op: "list", Cluster: cluster.Name, Err: err}
+	}
+
+	clusterPools, ok := f.pools[cluster.Name]
+	if !ok {
+		return nil, f.notFound("list", cluster, "")
+	}
+
+	names := make([]string, 0, len(clusterPools))
+	for name := range clusterPools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	nodePools := make([]models.NodePool, 0, len(names))
+	for _, name := range names {
+		pool := clusterPools[name]
+		pool.OriginalValues = ValuesOf(&pool)
+		nodePools = append(nodePools, pool)
+	}
+
+	return nodePools, nil
+}
+
+// Get implementa NodePoolProvider
+func (f *FakeProvider) Get(ctx context.Context, cluster ClusterRef, name string) (*models.NodePool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, FakeCall{Op: "get", Cluster: cluster, NodePool: name})
+	if err := f.Errors["get"]; err != nil {
+		return nil, &Error{Op: "get", Cluster: cluster.Name, NodePool: name, Err: err}
+	}
+
+	pool, ok := f.pools[cluster.Name][name]
+	if !ok {
+		return nil, f.notFound("get", cluster, name)
+	}
+
+	pool.OriginalValues = ValuesOf(&pool)
+	return &pool, nil
+}
+
+// Scale implementa NodePoolProvider
+func (f *FakeProvider) Scale(ctx context.Context, cluster ClusterRef, name string, count int32) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, FakeCall{Op: "scale", Cluster: cluster, NodePool: name, NodeCount: count})
+	if err := f.Errors["scale"]; err != nil {
+		return &Error{Op: "scale", Cluster: cluster.Name, NodePool: name, Err: err}
+	}
+
+	pool, ok := f.pools[cluster.Name][name]
+	if !ok {
+		return f.notFound("scale", cluster, name)
+	}
+
+	// Mesmo comportamento do AKS: scale manual não é permitido com autoscaler ativo
+	if pool.AutoscalingEnabled {
+		return &Error{
+			Op:         "scale",
+			Cluster:    cluster.Name,
+			NodePool:   name,
+			StatusCode: http.StatusBadRequest,
+			Code:       "InvalidParameter",
+			Err:        fmt.Errorf("cannot scale node pool with cluster autoscaler enabled"),
+		}
+	}
+
+	pool.NodeCount = count
+	f.pools[cluster.Name][name] = pool
+	return nil
+}
+
+// UpdateAutoscaler implementa NodePoolProvider
+func (f *FakeProvider) UpdateAutoscaler(ctx context.Context, cluster ClusterRef, name string, settings AutoscalerSettings) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, FakeCall{Op: "update_autoscaler", Cluster: cluster, NodePool: name, Autoscaler: settings})
+	if err := f.Errors["update_autoscaler"]; err != nil {
+		return &Error{Op: "update_autoscaler", Cluster: cluster.Name, NodePool: name, Err: err}
+	}
+
+	pool, ok := f.pools[cluster.Name][name]
+	if !ok {
+		return f.notFound("update_autoscaler", cluster, name)
+	}
+
+	pool.AutoscalingEnabled = settings.Enabled
+	if settings.Enabled {
+		pool.MinNodeCount = settings.MinCount
+		pool.MaxNodeCount = settings.MaxCount
+
+		// Autoscaler mantém o count dentro dos limites
+		if pool.NodeCount < settings.MinCount {
+			pool.NodeCount = settings.MinCount
+		}
+		if pool.NodeCount > settings.MaxCount {
+			pool.NodeCount = settings.MaxCount
+		}
+	} else {
+		pool.MinNodeCount = 0
+		pool.MaxNodeCount = 0
+	}
+
+	f.pools[cluster.Name][name] = pool
+	return nil
+}
+
+// notFound cria o erro padrão de recurso inexistente
+func (f *FakeProvider) notFound(op string, cluster ClusterRef, name string) error {
+	return &Error{
+		Op:         op,
+		Cluster:    cluster.Name,
+		NodePool:   name,
+		StatusCode: http.StatusNotFound,
+		Code:       "NotFound",
+		Err:        fmt.Errorf("resource not found"),
+	}
+}
@@ -0,0 +1,224 @@
+package nodepool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s-hpa-manager/internal/models"
+)
+
+// GKEOptions opções de criação do GKEProvider
+type GKEOptions struct {
+	// Account conta do gcloud a usar (vazio = conta ativa)
+	Account string
+}
+
+// GKEProvider implementa NodePoolProvider para node pools do GKE via gcloud CLI.
+// Os comandos do gcloud aguardam a operação terminar antes de retornar.
+// Em clusters regionais, contagens e limites do GKE são por zona.
+type GKEProvider struct {
+	run     commandRunner
+	account string
+}
+
+// NewGKEProvider cria um provider GKE
+func NewGKEProvider(opts *GKEOptions) *GKEProvider {
+	if opts == nil {
+		opts = &GKEOptions{}
+	}
+
+	return &GKEProvider{
+		run:     execRunner,
+		account: opts.Account,
+	}
+}
+
+// gkeNodePool campos de node-pools describe usados pela aplicação
+type gkeNodePool struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Config struct {
+		MachineType string            `json:"machineType"`
+		Labels      map[string]string `json:"labels"`
+	} `json:"config"`
+	InitialNodeCount int32 `json:"initialNodeCount"`
+	Autoscaling      struct {
+		Enabled      bool  `json:"enabled"`
+		MinNodeCount int32 `json:"minNodeCount"`
+		MaxNodeCount int32 `json:"maxNodeCount"`
+	} `json:"autoscaling"`
+	InstanceGroupUrls []string `json:"instanceGroupUrls"`
+}
+
+// List retorna os node pools do cluster GKE
+func (p *GKEProvider) List(ctx context.Context, cluster ClusterRef) ([]models.NodePool, error) {
+	if err := validateGKERef(cluster); err != nil {
+		return nil, wrapCLIError("list", cluster, "", err)
+	}
+
+	output, err := p.gcloud(ctx, cluster, "container", "node-pools", "list", "--cluster", cluster.Name)
+	if err != nil {
+		return nil, wrapCLIError("list", cluster, "", err)
+	}
+
+	var pools []gkeNodePool
+	if err := json.Unmarshal(output, &pools); err != nil {
+		return nil, wrapCLIError("list", cluster, "", fmt.Errorf("failed to parse gcloud output: %w", err))
+	}
+
+	nodePools := make([]models.NodePool, 0, len(pools))
+	for i := range pools {
+		nodePools = append(nodePools, p.toModel(ctx, cluster, &pools[i]))
+	}
+
+	return nodePools, nil
+}
+
+// Get retorna um node pool específico do cluster GKE
+func (p *GKEProvider) Get(ctx context.Context, cluster ClusterRef, name string) (*models.NodePool, error) {
+	if err := validateGKERef(cluster); err != nil {
+		return nil, wrapCLIError("get", cluster, name, err)
+	}
+
+	output, err := p.gcloud(ctx, cluster, "container", "node-pools", "describe", name, "--cluster", cluster.Name)
+	if err != nil {
+		return nil, wrapCLIError("get", cluster, name, err)
+	}
+
+	var pool gkeNodePool
+	if err := json.Unmarshal(output, &pool); err != nil {
+		return nil, wrapCLIError("get", cluster, name, fmt.Errorf("failed to parse gcloud output: %w", err))
+	}
+
+	nodePool := p.toModel(ctx, cluster, &pool)
+	return &nodePool, nil
+}
+
+// Scale altera o número de nodes do pool (equivalente a gcloud container clusters resize)
+func (p *GKEProvider) Scale(ctx context.Context, cluster ClusterRef, name string, count int32) error {
+	if count < 0 {
+		return wrapCLIError("scale", cluster, name, fmt.Errorf("invalid node count: %d", count))
+	}
+	if err := validateGKERef(cluster); err != nil {
+		return wrapCLIError("scale", cluster, name, err)
+	}
+
+	_, err := p.gcloud(ctx, cluster, "container", "clusters", "resize", cluster.Name,
+		"--node-pool", name,
+		"--num-nodes", strconv.Itoa(int(count)),
+		"--quiet")
+	if err != nil {
+		return wrapCLIError("scale", cluster, name, err)
+	}
+	return nil
+}
+
+// UpdateAutoscaler habilita, desabilita ou ajusta min/max do autoscaling do node pool
+func (p *GKEProvider) UpdateAutoscaler(ctx context.Context, cluster ClusterRef, name string, settings AutoscalerSettings) error {
+	if settings.Enabled && (settings.MinCount < 0 || settings.MaxCount < settings.MinCount) {
+		return wrapCLIError("update_autoscaler", cluster, name,
+			fmt.Errorf("invalid autoscaler limits: min=%d max=%d", settings.MinCount, settings.MaxCount))
+	}
+	if err := validateGKERef(cluster); err != nil {
+		return wrapCLIError("update_autoscaler", cluster, name, err)
+	}
+
+	args := []string{"container", "node-pools", "update", name, "--cluster", cluster.Name}
+	if settings.Enabled {
+		args = append(args,
+			"--enable-autoscaling",
+			"--min-nodes", strconv.Itoa(int(settings.MinCount)),
+			"--max-nodes", strconv.Itoa(int(settings.MaxCount)))
+	} else {
+		args = append(args, "--no-enable-autoscaling")
+	}
+	args = append(args, "--quiet")
+
+	if _, err := p.gcloud(ctx, cluster, args...); err != nil {
+		return wrapCLIError("update_autoscaler", cluster, name, err)
+	}
+	return nil
+}
+
+// gcloud executa o gcloud CLI com projeto, location e saída JSON
+func (p *GKEProvider) gcloud(ctx context.Context, cluster ClusterRef, args ...string) ([]byte, error) {
+	args = append(args, "--location", cluster.Region, "--project", cluster.Project, "--format", "json")
+	if p.account != "" {
+		args = append(args, "--account", p.account)
+	}
+	return p.run(ctx, "gcloud", args...)
+}
+
+// currentNodeCount soma o targetSize dos managed instance groups do node pool.
+// Retorna initialNodeCount se não for possível consultar os instance groups.
+func (p *GKEProvider) currentNodeCount(ctx context.Context, cluster ClusterRef, pool *gkeNodePool) int32 {
+	if len(pool.InstanceGroupUrls) == 0 {
+		return pool.InitialNodeCount
+	}
+
+	var total int32
+	for _, groupURL := range pool.InstanceGroupUrls {
+		// Formato: .../projects/P/zones/Z/instanceGroupManagers/NAME
+		parts := strings.Split(groupURL, "/")
+		if len(parts) < 4 {
+			return pool.InitialNodeCount
+		}
+		groupName := parts[len(parts)-1]
+		zone := parts[len(parts)-3]
+
+		args := []string{"compute", "instance-groups", "managed", "describe", groupName,
+			"--zone", zone, "--project", cluster.Project, "--format", "json"}
+		if p.account != "" {
+			args = append(args, "--account", p.account)
+		}
+
+		output, err := p.run(ctx, "gcloud", args...)
+		if err != nil {
+			return pool.InitialNodeCount
+		}
+
+		var group struct {
+			TargetSize int32 `json:"targetSize"`
+		}
+		if err := json.Unmarshal(output, &group); err != nil {
+			return pool.InitialNodeCount
+		}
+		total += group.TargetSize
+	}
+
+	return total
+}
+
+// toModel converte um node pool GKE para o modelo da aplicação
+func (p *GKEProvider) toModel(ctx context.Context, cluster ClusterRef, pool *gkeNodePool) models.NodePool {
+	nodePool := models.NodePool{
+		Name:               pool.Name,
+		VMSize:             pool.Config.MachineType,
+		NodeCount:          p.currentNodeCount(ctx, cluster, pool),
+		AutoscalingEnabled: pool.Autoscaling.Enabled,
+		Status:             pool.Status,
+		IsSystemPool:       pool.Config.Labels["role"] == "system",
+		Provider:           ProviderGKE,
+		ClusterName:        cluster.Name,
+		ResourceGroup:      cluster.Region,
+		Subscription:       cluster.Project,
+	}
+	if pool.Autoscaling.Enabled {
+		nodePool.MinNodeCount = pool.Autoscaling.MinNodeCount
+		nodePool.MaxNodeCount = pool.Autoscaling.MaxNodeCount
+	}
+
+	nodePool.OriginalValues = ValuesOf(&nodePool)
+	return nodePool
+}
+
+// validateGKERef garante que o cluster tem os campos exigidos pelo gcloud
+func validateGKERef(cluster ClusterRef) error {
+	if cluster.Name == "" || cluster.Region == "" || cluster.Project == "" {
+		return fmt.Errorf("cluster name, region (location) and project are required for GKE")
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package nodepool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s-hpa-manager/internal/models"
+)
+
+// ClusterRef identifica o cluster gerenciado dono dos node pools
+type ClusterRef struct {
+	Provider      string `json:"provider"` // aks (padrão), eks ou gke
+	Name          string `json:"name"`
+	ResourceGroup string `json:"resource_group"` // AKS
+	Subscription  string `json:"subscription"`   // AKS
+	Region        string `json:"region"`         // EKS: região AWS / GKE: região ou zona
+	Project       string `json:"project"`        // GKE: project ID
+}
+
+// ClusterRefFromConfig converte uma entrada do clusters-config.json em ClusterRef.
+// O sufixo "-admin" do contexto kubeconfig é removido para obter o nome real do cluster.
+func ClusterRefFromConfig(cfg *models.ClusterConfig) ClusterRef {
+	return ClusterRef{
+		Provider:      NormalizeProvider(cfg.Provider),
+		Name:          strings.TrimSuffix(cfg.ClusterName, "-admin"),
+		ResourceGroup: cfg.ResourceGroup,
+		Subscription:  cfg.Subscription,
+		Region:        cfg.Region,
+		Project:       cfg.Project,
+	}
+}
+
+// String retorna uma representação legível do cluster
+func (r ClusterRef) String() string {
+	switch NormalizeProvider(r.Provider) {
+	case ProviderEKS:
+		return fmt.Sprintf("eks:%s/%s", r.Region, r.Name)
+	case ProviderGKE:
+		return fmt.Sprintf("gke:%s/%s/%s", r.Project, r.Region, r.Name)
+	}
+	return fmt.Sprintf("%s/%s", r.ResourceGroup, r.Name)
+}
+
+// AutoscalerSettings define o estado desejado do cluster autoscaler de um node pool
+type AutoscalerSettings struct {
+	Enabled  bool  `json:"enabled"`
+	MinCount int32 `json:"min_count"`
+	MaxCount int32 `json:"max_count"`
+}
+
+// NodePoolProvider abstrai as operações de node pool de um provedor de nuvem
+type NodePoolProvider interface {
+	// List retorna todos os node pools do cluster
+	List(ctx context.Context, cluster ClusterRef) ([]models.NodePool, error)
+	// Get retorna um node pool específico
+	Get(ctx context.Context, cluster ClusterRef, name string) (*models.NodePool, error)
+	// Scale altera o número de nodes de um pool com autoscaling desabilitado
+	Scale(ctx context.Context, cluster ClusterRef, name string, count int32) error
+	// UpdateAutoscaler habilita, desabilita ou ajusta os limites do cluster autoscaler
+	UpdateAutoscaler(ctx context.Context, cluster ClusterRef, name string, settings AutoscalerSettings) error
+}
@@ -0,0 +1,254 @@
+package nodepool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+
+	"k8s-hpa-manager/internal/models"
+)
+
+// TestPlan valida a ordem dos passos em cada transição de autoscaling
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name     string
+		original models.NodePoolValues
+		target   models.NodePoolValues
+		want     []StepAction
+	}{
+		{
+			name:     "auto para manual desabilita antes do scale",
+			original: models.NodePoolValues{AutoscalingEnabled: true, NodeCount: 3, MinNodeCount: 2, MaxNodeCount: 5},
+			target:   models.NodePoolValues{AutoscalingEnabled: false, NodeCount: 0},
+			want:     []StepAction{StepDisableAutoscaler, StepScale},
+		},
+		{
+			name:     "manual para auto faz scale antes de habilitar",
+			original: models.NodePoolValues{AutoscalingEnabled: false, NodeCount: 0},
+			target:   models.NodePoolValues{AutoscalingEnabled: true, NodeCount: 2, MinNodeCount: 2, MaxNodeCount: 6},
+			want:     []StepAction{StepScale, StepEnableAutoscaler},
+		},
+		{
+			name:     "permanece auto atualiza limites",
+			original: models.NodePoolValues{AutoscalingEnabled: true, NodeCount: 3, MinNodeCount: 2, MaxNodeCount: 5},
+			target:   models.NodePoolValues{AutoscalingEnabled: true, NodeCount: 3, MinNodeCount: 3, MaxNodeCount: 10},
+			want:     []StepAction{StepUpdateAutoscaler},
+		},
+		{
+			name:     "permanece manual faz scale",
+			original: models.NodePoolValues{NodeCount: 3},
+			target:   models.NodePoolValues{NodeCount: 5},
+			want:     []StepAction{StepScale},
+		},
+		{
+			name:     "sem mudanças",
+			original: models.NodePoolValues{AutoscalingEnabled: true, MinNodeCount: 1, MaxNodeCount: 3},
+			target:   models.NodePoolValues{AutoscalingEnabled: true, MinNodeCount: 1, MaxNodeCount: 3},
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps := Plan(tt.original, tt.target)
+			if len(steps) != len(tt.want) {
+				t.Fatalf("Plan() retornou %d passos, esperado %d: %+v", len(steps), len(tt.want), steps)
+			}
+			for i, step := range steps {
+				if step.Action != tt.want[i] {
+					t.Errorf("passo %d = %s, esperado %s", i, step.Action, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// TestApplyFromLiveWithFakeProvider aplica mudanças a partir do estado real do fake
+func TestApplyFromLiveWithFakeProvider(t *testing.T) {
+	cluster := ClusterRef{Name: "aks-test", ResourceGroup: "rg-test", Subscription: "sub-1"}
+	fake := NewFakeProvider()
+	fake.AddNodePool(cluster, models.NodePool{
+		Name:               "monitoring-1",
+		NodeCount:          3,
+		MinNodeCount:       2,
+		MaxNodeCount:       5,
+		AutoscalingEnabled: true,
+	})
+
+	var progressCalls int
+	target := models.NodePoolValues{AutoscalingEnabled: false, NodeCount: 0}
+	err := ApplyFromLive(context.Background(), fake, cluster, "monitoring-1", target, func(step, total int, description string) {
+		progressCalls++
+	})
+	if err != nil {
+		t.Fatalf("ApplyFromLive() erro inesperado: %v", err)
+	}
+
+	if progressCalls != 2 {
+		t.Errorf("progresso chamado %d vezes, esperado 2", progressCalls)
+	}
+
+	pool, err := fake.Get(context.Background(), cluster, "monitoring-1")
+	if err != nil {
+		t.Fatalf("Get() erro inesperado: %v", err)
+	}
+	if pool.AutoscalingEnabled || pool.NodeCount != 0 {
+		t.Errorf("estado final inesperado: autoscaling=%v count=%d", pool.AutoscalingEnabled, pool.NodeCount)
+	}
+}
+
+// TestFakeProviderErrors valida os erros estruturados
+func TestFakeProviderErrors(t *testing.T) {
+	cluster := ClusterRef{Name: "aks-test", ResourceGroup: "rg-test", Subscription: "sub-1"}
+	fake := NewFakeProvider()
+	fake.AddNodePool(cluster, models.NodePool{Name: "pool-auto", AutoscalingEnabled: true, MinNodeCount: 1, MaxNodeCount: 3})
+
+	_, err := fake.Get(context.Background(), cluster, "inexistente")
+	if !IsNotFound(err) {
+		t.Errorf("esperado erro NotFound, obtido: %v", err)
+	}
+
+	// Scale com autoscaler ativo deve falhar (mesmo comportamento do AKS)
+	if err := fake.Scale(context.Background(), cluster, "pool-auto", 5); err == nil {
+		t.Error("esperado erro ao fazer scale com autoscaler habilitado")
+	}
+
+	fake.Errors["list"] = errors.New("boom")
+	if _, err := fake.List(context.Background(), cluster); err == nil {
+		t.Error("esperado erro forçado em List")
+	}
+}
+
+// fakeCredential credencial estática para testes
+type fakeCredential struct{}
+
+func (fakeCredential) GetToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "fake-token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+// newTestAKSProvider cria um AKSProvider apontando para o servidor de teste
+func newTestAKSProvider(serverURL string) *AKSProvider {
+	return NewAKSProviderWithCredential(fakeCredential{}, &AKSOptions{
+		PollFrequency: 10 * time.Millisecond,
+		ClientOptions: &arm.ClientOptions{
+			ClientOptions: policy.ClientOptions{
+				Cloud: cloud.Configuration{
+					ActiveDirectoryAuthorityHost: serverURL,
+					Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+						cloud.ResourceManager: {Audience: "https://management.core.windows.net/", Endpoint: serverURL},
+					},
+				},
+				Retry:                           policy.RetryOptions{MaxRetries: -1},
+				InsecureAllowCredentialWithHTTP: true,
+			},
+			DisableRPRegistration: true,
+		},
+	})
+}
+
+// TestAKSProviderListAndScale valida list e scale (com operação assíncrona) contra um servidor ARM simulado
+func TestAKSProviderListAndScale(t *testing.T) {
+	const poolPath = "/subscriptions/sub-1/resourceGroups/rg-test/providers/Microsoft.ContainerService/managedClusters/aks-test/agentPools"
+
+	var mu sync.Mutex
+	count := 3
+	var putBody map[string]any
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Header.Get("Authorization") != "Bearer fake-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == poolPath:
+			io.WriteString(w, `{"value":[
+				{"name":"system","properties":{"count":2,"vmSize":"Standard_D4s_v3","mode":"System","enableAutoScaling":false,"provisioningState":"Succeeded"}},
+				{"name":"user-1","properties":{"count":3,"vmSize":"Standard_D8s_v3","mode":"User","enableAutoScaling":true,"minCount":1,"maxCount":5,"provisioningState":"Succeeded"}}
+			]}`)
+
+		case r.Method == http.MethodGet && r.URL.Path == poolPath+"/system":
+			json.NewEncoder(w).Encode(map[string]any{
+				"name": "system",
+				"properties": map[string]any{
+					"count": count, "vmSize": "Standard_D4s_v3", "mode": "System",
+					"enableAutoScaling": false, "provisioningState": "Succeeded",
+				},
+			})
+
+		case r.Method == http.MethodPut && r.URL.Path == poolPath+"/system":
+			json.NewDecoder(r.Body).Decode(&putBody)
+			props := putBody["properties"].(map[string]any)
+			count = int(props["count"].(float64))
+
+			w.Header().Set("Azure-AsyncOperation", server.URL+"/operations/op-1")
+			w.WriteHeader(http.StatusCreated)
+			io.WriteString(w, `{"name":"system","properties":{"provisioningState":"Scaling"}}`)
+
+		case r.Method == http.MethodGet && r.URL.Path == "/operations/op-1":
+			io.WriteString(w, `{"status":"Succeeded"}`)
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"error":{"code":"ResourceNotFound","message":"not found"}}`)
+		}
+	}))
+	defer server.Close()
+
+	provider := newTestAKSProvider(server.URL)
+	cluster := ClusterRef{Name: "aks-test", ResourceGroup: "rg-test", Subscription: "sub-1"}
+
+	pools, err := provider.List(context.Background(), cluster)
+	if err != nil {
+		t.Fatalf("List() erro inesperado: %v", err)
+	}
+	if len(pools) != 2 {
+		t.Fatalf("List() retornou %d pools, esperado 2", len(pools))
+	}
+	if !pools[0].IsSystemPool || pools[1].MaxNodeCount != 5 || !pools[1].AutoscalingEnabled {
+		t.Errorf("conversão inesperada: %+v", pools)
+	}
+
+	if err := provider.Scale(context.Background(), cluster, "system", 4); err != nil {
+		t.Fatalf("Scale() erro inesperado: %v", err)
+	}
+
+	props := putBody["properties"].(map[string]any)
+	if _, ok := props["provisioningState"]; ok {
+		t.Error("PUT não deve enviar provisioningState")
+	}
+
+	pool, err := provider.Get(context.Background(), cluster, "system")
+	if err != nil {
+		t.Fatalf("Get() erro inesperado: %v", err)
+	}
+	if pool.NodeCount != 4 {
+		t.Errorf("NodeCount = %d, esperado 4", pool.NodeCount)
+	}
+
+	_, err = provider.Get(context.Background(), cluster, "inexistente")
+	if !IsNotFound(err) {
+		t.Fatalf("esperado erro NotFound, obtido: %v", err)
+	}
+	var npErr *Error
+	if !errors.As(err, &npErr) || npErr.Code != "ResourceNotFound" || !strings.Contains(err.Error(), "inexistente") {
+		t.Errorf("erro estruturado inesperado: %+v", npErr)
+	}
+}
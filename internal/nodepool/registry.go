@@ -0,0 +1,115 @@
+package nodepool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s-hpa-manager/internal/azure"
+	"k8s-hpa-manager/internal/models"
+)
+
+// Provedores suportados no campo "provider" do clusters-config.json
+const (
+	ProviderAKS = "aks"
+	ProviderEKS = "eks"
+	ProviderGKE = "gke"
+)
+
+// NormalizeProvider normaliza o nome do provedor (vazio = AKS, para compatibilidade com configs antigas)
+func NormalizeProvider(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return ProviderAKS
+	}
+	return name
+}
+
+// ProviderName retorna o provedor normalizado de uma entrada do clusters-config.json
+func ProviderName(cfg *models.ClusterConfig) string {
+	return NormalizeProvider(cfg.Provider)
+}
+
+// Registry despacha operações para o NodePoolProvider do provedor de cada cluster.
+// O próprio Registry implementa NodePoolProvider, então handlers e TUI usam uma única instância.
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[string]NodePoolProvider
+}
+
+// NewRegistry cria um Registry vazio
+func NewRegistry() *Registry {
+	return &Registry{
+		providers: make(map[string]NodePoolProvider),
+	}
+}
+
+// NewDefaultRegistry cria um Registry com AKS (API ARM), EKS (aws CLI) e GKE (gcloud CLI)
+func NewDefaultRegistry(auth *azure.AuthManager) *Registry {
+	registry := NewRegistry()
+	registry.Register(ProviderAKS, NewAKSProvider(auth, nil))
+	registry.Register(ProviderEKS, NewEKSProvider(nil))
+	registry.Register(ProviderGKE, NewGKEProvider(nil))
+	return registry
+}
+
+// Register registra (ou substitui) o provider de um provedor
+func (r *Registry) Register(name string, provider NodePoolProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[NormalizeProvider(name)] = provider
+}
+
+// For retorna o provider responsável pelo cluster
+func (r *Registry) For(cluster ClusterRef) (NodePoolProvider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	name := NormalizeProvider(cluster.Provider)
+	provider, ok := r.providers[name]
+	if !ok {
+		return nil, &Error{
+			Op:      "resolve_provider",
+			Cluster: cluster.Name,
+			Err:     fmt.Errorf("unsupported node pool provider: %s", name),
+		}
+	}
+	return provider, nil
+}
+
+// List implementa NodePoolProvider
+func (r *Registry) List(ctx context.Context, cluster ClusterRef) ([]models.NodePool, error) {
+	provider, err := r.For(cluster)
+	if err != nil {
+		return nil, err
+	}
+	return provider.List(ctx, cluster)
+}
+
+// Get implementa NodePoolProvider
+func (r *Registry) Get(ctx context.Context, cluster ClusterRef, name string) (*models.NodePool, error) {
+	provider, err := r.For(cluster)
+	if err != nil {
+		return nil, err
+	}
+	return provider.Get(ctx, cluster, name)
+}
+
+// Scale implementa NodePoolProvider
+func (r *Registry) Scale(ctx context.Context, cluster ClusterRef, name string, count int32) error {
+	provider, err := r.For(cluster)
+	if err != nil {
+		return err
+	}
+	return provider.Scale(ctx, cluster, name, count)
+}
+
+// UpdateAutoscaler implementa NodePoolProvider
+func (r *Registry) UpdateAutoscaler(ctx context.Context, cluster ClusterRef, name string, settings AutoscalerSettings) error {
+	provider, err := r.For(cluster)
+	if err != nil {
+		return err
+	}
+	return provider.UpdateAutoscaler(ctx, cluster, name, settings)
+}
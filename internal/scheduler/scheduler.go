@@ -0,0 +1,659 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s-hpa-manager/internal/history"
+	"k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/session"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+)
+
+// Status de um agendamento
+const (
+	StatusScheduled       = "scheduled"        // aguardando execução
+	StatusRunning         = "running"          // aplicando a sessão
+	StatusPendingRollback = "pending_rollback" // aplicado, aguardando rollback automático
+	StatusCompleted       = "completed"        // execução única concluída
+	StatusFailed          = "failed"           // execução única falhou
+	StatusMissed          = "missed"           // servidor estava fora do ar no horário
+	StatusDisabled        = "disabled"
+)
+
+// DefaultMissedGrace tolerância para executar agendamentos perdidos (servidor fora do ar)
+const DefaultMissedGrace = 15 * time.Minute
+
+// Schedule agendamento de aplicação de uma sessão salva
+type Schedule struct {
+	ID          string `json:"id"`
+	Name        string `json:"name,omitempty"`
+	SessionName string `json:"session_name"`
+	Folder      string `json:"folder,omitempty"`
+
+	// Quando executar: RunAt (execução única) OU Cron (expressão padrão de 5 campos)
+	RunAt    *time.Time `json:"run_at,omitempty"`
+	Cron     string     `json:"cron,omitempty"`
+	Timezone string     `json:"timezone,omitempty"` // IANA, ex: America/Sao_Paulo (padrão: local)
+
+	// Rollback automático (opcional): horário absoluto OU duração após a aplicação ("17h")
+	RollbackAt    *time.Time `json:"rollback_at,omitempty"`
+	RollbackAfter string     `json:"rollback_after,omitempty"`
+
+	RollbackOnFailure bool `json:"rollback_on_failure"`
+	Enabled           bool `json:"enabled"`
+
+	// ConflictResolution decisão para itens alterados no cluster desde que a sessão foi salva
+	// (skip, force ou rebase). Vazio: a execução falha sem aplicar nada se houver conflitos.
+	ConflictResolution session.ConflictResolution `json:"conflict_resolution,omitempty"`
+
+	// Estado (mantido pelo scheduler)
+	Status            string     `json:"status"`
+	NextRunAt         *time.Time `json:"next_run_at,omitempty"`
+	PendingRollbackAt *time.Time `json:"pending_rollback_at,omitempty"`
+	RollbackSession   string     `json:"rollback_session,omitempty"`
+	LastRun           *Run       `json:"last_run,omitempty"`
+	CreatedBy         string     `json:"created_by,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+
+	// Owner quem criou/atualizou o agendamento. A sessão é reautorizada a cada execução com
+	// as permissões atuais do owner, pois o arquivo pode ser editado depois de agendado.
+	Owner *Owner `json:"owner,omitempty"`
+}
+
+// Owner identidade gravada no agendamento. Só usuário e grupos são persistidos: as
+// permissões são resolvidas pelos bindings vigentes (usuário revogado deixa de executar).
+type Owner struct {
+	Method   string   `json:"method"` // oidc, token
+	Username string   `json:"username"`
+	Groups   []string `json:"groups,omitempty"`
+}
+
+// Authorizer verifica se o owner ainda pode aplicar a sessão no momento da execução
+type Authorizer interface {
+	AuthorizeSession(owner Owner, sess *models.Session) error
+}
+
+// Run resultado da última execução de um agendamento
+type Run struct {
+	Action          string    `json:"action"` // scheduled_apply ou scheduled_rollback
+	StartedAt       time.Time `json:"started_at"`
+	Status          string    `json:"status"` // success, failed, partial
+	Applied         int       `json:"applied"`
+	Failed          int       `json:"failed"`
+	Error           string    `json:"error,omitempty"`
+	RollbackSession string    `json:"rollback_session,omitempty"`
+}
+
+// Executor aplica uma sessão salva pelo mesmo caminho do POST /sessions/:name/apply.
+// authorize é chamado com a sessão carregada e, se retornar erro, nada é aplicado.
+type Executor func(ctx context.Context, sessionName string, folder session.SessionFolder, opts session.ApplyOptions, authorize func(*models.Session) error) (*session.Result, error)
+
+// Scheduler executa sessões em horários agendados
+type Scheduler struct {
+	mu        sync.Mutex
+	path      string
+	schedules map[string]*Schedule
+	execute   Executor
+	authz     Authorizer
+	history   *history.HistoryTracker
+	interval  time.Duration
+	now       func() time.Time
+
+	// MissedGrace tolerância para executar agendamentos cujo horário passou com o servidor fora do ar
+	MissedGrace time.Duration
+}
+
+// New cria um scheduler persistido em dir/schedules.json
+func New(dir string, execute Executor, authz Authorizer, ht *history.HistoryTracker) (*Scheduler, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create schedules directory: %w", err)
+	}
+
+	s := &Scheduler{
+		path:        filepath.Join(dir, "schedules.json"),
+		schedules:   make(map[string]*Schedule),
+		execute:     execute,
+		authz:       authz,
+		history:     ht,
+		interval:    15 * time.Second,
+		now:         time.Now,
+		MissedGrace: DefaultMissedGrace,
+	}
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// DefaultDir retorna o diretório de agendamentos ao lado do diretório de sessões
+func DefaultDir(sessionDir string) string {
+	return filepath.Join(filepath.Dir(sessionDir), "schedules")
+}
+
+// Run executa o loop do scheduler até o contexto ser cancelado
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.Tick(ctx)
+	for {
+		select {
+		case <-ticker.C:
+			s.Tick(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Tick executa os agendamentos e rollbacks vencidos
+func (s *Scheduler) Tick(ctx context.Context) {
+	now := s.now()
+
+	for _, id := range s.dueIDs(now) {
+		if ctx.Err() != nil {
+			return
+		}
+		s.runDue(ctx, id, now)
+	}
+}
+
+// dueIDs retorna os agendamentos com aplicação ou rollback vencidos, em ordem de horário
+func (s *Scheduler) dueIDs(now time.Time) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type due struct {
+		id string
+		at time.Time
+	}
+	var list []due
+	for id, sched := range s.schedules {
+		if !sched.Enabled || sched.Status == StatusRunning {
+			continue
+		}
+		if sched.PendingRollbackAt != nil && !sched.PendingRollbackAt.After(now) {
+			list = append(list, due{id, *sched.PendingRollbackAt})
+		} else if sched.NextRunAt != nil && !sched.NextRunAt.After(now) {
+			list = append(list, due{id, *sched.NextRunAt})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].at.Before(list[j].at) })
+
+	ids := make([]string, len(list))
+	for i, d := range list {
+		ids[i] = d.id
+	}
+	return ids
+}
+
+// runDue executa o rollback pendente ou a aplicação de um agendamento
+func (s *Scheduler) runDue(ctx context.Context, id string, now time.Time) {
+	s.mu.Lock()
+	sched, ok := s.schedules[id]
+	if !ok {
+		s.mu.Unlock()
+		return
+	}
+
+	rollback := sched.PendingRollbackAt != nil && !sched.PendingRollbackAt.After(now)
+	if !rollback && sched.NextRunAt != nil && now.Sub(*sched.NextRunAt) > s.MissedGrace {
+		// Horário perdido (servidor fora do ar): não aplicar fora da janela
+		fmt.Printf("⏰ Agendamento %s perdido (previsto para %s)\n", sched.displayName(), sched.NextRunAt.Format(time.RFC3339))
+		sched.LastRun = &Run{Action: history.ActionScheduledApply, StartedAt: now, Status: history.StatusFailed,
+			Error: fmt.Sprintf("missed: server was not running at %s", sched.NextRunAt.Format(time.RFC3339))}
+		s.advance(sched, now, StatusMissed)
+		s.saveLocked()
+		s.mu.Unlock()
+		return
+	}
+
+	sessionName, folder, owner := sched.SessionName, session.SessionFolder(sched.Folder), sched.Owner
+	action := history.ActionScheduledApply
+	opts := session.ApplyOptions{RollbackOnFailure: sched.RollbackOnFailure, DefaultResolution: sched.ConflictResolution}
+	if rollback {
+		sessionName, folder, action = sched.RollbackSession, session.FolderRollback, history.ActionScheduledRollback
+		opts = session.ApplyOptions{DefaultResolution: sched.ConflictResolution}
+	}
+	sched.Status = StatusRunning
+	s.saveLocked()
+	s.mu.Unlock()
+
+	fmt.Printf("⏰ Executando agendamento %s: %s (%s)\n", sched.displayName(), action, sessionName)
+	startedAt := s.now()
+	result, err := s.execute(ctx, sessionName, folder, opts, s.authorizer(owner))
+	run := newRun(action, startedAt, result, err)
+	s.logHistory(sched, sessionName, run, time.Since(startedAt))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Agendamento pode ter sido removido durante a execução
+	if _, ok := s.schedules[id]; !ok {
+		return
+	}
+	sched.LastRun = run
+
+	if rollback {
+		sched.PendingRollbackAt = nil
+		sched.RollbackSession = ""
+		s.advance(sched, s.now(), finalStatus(run))
+		s.saveLocked()
+		return
+	}
+
+	// Programar rollback automático se configurado e algo foi aplicado
+	if rollbackAt := sched.rollbackTime(startedAt); rollbackAt != nil && run.RollbackSession != "" {
+		sched.PendingRollbackAt = rollbackAt
+		sched.RollbackSession = run.RollbackSession
+		sched.Status = StatusPendingRollback
+		if sched.Cron != "" {
+			sched.NextRunAt = s.nextCronRun(sched, s.now())
+		} else {
+			sched.NextRunAt = nil
+		}
+		s.saveLocked()
+		return
+	}
+
+	s.advance(sched, s.now(), finalStatus(run))
+	s.saveLocked()
+}
+
+// authorizer reautoriza a sessão com as permissões atuais do owner. Agendamentos sem owner
+// (criados antes da reautorização) são recusados.
+func (s *Scheduler) authorizer(owner *Owner) func(*models.Session) error {
+	return func(sess *models.Session) error {
+		if owner == nil {
+			return fmt.Errorf("schedule has no owner: update it to authorize session %s", sess.Name)
+		}
+		if s.authz == nil {
+			return fmt.Errorf("no authorizer configured for session %s", sess.Name)
+		}
+		return s.authz.AuthorizeSession(*owner, sess)
+	}
+}
+
+// advance calcula a próxima execução (cron) ou encerra o agendamento (execução única)
+func (s *Scheduler) advance(sched *Schedule, now time.Time, onceStatus string) {
+	if sched.Cron != "" {
+		sched.NextRunAt = s.nextCronRun(sched, now)
+		sched.Status = StatusScheduled
+		return
+	}
+	sched.NextRunAt = nil
+	sched.Status = onceStatus
+}
+
+func (s *Scheduler) nextCronRun(sched *Schedule, after time.Time) *time.Time {
+	spec, loc, err := parseCron(sched.Cron, sched.Timezone)
+	if err != nil {
+		return nil
+	}
+	next := spec.Next(after.In(loc))
+	return &next
+}
+
+func newRun(action string, startedAt time.Time, result *session.Result, err error) *Run {
+	run := &Run{Action: action, StartedAt: startedAt, Status: history.StatusSuccess}
+	if result != nil {
+		run.Applied = result.Applied
+		run.Failed = result.Failed
+		run.RollbackSession = result.RollbackSession
+		if resultErr := result.Err(); resultErr != nil {
+			err = resultErr
+		}
+	}
+	if err != nil {
+		run.Error = err.Error()
+		run.Status = history.StatusFailed
+		if run.Applied > 0 {
+			run.Status = history.StatusPartial
+		}
+	}
+	return run
+}
+
+func finalStatus(run *Run) string {
+	if run.Status == history.StatusSuccess {
+		return StatusCompleted
+	}
+	return StatusFailed
+}
+
+func (s *Scheduler) logHistory(sched *Schedule, sessionName string, run *Run, duration time.Duration) {
+	if s.history == nil {
+		return
+	}
+
+	s.history.Log(history.HistoryEntry{
+		Action:   run.Action,
+		Resource: fmt.Sprintf("schedule:%s", sched.ID),
+		Before: map[string]interface{}{
+			"schedule": sched.displayName(),
+			"folder":   sched.Folder,
+		},
+		After: map[string]interface{}{
+			"applied":          run.Applied,
+			"failed":           run.Failed,
+			"rollback_session": run.RollbackSession,
+		},
+		Status:      run.Status,
+		ErrorMsg:    run.Error,
+		Duration:    duration.Milliseconds(),
+		SessionName: sessionName,
+		User:        sched.historyUser(),
+	})
+}
+
+// historyUser identifica execuções agendadas no histórico (scheduler:<quem criou>)
+func (s *Schedule) historyUser() string {
+	if s.CreatedBy == "" {
+		return "scheduler"
+	}
+	return "scheduler:" + s.CreatedBy
+}
+
+// List retorna todos os agendamentos ordenados pela próxima execução
+func (s *Scheduler) List() []Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]Schedule, 0, len(s.schedules))
+	for _, sched := range s.schedules {
+		list = append(list, *sched)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i].nextEvent(), list[j].nextEvent()
+		switch {
+		case a == nil:
+			return false
+		case b == nil:
+			return true
+		}
+		return a.Before(*b)
+	})
+	return list
+}
+
+// Get retorna um agendamento pelo ID
+func (s *Scheduler) Get(id string) (Schedule, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sched, ok := s.schedules[id]
+	if !ok {
+		return Schedule{}, false
+	}
+	return *sched, true
+}
+
+// Create valida e adiciona um agendamento
+func (s *Scheduler) Create(sched Schedule) (Schedule, error) {
+	now := s.now()
+	sched.ID = uuid.New().String()
+	sched.CreatedAt = now
+	sched.UpdatedAt = now
+	sched.LastRun = nil
+	sched.PendingRollbackAt = nil
+	sched.RollbackSession = ""
+
+	if err := s.prepare(&sched, now); err != nil {
+		return Schedule{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.schedules[sched.ID] = &sched
+	return sched, s.saveLocked()
+}
+
+// Update substitui a configuração de um agendamento (o estado de execução é preservado)
+func (s *Scheduler) Update(id string, update Schedule) (Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.schedules[id]
+	if !ok {
+		return Schedule{}, ErrNotFound
+	}
+	if current.Status == StatusRunning {
+		return Schedule{}, ErrRunning
+	}
+
+	now := s.now()
+	update.ID = id
+	update.CreatedAt = current.CreatedAt
+	update.CreatedBy = current.CreatedBy
+	update.UpdatedAt = now
+	update.LastRun = current.LastRun
+	update.PendingRollbackAt = current.PendingRollbackAt
+	update.RollbackSession = current.RollbackSession
+	update.Status = current.Status
+
+	if err := s.prepare(&update, now); err != nil {
+		return Schedule{}, err
+	}
+	if update.PendingRollbackAt != nil {
+		update.Status = StatusPendingRollback
+	}
+
+	s.schedules[id] = &update
+	return update, s.saveLocked()
+}
+
+// Delete remove um agendamento
+func (s *Scheduler) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.schedules[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.schedules, id)
+	return s.saveLocked()
+}
+
+// HasPending indica se há agendamentos habilitados aguardando execução ou rollback
+func (s *Scheduler) HasPending() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sched := range s.schedules {
+		if sched.Enabled && sched.nextEvent() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Erros retornados pelo scheduler
+var (
+	ErrNotFound = errors.New("schedule not found")
+	ErrRunning  = errors.New("schedule is running")
+)
+
+// prepare valida um agendamento e calcula a próxima execução
+func (s *Scheduler) prepare(sched *Schedule, now time.Time) error {
+	sched.SessionName = strings.TrimSpace(sched.SessionName)
+	sched.Cron = strings.TrimSpace(sched.Cron)
+	if sched.SessionName == "" {
+		return fmt.Errorf("session_name is required")
+	}
+	if sched.Folder != "" && !validFolder(sched.Folder) {
+		return fmt.Errorf("invalid folder: %s", sched.Folder)
+	}
+	if (sched.RunAt == nil) == (sched.Cron == "") {
+		return fmt.Errorf("exactly one of run_at or cron must be set")
+	}
+	if sched.ConflictResolution != "" {
+		if _, err := session.ParseConflictResolution(string(sched.ConflictResolution)); err != nil {
+			return err
+		}
+	}
+	if sched.RollbackAt != nil && sched.RollbackAfter != "" {
+		return fmt.Errorf("only one of rollback_at or rollback_after can be set")
+	}
+	if sched.RollbackAfter != "" {
+		after, err := time.ParseDuration(sched.RollbackAfter)
+		if err != nil || after <= 0 {
+			return fmt.Errorf("invalid rollback_after %q: use a positive duration like 30m or 17h", sched.RollbackAfter)
+		}
+	}
+
+	if sched.Cron != "" {
+		if sched.RollbackAt != nil {
+			return fmt.Errorf("rollback_at cannot be used with cron, use rollback_after")
+		}
+		if _, _, err := parseCron(sched.Cron, sched.Timezone); err != nil {
+			return err
+		}
+		sched.RunAt = nil
+		sched.NextRunAt = s.nextCronRun(sched, now)
+	} else {
+		if !sched.RunAt.After(now) && sched.LastRun == nil {
+			return fmt.Errorf("run_at must be in the future")
+		}
+		if sched.RollbackAt != nil && !sched.RollbackAt.After(*sched.RunAt) {
+			return fmt.Errorf("rollback_at must be after run_at")
+		}
+		runAt := *sched.RunAt
+		sched.NextRunAt = &runAt
+		if sched.LastRun != nil && !runAt.After(now) {
+			sched.NextRunAt = nil
+		}
+	}
+
+	switch {
+	case !sched.Enabled:
+		sched.Status = StatusDisabled
+	case sched.NextRunAt != nil:
+		sched.Status = StatusScheduled
+	case sched.Status == "" || sched.Status == StatusDisabled:
+		sched.Status = StatusCompleted
+	}
+	return nil
+}
+
+// rollbackTime calcula o horário do rollback automático de uma execução iniciada em startedAt
+func (sched *Schedule) rollbackTime(startedAt time.Time) *time.Time {
+	if sched.RollbackAt != nil {
+		at := *sched.RollbackAt
+		if at.Before(startedAt) {
+			at = startedAt
+		}
+		return &at
+	}
+	if sched.RollbackAfter != "" {
+		if after, err := time.ParseDuration(sched.RollbackAfter); err == nil {
+			at := startedAt.Add(after)
+			return &at
+		}
+	}
+	return nil
+}
+
+// nextEvent retorna o próximo horário em que o agendamento fará algo (aplicação ou rollback)
+func (sched *Schedule) nextEvent() *time.Time {
+	if sched.PendingRollbackAt != nil && (sched.NextRunAt == nil || sched.PendingRollbackAt.Before(*sched.NextRunAt)) {
+		return sched.PendingRollbackAt
+	}
+	return sched.NextRunAt
+}
+
+func (sched *Schedule) displayName() string {
+	if sched.Name != "" {
+		return sched.Name
+	}
+	return sched.SessionName
+}
+
+func parseCron(expr, timezone string) (cron.Schedule, *time.Location, error) {
+	loc := time.Local
+	if timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(timezone); err != nil {
+			return nil, nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+		}
+	}
+	spec, err := cron.ParseStandard(expr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	return spec, loc, nil
+}
+
+func validFolder(folder string) bool {
+	switch session.SessionFolder(folder) {
+	case session.FolderHPAUpscale, session.FolderHPADownscale, session.FolderNodeUpscale,
+		session.FolderNodeDownscale, session.FolderRollback:
+		return true
+	}
+	return false
+}
+
+// load carrega os agendamentos do disco. Agendamentos interrompidos durante a execução
+// (servidor parou no meio) são marcados como falhos para não serem reaplicados.
+func (s *Scheduler) load() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read schedules: %w", err)
+	}
+
+	var schedules []*Schedule
+	if err := json.Unmarshal(data, &schedules); err != nil {
+		return fmt.Errorf("failed to parse schedules: %w", err)
+	}
+
+	for _, sched := range schedules {
+		if sched.Status == StatusRunning {
+			sched.LastRun = &Run{Action: history.ActionScheduledApply, StartedAt: sched.UpdatedAt, Status: history.StatusFailed,
+				Error: "interrupted: server stopped during execution"}
+			s.advance(sched, s.now(), StatusFailed)
+		}
+		s.schedules[sched.ID] = sched
+	}
+	return nil
+}
+
+// saveLocked persiste os agendamentos (chamar com s.mu travado)
+func (s *Scheduler) saveLocked() error {
+	list := make([]*Schedule, 0, len(s.schedules))
+	for _, sched := range s.schedules {
+		list = append(list, sched)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal schedules: %w", err)
+	}
+
+	// Só o usuário do servidor lê os agendamentos; o .tmp de uma gravação interrompida
+	// manteria a permissão antiga
+	tmp := s.path + ".tmp"
+	os.Remove(tmp)
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write schedules: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write schedules: %w", err)
+	}
+	return nil
+}
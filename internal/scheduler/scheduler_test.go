@@ -0,0 +1,214 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s-hpa-manager/internal/history"
+	"k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/session"
+)
+
+// execCall registra uma chamada ao Executor
+type execCall struct {
+	session string
+	folder  session.SessionFolder
+}
+
+// fakeAuthorizer nega os usuários revogados
+type fakeAuthorizer struct {
+	revoked map[string]bool
+}
+
+func (a *fakeAuthorizer) AuthorizeSession(owner Owner, sess *models.Session) error {
+	if a.revoked[owner.Username] {
+		return fmt.Errorf("user %s has no role bindings", owner.Username)
+	}
+	return nil
+}
+
+func newTestScheduler(t *testing.T, dir string, now *time.Time, fail map[string]error) (*Scheduler, *[]execCall, *history.HistoryTracker) {
+	return newTestSchedulerWithAuthorizer(t, dir, now, fail, &fakeAuthorizer{})
+}
+
+func newTestSchedulerWithAuthorizer(t *testing.T, dir string, now *time.Time, fail map[string]error, authz Authorizer) (*Scheduler, *[]execCall, *history.HistoryTracker) {
+	t.Helper()
+
+	ht, err := history.NewHistoryTracker(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewHistoryTracker() erro inesperado: %v", err)
+	}
+
+	var calls []execCall
+	execute := func(ctx context.Context, name string, folder session.SessionFolder, opts session.ApplyOptions, authorize func(*models.Session) error) (*session.Result, error) {
+		if err := authorize(&models.Session{Name: name}); err != nil {
+			return nil, err
+		}
+		calls = append(calls, execCall{session: name, folder: folder})
+		if err := fail[name]; err != nil {
+			return nil, err
+		}
+		return &session.Result{Session: name, Applied: 2, RollbackSession: "Rollback_" + name}, nil
+	}
+
+	s, err := New(dir, execute, authz, ht)
+	if err != nil {
+		t.Fatalf("New() erro inesperado: %v", err)
+	}
+	s.now = func() time.Time { return *now }
+	return s, &calls, ht
+}
+
+// TestSchedulerRunAtWithRollback valida execução única com rollback automático e persistência
+func TestSchedulerRunAtWithRollback(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 11, 28, 5, 0, 0, 0, time.UTC)
+	s, calls, ht := newTestScheduler(t, dir, &now, nil)
+
+	runAt := now.Add(time.Hour)
+	rollbackAt := runAt.Add(17 * time.Hour)
+	sched, err := s.Create(Schedule{
+		SessionName: "black-friday",
+		Folder:      string(session.FolderHPAUpscale),
+		RunAt:       &runAt,
+		RollbackAt:  &rollbackAt,
+		Enabled:     true,
+		Owner:       &Owner{Method: "oidc", Username: "ops@empresa.com", Groups: []string{"sre"}},
+	})
+	if err != nil {
+		t.Fatalf("Create() erro inesperado: %v", err)
+	}
+	if info, err := os.Stat(filepath.Join(dir, "schedules.json")); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("schedules.json deveria ter permissão 0600: %v %v", info.Mode(), err)
+	}
+	if sched.Status != StatusScheduled || !sched.NextRunAt.Equal(runAt) {
+		t.Fatalf("agendamento inesperado: %+v", sched)
+	}
+	if !s.HasPending() {
+		t.Error("HasPending() deveria ser true")
+	}
+
+	// Antes do horário nada é executado
+	s.Tick(context.Background())
+	if len(*calls) != 0 {
+		t.Fatalf("execução antecipada: %+v", *calls)
+	}
+
+	now = runAt.Add(10 * time.Second)
+	s.Tick(context.Background())
+	if len(*calls) != 1 || (*calls)[0] != (execCall{"black-friday", session.FolderHPAUpscale}) {
+		t.Fatalf("chamadas inesperadas: %+v", *calls)
+	}
+	got, _ := s.Get(sched.ID)
+	if got.Status != StatusPendingRollback || got.RollbackSession != "Rollback_black-friday" || !got.PendingRollbackAt.Equal(rollbackAt) {
+		t.Fatalf("rollback não programado: %+v", got)
+	}
+
+	// Recarregar do disco preserva o estado
+	reloaded, _, _ := newTestScheduler(t, dir, &now, nil)
+	if got, ok := reloaded.Get(sched.ID); !ok || got.Status != StatusPendingRollback || got.Owner == nil || got.Owner.Username != "ops@empresa.com" {
+		t.Fatalf("estado não persistido: %+v", got)
+	}
+
+	now = rollbackAt.Add(time.Second)
+	s.Tick(context.Background())
+	if len(*calls) != 2 || (*calls)[1] != (execCall{"Rollback_black-friday", session.FolderRollback}) {
+		t.Fatalf("rollback não executado: %+v", *calls)
+	}
+	got, _ = s.Get(sched.ID)
+	if got.Status != StatusCompleted || got.NextRunAt != nil || got.PendingRollbackAt != nil {
+		t.Errorf("agendamento deveria estar concluído: %+v", got)
+	}
+	if s.HasPending() {
+		t.Error("HasPending() deveria ser false")
+	}
+
+	entries := ht.GetFiltered(history.HistoryFilter{SessionName: "black-friday"})
+	if len(entries) != 1 || entries[0].Action != history.ActionScheduledApply || entries[0].Status != history.StatusSuccess {
+		t.Errorf("histórico inesperado: %+v", entries)
+	}
+}
+
+// TestSchedulerCron valida o cálculo da próxima execução, falhas e horários perdidos
+func TestSchedulerCron(t *testing.T) {
+	now := time.Date(2025, 11, 28, 5, 0, 0, 0, time.UTC)
+	s, calls, _ := newTestScheduler(t, t.TempDir(), &now, map[string]error{"nightly": errors.New("cluster unreachable")})
+
+	sched, err := s.Create(Schedule{SessionName: "nightly", Cron: "0 23 * * *", Timezone: "UTC", Enabled: true, Owner: &Owner{Username: "ops@empresa.com"}})
+	if err != nil {
+		t.Fatalf("Create() erro inesperado: %v", err)
+	}
+	if want := time.Date(2025, 11, 28, 23, 0, 0, 0, time.UTC); !sched.NextRunAt.Equal(want) {
+		t.Fatalf("NextRunAt = %v, esperado %v", sched.NextRunAt, want)
+	}
+
+	now = *sched.NextRunAt
+	s.Tick(context.Background())
+	got, _ := s.Get(sched.ID)
+	if len(*calls) != 1 || got.LastRun == nil || got.LastRun.Status != history.StatusFailed || got.Status != StatusScheduled {
+		t.Fatalf("falha não registrada: %+v", got)
+	}
+	if want := time.Date(2025, 11, 29, 23, 0, 0, 0, time.UTC); !got.NextRunAt.Equal(want) {
+		t.Errorf("próxima execução = %v, esperado %v", got.NextRunAt, want)
+	}
+
+	// Servidor fora do ar além da tolerância: execução perdida não é aplicada
+	now = got.NextRunAt.Add(2 * time.Hour)
+	s.Tick(context.Background())
+	got, _ = s.Get(sched.ID)
+	if len(*calls) != 1 || got.LastRun.Error == "" {
+		t.Errorf("execução perdida não deveria aplicar: calls=%d last=%+v", len(*calls), got.LastRun)
+	}
+}
+
+// TestSchedulerReauthorizesOwner valida que a execução usa as permissões atuais do owner
+func TestSchedulerReauthorizesOwner(t *testing.T) {
+	now := time.Date(2025, 11, 28, 5, 0, 0, 0, time.UTC)
+	authz := &fakeAuthorizer{revoked: map[string]bool{}}
+	s, calls, _ := newTestSchedulerWithAuthorizer(t, t.TempDir(), &now, nil, authz)
+
+	runAt := now.Add(time.Hour)
+	revoked, _ := s.Create(Schedule{SessionName: "revoked", RunAt: &runAt, Enabled: true, Owner: &Owner{Username: "ex@empresa.com"}})
+	legacy, _ := s.Create(Schedule{SessionName: "legacy", RunAt: &runAt, Enabled: true})
+	authz.revoked["ex@empresa.com"] = true // removido do auth.yaml depois de agendar
+
+	now = runAt.Add(time.Second)
+	s.Tick(context.Background())
+	if len(*calls) != 0 {
+		t.Fatalf("sessões não autorizadas foram aplicadas: %+v", *calls)
+	}
+	for _, id := range []string{revoked.ID, legacy.ID} {
+		if got, _ := s.Get(id); got.Status != StatusFailed || got.LastRun == nil || got.LastRun.Error == "" {
+			t.Errorf("agendamento %s deveria falhar: %+v", got.SessionName, got)
+		}
+	}
+}
+
+// TestSchedulerValidation valida as regras de criação
+func TestSchedulerValidation(t *testing.T) {
+	now := time.Date(2025, 11, 28, 5, 0, 0, 0, time.UTC)
+	s, _, _ := newTestScheduler(t, t.TempDir(), &now, nil)
+
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	invalid := []Schedule{
+		{Cron: "0 6 * * *"}, // sem sessão
+		{SessionName: "s"},  // sem run_at/cron
+		{SessionName: "s", Cron: "0 6 * * *", RunAt: &future},
+		{SessionName: "s", RunAt: &past},
+		{SessionName: "s", Cron: "not a cron"},
+		{SessionName: "s", Cron: "0 6 * * *", RollbackAt: &future},
+		{SessionName: "s", RunAt: &future, RollbackAfter: "-1h"},
+		{SessionName: "s", RunAt: &future, Folder: "Unknown"},
+	}
+	for i, sched := range invalid {
+		if _, err := s.Create(sched); err == nil {
+			t.Errorf("caso %d: esperado erro de validação", i)
+		}
+	}
+}
@@ -0,0 +1,1018 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s-hpa-manager/internal/audit"
+	"k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/nodepool"
+)
+
+// KubeClient operações de cluster usadas pelo Applier (implementada por *kubernetes.Client)
+type KubeClient interface {
+	GetHPA(ctx context.Context, namespace, name string) (models.HPA, error)
+	UpdateHPA(ctx context.Context, hpa models.HPA) error
+	DryRunUpdateHPA(ctx context.Context, hpa models.HPA) error
+	ApplyResourceChanges(resource *models.ClusterResource) error
+	DryRunResourceChanges(resource *models.ClusterResource) error
+}
+
+// ClientFactory retorna o client Kubernetes de um cluster (nome do contexto kubeconfig)
+type ClientFactory func(cluster string) (KubeClient, error)
+
+// ItemKind tipo de item de uma sessão
+type ItemKind string
+
+const (
+	ItemHPA      ItemKind = "hpa"
+	ItemNodePool ItemKind = "node_pool"
+	ItemResource ItemKind = "resource"
+)
+
+// ItemStatus estado de um item no plano ou na aplicação
+type ItemStatus string
+
+const (
+	StatusPlanned        ItemStatus = "planned"
+	StatusInvalid        ItemStatus = "invalid"
+	StatusSkipped        ItemStatus = "skipped"
+	StatusApplied        ItemStatus = "applied"
+	StatusFailed         ItemStatus = "failed"
+	StatusRolledBack     ItemStatus = "rolled_back"
+	StatusRollbackFailed ItemStatus = "rollback_failed"
+	StatusConflict       ItemStatus = "conflict" // estado atual divergiu e não há decisão (skip/force/rebase)
+)
+
+// ItemResult resultado de planejamento/aplicação de um item da sessão
+type ItemResult struct {
+	Kind      ItemKind   `json:"kind"`
+	Cluster   string     `json:"cluster"`
+	Namespace string     `json:"namespace,omitempty"`
+	Name      string     `json:"name"`
+	Status    ItemStatus `json:"status"`
+	Changes   []string   `json:"changes,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	Conflict  *Conflict  `json:"conflict,omitempty"`
+
+	before, after interface{} // valores aplicados, para o audit log
+}
+
+// String retorna uma identificação legível do item
+func (r ItemResult) String() string {
+	if r.Namespace != "" {
+		return fmt.Sprintf("%s %s/%s/%s", r.Kind, r.Cluster, r.Namespace, r.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", r.Kind, r.Cluster, r.Name)
+}
+
+// Plan resultado do dry-run de uma sessão
+type Plan struct {
+	Session   string       `json:"session"`
+	Items     []ItemResult `json:"items"`
+	Valid     bool         `json:"valid"`
+	Conflicts int          `json:"conflicts"` // itens alterados no cluster desde que a sessão foi salva
+}
+
+// ApplyOptions controla a aplicação de uma sessão
+type ApplyOptions struct {
+	// RollbackOnFailure interrompe no primeiro erro e desfaz os itens já aplicados
+	RollbackOnFailure bool
+	// DeferRollouts não executa rollouts (o chamador dispara e acompanha os rollouts)
+	DeferRollouts bool
+	// Resolutions decisão por item (chave ItemResult.Key) para itens cujo estado atual divergiu
+	// de OriginalValues. Sem decisão para algum conflito, nada é aplicado.
+	Resolutions map[string]ConflictResolution
+	// DefaultResolution decisão para conflitos sem entrada em Resolutions (vazio = exigir decisão)
+	DefaultResolution ConflictResolution
+}
+
+// Result resultado da aplicação (ou rollback) de uma sessão
+type Result struct {
+	Session         string       `json:"session"`
+	Items           []ItemResult `json:"items"`
+	Applied         int          `json:"applied"`
+	Failed          int          `json:"failed"`
+	Conflicts       int          `json:"conflicts"` // conflitos sem decisão (aplicação não executada)
+	RolledBack      bool         `json:"rolled_back"`
+	RollbackSession string       `json:"rollback_session,omitempty"`
+	StartedAt       time.Time    `json:"started_at"`
+	Duration        string       `json:"duration"`
+}
+
+// Err retorna um erro resumindo as falhas (nil se todos os itens foram aplicados)
+func (r *Result) Err() error {
+	if r.Conflicts > 0 {
+		return fmt.Errorf("%d items changed in the cluster since the session was saved; choose skip, force or rebase for each", r.Conflicts)
+	}
+	if r.Failed == 0 {
+		return nil
+	}
+	var messages []string
+	for _, item := range r.Items {
+		if item.Status == StatusFailed || item.Status == StatusRollbackFailed {
+			messages = append(messages, fmt.Sprintf("%s: %s", item.String(), item.Error))
+		}
+	}
+	return fmt.Errorf("%d of %d items failed: %s", r.Failed, len(r.Items), strings.Join(messages, "; "))
+}
+
+// ApplierOptions dependências do Applier
+type ApplierOptions struct {
+	// Clients retorna o client Kubernetes de cada cluster (obrigatório para HPAs e recursos)
+	Clients ClientFactory
+	// NodePools provedor de node pools (obrigatório para sessões com node pools)
+	NodePools nodepool.NodePoolProvider
+	// ResolveCluster converte uma mudança de node pool em ClusterRef (padrão: AKS com RG/subscription da mudança)
+	ResolveCluster func(change *models.NodePoolChange) nodepool.ClusterRef
+	// Manager usado para salvar a sessão de rollback gerada (opcional)
+	Manager *Manager
+	// Progress recebe o resultado de cada item assim que processado (opcional)
+	Progress func(ItemResult)
+	// Audit registra cada item aplicado, desfeito ou com falha no audit log (opcional)
+	Audit *audit.Recorder
+}
+
+// Applier planeja, aplica e desfaz sessões
+type Applier struct {
+	opts ApplierOptions
+	now  func() time.Time
+}
+
+// NewApplier cria um novo Applier
+func NewApplier(opts ApplierOptions) *Applier {
+	if opts.ResolveCluster == nil {
+		opts.ResolveCluster = defaultClusterRef
+	}
+	return &Applier{opts: opts, now: time.Now}
+}
+
+func defaultClusterRef(change *models.NodePoolChange) nodepool.ClusterRef {
+	return nodepool.ClusterRef{
+		Provider:      nodepool.ProviderAKS,
+		Name:          strings.TrimSuffix(change.Cluster, "-admin"),
+		ResourceGroup: change.ResourceGroup,
+		Subscription:  change.Subscription,
+	}
+}
+
+// step item da sessão na ordem de aplicação
+type step struct {
+	kind  ItemKind
+	index int
+}
+
+// orderedSteps define a ordem de aplicação:
+// 1. node pools que aumentam capacidade (antes dos HPAs para os pods terem onde subir)
+// 2. HPAs
+// 3. recursos de workloads
+// 4. node pools que reduzem capacidade (depois dos HPAs reduzirem os pods)
+// Dentro de cada grupo de node pools, SequenceOrder é respeitado (0 = sem ordem, vai por último).
+func orderedSteps(session *models.Session) []step {
+	var scaleUp, scaleDown []int
+	for i := range session.NodePoolChanges {
+		if isNodePoolScaleDown(&session.NodePoolChanges[i]) {
+			scaleDown = append(scaleDown, i)
+		} else {
+			scaleUp = append(scaleUp, i)
+		}
+	}
+
+	bySequence := func(indexes []int) {
+		sort.SliceStable(indexes, func(a, b int) bool {
+			return sequenceKey(session.NodePoolChanges[indexes[a]].SequenceOrder) <
+				sequenceKey(session.NodePoolChanges[indexes[b]].SequenceOrder)
+		})
+	}
+	bySequence(scaleUp)
+	bySequence(scaleDown)
+
+	var steps []step
+	for _, i := range scaleUp {
+		steps = append(steps, step{ItemNodePool, i})
+	}
+	for i := range session.Changes {
+		steps = append(steps, step{ItemHPA, i})
+	}
+	for i := range session.ResourceChanges {
+		steps = append(steps, step{ItemResource, i})
+	}
+	for _, i := range scaleDown {
+		steps = append(steps, step{ItemNodePool, i})
+	}
+	return steps
+}
+
+func sequenceKey(order int) int {
+	if order <= 0 {
+		return int(^uint(0) >> 1)
+	}
+	return order
+}
+
+// isNodePoolScaleDown indica se a mudança reduz a capacidade do node pool
+func isNodePoolScaleDown(change *models.NodePoolChange) bool {
+	original, target := change.OriginalValues, change.NewValues
+	if target.AutoscalingEnabled {
+		return target.MaxNodeCount < original.MaxNodeCount ||
+			(!original.AutoscalingEnabled && target.MaxNodeCount < original.NodeCount)
+	}
+	if original.AutoscalingEnabled {
+		return target.NodeCount < original.MaxNodeCount
+	}
+	return target.NodeCount < original.NodeCount
+}
+
+// Plan executa um dry-run da sessão: server-side dry-run para HPAs e workloads e
+// validação contra o estado atual para node pools. Nada é alterado.
+func (a *Applier) Plan(ctx context.Context, session *models.Session) *Plan {
+	plan := &Plan{Session: session.Name, Valid: true}
+
+	for _, s := range orderedSteps(session) {
+		var item ItemResult
+		switch s.kind {
+		case ItemHPA:
+			item = a.planHPA(ctx, &session.Changes[s.index])
+		case ItemResource:
+			item = a.planResource(&session.ResourceChanges[s.index])
+		case ItemNodePool:
+			item = a.planNodePool(ctx, &session.NodePoolChanges[s.index])
+		}
+		if item.Status == StatusInvalid {
+			plan.Valid = false
+		} else if conflict, _ := a.conflictFor(ctx, session, s); conflict != nil {
+			item.Conflict = conflict
+			plan.Conflicts++
+		}
+		plan.Items = append(plan.Items, item)
+	}
+
+	return plan
+}
+
+func (a *Applier) planHPA(ctx context.Context, change *models.HPAChange) ItemResult {
+	item := ItemResult{Kind: ItemHPA, Cluster: change.Cluster, Namespace: change.Namespace, Name: change.HPAName, Status: StatusPlanned}
+	if change.NewValues == nil {
+		return invalid(item, errors.New("new values not set"))
+	}
+	item.Changes = describeHPAChange(change.OriginalValues, change.NewValues)
+	if len(item.Changes) == 0 {
+		item.Status = StatusSkipped
+		return item
+	}
+
+	client, err := a.client(change.Cluster)
+	if err != nil {
+		return invalid(item, err)
+	}
+	if err := client.DryRunUpdateHPA(ctx, hpaFromValues(change, change.NewValues, false)); err != nil {
+		return invalid(item, err)
+	}
+	return item
+}
+
+func (a *Applier) planResource(change *models.ClusterResourceChange) ItemResult {
+	item := ItemResult{Kind: ItemResource, Cluster: change.Cluster, Namespace: change.Namespace, Name: change.ResourceName, Status: StatusPlanned}
+	if change.NewValues == nil {
+		return invalid(item, errors.New("new values not set"))
+	}
+	item.Changes = describeResourceChange(change.OriginalValues, change.NewValues)
+	if len(item.Changes) == 0 {
+		item.Status = StatusSkipped
+		return item
+	}
+
+	client, err := a.client(change.Cluster)
+	if err != nil {
+		return invalid(item, err)
+	}
+	if err := client.DryRunResourceChanges(resourceFromValues(change, change.NewValues)); err != nil {
+		return invalid(item, err)
+	}
+	return item
+}
+
+func (a *Applier) planNodePool(ctx context.Context, change *models.NodePoolChange) ItemResult {
+	item := ItemResult{Kind: ItemNodePool, Cluster: change.Cluster, Name: change.NodePoolName, Status: StatusPlanned}
+	if err := validateNodePoolValues(change.NewValues); err != nil {
+		return invalid(item, err)
+	}
+	if a.opts.NodePools == nil {
+		return invalid(item, errors.New("node pool provider not configured"))
+	}
+
+	current, err := a.opts.NodePools.Get(ctx, a.opts.ResolveCluster(change), change.NodePoolName)
+	if err != nil {
+		return invalid(item, err)
+	}
+	for _, s := range nodepool.Plan(nodepool.ValuesOf(current), change.NewValues) {
+		item.Changes = append(item.Changes, s.Description())
+	}
+	if len(item.Changes) == 0 {
+		item.Status = StatusSkipped
+	}
+	return item
+}
+
+func invalid(item ItemResult, err error) ItemResult {
+	item.Status = StatusInvalid
+	item.Error = err.Error()
+	return item
+}
+
+// validateNodePoolValues valida os valores desejados de um node pool
+func validateNodePoolValues(values models.NodePoolValues) error {
+	if values.AutoscalingEnabled {
+		if values.MinNodeCount < 0 || values.MaxNodeCount < 1 {
+			return fmt.Errorf("invalid autoscaler limits min=%d max=%d", values.MinNodeCount, values.MaxNodeCount)
+		}
+		if values.MinNodeCount > values.MaxNodeCount {
+			return fmt.Errorf("min node count (%d) cannot exceed max node count (%d)", values.MinNodeCount, values.MaxNodeCount)
+		}
+		return nil
+	}
+	if values.NodeCount < 0 {
+		return fmt.Errorf("node count cannot be negative: %d", values.NodeCount)
+	}
+	return nil
+}
+
+// Apply aplica a sessão na ordem definida por orderedSteps, atualizando os campos
+// Applied/AppliedAt/Error das mudanças. Com RollbackOnFailure, o primeiro erro interrompe
+// a aplicação e os itens já aplicados voltam para OriginalValues. Se algum item permanecer
+// aplicado e houver Manager, uma sessão de rollback é salva na pasta Rollback.
+func (a *Applier) Apply(ctx context.Context, session *models.Session, opts ApplyOptions) *Result {
+	result := &Result{Session: session.Name, StartedAt: a.now()}
+	var applied []step
+
+	// Itens alterados no cluster desde que a sessão foi salva exigem uma decisão
+	conflicts, unresolved := a.resolveConflicts(ctx, session, opts)
+	if unresolved {
+		a.reportUnresolved(session, conflicts, result)
+		return result
+	}
+
+	for _, s := range orderedSteps(session) {
+		var item ItemResult
+		conflict, hasConflict := conflicts[s]
+		if hasConflict && conflict.Resolution == ResolutionSkip {
+			item = ItemResult{Kind: conflict.Kind, Cluster: conflict.Cluster, Namespace: conflict.Namespace, Name: conflict.Name, Status: StatusSkipped}
+		} else {
+			item = a.applyStep(ctx, session, s, false, opts.DeferRollouts)
+		}
+		if hasConflict {
+			item.Conflict = &conflict
+		}
+		result.Items = append(result.Items, item)
+		a.report(item)
+		a.audit(session, item, audit.ActionApplySession)
+
+		switch item.Status {
+		case StatusApplied:
+			result.Applied++
+			applied = append(applied, s)
+		case StatusFailed:
+			result.Failed++
+		}
+
+		if item.Status == StatusFailed && opts.RollbackOnFailure {
+			break
+		}
+	}
+
+	if result.Failed > 0 && opts.RollbackOnFailure && len(applied) > 0 {
+		a.rollbackApplied(ctx, session, applied, result)
+		result.RolledBack = true
+	}
+
+	if result.Applied > 0 && !result.RolledBack && a.opts.Manager != nil {
+		if name, err := a.saveRollbackSession(session); err != nil {
+			fmt.Printf("⚠️  Warning: failed to save rollback session for %s: %v\n", session.Name, err)
+		} else {
+			result.RollbackSession = name
+		}
+	}
+
+	result.Duration = a.now().Sub(result.StartedAt).Round(time.Millisecond).String()
+	return result
+}
+
+// Rollback leva os itens aplicados (Applied) de volta para OriginalValues, na ordem da sessão
+// invertida (node pools que voltam a crescer primeiro, os que encolhem por último). Itens alterados no cluster depois da aplicação (estado atual diferente de
+// NewValues) são conflitos e seguem as decisões de opts (skip, force ou rebase), como em Apply;
+// sem decisão para algum conflito, nada é desfeito. RollbackOnFailure é ignorado.
+func (a *Applier) Rollback(ctx context.Context, session *models.Session, opts ApplyOptions) (*Result, error) {
+	if session.RollbackData != nil && !session.RollbackData.CanRollback {
+		return nil, fmt.Errorf("session %s cannot be rolled back", session.Name)
+	}
+
+	result := &Result{Session: session.Name, StartedAt: a.now()}
+
+	// Sessão invertida (NewValues → OriginalValues) só com os itens aplicados: o estado
+	// esperado no cluster passa a ser o que foi aplicado
+	rollback := BuildRollbackSession(session, result.StartedAt)
+	sources := appliedSources(session)
+
+	conflicts, unresolved := a.resolveConflicts(ctx, rollback, opts)
+	if unresolved {
+		a.reportUnresolved(rollback, conflicts, result)
+		return result, nil
+	}
+
+	for _, s := range orderedSteps(rollback) {
+		var item ItemResult
+		conflict, hasConflict := conflicts[s]
+		if hasConflict && conflict.Resolution == ResolutionSkip {
+			item = ItemResult{Kind: conflict.Kind, Cluster: conflict.Cluster, Namespace: conflict.Namespace, Name: conflict.Name, Status: StatusSkipped}
+		} else {
+			item = a.applyStep(ctx, rollback, s, false, true)
+		}
+		if hasConflict {
+			item.Conflict = &conflict
+		}
+
+		switch {
+		case item.Status == StatusFailed:
+			item.Status = StatusRollbackFailed
+			result.Failed++
+		case item.Status == StatusApplied || !hasConflict:
+			// Pulado sem conflito: NewValues igual ao original, nada a desfazer
+			item.Status = StatusRolledBack
+			markRolledBack(session, sources[s])
+			result.Applied++
+		}
+		result.Items = append(result.Items, item)
+		a.report(item)
+		a.audit(session, item, audit.ActionRollbackSession)
+	}
+
+	result.RolledBack = true
+	result.Duration = a.now().Sub(result.StartedAt).Round(time.Millisecond).String()
+	return result, nil
+}
+
+// reportUnresolved preenche o resultado com os conflitos sem decisão (nada é aplicado)
+func (a *Applier) reportUnresolved(session *models.Session, conflicts map[step]Conflict, result *Result) {
+	for _, s := range orderedSteps(session) {
+		conflict, ok := conflicts[s]
+		if !ok {
+			continue
+		}
+		item := ItemResult{Kind: conflict.Kind, Cluster: conflict.Cluster, Namespace: conflict.Namespace, Name: conflict.Name, Status: StatusSkipped, Conflict: &conflict}
+		if conflict.Resolution == "" {
+			item.Status = StatusConflict
+			result.Conflicts++
+		}
+		result.Items = append(result.Items, item)
+	}
+	result.Duration = a.now().Sub(result.StartedAt).Round(time.Millisecond).String()
+}
+
+// appliedSources mapeia cada passo da sessão gerada por BuildRollbackSession para o item
+// de origem (mesma ordem e mesmos filtros de BuildRollbackSession)
+func appliedSources(session *models.Session) map[step]step {
+	sources := make(map[step]step)
+	n := 0
+	for i, change := range session.Changes {
+		if change.Applied && change.OriginalValues != nil && change.NewValues != nil {
+			sources[step{kind: ItemHPA, index: n}] = step{kind: ItemHPA, index: i}
+			n++
+		}
+	}
+	n = 0
+	for i, change := range session.NodePoolChanges {
+		if change.Applied {
+			sources[step{kind: ItemNodePool, index: n}] = step{kind: ItemNodePool, index: i}
+			n++
+		}
+	}
+	n = 0
+	for i, change := range session.ResourceChanges {
+		if change.Applied && change.OriginalValues != nil && change.NewValues != nil {
+			sources[step{kind: ItemResource, index: n}] = step{kind: ItemResource, index: i}
+			n++
+		}
+	}
+	return sources
+}
+
+// markRolledBack marca o item de origem como não aplicado
+func markRolledBack(session *models.Session, s step) {
+	switch s.kind {
+	case ItemHPA:
+		change := &session.Changes[s.index]
+		change.Applied, change.AppliedAt = false, nil
+	case ItemResource:
+		change := &session.ResourceChanges[s.index]
+		change.Applied, change.AppliedAt, change.Error = false, nil, ""
+	default:
+		change := &session.NodePoolChanges[s.index]
+		change.Applied, change.AppliedAt, change.Error = false, nil, ""
+		change.SequenceStatus = "pending"
+	}
+}
+
+// rollbackApplied desfaz os passos aplicados, do último para o primeiro
+func (a *Applier) rollbackApplied(ctx context.Context, session *models.Session, applied []step, result *Result) {
+	for i := len(applied) - 1; i >= 0; i-- {
+		item := a.applyStep(ctx, session, applied[i], true, true)
+		if item.Status == StatusApplied {
+			item.Status = StatusRolledBack
+			result.Applied--
+		} else {
+			item.Status = StatusRollbackFailed
+		}
+		a.report(item)
+		a.audit(session, item, audit.ActionRollbackSession)
+
+		for j := range result.Items {
+			r := result.Items[j]
+			if r.Kind == item.Kind && r.Cluster == item.Cluster && r.Namespace == item.Namespace && r.Name == item.Name {
+				item.Changes = r.Changes
+				result.Items[j] = item
+				break
+			}
+		}
+	}
+}
+
+// applyStep aplica um item da sessão (NewValues, ou OriginalValues quando rollback=true)
+func (a *Applier) applyStep(ctx context.Context, session *models.Session, s step, rollback, deferRollouts bool) ItemResult {
+	switch s.kind {
+	case ItemHPA:
+		return a.applyHPA(ctx, &session.Changes[s.index], rollback, deferRollouts)
+	case ItemResource:
+		return a.applyResource(&session.ResourceChanges[s.index], rollback)
+	default:
+		return a.applyNodePool(ctx, &session.NodePoolChanges[s.index], rollback)
+	}
+}
+
+func (a *Applier) applyHPA(ctx context.Context, change *models.HPAChange, rollback, deferRollouts bool) ItemResult {
+	item := ItemResult{Kind: ItemHPA, Cluster: change.Cluster, Namespace: change.Namespace, Name: change.HPAName}
+	from, to := change.OriginalValues, change.NewValues
+	if rollback {
+		if from == nil {
+			return failed(item, errors.New("original values not captured"))
+		}
+		from, to = to, rollbackHPAValues(from, to)
+	}
+	if to == nil {
+		return failed(item, errors.New("target values not set"))
+	}
+	item.Changes = describeHPAChange(from, to)
+	item.before, item.after = from, to
+	if len(item.Changes) == 0 && !rollback {
+		item.Status = StatusSkipped
+		return item
+	}
+
+	client, err := a.client(change.Cluster)
+	if err != nil {
+		return failed(item, err)
+	}
+	performRollouts := !rollback && !deferRollouts
+	if err := client.UpdateHPA(ctx, hpaFromValues(change, to, performRollouts)); err != nil {
+		return failed(item, err)
+	}
+
+	now := a.now()
+	item.Status = StatusApplied
+	item.AppliedAt = &now
+	if !rollback {
+		change.Applied = true
+		change.AppliedAt = &now
+		if performRollouts {
+			change.RolloutTriggered = to.PerformRollout
+			change.DaemonSetRolloutTriggered = to.PerformDaemonSetRollout
+			change.StatefulSetRolloutTriggered = to.PerformStatefulSetRollout
+		}
+	} else {
+		change.Applied = false
+		change.AppliedAt = nil
+	}
+	return item
+}
+
+func (a *Applier) applyResource(change *models.ClusterResourceChange, rollback bool) ItemResult {
+	item := ItemResult{Kind: ItemResource, Cluster: change.Cluster, Namespace: change.Namespace, Name: change.ResourceName}
+	from, to := change.OriginalValues, change.NewValues
+	if rollback {
+		from, to = to, from
+	}
+	if to == nil {
+		return failed(item, errors.New("target values not set"))
+	}
+	item.Changes = describeResourceChange(from, to)
+	item.before, item.after = from, to
+	if len(item.Changes) == 0 && !rollback {
+		item.Status = StatusSkipped
+		return item
+	}
+
+	client, err := a.client(change.Cluster)
+	if err == nil {
+		err = client.ApplyResourceChanges(resourceFromValues(change, to))
+	}
+	if err != nil {
+		if !rollback {
+			change.Error = err.Error()
+		}
+		return failed(item, err)
+	}
+
+	now := a.now()
+	item.Status = StatusApplied
+	item.AppliedAt = &now
+	change.Applied = !rollback
+	change.AppliedAt = nil
+	change.Error = ""
+	if !rollback {
+		change.AppliedAt = &now
+	}
+	return item
+}
+
+func (a *Applier) applyNodePool(ctx context.Context, change *models.NodePoolChange, rollback bool) ItemResult {
+	item := ItemResult{Kind: ItemNodePool, Cluster: change.Cluster, Name: change.NodePoolName}
+	target := change.NewValues
+	item.before, item.after = change.OriginalValues, change.NewValues
+	if rollback {
+		target = change.OriginalValues
+		item.before, item.after = change.NewValues, change.OriginalValues
+	}
+
+	err := validateNodePoolValues(target)
+	if err == nil && a.opts.NodePools == nil {
+		err = errors.New("node pool provider not configured")
+	}
+	if err == nil {
+		if !rollback {
+			change.SequenceStatus = "executing"
+		}
+		err = nodepool.ApplyFromLive(ctx, a.opts.NodePools, a.opts.ResolveCluster(change), change.NodePoolName, target,
+			func(step, total int, description string) {
+				item.Changes = append(item.Changes, description)
+			})
+	}
+	if err != nil {
+		if !rollback {
+			change.Error = err.Error()
+			change.SequenceStatus = "failed"
+		}
+		return failed(item, err)
+	}
+
+	now := a.now()
+	item.Status = StatusApplied
+	item.AppliedAt = &now
+	change.Applied = !rollback
+	change.AppliedAt = nil
+	change.Error = ""
+	if rollback {
+		change.SequenceStatus = "pending"
+	} else {
+		change.AppliedAt = &now
+		change.SequenceStatus = "completed"
+	}
+	return item
+}
+
+func failed(item ItemResult, err error) ItemResult {
+	item.Status = StatusFailed
+	item.Error = err.Error()
+	return item
+}
+
+func (a *Applier) client(cluster string) (KubeClient, error) {
+	if a.opts.Clients == nil {
+		return nil, errors.New("kubernetes client factory not configured")
+	}
+	client, err := a.opts.Clients(cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client for cluster %s: %w", cluster, err)
+	}
+	return client, nil
+}
+
+func (a *Applier) report(item ItemResult) {
+	if a.opts.Progress != nil {
+		a.opts.Progress(item)
+	}
+}
+
+// audit registra no audit log os itens que chegaram ao cluster (ignora skipped/conflict)
+func (a *Applier) audit(session *models.Session, item ItemResult, action string) {
+	if a.opts.Audit == nil {
+		return
+	}
+	switch item.Status {
+	case StatusApplied, StatusFailed, StatusRolledBack, StatusRollbackFailed:
+	default:
+		return
+	}
+
+	resource := item.Name
+	if item.Namespace != "" {
+		resource = item.Namespace + "/" + item.Name
+	}
+	event := audit.Event{
+		Action:   action,
+		Cluster:  item.Cluster,
+		Resource: fmt.Sprintf("%s:%s", item.Kind, resource),
+		Before:   item.before,
+		After:    item.after,
+		Session:  session.Name,
+	}
+	if item.Error != "" {
+		event.Err = errors.New(item.Error)
+	}
+	a.opts.Audit.Record(event)
+}
+
+// saveRollbackSession salva na pasta Rollback uma sessão que desfaz os itens aplicados
+func (a *Applier) saveRollbackSession(session *models.Session) (string, error) {
+	rollback := BuildRollbackSession(session, a.now())
+	if len(rollback.Changes)+len(rollback.NodePoolChanges)+len(rollback.ResourceChanges) == 0 {
+		return "", nil
+	}
+	if err := a.opts.Manager.SaveSessionToFolder(rollback, FolderRollback); err != nil {
+		return "", err
+	}
+
+	session.RollbackData = &models.RollbackData{
+		OriginalStateCaptured:   true,
+		CanRollback:             true,
+		RollbackScriptGenerated: true,
+	}
+	return rollback.Name, nil
+}
+
+// BuildRollbackSession gera uma sessão que leva os itens aplicados de volta para OriginalValues
+// (NewValues e OriginalValues invertidos, sem rollouts)
+func BuildRollbackSession(session *models.Session, now time.Time) *models.Session {
+	rollback := &models.Session{
+		Name:         RollbackSessionName(session.Name, now),
+		CreatedAt:    now,
+		Description:  fmt.Sprintf("Rollback automático da sessão %s", session.Name),
+		TemplateUsed: "rollback",
+		RollbackData: &models.RollbackData{OriginalStateCaptured: true, CanRollback: true},
+	}
+
+	for _, change := range session.Changes {
+		if !change.Applied || change.OriginalValues == nil || change.NewValues == nil {
+			continue
+		}
+		rollback.Changes = append(rollback.Changes, models.HPAChange{
+			Cluster:        change.Cluster,
+			Namespace:      change.Namespace,
+			HPAName:        change.HPAName,
+			OriginalValues: cloneHPAValues(change.NewValues),
+			NewValues:      rollbackHPAValues(change.OriginalValues, change.NewValues),
+		})
+	}
+
+	for _, change := range session.NodePoolChanges {
+		if !change.Applied {
+			continue
+		}
+		rollback.NodePoolChanges = append(rollback.NodePoolChanges, models.NodePoolChange{
+			Cluster:        change.Cluster,
+			ResourceGroup:  change.ResourceGroup,
+			Subscription:   change.Subscription,
+			NodePoolName:   change.NodePoolName,
+			OriginalValues: change.NewValues,
+			NewValues:      change.OriginalValues,
+			SequenceOrder:  change.SequenceOrder,
+		})
+	}
+
+	for _, change := range session.ResourceChanges {
+		if !change.Applied || change.OriginalValues == nil || change.NewValues == nil {
+			continue
+		}
+		original, target := *change.NewValues, *change.OriginalValues
+		rollback.ResourceChanges = append(rollback.ResourceChanges, models.ClusterResourceChange{
+			Cluster:        change.Cluster,
+			Namespace:      change.Namespace,
+			ResourceName:   change.ResourceName,
+			WorkloadType:   change.WorkloadType,
+			Component:      change.Component,
+			OriginalValues: &original,
+			NewValues:      &target,
+		})
+	}
+
+	rollback.Metadata = GenerateMetadata(rollback)
+	return rollback
+}
+
+// RollbackSessionName gera o nome da sessão de rollback respeitando o limite de 50 caracteres
+func RollbackSessionName(name string, now time.Time) string {
+	return derivedSessionName("Rollback_", name, now)
+}
+
+// DerivedSessionName gera <prefix><name>_<timestamp> para sessões geradas a partir de outra origem
+// (ex: RightSizing_<cluster>_<timestamp>)
+func DerivedSessionName(prefix, name string, now time.Time) string {
+	return derivedSessionName(prefix, name, now)
+}
+
+// derivedSessionName gera <prefix><name>_<timestamp> com caracteres válidos e no máximo 50 caracteres
+func derivedSessionName(prefix, name string, now time.Time) string {
+	suffix := "_" + now.Format("020106-150405")
+	base := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' || r == '-' {
+			return r
+		}
+		return '_'
+	}, name)
+
+	maxBase := 50 - len(prefix) - len(suffix)
+	if len(base) > maxBase {
+		base = base[:maxBase]
+	}
+	return prefix + base + suffix
+}
+
+// GenerateMetadata gera os metadados de uma sessão considerando HPAs, node pools e recursos
+func GenerateMetadata(session *models.Session) *models.SessionMetadata {
+	clustersMap := make(map[string]bool)
+	namespacesMap := make(map[string]bool)
+	var clusters []string
+
+	addCluster := func(cluster string) {
+		if !clustersMap[cluster] {
+			clustersMap[cluster] = true
+			clusters = append(clusters, cluster)
+		}
+	}
+
+	for _, change := range session.Changes {
+		addCluster(change.Cluster)
+		namespacesMap[change.Cluster+"/"+change.Namespace] = true
+	}
+	for _, change := range session.NodePoolChanges {
+		addCluster(change.Cluster)
+	}
+	for _, change := range session.ResourceChanges {
+		addCluster(change.Cluster)
+		namespacesMap[change.Cluster+"/"+change.Namespace] = true
+	}
+
+	return &models.SessionMetadata{
+		ClustersAffected: clusters,
+		NamespacesCount:  len(namespacesMap),
+		HPACount:         len(session.Changes),
+		NodePoolCount:    len(session.NodePoolChanges),
+		ResourceCount:    len(session.ResourceChanges),
+		TotalChanges:     len(session.Changes) + len(session.NodePoolChanges) + len(session.ResourceChanges),
+	}
+}
+
+// hpaFromValues monta o HPA a ser enviado ao cluster a partir dos valores da sessão
+func hpaFromValues(change *models.HPAChange, values *models.HPAValues, performRollouts bool) models.HPA {
+	hpa := models.HPA{
+		Name:                change.HPAName,
+		Namespace:           change.Namespace,
+		Cluster:             change.Cluster,
+		MinReplicas:         values.MinReplicas,
+		MaxReplicas:         values.MaxReplicas,
+		TargetCPU:           values.TargetCPU,
+		TargetMemory:        values.TargetMemory,
+		Metrics:             models.CloneHPAMetrics(values.Metrics),
+		Behavior:            values.Behavior.Clone(),
+		DeploymentName:      values.DeploymentName,
+		TargetCPURequest:    values.CPURequest,
+		TargetCPULimit:      values.CPULimit,
+		TargetMemoryRequest: values.MemoryRequest,
+		TargetMemoryLimit:   values.MemoryLimit,
+	}
+	if performRollouts {
+		hpa.PerformRollout = values.PerformRollout
+		hpa.PerformDaemonSetRollout = values.PerformDaemonSetRollout
+		hpa.PerformStatefulSetRollout = values.PerformStatefulSetRollout
+	}
+	return hpa
+}
+
+// resourceFromValues monta o ClusterResource a ser enviado ao cluster a partir dos valores da sessão
+func resourceFromValues(change *models.ClusterResourceChange, values *models.ResourceValues) *models.ClusterResource {
+	resource := &models.ClusterResource{
+		Name:                change.ResourceName,
+		Namespace:           change.Namespace,
+		Component:           change.Component,
+		WorkloadType:        change.WorkloadType,
+		Cluster:             change.Cluster,
+		TargetCPURequest:    values.CPURequest,
+		TargetMemoryRequest: values.MemoryRequest,
+		TargetCPULimit:      values.CPULimit,
+		TargetMemoryLimit:   values.MemoryLimit,
+		StorageSize:         values.StorageSize,
+	}
+	if values.Replicas > 0 {
+		replicas := values.Replicas
+		resource.TargetReplicas = &replicas
+	}
+	return resource
+}
+
+// rollbackHPAValues retorna os valores que desfazem applied, sem rollouts.
+// Behavior ausente no original vira behavior vazio para remover o behavior aplicado.
+func rollbackHPAValues(original, applied *models.HPAValues) *models.HPAValues {
+	values := cloneHPAValues(original)
+	values.PerformRollout, values.PerformDaemonSetRollout, values.PerformStatefulSetRollout = false, false, false
+	if values.Behavior == nil && applied != nil && applied.Behavior != nil {
+		values.Behavior = &models.HPABehavior{}
+	}
+	return values
+}
+
+func cloneHPAValues(values *models.HPAValues) *models.HPAValues {
+	clone := *values
+	clone.Metrics = models.CloneHPAMetrics(values.Metrics)
+	clone.Behavior = values.Behavior.Clone()
+	return &clone
+}
+
+// describeHPAChange lista as diferenças entre os valores originais e novos de um HPA
+func describeHPAChange(original, target *models.HPAValues) []string {
+	if original == nil {
+		original = &models.HPAValues{}
+	}
+
+	var changes []string
+	add := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, fmt.Sprintf("%s: %s → %s", field, from, to))
+		}
+	}
+
+	add("min_replicas", int32PtrString(original.MinReplicas), int32PtrString(target.MinReplicas))
+	add("max_replicas", fmt.Sprint(original.MaxReplicas), fmt.Sprint(target.MaxReplicas))
+	add("target_cpu", int32PtrString(original.TargetCPU), int32PtrString(target.TargetCPU))
+	add("target_memory", int32PtrString(original.TargetMemory), int32PtrString(target.TargetMemory))
+	if target.Metrics != nil && !reflect.DeepEqual(original.Metrics, target.Metrics) {
+		changes = append(changes, fmt.Sprintf("metrics: %d → %d métricas", len(original.Metrics), len(target.Metrics)))
+	}
+	if target.Behavior != nil && !reflect.DeepEqual(normalizeBehavior(original.Behavior), normalizeBehavior(target.Behavior)) {
+		changes = append(changes, "behavior alterado")
+	}
+	if target.CPURequest != "" {
+		add("cpu_request", original.CPURequest, target.CPURequest)
+	}
+	if target.CPULimit != "" {
+		add("cpu_limit", original.CPULimit, target.CPULimit)
+	}
+	if target.MemoryRequest != "" {
+		add("memory_request", original.MemoryRequest, target.MemoryRequest)
+	}
+	if target.MemoryLimit != "" {
+		add("memory_limit", original.MemoryLimit, target.MemoryLimit)
+	}
+	return changes
+}
+
+// describeResourceChange lista as diferenças entre os valores originais e novos de um workload
+func describeResourceChange(original, target *models.ResourceValues) []string {
+	if original == nil {
+		original = &models.ResourceValues{}
+	}
+
+	var changes []string
+	add := func(field, from, to string) {
+		if to != "" && from != to {
+			changes = append(changes, fmt.Sprintf("%s: %s → %s", field, from, to))
+		}
+	}
+
+	add("cpu_request", original.CPURequest, target.CPURequest)
+	add("memory_request", original.MemoryRequest, target.MemoryRequest)
+	add("cpu_limit", original.CPULimit, target.CPULimit)
+	add("memory_limit", original.MemoryLimit, target.MemoryLimit)
+	if target.Replicas > 0 {
+		add("replicas", fmt.Sprint(original.Replicas), fmt.Sprint(target.Replicas))
+	}
+	return changes
+}
+
+func int32PtrString(value *int32) string {
+	if value == nil {
+		return "-"
+	}
+	return fmt.Sprint(*value)
+}
+
+// normalizeBehavior trata behavior vazio como ausente para comparação
+func normalizeBehavior(behavior *models.HPABehavior) *models.HPABehavior {
+	if behavior == nil || (behavior.ScaleUp == nil && behavior.ScaleDown == nil) {
+		return nil
+	}
+	return behavior
+}
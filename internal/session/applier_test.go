@@ -0,0 +1,283 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"k8s-hpa-manager/internal/audit"
+	"k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/nodepool"
+)
+
+// fakeKubeClient implementação em memória de KubeClient
+type fakeKubeClient struct {
+	hpas      map[string]models.HPA // namespace/nome -> HPA aplicado
+	resources map[string]models.ClusterResource
+	failHPA   map[string]error
+	dryRuns   int
+}
+
+func newFakeKubeClient() *fakeKubeClient {
+	return &fakeKubeClient{
+		hpas:      make(map[string]models.HPA),
+		resources: make(map[string]models.ClusterResource),
+		failHPA:   make(map[string]error),
+	}
+}
+
+func (f *fakeKubeClient) GetHPA(ctx context.Context, namespace, name string) (models.HPA, error) {
+	hpa, ok := f.hpas[namespace+"/"+name]
+	if !ok {
+		return models.HPA{}, errors.New("not found")
+	}
+	return hpa, nil
+}
+
+func (f *fakeKubeClient) UpdateHPA(ctx context.Context, hpa models.HPA) error {
+	if err := f.failHPA[hpa.Namespace+"/"+hpa.Name]; err != nil {
+		return err
+	}
+	f.hpas[hpa.Namespace+"/"+hpa.Name] = hpa
+	return nil
+}
+
+func (f *fakeKubeClient) DryRunUpdateHPA(ctx context.Context, hpa models.HPA) error {
+	f.dryRuns++
+	return f.failHPA[hpa.Namespace+"/"+hpa.Name]
+}
+
+func (f *fakeKubeClient) ApplyResourceChanges(resource *models.ClusterResource) error {
+	f.resources[resource.Namespace+"/"+resource.Name] = *resource
+	return nil
+}
+
+func (f *fakeKubeClient) DryRunResourceChanges(resource *models.ClusterResource) error {
+	f.dryRuns++
+	return nil
+}
+
+func int32Ptr(v int32) *int32 { return &v }
+
+func testSession() *models.Session {
+	return &models.Session{
+		Name: "upscale-test",
+		Changes: []models.HPAChange{
+			{
+				Cluster: "aks-prd-admin", Namespace: "api", HPAName: "web",
+				OriginalValues: &models.HPAValues{MinReplicas: int32Ptr(2), MaxReplicas: 10},
+				NewValues:      &models.HPAValues{MinReplicas: int32Ptr(4), MaxReplicas: 20, PerformRollout: true},
+			},
+			{
+				Cluster: "aks-prd-admin", Namespace: "api", HPAName: "worker",
+				OriginalValues: &models.HPAValues{MinReplicas: int32Ptr(1), MaxReplicas: 5},
+				NewValues:      &models.HPAValues{MinReplicas: int32Ptr(3), MaxReplicas: 8},
+			},
+		},
+		NodePoolChanges: []models.NodePoolChange{
+			{
+				Cluster: "aks-prd-admin", ResourceGroup: "rg-prd", Subscription: "sub", NodePoolName: "user",
+				OriginalValues: models.NodePoolValues{NodeCount: 3},
+				NewValues:      models.NodePoolValues{NodeCount: 6},
+			},
+		},
+	}
+}
+
+func newTestApplier(t *testing.T, client *fakeKubeClient) (*Applier, *nodepool.FakeProvider, *Manager) {
+	t.Helper()
+
+	manager, err := NewManagerWithDir(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManagerWithDir() erro inesperado: %v", err)
+	}
+
+	provider := nodepool.NewFakeProvider()
+	provider.AddNodePool(nodepool.ClusterRef{Name: "aks-prd", ResourceGroup: "rg-prd", Subscription: "sub"},
+		models.NodePool{Name: "user", NodeCount: 3})
+
+	applier := NewApplier(ApplierOptions{
+		Clients:   func(cluster string) (KubeClient, error) { return client, nil },
+		NodePools: provider,
+		Manager:   manager,
+	})
+	return applier, provider, manager
+}
+
+// TestApplierPlan valida o dry-run sem alterar o cluster
+func TestApplierPlan(t *testing.T) {
+	client := newFakeKubeClient()
+	client.failHPA["api/worker"] = errors.New("admission webhook denied")
+	applier, provider, _ := newTestApplier(t, client)
+
+	plan := applier.Plan(context.Background(), testSession())
+	if plan.Valid {
+		t.Error("esperado plano inválido")
+	}
+	if len(plan.Items) != 3 || plan.Items[0].Kind != ItemNodePool {
+		t.Fatalf("itens inesperados (node pool de upscale deve vir primeiro): %+v", plan.Items)
+	}
+	if plan.Items[0].Status != StatusPlanned || plan.Items[0].Changes[0] != "scale para 6 nodes" {
+		t.Errorf("node pool inesperado: %+v", plan.Items[0])
+	}
+	if plan.Items[2].Status != StatusInvalid || !strings.Contains(plan.Items[2].Error, "webhook") {
+		t.Errorf("HPA worker deveria ser inválido: %+v", plan.Items[2])
+	}
+	if len(client.hpas) != 0 || client.dryRuns != 2 {
+		t.Errorf("plano não deve aplicar nada: hpas=%d dryRuns=%d", len(client.hpas), client.dryRuns)
+	}
+	for _, call := range provider.Calls() {
+		if call.Op != "get" {
+			t.Errorf("plano não deve alterar node pools: %+v", call)
+		}
+	}
+}
+
+// TestApplierApply valida a aplicação e a sessão de rollback gerada
+func TestApplierApply(t *testing.T) {
+	client := newFakeKubeClient()
+	applier, _, manager := newTestApplier(t, client)
+	session := testSession()
+
+	var progress []ItemResult
+	applier.opts.Progress = func(item ItemResult) { progress = append(progress, item) }
+
+	result := applier.Apply(context.Background(), session, ApplyOptions{DeferRollouts: true})
+	if err := result.Err(); err != nil {
+		t.Fatalf("Apply() erro inesperado: %v", err)
+	}
+	if result.Applied != 3 || len(progress) != 3 {
+		t.Errorf("esperado 3 itens aplicados, obtido %d (progress=%d)", result.Applied, len(progress))
+	}
+	if hpa := client.hpas["api/web"]; *hpa.MinReplicas != 4 || hpa.PerformRollout {
+		t.Errorf("HPA web inesperado (rollout deveria ser adiado): %+v", hpa)
+	}
+	if !session.Changes[0].Applied || !session.NodePoolChanges[0].Applied || session.NodePoolChanges[0].SequenceStatus != "completed" {
+		t.Error("mudanças deveriam estar marcadas como aplicadas")
+	}
+	if session.RollbackData == nil || !session.RollbackData.CanRollback {
+		t.Error("RollbackData deveria indicar rollback disponível")
+	}
+
+	rollback, err := manager.LoadSessionFromFolder(result.RollbackSession, FolderRollback)
+	if err != nil {
+		t.Fatalf("sessão de rollback não salva: %v", err)
+	}
+	if len(rollback.Changes) != 2 || *rollback.Changes[0].NewValues.MinReplicas != 2 || rollback.Changes[0].NewValues.PerformRollout {
+		t.Errorf("HPAs do rollback inesperados: %+v", rollback.Changes)
+	}
+	if len(rollback.NodePoolChanges) != 1 || rollback.NodePoolChanges[0].NewValues.NodeCount != 3 {
+		t.Errorf("node pools do rollback inesperados: %+v", rollback.NodePoolChanges)
+	}
+	if rollback.Metadata.TotalChanges != 3 {
+		t.Errorf("metadata inesperado: %+v", rollback.Metadata)
+	}
+}
+
+// TestApplierRollbackOnFailure valida a interrupção e o rollback dos itens já aplicados
+func TestApplierRollbackOnFailure(t *testing.T) {
+	client := newFakeKubeClient()
+	client.failHPA["api/worker"] = errors.New("conflict")
+	applier, provider, manager := newTestApplier(t, client)
+	session := testSession()
+
+	auditLog, err := audit.Open(&audit.Config{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("audit.Open() erro inesperado: %v", err)
+	}
+	applier.opts.Audit = audit.NewRecorder(auditLog, audit.SourceCLI, "ops")
+
+	result := applier.Apply(context.Background(), session, ApplyOptions{RollbackOnFailure: true})
+	if result.Err() == nil || !result.RolledBack {
+		t.Fatalf("esperado falha com rollback: %+v", result)
+	}
+
+	statuses := map[string]ItemStatus{}
+	for _, item := range result.Items {
+		statuses[item.Name] = item.Status
+	}
+	if statuses["user"] != StatusRolledBack || statuses["web"] != StatusRolledBack || statuses["worker"] != StatusFailed {
+		t.Errorf("status inesperados: %+v", statuses)
+	}
+	if hpa := client.hpas["api/web"]; *hpa.MinReplicas != 2 || hpa.MaxReplicas != 10 {
+		t.Errorf("HPA web deveria voltar ao original: %+v", hpa)
+	}
+	pool, _ := provider.Get(context.Background(), nodepool.ClusterRef{Name: "aks-prd"}, "user")
+	if pool.NodeCount != 3 {
+		t.Errorf("node pool deveria voltar para 3 nodes, obtido %d", pool.NodeCount)
+	}
+	if session.Changes[0].Applied || result.RollbackSession != "" {
+		t.Error("após rollback nada deve permanecer aplicado nem gerar sessão de rollback")
+	}
+	if sessions, _ := manager.ListSessionsInFolder(FolderRollback); len(sessions) != 0 {
+		t.Errorf("nenhuma sessão de rollback esperada, obtido %d", len(sessions))
+	}
+
+	// Audit log: 3 aplicações (1 falha) + 2 rollbacks, com valores antes/depois
+	entries, _ := auditLog.Entries(audit.Filter{})
+	var actions []string
+	for i := len(entries) - 1; i >= 0; i-- {
+		actions = append(actions, entries[i].Action+" "+entries[i].Resource+" "+string(entries[i].Result))
+	}
+	want := "apply_session node_pool:user success, apply_session hpa:api/web success, apply_session hpa:api/worker failed, " +
+		"rollback_session hpa:api/web success, rollback_session node_pool:user success"
+	if strings.Join(actions, ", ") != want {
+		t.Errorf("audit inesperado:\n got %s\nwant %s", strings.Join(actions, ", "), want)
+	}
+	if rollbackWeb := entries[1]; !strings.Contains(string(rollbackWeb.After), `"min_replicas":2`) || rollbackWeb.Session != "upscale-test" {
+		t.Errorf("entrada de rollback inesperada: %+v", rollbackWeb)
+	}
+}
+
+// TestApplierRollback valida que só os itens aplicados voltam para OriginalValues
+func TestApplierRollback(t *testing.T) {
+	client := newFakeKubeClient()
+	applier, provider, _ := newTestApplier(t, client)
+	session := testSession()
+
+	if result := applier.Apply(context.Background(), session, ApplyOptions{DeferRollouts: true}); result.Err() != nil {
+		t.Fatalf("Apply() erro inesperado: %v", result.Err())
+	}
+
+	// worker não foi aplicado por esta sessão: o valor atual no cluster não pode ser revertido
+	session.Changes[1].Applied = false
+	client.hpas["api/worker"] = models.HPA{Namespace: "api", Name: "worker", MinReplicas: int32Ptr(7), MaxReplicas: 9}
+
+	result, err := applier.Rollback(context.Background(), session, ApplyOptions{})
+	if err != nil || result.Err() != nil {
+		t.Fatalf("Rollback() erro inesperado: %v %v", err, result.Err())
+	}
+	if result.Applied != 2 || len(result.Items) != 2 {
+		t.Fatalf("esperado rollback de 2 itens: %+v", result.Items)
+	}
+	if hpa := client.hpas["api/web"]; *hpa.MinReplicas != 2 || hpa.MaxReplicas != 10 {
+		t.Errorf("HPA web deveria voltar ao original: %+v", hpa)
+	}
+	if hpa := client.hpas["api/worker"]; *hpa.MinReplicas != 7 || hpa.MaxReplicas != 9 {
+		t.Errorf("HPA worker não aplicado não deveria ser alterado: %+v", hpa)
+	}
+	pool, _ := provider.Get(context.Background(), nodepool.ClusterRef{Name: "aks-prd"}, "user")
+	if pool.NodeCount != 3 {
+		t.Errorf("node pool deveria voltar para 3 nodes, obtido %d", pool.NodeCount)
+	}
+	if session.Changes[0].Applied || session.NodePoolChanges[0].Applied || session.NodePoolChanges[0].SequenceStatus != "pending" {
+		t.Error("itens desfeitos deveriam ser marcados como não aplicados")
+	}
+	// HPAs reduzem antes do node pool encolher
+	if result.Items[0].Kind != ItemHPA || result.Items[1].Kind != ItemNodePool {
+		t.Errorf("ordem de rollback inesperada: %+v", result.Items)
+	}
+}
+
+// TestRollbackSessionName valida o limite de tamanho e caracteres do nome gerado
+func TestRollbackSessionName(t *testing.T) {
+	name := RollbackSessionName(strings.Repeat("upscale.black-friday ", 5), testSession().CreatedAt)
+	if len(name) > 50 || !strings.HasPrefix(name, "Rollback_upscale_black-friday") {
+		t.Errorf("nome inesperado: %q", name)
+	}
+	manager, _ := NewManagerWithDir(t.TempDir())
+	if err := manager.validateSessionName(name); err != nil {
+		t.Errorf("nome gerado inválido: %v", err)
+	}
+}
@@ -0,0 +1,450 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"k8s-hpa-manager/internal/models"
+)
+
+// ConflictResolution decisão do usuário para um item cujo estado atual divergiu de OriginalValues
+type ConflictResolution string
+
+const (
+	// ResolutionSkip não aplica o item
+	ResolutionSkip ConflictResolution = "skip"
+	// ResolutionForce aplica NewValues sobrescrevendo o estado atual
+	ResolutionForce ConflictResolution = "force"
+	// ResolutionRebase aplica apenas os campos alterados pela sessão sobre o estado atual
+	ResolutionRebase ConflictResolution = "rebase"
+)
+
+// ParseConflictResolution valida uma decisão de conflito
+func ParseConflictResolution(value string) (ConflictResolution, error) {
+	switch resolution := ConflictResolution(value); resolution {
+	case ResolutionSkip, ResolutionForce, ResolutionRebase:
+		return resolution, nil
+	default:
+		return "", fmt.Errorf("invalid conflict resolution %q (use skip, force or rebase)", value)
+	}
+}
+
+// FieldConflict campo que mudou no cluster desde que a sessão foi salva
+type FieldConflict struct {
+	Field    string `json:"field"`
+	Original string `json:"original"` // valor registrado na sessão (OriginalValues)
+	Live     string `json:"live"`     // valor atual no cluster
+	Target   string `json:"target"`   // valor que a sessão vai aplicar (NewValues)
+}
+
+// Conflict divergência entre o estado atual de um item e o OriginalValues da sessão
+type Conflict struct {
+	Key        string             `json:"key"`
+	Kind       ItemKind           `json:"kind"`
+	Cluster    string             `json:"cluster"`
+	Namespace  string             `json:"namespace,omitempty"`
+	Name       string             `json:"name"`
+	Fields     []FieldConflict    `json:"fields"`
+	Resolution ConflictResolution `json:"resolution,omitempty"`
+}
+
+// ItemKey identifica um item da sessão (usado para informar decisões de conflito)
+func ItemKey(kind ItemKind, cluster, namespace, name string) string {
+	if namespace != "" {
+		return fmt.Sprintf("%s:%s/%s/%s", kind, cluster, namespace, name)
+	}
+	return fmt.Sprintf("%s:%s/%s", kind, cluster, name)
+}
+
+// Key retorna a chave do item (ver ItemKey)
+func (r ItemResult) Key() string {
+	return ItemKey(r.Kind, r.Cluster, r.Namespace, r.Name)
+}
+
+// resolution retorna a decisão para um conflito (vazio = sem decisão)
+func (o ApplyOptions) resolution(key string) ConflictResolution {
+	if resolution, ok := o.Resolutions[key]; ok && resolution != "" {
+		return resolution
+	}
+	return o.DefaultResolution
+}
+
+// DetectConflicts compara o estado atual de HPAs e node pools com o OriginalValues da sessão.
+// Itens que não puderam ser lidos são ignorados (a falha aparece no plano ou na aplicação).
+func (a *Applier) DetectConflicts(ctx context.Context, session *models.Session) []Conflict {
+	var conflicts []Conflict
+	for _, s := range orderedSteps(session) {
+		if conflict, _ := a.conflictFor(ctx, session, s); conflict != nil {
+			conflicts = append(conflicts, *conflict)
+		}
+	}
+	return conflicts
+}
+
+// liveState estado atual de um item lido do cluster
+type liveState struct {
+	hpa      *models.HPAValues
+	nodePool models.NodePoolValues
+}
+
+// conflictFor lê o estado atual de um passo e retorna o conflito (nil se não houver)
+func (a *Applier) conflictFor(ctx context.Context, session *models.Session, s step) (*Conflict, liveState) {
+	var live liveState
+	switch s.kind {
+	case ItemHPA:
+		values, err := a.LiveHPAValues(ctx, &session.Changes[s.index])
+		if err != nil {
+			return nil, live
+		}
+		live.hpa = values
+		return hpaConflict(&session.Changes[s.index], values), live
+	case ItemNodePool:
+		values, err := a.LiveNodePoolValues(ctx, &session.NodePoolChanges[s.index])
+		if err != nil {
+			return nil, live
+		}
+		live.nodePool = values
+		return nodePoolConflict(&session.NodePoolChanges[s.index], values), live
+	}
+	return nil, live
+}
+
+// LiveHPAValues lê os valores atuais do HPA no cluster
+func (a *Applier) LiveHPAValues(ctx context.Context, change *models.HPAChange) (*models.HPAValues, error) {
+	client, err := a.client(change.Cluster)
+	if err != nil {
+		return nil, err
+	}
+	hpa, err := client.GetHPA(ctx, change.Namespace, change.HPAName)
+	if err != nil {
+		return nil, err
+	}
+	return &models.HPAValues{
+		MinReplicas:  hpa.MinReplicas,
+		MaxReplicas:  hpa.MaxReplicas,
+		TargetCPU:    hpa.TargetCPU,
+		TargetMemory: hpa.TargetMemory,
+		Metrics:      hpa.Metrics,
+		Behavior:     hpa.Behavior,
+	}, nil
+}
+
+// LiveNodePoolValues lê a configuração atual do node pool no provider
+func (a *Applier) LiveNodePoolValues(ctx context.Context, change *models.NodePoolChange) (models.NodePoolValues, error) {
+	if a.opts.NodePools == nil {
+		return models.NodePoolValues{}, fmt.Errorf("node pool provider not configured")
+	}
+	current, err := a.opts.NodePools.Get(ctx, a.opts.ResolveCluster(change), change.NodePoolName)
+	if err != nil {
+		return models.NodePoolValues{}, err
+	}
+	return models.NodePoolValues{
+		NodeCount:          current.NodeCount,
+		MinNodeCount:       current.MinNodeCount,
+		MaxNodeCount:       current.MaxNodeCount,
+		AutoscalingEnabled: current.AutoscalingEnabled,
+	}, nil
+}
+
+// hpaField campo de HPA comparado na detecção de conflitos
+type hpaField struct {
+	name string
+	// compared indica se o campo é comparável para esta mudança (ex: behavior só quando a sessão o aplica)
+	compared func(original, target *models.HPAValues) bool
+	display  func(v *models.HPAValues) string
+	// equal compara dois valores (nil = comparar display)
+	equal func(a, b *models.HPAValues) bool
+	copy  func(dst, src *models.HPAValues)
+}
+
+func (f hpaField) same(a, b *models.HPAValues) bool {
+	if f.equal != nil {
+		return f.equal(a, b)
+	}
+	return f.display(a) == f.display(b)
+}
+
+func always(original, target *models.HPAValues) bool { return true }
+
+var hpaFields = []hpaField{
+	{
+		name:     "min_replicas",
+		compared: always,
+		display:  func(v *models.HPAValues) string { return int32PtrString(v.MinReplicas) },
+		copy: func(dst, src *models.HPAValues) {
+			dst.MinReplicas = nil
+			if src.MinReplicas != nil {
+				value := *src.MinReplicas
+				dst.MinReplicas = &value
+			}
+		},
+	},
+	{
+		name:     "max_replicas",
+		compared: always,
+		display:  func(v *models.HPAValues) string { return fmt.Sprint(v.MaxReplicas) },
+		copy:     func(dst, src *models.HPAValues) { dst.MaxReplicas = src.MaxReplicas },
+	},
+	{
+		name:     "target_cpu",
+		compared: always,
+		display:  func(v *models.HPAValues) string { return int32PtrString(v.TargetCPU) },
+		copy: func(dst, src *models.HPAValues) {
+			dst.TargetCPU = nil
+			if src.TargetCPU != nil {
+				value := *src.TargetCPU
+				dst.TargetCPU = &value
+			}
+		},
+	},
+	{
+		name:     "target_memory",
+		compared: always,
+		display:  func(v *models.HPAValues) string { return int32PtrString(v.TargetMemory) },
+		copy: func(dst, src *models.HPAValues) {
+			dst.TargetMemory = nil
+			if src.TargetMemory != nil {
+				value := *src.TargetMemory
+				dst.TargetMemory = &value
+			}
+		},
+	},
+	{
+		// Métricas só são comparadas quando a sessão registrou a lista completa
+		name: "metrics",
+		compared: func(original, target *models.HPAValues) bool {
+			return original.Metrics != nil && target.Metrics != nil
+		},
+		equal: func(a, b *models.HPAValues) bool {
+			return reflect.DeepEqual(specMetrics(a.Metrics), specMetrics(b.Metrics))
+		},
+		display: func(v *models.HPAValues) string { return fmt.Sprintf("%d métricas", len(v.Metrics)) },
+		copy:    func(dst, src *models.HPAValues) { dst.Metrics = models.CloneHPAMetrics(specMetrics(src.Metrics)) },
+	},
+	{
+		// Behavior nil na sessão não é aplicado (o behavior atual é mantido)
+		name:     "behavior",
+		compared: func(original, target *models.HPAValues) bool { return target.Behavior != nil },
+		equal: func(a, b *models.HPAValues) bool {
+			return reflect.DeepEqual(normalizeBehavior(a.Behavior), normalizeBehavior(b.Behavior))
+		},
+		display: func(v *models.HPAValues) string {
+			if normalizeBehavior(v.Behavior) == nil {
+				return "padrão"
+			}
+			return "customizado"
+		},
+		copy: func(dst, src *models.HPAValues) {
+			dst.Behavior = src.Behavior.Clone()
+			if dst.Behavior == nil {
+				dst.Behavior = &models.HPABehavior{}
+			}
+		},
+	},
+}
+
+// specMetrics remove o valor atual (status) das métricas para comparar apenas o spec
+func specMetrics(metrics []models.HPAMetric) []models.HPAMetric {
+	if metrics == nil {
+		return nil
+	}
+	spec := models.CloneHPAMetrics(metrics)
+	for i := range spec {
+		spec[i].Current = ""
+	}
+	return spec
+}
+
+// hpaConflict retorna os campos que mudaram no cluster e que a aplicação sobrescreveria
+// (live diferente do original e do alvo); nil se não houver conflito
+func hpaConflict(change *models.HPAChange, live *models.HPAValues) *Conflict {
+	if change.OriginalValues == nil || change.NewValues == nil {
+		return nil
+	}
+	original, target := change.OriginalValues, change.NewValues
+
+	conflict := &Conflict{
+		Key:       ItemKey(ItemHPA, change.Cluster, change.Namespace, change.HPAName),
+		Kind:      ItemHPA,
+		Cluster:   change.Cluster,
+		Namespace: change.Namespace,
+		Name:      change.HPAName,
+	}
+	for _, field := range hpaFields {
+		if !field.compared(original, target) || field.same(live, original) || field.same(live, target) {
+			continue
+		}
+		conflict.Fields = append(conflict.Fields, FieldConflict{
+			Field:    field.name,
+			Original: field.display(original),
+			Live:     field.display(live),
+			Target:   field.display(target),
+		})
+	}
+	if len(conflict.Fields) == 0 {
+		return nil
+	}
+	return conflict
+}
+
+// resolveHPAConflict ajusta a mudança conforme a decisão: com force o alvo é mantido e com rebase
+// os campos não alterados pela sessão passam a usar o valor atual. Em ambos os casos
+// OriginalValues passa a refletir o estado atual, para o rollback não desfazer a mudança externa.
+func resolveHPAConflict(change *models.HPAChange, live *models.HPAValues, resolution ConflictResolution) {
+	original, target := change.OriginalValues, change.NewValues
+	rebased := cloneHPAValues(target)
+	current := cloneHPAValues(original)
+
+	for _, field := range hpaFields {
+		if !field.compared(original, target) {
+			continue
+		}
+		if resolution == ResolutionRebase && field.same(original, target) {
+			field.copy(rebased, live)
+		}
+		field.copy(current, live)
+	}
+
+	change.OriginalValues = current
+	if resolution == ResolutionRebase {
+		change.NewValues = rebased
+	}
+}
+
+// nodePoolField campo de node pool comparado na detecção de conflitos
+type nodePoolField struct {
+	name string
+	// compared indica se o campo é comparável (node_count varia com o autoscaler)
+	compared func(original, live, target models.NodePoolValues) bool
+	value    func(v models.NodePoolValues) string
+	copy     func(dst *models.NodePoolValues, src models.NodePoolValues)
+}
+
+var nodePoolFields = []nodePoolField{
+	{
+		name:     "autoscaling_enabled",
+		compared: func(original, live, target models.NodePoolValues) bool { return true },
+		value:    func(v models.NodePoolValues) string { return fmt.Sprint(v.AutoscalingEnabled) },
+		copy: func(dst *models.NodePoolValues, src models.NodePoolValues) {
+			dst.AutoscalingEnabled = src.AutoscalingEnabled
+		},
+	},
+	{
+		name: "min_node_count",
+		compared: func(original, live, target models.NodePoolValues) bool {
+			return original.AutoscalingEnabled || target.AutoscalingEnabled
+		},
+		value: func(v models.NodePoolValues) string { return fmt.Sprint(v.MinNodeCount) },
+		copy:  func(dst *models.NodePoolValues, src models.NodePoolValues) { dst.MinNodeCount = src.MinNodeCount },
+	},
+	{
+		name: "max_node_count",
+		compared: func(original, live, target models.NodePoolValues) bool {
+			return original.AutoscalingEnabled || target.AutoscalingEnabled
+		},
+		value: func(v models.NodePoolValues) string { return fmt.Sprint(v.MaxNodeCount) },
+		copy:  func(dst *models.NodePoolValues, src models.NodePoolValues) { dst.MaxNodeCount = src.MaxNodeCount },
+	},
+	{
+		name: "node_count",
+		compared: func(original, live, target models.NodePoolValues) bool {
+			return !original.AutoscalingEnabled && !live.AutoscalingEnabled && !target.AutoscalingEnabled
+		},
+		value: func(v models.NodePoolValues) string { return fmt.Sprint(v.NodeCount) },
+		copy:  func(dst *models.NodePoolValues, src models.NodePoolValues) { dst.NodeCount = src.NodeCount },
+	},
+}
+
+// nodePoolConflict equivalente a hpaConflict para node pools
+func nodePoolConflict(change *models.NodePoolChange, live models.NodePoolValues) *Conflict {
+	original, target := change.OriginalValues, change.NewValues
+
+	conflict := &Conflict{
+		Key:     ItemKey(ItemNodePool, change.Cluster, "", change.NodePoolName),
+		Kind:    ItemNodePool,
+		Cluster: change.Cluster,
+		Name:    change.NodePoolName,
+	}
+	for _, field := range nodePoolFields {
+		if !field.compared(original, live, target) {
+			continue
+		}
+		value := field.value(live)
+		if value == field.value(original) || value == field.value(target) {
+			continue
+		}
+		conflict.Fields = append(conflict.Fields, FieldConflict{
+			Field:    field.name,
+			Original: field.value(original),
+			Live:     value,
+			Target:   field.value(target),
+		})
+	}
+	if len(conflict.Fields) == 0 {
+		return nil
+	}
+	return conflict
+}
+
+// resolveNodePoolConflict equivalente a resolveHPAConflict para node pools
+func resolveNodePoolConflict(change *models.NodePoolChange, live models.NodePoolValues, resolution ConflictResolution) {
+	original, target := change.OriginalValues, change.NewValues
+	rebased, current := target, original
+
+	for _, field := range nodePoolFields {
+		if !field.compared(original, live, target) {
+			continue
+		}
+		if resolution == ResolutionRebase && field.value(original) == field.value(target) {
+			field.copy(&rebased, live)
+		}
+		field.copy(&current, live)
+	}
+
+	change.OriginalValues = current
+	if resolution == ResolutionRebase {
+		change.NewValues = rebased
+	}
+}
+
+// resolveConflicts detecta conflitos e aplica as decisões de opts. Retorna os conflitos
+// encontrados (com a decisão tomada), os passos que devem ser pulados e se algum conflito
+// ficou sem decisão (nesse caso nada é alterado).
+func (a *Applier) resolveConflicts(ctx context.Context, session *models.Session, opts ApplyOptions) (map[step]Conflict, bool) {
+	conflicts := make(map[step]Conflict)
+	unresolved := false
+
+	lives := make(map[step]liveState)
+
+	for _, s := range orderedSteps(session) {
+		conflict, live := a.conflictFor(ctx, session, s)
+		if conflict == nil {
+			continue
+		}
+
+		conflict.Resolution = opts.resolution(conflict.Key)
+		if conflict.Resolution == "" {
+			unresolved = true
+		}
+		conflicts[s] = *conflict
+		lives[s] = live
+	}
+
+	if unresolved {
+		return conflicts, true
+	}
+
+	for s, conflict := range conflicts {
+		if conflict.Resolution == ResolutionSkip {
+			continue
+		}
+		switch s.kind {
+		case ItemHPA:
+			resolveHPAConflict(&session.Changes[s.index], lives[s].hpa, conflict.Resolution)
+		case ItemNodePool:
+			resolveNodePoolConflict(&session.NodePoolChanges[s.index], lives[s].nodePool, conflict.Resolution)
+		}
+	}
+	return conflicts, false
+}
@@ -0,0 +1,185 @@
+package session
+
+import (
+	"context"
+	"testing"
+
+	"k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/nodepool"
+)
+
+// staleSession sessão cujo HPA web mudou no cluster (max 15, cpu 80) depois de salva
+func staleSession(t *testing.T) (*Applier, *fakeKubeClient, *nodepool.FakeProvider, *models.Session) {
+	t.Helper()
+
+	client := newFakeKubeClient()
+	client.hpas["api/web"] = models.HPA{Namespace: "api", Name: "web", MinReplicas: int32Ptr(2), MaxReplicas: 15, TargetCPU: int32Ptr(80)}
+	applier, provider, _ := newTestApplier(t, client)
+
+	session := testSession()
+	session.Changes = session.Changes[:1]
+	session.Changes[0].OriginalValues.TargetCPU = int32Ptr(70)
+	session.Changes[0].NewValues.TargetCPU = int32Ptr(70)
+	return applier, client, provider, session
+}
+
+// TestDetectConflicts valida o relatório de três vias (original/live/target)
+func TestDetectConflicts(t *testing.T) {
+	applier, _, provider, session := staleSession(t)
+	provider.AddNodePool(nodepool.ClusterRef{Name: "aks-prd", ResourceGroup: "rg-prd", Subscription: "sub"},
+		models.NodePool{Name: "user", NodeCount: 4})
+
+	conflicts := applier.DetectConflicts(context.Background(), session)
+	if len(conflicts) != 2 {
+		t.Fatalf("esperado 2 conflitos, obtido %+v", conflicts)
+	}
+
+	pool, hpa := conflicts[0], conflicts[1]
+	if pool.Key != "node_pool:aks-prd-admin/user" || pool.Fields[0] != (FieldConflict{"node_count", "3", "4", "6"}) {
+		t.Errorf("conflito de node pool inesperado: %+v", pool)
+	}
+	want := []FieldConflict{{"max_replicas", "10", "15", "20"}, {"target_cpu", "70", "80", "70"}}
+	if hpa.Key != "hpa:aks-prd-admin/api/web" || len(hpa.Fields) != 2 || hpa.Fields[0] != want[0] || hpa.Fields[1] != want[1] {
+		t.Errorf("conflito de HPA inesperado: %+v", hpa)
+	}
+
+	plan := applier.Plan(context.Background(), session)
+	if plan.Conflicts != 2 || plan.Items[1].Conflict == nil {
+		t.Errorf("plano deveria reportar conflitos: %+v", plan)
+	}
+}
+
+// TestApplyConflictResolutions valida que conflitos sem decisão bloqueiam a aplicação
+// e o efeito de skip, force e rebase
+func TestApplyConflictResolutions(t *testing.T) {
+	t.Run("sem decisão", func(t *testing.T) {
+		applier, client, _, session := staleSession(t)
+		result := applier.Apply(context.Background(), session, ApplyOptions{})
+		if result.Conflicts != 1 || result.Applied != 0 || result.Err() == nil {
+			t.Fatalf("esperado conflito pendente: %+v", result)
+		}
+		if result.Items[0].Status != StatusConflict {
+			t.Errorf("status inesperado: %+v", result.Items[0])
+		}
+		if client.hpas["api/web"].MaxReplicas != 15 || session.NodePoolChanges[0].Applied {
+			t.Error("nada deveria ser aplicado com conflito pendente")
+		}
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		applier, client, _, session := staleSession(t)
+		result := applier.Apply(context.Background(), session, ApplyOptions{
+			Resolutions: map[string]ConflictResolution{"hpa:aks-prd-admin/api/web": ResolutionSkip},
+		})
+		if result.Err() != nil || result.Applied != 1 {
+			t.Fatalf("esperado apenas o node pool aplicado: %+v", result)
+		}
+		if client.hpas["api/web"].MaxReplicas != 15 || result.Items[1].Status != StatusSkipped {
+			t.Errorf("HPA deveria ser mantido: %+v", result.Items[1])
+		}
+	})
+
+	t.Run("force", func(t *testing.T) {
+		applier, client, _, session := staleSession(t)
+		result := applier.Apply(context.Background(), session, ApplyOptions{DefaultResolution: ResolutionForce})
+		if result.Err() != nil {
+			t.Fatalf("Apply() erro inesperado: %v", result.Err())
+		}
+		if hpa := client.hpas["api/web"]; hpa.MaxReplicas != 20 || *hpa.TargetCPU != 70 {
+			t.Errorf("force deveria aplicar o alvo: %+v", hpa)
+		}
+		if original := session.Changes[0].OriginalValues; original.MaxReplicas != 15 || *original.TargetCPU != 80 {
+			t.Errorf("rollback deveria voltar ao estado atual: %+v", original)
+		}
+	})
+
+	t.Run("rebase", func(t *testing.T) {
+		applier, client, _, session := staleSession(t)
+		result := applier.Apply(context.Background(), session, ApplyOptions{DefaultResolution: ResolutionRebase})
+		if result.Err() != nil {
+			t.Fatalf("Apply() erro inesperado: %v", result.Err())
+		}
+		// max_replicas é alterado pela sessão; target_cpu não, então o valor atual é preservado
+		if hpa := client.hpas["api/web"]; *hpa.MinReplicas != 4 || hpa.MaxReplicas != 20 || *hpa.TargetCPU != 80 {
+			t.Errorf("rebase inesperado: %+v", hpa)
+		}
+		if result.Items[1].Conflict == nil || result.Items[1].Conflict.Resolution != ResolutionRebase {
+			t.Errorf("resultado deveria registrar a decisão: %+v", result.Items[1])
+		}
+	})
+}
+
+// appliedSession sessão aplicada cujo HPA web ganhou target_cpu 80 no cluster depois da aplicação
+func appliedSession(t *testing.T) (*Applier, *fakeKubeClient, *nodepool.FakeProvider, *models.Session) {
+	t.Helper()
+
+	client := newFakeKubeClient()
+	applier, provider, _ := newTestApplier(t, client)
+	session := testSession()
+	session.Changes = session.Changes[:1]
+	if result := applier.Apply(context.Background(), session, ApplyOptions{DeferRollouts: true}); result.Err() != nil {
+		t.Fatalf("Apply() erro inesperado: %v", result.Err())
+	}
+
+	hpa := client.hpas["api/web"]
+	hpa.TargetCPU = int32Ptr(80)
+	client.hpas["api/web"] = hpa
+	return applier, client, provider, session
+}
+
+// TestRollbackConflictResolutions valida que o rollback compara o estado atual com o que foi
+// aplicado (NewValues) e trata a divergência com skip, force e rebase
+func TestRollbackConflictResolutions(t *testing.T) {
+	pool := func(provider *nodepool.FakeProvider) int32 {
+		current, _ := provider.Get(context.Background(), nodepool.ClusterRef{Name: "aks-prd"}, "user")
+		return current.NodeCount
+	}
+
+	t.Run("sem decisão", func(t *testing.T) {
+		applier, client, provider, session := appliedSession(t)
+		result, err := applier.Rollback(context.Background(), session, ApplyOptions{})
+		if err != nil || result.Conflicts != 1 || result.Applied != 0 || result.Err() == nil {
+			t.Fatalf("esperado conflito pendente: %+v %v", result, err)
+		}
+		if result.Items[0].Conflict.Fields[0] != (FieldConflict{"target_cpu", "-", "80", "-"}) {
+			t.Errorf("conflito inesperado: %+v", result.Items[0].Conflict)
+		}
+		if client.hpas["api/web"].MaxReplicas != 20 || pool(provider) != 6 || !session.Changes[0].Applied {
+			t.Error("nada deveria ser desfeito com conflito pendente")
+		}
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		applier, client, provider, session := appliedSession(t)
+		result, _ := applier.Rollback(context.Background(), session, ApplyOptions{DefaultResolution: ResolutionSkip})
+		if result.Err() != nil || result.Applied != 1 {
+			t.Fatalf("esperado apenas o node pool desfeito: %+v", result)
+		}
+		if client.hpas["api/web"].MaxReplicas != 20 || !session.Changes[0].Applied || pool(provider) != 3 {
+			t.Errorf("HPA deveria ser mantido e o node pool desfeito: %+v", client.hpas["api/web"])
+		}
+	})
+
+	t.Run("force", func(t *testing.T) {
+		applier, client, _, session := appliedSession(t)
+		result, _ := applier.Rollback(context.Background(), session, ApplyOptions{DefaultResolution: ResolutionForce})
+		if result.Err() != nil {
+			t.Fatalf("Rollback() erro inesperado: %v", result.Err())
+		}
+		if hpa := client.hpas["api/web"]; hpa.MaxReplicas != 10 || hpa.TargetCPU != nil || session.Changes[0].Applied {
+			t.Errorf("force deveria aplicar o original: %+v", hpa)
+		}
+	})
+
+	t.Run("rebase", func(t *testing.T) {
+		applier, client, _, session := appliedSession(t)
+		result, _ := applier.Rollback(context.Background(), session, ApplyOptions{DefaultResolution: ResolutionRebase})
+		if result.Err() != nil {
+			t.Fatalf("Rollback() erro inesperado: %v", result.Err())
+		}
+		// target_cpu não foi alterado pela sessão: a mudança externa é preservada
+		if hpa := client.hpas["api/web"]; *hpa.MinReplicas != 2 || hpa.MaxReplicas != 10 || hpa.TargetCPU == nil || *hpa.TargetCPU != 80 {
+			t.Errorf("rebase inesperado: %+v", hpa)
+		}
+	})
+}
@@ -0,0 +1,400 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s-hpa-manager/internal/models"
+)
+
+// DiffChange tipo de diferença de um item entre duas sessões
+type DiffChange string
+
+const (
+	DiffOnlyA   DiffChange = "only_a"  // item presente apenas na sessão A
+	DiffOnlyB   DiffChange = "only_b"  // item presente apenas na sessão B
+	DiffChanged DiffChange = "changed" // item presente nas duas com valores alvo diferentes
+)
+
+// FieldDiff valor alvo (NewValues) de um campo em cada sessão
+type FieldDiff struct {
+	Field string `json:"field"`
+	A     string `json:"a"`
+	B     string `json:"b"`
+}
+
+// ItemDiff diferença de um HPA, node pool ou recurso entre duas sessões
+type ItemDiff struct {
+	Key       string      `json:"key"`
+	Kind      ItemKind    `json:"kind"`
+	Cluster   string      `json:"cluster"`
+	Namespace string      `json:"namespace,omitempty"`
+	Name      string      `json:"name"`
+	Change    DiffChange  `json:"change"`
+	Fields    []FieldDiff `json:"fields,omitempty"`
+}
+
+// SessionDiff diferenças entre os valores alvo de duas sessões
+type SessionDiff struct {
+	A         string     `json:"a"`
+	B         string     `json:"b"`
+	Items     []ItemDiff `json:"items"`
+	Unchanged int        `json:"unchanged"` // itens iguais nas duas sessões
+	Identical bool       `json:"identical"`
+}
+
+// sessionItem item de uma sessão indexado pela chave (ver ItemKey)
+type sessionItem struct {
+	key       string
+	kind      ItemKind
+	cluster   string
+	namespace string
+	name      string
+	fields    []namedValue
+	hpa       *models.HPAChange
+	nodePool  *models.NodePoolChange
+	resource  *models.ClusterResourceChange
+}
+
+type namedValue struct {
+	name  string
+	value string
+}
+
+// sessionItems lista os itens da sessão na ordem HPAs → node pools → recursos
+func sessionItems(session *models.Session) []sessionItem {
+	var items []sessionItem
+	for i := range session.Changes {
+		change := &session.Changes[i]
+		items = append(items, sessionItem{
+			key:  ItemKey(ItemHPA, change.Cluster, change.Namespace, change.HPAName),
+			kind: ItemHPA, cluster: change.Cluster, namespace: change.Namespace, name: change.HPAName,
+			fields: hpaTargetFields(change.NewValues),
+			hpa:    change,
+		})
+	}
+	for i := range session.NodePoolChanges {
+		change := &session.NodePoolChanges[i]
+		items = append(items, sessionItem{
+			key:  ItemKey(ItemNodePool, change.Cluster, "", change.NodePoolName),
+			kind: ItemNodePool, cluster: change.Cluster, name: change.NodePoolName,
+			fields:   nodePoolTargetFields(change),
+			nodePool: change,
+		})
+	}
+	for i := range session.ResourceChanges {
+		change := &session.ResourceChanges[i]
+		items = append(items, sessionItem{
+			key:  ItemKey(ItemResource, change.Cluster, change.Namespace, change.ResourceName),
+			kind: ItemResource, cluster: change.Cluster, namespace: change.Namespace, name: change.ResourceName,
+			fields:   resourceTargetFields(change.NewValues),
+			resource: change,
+		})
+	}
+	return items
+}
+
+// Diff compara os valores alvo (NewValues) de duas sessões, item a item e campo a campo
+func Diff(a, b *models.Session) *SessionDiff {
+	diff := &SessionDiff{A: a.Name, B: b.Name}
+
+	itemsB := make(map[string]sessionItem)
+	for _, item := range sessionItems(b) {
+		itemsB[item.key] = item
+	}
+
+	seen := make(map[string]bool)
+	for _, itemA := range sessionItems(a) {
+		seen[itemA.key] = true
+		itemB, ok := itemsB[itemA.key]
+		if !ok {
+			diff.Items = append(diff.Items, newItemDiff(itemA, DiffOnlyA, nil))
+			continue
+		}
+		fields := diffFields(itemA.fields, itemB.fields)
+		if len(fields) == 0 {
+			diff.Unchanged++
+			continue
+		}
+		diff.Items = append(diff.Items, newItemDiff(itemA, DiffChanged, fields))
+	}
+	for _, itemB := range sessionItems(b) {
+		if !seen[itemB.key] {
+			diff.Items = append(diff.Items, newItemDiff(itemB, DiffOnlyB, nil))
+		}
+	}
+
+	diff.Identical = len(diff.Items) == 0
+	return diff
+}
+
+func newItemDiff(item sessionItem, change DiffChange, fields []FieldDiff) ItemDiff {
+	return ItemDiff{
+		Key:       item.key,
+		Kind:      item.kind,
+		Cluster:   item.cluster,
+		Namespace: item.namespace,
+		Name:      item.name,
+		Change:    change,
+		Fields:    fields,
+	}
+}
+
+func diffFields(a, b []namedValue) []FieldDiff {
+	var fields []FieldDiff
+	for i := range a {
+		if i < len(b) && a[i].value != b[i].value {
+			fields = append(fields, FieldDiff{Field: a[i].name, A: a[i].value, B: b[i].value})
+		}
+	}
+	return fields
+}
+
+// MergeSide sessão escolhida para um item em conflito
+type MergeSide string
+
+const (
+	MergeSideA MergeSide = "a"
+	MergeSideB MergeSide = "b"
+)
+
+// ParseMergeSide valida a escolha de lado de um merge
+func ParseMergeSide(value string) (MergeSide, error) {
+	switch side := MergeSide(strings.ToLower(value)); side {
+	case MergeSideA, MergeSideB:
+		return side, nil
+	default:
+		return "", fmt.Errorf("invalid merge side %q (use a or b)", value)
+	}
+}
+
+// MergeOptions controla o merge de duas sessões
+type MergeOptions struct {
+	// Name nome da sessão resultante
+	Name string
+	// Resolutions lado escolhido por item em conflito (chave ItemDiff.Key)
+	Resolutions map[string]MergeSide
+	// Prefer lado usado para conflitos sem entrada em Resolutions (vazio = reportar conflito)
+	Prefer MergeSide
+}
+
+// MergeResult resultado do merge; Session é nil quando há conflitos sem decisão
+type MergeResult struct {
+	Session   *models.Session `json:"session,omitempty"`
+	Diff      *SessionDiff    `json:"diff"`
+	Conflicts []ItemDiff      `json:"conflicts,omitempty"`
+}
+
+// ErrMergeConflicts indica itens presentes nas duas sessões com valores diferentes e sem decisão
+var ErrMergeConflicts = errors.New("sessions have conflicting items")
+
+// Merge combina duas sessões: itens de apenas uma delas são incluídos, itens iguais entram uma
+// vez e itens com valores alvo diferentes usam o lado escolhido em opts. O estado de aplicação
+// (Applied, rollouts disparados, erros) não é copiado: a sessão resultante é nova.
+func Merge(a, b *models.Session, opts MergeOptions, now time.Time) (*MergeResult, error) {
+	if opts.Name == "" {
+		return nil, fmt.Errorf("merged session name is required")
+	}
+
+	diff := Diff(a, b)
+	result := &MergeResult{Diff: diff}
+
+	choice := make(map[string]MergeSide)
+	for _, item := range diff.Items {
+		if item.Change != DiffChanged {
+			continue
+		}
+		side := opts.Resolutions[item.Key]
+		if side == "" {
+			side = opts.Prefer
+		}
+		if side == "" {
+			result.Conflicts = append(result.Conflicts, item)
+			continue
+		}
+		choice[item.Key] = side
+	}
+	if len(result.Conflicts) > 0 {
+		return result, fmt.Errorf("%w: %d items differ and need a side (a or b)", ErrMergeConflicts, len(result.Conflicts))
+	}
+
+	merged := &models.Session{
+		Name:         opts.Name,
+		CreatedAt:    now,
+		Description:  fmt.Sprintf("Merge das sessões %s e %s", a.Name, b.Name),
+		TemplateUsed: a.TemplateUsed,
+		RollbackData: &models.RollbackData{OriginalStateCaptured: true, CanRollback: true},
+	}
+
+	itemsB := make(map[string]sessionItem)
+	for _, item := range sessionItems(b) {
+		itemsB[item.key] = item
+	}
+	seen := make(map[string]bool)
+	for _, item := range sessionItems(a) {
+		seen[item.key] = true
+		if other, ok := itemsB[item.key]; ok && choice[item.key] == MergeSideB {
+			item = other
+		}
+		addMergedItem(merged, item)
+	}
+	for _, item := range sessionItems(b) {
+		if !seen[item.key] {
+			addMergedItem(merged, item)
+		}
+	}
+
+	merged.Metadata = GenerateMetadata(merged)
+	result.Session = merged
+	return result, nil
+}
+
+// addMergedItem copia o item para a sessão resultante sem o estado de aplicação
+func addMergedItem(merged *models.Session, item sessionItem) {
+	switch {
+	case item.hpa != nil:
+		change := models.HPAChange{
+			Cluster:   item.hpa.Cluster,
+			Namespace: item.hpa.Namespace,
+			HPAName:   item.hpa.HPAName,
+		}
+		if item.hpa.OriginalValues != nil {
+			change.OriginalValues = cloneHPAValues(item.hpa.OriginalValues)
+		}
+		if item.hpa.NewValues != nil {
+			change.NewValues = cloneHPAValues(item.hpa.NewValues)
+		}
+		merged.Changes = append(merged.Changes, change)
+	case item.nodePool != nil:
+		change := *item.nodePool
+		change.Applied, change.AppliedAt, change.Error = false, nil, ""
+		change.SequenceStatus = ""
+		if change.SequenceOrder > 0 {
+			change.SequenceStatus = "pending"
+		}
+		merged.NodePoolChanges = append(merged.NodePoolChanges, change)
+	case item.resource != nil:
+		change := *item.resource
+		change.Applied, change.AppliedAt, change.Error = false, nil, ""
+		if change.OriginalValues != nil {
+			original := *change.OriginalValues
+			change.OriginalValues = &original
+		}
+		if change.NewValues != nil {
+			target := *change.NewValues
+			change.NewValues = &target
+		}
+		merged.ResourceChanges = append(merged.ResourceChanges, change)
+	}
+}
+
+// hpaTargetFields campos comparáveis dos valores alvo de um HPA
+func hpaTargetFields(v *models.HPAValues) []namedValue {
+	if v == nil {
+		v = &models.HPAValues{}
+	}
+	return []namedValue{
+		{"min_replicas", int32PtrString(v.MinReplicas)},
+		{"max_replicas", fmt.Sprint(v.MaxReplicas)},
+		{"target_cpu", int32PtrString(v.TargetCPU)},
+		{"target_memory", int32PtrString(v.TargetMemory)},
+		{"metrics", metricsSummary(v.Metrics)},
+		{"behavior", behaviorSummary(v.Behavior)},
+		{"cpu_request", stringOrDash(v.CPURequest)},
+		{"cpu_limit", stringOrDash(v.CPULimit)},
+		{"memory_request", stringOrDash(v.MemoryRequest)},
+		{"memory_limit", stringOrDash(v.MemoryLimit)},
+		{"perform_rollout", fmt.Sprint(v.PerformRollout)},
+		{"perform_daemonset_rollout", fmt.Sprint(v.PerformDaemonSetRollout)},
+		{"perform_statefulset_rollout", fmt.Sprint(v.PerformStatefulSetRollout)},
+	}
+}
+
+// nodePoolTargetFields campos comparáveis dos valores alvo de um node pool
+func nodePoolTargetFields(change *models.NodePoolChange) []namedValue {
+	v := change.NewValues
+	return []namedValue{
+		{"autoscaling_enabled", fmt.Sprint(v.AutoscalingEnabled)},
+		{"node_count", fmt.Sprint(v.NodeCount)},
+		{"min_node_count", fmt.Sprint(v.MinNodeCount)},
+		{"max_node_count", fmt.Sprint(v.MaxNodeCount)},
+		{"sequence_order", fmt.Sprint(change.SequenceOrder)},
+	}
+}
+
+// resourceTargetFields campos comparáveis dos valores alvo de um workload
+func resourceTargetFields(v *models.ResourceValues) []namedValue {
+	if v == nil {
+		v = &models.ResourceValues{}
+	}
+	return []namedValue{
+		{"cpu_request", stringOrDash(v.CPURequest)},
+		{"memory_request", stringOrDash(v.MemoryRequest)},
+		{"cpu_limit", stringOrDash(v.CPULimit)},
+		{"memory_limit", stringOrDash(v.MemoryLimit)},
+		{"replicas", fmt.Sprint(v.Replicas)},
+		{"storage_size", stringOrDash(v.StorageSize)},
+	}
+}
+
+// metricsSummary descreve o spec das métricas em uma linha (ex: "Resource/cpu Utilization=70")
+func metricsSummary(metrics []models.HPAMetric) string {
+	if metrics == nil {
+		return "-"
+	}
+	parts := make([]string, 0, len(metrics))
+	for _, metric := range metrics {
+		name := metric.Type + "/" + metric.Name
+		if metric.Container != "" {
+			name += "[" + metric.Container + "]"
+		}
+		if metric.DescribedObject != nil {
+			name += "@" + metric.DescribedObject.Kind + "/" + metric.DescribedObject.Name
+		}
+		if metric.Selector != "" {
+			name += "{" + metric.Selector + "}"
+		}
+		value := metric.TargetValue
+		if metric.TargetType == models.HPAMetricTargetUtilization {
+			value = int32PtrString(metric.TargetUtilization)
+		}
+		parts = append(parts, fmt.Sprintf("%s %s=%s", name, metric.TargetType, value))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// behaviorSummary descreve o behavior em uma linha ("-" quando não definido)
+func behaviorSummary(behavior *models.HPABehavior) string {
+	if behavior == nil {
+		return "-"
+	}
+	if normalizeBehavior(behavior) == nil {
+		return "padrão"
+	}
+	rules := func(direction string, r *models.HPAScalingRules) string {
+		if r == nil {
+			return direction + ": padrão"
+		}
+		summary := direction + ":"
+		if r.StabilizationWindowSeconds != nil {
+			summary += fmt.Sprintf(" janela=%ds", *r.StabilizationWindowSeconds)
+		}
+		if r.SelectPolicy != nil {
+			summary += " " + *r.SelectPolicy
+		}
+		for _, policy := range r.Policies {
+			summary += fmt.Sprintf(" %s=%d/%ds", policy.Type, policy.Value, policy.PeriodSeconds)
+		}
+		return summary
+	}
+	return rules("scaleUp", behavior.ScaleUp) + "; " + rules("scaleDown", behavior.ScaleDown)
+}
+
+func stringOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
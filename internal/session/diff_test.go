@@ -0,0 +1,105 @@
+package session
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"k8s-hpa-manager/internal/models"
+)
+
+// divergentSessions duas sessões preparadas para o mesmo cluster: web com max diferente,
+// worker apenas em A e um node pool igual nas duas mais um recurso apenas em B
+func divergentSessions() (*models.Session, *models.Session) {
+	a := testSession()
+	a.Name = "upscale-ana"
+
+	b := testSession()
+	b.Name = "upscale-bruno"
+	b.Changes = b.Changes[:1]
+	b.Changes[0].NewValues.MaxReplicas = 30
+	b.ResourceChanges = []models.ClusterResourceChange{{
+		Cluster: "aks-prd-admin", Namespace: "api", ResourceName: "cache",
+		NewValues: &models.ResourceValues{Replicas: 3},
+	}}
+	return a, b
+}
+
+// TestDiff valida o relatório de diferenças por item e por campo
+func TestDiff(t *testing.T) {
+	a, b := divergentSessions()
+
+	diff := Diff(a, b)
+	if diff.Identical || diff.Unchanged != 1 || len(diff.Items) != 3 {
+		t.Fatalf("diff inesperado: %+v", diff)
+	}
+
+	web := diff.Items[0]
+	if web.Key != "hpa:aks-prd-admin/api/web" || web.Change != DiffChanged ||
+		len(web.Fields) != 1 || web.Fields[0] != (FieldDiff{"max_replicas", "20", "30"}) {
+		t.Errorf("diff de web inesperado: %+v", web)
+	}
+	if worker := diff.Items[1]; worker.Name != "worker" || worker.Change != DiffOnlyA {
+		t.Errorf("worker deveria existir apenas em A: %+v", worker)
+	}
+	if cache := diff.Items[2]; cache.Kind != ItemResource || cache.Change != DiffOnlyB {
+		t.Errorf("cache deveria existir apenas em B: %+v", cache)
+	}
+
+	if same := Diff(a, a); !same.Identical || same.Unchanged != 3 {
+		t.Errorf("sessão comparada com ela mesma deveria ser idêntica: %+v", same)
+	}
+}
+
+// TestMerge valida o merge com conflitos pendentes, decisão por item e lado preferido
+func TestMerge(t *testing.T) {
+	now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+
+	t.Run("conflito sem decisão", func(t *testing.T) {
+		a, b := divergentSessions()
+		result, err := Merge(a, b, MergeOptions{Name: "upscale-merge"}, now)
+		if !errors.Is(err, ErrMergeConflicts) {
+			t.Fatalf("esperado ErrMergeConflicts, obtido %v", err)
+		}
+		if result.Session != nil || len(result.Conflicts) != 1 || result.Conflicts[0].Name != "web" {
+			t.Errorf("conflito inesperado: %+v", result)
+		}
+	})
+
+	t.Run("decisão por item", func(t *testing.T) {
+		a, b := divergentSessions()
+		a.Changes[0].Applied = true
+		result, err := Merge(a, b, MergeOptions{
+			Name:        "upscale-merge",
+			Resolutions: map[string]MergeSide{"hpa:aks-prd-admin/api/web": MergeSideB},
+		}, now)
+		if err != nil {
+			t.Fatalf("Merge() erro inesperado: %v", err)
+		}
+
+		merged := result.Session
+		if len(merged.Changes) != 2 || len(merged.NodePoolChanges) != 1 || len(merged.ResourceChanges) != 1 {
+			t.Fatalf("sessão resultante deveria ter a união dos itens: %+v", merged)
+		}
+		if web := merged.Changes[0]; web.NewValues.MaxReplicas != 30 || web.Applied {
+			t.Errorf("web deveria vir de B sem estado de aplicação: %+v", web)
+		}
+		if merged.Metadata == nil || merged.Metadata.ClustersAffected[0] != "aks-prd-admin" || !merged.CreatedAt.Equal(now) {
+			t.Errorf("metadata inesperada: %+v", merged)
+		}
+		if a.Changes[0].NewValues.MaxReplicas != 20 {
+			t.Error("merge não deveria alterar a sessão de origem")
+		}
+	})
+
+	t.Run("lado preferido", func(t *testing.T) {
+		a, b := divergentSessions()
+		result, err := Merge(a, b, MergeOptions{Name: "upscale-merge", Prefer: MergeSideA}, now)
+		if err != nil {
+			t.Fatalf("Merge() erro inesperado: %v", err)
+		}
+		if result.Session.Changes[0].NewValues.MaxReplicas != 20 {
+			t.Errorf("web deveria vir de A: %+v", result.Session.Changes[0].NewValues)
+		}
+	})
+}
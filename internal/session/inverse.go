@@ -0,0 +1,185 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s-hpa-manager/internal/models"
+)
+
+// RelationInverse relação de uma sessão gerada por BuildInverseSession com a sessão de origem
+const RelationInverse = "inverse"
+
+// InverseFolder retorna a pasta oposta (HPA-Upscale ↔ HPA-Downscale, Node-Upscale ↔ Node-Downscale).
+// Sessões da raiz ou da pasta Rollback geram inversas em Rollback.
+func InverseFolder(folder SessionFolder) SessionFolder {
+	switch folder {
+	case FolderHPAUpscale:
+		return FolderHPADownscale
+	case FolderHPADownscale:
+		return FolderHPAUpscale
+	case FolderNodeUpscale:
+		return FolderNodeDownscale
+	case FolderNodeDownscale:
+		return FolderNodeUpscale
+	default:
+		return FolderRollback
+	}
+}
+
+// LiveStateReader lê o estado atual dos itens de uma sessão (implementado por Applier)
+type LiveStateReader interface {
+	LiveHPAValues(ctx context.Context, change *models.HPAChange) (*models.HPAValues, error)
+	LiveNodePoolValues(ctx context.Context, change *models.NodePoolChange) (models.NodePoolValues, error)
+}
+
+// InverseOptions controla a geração da sessão inversa
+type InverseOptions struct {
+	// Name nome da sessão inversa (vazio = Inverse_<origem>_<timestamp>)
+	Name string
+	// Folder pasta de destino (vazio = InverseFolder da pasta de origem)
+	Folder SessionFolder
+	// Live quando definido, os valores originais da inversa são lidos do cluster em vez de
+	// assumir que a origem foi aplicada. Requests/limits de recursos não são atualizados.
+	Live LiveStateReader
+	// Now horário de criação (zero = time.Now())
+	Now time.Time
+}
+
+// BuildInverseSession gera a sessão que desfaz todos os itens da origem, aplicados ou não:
+// OriginalValues e NewValues são trocados, rollouts da origem são mantidos e a ordem
+// sequencial dos node pools é invertida
+func BuildInverseSession(ctx context.Context, source *models.Session, sourceFolder SessionFolder, opts InverseOptions) (*models.Session, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	name := opts.Name
+	if name == "" {
+		name = derivedSessionName("Inverse_", source.Name, now)
+	}
+
+	inverse := &models.Session{
+		Name:         name,
+		CreatedAt:    now,
+		Description:  fmt.Sprintf("Sessão inversa de %s", source.Name),
+		TemplateUsed: RelationInverse,
+		RollbackData: &models.RollbackData{OriginalStateCaptured: true, CanRollback: true},
+		SourceSession: &models.SessionReference{
+			Name:     source.Name,
+			Folder:   string(sourceFolder),
+			Relation: RelationInverse,
+		},
+	}
+
+	for i := range source.Changes {
+		change := &source.Changes[i]
+		if change.OriginalValues == nil || change.NewValues == nil {
+			continue
+		}
+
+		original := cloneHPAValues(change.NewValues)
+		original.PerformRollout, original.PerformDaemonSetRollout, original.PerformStatefulSetRollout = false, false, false
+		if opts.Live != nil {
+			live, err := opts.Live.LiveHPAValues(ctx, change)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read live state of HPA %s/%s: %w", change.Namespace, change.HPAName, err)
+			}
+			original.MinReplicas, original.MaxReplicas = live.MinReplicas, live.MaxReplicas
+			original.TargetCPU, original.TargetMemory = live.TargetCPU, live.TargetMemory
+			original.Metrics, original.Behavior = live.Metrics, live.Behavior
+		}
+
+		target := rollbackHPAValues(change.OriginalValues, change.NewValues)
+		target.PerformRollout = change.NewValues.PerformRollout
+		target.PerformDaemonSetRollout = change.NewValues.PerformDaemonSetRollout
+		target.PerformStatefulSetRollout = change.NewValues.PerformStatefulSetRollout
+
+		inverse.Changes = append(inverse.Changes, models.HPAChange{
+			Cluster:        change.Cluster,
+			Namespace:      change.Namespace,
+			HPAName:        change.HPAName,
+			OriginalValues: original,
+			NewValues:      target,
+		})
+	}
+
+	maxOrder := 0
+	for _, change := range source.NodePoolChanges {
+		if change.SequenceOrder > maxOrder {
+			maxOrder = change.SequenceOrder
+		}
+	}
+	for i := range source.NodePoolChanges {
+		change := &source.NodePoolChanges[i]
+
+		original := change.NewValues
+		if opts.Live != nil {
+			live, err := opts.Live.LiveNodePoolValues(ctx, change)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read live state of node pool %s: %w", change.NodePoolName, err)
+			}
+			original = live
+		}
+
+		inverseChange := models.NodePoolChange{
+			Cluster:        change.Cluster,
+			ResourceGroup:  change.ResourceGroup,
+			Subscription:   change.Subscription,
+			NodePoolName:   change.NodePoolName,
+			OriginalValues: original,
+			NewValues:      change.OriginalValues,
+		}
+		if change.SequenceOrder > 0 {
+			inverseChange.SequenceOrder = maxOrder + 1 - change.SequenceOrder
+			inverseChange.SequenceStatus = "pending"
+		}
+		inverse.NodePoolChanges = append(inverse.NodePoolChanges, inverseChange)
+	}
+
+	for _, change := range source.ResourceChanges {
+		if change.OriginalValues == nil || change.NewValues == nil {
+			continue
+		}
+		original, target := *change.NewValues, *change.OriginalValues
+		inverse.ResourceChanges = append(inverse.ResourceChanges, models.ClusterResourceChange{
+			Cluster:        change.Cluster,
+			Namespace:      change.Namespace,
+			ResourceName:   change.ResourceName,
+			WorkloadType:   change.WorkloadType,
+			Component:      change.Component,
+			OriginalValues: &original,
+			NewValues:      &target,
+		})
+	}
+
+	if len(inverse.Changes)+len(inverse.NodePoolChanges)+len(inverse.ResourceChanges) == 0 {
+		return nil, fmt.Errorf("session %s has no items with original values to invert", source.Name)
+	}
+
+	inverse.Metadata = GenerateMetadata(inverse)
+	return inverse, nil
+}
+
+// GenerateInverseSession gera e salva a sessão inversa de uma sessão salva.
+// Retorna a sessão gerada e a pasta onde foi salva; não sobrescreve sessões existentes.
+func (m *Manager) GenerateInverseSession(ctx context.Context, source *models.Session, sourceFolder SessionFolder, opts InverseOptions) (*models.Session, SessionFolder, error) {
+	folder := opts.Folder
+	if folder == "" {
+		folder = InverseFolder(sourceFolder)
+	}
+
+	inverse, err := BuildInverseSession(ctx, source, sourceFolder, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if _, err := m.LoadSessionFromFolder(inverse.Name, folder); err == nil {
+		return nil, "", fmt.Errorf("session %s already exists in folder %s", inverse.Name, folder)
+	}
+	if err := m.SaveSessionToFolder(inverse, folder); err != nil {
+		return nil, "", fmt.Errorf("failed to save inverse session: %w", err)
+	}
+	return inverse, folder, nil
+}
@@ -0,0 +1,82 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s-hpa-manager/internal/models"
+)
+
+// TestBuildInverseSession valida a troca de valores, a ordem dos node pools e a referência à origem
+func TestBuildInverseSession(t *testing.T) {
+	source := testSession()
+	source.NodePoolChanges = append(source.NodePoolChanges, models.NodePoolChange{
+		Cluster: "aks-prd-admin", NodePoolName: "system",
+		OriginalValues: models.NodePoolValues{NodeCount: 2},
+		NewValues:      models.NodePoolValues{NodeCount: 4},
+		SequenceOrder:  2,
+	})
+	source.NodePoolChanges[0].SequenceOrder = 1
+
+	now := time.Date(2026, 10, 17, 18, 0, 0, 0, time.UTC)
+	inverse, err := BuildInverseSession(context.Background(), source, FolderHPAUpscale, InverseOptions{Now: now})
+	if err != nil {
+		t.Fatalf("BuildInverseSession() erro inesperado: %v", err)
+	}
+
+	if inverse.Name != "Inverse_upscale-test_171026-180000" {
+		t.Errorf("nome inesperado: %s", inverse.Name)
+	}
+	if ref := inverse.SourceSession; ref == nil || ref.Name != "upscale-test" || ref.Folder != "HPA-Upscale" || ref.Relation != RelationInverse {
+		t.Errorf("referência à origem inesperada: %+v", ref)
+	}
+
+	web := inverse.Changes[0]
+	if web.OriginalValues.MaxReplicas != 20 || web.NewValues.MaxReplicas != 10 || *web.NewValues.MinReplicas != 2 {
+		t.Errorf("valores do HPA deveriam ser trocados: %+v → %+v", web.OriginalValues, web.NewValues)
+	}
+	if !web.NewValues.PerformRollout || web.OriginalValues.PerformRollout {
+		t.Error("rollout da origem deveria ser mantido apenas no alvo")
+	}
+
+	user, system := inverse.NodePoolChanges[0], inverse.NodePoolChanges[1]
+	if user.NewValues.NodeCount != 3 || user.SequenceOrder != 2 || system.SequenceOrder != 1 {
+		t.Errorf("node pools inesperados: %+v / %+v", user, system)
+	}
+	if inverse.Metadata.TotalChanges != 4 {
+		t.Errorf("metadata inesperada: %+v", inverse.Metadata)
+	}
+}
+
+// TestGenerateInverseSessionLive valida a leitura do estado atual e o salvamento na pasta oposta
+func TestGenerateInverseSessionLive(t *testing.T) {
+	client := newFakeKubeClient()
+	client.hpas["api/web"] = models.HPA{Namespace: "api", Name: "web", MinReplicas: int32Ptr(5), MaxReplicas: 25}
+	client.hpas["api/worker"] = models.HPA{Namespace: "api", Name: "worker", MinReplicas: int32Ptr(3), MaxReplicas: 8}
+	applier, _, manager := newTestApplier(t, client)
+
+	opts := InverseOptions{Name: "downscale-test", Live: applier}
+	inverse, folder, err := manager.GenerateInverseSession(context.Background(), testSession(), FolderHPAUpscale, opts)
+	if err != nil {
+		t.Fatalf("GenerateInverseSession() erro inesperado: %v", err)
+	}
+	if folder != FolderHPADownscale {
+		t.Errorf("pasta inesperada: %s", folder)
+	}
+	if original := inverse.Changes[0].OriginalValues; *original.MinReplicas != 5 || original.MaxReplicas != 25 {
+		t.Errorf("valores originais deveriam vir do cluster: %+v", original)
+	}
+	if inverse.NodePoolChanges[0].OriginalValues.NodeCount != 3 {
+		t.Errorf("node pool deveria refletir o provider: %+v", inverse.NodePoolChanges[0])
+	}
+
+	saved, err := manager.LoadSessionFromFolder("downscale-test", FolderHPADownscale)
+	if err != nil || saved.SourceSession == nil || saved.SourceSession.Name != "upscale-test" {
+		t.Fatalf("sessão inversa deveria ser salva com a referência: %+v, %v", saved, err)
+	}
+
+	if _, _, err := manager.GenerateInverseSession(context.Background(), testSession(), FolderHPAUpscale, opts); err == nil {
+		t.Error("esperado erro ao sobrescrever sessão existente")
+	}
+}
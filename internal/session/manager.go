@@ -37,7 +37,11 @@ func NewManager() (*Manager, error) {
 		return nil, fmt.Errorf("failed to get user home directory: %w", err)
 	}
 
-	sessionDir := filepath.Join(homeDir, ".k8s-hpa-manager", "sessions")
+	return NewManagerWithDir(filepath.Join(homeDir, ".k8s-hpa-manager", "sessions"))
+}
+
+// NewManagerWithDir cria um gerenciador de sessões usando um diretório específico
+func NewManagerWithDir(sessionDir string) (*Manager, error) {
 	if err := os.MkdirAll(sessionDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create session directory: %w", err)
 	}
@@ -59,6 +63,11 @@ func NewManager() (*Manager, error) {
 	return manager, nil
 }
 
+// SessionDir retorna o diretório raiz das sessões
+func (m *Manager) SessionDir() string {
+	return m.sessionDir
+}
+
 // SaveSession salva uma sessão com nome personalizado
 func (m *Manager) SaveSession(session *models.Session) error {
 	return m.SaveSessionToFolder(session, "")
@@ -190,6 +199,27 @@ func (m *Manager) LoadSessionFromFolder(name string, folder SessionFolder) (*mod
 	return &session, nil
 }
 
+// FindSession carrega uma sessão pelo nome. Com folder vazio, procura na raiz e depois em todas as pastas.
+// Retorna também a pasta onde a sessão foi encontrada.
+func (m *Manager) FindSession(name string, folder SessionFolder) (*models.Session, SessionFolder, error) {
+	if folder != "" {
+		session, err := m.LoadSessionFromFolder(name, folder)
+		return session, folder, err
+	}
+
+	if session, err := m.LoadSession(name); err == nil {
+		return session, "", nil
+	}
+
+	for _, candidate := range m.ListSessionFolders() {
+		if session, err := m.LoadSessionFromFolder(name, candidate); err == nil {
+			return session, candidate, nil
+		}
+	}
+
+	return nil, "", fmt.Errorf("session %s not found", name)
+}
+
 // ListSessions lista todas as sessões salvas
 func (m *Manager) ListSessions() ([]models.Session, error) {
 	files, err := os.ReadDir(m.sessionDir)
@@ -14,6 +14,9 @@ type ClusterConfig struct {
 	Name          string `json:"clusterName"`
 	ResourceGroup string `json:"resourceGroup"`
 	Subscription  string `json:"subscription"`
+	Provider      string `json:"provider,omitempty"` // aks (padrão se vazio), eks ou gke
+	Region        string `json:"region,omitempty"`
+	Project       string `json:"project,omitempty"`
 }
 
 // saveNewCluster salva o novo cluster no arquivo clusters-config.json
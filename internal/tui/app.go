@@ -1,9 +1,9 @@
 package tui
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -18,10 +18,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sClientSet "k8s.io/client-go/kubernetes"
 
+	"k8s-hpa-manager/internal/audit"
 	"k8s-hpa-manager/internal/azure"
 	"k8s-hpa-manager/internal/config"
 	"k8s-hpa-manager/internal/kubernetes"
 	"k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/nodepool"
 	"k8s-hpa-manager/internal/session"
 	"k8s-hpa-manager/internal/tui/components"
 	"k8s-hpa-manager/internal/tui/layout"
@@ -52,6 +54,22 @@ type App struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 
+	// Conflitos de aplicação: HPAs alterados no cluster desde que foram carregados
+	conflictHPAs        []models.HPA
+	conflicts           []session.Conflict
+	conflictResolutions map[string]session.ConflictResolution
+	conflictSelected    int
+
+	// Diff/merge de sessões: base marcada com "d" na seleção de sessões
+	diffBase        *models.Session
+	diffSessions    [2]*models.Session
+	sessionDiff     *session.SessionDiff
+	diffResolutions map[string]session.MergeSide
+	diffSelected    int
+
+	// Audit log compartilhado com web/CLI (nil se indisponível)
+	auditRecorder *audit.Recorder
+
 }
 
 // debugLog imprime mensagens apenas quando debug está habilitado
@@ -124,6 +142,9 @@ func NewApp(kubeconfigPath string, debug bool) *App {
 		tabManager: models.NewTabManager(), // Inicializar TabManager
 	}
 
+	// Audit log append-only (mesmo diretório usado pela interface web e pela CLI)
+	app.auditRecorder = audit.NewRecorder(audit.Default(), audit.SourceTUI, audit.LocalActor())
+
 	// Criar primeira aba com o modelo inicial
 	app.tabManager.AddTab("Principal", "", initialModel)
 
@@ -706,6 +727,13 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return a, nil
 
+	case hpaConflictsMsg:
+		a.conflictHPAs = msg.hpas
+		a.conflicts = msg.conflicts
+		a.conflictResolutions = make(map[string]session.ConflictResolution)
+		a.conflictSelected = 0
+		return a, nil
+
 	case hpaChangesAppliedMsg:
 		if msg.err != nil {
 			a.model.Error = fmt.Sprintf("Failed to apply HPA changes: %v", msg.err)
@@ -771,6 +799,16 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return a, a.clearStatusMessages()
 
+	case sessionsMergedMsg:
+		if msg.err != nil {
+			a.model.Error = fmt.Sprintf("Failed to merge sessions: %v", msg.err)
+			a.model.SuccessMsg = ""
+			return a, a.clearStatusMessages()
+		}
+		a.model.SuccessMsg = fmt.Sprintf("🔀 Sessão '%s' criada a partir do merge", msg.name)
+		a.model.Error = ""
+		return a, tea.Batch(a.loadSessionsFromFolder(a.model.CurrentFolder), a.clearStatusMessages())
+
 	case sessionRenamedMsg:
 		if msg.err != nil {
 			a.model.Error = fmt.Sprintf("Failed to rename session: %v", msg.err)
@@ -1328,6 +1366,14 @@ func (a *App) View() string {
 		content = a.renderModalOverlay(content, a.renderConfirmModal())
 	}
 
+	if len(a.conflicts) > 0 {
+		content = a.renderModalOverlay(content, a.renderConflictModal())
+	}
+
+	if a.sessionDiff != nil {
+		content = a.renderModalOverlay(content, a.renderSessionDiffModal())
+	}
+
 	return content
 }
 
@@ -1362,6 +1408,16 @@ func (a *App) renderModalOverlay(background, modal string) string {
 
 // handleKeyPress processa as teclas pressionadas
 func (a *App) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Se o modal de conflitos estiver ativo, apenas navegação e decisões funcionam
+	if len(a.conflicts) > 0 {
+		return a.handleConflictModalKeys(msg)
+	}
+
+	// Se o diff de sessões estiver aberto, apenas navegação, escolhas e merge funcionam
+	if a.sessionDiff != nil {
+		return a.handleSessionDiffKeys(msg)
+	}
+
 	// Se o modal de confirmação estiver ativo, apenas ENTER e ESC funcionam
 	if a.model.ShowConfirmModal {
 		switch msg.String() {
@@ -1896,6 +1952,11 @@ func (a *App) setupAzureSubscription(clusterName string) error {
 		return fmt.Errorf("failed to find cluster in config: %w", err)
 	}
 
+	// Subscription Azure só se aplica a clusters AKS
+	if nodepool.ProviderName(clusterConfig) != nodepool.ProviderAKS {
+		return nil
+	}
+
 	a.model.StatusContainer.AddInfo("azure-config", fmt.Sprintf("🔄 Configurando subscription para cluster %s: %s", clusterName, clusterConfig.Subscription))
 	a.debugLog("🔄 Setting Azure subscription to: %s\n", clusterConfig.Subscription)
 
@@ -2076,66 +2137,88 @@ func (a *App) applyHPAChanges(hpas []models.HPA) tea.Cmd {
 
 // applyHPAChangesAsync - Aplica mudanças em HPAs com rollouts assíncronos
 func (a *App) applyHPAChangesAsync(hpas []models.HPA) tea.Cmd {
+	return a.applyHPAChangesResolved(hpas, nil)
+}
+
+// applyHPAChangesResolved aplica os HPAs com as decisões do usuário para HPAs em conflito
+// (alterados no cluster desde que foram carregados). Sem decisão, retorna hpaConflictsMsg.
+func (a *App) applyHPAChangesResolved(hpas []models.HPA, resolutions map[string]session.ConflictResolution) tea.Cmd {
 	return func() tea.Msg {
 		if len(hpas) == 0 {
 			return hpaChangesAppliedMsg{count: 0, appliedHPAs: nil, err: nil}
 		}
 
+		// Montar uma sessão com os HPAs e aplicar via session.Applier
+		// (dry-run não é necessário aqui: o Applier aplica e gera a sessão de rollback)
+		applySession := &models.Session{Name: "TUI-Apply", CreatedAt: time.Now()}
+		if a.model.CurrentSession != nil && a.model.CurrentSession.Name != "" {
+			applySession.Name = a.model.CurrentSession.Name
+		}
+		for _, hpa := range hpas {
+			applySession.Changes = append(applySession.Changes, hpaChangeFromHPA(hpa))
+			a.model.StatusContainer.AddInfo("apply-hpa", fmt.Sprintf("⚙️ Aplicando HPA: %s/%s", hpa.Namespace, hpa.Name))
+		}
+
+		// Rollouts são disparados pela TUI para acompanhar o progresso
+		applier := a.newSessionApplier(nil)
+		result := applier.Apply(a.ctx, applySession, session.ApplyOptions{DeferRollouts: true, Resolutions: resolutions})
+		if result.Conflicts > 0 {
+			var conflicts []session.Conflict
+			for _, item := range result.Items {
+				if item.Conflict != nil {
+					conflicts = append(conflicts, *item.Conflict)
+				}
+			}
+			a.model.StatusContainer.AddWarning("apply-hpa", fmt.Sprintf("⚠️ %d HPA(s) alterados no cluster desde que foram carregados - escolha skip/force/rebase", result.Conflicts))
+			return hpaConflictsMsg{hpas: hpas, conflicts: conflicts}
+		}
+
 		successCount := 0
 		var appliedHPAs []models.HPA
 		var lastError error
 
-		// Aplicar mudanças em cada HPA
-		for _, hpa := range hpas {
-			// Obter cliente do cluster
-			client, exists := a.getClient(hpa.Cluster)
-			if !exists {
-				lastError = fmt.Errorf("client not found for cluster %s", hpa.Cluster)
+		for i, item := range result.Items {
+			hpa := hpas[i]
+			if item.Conflict != nil && item.Conflict.Resolution == session.ResolutionSkip {
+				a.model.StatusContainer.AddWarning("apply-hpa", fmt.Sprintf("⏭️ HPA mantido (alterado no cluster): %s/%s", hpa.Namespace, hpa.Name))
 				continue
 			}
-
-			// Logar início da aplicação
-			a.model.StatusContainer.AddInfo("apply-hpa", fmt.Sprintf("⚙️ Aplicando HPA: %s/%s", hpa.Namespace, hpa.Name))
-
-			// Aplicar mudanças no HPA
-			err := client.UpdateHPA(a.ctx, hpa)
-			if err != nil {
-				lastError = err
-				a.model.StatusContainer.AddError("apply-hpa", fmt.Sprintf("❌ Erro ao aplicar HPA %s/%s: %v", hpa.Namespace, hpa.Name, err))
+			switch item.Status {
+			case session.StatusFailed:
+				lastError = errors.New(item.Error)
+				a.model.StatusContainer.AddError("apply-hpa", fmt.Sprintf("❌ Erro ao aplicar HPA %s/%s: %s", hpa.Namespace, hpa.Name, item.Error))
 				continue
-			}
-
-			// Logar alterações no HPA
-			a.logHPAChanges(hpa)
-
-			// Aplicar mudanças nos recursos do deployment se modificados
-			if hpa.ResourcesModified {
-				err = client.ApplyHPADeploymentResourceChanges(a.ctx, &hpa)
-				if err != nil {
-					lastError = fmt.Errorf("failed to apply deployment resources: %w", err)
-					a.model.StatusContainer.AddError("apply-resources", fmt.Sprintf("❌ Erro ao aplicar recursos do deployment: %v", err))
-					continue
+			case session.StatusApplied:
+				// Logar alterações no HPA e nos recursos do deployment
+				a.logHPAChanges(hpa)
+				if hpa.ResourcesModified {
+					a.logResourceChanges(hpa)
 				}
-				// Logar alterações nos recursos
-				a.logResourceChanges(hpa)
 			}
 
 			// HPA aplicado com sucesso - incrementar contador de aplicações
 			now := time.Now()
 			hpa.AppliedCount++
 			hpa.LastAppliedAt = &now
+			hpa.ResourcesModified = false
 
 			a.model.StatusContainer.AddSuccess("apply-hpa", fmt.Sprintf("✅ HPA aplicado: %s/%s", hpa.Namespace, hpa.Name))
 
 			// Iniciar rollouts assíncronos se solicitados
 			if hpa.PerformRollout || hpa.PerformDaemonSetRollout || hpa.PerformStatefulSetRollout {
-				a.startAsyncRollouts(hpa, client)
+				if client, exists := a.getClient(hpa.Cluster); exists {
+					a.startAsyncRollouts(hpa, client)
+				}
 			}
 
 			successCount++
 			appliedHPAs = append(appliedHPAs, hpa)
 		}
 
+		if result.RollbackSession != "" {
+			a.model.StatusContainer.AddInfo("apply-hpa", fmt.Sprintf("↩️ Sessão de rollback salva: %s/%s", session.FolderRollback, result.RollbackSession))
+		}
+
 		// Se houve falhas, reportar erro
 		if successCount < len(hpas) {
 			return hpaChangesAppliedMsg{
@@ -2154,6 +2237,251 @@ func (a *App) applyHPAChangesAsync(hpas []models.HPA) tea.Cmd {
 	}
 }
 
+// handleConflictModalKeys processa as teclas do modal de conflitos:
+// ↑↓ navega, s/f/r decide o HPA selecionado, S/F/R decide todos, ENTER aplica, ESC cancela
+func (a *App) handleConflictModalKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	decide := func(resolution session.ConflictResolution, all bool) {
+		for i, conflict := range a.conflicts {
+			if all || i == a.conflictSelected {
+				a.conflictResolutions[conflict.Key] = resolution
+			}
+		}
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if a.conflictSelected > 0 {
+			a.conflictSelected--
+		}
+	case "down", "j":
+		if a.conflictSelected < len(a.conflicts)-1 {
+			a.conflictSelected++
+		}
+	case "s":
+		decide(session.ResolutionSkip, false)
+	case "f":
+		decide(session.ResolutionForce, false)
+	case "r":
+		decide(session.ResolutionRebase, false)
+	case "S":
+		decide(session.ResolutionSkip, true)
+	case "F":
+		decide(session.ResolutionForce, true)
+	case "R":
+		decide(session.ResolutionRebase, true)
+	case "enter":
+		for _, conflict := range a.conflicts {
+			if a.conflictResolutions[conflict.Key] == "" {
+				a.model.StatusContainer.AddWarning("apply-hpa", fmt.Sprintf("⚠️ Escolha skip/force/rebase para %s/%s", conflict.Namespace, conflict.Name))
+				return a, nil
+			}
+		}
+		hpas, resolutions := a.conflictHPAs, a.conflictResolutions
+		a.clearConflicts()
+		return a, a.applyHPAChangesResolved(hpas, resolutions)
+	case "esc":
+		a.clearConflicts()
+		a.model.StatusContainer.AddInfo("cancel", "❌ Aplicação cancelada - HPAs em conflito não foram alterados")
+	}
+	return a, nil
+}
+
+func (a *App) clearConflicts() {
+	a.conflictHPAs = nil
+	a.conflicts = nil
+	a.conflictResolutions = nil
+	a.conflictSelected = 0
+}
+
+// toggleSessionDiff marca a sessão selecionada como base do diff ou, se já houver base,
+// abre o diff entre a base e a sessão selecionada
+func (a *App) toggleSessionDiff(selected models.Session) {
+	if a.diffBase == nil {
+		a.diffBase = &selected
+		a.model.StatusContainer.AddInfo("session-diff", fmt.Sprintf("🔀 '%s' marcada para comparação - pressione D em outra sessão", selected.Name))
+		return
+	}
+	if a.diffBase.Name == selected.Name {
+		a.diffBase = nil
+		a.model.StatusContainer.AddInfo("session-diff", "🔀 Comparação cancelada")
+		return
+	}
+
+	a.diffSessions = [2]*models.Session{a.diffBase, &selected}
+	a.sessionDiff = session.Diff(a.diffBase, &selected)
+	a.diffResolutions = make(map[string]session.MergeSide)
+	a.diffSelected = 0
+	a.diffBase = nil
+}
+
+// diffConflicts itens presentes nas duas sessões com valores diferentes (exigem escolha no merge)
+func (a *App) diffConflicts() []session.ItemDiff {
+	var conflicts []session.ItemDiff
+	for _, item := range a.sessionDiff.Items {
+		if item.Change == session.DiffChanged {
+			conflicts = append(conflicts, item)
+		}
+	}
+	return conflicts
+}
+
+// handleSessionDiffKeys processa as teclas do diff de sessões:
+// ↑↓ navega entre itens em conflito, a/b escolhe o lado, A/B escolhe para todos, M faz o merge, ESC fecha
+func (a *App) handleSessionDiffKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	conflicts := a.diffConflicts()
+	choose := func(side session.MergeSide, all bool) {
+		for i, item := range conflicts {
+			if all || i == a.diffSelected {
+				a.diffResolutions[item.Key] = side
+			}
+		}
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if a.diffSelected > 0 {
+			a.diffSelected--
+		}
+	case "down", "j":
+		if a.diffSelected < len(conflicts)-1 {
+			a.diffSelected++
+		}
+	case "a":
+		choose(session.MergeSideA, false)
+	case "b":
+		choose(session.MergeSideB, false)
+	case "A":
+		choose(session.MergeSideA, true)
+	case "B":
+		choose(session.MergeSideB, true)
+	case "m", "M":
+		for _, item := range conflicts {
+			if a.diffResolutions[item.Key] == "" {
+				a.model.StatusContainer.AddWarning("session-diff", fmt.Sprintf("⚠️ Escolha a ou b para %s", item.Key))
+				return a, nil
+			}
+		}
+		sessions, resolutions := a.diffSessions, a.diffResolutions
+		a.clearSessionDiff()
+		return a, a.mergeSessions(sessions[0], sessions[1], resolutions, a.model.CurrentFolder)
+	case "esc":
+		a.clearSessionDiff()
+	}
+	return a, nil
+}
+
+func (a *App) clearSessionDiff() {
+	a.diffSessions = [2]*models.Session{}
+	a.sessionDiff = nil
+	a.diffResolutions = nil
+	a.diffSelected = 0
+}
+
+// mergeSessions combina as duas sessões e salva o resultado na pasta atual
+func (a *App) mergeSessions(sessionA, sessionB *models.Session, resolutions map[string]session.MergeSide, folderName string) tea.Cmd {
+	return func() tea.Msg {
+		if a.sessionManager == nil {
+			return sessionsMergedMsg{err: fmt.Errorf("session manager not initialized")}
+		}
+
+		now := time.Now()
+		name := "merge_" + now.Format("02-01-06_150405")
+		result, err := session.Merge(sessionA, sessionB, session.MergeOptions{Name: name, Resolutions: resolutions}, now)
+		if err != nil {
+			return sessionsMergedMsg{name: name, err: err}
+		}
+
+		if err := a.sessionManager.SaveSessionToFolder(result.Session, session.SessionFolder(folderName)); err != nil {
+			return sessionsMergedMsg{name: name, err: err}
+		}
+		return sessionsMergedMsg{name: name}
+	}
+}
+
+// newSessionApplier cria o session.Applier da TUI usando os clients já conectados
+// e o provider de node pools configurado
+func (a *App) newSessionApplier(progress func(session.ItemResult)) *session.Applier {
+	return session.NewApplier(session.ApplierOptions{
+		Clients: func(cluster string) (session.KubeClient, error) {
+			client, exists := a.getClient(cluster)
+			if !exists {
+				return nil, fmt.Errorf("client not found for cluster %s", cluster)
+			}
+			return client, nil
+		},
+		NodePools: nodePoolProvider,
+		ResolveCluster: func(change *models.NodePoolChange) nodepool.ClusterRef {
+			if clusterConfig, err := findClusterInConfig(change.Cluster); err == nil {
+				return nodepool.ClusterRefFromConfig(clusterConfig)
+			}
+			return nodepool.ClusterRef{
+				Provider:      nodepool.ProviderAKS,
+				Name:          strings.TrimSuffix(change.Cluster, "-admin"),
+				ResourceGroup: change.ResourceGroup,
+				Subscription:  change.Subscription,
+			}
+		},
+		Manager:  a.sessionManager,
+		Progress: progress,
+		Audit:    a.auditRecorder,
+	})
+}
+
+// hpaChangeFromHPA converte um HPA editado em HPAChange (valores originais + novos)
+func hpaChangeFromHPA(hpa models.HPA) models.HPAChange {
+	// Usar valores originais se existirem, senão usar valores atuais
+	originalValues := hpa.OriginalValues
+	if originalValues == nil {
+		// Se não há OriginalValues, significa que nunca foi modificado
+		// Então os valores atuais SÃO os originais
+		originalValues = &models.HPAValues{
+			MinReplicas:  hpa.MinReplicas,
+			MaxReplicas:  hpa.MaxReplicas,
+			TargetCPU:    hpa.TargetCPU,
+			TargetMemory: hpa.TargetMemory,
+			Metrics:      models.CloneHPAMetrics(hpa.Metrics),
+			Behavior:     hpa.Behavior.Clone(),
+
+			DeploymentName: hpa.DeploymentName,
+			CPURequest:     hpa.TargetCPURequest,
+			CPULimit:       hpa.TargetCPULimit,
+			MemoryRequest:  hpa.TargetMemoryRequest,
+			MemoryLimit:    hpa.TargetMemoryLimit,
+		}
+	}
+
+	return models.HPAChange{
+		Cluster:        hpa.Cluster,
+		Namespace:      hpa.Namespace,
+		HPAName:        hpa.Name,
+		OriginalValues: originalValues,
+		NewValues: &models.HPAValues{
+			MinReplicas:  hpa.MinReplicas,
+			MaxReplicas:  hpa.MaxReplicas,
+			TargetCPU:    hpa.TargetCPU,
+			TargetMemory: hpa.TargetMemory,
+			Metrics:      models.CloneHPAMetrics(hpa.Metrics),
+			Behavior:     hpa.Behavior.Clone(),
+
+			// Rollout Options
+			PerformRollout:            hpa.PerformRollout,
+			PerformDaemonSetRollout:   hpa.PerformDaemonSetRollout,
+			PerformStatefulSetRollout: hpa.PerformStatefulSetRollout,
+
+			// Recursos do deployment
+			DeploymentName: hpa.DeploymentName,
+			CPURequest:     hpa.TargetCPURequest,
+			CPULimit:       hpa.TargetCPULimit,
+			MemoryRequest:  hpa.TargetMemoryRequest,
+			MemoryLimit:    hpa.TargetMemoryLimit,
+		},
+		Applied:                     false,
+		RolloutTriggered:            hpa.PerformRollout,
+		DaemonSetRolloutTriggered:   hpa.PerformDaemonSetRollout,
+		StatefulSetRolloutTriggered: hpa.PerformStatefulSetRollout,
+	}
+}
+
 // logHPAChanges - Loga alterações feitas no HPA
 func (a *App) logHPAChanges(hpa models.HPA) {
 	if hpa.OriginalValues == nil {
@@ -2430,6 +2758,7 @@ func (a *App) executeRollout(progressID string, hpa models.HPA, rolloutType stri
 
 	err := cmd.Start()
 	if err != nil {
+		a.auditRollout(hpa, rolloutType, err)
 		updateProgress(models.RolloutStatusFailed, 0, "Falha na execução", err.Error())
 		return
 	}
@@ -2442,6 +2771,7 @@ func (a *App) executeRollout(progressID string, hpa models.HPA, rolloutType stri
 
 	// Aguardar conclusão do comando
 	err = cmd.Wait()
+	a.auditRollout(hpa, rolloutType, err)
 
 	if err != nil {
 		updateProgress(models.RolloutStatusFailed, 0, "Rollout falhou", err.Error())
@@ -2552,8 +2882,8 @@ func (a *App) applyNodePoolChanges(nodePools []models.NodePool) tea.Cmd {
 			// Logar alterações que serão aplicadas
 			a.logNodePoolChanges(pool)
 
-			// Executar comando Azure CLI para update do node pool
-			err := a.updateNodePoolViaAzureCLI(pool)
+			// Aplicar update do node pool via API do Azure
+			err := a.updateNodePoolViaProvider(pool)
 			if err != nil {
 				// Atualizar progress para falha
 				a.updateNodePoolProgress(pool.Name, models.RolloutStatusFailed, 100, "Falha na aplicação", err.Error())
@@ -2616,53 +2946,7 @@ func (a *App) saveSession(sessionParam *models.Session) tea.Cmd {
 		for _, hpa := range a.model.SelectedHPAs {
 			clustersMap[hpa.Cluster] = true
 
-			// Usar valores originais se existirem, senão usar valores atuais
-			originalValues := hpa.OriginalValues
-			if originalValues == nil {
-				// Se não há OriginalValues, significa que nunca foi modificado
-				// Então os valores atuais SÃO os originais
-				originalValues = &models.HPAValues{
-					MinReplicas:  hpa.MinReplicas,
-					MaxReplicas:  hpa.MaxReplicas,
-					TargetCPU:    hpa.TargetCPU,
-					TargetMemory: hpa.TargetMemory,
-
-					DeploymentName: hpa.DeploymentName,
-					CPURequest:     hpa.TargetCPURequest,
-					CPULimit:       hpa.TargetCPULimit,
-					MemoryRequest:  hpa.TargetMemoryRequest,
-					MemoryLimit:    hpa.TargetMemoryLimit,
-				}
-			}
-
-			change := models.HPAChange{
-				Cluster:        hpa.Cluster,
-				Namespace:      hpa.Namespace,
-				HPAName:        hpa.Name,
-				OriginalValues: originalValues,
-				NewValues: &models.HPAValues{
-					MinReplicas:  hpa.MinReplicas,
-					MaxReplicas:  hpa.MaxReplicas,
-					TargetCPU:    hpa.TargetCPU,
-					TargetMemory: hpa.TargetMemory,
-
-					// Rollout Options
-					PerformRollout:            hpa.PerformRollout,
-					PerformDaemonSetRollout:   hpa.PerformDaemonSetRollout,
-					PerformStatefulSetRollout: hpa.PerformStatefulSetRollout,
-
-					// Recursos do deployment
-					DeploymentName: hpa.DeploymentName,
-					CPURequest:     hpa.TargetCPURequest,
-					CPULimit:       hpa.TargetCPULimit,
-					MemoryRequest:  hpa.TargetMemoryRequest,
-					MemoryLimit:    hpa.TargetMemoryLimit,
-				},
-				Applied:                     false,
-				RolloutTriggered:            hpa.PerformRollout,
-				DaemonSetRolloutTriggered:   hpa.PerformDaemonSetRollout,
-				StatefulSetRolloutTriggered: hpa.PerformStatefulSetRollout,
-			}
+			change := hpaChangeFromHPA(hpa)
 			fullSession.Changes = append(fullSession.Changes, change)
 		}
 
@@ -3019,6 +3303,8 @@ func (a *App) applySessionChanges(session *models.Session) tea.Cmd {
 				MaxReplicas:  change.NewValues.MaxReplicas,
 				TargetCPU:    change.NewValues.TargetCPU,
 				TargetMemory: change.NewValues.TargetMemory,
+				Metrics:      change.NewValues.Metrics,
+				Behavior:     change.NewValues.Behavior,
 			}
 
 			// Aplicar mudanças no HPA
@@ -3137,6 +3423,8 @@ func (a *App) loadHPASessionState(session *models.Session) tea.Msg {
 			MaxReplicas:               change.NewValues.MaxReplicas,
 			TargetCPU:                 change.NewValues.TargetCPU,
 			TargetMemory:              change.NewValues.TargetMemory,
+			Metrics:                   models.CloneHPAMetrics(change.NewValues.Metrics),
+			Behavior:                  change.NewValues.Behavior.Clone(),
 			PerformRollout:            change.RolloutTriggered,
 			PerformDaemonSetRollout:   change.DaemonSetRolloutTriggered,
 			PerformStatefulSetRollout: change.StatefulSetRolloutTriggered,
@@ -3227,28 +3515,26 @@ func (a *App) loadNodePoolSessionState(session *models.Session) tea.Msg {
 		return sessionStateLoadedMsg{err: fmt.Errorf("failed to find cluster config for %s: %w", targetCluster, err)}
 	}
 
-	// Configurar contexto Azure com a subscription do cluster
-	if err := a.setupAzureContext(clusterConfig.Subscription); err != nil {
-		return sessionStateLoadedMsg{err: fmt.Errorf("failed to setup Azure context: %w", err)}
+	clusterRef := nodepool.ClusterRefFromConfig(clusterConfig)
+
+	// Configurar contexto Azure com a subscription do cluster (apenas AKS)
+	if clusterRef.Provider == nodepool.ProviderAKS {
+		if err := a.setupAzureContext(clusterConfig.Subscription); err != nil {
+			return sessionStateLoadedMsg{err: fmt.Errorf("failed to setup Azure context: %w", err)}
+		}
 	}
 
 	// Carregar node pools atuais do cluster
 	a.debugLog("🔄 Carregando node pools do cluster %s...\n", targetCluster)
+	a.debugLog("📋 Carregando node pools: %s\n", clusterRef)
 
-	// Normalizar nome do cluster para Azure CLI (remover -admin se existir)
-	clusterNameForAzure := strings.TrimSuffix(targetCluster, "-admin")
-
-	// Carregar node pools via Azure CLI
-	a.debugLog("📋 Carregando node pools: cluster=%s, resourceGroup=%s, subscription=%s\n",
-		clusterNameForAzure, clusterConfig.ResourceGroup, clusterConfig.Subscription)
-
-	nodePools, err := loadNodePoolsFromAzure(clusterNameForAzure, clusterConfig.ResourceGroup, clusterConfig.Subscription)
+	nodePools, err := loadNodePoolsFromProvider(clusterRef)
 	if err != nil {
 		a.debugLog("❌ Erro ao carregar node pools: %v\n", err)
 		return sessionStateLoadedMsg{err: fmt.Errorf("failed to load node pools: %w", err)}
 	}
 
-	a.debugLog("📊 Carregados %d node pools do Azure\n", len(nodePools))
+	a.debugLog("📊 Carregados %d node pools (%s)\n", len(nodePools), clusterRef.Provider)
 
 	// Aplicar as modificações da sessão aos node pools carregados
 	var sessionNodePools []models.NodePool
@@ -3352,6 +3638,14 @@ func (a *App) getCurrentFieldValue(fieldName string) string {
 		}
 		return "false"
 
+	case models.FieldMetrics:
+		return kubernetes.FormatMetrics(hpa.Metrics)
+
+	// Campos de behavior (scaleUp/scaleDown)
+	case models.FieldScaleUpWindow, models.FieldScaleUpSelect, models.FieldScaleUpPolicies,
+		models.FieldScaleDownWindow, models.FieldScaleDownSelect, models.FieldScaleDownPolicies:
+		return getBehaviorFieldValue(hpa.Behavior, fieldName)
+
 	// Campos de recursos do deployment
 	case "deployment_cpu_request":
 		if hpa.TargetCPURequest != "" {
@@ -3414,6 +3708,9 @@ func (a *App) applyFieldValue(fieldName, value string) error {
 			}
 			cpuVal := int32(val)
 			hpa.TargetCPU = &cpuVal
+			if hpa.Metrics != nil {
+				hpa.Metrics = kubernetes.SetResourceUtilization(models.CloneHPAMetrics(hpa.Metrics), "cpu", cpuVal)
+			}
 		}
 	case "target_memory":
 		if value == "" {
@@ -3425,11 +3722,29 @@ func (a *App) applyFieldValue(fieldName, value string) error {
 			}
 			memVal := int32(val)
 			hpa.TargetMemory = &memVal
+			if hpa.Metrics != nil {
+				hpa.Metrics = kubernetes.SetResourceUtilization(models.CloneHPAMetrics(hpa.Metrics), "memory", memVal)
+			}
 		}
 	case "rollout":
 		lowerValue := strings.ToLower(value)
 		hpa.PerformRollout = (lowerValue == "true" || lowerValue == "t" || lowerValue == "yes" || lowerValue == "y" || lowerValue == "1")
 
+	case models.FieldMetrics:
+		metrics, err := kubernetes.ParseMetrics(value)
+		if err != nil {
+			return err
+		}
+		hpa.Metrics = metrics
+		// Manter Target CPU/Memory coerentes com a lista (UpdateHPA aplica ambos)
+		hpa.TargetCPU = kubernetes.ResourceUtilization(metrics, "cpu")
+		hpa.TargetMemory = kubernetes.ResourceUtilization(metrics, "memory")
+
+	// Campos de behavior (scaleUp/scaleDown)
+	case models.FieldScaleUpWindow, models.FieldScaleUpSelect, models.FieldScaleUpPolicies,
+		models.FieldScaleDownWindow, models.FieldScaleDownSelect, models.FieldScaleDownPolicies:
+		return applyBehaviorFieldValue(hpa, fieldName, value)
+
 	// Campos de recursos do deployment
 	case "deployment_cpu_request":
 		hpa.TargetCPURequest = value
@@ -3448,6 +3763,96 @@ func (a *App) applyFieldValue(fieldName, value string) error {
 	return nil
 }
 
+// getBehaviorFieldValue retorna o valor de um campo de behavior no formato de edição
+func getBehaviorFieldValue(behavior *models.HPABehavior, fieldName string) string {
+	if behavior == nil {
+		return ""
+	}
+
+	rules := behavior.ScaleDown
+	if strings.HasPrefix(fieldName, "scale_up_") {
+		rules = behavior.ScaleUp
+	}
+	if rules == nil {
+		return ""
+	}
+
+	switch fieldName {
+	case models.FieldScaleUpWindow, models.FieldScaleDownWindow:
+		if rules.StabilizationWindowSeconds != nil {
+			return fmt.Sprintf("%d", *rules.StabilizationWindowSeconds)
+		}
+	case models.FieldScaleUpSelect, models.FieldScaleDownSelect:
+		if rules.SelectPolicy != nil {
+			return *rules.SelectPolicy
+		}
+	case models.FieldScaleUpPolicies, models.FieldScaleDownPolicies:
+		return kubernetes.FormatScalingPolicies(rules.Policies)
+	}
+	return ""
+}
+
+// applyBehaviorFieldValue aplica um campo de behavior editado no HPA.
+// Valor vazio remove o campo (o Kubernetes volta a usar o padrão).
+func applyBehaviorFieldValue(hpa *models.HPA, fieldName, value string) error {
+	value = strings.TrimSpace(value)
+
+	// Trabalhar sobre uma cópia para não alterar OriginalValues por acidente
+	behavior := hpa.Behavior.Clone()
+	if behavior == nil {
+		behavior = &models.HPABehavior{}
+	}
+
+	rulesPtr := &behavior.ScaleDown
+	if strings.HasPrefix(fieldName, "scale_up_") {
+		rulesPtr = &behavior.ScaleUp
+	}
+	if *rulesPtr == nil {
+		*rulesPtr = &models.HPAScalingRules{}
+	}
+	rules := *rulesPtr
+
+	switch fieldName {
+	case models.FieldScaleUpWindow, models.FieldScaleDownWindow:
+		if value == "" {
+			rules.StabilizationWindowSeconds = nil
+		} else {
+			val, err := strconv.ParseInt(value, 10, 32)
+			if err != nil {
+				return err
+			}
+			window := int32(val)
+			rules.StabilizationWindowSeconds = &window
+		}
+	case models.FieldScaleUpSelect, models.FieldScaleDownSelect:
+		if value == "" {
+			rules.SelectPolicy = nil
+		} else {
+			// Aceitar max/min/disabled em qualquer caixa
+			selectPolicy := strings.ToUpper(value[:1]) + strings.ToLower(value[1:])
+			rules.SelectPolicy = &selectPolicy
+		}
+	case models.FieldScaleUpPolicies, models.FieldScaleDownPolicies:
+		policies, err := kubernetes.ParseScalingPolicies(value)
+		if err != nil {
+			return err
+		}
+		rules.Policies = policies
+	}
+
+	// Direção sem nenhum campo volta ao padrão do Kubernetes
+	if rules.StabilizationWindowSeconds == nil && rules.SelectPolicy == nil && len(rules.Policies) == 0 {
+		*rulesPtr = nil
+	}
+
+	if err := kubernetes.ValidateBehavior(behavior); err != nil {
+		return err
+	}
+
+	hpa.Behavior = behavior
+	return nil
+}
+
 // handleHelpKeys - Navegação na tela de ajuda
 func (a *App) handleHelpKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
@@ -3477,8 +3882,8 @@ func (a *App) handleHelpKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return a, nil
 }
 
-// updateNodePoolViaAzureCLI atualiza um node pool via Azure CLI
-func (a *App) updateNodePoolViaAzureCLI(pool models.NodePool) error {
+// updateNodePoolViaProvider atualiza um node pool via provider do cluster (AKS, EKS ou GKE)
+func (a *App) updateNodePoolViaProvider(pool models.NodePool) error {
 	// Primeiro, verificar se há mudanças para aplicar
 	if !pool.Modified {
 		return nil
@@ -3487,349 +3892,53 @@ func (a *App) updateNodePoolViaAzureCLI(pool models.NodePool) error {
 	// Etapa 1: Validação inicial (5% -> 15%)
 	a.updateNodePoolProgress(pool.Name, models.RolloutStatusRunning, 15, "Validando configurações...", "")
 
-	// Normalizar nome do cluster para Azure CLI (remover -admin se existir)
-	clusterNameForAzure := strings.TrimSuffix(pool.ClusterName, "-admin")
-
-	// Etapa 2: Preparando comandos (15% -> 25%)
-	a.updateNodePoolProgress(pool.Name, models.RolloutStatusRunning, 25, "Preparando comandos Azure CLI...", "")
-
-	// Construir comandos Azure CLI baseados nas mudanças
-	// IMPORTANTE: Ordem correta para evitar conflitos:
-	// 1. Se mudou de auto→manual: PRIMEIRO desabilita autoscaling, DEPOIS faz scale
-	// 2. Se mudou de manual→auto: PRIMEIRO faz scale (se necessário), DEPOIS habilita autoscaling
-	// 3. Se permaneceu auto: Atualiza min/max
-	// 4. Se permaneceu manual: Faz scale
-	var cmds [][]string
-
-	// Detectar transição de autoscaling
-	changingToManual := pool.OriginalValues.AutoscalingEnabled && !pool.AutoscalingEnabled
-	changingToAuto := !pool.OriginalValues.AutoscalingEnabled && pool.AutoscalingEnabled
-	staysAuto := pool.OriginalValues.AutoscalingEnabled && pool.AutoscalingEnabled
-	staysManual := !pool.OriginalValues.AutoscalingEnabled && !pool.AutoscalingEnabled
-
-	// CENÁRIO 1: Mudou de AUTO → MANUAL (precisa desabilitar autoscaling PRIMEIRO)
-	if changingToManual {
-		// Passo 1: Desabilitar autoscaling
-		cmd := []string{
-			"az", "aks", "nodepool", "update",
-			"--disable-cluster-autoscaler",
-			"--resource-group", pool.ResourceGroup,
-			"--cluster-name", clusterNameForAzure,
-			"--name", pool.Name,
-		}
-		if pool.Subscription != "" {
-			cmd = append(cmd, "--subscription", pool.Subscription)
-		}
-		cmds = append(cmds, cmd)
-
-		// Passo 2: Se NodeCount mudou, fazer scale manual
-		if pool.NodeCount != pool.OriginalValues.NodeCount {
-			cmd := []string{
-				"az", "aks", "nodepool", "scale",
-				"--resource-group", pool.ResourceGroup,
-				"--cluster-name", clusterNameForAzure,
-				"--name", pool.Name,
-				"--node-count", fmt.Sprintf("%d", pool.NodeCount),
-			}
-			if pool.Subscription != "" {
-				cmd = append(cmd, "--subscription", pool.Subscription)
-			}
-			cmds = append(cmds, cmd)
-		}
+	// Resolver o cluster no clusters-config.json (provider, região, projeto);
+	// sem entrada na configuração, assumir AKS com os dados do próprio node pool
+	clusterRef := nodepool.ClusterRef{
+		Provider:      nodepool.NormalizeProvider(pool.Provider),
+		Name:          strings.TrimSuffix(pool.ClusterName, "-admin"),
+		ResourceGroup: pool.ResourceGroup,
+		Subscription:  pool.Subscription,
 	}
-
-	// CENÁRIO 2: Mudou de MANUAL → AUTO
-	if changingToAuto {
-		// Passo 1: Se NodeCount mudou, fazer scale manual ANTES de habilitar autoscaling
-		if pool.NodeCount != pool.OriginalValues.NodeCount {
-			cmd := []string{
-				"az", "aks", "nodepool", "scale",
-				"--resource-group", pool.ResourceGroup,
-				"--cluster-name", clusterNameForAzure,
-				"--name", pool.Name,
-				"--node-count", fmt.Sprintf("%d", pool.NodeCount),
-			}
-			if pool.Subscription != "" {
-				cmd = append(cmd, "--subscription", pool.Subscription)
-			}
-			cmds = append(cmds, cmd)
-		}
-
-		// Passo 2: Habilitar autoscaling com min/max
-		cmd := []string{
-			"az", "aks", "nodepool", "update",
-			"--enable-cluster-autoscaler",
-			"--min-count", fmt.Sprintf("%d", pool.MinNodeCount),
-			"--max-count", fmt.Sprintf("%d", pool.MaxNodeCount),
-			"--resource-group", pool.ResourceGroup,
-			"--cluster-name", clusterNameForAzure,
-			"--name", pool.Name,
-		}
-		if pool.Subscription != "" {
-			cmd = append(cmd, "--subscription", pool.Subscription)
-		}
-		cmds = append(cmds, cmd)
-	}
-
-	// CENÁRIO 3: Permaneceu AUTO - atualizar min/max se mudou
-	if staysAuto {
-		if pool.MinNodeCount != pool.OriginalValues.MinNodeCount || pool.MaxNodeCount != pool.OriginalValues.MaxNodeCount {
-			cmd := []string{
-				"az", "aks", "nodepool", "update",
-				"--update-cluster-autoscaler",
-				"--min-count", fmt.Sprintf("%d", pool.MinNodeCount),
-				"--max-count", fmt.Sprintf("%d", pool.MaxNodeCount),
-				"--resource-group", pool.ResourceGroup,
-				"--cluster-name", clusterNameForAzure,
-				"--name", pool.Name,
-			}
-			if pool.Subscription != "" {
-				cmd = append(cmd, "--subscription", pool.Subscription)
-			}
-			cmds = append(cmds, cmd)
-		}
+	if clusterConfig, err := findClusterInConfig(pool.ClusterName); err == nil {
+		clusterRef = nodepool.ClusterRefFromConfig(clusterConfig)
 	}
 
-	// CENÁRIO 4: Permaneceu MANUAL - fazer scale se mudou
-	if staysManual {
-		if pool.NodeCount != pool.OriginalValues.NodeCount {
-			cmd := []string{
-				"az", "aks", "nodepool", "scale",
-				"--resource-group", pool.ResourceGroup,
-				"--cluster-name", clusterNameForAzure,
-				"--name", pool.Name,
-				"--node-count", fmt.Sprintf("%d", pool.NodeCount),
-			}
-			if pool.Subscription != "" {
-				cmd = append(cmd, "--subscription", pool.Subscription)
-			}
-			cmds = append(cmds, cmd)
-		}
-	}
+	// Etapa 2: Planejando operações (15% -> 25%)
+	// A ordem dos passos (auto→manual, manual→auto, etc) é definida por nodepool.Plan
+	a.updateNodePoolProgress(pool.Name, models.RolloutStatusRunning, 25, "Planejando operações...", "")
+	steps := nodepool.Plan(pool.OriginalValues, nodepool.ValuesOf(&pool))
 
-	// Se não há comandos para executar, não há mudanças
-	if len(cmds) == 0 {
+	// Se não há passos para executar, não há mudanças
+	if len(steps) == 0 {
 		return nil
 	}
 
-	// Executar todos os comandos com progress tracking mais granular (30% -> 90%)
-	totalCmds := len(cmds)
-	for i, cmd := range cmds {
-		// Progresso usando StatusPanel
-		startProgress := 30 + (i * 60 / totalCmds)
-		a.updateNodePoolProgress(pool.Name, models.RolloutStatusRunning, startProgress, fmt.Sprintf("Iniciando comando %d/%d...", i+1, totalCmds), "")
-
-		// Progresso durante execução
-		midProgress := 30 + ((i*60 + 20) / totalCmds)
-		a.updateNodePoolProgress(pool.Name, models.RolloutStatusRunning, midProgress, fmt.Sprintf("Executando comando %d/%d...", i+1, totalCmds), "")
-
-		err := a.executeAzureCommand(cmd)
-		if err != nil {
-			a.updateNodePoolProgress(pool.Name, models.RolloutStatusFailed, 100, "Falha na execução", err.Error())
-			return fmt.Errorf("failed to update node pool %s: %w", pool.Name, err)
-		}
-
-		// Progresso final do comando
-		endProgress := 30 + ((i + 1) * 60 / totalCmds)
-		a.updateNodePoolProgress(pool.Name, models.RolloutStatusRunning, endProgress, fmt.Sprintf("Comando %d/%d concluído", i+1, totalCmds), "")
+	// Executar todos os passos com progress tracking (30% -> 90%)
+	progress := func(step, total int, description string) {
+		startProgress := 30 + ((step - 1) * 60 / total)
+		a.updateNodePoolProgress(pool.Name, models.RolloutStatusRunning, startProgress, fmt.Sprintf("Passo %d/%d: %s...", step, total, description), "")
+		a.model.StatusContainer.AddInfo("azure-api", fmt.Sprintf("🚀 %s: %s", pool.Name, description))
 	}
 
-	// Progresso final antes de completar
-	a.updateNodePoolProgress(pool.Name, models.RolloutStatusRunning, 95, "Finalizando operação...", "")
-	return nil
-}
-
-// executeAzureCommand executa um comando Azure CLI
-func (a *App) executeAzureCommand(cmdArgs []string) error {
-	// statusPanel direct access
-	cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
-
-	// Extrair operação para log mais legível
-	operation := "operação Azure"
-	if len(cmdArgs) >= 4 {
-		operation = fmt.Sprintf("%s %s", cmdArgs[2], cmdArgs[3]) // ex: "nodepool scale"
-	}
-
-	a.model.StatusContainer.AddInfo("azure-cli", fmt.Sprintf("🚀 Executando %s via Azure CLI", operation))
-
-	// Log comando completo para debug (apenas em debug mode)
-	if a.debug {
-		a.debugLog("🚀 Running command: %s %s", cmdArgs[0], strings.Join(cmdArgs[1:], " "))
-	}
-
-	// Separar stdout e stderr para tratar warnings adequadamente
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-
-	stdoutStr := strings.TrimSpace(stdout.String())
-	stderrStr := strings.TrimSpace(stderr.String())
-
-	// Warnings conhecidos que devem ser ignorados (não são erros!)
-	knownWarnings := []string{
-		"UserWarning: pkg_resources is deprecated",
-		"The behavior of this command has been altered by the following extension",
-		"__import__('pkg_resources').declare_namespace(__name__)",
-	}
-
-	// Função helper para verificar se stderr contém apenas warnings
-	isOnlyWarnings := func(stderr string) bool {
-		if stderr == "" {
-			return true // Sem stderr = sem problemas
-		}
-
-		lines := strings.Split(stderr, "\n")
-		for _, line := range lines {
-			trimmed := strings.TrimSpace(line)
-			if trimmed == "" {
-				continue // Ignorar linhas vazias
-			}
-
-			// Verificar se linha contém warning conhecido
-			isWarning := false
-			for _, warning := range knownWarnings {
-				if strings.Contains(trimmed, warning) {
-					isWarning = true
-					break
-				}
-			}
-
-			// Se encontrou linha que NÃO é warning conhecido, é erro real
-			if !isWarning && !strings.Contains(trimmed, "WARNING:") {
-				return false
-			}
-		}
-		return true // Todas as linhas são warnings
-	}
-
-	// Verificar se houve erro REAL (não apenas warnings)
+	err := nodepool.Execute(a.ctx, nodePoolProvider, clusterRef, pool.Name, steps, progress)
+	a.auditRecorder.Record(audit.Event{
+		Action:   audit.ActionApplyNodePool,
+		Cluster:  pool.ClusterName,
+		Resource: pool.ResourceGroup + "/" + pool.Name,
+		Before:   pool.OriginalValues,
+		After:    nodepool.ValuesOf(&pool),
+		Err:      err,
+	})
 	if err != nil {
-		// Se stderr contém apenas warnings, ignorar o "erro"
-		if isOnlyWarnings(stderrStr) {
-			// Log warnings em debug mode, mas não tratar como erro
-			if a.debug && stderrStr != "" {
-				a.debugLog("⚠️ Warnings ignorados:\n%s", stderrStr)
-			}
-			// Continuar normalmente - comando foi bem-sucedido
-			a.model.StatusContainer.AddSuccess("azure-cli", fmt.Sprintf("✅ %s executado com sucesso", operation))
-			a.processAzureOutput(stdoutStr)
-			return nil
-		}
-
-		// Erro REAL - extrair mensagem
-		azureError := "exit status 1"
-		if stderrStr != "" {
-			// Pegar primeira linha não-vazia do erro REAL
-			lines := strings.Split(stderrStr, "\n")
-			for _, line := range lines {
-				if trimmed := strings.TrimSpace(line); trimmed != "" {
-					// Ignorar warnings na extração de erro
-					isWarning := false
-					for _, warning := range knownWarnings {
-						if strings.Contains(trimmed, warning) {
-							isWarning = true
-							break
-						}
-					}
-					if !isWarning && !strings.Contains(trimmed, "WARNING:") {
-						azureError = trimmed
-						// Limitar tamanho para não poluir
-						if len(azureError) > 150 {
-							azureError = azureError[:150] + "..."
-						}
-						break
-					}
-				}
-			}
-		}
-
-		a.model.StatusContainer.AddError("azure-cli", fmt.Sprintf("❌ Falha: %s", azureError))
-
-		// Log detalhado no terminal (apenas em debug mode)
-		if a.debug {
-			a.debugLog("❌ Command failed with error: %s", err.Error())
-			a.debugLog("📄 Stderr output:\n%s", stderrStr)
-			a.debugLog("📄 Stdout output:\n%s", stdoutStr)
-		} else if stderrStr != "" {
-			// Mostrar apenas primeiras 3 linhas de ERRO REAL no StatusContainer
-			lines := strings.Split(stderrStr, "\n")
-			count := 0
-			for _, line := range lines {
-				if trimmed := strings.TrimSpace(line); trimmed != "" && count < 3 {
-					// Não mostrar warnings como erro
-					isWarning := false
-					for _, warning := range knownWarnings {
-						if strings.Contains(trimmed, warning) {
-							isWarning = true
-							break
-						}
-					}
-					if !isWarning && !strings.Contains(trimmed, "WARNING:") {
-						a.model.StatusContainer.AddError("azure-error", trimmed)
-						count++
-					}
-				}
-			}
-		}
-
-		return fmt.Errorf("Azure CLI: %s", azureError)
-	}
-
-	// Sucesso - verificar se há warnings para logar em debug
-	if a.debug && stderrStr != "" {
-		a.debugLog("⚠️ Warnings (ignorados):\n%s", stderrStr)
+		a.updateNodePoolProgress(pool.Name, models.RolloutStatusFailed, 100, "Falha na execução", err.Error())
+		return fmt.Errorf("failed to update node pool %s: %w", pool.Name, err)
 	}
 
-	a.model.StatusContainer.AddSuccess("azure-cli", fmt.Sprintf("✅ %s executado com sucesso", operation))
-
-	// Filtrar output JSON para mostrar apenas informações relevantes
-	a.processAzureOutput(stdoutStr)
+	// Progresso final antes de completar
+	a.updateNodePoolProgress(pool.Name, models.RolloutStatusRunning, 95, "Finalizando operação...", "")
 	return nil
 }
-
-// processAzureOutput processa e filtra o output do Azure CLI
-func (a *App) processAzureOutput(output string) {
-	// Se o output parece ser JSON, tentar extrair informações relevantes
-	if strings.TrimSpace(output) != "" && (strings.HasPrefix(strings.TrimSpace(output), "{") || strings.HasPrefix(strings.TrimSpace(output), "[")) {
-		// Tentar parsear como JSON para extrair informações úteis
-		var jsonData map[string]interface{}
-		if err := json.Unmarshal([]byte(output), &jsonData); err == nil {
-			// Extrair apenas campos relevantes
-			if name, ok := jsonData["name"].(string); ok {
-				a.model.StatusContainer.AddInfo("azure-output", fmt.Sprintf("📋 Nome: %s", name))
-			}
-			if count, ok := jsonData["count"].(float64); ok {
-				a.model.StatusContainer.AddInfo("azure-output", fmt.Sprintf("🔢 Node Count: %.0f", count))
-			}
-			if minCount, ok := jsonData["minCount"].(float64); ok {
-				a.model.StatusContainer.AddInfo("azure-output", fmt.Sprintf("📉 Min Count: %.0f", minCount))
-			}
-			if maxCount, ok := jsonData["maxCount"].(float64); ok {
-				a.model.StatusContainer.AddInfo("azure-output", fmt.Sprintf("📈 Max Count: %.0f", maxCount))
-			}
-			if status, ok := jsonData["provisioningState"].(string); ok && status != "" {
-				a.model.StatusContainer.AddInfo("azure-output", fmt.Sprintf("🏷️  Status: %s", status))
-			}
-		} else {
-			// Se não conseguir parsear JSON, mostrar apenas se não for muito grande
-			if len(output) < 200 {
-				a.model.StatusContainer.AddInfo("azure-output", fmt.Sprintf("📄 Output: %s", strings.TrimSpace(output)))
-			} else {
-				a.model.StatusContainer.AddInfo("azure-output", "📄 Output: ✅ Command executed successfully (output truncated)")
-			}
-		}
-	} else if strings.TrimSpace(output) != "" {
-		// Para output não-JSON, mostrar apenas se for pequeno
-		if len(output) < 200 {
-			a.model.StatusContainer.AddInfo("azure-output", fmt.Sprintf("📄 Output: %s", strings.TrimSpace(output)))
-		} else {
-			a.model.StatusContainer.AddInfo("azure-output", "📄 Output: ✅ Command executed successfully")
-		}
-	}
-}
-
 // renderMixedSession renderiza a interface de sessão mista (HPAs + Node Pools)
 func (a *App) renderMixedSession() string {
 	return a.getTabBar() + "🔄 Sessão Mista (HPAs + Node Pools) - Em Implementação\n\n" +
@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"fmt"
+
+	"k8s-hpa-manager/internal/audit"
+	"k8s-hpa-manager/internal/models"
+)
+
+// auditResourceChange registra no audit log a aplicação de requests/limits de um workload
+func (a *App) auditResourceChange(cluster string, resource *models.ClusterResource, err error) {
+	a.auditRecorder.Record(audit.Event{
+		Action:   audit.ActionApplyResource,
+		Cluster:  cluster,
+		Resource: fmt.Sprintf("%s/%s", resource.Namespace, resource.Name),
+		Before:   resource.OriginalValues,
+		After: map[string]interface{}{
+			"workload_type":  resource.WorkloadType,
+			"cpu_request":    resource.TargetCPURequest,
+			"memory_request": resource.TargetMemoryRequest,
+			"cpu_limit":      resource.TargetCPULimit,
+			"memory_limit":   resource.TargetMemoryLimit,
+			"replicas":       resource.TargetReplicas,
+		},
+		Err: err,
+	})
+}
+
+// auditCronJob registra no audit log a alteração de suspend de um CronJob
+func (a *App) auditCronJob(cronJob *models.CronJob, before *bool, err error) {
+	a.auditRecorder.Record(audit.Event{
+		Action:   audit.ActionUpdateCronJob,
+		Cluster:  cronJob.Cluster,
+		Resource: fmt.Sprintf("%s/%s", cronJob.Namespace, cronJob.Name),
+		Before:   map[string]interface{}{"suspend": before},
+		After:    map[string]interface{}{"suspend": cronJob.Suspend},
+		Err:      err,
+	})
+}
+
+// auditRollout registra no audit log um rollout restart disparado pela TUI
+func (a *App) auditRollout(hpa models.HPA, rolloutType string, err error) {
+	a.auditRecorder.Record(audit.Event{
+		Action:   audit.ActionRollout,
+		Cluster:  hpa.Cluster,
+		Resource: fmt.Sprintf("%s:%s/%s", rolloutType, hpa.Namespace, hpa.Name),
+		Err:      err,
+	})
+}
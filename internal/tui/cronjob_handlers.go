@@ -182,10 +182,12 @@ func (a *App) updateCronJobInKubernetes(client k8sClientSet.Interface, cronJob *
 	}
 
 	// Atualizar apenas o campo Suspend
+	before := currentCronJob.Spec.Suspend
 	currentCronJob.Spec.Suspend = cronJob.Suspend
 
 	// Aplicar a atualização
 	_, err = client.BatchV1().CronJobs(cronJob.Namespace).Update(ctx, currentCronJob, metav1.UpdateOptions{})
+	a.auditCronJob(cronJob, before, err)
 	if err != nil {
 		return fmt.Errorf("failed to update cronjob %s: %w", cronJob.Name, err)
 	}
@@ -151,6 +151,11 @@ func (a *App) handleSessionSelectionKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			a.model.ConfirmingDeletion = true
 			a.model.DeletingSessionName = session.Name
 		}
+	case "d", "D":
+		//Marcar sessão como base do diff ou comparar com a base marcada
+		if a.model.SelectedSessionIdx < len(a.model.LoadedSessions) && len(a.model.LoadedSessions) > 0 {
+			a.toggleSessionDiff(a.model.LoadedSessions[a.model.SelectedSessionIdx])
+		}
 	case "ctrl+n", "f2":
 		//Iniciar renome da sessão
 		if a.model.SelectedSessionIdx < len(a.model.LoadedSessions) && len(a.model.LoadedSessions) > 0 {
@@ -724,7 +729,9 @@ func (a *App) handleHPAEditingKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 
 	// Campos do painel principal (HPA)
-	mainFields := []string{"min_replicas", "max_replicas", "target_cpu", "target_memory", "rollout", "daemonset_rollout", "statefulset_rollout"}
+	mainFields := []string{"min_replicas", "max_replicas", "target_cpu", "target_memory", models.FieldMetrics, "rollout", "daemonset_rollout", "statefulset_rollout",
+		models.FieldScaleUpWindow, models.FieldScaleUpSelect, models.FieldScaleUpPolicies,
+		models.FieldScaleDownWindow, models.FieldScaleDownSelect, models.FieldScaleDownPolicies}
 
 	// Campos do painel de recursos
 	resourceFields := []string{"deployment_cpu_request", "deployment_cpu_limit", "deployment_memory_request", "deployment_memory_limit"}
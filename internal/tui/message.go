@@ -13,8 +13,10 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"k8s-hpa-manager/internal/azure"
 	"k8s-hpa-manager/internal/config"
 	"k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/nodepool"
 	"k8s-hpa-manager/internal/session"
 	"k8s-hpa-manager/internal/tui/components"
 )
@@ -98,6 +100,12 @@ type hpaChangesAppliedMsg struct {
 	err         error
 }
 
+// hpaConflictsMsg HPAs alterados no cluster desde que foram carregados (aplicação aguardando decisão)
+type hpaConflictsMsg struct {
+	hpas      []models.HPA
+	conflicts []session.Conflict
+}
+
 // Mensagem para contagem de HPAs
 type hpaCountUpdatedMsg struct {
 	namespace string
@@ -118,6 +126,12 @@ type sessionDeletedMsg struct {
 }
 
 // Mensagem para renome de sessão
+// sessionsMergedMsg resultado do merge de duas sessões
+type sessionsMergedMsg struct {
+	name string
+	err  error
+}
+
 type sessionRenamedMsg struct {
 	oldName string
 	newName string
@@ -378,58 +392,60 @@ func configurateSubscriptionWithStatus(clusterConfig *models.ClusterConfig, stat
 		// Log do início da configuração Azure
 		// Nota: Vamos usar um batch de comandos para enviar multiple logs
 
-		// Verificar autenticação Azure primeiro
-		if !isAzureCliAuthenticated() {
-			return statusLogMsg{
-				level:   "error",
-				source:  "azure-auth",
-				message: "❌ Azure CLI não autenticado. Execute 'az login' primeiro.",
-			}
-		}
-
-		// Configurar a subscription com timeout
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
+		clusterRef := nodepool.ClusterRefFromConfig(clusterConfig)
 
-		cmd := exec.CommandContext(ctx, "az", "account", "set", "--subscription", clusterConfig.Subscription)
-		err := cmd.Run()
+		// Subscription e autenticação Azure CLI só se aplicam a clusters AKS
+		if clusterRef.Provider == nodepool.ProviderAKS {
+			// Verificar autenticação Azure primeiro
+			if !isAzureCliAuthenticated() {
+				return statusLogMsg{
+					level:   "error",
+					source:  "azure-auth",
+					message: "❌ Azure CLI não autenticado. Execute 'az login' primeiro.",
+				}
+			}
 
-		// Se timeout ou erro de rede, diagnosticar conectividade
-		if err != nil {
-			if ctx.Err() == context.DeadlineExceeded {
-				logToStatusPanel(statusPanel, "warn", "azure-timeout", "⏱️ Timeout ao configurar Azure subscription - diagnosticando...")
-				// Diagnosticar de forma síncrona para ter resultado imediato
-				diagnoseErr := checkVPNConnectivity(statusPanel)
-				if diagnoseErr != nil {
-					return nodePoolsLoadedMsg{
-						err: fmt.Errorf("VPN desconectada ao configurar subscription: %w", diagnoseErr),
-						azureLogMsg: &statusLogMsg{
-							level:   "error",
-							source:  "vpn-check",
-							message: "❌ VPN desconectada - conecte-se à VPN e tente novamente",
-						},
+			// Configurar a subscription com timeout
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			cmd := exec.CommandContext(ctx, "az", "account", "set", "--subscription", clusterConfig.Subscription)
+			err := cmd.Run()
+
+			// Se timeout ou erro de rede, diagnosticar conectividade
+			if err != nil {
+				if ctx.Err() == context.DeadlineExceeded {
+					logToStatusPanel(statusPanel, "warn", "azure-timeout", "⏱️ Timeout ao configurar Azure subscription - diagnosticando...")
+					// Diagnosticar de forma síncrona para ter resultado imediato
+					diagnoseErr := checkVPNConnectivity(statusPanel)
+					if diagnoseErr != nil {
+						return nodePoolsLoadedMsg{
+							err: fmt.Errorf("VPN desconectada ao configurar subscription: %w", diagnoseErr),
+							azureLogMsg: &statusLogMsg{
+								level:   "error",
+								source:  "vpn-check",
+								message: "❌ VPN desconectada - conecte-se à VPN e tente novamente",
+							},
+						}
 					}
 				}
-			}
 
-			return nodePoolsLoadedMsg{
-				err: fmt.Errorf("failed to set subscription '%s': %w", clusterConfig.Subscription, err),
-				azureLogMsg: &statusLogMsg{
-					level:   "error",
-					source:  "azure-config",
-					message: fmt.Sprintf("❌ Falha ao configurar subscription: %s", clusterConfig.Subscription),
-				},
+				return nodePoolsLoadedMsg{
+					err: fmt.Errorf("failed to set subscription '%s': %w", clusterConfig.Subscription, err),
+					azureLogMsg: &statusLogMsg{
+						level:   "error",
+						source:  "azure-config",
+						message: fmt.Sprintf("❌ Falha ao configurar subscription: %s", clusterConfig.Subscription),
+					},
+				}
 			}
 		}
 
-		// 4. Normalizar nome do cluster para Azure CLI (remover -admin se existir)
-		clusterNameForAzure := strings.TrimSuffix(clusterConfig.ClusterName, "-admin")
-
-		// 5. Listar node pools do cluster via Azure CLI usando a função correta com StatusPanel
-		nodePools, err := loadNodePoolsFromAzureWithRetryAndStatus(clusterNameForAzure, clusterConfig.ResourceGroup, clusterConfig.Subscription, true, statusPanel)
+		// 4. Listar node pools do cluster via provider com StatusPanel
+		nodePools, err := loadNodePoolsFromProviderWithStatus(clusterRef, true, statusPanel)
 		if err != nil {
 			return nodePoolsLoadedMsg{
-				err: fmt.Errorf("failed to load node pools from Azure: %w", err),
+				err: fmt.Errorf("failed to load node pools from %s: %w", clusterRef.Provider, err),
 				azureLogMsg: &statusLogMsg{
 					level:   "error",
 					source:  "azure-nodepool",
@@ -438,6 +454,11 @@ func configurateSubscriptionWithStatus(clusterConfig *models.ClusterConfig, stat
 			}
 		}
 
+		successMessage := fmt.Sprintf("✅ Subscription configurada: %s", clusterConfig.Subscription)
+		if clusterRef.Provider != nodepool.ProviderAKS {
+			successMessage = fmt.Sprintf("✅ Node pools carregados: %s", clusterRef)
+		}
+
 		return nodePoolsLoadedMsg{
 			nodePools:   nodePools,
 			subscription: clusterConfig.Subscription,
@@ -445,7 +466,7 @@ func configurateSubscriptionWithStatus(clusterConfig *models.ClusterConfig, stat
 			azureLogMsg: &statusLogMsg{
 				level:   "success",
 				source:  "azure-config",
-				message: fmt.Sprintf("✅ Subscription configurada: %s", clusterConfig.Subscription),
+				message: successMessage,
 			},
 		}
 	}
@@ -992,120 +1013,55 @@ func performAzureLoginWithStatus(errorContext string, statusPanel interface{}) e
 	return nil
 }
 
-// loadNodePoolsFromAzure carrega node pools via Azure CLI
-func loadNodePoolsFromAzure(clusterName, resourceGroup, subscription string) ([]models.NodePool, error) {
-	return loadNodePoolsFromAzureWithRetry(clusterName, resourceGroup, subscription, true)
-}
+// nodePoolProvider provider usado pela TUI para operações de node pool.
+// Despacha para AKS (API ARM), EKS ou GKE conforme o campo "provider" do clusters-config.json.
+var nodePoolProvider nodepool.NodePoolProvider = nodepool.NewDefaultRegistry(azure.NewAuthManager())
 
-// loadNodePoolsFromAzureWithRetry carrega node pools com retry de autenticação
-func loadNodePoolsFromAzureWithRetry(clusterName, resourceGroup, subscription string, allowRetry bool) ([]models.NodePool, error) {
-	return loadNodePoolsFromAzureWithRetryAndStatus(clusterName, resourceGroup, subscription, allowRetry, nil)
+// nodePoolRequestTimeout timeout para listagem de node pools
+const nodePoolRequestTimeout = 60 * time.Second
+
+// loadNodePoolsFromProvider carrega node pools via provider do cluster
+func loadNodePoolsFromProvider(clusterRef nodepool.ClusterRef) ([]models.NodePool, error) {
+	return loadNodePoolsFromProviderWithStatus(clusterRef, true, nil)
 }
 
-// loadNodePoolsFromAzureWithRetryAndStatus carrega node pools com retry de autenticação e StatusPanel
-func loadNodePoolsFromAzureWithRetryAndStatus(clusterName, resourceGroup, subscription string, allowRetry bool, statusPanel interface{}) ([]models.NodePool, error) {
-	// Executar comando Azure CLI
-	cmd := exec.Command("az", "aks", "nodepool", "list",
-		"--resource-group", resourceGroup,
-		"--cluster-name", clusterName,
-		"--output", "json")
+// loadNodePoolsFromProviderWithStatus carrega node pools com retry de autenticação (AKS) e StatusPanel
+func loadNodePoolsFromProviderWithStatus(clusterRef nodepool.ClusterRef, allowRetry bool, statusPanel interface{}) ([]models.NodePool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), nodePoolRequestTimeout)
+	defer cancel()
 
-	output, err := cmd.Output()
+	nodePools, err := nodePoolProvider.List(ctx, clusterRef)
 	if err != nil {
-		// Capturar stderr para melhor debugging
-		if exitError, ok := err.(*exec.ExitError); ok {
-			stderr := string(exitError.Stderr)
-
-			// Verificar se é erro de autenticação e tentar reautenticar
-			if allowRetry && (strings.Contains(stderr, "AADSTS50173") ||
-				strings.Contains(stderr, "expired") ||
-				strings.Contains(stderr, "The provided grant has expired") ||
-				strings.Contains(stderr, "authentication")) {
-
-				logToStatusPanel(statusPanel, "info", "azure-auth", "🔄 Authentication error detected, attempting re-authentication...")
-
-				// Invalidar cache antes de reautenticar
-				azureAuthCache.Lock()
-				azureAuthCache.isAuthenticated = false
-				azureAuthCache.validUntil = time.Time{}
-				azureAuthCache.Unlock()
-
-				// Tentar reautenticar
-				if authErr := ensureAzureLoginWithStatus(statusPanel); authErr != nil {
-					return nil, fmt.Errorf("failed to re-authenticate: %w", authErr)
-				}
-
-				// Tentar novamente (sem retry para evitar loop infinito)
-				logToStatusPanel(statusPanel, "info", "azure-auth", "🔄 Retrying node pool loading after re-authentication...")
-				return loadNodePoolsFromAzureWithRetryAndStatus(clusterName, resourceGroup, subscription, false, statusPanel)
+		// Verificar se é erro de autenticação e tentar reautenticar
+		if allowRetry && clusterRef.Provider == nodepool.ProviderAKS && nodepool.IsAuthError(err) {
+			logToStatusPanel(statusPanel, "info", "azure-auth", "🔄 Authentication error detected, attempting re-authentication...")
+
+			// Invalidar cache antes de reautenticar
+			azureAuthCache.Lock()
+			azureAuthCache.isAuthenticated = false
+			azureAuthCache.validUntil = time.Time{}
+			azureAuthCache.Unlock()
+
+			// Tentar reautenticar
+			if authErr := ensureAzureLoginWithStatus(statusPanel); authErr != nil {
+				return nil, fmt.Errorf("failed to re-authenticate: %w", authErr)
 			}
 
-			return nil, fmt.Errorf("az command failed: %s - stderr: %s", err.Error(), stderr)
-		}
-		return nil, fmt.Errorf("failed to execute az command: %w", err)
-	}
-	
-	// Parse do JSON
-	var azureNodePools []AzureNodePool
-	if err := json.Unmarshal(output, &azureNodePools); err != nil {
-		return nil, fmt.Errorf("failed to parse Azure CLI output: %w", err)
-	}
-	
-	// Converter para nosso modelo
-	var nodePools []models.NodePool
-	for _, azPool := range azureNodePools {
-		// Converter pointers para valores diretos, usando 0 como padrão se null
-		var minCount, maxCount int32
-		if azPool.MinCount != nil {
-			minCount = *azPool.MinCount
-		}
-		if azPool.MaxCount != nil {
-			maxCount = *azPool.MaxCount
+			// Tentar novamente (sem retry para evitar loop infinito)
+			logToStatusPanel(statusPanel, "info", "azure-auth", "🔄 Retrying node pool loading after re-authentication...")
+			return loadNodePoolsFromProviderWithStatus(clusterRef, false, statusPanel)
 		}
 
-		nodePool := models.NodePool{
-			Name:               azPool.Name,
-			VMSize:             azPool.VmSize,
-			NodeCount:          azPool.Count,
-			MinNodeCount:       minCount,
-			MaxNodeCount:       maxCount,
-			AutoscalingEnabled: azPool.EnableAutoScaling, // Usar campo correto do Azure
-			Status:             azPool.ProvisioningState,
-			IsSystemPool:       azPool.Mode == "System",
-			ClusterName:        clusterName,
-			ResourceGroup:      resourceGroup,
-			Subscription:       subscription,
-			Selected:           false, // Inicializar explicitamente como não selecionado
-			Modified:           false, // Inicializar como não modificado
-		}
-
-		// Definir valores originais
-		nodePool.OriginalValues = models.NodePoolValues{
-			NodeCount:          nodePool.NodeCount,
-			MinNodeCount:       nodePool.MinNodeCount,
-			MaxNodeCount:       nodePool.MaxNodeCount,
-			AutoscalingEnabled: nodePool.AutoscalingEnabled,
-		}
-
-		nodePools = append(nodePools, nodePool)
+		return nil, err
 	}
-	
-	return nodePools, nil
-}
-
 
+	// Inicializar explicitamente como não selecionado e não modificado
+	for i := range nodePools {
+		nodePools[i].Selected = false
+		nodePools[i].Modified = false
+	}
 
-// AzureNodePool representa a estrutura retornada pela Azure CLI
-type AzureNodePool struct {
-	Name                    string `json:"name"`
-	VmSize                  string `json:"vmSize"`
-	Count                   int32  `json:"count"`
-	MinCount                *int32 `json:"minCount"`        // Pointer pois pode ser null
-	MaxCount                *int32 `json:"maxCount"`        // Pointer pois pode ser null
-	EnableAutoScaling       bool   `json:"enableAutoScaling"` // Campo correto do Azure
-	Mode                    string `json:"mode"`            // "System" ou "User"
-	ProvisioningState       string `json:"provisioningState"`
-	ScaleSetEvictionPolicy  string `json:"scaleSetEvictionPolicy"`
+	return nodePools, nil
 }
 
 // clearScreen retorna um comando para forçar redesenho da tela
@@ -1250,8 +1206,8 @@ func (a *App) applySequentialNodePool(nodePool models.NodePool, order int) tea.C
 			}
 		}
 
-		// Aplicar mudanças via Azure CLI
-		err := a.updateNodePoolViaAzureCLI(nodePool)
+		// Aplicar mudanças via API do Azure
+		err := a.updateNodePoolViaProvider(nodePool)
 
 		if err != nil {
 			a.debugLog("❌ Erro ao aplicar node pool %s: %v", nodePool.Name, err)
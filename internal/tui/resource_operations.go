@@ -28,6 +28,7 @@ func (a *App) applyResourceChange(resource *models.ClusterResource) tea.Cmd {
 		
 		// Aplicar mudanças
 		err = client.ApplyResourceChanges(resource)
+		a.auditResourceChange(clusterName, resource, err)
 		if err != nil {
 			return resourceChangeAppliedMsg{
 				resource: resource,
@@ -68,6 +69,7 @@ func (a *App) applyAllResourceChanges() tea.Cmd {
 			resource := &a.model.ClusterResources[i]
 			if resource.Modified {
 				err := client.ApplyResourceChanges(resource)
+				a.auditResourceChange(clusterName, resource, err)
 				if err == nil {
 					resource.Modified = false
 					resource.LastUpdated = time.Now()
@@ -105,6 +107,7 @@ func (a *App) applyPrometheusStack() tea.Cmd {
 			resource := &a.model.SelectedResources[i]
 			if resource.Modified {
 				err := client.ApplyResourceChanges(resource)
+				a.auditResourceChange(clusterName, resource, err)
 				if err == nil {
 					resource.Modified = false
 					resource.LastUpdated = time.Now()
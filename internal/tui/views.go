@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"strings"
 
+	"k8s-hpa-manager/internal/kubernetes"
 	"k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/session"
 	"k8s-hpa-manager/internal/tui/layout"
 
 	"github.com/charmbracelet/lipgloss"
@@ -590,6 +592,9 @@ func (a *App) renderSessionSelection() string {
 		
 		sessionInfo := fmt.Sprintf("%s\n   %s • %d mudanças • %s", 
 			session.Name, sessionType, changesCount, createdAt)
+		if a.diffBase != nil && a.diffBase.Name == session.Name {
+			sessionInfo = "🔀 " + sessionInfo
+		}
 
 		if i == a.model.SelectedSessionIdx {
 			sessionList[i] = selectedItemStyle.Render(sessionInfo)
@@ -640,7 +645,7 @@ func (a *App) renderSessionSelection() string {
 	} else {
 		// Ajuda normal
 		content.WriteString("\n")
-		content.WriteString(helpStyle.Render("↑↓ Navegar • ENTER Aplicar • Ctrl+N/F2 Renomear • Ctrl+R Deletar • ESC Voltar"))
+		content.WriteString(helpStyle.Render("↑↓ Navegar • ENTER Aplicar • D Comparar/Merge • Ctrl+N/F2 Renomear • Ctrl+R Deletar • ESC Voltar"))
 	}
 
 	return a.getTabBar() + content.String()
@@ -3199,6 +3204,31 @@ func (a *App) renderHelp() string {
 	return a.getTabBar() + containerStyle.Render(content)
 }
 
+// getMetricsDisplayValue retorna as métricas do HPA para exibição, com o valor atual de cada uma
+func getMetricsDisplayValue(metrics []models.HPAMetric) string {
+	if len(metrics) == 0 {
+		return "nenhuma"
+	}
+
+	parts := make([]string, 0, len(metrics))
+	for _, metric := range metrics {
+		part := kubernetes.FormatMetrics([]models.HPAMetric{metric})
+		if metric.Current != "" {
+			part += fmt.Sprintf(" (atual: %s)", metric.Current)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// getBehaviorDisplayValue retorna o valor de um campo de behavior para exibição
+func getBehaviorDisplayValue(behavior *models.HPABehavior, fieldName string) string {
+	if value := getBehaviorFieldValue(behavior, fieldName); value != "" {
+		return value
+	}
+	return "padrão"
+}
+
 // getDeploymentResourceValue retorna o valor do recurso do deployment (target ou current)
 func getDeploymentResourceValue(targetValue, currentValue string) string {
 	if targetValue != "" {
@@ -3234,9 +3264,16 @@ func (a *App) renderHPAMainPanel() string {
 		{"Max Replicas", getFormValue(a.model.FormFields, "max_replicas", fmt.Sprintf("%d", hpa.MaxReplicas)), "max_replicas", "Número máximo de replicas"},
 		{"Target CPU", getFormValue(a.model.FormFields, "target_cpu", getIntPtrString(hpa.TargetCPU)), "target_cpu", "Porcentagem de CPU alvo"},
 		{"Target Memory", getFormValue(a.model.FormFields, "target_memory", getIntPtrString(hpa.TargetMemory)), "target_memory", "Porcentagem de memória alvo"},
+		{"Métricas", getMetricsDisplayValue(hpa.Metrics), models.FieldMetrics, "Tipo:nome=Alvo:valor separados por ; (ex: External:queue{q=orders}=AverageValue:30)"},
 		{"Rollout", fmt.Sprintf("%t", hpa.PerformRollout), "rollout", "Executar rollout após aplicar"},
 		{"DaemonSet Rollout", fmt.Sprintf("%t", hpa.PerformDaemonSetRollout), "daemonset_rollout", "Executar rollout de DaemonSets"},
 		{"StatefulSet Rollout", fmt.Sprintf("%t", hpa.PerformStatefulSetRollout), "statefulset_rollout", "Executar rollout de StatefulSets"},
+		{"Scale Up Window", getBehaviorDisplayValue(hpa.Behavior, models.FieldScaleUpWindow), models.FieldScaleUpWindow, "Janela de estabilização do scale up em segundos (0-3600)"},
+		{"Scale Up Select", getBehaviorDisplayValue(hpa.Behavior, models.FieldScaleUpSelect), models.FieldScaleUpSelect, "Policy escolhida no scale up: Max, Min ou Disabled"},
+		{"Scale Up Policies", getBehaviorDisplayValue(hpa.Behavior, models.FieldScaleUpPolicies), models.FieldScaleUpPolicies, "Formato Tipo:Valor:Período (ex: Pods:4:60,Percent:100:15)"},
+		{"Scale Down Window", getBehaviorDisplayValue(hpa.Behavior, models.FieldScaleDownWindow), models.FieldScaleDownWindow, "Janela de estabilização do scale down em segundos (0-3600)"},
+		{"Scale Down Select", getBehaviorDisplayValue(hpa.Behavior, models.FieldScaleDownSelect), models.FieldScaleDownSelect, "Policy escolhida no scale down: Max, Min ou Disabled"},
+		{"Scale Down Policies", getBehaviorDisplayValue(hpa.Behavior, models.FieldScaleDownPolicies), models.FieldScaleDownPolicies, "Formato Tipo:Valor:Período (ex: Percent:10:60)"},
 	}
 	
 	for _, field := range fields {
@@ -3251,6 +3288,12 @@ func (a *App) renderHPAMainPanel() string {
 			displayValue = a.insertCursorInText(a.model.EditingValue, a.model.CursorPosition)
 		}
 
+		// Separador da seção de behavior
+		if field.fieldKey == models.FieldScaleUpWindow {
+			mutedStyle := lipgloss.NewStyle().Foreground(mutedColor)
+			content.WriteString("\n" + mutedStyle.Render("Scaling Behavior (vazio = padrão do Kubernetes)") + "\n")
+		}
+
 		line := fmt.Sprintf("%s: %s", field.name, displayValue)
 		content.WriteString(style.Render(line) + "\n")
 
@@ -3596,6 +3639,187 @@ func (a *App) renderConfirmModal() string {
 	return strings.Join(centeredLines, "\n")
 }
 
+// renderConflictModal renderiza o modal de HPAs alterados no cluster desde que foram carregados
+// (valor da sessão | valor atual no cluster | valor a aplicar) com a decisão de cada HPA
+func (a *App) renderConflictModal() string {
+	if len(a.conflicts) == 0 {
+		return ""
+	}
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("208")). // Laranja (conflito)
+		Padding(1, 2).
+		Width(90)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("208")).
+		Width(84).
+		Align(lipgloss.Center)
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")).
+		Bold(true)
+
+	fieldStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252"))
+
+	pendingStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")).
+		Bold(true)
+
+	resolvedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("46")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240"))
+
+	var content strings.Builder
+	content.WriteString(titleStyle.Render("⚠️ HPAs ALTERADOS NO CLUSTER") + "\n\n")
+	content.WriteString(fieldStyle.Render("Os HPAs abaixo mudaram desde que foram carregados. Nada foi aplicado.") + "\n\n")
+
+	for i, conflict := range a.conflicts {
+		cursor := "  "
+		name := fmt.Sprintf("%s/%s (%s)", conflict.Namespace, conflict.Name, conflict.Cluster)
+		if i == a.conflictSelected {
+			cursor = "▶ "
+			name = selectedStyle.Render(name)
+		}
+
+		decision := pendingStyle.Render("[pendente]")
+		if resolution := a.conflictResolutions[conflict.Key]; resolution != "" {
+			decision = resolvedStyle.Render(fmt.Sprintf("[%s]", resolution))
+		}
+		content.WriteString(fmt.Sprintf("%s%s %s\n", cursor, name, decision))
+
+		for _, field := range conflict.Fields {
+			content.WriteString(fieldStyle.Render(fmt.Sprintf("     %-14s sessão %-8s cluster %-8s alvo %s",
+				field.Field, field.Original, field.Live, field.Target)) + "\n")
+		}
+	}
+
+	content.WriteString("\n")
+	content.WriteString(helpStyle.Render("s: manter cluster (skip)  f: sobrescrever (force)  r: aplicar só o que a sessão muda (rebase)") + "\n")
+	content.WriteString(helpStyle.Render("S/F/R: decidir todos  ↑↓: navegar  ENTER: aplicar  ESC: cancelar"))
+
+	modal := modalStyle.Render(content.String())
+
+	// Centralizar horizontalmente apenas (vertical é feito por renderModalOverlay)
+	lines := strings.Split(modal, "\n")
+	centeredLines := make([]string, len(lines))
+	for i, line := range lines {
+		padding := (a.width - lipgloss.Width(line)) / 2
+		if padding > 0 {
+			centeredLines[i] = strings.Repeat(" ", padding) + line
+		} else {
+			centeredLines[i] = line
+		}
+	}
+	return strings.Join(centeredLines, "\n")
+}
+
+// renderSessionDiffModal renderiza o diff entre duas sessões (valores alvo de A | B)
+// com a escolha de lado de cada item em conflito para o merge
+func (a *App) renderSessionDiffModal() string {
+	if a.sessionDiff == nil {
+		return ""
+	}
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("39")). // Azul (comparação)
+		Padding(1, 2).
+		Width(90)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Width(84).
+		Align(lipgloss.Center)
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")).
+		Bold(true)
+
+	fieldStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252"))
+
+	pendingStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")).
+		Bold(true)
+
+	resolvedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("46")).
+		Bold(true)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240"))
+
+	diff := a.sessionDiff
+	var content strings.Builder
+	content.WriteString(titleStyle.Render("🔀 COMPARAR SESSÕES") + "\n\n")
+	content.WriteString(fieldStyle.Render(fmt.Sprintf("A: %s    B: %s", diff.A, diff.B)) + "\n")
+	content.WriteString(fieldStyle.Render(fmt.Sprintf("%d itens diferentes • %d iguais", len(diff.Items), diff.Unchanged)) + "\n\n")
+
+	if diff.Identical {
+		content.WriteString(resolvedStyle.Render("✅ As sessões têm os mesmos valores alvo") + "\n")
+	}
+
+	conflictIdx := 0
+	for _, item := range diff.Items {
+		name := item.Name
+		if item.Namespace != "" {
+			name = item.Namespace + "/" + item.Name
+		}
+		name = fmt.Sprintf("%s %s (%s)", item.Kind, name, item.Cluster)
+
+		switch item.Change {
+		case session.DiffOnlyA:
+			content.WriteString(fieldStyle.Render(fmt.Sprintf("  %s [apenas A]", name)) + "\n")
+		case session.DiffOnlyB:
+			content.WriteString(fieldStyle.Render(fmt.Sprintf("  %s [apenas B]", name)) + "\n")
+		case session.DiffChanged:
+			cursor := "  "
+			if conflictIdx == a.diffSelected {
+				cursor = "▶ "
+				name = selectedStyle.Render(name)
+			}
+			conflictIdx++
+
+			decision := pendingStyle.Render("[pendente]")
+			if side := a.diffResolutions[item.Key]; side != "" {
+				decision = resolvedStyle.Render(fmt.Sprintf("[%s]", strings.ToUpper(string(side))))
+			}
+			content.WriteString(fmt.Sprintf("%s%s %s\n", cursor, name, decision))
+
+			for _, field := range item.Fields {
+				content.WriteString(fieldStyle.Render(fmt.Sprintf("     %-14s A %-12s B %s", field.Field, field.A, field.B)) + "\n")
+			}
+		}
+	}
+
+	content.WriteString("\n")
+	content.WriteString(helpStyle.Render("a/b: usar valores de A/B no item  A/B: decidir todos  ↑↓: navegar") + "\n")
+	content.WriteString(helpStyle.Render("M: salvar merge na pasta atual  ESC: fechar"))
+
+	modal := modalStyle.Render(content.String())
+
+	// Centralizar horizontalmente apenas (vertical é feito por renderModalOverlay)
+	lines := strings.Split(modal, "\n")
+	centeredLines := make([]string, len(lines))
+	for i, line := range lines {
+		padding := (a.width - lipgloss.Width(line)) / 2
+		if padding > 0 {
+			centeredLines[i] = strings.Repeat(" ", padding) + line
+		} else {
+			centeredLines[i] = line
+		}
+	}
+	return strings.Join(centeredLines, "\n")
+}
+
 // renderVPNErrorModal renderiza modal de erro de VPN
 func (a *App) renderVPNErrorModal() string {
 	if !a.model.ShowVPNErrorModal {
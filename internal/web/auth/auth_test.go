@@ -0,0 +1,253 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s-hpa-manager/internal/web/auth/oidctest"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testConfig = `
+oidc:
+  issuer: ISSUER
+  client_id: hpa-manager
+  redirect_url: REDIRECT
+static_token_role: viewer
+bindings:
+  - {subjects: ["group:sre"], role: admin}
+  - {subjects: ["group:squad"], role: operator, clusters: ["*-hlg"]}
+  - {subjects: ["user:plantao@empresa.com"], role: operator, clusters: ["aks-prd"], operations: [hpa_edit]}
+  - {subjects: ["group:squad"], role: admin, clusters: ["*-hlg"], operations: [drain]}
+`
+
+func TestPermissions(t *testing.T) {
+	cfg, err := ParseConfig([]byte(strings.NewReplacer("ISSUER", "http://idp", "REDIRECT", "http://app/auth/callback").Replace(testConfig)))
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	squad := Identity{Username: "dev@empresa.com", Groups: []string{"squad"}}
+	squad.Grants = cfg.grantsFor(squad.Username, squad.Groups)
+	plantao := Identity{Username: "Plantao@empresa.com"}
+	plantao.Grants = cfg.grantsFor(plantao.Username, nil)
+	sre := Identity{Username: "ops@empresa.com", Groups: []string{"sre"}}
+	sre.Grants = cfg.grantsFor(sre.Username, sre.Groups)
+	nobody := Identity{Username: "x@empresa.com"}
+
+	tests := []struct {
+		name    string
+		id      Identity
+		op      Operation
+		cluster string
+		want    bool
+	}{
+		{"operator in scope", squad, OpHPAEdit, "aks-pagamentos-hlg-admin", true},
+		{"operator out of scope", squad, OpHPAEdit, "aks-pagamentos-prd", false},
+		{"scoped drain", squad, OpDrain, "aks-hlg", true},
+		{"scoped admin has no server admin", squad, OpAdmin, "", false},
+		{"operator no drain outside scope", squad, OpDrain, "aks-prd", false},
+		{"restricted operations", plantao, OpHPAEdit, "aks-prd-admin", true},
+		{"restricted operations deny others", plantao, OpNodePoolScale, "aks-prd", false},
+		{"restricted operations keep read", plantao, OpRead, "", true},
+		{"admin everywhere", sre, OpDrain, "aks-prd", true},
+		{"admin server ops", sre, OpAdmin, "", true},
+		{"scoped operator not on all clusters", squad, OpMonitoringManage, AllClusters, false},
+		{"glob inside scope", squad, OpMonitoringManage, "*-hlg", true},
+		{"admin on all clusters", sre, OpMonitoringManage, AllClusters, true},
+		{"no bindings", nobody, OpRead, "", false},
+	}
+	for _, tt := range tests {
+		if got := tt.id.Can(tt.op, tt.cluster); got != tt.want {
+			t.Errorf("%s: Can(%s, %q) = %v, want %v", tt.name, tt.op, tt.cluster, got, tt.want)
+		}
+	}
+
+	invalid := []string{
+		"bindings: [{subjects: [x], role: admin}]\nstatic_token_role: admin",
+		"bindings: [{subjects: ['*'], role: root}]\nstatic_token_role: admin",
+		"bindings: [{subjects: ['*'], role: viewer, operations: [drain]}]\nstatic_token_role: admin",
+		"bindings: [{subjects: ['*'], role: viewer}]",
+		"oidc: {issuer: http://idp}",
+	}
+	for _, data := range invalid {
+		if _, err := ParseConfig([]byte(data)); err == nil {
+			t.Errorf("expected error for config %q", data)
+		}
+	}
+}
+
+// newTestServer servidor com a API protegida, /auth/* e uma rota que exige hpa_edit
+func newTestServer(t *testing.T, idp *oidctest.IdP) (*httptest.Server, *Authenticator) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	cfg, err := ParseConfig([]byte(strings.NewReplacer("ISSUER", idp.Issuer(), "REDIRECT", server.URL+"/auth/callback").Replace(testConfig)))
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	authenticator, err := New(context.Background(), cfg, "static-token")
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	authenticator.RegisterRoutes(router)
+	api := router.Group("/api/v1", authenticator.Middleware())
+	api.GET("/auth/me", authenticator.Me)
+	api.PUT("/hpas/:cluster", func(c *gin.Context) {
+		if !Require(c, OpHPAEdit, c.Param("cluster")) {
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"success": true, "user": User(c)})
+	})
+	router.GET("/", authenticator.RequireLogin(), func(c *gin.Context) { c.String(http.StatusOK, "app") })
+	return server, authenticator
+}
+
+func TestOIDCLoginFlow(t *testing.T) {
+	idp := oidctest.New("hpa-manager", oidctest.User{Subject: "u1", Email: "dev@empresa.com", Groups: []string{"squad"}})
+	defer idp.Close()
+	server, _ := newTestServer(t, idp)
+
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{Jar: jar}
+
+	// Sem sessão: página redireciona para o IdP e volta com cookie de sessão
+	resp, err := client.Get(server.URL + "/?tab=hpas")
+	if err != nil {
+		t.Fatalf("login flow failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Request.URL.RawQuery != "tab=hpas" {
+		t.Fatalf("expected to land on /?tab=hpas after login, got %d %s", resp.StatusCode, resp.Request.URL)
+	}
+
+	var me struct {
+		Data Identity `json:"data"`
+	}
+	resp, err = client.Get(server.URL + "/api/v1/auth/me")
+	if err != nil {
+		t.Fatalf("me failed: %v", err)
+	}
+	json.NewDecoder(resp.Body).Decode(&me)
+	resp.Body.Close()
+	if me.Data.Username != "dev@empresa.com" || me.Data.Method != MethodOIDC || len(me.Data.Grants) != 2 {
+		t.Errorf("unexpected identity: %+v", me.Data)
+	}
+
+	for cluster, want := range map[string]int{"aks-hlg-admin": http.StatusOK, "aks-prd-admin": http.StatusForbidden} {
+		req, _ := http.NewRequest(http.MethodPut, server.URL+"/api/v1/hpas/"+cluster, nil)
+		req.Header.Set("Authorization", "Bearer poc-token-123") // cookie tem precedência sobre token legado
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("update failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("PUT %s: status %d, want %d", cluster, resp.StatusCode, want)
+		}
+	}
+
+	// Logout remove a sessão
+	resp, _ = client.Post(server.URL+"/auth/logout", "application/json", nil)
+	resp.Body.Close()
+	resp, _ = client.Get(server.URL + "/api/v1/auth/me")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 after logout, got %d", resp.StatusCode)
+	}
+
+	// Usuário sem bindings não recebe sessão
+	idp.SetUser(oidctest.User{Subject: "u9", Email: "guest@empresa.com"})
+	resp, err = client.Get(server.URL + "/")
+	if err != nil {
+		t.Fatalf("login flow failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected 403 for user without bindings, got %d", resp.StatusCode)
+	}
+}
+
+func TestBearerTokens(t *testing.T) {
+	idp := oidctest.New("hpa-manager", oidctest.User{Subject: "u2"})
+	defer idp.Close()
+	server, _ := newTestServer(t, idp)
+
+	put := func(token string) int {
+		req, _ := http.NewRequest(http.MethodPut, server.URL+"/api/v1/hpas/aks-prd", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	sre := oidctest.User{Subject: "u3", Email: "ops@empresa.com", Groups: []string{"sre"}}
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"id token", idp.IDToken(sre, "", time.Hour), http.StatusOK},
+		{"expired id token", idp.IDToken(sre, "", -time.Hour), http.StatusUnauthorized},
+		{"static token is viewer", "static-token", http.StatusForbidden},
+		{"unknown token", "poc-token-123", http.StatusUnauthorized},
+		{"user without bindings", idp.IDToken(oidctest.User{Subject: "u4"}, "", time.Hour), http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		if got := put(tt.token); got != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	// state adulterado é rejeitado
+	resp, err := http.Get(server.URL + "/auth/callback?code=x&state=" + url.QueryEscape("forged"))
+	if err != nil {
+		t.Fatalf("callback failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for forged state, got %d", resp.StatusCode)
+	}
+}
+
+func TestLegacyMode(t *testing.T) {
+	authenticator, err := New(context.Background(), nil, "poc-token-123")
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if authenticator.OIDCEnabled() || !authenticator.StaticTokenEnabled() {
+		t.Fatal("legacy mode should only accept the static token")
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/shutdown", authenticator.Middleware(), func(c *gin.Context) {
+		if Require(c, OpAdmin, "") {
+			c.Status(http.StatusOK)
+		}
+	})
+	for token, want := range map[string]int{"poc-token-123": http.StatusOK, "other": http.StatusUnauthorized} {
+		req := httptest.NewRequest(http.MethodPost, "/shutdown", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("token %q: status %d, want %d", token, rec.Code, want)
+		}
+	}
+}
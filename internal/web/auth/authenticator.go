@@ -0,0 +1,409 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/oauth2"
+)
+
+// identityKey chave da identidade no gin.Context
+const identityKey = "auth.identity"
+
+// Authenticator autentica requisições (cookie de sessão OIDC, ID token ou token estático)
+// e resolve os papéis dos bindings
+type Authenticator struct {
+	config      *Config
+	provider    *Provider // nil sem OIDC
+	staticToken string
+	signer      signer
+	ttl         time.Duration
+}
+
+// New cria o autenticador. config nil = modo legado (somente o token estático, papel admin).
+// Com OIDC, executa o discovery do issuer.
+func New(ctx context.Context, config *Config, staticToken string) (*Authenticator, error) {
+	if config == nil {
+		config = LegacyConfig()
+	}
+	ttl, err := config.Session.ttl()
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Authenticator{
+		config:      config,
+		staticToken: staticToken,
+		ttl:         ttl,
+	}
+
+	secret := os.Getenv(config.Session.secretEnv())
+	if secret == "" {
+		secret = randomString(32)
+		if config.OIDC != nil {
+			fmt.Printf("⚠️  %s não definido: sessões de login serão invalidadas ao reiniciar o servidor\n", config.Session.secretEnv())
+		}
+	}
+	a.signer = signer{secret: []byte(secret)}
+
+	if config.OIDC != nil {
+		provider, err := NewProvider(ctx, config.OIDC, nil)
+		if err != nil {
+			return nil, err
+		}
+		a.provider = provider
+	}
+	return a, nil
+}
+
+// OIDCEnabled indica se o login OIDC está configurado
+func (a *Authenticator) OIDCEnabled() bool {
+	return a.provider != nil
+}
+
+// StaticTokenEnabled indica se o token estático (K8S_HPA_WEB_TOKEN) é aceito
+func (a *Authenticator) StaticTokenEnabled() bool {
+	return a.config.StaticTokenRole != "" && a.staticToken != ""
+}
+
+// CORSOrigins origens configuradas ou, sem configuração, as padrão informadas
+func (a *Authenticator) CORSOrigins(defaults []string) []string {
+	if len(a.config.CORSOrigins) > 0 {
+		return a.config.CORSOrigins
+	}
+	return defaults
+}
+
+// Middleware exige uma identidade válida com permissão de leitura e a grava no contexto
+func (a *Authenticator) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, code, err := a.authenticate(c)
+		if err != nil {
+			body := gin.H{"code": code, "message": err.Error()}
+			if a.provider != nil {
+				body["login_url"] = "/auth/login"
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"success": false, "error": body})
+			return
+		}
+		if !id.Can(OpRead, "") {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "FORBIDDEN",
+					"message": fmt.Sprintf("User %s has no role bindings", id.Name()),
+				},
+			})
+			return
+		}
+
+		c.Set(identityKey, id)
+		c.Next()
+	}
+}
+
+// authenticate tenta o cookie de sessão e depois o header Authorization.
+// O cookie tem precedência: o frontend ainda envia o token legado em alguns componentes.
+func (a *Authenticator) authenticate(c *gin.Context) (*Identity, string, error) {
+	if id, ok := a.sessionIdentity(c); ok {
+		return id, "", nil
+	}
+
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		return nil, "UNAUTHORIZED", errors.New("No authorization header provided")
+	}
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return nil, "INVALID_AUTH_FORMAT", errors.New("Authorization header must be 'Bearer <token>'")
+	}
+
+	if a.StaticTokenEnabled() && subtle.ConstantTimeCompare([]byte(parts[1]), []byte(a.staticToken)) == 1 {
+		return a.tokenIdentity(), "", nil
+	}
+
+	// ID token emitido pelo IdP (CLI/automação com client credentials ou device flow)
+	if a.provider != nil && strings.Count(parts[1], ".") == 2 {
+		claims, err := a.provider.Verify(c.Request.Context(), parts[1])
+		if err != nil {
+			return nil, "INVALID_TOKEN", err
+		}
+		id := a.resolve(a.provider.identityFromClaims(claims))
+		return &id, "", nil
+	}
+
+	return nil, "INVALID_TOKEN", errors.New("Invalid authentication token")
+}
+
+// sessionIdentity identidade do cookie de sessão, se válido e não expirado
+func (a *Authenticator) sessionIdentity(c *gin.Context) (*Identity, bool) {
+	if a.provider == nil {
+		return nil, false
+	}
+	value, err := c.Cookie(SessionCookie)
+	if err != nil || value == "" {
+		return nil, false
+	}
+	var payload sessionPayload
+	if err := a.signer.decode(value, &payload); err != nil || time.Now().Unix() > payload.ExpiresAt {
+		return nil, false
+	}
+
+	id := a.resolve(Identity{
+		Subject:  payload.Subject,
+		Username: payload.Username,
+		Email:    payload.Email,
+		Groups:   payload.Groups,
+		Method:   MethodOIDC,
+	})
+	return &id, true
+}
+
+// resolve aplica os bindings à identidade
+func (a *Authenticator) resolve(id Identity) Identity {
+	id.Grants = a.config.grantsFor(id.Username, id.Groups)
+	return id
+}
+
+// tokenIdentity identidade do token estático (K8S_HPA_WEB_TOKEN)
+func (a *Authenticator) tokenIdentity() *Identity {
+	return &Identity{
+		Subject:  MethodToken,
+		Username: MethodToken,
+		Method:   MethodToken,
+		Grants:   []Grant{newGrant(Binding{Role: a.config.StaticTokenRole})},
+	}
+}
+
+// IdentityFor resolve os grants atuais de um usuário fora de uma requisição (ex: agendamentos
+// reautorizados a cada execução). Token estático desabilitado não recebe grants.
+func (a *Authenticator) IdentityFor(method, username string, groups []string) *Identity {
+	if method == MethodToken {
+		if !a.StaticTokenEnabled() {
+			return &Identity{Subject: MethodToken, Username: MethodToken, Method: MethodToken}
+		}
+		return a.tokenIdentity()
+	}
+	id := a.resolve(Identity{Username: username, Groups: groups, Method: method})
+	return &id
+}
+
+// RegisterRoutes registra /auth/info, /auth/login, /auth/callback e /auth/logout (sem auth)
+func (a *Authenticator) RegisterRoutes(r gin.IRouter) {
+	r.GET("/auth/info", a.Info)
+	r.GET("/auth/login", a.Login)
+	r.GET("/auth/callback", a.Callback)
+	r.GET("/auth/logout", a.Logout)
+	r.POST("/auth/logout", a.Logout)
+}
+
+// Info informa ao frontend quais métodos de login estão disponíveis
+// GET /auth/info
+func (a *Authenticator) Info(c *gin.Context) {
+	data := gin.H{
+		"oidc":         a.provider != nil,
+		"static_token": a.StaticTokenEnabled(),
+	}
+	if a.provider != nil {
+		data["login_url"] = "/auth/login"
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": data})
+}
+
+// Login inicia o authorization code flow (state, nonce e PKCE em cookie de curta duração)
+// GET /auth/login?return_to=/caminho
+func (a *Authenticator) Login(c *gin.Context) {
+	if !a.requireOIDC(c) {
+		return
+	}
+
+	payload := statePayload{
+		State:     randomString(24),
+		Nonce:     randomString(24),
+		Verifier:  oauth2.GenerateVerifier(),
+		ReturnTo:  safeReturnTo(c.Query("return_to")),
+		ExpiresAt: time.Now().Add(stateTTL).Unix(),
+	}
+	value, err := a.signer.encode(payload)
+	if err != nil {
+		authError(c, http.StatusInternalServerError, "LOGIN_FAILED", err.Error())
+		return
+	}
+	a.setCookie(c, stateCookie, value, "/auth", int(stateTTL.Seconds()))
+	c.Redirect(http.StatusFound, a.provider.AuthCodeURL(payload.State, payload.Nonce, payload.Verifier))
+}
+
+// Callback recebe o code do IdP, valida o ID token e cria o cookie de sessão
+// GET /auth/callback?code=&state=
+func (a *Authenticator) Callback(c *gin.Context) {
+	if !a.requireOIDC(c) {
+		return
+	}
+	if idpError := c.Query("error"); idpError != "" {
+		authError(c, http.StatusUnauthorized, "LOGIN_FAILED",
+			fmt.Sprintf("Identity provider error: %s %s", idpError, c.Query("error_description")))
+		return
+	}
+
+	value, _ := c.Cookie(stateCookie)
+	a.setCookie(c, stateCookie, "", "/auth", -1)
+	var state statePayload
+	if err := a.signer.decode(value, &state); err != nil || time.Now().Unix() > state.ExpiresAt {
+		authError(c, http.StatusBadRequest, "INVALID_STATE", "Login expired or started in another browser, try again")
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(c.Query("state")), []byte(state.State)) != 1 {
+		authError(c, http.StatusBadRequest, "INVALID_STATE", "State mismatch")
+		return
+	}
+
+	claims, err := a.provider.Exchange(c.Request.Context(), c.Query("code"), state.Verifier, state.Nonce)
+	if err != nil {
+		authError(c, http.StatusUnauthorized, "LOGIN_FAILED", err.Error())
+		return
+	}
+	id := a.resolve(a.provider.identityFromClaims(claims))
+	if !id.Can(OpRead, "") {
+		fmt.Printf("🚫 Login OIDC negado: %s sem bindings (grupos: %s)\n", id.Name(), strings.Join(id.Groups, ", "))
+		authError(c, http.StatusForbidden, "FORBIDDEN", fmt.Sprintf("User %s has no role bindings", id.Name()))
+		return
+	}
+
+	session, err := a.signer.encode(sessionPayload{
+		Subject:   id.Subject,
+		Username:  id.Username,
+		Email:     id.Email,
+		Groups:    id.Groups,
+		ExpiresAt: time.Now().Add(a.ttl).Unix(),
+	})
+	if err != nil {
+		authError(c, http.StatusInternalServerError, "LOGIN_FAILED", err.Error())
+		return
+	}
+	a.setCookie(c, SessionCookie, session, "/", int(a.ttl.Seconds()))
+
+	fmt.Printf("🔑 Login OIDC: %s (%s)\n", id.Name(), strings.Join(id.Roles(), ", "))
+	returnTo := state.ReturnTo
+	if returnTo == "" {
+		returnTo = "/"
+	}
+	c.Redirect(http.StatusFound, returnTo)
+}
+
+// Logout remove o cookie de sessão
+// GET|POST /auth/logout
+func (a *Authenticator) Logout(c *gin.Context) {
+	a.setCookie(c, SessionCookie, "", "/", -1)
+	if c.Request.Method == http.MethodGet {
+		c.Redirect(http.StatusFound, "/")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// Me retorna a identidade e as permissões do usuário atual
+// GET /api/v1/auth/me
+func (a *Authenticator) Me(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    FromContext(c),
+	})
+}
+
+// RequireLogin redireciona páginas do frontend para /auth/login quando o OIDC está
+// habilitado e não há sessão. Sem OIDC, não faz nada (o frontend pede o token).
+func (a *Authenticator) RequireLogin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if a.LoginRedirect(c) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// LoginRedirect redireciona para /auth/login quando o OIDC está habilitado e não há
+// sessão; retorna true se a requisição foi respondida
+func (a *Authenticator) LoginRedirect(c *gin.Context) bool {
+	if a.provider == nil {
+		return false
+	}
+	if _, ok := a.sessionIdentity(c); ok {
+		return false
+	}
+	c.Redirect(http.StatusFound, "/auth/login?return_to="+url.QueryEscape(c.Request.URL.RequestURI()))
+	c.Abort()
+	return true
+}
+
+func (a *Authenticator) requireOIDC(c *gin.Context) bool {
+	if a.provider != nil {
+		return true
+	}
+	authError(c, http.StatusNotFound, "OIDC_DISABLED", "OIDC login is not configured")
+	return false
+}
+
+// setCookie cookie HttpOnly/SameSite=Lax, Secure atrás de TLS ou proxy HTTPS
+func (a *Authenticator) setCookie(c *gin.Context, name, value, path string, maxAge int) {
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(name, value, maxAge, path, "", secure, true)
+}
+
+// safeReturnTo aceita apenas caminhos locais (evita open redirect)
+func safeReturnTo(returnTo string) string {
+	if !strings.HasPrefix(returnTo, "/") || strings.HasPrefix(returnTo, "//") || strings.HasPrefix(returnTo, "/\\") {
+		return ""
+	}
+	return returnTo
+}
+
+// FromContext identidade autenticada da requisição (nil fora do middleware)
+func FromContext(c *gin.Context) *Identity {
+	value, ok := c.Get(identityKey)
+	if !ok {
+		return nil
+	}
+	id, _ := value.(*Identity)
+	return id
+}
+
+// User nome do usuário autenticado para logs e histórico
+func User(c *gin.Context) string {
+	return FromContext(c).Name()
+}
+
+// Require verifica a permissão e responde 403 quando negada. cluster "" = operação
+// que não é de um cluster específico; AllClusters = operação que afeta todos os clusters.
+func Require(c *gin.Context, op Operation, cluster string) bool {
+	id := FromContext(c)
+	if id.Can(op, cluster) {
+		return true
+	}
+
+	message := fmt.Sprintf("User %s is not allowed to %s", id.Name(), op)
+	if cluster != "" {
+		message += fmt.Sprintf(" on cluster %s", cluster)
+	}
+	fmt.Printf("🚫 %s\n", message)
+	authError(c, http.StatusForbidden, "FORBIDDEN", message)
+	return false
+}
+
+func authError(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, gin.H{
+		"success": false,
+		"error": gin.H{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Config formato do arquivo de autenticação (YAML)
+//
+//	oidc:
+//	  issuer: https://login.microsoftonline.com/<tenant>/v2.0
+//	  client_id: k8s-hpa-manager
+//	  client_secret_env: K8S_HPA_OIDC_CLIENT_SECRET
+//	  redirect_url: https://hpa.empresa.com/auth/callback
+//	  groups_claim: groups          # default: groups
+//	  username_claim: email         # default: email (fallback preferred_username, sub)
+//	session:
+//	  ttl: 8h                       # default: 8h
+//	  secret_env: K8S_HPA_SESSION_SECRET
+//	static_token_role: viewer       # K8S_HPA_WEB_TOKEN continua válido com este papel (ex: scrape do /metrics)
+//	cors_origins: ["https://hpa.empresa.com"]
+//	bindings:
+//	  - {subjects: ["group:sre"], role: admin}
+//	  - {subjects: ["group:squad-pagamentos"], role: operator, clusters: ["*-hlg", "aks-pagamentos-prd"]}
+//	  - {subjects: ["group:plantao"], role: operator, clusters: ["*-prd"], operations: [hpa_edit, nodepool_scale]}
+//	  - {subjects: ["*"], role: viewer}
+//
+// Sem arquivo, o servidor mantém o modo de token único (K8S_HPA_WEB_TOKEN) com papel admin.
+type Config struct {
+	OIDC            *OIDCConfig   `json:"oidc,omitempty"`
+	Session         SessionConfig `json:"session,omitempty"`
+	StaticTokenRole Role          `json:"static_token_role,omitempty"` // vazio = token desabilitado
+	CORSOrigins     []string      `json:"cors_origins,omitempty"`
+	Bindings        []Binding     `json:"bindings,omitempty"`
+}
+
+// OIDCConfig provedor OpenID Connect (authorization code flow com PKCE)
+type OIDCConfig struct {
+	Issuer          string   `json:"issuer"`
+	ClientID        string   `json:"client_id"`
+	ClientSecret    string   `json:"client_secret,omitempty"`
+	ClientSecretEnv string   `json:"client_secret_env,omitempty"`
+	RedirectURL     string   `json:"redirect_url"`
+	Scopes          []string `json:"scopes,omitempty"` // default: openid, profile, email
+	UsernameClaim   string   `json:"username_claim,omitempty"`
+	GroupsClaim     string   `json:"groups_claim,omitempty"`
+}
+
+// SessionConfig cookie de sessão após o login
+type SessionConfig struct {
+	TTL       string `json:"ttl,omitempty"`
+	SecretEnv string `json:"secret_env,omitempty"` // sem secret, uma chave aleatória é gerada (sessões não sobrevivem a restart)
+}
+
+// Binding concede um papel aos subjects, opcionalmente restrito a clusters e operações
+type Binding struct {
+	Subjects   []string    `json:"subjects"`             // user:<username>, group:<grupo> ou *
+	Role       Role        `json:"role"`                 // viewer, operator, admin
+	Clusters   []string    `json:"clusters,omitempty"`   // glob, sem sufixo -admin; vazio = todos
+	Operations []Operation `json:"operations,omitempty"` // restringe as operações do papel; vazio = todas
+}
+
+const (
+	defaultSessionTTL       = 8 * time.Hour
+	defaultSessionSecretEnv = "K8S_HPA_SESSION_SECRET"
+	defaultUsernameClaim    = "email"
+	defaultGroupsClaim      = "groups"
+)
+
+// DefaultConfigPath retorna ~/.k8s-hpa-manager/auth.yaml
+func DefaultConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	return filepath.Join(home, ".k8s-hpa-manager", "auth.yaml")
+}
+
+// LegacyConfig configuração usada sem auth.yaml: apenas o token único, com papel admin
+func LegacyConfig() *Config {
+	return &Config{StaticTokenRole: RoleAdmin}
+}
+
+// LoadConfig lê e valida o arquivo; retorna nil (sem erro) quando o arquivo não existe
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auth config: %w", err)
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig valida a configuração YAML
+func ParseConfig(data []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid auth config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate verifica OIDC, papéis, operações e globs de clusters
+func (c *Config) Validate() error {
+	if c.OIDC == nil && c.StaticTokenRole == "" {
+		return fmt.Errorf("auth config needs oidc or static_token_role")
+	}
+	if c.StaticTokenRole != "" && !c.StaticTokenRole.Valid() {
+		return fmt.Errorf("invalid static_token_role %q", c.StaticTokenRole)
+	}
+	if c.OIDC != nil {
+		if c.OIDC.Issuer == "" || c.OIDC.ClientID == "" || c.OIDC.RedirectURL == "" {
+			return fmt.Errorf("oidc requires issuer, client_id and redirect_url")
+		}
+	}
+	if _, err := c.Session.ttl(); err != nil {
+		return err
+	}
+
+	for i, binding := range c.Bindings {
+		if len(binding.Subjects) == 0 {
+			return fmt.Errorf("binding %d: no subjects", i)
+		}
+		for _, subject := range binding.Subjects {
+			if err := validateSubject(subject); err != nil {
+				return fmt.Errorf("binding %d: %w", i, err)
+			}
+		}
+		if !binding.Role.Valid() {
+			return fmt.Errorf("binding %d: invalid role %q", i, binding.Role)
+		}
+		for _, op := range binding.Operations {
+			if !op.Valid() {
+				return fmt.Errorf("binding %d: invalid operation %q", i, op)
+			}
+			if !binding.Role.Grants(op) {
+				return fmt.Errorf("binding %d: role %s does not grant %s", i, binding.Role, op)
+			}
+		}
+		for _, pattern := range binding.Clusters {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("binding %d: invalid cluster pattern %q: %w", i, pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// clientSecret resolve o secret direto ou pela variável de ambiente
+func (o *OIDCConfig) clientSecret() string {
+	if o.ClientSecretEnv != "" {
+		return os.Getenv(o.ClientSecretEnv)
+	}
+	return o.ClientSecret
+}
+
+func (o *OIDCConfig) scopes() []string {
+	if len(o.Scopes) > 0 {
+		return o.Scopes
+	}
+	return []string{"openid", "profile", "email"}
+}
+
+func (o *OIDCConfig) usernameClaim() string {
+	if o.UsernameClaim != "" {
+		return o.UsernameClaim
+	}
+	return defaultUsernameClaim
+}
+
+func (o *OIDCConfig) groupsClaim() string {
+	if o.GroupsClaim != "" {
+		return o.GroupsClaim
+	}
+	return defaultGroupsClaim
+}
+
+func (s SessionConfig) ttl() (time.Duration, error) {
+	if s.TTL == "" {
+		return defaultSessionTTL, nil
+	}
+	ttl, err := time.ParseDuration(s.TTL)
+	if err != nil || ttl <= 0 {
+		return 0, fmt.Errorf("invalid session ttl %q", s.TTL)
+	}
+	return ttl, nil
+}
+
+func (s SessionConfig) secretEnv() string {
+	if s.SecretEnv != "" {
+		return s.SecretEnv
+	}
+	return defaultSessionSecretEnv
+}
+
+// grantsFor resolve os grants dos bindings que casam com o usuário
+func (c *Config) grantsFor(username string, groups []string) []Grant {
+	var grants []Grant
+	for _, binding := range c.Bindings {
+		if binding.matches(username, groups) {
+			grants = append(grants, newGrant(binding))
+		}
+	}
+	return grants
+}
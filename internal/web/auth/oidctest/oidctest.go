@@ -0,0 +1,239 @@
+// Package oidctest implementa um IdP OIDC mínimo para testes e desenvolvimento local:
+// discovery, JWKS, authorize (aprova automaticamente o usuário configurado) e token
+// endpoint com PKCE.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const keyID = "oidctest"
+
+// User usuário que o IdP autentica no /authorize
+type User struct {
+	Subject           string
+	Email             string
+	PreferredUsername string
+	Groups            []string
+}
+
+// IdP servidor OIDC de teste
+type IdP struct {
+	Server   *httptest.Server
+	ClientID string
+
+	key *rsa.PrivateKey
+
+	mu    sync.Mutex
+	user  User
+	codes map[string]authorization
+}
+
+// authorization code emitido e ainda não trocado
+type authorization struct {
+	nonce     string
+	challenge string
+	user      User
+}
+
+// New inicia o IdP para o client informado em uma porta aleatória (testes)
+func New(clientID string, user User) *IdP {
+	idp := newIdP(clientID, user)
+	idp.Server = httptest.NewServer(idp.handler())
+	return idp
+}
+
+// Listen inicia o IdP em um endereço fixo (ex: 127.0.0.1:5556), para desenvolvimento local
+func Listen(addr, clientID string, user User) (*IdP, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	idp := newIdP(clientID, user)
+	idp.Server = httptest.NewUnstartedServer(idp.handler())
+	idp.Server.Listener.Close()
+	idp.Server.Listener = listener
+	idp.Server.Start()
+	return idp, nil
+}
+
+func newIdP(clientID string, user User) *IdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return &IdP{
+		ClientID: clientID,
+		key:      key,
+		user:     user,
+		codes:    make(map[string]authorization),
+	}
+}
+
+func (i *IdP) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", i.discovery)
+	mux.HandleFunc("/keys", i.jwks)
+	mux.HandleFunc("/authorize", i.authorize)
+	mux.HandleFunc("/token", i.token)
+	return mux
+}
+
+// Issuer URL do issuer
+func (i *IdP) Issuer() string {
+	return i.Server.URL
+}
+
+// Close encerra o servidor
+func (i *IdP) Close() {
+	i.Server.Close()
+}
+
+// SetUser troca o usuário autenticado nos próximos /authorize
+func (i *IdP) SetUser(user User) {
+	i.mu.Lock()
+	i.user = user
+	i.mu.Unlock()
+}
+
+// IDToken emite um ID token assinado para o usuário (útil para testar Bearer)
+func (i *IdP) IDToken(user User, nonce string, ttl time.Duration) string {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":                i.Issuer(),
+		"aud":                i.ClientID,
+		"sub":                user.Subject,
+		"email":              user.Email,
+		"preferred_username": user.PreferredUsername,
+		"groups":             user.Groups,
+		"iat":                now.Unix(),
+		"exp":                now.Add(ttl).Unix(),
+	}
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyID
+	signed, err := token.SignedString(i.key)
+	if err != nil {
+		panic(err)
+	}
+	return signed
+}
+
+func (i *IdP) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{
+		"issuer":                                i.Issuer(),
+		"authorization_endpoint":                i.Issuer() + "/authorize",
+		"token_endpoint":                        i.Issuer() + "/token",
+		"jwks_uri":                              i.Issuer() + "/keys",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (i *IdP) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := i.key.PublicKey
+	writeJSON(w, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": keyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+// authorize aprova o usuário atual e redireciona com code e state
+func (i *IdP) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != i.ClientID || query.Get("response_type") != "code" {
+		http.Error(w, "invalid client or response_type", http.StatusBadRequest)
+		return
+	}
+	redirect, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirect.Host == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	code := randomString()
+	i.mu.Lock()
+	i.codes[code] = authorization{
+		nonce:     query.Get("nonce"),
+		challenge: query.Get("code_challenge"),
+		user:      i.user,
+	}
+	i.mu.Unlock()
+
+	params := redirect.Query()
+	params.Set("code", code)
+	params.Set("state", query.Get("state"))
+	redirect.RawQuery = params.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+// token troca o code (uma única vez) validando o code_verifier
+func (i *IdP) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type")
+		return
+	}
+
+	i.mu.Lock()
+	auth, ok := i.codes[r.PostForm.Get("code")]
+	delete(i.codes, r.PostForm.Get("code"))
+	i.mu.Unlock()
+	if !ok {
+		tokenError(w, "invalid_grant")
+		return
+	}
+	if auth.challenge != "" {
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if base64.RawURLEncoding.EncodeToString(sum[:]) != auth.challenge {
+			tokenError(w, "invalid_grant")
+			return
+		}
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     i.IDToken(auth.user, auth.nonce, time.Hour),
+	})
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
+
+func randomString() string {
+	buf := make([]byte, 18)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
@@ -0,0 +1,226 @@
+package auth
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Role papel atribuído por um binding
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"   // somente leitura
+	RoleOperator Role = "operator" // edita HPAs, node pools, ConfigMaps, sessões e monitoramento
+	RoleAdmin    Role = "admin"    // tudo, inclusive drain e operações do servidor
+)
+
+// Operation operação verificada nos handlers
+type Operation string
+
+const (
+	OpRead             Operation = "read"              // qualquer GET da API
+	OpHPAEdit          Operation = "hpa_edit"          // PUT /hpas e HPAs de sessões
+	OpNodePoolScale    Operation = "nodepool_scale"    // PUT /nodepools, apply-sequential e node pools de sessões
+	OpDrain            Operation = "drain"             // cordon/drain em sequence/execute
+	OpConfigMapApply   Operation = "configmap_apply"   // PUT /configmaps
+	OpWorkloadEdit     Operation = "workload_edit"     // CronJobs, Prometheus Stack e recursos de sessões
+	OpSessionManage    Operation = "session_manage"    // salvar, editar, renomear, remover e agendar sessões
+	OpMonitoringManage Operation = "monitoring_manage" // targets, start/stop, alertas e silences
+	OpAdmin            Operation = "admin"             // shutdown, regras, limpeza de logs/histórico, troca de contexto/subscription
+)
+
+// roleOperations operações concedidas por cada papel
+var roleOperations = map[Role][]Operation{
+	RoleViewer: {OpRead},
+	RoleOperator: {
+		OpRead, OpHPAEdit, OpNodePoolScale, OpConfigMapApply, OpWorkloadEdit,
+		OpSessionManage, OpMonitoringManage,
+	},
+	RoleAdmin: {
+		OpRead, OpHPAEdit, OpNodePoolScale, OpDrain, OpConfigMapApply, OpWorkloadEdit,
+		OpSessionManage, OpMonitoringManage, OpAdmin,
+	},
+}
+
+// Valid indica se o papel é conhecido
+func (r Role) Valid() bool {
+	_, ok := roleOperations[r]
+	return ok
+}
+
+// Grants indica se o papel concede a operação
+func (r Role) Grants(op Operation) bool {
+	for _, granted := range roleOperations[r] {
+		if granted == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Valid indica se a operação é conhecida
+func (op Operation) Valid() bool {
+	return RoleAdmin.Grants(op)
+}
+
+// Grant permissões efetivas de um binding para a identidade
+type Grant struct {
+	Role       Role        `json:"role"`
+	Clusters   []string    `json:"clusters,omitempty"` // vazio = todos os clusters
+	Operations []Operation `json:"operations"`
+}
+
+// allows indica se o grant cobre a operação no cluster ("" = em algum cluster,
+// AllClusters = em todos os clusters)
+func (g Grant) allows(op Operation, cluster string) bool {
+	granted := false
+	for _, candidate := range g.Operations {
+		if candidate == op {
+			granted = true
+			break
+		}
+	}
+	if !granted {
+		return false
+	}
+	if cluster == "" || len(g.Clusters) == 0 {
+		return true
+	}
+	return matchCluster(g.Clusters, cluster) // AllClusters só casa com o padrão "*"
+}
+
+// newGrant resolve as operações de um binding: as do papel, opcionalmente restritas por
+// binding.Operations. OpAdmin só vale em bindings sem restrição de cluster, pois
+// afeta o servidor inteiro.
+func newGrant(binding Binding) Grant {
+	grant := Grant{Role: binding.Role, Clusters: binding.Clusters}
+	for _, op := range roleOperations[binding.Role] {
+		if op == OpAdmin && len(binding.Clusters) > 0 {
+			continue
+		}
+		if len(binding.Operations) > 0 && op != OpRead && !containsOperation(binding.Operations, op) {
+			continue
+		}
+		grant.Operations = append(grant.Operations, op)
+	}
+	return grant
+}
+
+func containsOperation(ops []Operation, op Operation) bool {
+	for _, candidate := range ops {
+		if candidate == op {
+			return true
+		}
+	}
+	return false
+}
+
+// matchCluster compara clusters com glob ignorando o sufixo -admin dos contextos kubectl
+func matchCluster(patterns []string, cluster string) bool {
+	cluster = normalizeCluster(cluster)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(normalizeCluster(pattern), cluster); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeCluster(cluster string) string {
+	return strings.TrimSuffix(strings.TrimSpace(cluster), "-admin")
+}
+
+// AllClusters exige grant sem restrição de cluster (ex: silence sem filtro de cluster)
+const AllClusters = "*"
+
+// Identity usuário autenticado da requisição
+type Identity struct {
+	Subject  string   `json:"subject"`
+	Username string   `json:"username"`
+	Email    string   `json:"email,omitempty"`
+	Groups   []string `json:"groups,omitempty"`
+	Method   string   `json:"method"` // oidc, token
+	Grants   []Grant  `json:"grants"`
+}
+
+// Métodos de autenticação
+const (
+	MethodOIDC  = "oidc"
+	MethodToken = "token"
+)
+
+// Can indica se a identidade pode executar a operação no cluster
+// ("" = em algum cluster, AllClusters = em todos)
+func (id *Identity) Can(op Operation, cluster string) bool {
+	if id == nil {
+		return false
+	}
+	for _, grant := range id.Grants {
+		if grant.allows(op, cluster) {
+			return true
+		}
+	}
+	return false
+}
+
+// Name nome exibido em logs e no histórico
+func (id *Identity) Name() string {
+	if id == nil {
+		return ""
+	}
+	if id.Username != "" {
+		return id.Username
+	}
+	return id.Subject
+}
+
+// Roles papéis distintos da identidade, ordenados
+func (id *Identity) Roles() []string {
+	seen := make(map[Role]bool)
+	var roles []string
+	for _, grant := range id.Grants {
+		if !seen[grant.Role] {
+			seen[grant.Role] = true
+			roles = append(roles, string(grant.Role))
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// matches indica se o binding se aplica ao usuário/grupos
+func (b Binding) matches(username string, groups []string) bool {
+	for _, subject := range b.Subjects {
+		switch {
+		case subject == "*":
+			return true
+		case strings.HasPrefix(subject, "user:"):
+			if strings.EqualFold(strings.TrimPrefix(subject, "user:"), username) {
+				return true
+			}
+		case strings.HasPrefix(subject, "group:"):
+			group := strings.TrimPrefix(subject, "group:")
+			for _, candidate := range groups {
+				if candidate == group {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// validateSubject aceita "*", "user:<nome>" e "group:<grupo>"
+func validateSubject(subject string) error {
+	if subject == "*" {
+		return nil
+	}
+	for _, prefix := range []string{"user:", "group:"} {
+		if strings.HasPrefix(subject, prefix) && len(subject) > len(prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid subject %q (use user:<name>, group:<name> or *)", subject)
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Cookies do login
+const (
+	SessionCookie = "hpa_session"
+	stateCookie   = "hpa_oidc_state"
+)
+
+// stateTTL tempo máximo entre /auth/login e /auth/callback
+const stateTTL = 10 * time.Minute
+
+var errInvalidCookie = errors.New("invalid or expired cookie")
+
+// sessionPayload conteúdo do cookie de sessão. Os grants não são gravados: são
+// resolvidos dos bindings a cada requisição, então mudanças no auth.yaml valem na hora.
+type sessionPayload struct {
+	Subject   string   `json:"sub"`
+	Username  string   `json:"usr"`
+	Email     string   `json:"eml,omitempty"`
+	Groups    []string `json:"grp,omitempty"`
+	ExpiresAt int64    `json:"exp"`
+}
+
+// statePayload dados do login em andamento (cookie de curta duração)
+type statePayload struct {
+	State     string `json:"state"`
+	Nonce     string `json:"nonce"`
+	Verifier  string `json:"verifier"`
+	ReturnTo  string `json:"return_to,omitempty"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// signer assina cookies com HMAC-SHA256 (payload.assinatura em base64url)
+type signer struct {
+	secret []byte
+}
+
+func (s signer) encode(payload interface{}) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	body := base64.RawURLEncoding.EncodeToString(data)
+	return body + "." + s.sign(body), nil
+}
+
+func (s signer) decode(value string, payload interface{}) error {
+	body, signature, ok := strings.Cut(value, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.sign(body))) {
+		return errInvalidCookie
+	}
+	data, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return errInvalidCookie
+	}
+	if err := json.Unmarshal(data, payload); err != nil {
+		return errInvalidCookie
+	}
+	return nil
+}
+
+func (s signer) sign(body string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(body))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// randomString valor aleatório em base64url (state, nonce, secret de sessão)
+func randomString(bytes int) string {
+	buf := make([]byte, bytes)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err) // crypto/rand não falha em plataformas suportadas
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
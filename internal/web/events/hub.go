@@ -0,0 +1,286 @@
+package events
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tipos de evento publicados em GET /api/v1/events
+const (
+	TypeHPAAdded          = "hpa.added"
+	TypeHPAUpdated        = "hpa.updated" // spec ou condições mudaram
+	TypeHPAScaled         = "hpa.scaled"  // réplicas atuais/desejadas mudaram
+	TypeHPADeleted        = "hpa.deleted"
+	TypeNodePoolUpdated   = "nodepool.updated"
+	TypeAnomaly           = "anomaly"
+	TypeHistory           = "history"
+	TypeOperationProgress = "operation.progress"
+
+	// TypeReset avisa o cliente que eventos foram perdidos (Last-Event-ID fora do buffer
+	// ou de outro processo): ele deve recarregar o estado pela API REST
+	TypeReset = "reset"
+)
+
+// Operações longas publicadas em operation.progress
+const (
+	OperationNodePoolSequence = "nodepool_sequence" // cordon/drain (mesmo conteúdo do SSE de sequence/progress)
+	OperationSessionApply     = "session_apply"     // itens aplicados de uma sessão
+)
+
+// Valores padrão do Hub
+const (
+	DefaultBufferSize     = 1000 // eventos mantidos para retomada via Last-Event-ID
+	DefaultSubscriberSize = 256  // eventos pendentes por cliente antes de desconectá-lo
+)
+
+// Event evento entregue aos clientes SSE
+type Event struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	Cluster   string      `json:"cluster,omitempty"`
+	Namespace string      `json:"namespace,omitempty"`
+	Time      time.Time   `json:"time"`
+	Data      interface{} `json:"data,omitempty"`
+
+	seq uint64
+}
+
+// Filter assinatura de um cliente. Listas vazias aceitam tudo.
+type Filter struct {
+	Clusters   []string
+	Namespaces []string // só se aplica a eventos de namespace (HPAs e anomalias)
+	Types      []string // tipo exato ("hpa.scaled") ou grupo ("hpa")
+
+	// Permit permissão de leitura por cluster (nil = todos)
+	Permit func(cluster string) bool
+}
+
+// Match indica se o evento deve ser entregue ao cliente
+func (f Filter) Match(e Event) bool {
+	if e.Type == TypeReset {
+		return true
+	}
+	if e.Cluster != "" && f.Permit != nil && !f.Permit(e.Cluster) {
+		return false
+	}
+	if len(f.Clusters) > 0 && e.Cluster != "" && !containsCluster(f.Clusters, e.Cluster) {
+		return false
+	}
+	if len(f.Namespaces) > 0 && e.Namespace != "" && !contains(f.Namespaces, e.Namespace) {
+		return false
+	}
+	if len(f.Types) > 0 {
+		group, _, _ := strings.Cut(e.Type, ".")
+		if !contains(f.Types, e.Type) && !contains(f.Types, group) {
+			return false
+		}
+	}
+	return true
+}
+
+// OperationProgress dados do evento operation.progress
+type OperationProgress struct {
+	OperationID string      `json:"operation_id"`
+	Kind        string      `json:"kind"`
+	Progress    interface{} `json:"progress"`
+}
+
+// Subscription cliente conectado ao Hub. Events é fechado quando o cliente é removido,
+// fica para trás (buffer cheio) ou o Hub é encerrado; o cliente reconecta com Last-Event-ID.
+type Subscription struct {
+	Events <-chan Event
+	events chan Event
+	filter Filter
+}
+
+// Hub distribui eventos aos clientes e mantém os últimos em memória para retomada
+type Hub struct {
+	mu          sync.Mutex
+	epoch       string // identifica o processo: IDs de outra execução não são retomáveis
+	seq         uint64
+	buffer      []Event // anel com os últimos eventos
+	next        int
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+// NewHub cria um Hub que guarda os últimos bufferSize eventos
+func NewHub(bufferSize int) *Hub {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Hub{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		buffer:      make([]Event, 0, bufferSize),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish atribui ID ao evento e o entrega aos clientes interessados.
+// Nunca bloqueia: clientes com o buffer cheio são desconectados. Hub nil ignora o evento.
+func (h *Hub) Publish(e Event) Event {
+	if h == nil {
+		return e
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return e
+	}
+
+	h.seq++
+	e.seq = h.seq
+	e.ID = fmt.Sprintf("%s-%d", h.epoch, h.seq)
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	if len(h.buffer) < cap(h.buffer) {
+		h.buffer = append(h.buffer, e)
+	} else {
+		h.buffer[h.next] = e
+		h.next = (h.next + 1) % len(h.buffer)
+	}
+
+	for sub := range h.subscribers {
+		if !sub.filter.Match(e) {
+			continue
+		}
+		select {
+		case sub.events <- e:
+		default:
+			h.removeLocked(sub)
+		}
+	}
+	return e
+}
+
+// PublishProgress publica o progresso de uma operação longa
+func (h *Hub) PublishProgress(kind, operationID, cluster string, progress interface{}) {
+	h.Publish(Event{
+		Type:    TypeOperationProgress,
+		Cluster: cluster,
+		Data:    OperationProgress{OperationID: operationID, Kind: kind, Progress: progress},
+	})
+}
+
+// Subscribe registra um cliente. Com lastEventID, devolve os eventos posteriores ainda
+// no buffer; se não for possível retomar, o backlog é um único evento reset (com o ID
+// atual, para a próxima reconexão retomar a partir dele) e o estado deve ser recarregado.
+func (h *Hub) Subscribe(filter Filter, lastEventID string) (*Subscription, []Event, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, nil, fmt.Errorf("event hub is closed")
+	}
+
+	var backlog []Event
+	if lastEventID != "" {
+		var reset bool
+		if backlog, reset = h.replayLocked(filter, lastEventID); reset {
+			backlog = []Event{{
+				ID:   fmt.Sprintf("%s-%d", h.epoch, h.seq),
+				Type: TypeReset,
+				Time: time.Now(),
+				Data: map[string]string{"message": "Events since Last-Event-ID are no longer available; reload state"},
+				seq:  h.seq,
+			}}
+		}
+	}
+
+	events := make(chan Event, DefaultSubscriberSize)
+	sub := &Subscription{Events: events, events: events, filter: filter}
+	h.subscribers[sub] = struct{}{}
+	return sub, backlog, nil
+}
+
+// Unsubscribe remove o cliente (idempotente)
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeLocked(sub)
+}
+
+// Subscribers número de clientes conectados
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers)
+}
+
+// Close desconecta todos os clientes; Publish passa a ser ignorado
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for sub := range h.subscribers {
+		h.removeLocked(sub)
+	}
+}
+
+func (h *Hub) removeLocked(sub *Subscription) {
+	if _, ok := h.subscribers[sub]; !ok {
+		return
+	}
+	delete(h.subscribers, sub)
+	close(sub.events)
+}
+
+// replayLocked eventos do buffer posteriores a lastEventID
+func (h *Hub) replayLocked(filter Filter, lastEventID string) ([]Event, bool) {
+	epoch, seqText, ok := strings.Cut(lastEventID, "-")
+	if !ok || epoch != h.epoch {
+		return nil, true
+	}
+	seq, err := strconv.ParseUint(seqText, 10, 64)
+	if err != nil || seq > h.seq {
+		return nil, true
+	}
+	if seq == h.seq {
+		return nil, false
+	}
+
+	// Mais antigo disponível: se o seguinte ao último recebido já saiu do anel, houve perda
+	oldest := h.buffer[h.next].seq
+	if seq+1 < oldest {
+		return nil, true
+	}
+
+	var backlog []Event
+	for i := 0; i < len(h.buffer); i++ {
+		e := h.buffer[(h.next+i)%len(h.buffer)]
+		if e.seq > seq && filter.Match(e) {
+			backlog = append(backlog, e)
+		}
+	}
+	return backlog, false
+}
+
+// SameCluster compara nomes de cluster com ou sem o sufixo -admin do contexto
+func SameCluster(a, b string) bool {
+	return strings.TrimSuffix(a, "-admin") == strings.TrimSuffix(b, "-admin")
+}
+
+func containsCluster(clusters []string, cluster string) bool {
+	for _, c := range clusters {
+		if SameCluster(c, cluster) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
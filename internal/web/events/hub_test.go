@@ -0,0 +1,127 @@
+package events
+
+import (
+	"testing"
+)
+
+func TestFilterMatch(t *testing.T) {
+	filter := Filter{
+		Clusters:   []string{"akspriv-prd-admin"},
+		Namespaces: []string{"api"},
+		Types:      []string{"hpa", TypeAnomaly},
+	}
+
+	tests := []struct {
+		name  string
+		event Event
+		want  bool
+	}{
+		{"hpa same cluster", Event{Type: TypeHPAScaled, Cluster: "akspriv-prd-admin", Namespace: "api"}, true},
+		{"anomaly without -admin", Event{Type: TypeAnomaly, Cluster: "akspriv-prd", Namespace: "api"}, true},
+		{"other cluster", Event{Type: TypeHPAScaled, Cluster: "akspriv-hlg-admin", Namespace: "api"}, false},
+		{"other namespace", Event{Type: TypeHPAUpdated, Cluster: "akspriv-prd-admin", Namespace: "jobs"}, false},
+		{"type not subscribed", Event{Type: TypeHistory, Cluster: "akspriv-prd-admin", Namespace: "api"}, false},
+		{"reset always delivered", Event{Type: TypeReset}, true},
+	}
+	for _, tt := range tests {
+		if got := filter.Match(tt.event); got != tt.want {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Eventos sem namespace (node pools) passam pelo filtro de namespace
+	pools := Filter{Namespaces: []string{"api"}}
+	if !pools.Match(Event{Type: TypeNodePoolUpdated, Cluster: "akspriv-prd-admin"}) {
+		t.Errorf("cluster-scoped event should ignore namespace filter")
+	}
+
+	denied := Filter{Permit: func(cluster string) bool { return cluster == "akspriv-hlg-admin" }}
+	if denied.Match(Event{Type: TypeHPAScaled, Cluster: "akspriv-prd-admin"}) {
+		t.Errorf("event of cluster without read permission must be filtered")
+	}
+}
+
+func TestHubResume(t *testing.T) {
+	hub := NewHub(3)
+	first := hub.Publish(Event{Type: TypeHistory, Cluster: "a"})
+	second := hub.Publish(Event{Type: TypeHistory, Cluster: "b"})
+	hub.Publish(Event{Type: TypeHistory, Cluster: "a"})
+
+	// Retomada dentro do buffer: só eventos posteriores e do filtro
+	_, backlog, err := hub.Subscribe(Filter{Clusters: []string{"a"}}, first.ID)
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if len(backlog) != 1 || backlog[0].seq != 3 {
+		t.Errorf("expected event 3 in backlog, got %+v", backlog)
+	}
+
+	// Em dia: nada a reenviar
+	last := hub.Publish(Event{Type: TypeHistory})
+	if _, backlog, _ := hub.Subscribe(Filter{}, last.ID); len(backlog) != 0 {
+		t.Errorf("up to date client: backlog=%+v", backlog)
+	}
+
+	// O evento 2 saiu do anel de 3: quem parou no 1 perdeu eventos
+	latest := hub.Publish(Event{Type: TypeHistory})
+	_, backlog, _ = hub.Subscribe(Filter{}, first.ID)
+	if len(backlog) != 1 || backlog[0].Type != TypeReset {
+		t.Fatalf("expected reset for event outside buffer, got %+v", backlog)
+	}
+	// O reset carrega o ID atual: a próxima reconexão retoma normalmente
+	if backlog[0].ID != latest.ID {
+		t.Errorf("reset ID = %q, want %q", backlog[0].ID, latest.ID)
+	}
+	if _, backlog, _ := hub.Subscribe(Filter{}, backlog[0].ID); len(backlog) != 0 {
+		t.Errorf("resume after reset: backlog=%+v", backlog)
+	}
+	if _, backlog, _ := hub.Subscribe(Filter{}, second.ID); len(backlog) != 3 || backlog[0].Type == TypeReset {
+		t.Errorf("resume from oldest-1: backlog=%+v", backlog)
+	}
+
+	// ID de outro processo ou inválido
+	for _, id := range []string{"otherepoch-1", "garbage", hub.epoch + "-999"} {
+		if _, backlog, _ := hub.Subscribe(Filter{}, id); len(backlog) != 1 || backlog[0].Type != TypeReset {
+			t.Errorf("expected reset for %q, got %+v", id, backlog)
+		}
+	}
+}
+
+func TestHubDelivery(t *testing.T) {
+	hub := NewHub(10)
+	sub, _, err := hub.Subscribe(Filter{Types: []string{TypeAnomaly}}, "")
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	hub.Publish(Event{Type: TypeHistory})
+	hub.Publish(Event{Type: TypeAnomaly, Cluster: "a"})
+	if e := <-sub.Events; e.Type != TypeAnomaly || e.ID == "" || e.Time.IsZero() {
+		t.Errorf("unexpected event: %+v", e)
+	}
+
+	// Cliente lento: desconectado quando o buffer enche
+	for i := 0; i <= DefaultSubscriberSize; i++ {
+		hub.Publish(Event{Type: TypeAnomaly})
+	}
+	if hub.Subscribers() != 0 {
+		t.Fatalf("slow subscriber should be dropped")
+	}
+	drained := 0
+	for range sub.Events {
+		drained++
+	}
+	if drained != DefaultSubscriberSize {
+		t.Errorf("expected %d buffered events before close, got %d", DefaultSubscriberSize, drained)
+	}
+	hub.Unsubscribe(sub) // idempotente
+
+	other, _, _ := hub.Subscribe(Filter{}, "")
+	hub.Close()
+	if _, ok := <-other.Events; ok {
+		t.Errorf("Close should close subscriber channels")
+	}
+	if _, _, err := hub.Subscribe(Filter{}, ""); err == nil {
+		t.Errorf("Subscribe after Close should fail")
+	}
+}
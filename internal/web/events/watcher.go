@@ -0,0 +1,384 @@
+package events
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	kubeclient "k8s-hpa-manager/internal/kubernetes"
+	"k8s-hpa-manager/internal/models"
+)
+
+// DefaultIdleTimeout tempo que os informers de um cluster continuam ativos após o último
+// cliente sair (evita relistar HPAs e nodes a cada reload da página)
+const DefaultIdleTimeout = 2 * time.Minute
+
+// ClientFunc cria um cliente sem timeout de requisição para o cluster (KubeConfigManager.GetWatchClient)
+type ClientFunc func(cluster string) (kubernetes.Interface, error)
+
+// HPACondition condição do status do HPA (AbleToScale, ScalingActive, ScalingLimited)
+type HPACondition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// HPAStatus réplicas e condições reportadas pelo controlador do HPA
+type HPAStatus struct {
+	CurrentReplicas int32          `json:"current_replicas"`
+	DesiredReplicas int32          `json:"desired_replicas"`
+	LastScaleTime   *time.Time     `json:"last_scale_time,omitempty"`
+	Conditions      []HPACondition `json:"conditions,omitempty"`
+}
+
+// HPAEvent dados dos eventos hpa.*
+type HPAEvent struct {
+	HPA      models.HPA `json:"hpa"`
+	Status   HPAStatus  `json:"status"`
+	Previous *HPAStatus `json:"previous,omitempty"` // hpa.updated e hpa.scaled
+}
+
+// NodePoolState dados do evento nodepool.updated (visão do Kubernetes, não do provedor)
+type NodePoolState struct {
+	Name          string `json:"name"`
+	Nodes         int    `json:"nodes"`
+	Ready         int    `json:"ready"`
+	Unschedulable int    `json:"unschedulable"`
+}
+
+// Watcher mantém informers de HPAs e nodes dos clusters que têm clientes conectados
+// e publica as mudanças no Hub
+type Watcher struct {
+	hub         *Hub
+	newClient   ClientFunc
+	idleTimeout time.Duration
+
+	mu       sync.Mutex
+	clusters map[string]*clusterWatch
+	closed   bool
+}
+
+// NewWatcher cria um Watcher que publica no hub
+func NewWatcher(hub *Hub, newClient ClientFunc) *Watcher {
+	return &Watcher{
+		hub:         hub,
+		newClient:   newClient,
+		idleTimeout: DefaultIdleTimeout,
+		clusters:    make(map[string]*clusterWatch),
+	}
+}
+
+// Acquire inicia (ou reaproveita) os informers do cluster para mais um cliente
+func (w *Watcher) Acquire(cluster string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return fmt.Errorf("event watcher is closed")
+	}
+	if cw, ok := w.clusters[cluster]; ok {
+		cw.refs++
+		if cw.idle != nil {
+			cw.idle.Stop()
+			cw.idle = nil
+		}
+		return nil
+	}
+
+	client, err := w.newClient(cluster)
+	if err != nil {
+		return fmt.Errorf("failed to create watch client for %s: %w", cluster, err)
+	}
+
+	cw := newClusterWatch(cluster, w.hub)
+	cw.refs = 1
+	cw.start(client)
+	w.clusters[cluster] = cw
+	fmt.Printf("👀 Informers iniciados para o cluster %s\n", cluster)
+	return nil
+}
+
+// Release libera o cluster; os informers param após idleTimeout sem clientes
+func (w *Watcher) Release(cluster string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	cw, ok := w.clusters[cluster]
+	if !ok {
+		return
+	}
+	cw.refs--
+	if cw.refs > 0 {
+		return
+	}
+
+	cw.idle = time.AfterFunc(w.idleTimeout, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if cw.refs == 0 && w.clusters[cluster] == cw {
+			close(cw.stop)
+			delete(w.clusters, cluster)
+			fmt.Printf("👀 Informers encerrados para o cluster %s (sem clientes)\n", cluster)
+		}
+	})
+}
+
+// Clusters clusters com informers ativos
+func (w *Watcher) Clusters() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	clusters := make([]string, 0, len(w.clusters))
+	for cluster := range w.clusters {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+	return clusters
+}
+
+// Close para todos os informers
+func (w *Watcher) Close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	for cluster, cw := range w.clusters {
+		if cw.idle != nil {
+			cw.idle.Stop()
+		}
+		close(cw.stop)
+		delete(w.clusters, cluster)
+	}
+}
+
+// clusterWatch informers de um cluster
+type clusterWatch struct {
+	cluster string
+	hub     *Hub
+	refs    int
+	stop    chan struct{}
+	idle    *time.Timer
+
+	mu    sync.Mutex
+	nodes map[string]nodeInfo      // node -> pool/estado
+	pools map[string]NodePoolState // último estado publicado por pool
+}
+
+type nodeInfo struct {
+	pool          string
+	ready         bool
+	unschedulable bool
+}
+
+func newClusterWatch(cluster string, hub *Hub) *clusterWatch {
+	return &clusterWatch{
+		cluster: cluster,
+		hub:     hub,
+		stop:    make(chan struct{}),
+		nodes:   make(map[string]nodeInfo),
+		pools:   make(map[string]NodePoolState),
+	}
+}
+
+func (cw *clusterWatch) start(client kubernetes.Interface) {
+	factory := informers.NewSharedInformerFactory(client, 0)
+
+	hpaInformer := factory.Autoscaling().V2().HorizontalPodAutoscalers().Informer()
+	cw.setWatchErrorHandler(hpaInformer, "HPAs")
+	hpaInformer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if hpa, ok := obj.(*autoscalingv2.HorizontalPodAutoscaler); ok && !isInInitialList {
+				cw.hpaAdded(hpa)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldHPA, okOld := oldObj.(*autoscalingv2.HorizontalPodAutoscaler)
+			newHPA, okNew := newObj.(*autoscalingv2.HorizontalPodAutoscaler)
+			if okOld && okNew {
+				cw.hpaUpdated(oldHPA, newHPA)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if hpa, ok := obj.(*autoscalingv2.HorizontalPodAutoscaler); ok {
+				cw.hpaDeleted(hpa)
+			}
+		},
+	})
+
+	nodeInformer := factory.Core().V1().Nodes().Informer()
+	cw.setWatchErrorHandler(nodeInformer, "nodes")
+	nodeInformer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if node, ok := obj.(*corev1.Node); ok {
+				cw.nodeChanged(node.Name, node, !isInInitialList)
+			}
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			if node, ok := newObj.(*corev1.Node); ok {
+				cw.nodeChanged(node.Name, node, true)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				cw.nodeChanged(tombstone.Key, nil, true)
+				return
+			}
+			if node, ok := obj.(*corev1.Node); ok {
+				cw.nodeChanged(node.Name, nil, true)
+			}
+		},
+	})
+
+	factory.Start(cw.stop)
+}
+
+func (cw *clusterWatch) setWatchErrorHandler(informer cache.SharedIndexInformer, resource string) {
+	err := informer.SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
+		fmt.Printf("⚠️  Watch de %s falhou no cluster %s: %v\n", resource, cw.cluster, err)
+	})
+	if err != nil {
+		fmt.Printf("⚠️  Falha ao configurar watch de %s no cluster %s: %v\n", resource, cw.cluster, err)
+	}
+}
+
+func (cw *clusterWatch) hpaAdded(hpa *autoscalingv2.HorizontalPodAutoscaler) {
+	cw.publishHPA(TypeHPAAdded, hpa, nil)
+}
+
+// hpaUpdated publica hpa.updated (spec/condições) e hpa.scaled (réplicas). Atualizações
+// só de métricas correntes, feitas a cada sync do controlador, não geram evento.
+func (cw *clusterWatch) hpaUpdated(oldHPA, newHPA *autoscalingv2.HorizontalPodAutoscaler) {
+	previous := hpaStatus(oldHPA)
+	current := hpaStatus(newHPA)
+
+	if oldHPA.Generation != newHPA.Generation || !sameConditions(previous.Conditions, current.Conditions) {
+		cw.publishHPA(TypeHPAUpdated, newHPA, &previous)
+	}
+	if previous.CurrentReplicas != current.CurrentReplicas || previous.DesiredReplicas != current.DesiredReplicas {
+		cw.publishHPA(TypeHPAScaled, newHPA, &previous)
+	}
+}
+
+func (cw *clusterWatch) hpaDeleted(hpa *autoscalingv2.HorizontalPodAutoscaler) {
+	cw.publishHPA(TypeHPADeleted, hpa, nil)
+}
+
+func (cw *clusterWatch) publishHPA(eventType string, hpa *autoscalingv2.HorizontalPodAutoscaler, previous *HPAStatus) {
+	cw.hub.Publish(Event{
+		Type:      eventType,
+		Cluster:   cw.cluster,
+		Namespace: hpa.Namespace,
+		Data: HPAEvent{
+			HPA:      kubeclient.HPAFromKubernetes(cw.cluster, hpa),
+			Status:   hpaStatus(hpa),
+			Previous: previous,
+		},
+	})
+}
+
+// nodeChanged atualiza o estado do node (nil = removido) e publica nodepool.updated
+// quando a contagem do pool muda. Durante a listagem inicial só registra o estado.
+func (cw *clusterWatch) nodeChanged(name string, node *corev1.Node, publish bool) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	affected := map[string]bool{}
+	if old, ok := cw.nodes[name]; ok {
+		affected[old.pool] = true
+	}
+	if node == nil {
+		delete(cw.nodes, name)
+	} else {
+		info := nodeInfo{
+			pool:          kubeclient.NodePoolName(node),
+			ready:         nodeReady(node),
+			unschedulable: node.Spec.Unschedulable,
+		}
+		cw.nodes[name] = info
+		affected[info.pool] = true
+	}
+
+	for pool := range affected {
+		if pool == "" {
+			continue
+		}
+		state := cw.poolStateLocked(pool)
+		if last, ok := cw.pools[pool]; ok && last == state {
+			continue
+		}
+		cw.pools[pool] = state
+		if publish {
+			cw.hub.Publish(Event{Type: TypeNodePoolUpdated, Cluster: cw.cluster, Data: state})
+		}
+	}
+}
+
+func (cw *clusterWatch) poolStateLocked(pool string) NodePoolState {
+	state := NodePoolState{Name: pool}
+	for _, info := range cw.nodes {
+		if info.pool != pool {
+			continue
+		}
+		state.Nodes++
+		if info.ready {
+			state.Ready++
+		}
+		if info.unschedulable {
+			state.Unschedulable++
+		}
+	}
+	return state
+}
+
+func hpaStatus(hpa *autoscalingv2.HorizontalPodAutoscaler) HPAStatus {
+	status := HPAStatus{
+		CurrentReplicas: hpa.Status.CurrentReplicas,
+		DesiredReplicas: hpa.Status.DesiredReplicas,
+	}
+	if hpa.Status.LastScaleTime != nil {
+		lastScale := hpa.Status.LastScaleTime.Time
+		status.LastScaleTime = &lastScale
+	}
+	for _, condition := range hpa.Status.Conditions {
+		status.Conditions = append(status.Conditions, HPACondition{
+			Type:    string(condition.Type),
+			Status:  string(condition.Status),
+			Reason:  condition.Reason,
+			Message: condition.Message,
+		})
+	}
+	return status
+}
+
+// sameConditions compara tipo, status e motivo (a mensagem inclui valores de métricas)
+func sameConditions(a, b []HPACondition) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Type != b[i].Type || a[i].Status != b[i].Status || a[i].Reason != b[i].Reason {
+			return false
+		}
+	}
+	return true
+}
+
+func nodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testHPA(generation int64, current, desired int32, reason, message string) *autoscalingv2.HorizontalPodAutoscaler {
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "api", Generation: generation},
+		Spec:       autoscalingv2.HorizontalPodAutoscalerSpec{MaxReplicas: 10},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: current,
+			DesiredReplicas: desired,
+			Conditions: []autoscalingv2.HorizontalPodAutoscalerCondition{
+				{Type: autoscalingv2.ScalingLimited, Status: corev1.ConditionFalse, Reason: reason, Message: message},
+			},
+		},
+	}
+}
+
+func collect(sub *Subscription) []Event {
+	var events []Event
+	for {
+		select {
+		case e := <-sub.Events:
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+func TestHPAUpdated(t *testing.T) {
+	hub := NewHub(10)
+	sub, _, _ := hub.Subscribe(Filter{}, "")
+	cw := newClusterWatch("akspriv-prd-admin", hub)
+
+	base := testHPA(1, 2, 2, "DesiredWithinRange", "cpu 40%")
+
+	// Só a mensagem (valores de métricas) mudou: nenhum evento
+	cw.hpaUpdated(base, testHPA(1, 2, 2, "DesiredWithinRange", "cpu 55%"))
+	if events := collect(sub); len(events) != 0 {
+		t.Fatalf("metric-only update should not publish, got %+v", events)
+	}
+
+	cw.hpaUpdated(base, testHPA(1, 2, 4, "DesiredWithinRange", "cpu 90%"))
+	events := collect(sub)
+	if len(events) != 1 || events[0].Type != TypeHPAScaled {
+		t.Fatalf("expected hpa.scaled, got %+v", events)
+	}
+	data := events[0].Data.(HPAEvent)
+	if data.Status.DesiredReplicas != 4 || data.Previous.DesiredReplicas != 2 || data.HPA.Cluster != "akspriv-prd-admin" {
+		t.Errorf("unexpected hpa.scaled data: %+v", data)
+	}
+	if events[0].Namespace != "api" {
+		t.Errorf("expected namespace api, got %q", events[0].Namespace)
+	}
+
+	// Spec alterado e limite atingido
+	cw.hpaUpdated(base, testHPA(2, 2, 2, "TooManyReplicas", "cpu 40%"))
+	if events := collect(sub); len(events) != 1 || events[0].Type != TypeHPAUpdated {
+		t.Errorf("expected hpa.updated, got %+v", events)
+	}
+}
+
+func testNode(name, pool string, ready, unschedulable bool) *corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"agentpool": pool}},
+		Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+		Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}}},
+	}
+}
+
+func TestNodeChanged(t *testing.T) {
+	hub := NewHub(10)
+	sub, _, _ := hub.Subscribe(Filter{}, "")
+	cw := newClusterWatch("akspriv-prd-admin", hub)
+
+	// Listagem inicial: só registra
+	cw.nodeChanged("n1", testNode("n1", "monitoring", true, false), false)
+	cw.nodeChanged("n2", testNode("n2", "monitoring", true, false), false)
+	if events := collect(sub); len(events) != 0 {
+		t.Fatalf("initial list should not publish, got %+v", events)
+	}
+
+	// Heartbeat sem mudança de estado: nada
+	cw.nodeChanged("n1", testNode("n1", "monitoring", true, false), true)
+	if events := collect(sub); len(events) != 0 {
+		t.Fatalf("unchanged node should not publish, got %+v", events)
+	}
+
+	cw.nodeChanged("n1", testNode("n1", "monitoring", true, true), true)
+	cw.nodeChanged("n2", nil, true)
+	events := collect(sub)
+	if len(events) != 2 {
+		t.Fatalf("expected 2 nodepool events, got %+v", events)
+	}
+	want := NodePoolState{Name: "monitoring", Nodes: 1, Ready: 1, Unschedulable: 1}
+	if got := events[1].Data.(NodePoolState); got != want {
+		t.Errorf("pool state = %+v, want %+v", got, want)
+	}
+}
+
+func TestWatcherLifecycle(t *testing.T) {
+	hub := NewHub(10)
+	created := 0
+	w := NewWatcher(hub, func(string) (kubernetes.Interface, error) {
+		created++
+		return fake.NewSimpleClientset(), nil
+	})
+	w.idleTimeout = 10 * time.Millisecond
+	defer w.Close()
+
+	if err := w.Acquire("akspriv-prd-admin"); err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	if err := w.Acquire("akspriv-prd-admin"); err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	if created != 1 {
+		t.Errorf("informers should be shared, created %d clients", created)
+	}
+
+	w.Release("akspriv-prd-admin")
+	w.Release("akspriv-prd-admin")
+	deadline := time.Now().Add(time.Second)
+	for len(w.Clusters()) != 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if clusters := w.Clusters(); len(clusters) != 0 {
+		t.Errorf("informers should stop after idle timeout, still watching %v", clusters)
+	}
+}
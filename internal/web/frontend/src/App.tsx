@@ -28,8 +28,19 @@ const App = () => {
     if (token) {
       apiClient.setToken(token);
       setIsAuthenticated(true);
+      setIsChecking(false);
+      return;
     }
-    setIsChecking(false);
+
+    // Sessão OIDC (cookie definido por /auth/callback)
+    fetch("/api/v1/auth/me")
+      .then((response) => {
+        if (response.ok) {
+          setIsAuthenticated(true);
+        }
+      })
+      .catch(() => {})
+      .finally(() => setIsChecking(false));
   }, []);
 
   const handleLogin = () => {
@@ -38,7 +49,10 @@ const App = () => {
 
   const handleLogout = () => {
     apiClient.clearToken();
-    setIsAuthenticated(false);
+    // Remove também o cookie de sessão OIDC (sem efeito no modo token)
+    fetch("/auth/logout", { method: "POST" })
+      .catch(() => {})
+      .finally(() => setIsAuthenticated(false));
   };
 
   if (isChecking) {
@@ -44,12 +44,14 @@ export const NodePoolTab = ({ onNodePoolModified }: NodePoolTabProps) => {
   const handleClusterChange = async (newCluster: string) => {
     if (newCluster === selectedCluster) return;
 
+    // As listagens passam o cluster explicitamente: a troca do contexto global do servidor
+    // exige permissão admin e sua falha não impede a seleção
+    setSelectedCluster(newCluster);
     try {
       await apiClient.switchContext(newCluster);
-      setSelectedCluster(newCluster);
       toast.success(`Contexto alterado para: ${newCluster}`);
     } catch (error) {
-      toast.error(`Erro ao alterar contexto: ${error instanceof Error ? error.message : 'Erro desconhecido'}`);
+      toast.warning(`Contexto do servidor não alterado: ${error instanceof Error ? error.message : 'Erro desconhecido'}`);
     }
   };
 
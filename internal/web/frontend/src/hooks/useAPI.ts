@@ -10,7 +10,10 @@ import type {
   CronJob,
   PrometheusResource,
   ConfigMapSummary,
+  HPAEventData,
+  NodePoolEventData,
 } from "@/lib/api/types";
+import { useEventStream } from "@/hooks/useEventStream";
 import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
 
 export function useClusters() {
@@ -176,6 +179,42 @@ export function useHPAs(cluster?: string, namespace?: string, showSystem: boolea
     };
   }, [cluster, namespace]);
 
+  // Tempo real (informers no servidor): réplicas e spec atualizados no lugar (sem sobrescrever
+  // HPAs editados localmente); HPAs criados/removidos ou eventos perdidos recarregam a lista
+  useEventStream(
+    { clusters: cluster ? [cluster] : [], namespaces: namespace ? [namespace] : [], types: ["hpa"] },
+    (event) => {
+      if (event.type === "hpa.scaled" || event.type === "hpa.updated") {
+        const { hpa, status } = event.data as HPAEventData;
+        setHPAs((prev) =>
+          prev.map((item) => {
+            if (item.namespace !== hpa.namespace || item.name !== hpa.name) return item;
+            const next = {
+              ...item,
+              current_replicas: status.current_replicas,
+              desired_replicas: status.desired_replicas,
+              last_scale_time: status.last_scale_time ?? item.last_scale_time,
+            };
+            if (event.type === "hpa.updated" && !item.modified) {
+              next.min_replicas = hpa.min_replicas;
+              next.max_replicas = hpa.max_replicas;
+              next.target_cpu = hpa.target_cpu;
+              next.target_memory = hpa.target_memory;
+            }
+            return next;
+          })
+        );
+        return;
+      }
+      if (event.type === "hpa.added" || event.type === "hpa.deleted" || event.type === "reset") {
+        fetchHPAs(true).catch((err) => {
+          console.error("[useHPAs] Error reloading after event:", err);
+        });
+      }
+    },
+    !!cluster
+  );
+
   return { hpas, loading, error, refetch: fetchHPAs, updateHPA };
 }
 
@@ -252,6 +291,26 @@ export function useNodePools(cluster?: string) {
     };
   }, [cluster]);
 
+  // Tempo real: contagem de nodes do pool vista pelo Kubernetes (pools editados localmente não mudam)
+  useEventStream(
+    { clusters: cluster ? [cluster] : [], types: ["nodepool"] },
+    (event) => {
+      if (event.type === "reset") {
+        fetchNodePools().catch((err) => {
+          console.error("[useNodePools] Error reloading after event:", err);
+        });
+        return;
+      }
+      const pool = event.data as NodePoolEventData;
+      setNodePools((prev) =>
+        prev.map((item) =>
+          item.name === pool.name && !item.modified ? { ...item, node_count: pool.nodes } : item
+        )
+      );
+    },
+    !!cluster
+  );
+
   return {
     nodePools,
     loading,
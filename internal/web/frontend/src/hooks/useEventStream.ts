@@ -0,0 +1,37 @@
+// Hook para o stream de eventos do servidor (HPAs, node pools, anomalias, histórico e operações)
+
+import { useEffect, useRef } from "react";
+import { subscribeEvents, type EventStreamOptions } from "@/lib/api/events";
+import type { ServerEvent } from "@/lib/api/types";
+
+/**
+ * Mantém uma conexão com GET /api/v1/events enquanto o componente está montado.
+ * Reconecta quando clusters/namespaces/types mudam; o handler pode mudar a cada render.
+ * Sem cluster, nenhuma conexão é aberta (enabled = false).
+ */
+export function useEventStream(
+  options: EventStreamOptions,
+  onEvent: (event: ServerEvent) => void,
+  enabled: boolean = true
+) {
+  const handlerRef = useRef(onEvent);
+  handlerRef.current = onEvent;
+
+  const clusters = (options.clusters || []).join(",");
+  const namespaces = (options.namespaces || []).join(",");
+  const types = (options.types || []).join(",");
+
+  useEffect(() => {
+    if (!enabled) return;
+
+    const split = (value: string) => (value ? value.split(",") : undefined);
+    return subscribeEvents(
+      {
+        clusters: split(clusters),
+        namespaces: split(namespaces),
+        types: split(types) as EventStreamOptions["types"],
+      },
+      (event) => handlerRef.current(event)
+    );
+  }, [clusters, namespaces, types, enabled]);
+}
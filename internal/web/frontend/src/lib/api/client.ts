@@ -15,6 +15,8 @@ import type {
   SessionFolder,
   SessionTemplate,
   MonitoringStatus,
+  MonitoringSyncResult,
+  MonitoredHPAEntry,
   HPAMetrics,
   Anomalies,
   HPAHealth,
@@ -47,6 +49,11 @@ class APIClient {
     localStorage.removeItem("auth_token");
   }
 
+  // Headers de autenticação para requisições fora de request() (stream de eventos)
+  authHeaders(): Record<string, string> {
+    return this.token ? { Authorization: `Bearer ${this.token}` } : {};
+  }
+
   private async request<T>(
     endpoint: string,
     options: RequestInit = {}
@@ -569,11 +576,56 @@ class APIClient {
     );
   }
 
-  // Sincronizar lista completa de HPAs monitorados (reconciliação)
+  async getMonitoredHPAs(): Promise<{ status: string; total: number; hpas: MonitoredHPAEntry[] }> {
+    return this.request<{ status: string; total: number; hpas: MonitoredHPAEntry[] }>("/monitoring/hpas");
+  }
+
+  async removeHPAFromMonitoring(
+    cluster: string,
+    namespace: string,
+    hpa: string
+  ): Promise<{ status: string; message: string }> {
+    return this.request<{ status: string; message: string }>(
+      `/monitoring/hpa/${encodeURIComponent(cluster)}/${encodeURIComponent(namespace)}/${encodeURIComponent(hpa)}`,
+      {
+        method: "DELETE",
+      }
+    );
+  }
+
+  async pauseMonitoring(): Promise<{ status: string; message: string }> {
+    return this.request<{ status: string; message: string }>(
+      "/monitoring/pause",
+      {
+        method: "POST",
+      }
+    );
+  }
+
+  async resumeMonitoring(): Promise<{ status: string; message: string }> {
+    return this.request<{ status: string; message: string }>(
+      "/monitoring/resume",
+      {
+        method: "POST",
+      }
+    );
+  }
+
+  async setMonitoringInterval(interval: string): Promise<{ status: string; interval: string }> {
+    return this.request<{ status: string; interval: string }>(
+      "/monitoring/interval",
+      {
+        method: "PUT",
+        body: JSON.stringify({ interval }),
+      }
+    );
+  }
+
+  // Mesclar HPAs monitorados do navegador com os salvos no servidor (servidor é a fonte da verdade)
   async syncMonitoredHPAs(
     hpas: Array<{ cluster: string; namespace: string; hpa: string }>
-  ): Promise<{ status: string; added: number; removed: number; total: number }> {
-    return this.request<{ status: string; added: number; removed: number; total: number }>(
+  ): Promise<MonitoringSyncResult> {
+    return this.request<MonitoringSyncResult>(
       "/monitoring/sync",
       {
         method: "POST",
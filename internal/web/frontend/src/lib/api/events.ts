@@ -0,0 +1,130 @@
+// Stream de eventos (GET /api/v1/events) via Server-Sent Events.
+// Usa fetch em vez de EventSource para enviar o header Authorization (token estático);
+// reconecta sozinho e retoma a partir do último evento recebido (Last-Event-ID).
+
+import { apiClient } from "./client";
+import type { ServerEvent, ServerEventType } from "./types";
+
+export interface EventStreamOptions {
+  clusters?: string[];
+  namespaces?: string[];
+  types?: (ServerEventType | "hpa" | "nodepool" | "operation")[];
+}
+
+type Listener = (event: ServerEvent) => void;
+
+const RECONNECT_MIN_MS = 1000;
+const RECONNECT_MAX_MS = 30000;
+
+/**
+ * Abre o stream de eventos e chama onEvent a cada evento. O evento "reset" indica que
+ * eventos foram perdidos (servidor reiniciado ou desconexão longa): recarregue o estado.
+ * Retorna a função que encerra o stream.
+ */
+export function subscribeEvents(options: EventStreamOptions, onEvent: Listener): () => void {
+  const controller = new AbortController();
+  let lastEventId = "";
+  let retryMs = RECONNECT_MIN_MS;
+
+  const params = new URLSearchParams();
+  if (options.clusters?.length) params.set("cluster", options.clusters.join(","));
+  if (options.namespaces?.length) params.set("namespace", options.namespaces.join(","));
+  if (options.types?.length) params.set("types", options.types.join(","));
+  const query = params.toString();
+  const url = `/api/v1/events${query ? `?${query}` : ""}`;
+
+  const connect = async () => {
+    while (!controller.signal.aborted) {
+      try {
+        const headers: Record<string, string> = {
+          Accept: "text/event-stream",
+          ...apiClient.authHeaders(),
+        };
+        if (lastEventId) {
+          headers["Last-Event-ID"] = lastEventId;
+        }
+
+        const response = await fetch(url, { headers, signal: controller.signal });
+        if (!response.ok || !response.body) {
+          throw new Error(`HTTP ${response.status}`);
+        }
+        retryMs = RECONNECT_MIN_MS;
+
+        await readStream(response.body, (id, type, data) => {
+          if (id) lastEventId = id;
+          if (!type || !data) return;
+          try {
+            onEvent(JSON.parse(data) as ServerEvent);
+          } catch (err) {
+            console.error("[events] Invalid event payload:", err);
+          }
+        }, (retry) => {
+          retryMs = retry;
+        });
+      } catch (err) {
+        if (controller.signal.aborted) return;
+        console.warn(`[events] Stream disconnected, retrying in ${retryMs}ms:`, err);
+      }
+
+      if (controller.signal.aborted) return;
+      await new Promise((resolve) => setTimeout(resolve, retryMs));
+      retryMs = Math.min(retryMs * 2, RECONNECT_MAX_MS);
+    }
+  };
+
+  connect();
+  return () => controller.abort();
+}
+
+// readStream interpreta o formato SSE (id, event, data, retry e comentários)
+async function readStream(
+  body: ReadableStream<Uint8Array>,
+  dispatch: (id: string, type: string, data: string) => void,
+  setRetry: (ms: number) => void
+) {
+  const reader = body.getReader();
+  const decoder = new TextDecoder();
+  let buffer = "";
+  let id = "";
+  let type = "";
+  let data: string[] = [];
+
+  for (;;) {
+    const { done, value } = await reader.read();
+    if (done) return;
+    buffer += decoder.decode(value, { stream: true });
+
+    let newline: number;
+    while ((newline = buffer.indexOf("\n")) >= 0) {
+      const line = buffer.slice(0, newline).replace(/\r$/, "");
+      buffer = buffer.slice(newline + 1);
+
+      if (line === "") {
+        dispatch(id, type, data.join("\n"));
+        id = "";
+        type = "";
+        data = [];
+        continue;
+      }
+      if (line.startsWith(":")) continue; // keep-alive
+
+      const colon = line.indexOf(":");
+      const field = colon >= 0 ? line.slice(0, colon) : line;
+      const value = colon >= 0 ? line.slice(colon + 1).replace(/^ /, "") : "";
+      switch (field) {
+        case "id":
+          id = value;
+          break;
+        case "event":
+          type = value;
+          break;
+        case "data":
+          data.push(value);
+          break;
+        case "retry":
+          if (/^\d+$/.test(value)) setRetry(Number(value));
+          break;
+      }
+    }
+  }
+}
@@ -374,14 +374,34 @@ export interface SessionTemplate {
 // Monitoring Types
 export interface MonitoringStatus {
   running: boolean;
+  paused?: boolean;
+  status?: "running" | "paused" | "stopped";
   mode: string;
   interval: string;
+  hpas?: number; // HPAs monitorados (salvos no servidor)
   clusters: number;
   last_scan: string | null;
   total_scans: number;
   port_info?: Record<string, number>; // cluster -> porta
 }
 
+export interface MonitoredHPAEntry {
+  cluster: string; // sem o sufixo -admin
+  namespace: string;
+  hpa: string;
+  added_by?: string;
+  added_at: string;
+}
+
+export interface MonitoringSyncResult {
+  status: string;
+  added: number;
+  denied?: string[]; // cluster/namespace/hpa sem permissão de monitoring no cluster
+  removed: number;
+  total: number;
+  hpas: MonitoredHPAEntry[];
+}
+
 export interface HPASnapshot {
   cluster: string;
   namespace: string;
@@ -455,3 +475,50 @@ export interface HPAHealth {
   score?: number; // 0-100
   recommendations?: string[];
 }
+
+// Stream de eventos (GET /api/v1/events)
+export type ServerEventType =
+  | "hpa.added"
+  | "hpa.updated"
+  | "hpa.scaled"
+  | "hpa.deleted"
+  | "nodepool.updated"
+  | "anomaly"
+  | "history"
+  | "operation.progress"
+  | "reset";
+
+export interface ServerEvent<T = unknown> {
+  id: string;
+  type: ServerEventType;
+  cluster?: string;
+  namespace?: string;
+  time: string;
+  data?: T;
+}
+
+export interface HPAStatusEvent {
+  current_replicas: number;
+  desired_replicas: number;
+  last_scale_time?: string;
+  conditions?: { type: string; status: string; reason?: string; message?: string }[];
+}
+
+export interface HPAEventData {
+  hpa: HPA;
+  status: HPAStatusEvent;
+  previous?: HPAStatusEvent;
+}
+
+export interface NodePoolEventData {
+  name: string;
+  nodes: number;
+  ready: number;
+  unschedulable: number;
+}
+
+export interface OperationProgressEventData<T = unknown> {
+  operation_id: string;
+  kind: "nodepool_sequence" | "session_apply";
+  progress: T;
+}
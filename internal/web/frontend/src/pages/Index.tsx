@@ -343,8 +343,9 @@ const Index = ({ onLogout }: IndexProps) => {
                                 const stored = localStorage.getItem("monitored_hpas");
                                 const current = stored ? JSON.parse(stored) : [];
 
+                                // Servidor salva clusters sem -admin (HPAs restaurados vêm assim)
                                 const exists = current.some((h: any) =>
-                                  h.cluster === displayHPA.cluster &&
+                                  h.cluster.replace(/-admin$/, "") === displayHPA.cluster.replace(/-admin$/, "") &&
                                   h.namespace === displayHPA.namespace &&
                                   h.name === displayHPA.name
                                 );
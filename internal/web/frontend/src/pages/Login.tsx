@@ -1,4 +1,4 @@
-import { useState } from "react";
+import { useEffect, useState } from "react";
 import { Button } from "@/components/ui/button";
 import { Input } from "@/components/ui/input";
 import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card";
@@ -13,6 +13,21 @@ export const Login = ({ onLogin }: LoginProps) => {
   const [token, setToken] = useState("poc-token-123"); // Default POC token
   const [error, setError] = useState("");
   const [loading, setLoading] = useState(false);
+  const [oidcEnabled, setOidcEnabled] = useState(false);
+  const [tokenEnabled, setTokenEnabled] = useState(true);
+
+  // Métodos de login habilitados no servidor (auth.yaml)
+  useEffect(() => {
+    fetch("/auth/info")
+      .then((response) => (response.ok ? response.json() : null))
+      .then((info) => {
+        if (info?.data) {
+          setOidcEnabled(Boolean(info.data.oidc));
+          setTokenEnabled(Boolean(info.data.static_token));
+        }
+      })
+      .catch(() => {});
+  }, []);
 
   const handleLogin = async (e: React.FormEvent) => {
     e.preventDefault();
@@ -52,39 +67,47 @@ export const Login = ({ onLogin }: LoginProps) => {
           </div>
           <CardTitle className="text-2xl">k8s HPA Manager</CardTitle>
           <CardDescription>
-            Enter your authentication token to continue
+            {oidcEnabled ? "Sign in with your company account" : "Enter your authentication token to continue"}
           </CardDescription>
         </CardHeader>
-        <CardContent>
-          <form onSubmit={handleLogin} className="space-y-4">
-            <div className="space-y-2">
-              <label htmlFor="token" className="text-sm font-medium">
-                Authentication Token
-              </label>
-              <Input
-                id="token"
-                type="password"
-                placeholder="Enter your token"
-                value={token}
-                onChange={(e) => setToken(e.target.value)}
-                disabled={loading}
-                className="font-mono"
-              />
-              <p className="text-xs text-muted-foreground">
-                Default POC token: <code className="bg-muted px-1 py-0.5 rounded">poc-token-123</code>
-              </p>
-            </div>
+        <CardContent className="space-y-4">
+          {oidcEnabled && (
+            <Button asChild className="w-full">
+              <a href="/auth/login">Sign in with SSO</a>
+            </Button>
+          )}
 
-            {error && (
-              <div className="p-3 text-sm text-destructive bg-destructive/10 border border-destructive/20 rounded-md">
-                {error}
+          {tokenEnabled && (
+            <form onSubmit={handleLogin} className="space-y-4">
+              <div className="space-y-2">
+                <label htmlFor="token" className="text-sm font-medium">
+                  Authentication Token
+                </label>
+                <Input
+                  id="token"
+                  type="password"
+                  placeholder="Enter your token"
+                  value={token}
+                  onChange={(e) => setToken(e.target.value)}
+                  disabled={loading}
+                  className="font-mono"
+                />
+                <p className="text-xs text-muted-foreground">
+                  Default POC token: <code className="bg-muted px-1 py-0.5 rounded">poc-token-123</code>
+                </p>
               </div>
-            )}
 
-            <Button type="submit" className="w-full" disabled={loading || !token}>
-              {loading ? "Authenticating..." : "Login"}
-            </Button>
-          </form>
+              {error && (
+                <div className="p-3 text-sm text-destructive bg-destructive/10 border border-destructive/20 rounded-md">
+                  {error}
+                </div>
+              )}
+
+              <Button type="submit" className="w-full" disabled={loading || !token}>
+                {loading ? "Authenticating..." : "Login"}
+              </Button>
+            </form>
+          )}
         </CardContent>
       </Card>
     </div>
@@ -1,11 +1,11 @@
 import { useState, useEffect, useMemo } from "react";
-import { ChevronDown, ChevronRight, Activity, Trash2, Circle, PanelLeftClose, PanelLeft, RotateCw, Info } from "lucide-react";
+import { ChevronDown, ChevronRight, Activity, Trash2, Circle, PanelLeftClose, PanelLeft, RotateCw, Info, Pause, Play } from "lucide-react";
 import { Button } from "@/components/ui/button";
 import { Card } from "@/components/ui/card";
 import { ScrollArea } from "@/components/ui/scroll-area";
 import { MetricsPanel } from "@/components/MetricsPanel";
 import { apiClient } from "@/lib/api/client";
-import type { HPA, MonitoringStatus } from "@/lib/api/types";
+import type { HPA, MonitoredHPAEntry, MonitoringStatus } from "@/lib/api/types";
 import {
   ContextMenu,
   ContextMenuContent,
@@ -25,9 +25,38 @@ interface MonitoredHPA {
 }
 
 interface MonitoringPageProps {
-  // HPAs serão gerenciados via localStorage
+  // HPAs salvos no servidor, com cópia no localStorage (mesclada via /monitoring/sync)
 }
 
+// O servidor salva clusters sem o sufixo -admin dos contextos kubectl
+const normalizeCluster = (cluster: string) => cluster.replace(/-admin$/, "");
+
+const hpaKey = (cluster: string, namespace: string, name: string) =>
+  `${normalizeCluster(cluster)}/${namespace}/${name}`;
+
+// Acrescenta à lista local os HPAs que só o servidor conhece (restaurados ou de outro operador)
+const mergeServerHPAs = (local: MonitoredHPA[], server: MonitoredHPAEntry[]): MonitoredHPA[] => {
+  const known = new Set(local.map((h) => hpaKey(h.cluster, h.namespace, h.name)));
+  const merged = [...local];
+  for (const entry of server) {
+    if (known.has(hpaKey(entry.cluster, entry.namespace, entry.hpa))) continue;
+    merged.push({
+      cluster: entry.cluster,
+      namespace: entry.namespace,
+      name: entry.hpa,
+      hpa: {
+        name: entry.hpa,
+        namespace: entry.namespace,
+        cluster: entry.cluster,
+        min_replicas: null,
+        max_replicas: 0,
+        current_replicas: 0,
+      },
+    });
+  }
+  return merged;
+};
+
 export const MonitoringPage = ({}: MonitoringPageProps) => {
   const [monitoredHPAs, setMonitoredHPAs] = useState<MonitoredHPA[]>([]);
   const [selectedHPA, setSelectedHPA] = useState<MonitoredHPA | null>(null);
@@ -37,28 +66,29 @@ export const MonitoringPage = ({}: MonitoringPageProps) => {
   const [portInfo, setPortInfo] = useState<any>(null);
   const { toast } = useToast();
 
-  // Carregar HPAs monitorados do localStorage
+  // Carregar HPAs monitorados do localStorage e mesclar com os salvos no servidor
   useEffect(() => {
     const stored = localStorage.getItem("monitored_hpas");
     console.log("[MonitoringPage] localStorage data:", stored);
+    let parsed: MonitoredHPA[] = [];
     if (stored) {
       try {
-        const parsed = JSON.parse(stored) as MonitoredHPA[];
+        parsed = JSON.parse(stored) as MonitoredHPA[];
         console.log("[MonitoringPage] Parsed HPAs:", parsed);
         setMonitoredHPAs(parsed);
         // Auto-expandir todos os clusters ao carregar
         const clusters = new Set<string>(parsed.map((h) => h.cluster));
         setExpandedClusters(clusters);
-
-        // Sincronizar com backend imediatamente
-        syncWithBackend(parsed);
       } catch (e) {
         console.error("Failed to parse monitored HPAs:", e);
       }
     }
+
+    // Sincronizar com backend imediatamente (mesmo sem lista local: servidor pode ter HPAs salvos)
+    syncWithBackend(parsed);
   }, []);
 
-  // Sistema de reconciliação: Sincroniza lista do frontend com backend
+  // Sistema de reconciliação: envia a lista local e incorpora os HPAs que só o servidor conhece
   const syncWithBackend = async (hpas: MonitoredHPA[]) => {
     try {
       const hpaList = hpas.map((h) => ({
@@ -71,11 +101,26 @@ export const MonitoringPage = ({}: MonitoringPageProps) => {
       const result = await apiClient.syncMonitoredHPAs(hpaList);
       console.log("[MonitoringPage] Resultado da sincronização:", result);
 
-      if (result.added > 0 || result.removed > 0) {
+      if (result.added > 0) {
         console.log(
-          `[MonitoringPage] ✅ Reconciliação: ${result.added} adicionados, ${result.removed} removidos, ${result.total} total`
+          `[MonitoringPage] ✅ Reconciliação: ${result.added} adicionados ao servidor, ${result.total} total`
         );
       }
+
+      if (result.denied?.length) {
+        console.warn("[MonitoringPage] 🚫 HPAs não adicionados (sem permissão no cluster):", result.denied);
+      }
+
+      const merged = mergeServerHPAs(hpas, result.hpas ?? []);
+      if (merged.length > hpas.length) {
+        const serverOnly = merged.slice(hpas.length);
+        setMonitoredHPAs((prev) => {
+          const next = mergeServerHPAs(prev, result.hpas ?? []);
+          localStorage.setItem("monitored_hpas", JSON.stringify(next));
+          return next;
+        });
+        setExpandedClusters((prev) => new Set([...prev, ...serverOnly.map((h) => h.cluster)]));
+      }
     } catch (error) {
       console.error("[MonitoringPage] Erro na sincronização:", error);
     }
@@ -135,7 +180,20 @@ export const MonitoringPage = ({}: MonitoringPageProps) => {
     });
   };
 
-  const removeHPA = (cluster: string, namespace: string, name: string) => {
+  const removeHPA = async (cluster: string, namespace: string, name: string) => {
+    // Remover no servidor primeiro: a sincronização mescla e traria o HPA de volta
+    try {
+      await apiClient.removeHPAFromMonitoring(normalizeCluster(cluster), namespace, name);
+    } catch (error) {
+      console.error("[MonitoringPage] Erro ao remover HPA:", error);
+      toast({
+        title: "❌ Erro ao remover HPA",
+        description: error instanceof Error ? error.message : "Erro desconhecido",
+        variant: "destructive",
+      });
+      return;
+    }
+
     const updated = monitoredHPAs.filter(
       h => !(h.cluster === cluster && h.namespace === namespace && h.name === name)
     );
@@ -185,6 +243,25 @@ export const MonitoringPage = ({}: MonitoringPageProps) => {
     }
   };
 
+  // Pausar/retomar scans (estado salvo no servidor e restaurado no próximo startup)
+  const handleTogglePause = async () => {
+    try {
+      if (monitoringStatus?.paused) {
+        await apiClient.resumeMonitoring();
+      } else {
+        await apiClient.pauseMonitoring();
+      }
+      setMonitoringStatus(await apiClient.getMonitoringStatus());
+    } catch (error) {
+      console.error("[MonitoringPage] Erro ao pausar/retomar engine:", error);
+      toast({
+        title: "❌ Erro",
+        description: error instanceof Error ? error.message : "Erro desconhecido",
+        variant: "destructive",
+      });
+    }
+  };
+
   // Copiar informações de portas para clipboard
   const handleShowPortInfo = async () => {
     try {
@@ -294,13 +371,26 @@ export const MonitoringPage = ({}: MonitoringPageProps) => {
                     <RotateCw className="w-4 h-4 mr-2" />
                     Reiniciar Engine
                   </ContextMenuItem>
+                  {monitoringStatus.running && (
+                    <ContextMenuItem onClick={handleTogglePause}>
+                      {monitoringStatus.paused ? (
+                        <Play className="w-4 h-4 mr-2" />
+                      ) : (
+                        <Pause className="w-4 h-4 mr-2" />
+                      )}
+                      {monitoringStatus.paused ? "Retomar Scans" : "Pausar Scans"}
+                    </ContextMenuItem>
+                  )}
                   <ContextMenuItem onClick={handleShowPortInfo}>
                     <Info className="w-4 h-4 mr-2" />
                     Copiar Info para Clipboard
                   </ContextMenuItem>
                   <ContextMenuSeparator />
                   <ContextMenuLabel className="text-xs text-muted-foreground">
-                    Status: {monitoringStatus.running ? "🟢 Ativo" : "⚫ Parado"}
+                    Status: {monitoringStatus.paused ? "⏸️ Pausado" : monitoringStatus.running ? "🟢 Ativo" : "⚫ Parado"}
+                  </ContextMenuLabel>
+                  <ContextMenuLabel className="text-xs text-muted-foreground">
+                    Intervalo: {monitoringStatus.interval}
                   </ContextMenuLabel>
                   <ContextMenuLabel className="text-xs text-muted-foreground">
                     Clusters: {monitoringStatus.clusters || 0}
@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s-hpa-manager/internal/audit"
+	"k8s-hpa-manager/internal/web/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	auditRecorderKey  = "audit.recorder"
+	auditRecordedKey  = "audit.recorded"
+	auditMaxBody      = 64 * 1024
+	auditDefaultLimit = 200
+	auditMaxLimit     = 5000
+)
+
+// AuditMiddleware registra no audit log toda requisição mutável da API (POST/PUT/PATCH/DELETE)
+// que o handler não registrou com detalhes próprios (auditRecorder) nem marcou como somente
+// leitura (skipAudit). A entrada genérica guarda o corpo JSON da requisição em "after".
+// Deve ser registrado depois do middleware de autenticação.
+func AuditMiddleware(log *audit.Log) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if log == nil {
+			c.Next()
+			return
+		}
+
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+		c.Header("X-Request-ID", requestID)
+		recorder := &audit.Recorder{Log: log, Source: audit.SourceWeb, Actor: auth.User(c), RequestID: requestID}
+		c.Set(auditRecorderKey, recorder)
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		body := readAuditBody(c)
+		writer := &auditResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		if c.GetBool(auditRecordedKey) {
+			return
+		}
+
+		event := audit.Event{
+			Action:   c.Request.Method + " " + c.FullPath(),
+			Cluster:  c.Param("cluster"),
+			Resource: auditResource(c),
+			After:    body,
+		}
+		if event.Cluster == "" {
+			event.Cluster = c.Query("cluster")
+		}
+		if c.FullPath() == "" {
+			event.Action = c.Request.Method + " " + c.Request.URL.Path
+		}
+		status := writer.Status()
+		switch {
+		case status == http.StatusForbidden:
+			event.Result = audit.ResultDenied
+			event.Err = writer.errorMessage()
+		case status >= http.StatusBadRequest:
+			event.Err = writer.errorMessage()
+		}
+		recorder.Record(event)
+	}
+}
+
+// auditRecorder retorna o recorder da requisição para handlers que registram eventos
+// detalhados (antes/depois); a chamada deixa de ser registrada genericamente pelo middleware
+func auditRecorder(c *gin.Context) *audit.Recorder {
+	c.Set(auditRecordedKey, true)
+	if value, ok := c.Get(auditRecorderKey); ok {
+		return value.(*audit.Recorder)
+	}
+	return nil
+}
+
+// skipAudit marca requisições POST que não alteram nada (diff, validação, plano, dry-run)
+func skipAudit(c *gin.Context) {
+	c.Set(auditRecordedKey, true)
+}
+
+// readAuditBody lê (e devolve à requisição) o corpo JSON de até 64KB
+func readAuditBody(c *gin.Context) json.RawMessage {
+	if c.Request.Body == nil || !strings.Contains(c.ContentType(), "json") || c.Request.ContentLength > auditMaxBody {
+		return nil
+	}
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, auditMaxBody+1))
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(data), c.Request.Body))
+	if err != nil || len(data) > auditMaxBody || !json.Valid(data) {
+		return nil
+	}
+	return json.RawMessage(data)
+}
+
+// auditResource monta namespace/nome a partir dos parâmetros da rota
+func auditResource(c *gin.Context) string {
+	var parts []string
+	for _, key := range []string{"namespace", "resource_group", "type", "name", "id"} {
+		if value := c.Param(key); value != "" {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// auditResponseWriter guarda o início do corpo de respostas de erro
+type auditResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditResponseWriter) Write(data []byte) (int, error) {
+	if w.Status() >= http.StatusBadRequest && w.body.Len() < 8*1024 {
+		w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *auditResponseWriter) WriteString(data string) (int, error) {
+	return w.Write([]byte(data))
+}
+
+// errorMessage extrai error.message (ou error) do corpo JSON padrão da API
+func (w *auditResponseWriter) errorMessage() error {
+	var response struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(w.body.Bytes(), &response) == nil && len(response.Error) > 0 {
+		var detailed struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(response.Error, &detailed) == nil && detailed.Message != "" {
+			return fmt.Errorf("%s: %s", detailed.Code, detailed.Message)
+		}
+		var message string
+		if json.Unmarshal(response.Error, &message) == nil && message != "" {
+			return fmt.Errorf("%s", message)
+		}
+	}
+	return fmt.Errorf("HTTP %d", w.Status())
+}
+
+// AuditHandler consulta, verificação e exportação do audit log (somente admin)
+type AuditHandler struct {
+	log *audit.Log
+}
+
+// NewAuditHandler cria um novo handler
+func NewAuditHandler(log *audit.Log) *AuditHandler {
+	return &AuditHandler{log: log}
+}
+
+// List retorna as entradas mais recentes com filtros opcionais
+// GET /api/v1/audit?since=2026-01-01&until=&actor=&source=web&action=&cluster=&result=failed&limit=200
+func (h *AuditHandler) List(c *gin.Context) {
+	filter, ok := h.filter(c)
+	if !ok {
+		return
+	}
+	filter.Limit = auditDefaultLimit
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > auditMaxLimit {
+			c.JSON(http.StatusBadRequest, errorResponse("INVALID_LIMIT", fmt.Sprintf("limit must be between 1 and %d", auditMaxLimit)))
+			return
+		}
+		filter.Limit = limit
+	}
+
+	entries, err := h.log.Entries(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse("AUDIT_READ_ERROR", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entries,
+		"count":   len(entries),
+	})
+}
+
+// Verify recalcula a cadeia de hashes; checkpoint=seq:hash confere um hash guardado fora do servidor
+// GET /api/v1/audit/verify?checkpoint=
+func (h *AuditHandler) Verify(c *gin.Context) {
+	if !h.require(c) {
+		return
+	}
+	var checkpoints []audit.Checkpoint
+	for _, value := range c.QueryArray("checkpoint") {
+		checkpoint, err := audit.ParseCheckpoint(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("INVALID_CHECKPOINT", err.Error()))
+			return
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	report, err := h.log.Verify(checkpoints...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse("AUDIT_READ_ERROR", err.Error()))
+		return
+	}
+	if !report.Valid {
+		fmt.Printf("🚨 Audit log inconsistente: %d problema(s)\n", len(report.Problems))
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// Export exporta as entradas filtradas em JSON Lines (verificável) ou CSV
+// GET /api/v1/audit/export?format=jsonl|csv&since=&until=&cluster=...
+func (h *AuditHandler) Export(c *gin.Context) {
+	format, err := audit.ParseFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse("INVALID_FORMAT", err.Error()))
+		return
+	}
+	filter, ok := h.filter(c)
+	if !ok {
+		return
+	}
+
+	var buf bytes.Buffer
+	if _, err := h.log.Export(&buf, format, filter); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse("AUDIT_READ_ERROR", err.Error()))
+		return
+	}
+	filename := fmt.Sprintf("audit-%s.%s", time.Now().Format("20060102-150405"), format.Extension())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
+
+// filter lê os filtros comuns da query string (exige admin)
+func (h *AuditHandler) filter(c *gin.Context) (audit.Filter, bool) {
+	if !h.require(c) {
+		return audit.Filter{}, false
+	}
+	filter := audit.Filter{
+		Actor:   c.Query("actor"),
+		Source:  audit.Source(c.Query("source")),
+		Action:  c.Query("action"),
+		Cluster: c.Query("cluster"),
+		Result:  audit.Result(c.Query("result")),
+	}
+	for param, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := audit.ParseTime(value, param == "until")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse("INVALID_TIME", fmt.Sprintf("%s: %v", param, err)))
+			return filter, false
+		}
+		*target = parsed
+	}
+	return filter, true
+}
+
+func (h *AuditHandler) require(c *gin.Context) bool {
+	if !auth.Require(c, auth.OpAdmin, "") {
+		return false
+	}
+	if h.log == nil {
+		c.JSON(http.StatusServiceUnavailable, errorResponse("AUDIT_DISABLED", "audit log is not available"))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"k8s-hpa-manager/internal/config"
+	"k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/monitoring/engine"
+	"k8s-hpa-manager/internal/scheduler"
+	"k8s-hpa-manager/internal/web/auth"
+	"k8s-hpa-manager/internal/web/auth/oidctest"
+)
+
+// testRouter router com o token estático "test-token" resolvido para o papel informado
+func testRouter(t *testing.T, role auth.Role) *gin.Engine {
+	t.Helper()
+	authenticator, err := auth.New(context.Background(), &auth.Config{StaticTokenRole: role}, "test-token")
+	if err != nil {
+		t.Fatalf("auth.New failed: %v", err)
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(authenticator.Middleware())
+	return router
+}
+
+func serve(router *gin.Engine, method, path, body string) int {
+	return serveAs(router, "test-token", method, path, body)
+}
+
+func serveAs(router *gin.Engine, token, method, path, body string) int {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestServerContextRequiresAdmin(t *testing.T) {
+	router := testRouter(t, auth.RoleViewer)
+	clusterHandler := NewClusterHandler(nil)
+	router.POST("/clusters/:name/context", clusterHandler.SwitchToClusterContext)
+	router.POST("/clusters/switch-context", clusterHandler.SwitchContext)
+	router.POST("/azure/subscription", NewAzureHandler().SetSubscription)
+
+	requests := []struct {
+		path string
+		body string
+	}{
+		{"/clusters/akspriv-prd-admin/context", ""},
+		{"/clusters/switch-context", `{"context":"akspriv-prd-admin"}`},
+		{"/azure/subscription", `{"subscription":"prd"}`},
+	}
+	for _, r := range requests {
+		if code := serve(router, http.MethodPost, r.path, r.body); code != http.StatusForbidden {
+			t.Errorf("viewer POST %s: status %d, want %d", r.path, code, http.StatusForbidden)
+		}
+	}
+}
+
+func TestReadsRequireClusterGrant(t *testing.T) {
+	idp := oidctest.New("hpa-manager", oidctest.User{})
+	defer idp.Close()
+	cfg, err := auth.ParseConfig([]byte(`
+oidc:
+  issuer: ` + idp.Issuer() + `
+  client_id: hpa-manager
+  redirect_url: http://app/auth/callback
+bindings:
+  - {subjects: ["group:squad"], role: viewer, clusters: ["*-hlg"]}
+`))
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	authenticator, err := auth.New(context.Background(), cfg, "")
+	if err != nil {
+		t.Fatalf("auth.New failed: %v", err)
+	}
+	token := idp.IDToken(oidctest.User{Subject: "u1", Email: "dev@empresa.com", Groups: []string{"squad"}}, "", time.Hour)
+
+	// Kubeconfig sem contextos: leituras autorizadas falham ao obter o client (500), nunca 403
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(kubeconfig, []byte("apiVersion: v1\nkind: Config\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	kubeManager, err := config.NewKubeConfigManager(kubeconfig)
+	if err != nil {
+		t.Fatalf("NewKubeConfigManager failed: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(authenticator.Middleware())
+	hpaHandler := NewHPAHandler(kubeManager, nil)
+	router.GET("/hpas", hpaHandler.List)
+	router.GET("/hpas/:cluster/:namespace/:name", hpaHandler.Get)
+	router.GET("/namespaces", NewNamespaceHandler(kubeManager).List)
+	router.GET("/configmaps/:cluster/:namespace/:name", NewConfigMapHandler(kubeManager, nil).Get)
+
+	for _, path := range []string{
+		"/hpas?cluster=akspriv-prd-admin",
+		"/hpas/akspriv-prd-admin/payments/api",
+		"/namespaces?cluster=akspriv-prd-admin",
+		"/configmaps/akspriv-prd-admin/payments/api",
+	} {
+		if code := serveAs(router, token, http.MethodGet, path, ""); code != http.StatusForbidden {
+			t.Errorf("GET %s outside granted clusters: status %d, want %d", path, code, http.StatusForbidden)
+		}
+		allowed := strings.ReplaceAll(path, "-prd-", "-hlg-")
+		if code := serveAs(router, token, http.MethodGet, allowed, ""); code == http.StatusForbidden {
+			t.Errorf("GET %s within granted clusters: status %d", allowed, code)
+		}
+	}
+}
+
+func TestSyncMonitoredHPAsSkipsDeniedClusters(t *testing.T) {
+	idp := oidctest.New("hpa-manager", oidctest.User{})
+	defer idp.Close()
+	cfg, err := auth.ParseConfig([]byte(`
+oidc:
+  issuer: ` + idp.Issuer() + `
+  client_id: hpa-manager
+  redirect_url: http://app/auth/callback
+bindings:
+  - {subjects: ["group:squad"], role: operator, clusters: ["*-hlg"]}
+`))
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	authenticator, err := auth.New(context.Background(), cfg, "")
+	if err != nil {
+		t.Fatalf("auth.New failed: %v", err)
+	}
+	token := idp.IDToken(oidctest.User{Subject: "u1", Email: "dev@empresa.com", Groups: []string{"squad"}}, "", time.Hour)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(authenticator.Middleware())
+	// Engine sem PriorityCollector: entradas autorizadas falham ao adicionar, negadas nem chegam lá
+	router.POST("/monitoring/sync", (&MonitoringHandler{engine: &engine.ScanEngine{}}).SyncMonitoredHPAs)
+
+	body := `{"hpas":[{"cluster":"akspriv-prd-admin","namespace":"payments","hpa":"api"},{"cluster":"akspriv-hlg-admin","namespace":"payments","hpa":"api"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/monitoring/sync", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	var resp struct {
+		Denied []string `json:"denied"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Denied) != 1 || resp.Denied[0] != "akspriv-prd/payments/api" {
+		t.Errorf("denied = %v, want [akspriv-prd/payments/api]", resp.Denied)
+	}
+}
+
+func TestNodePoolUpdateCordonDrainRequiresDrain(t *testing.T) {
+	router := testRouter(t, auth.RoleOperator)
+	router.PUT("/nodepools/:cluster/:resource_group/:name", (&NodePoolHandler{}).Update)
+
+	for _, body := range []string{
+		`{"node_count":0,"cordon_drain_config":{"drain_enabled":true}}`,
+		`{"node_count":0,"cordon_drain_config":{"cordon_enabled":true}}`,
+	} {
+		if code := serve(router, http.MethodPut, "/nodepools/akspriv-prd-admin/rg-prd/monitoring", body); code != http.StatusForbidden {
+			t.Errorf("operator %s: status %d, want %d", body, code, http.StatusForbidden)
+		}
+	}
+}
+
+func TestScheduleAuthorizer(t *testing.T) {
+	cfg := &auth.Config{Bindings: []auth.Binding{
+		{Subjects: []string{"group:squad"}, Role: auth.RoleOperator, Clusters: []string{"*-hlg"}, Operations: []auth.Operation{auth.OpHPAEdit}},
+	}}
+	authenticator, err := auth.New(context.Background(), cfg, "")
+	if err != nil {
+		t.Fatalf("auth.New failed: %v", err)
+	}
+	authorizer := NewScheduleAuthorizer(authenticator)
+	owner := scheduler.Owner{Method: auth.MethodOIDC, Username: "dev@empresa.com", Groups: []string{"squad"}}
+	sess := &models.Session{
+		Name:    "black-friday",
+		Changes: []models.HPAChange{{Cluster: "akspriv-hlg-admin"}},
+	}
+
+	if err := authorizer.AuthorizeSession(owner, sess); err != nil {
+		t.Errorf("session within the owner's bindings: %v", err)
+	}
+
+	// Sessão editada depois de agendada: item fora das permissões de quem agendou
+	sess.NodePoolChanges = []models.NodePoolChange{{Cluster: "akspriv-hlg-admin"}}
+	if err := authorizer.AuthorizeSession(owner, sess); err == nil {
+		t.Error("node pool change without nodepool_scale should be refused")
+	}
+	sess.NodePoolChanges = nil
+	sess.Changes = append(sess.Changes, models.HPAChange{Cluster: "akspriv-prd-admin"})
+	if err := authorizer.AuthorizeSession(owner, sess); err == nil {
+		t.Error("HPA change outside the granted clusters should be refused")
+	}
+
+	// Usuário removido do grupo no IdP / binding removido do auth.yaml
+	sess.Changes = sess.Changes[:1]
+	if err := authorizer.AuthorizeSession(scheduler.Owner{Method: auth.MethodOIDC, Username: "dev@empresa.com"}, sess); err == nil {
+		t.Error("owner without bindings should be refused")
+	}
+	if err := authorizer.AuthorizeSession(scheduler.Owner{Method: auth.MethodToken}, sess); err == nil {
+		t.Error("static token owner should be refused when the token is disabled")
+	}
+}
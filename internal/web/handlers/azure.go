@@ -6,6 +6,8 @@ import (
 	"os/exec"
 
 	"github.com/gin-gonic/gin"
+
+	"k8s-hpa-manager/internal/web/auth"
 )
 
 // AzureHandler gerencia operações do Azure CLI
@@ -16,8 +18,13 @@ func NewAzureHandler() *AzureHandler {
 	return &AzureHandler{}
 }
 
-// SetSubscription define a subscription ativa do Azure CLI
+// SetSubscription define a subscription ativa do Azure CLI. A subscription vale para
+// o servidor inteiro: exige permissão admin
 func (h *AzureHandler) SetSubscription(c *gin.Context) {
+	if !auth.Require(c, auth.OpAdmin, "") {
+		return
+	}
+
 	var request struct {
 		Subscription string `json:"subscription" binding:"required"`
 	}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"k8s-hpa-manager/internal/config"
+	"k8s-hpa-manager/internal/web/auth"
 
 	"github.com/gin-gonic/gin"
 )
@@ -44,6 +45,9 @@ func (h *ClusterHandler) List(c *gin.Context) {
 // Test testa a conexão com um cluster específico
 func (h *ClusterHandler) Test(c *gin.Context) {
 	clusterName := c.Param("name")
+	if !auth.Require(c, auth.OpRead, clusterName) {
+		return
+	}
 
 	// Testar conexão (reutilizar código existente)
 	status := h.kubeManager.TestClusterConnection(c.Request.Context(), clusterName)
@@ -58,7 +62,12 @@ func (h *ClusterHandler) Test(c *gin.Context) {
 }
 
 // SwitchContext muda o contexto ativo do Kubernetes e Azure CLI para o cluster especificado
+// Afeta as requisições de todos os usuários: exige permissão admin
 func (h *ClusterHandler) SwitchContext(c *gin.Context) {
+	if !auth.Require(c, auth.OpAdmin, "") {
+		return
+	}
+
 	var request struct {
 		Context string `json:"context" binding:"required"`
 	}
@@ -107,6 +116,9 @@ func (h *ClusterHandler) GetClusterInfo(c *gin.Context) {
 		// Se não especificado, usar o contexto atual
 		clusterName = h.kubeManager.GetCurrentContext()
 	}
+	if !auth.Require(c, auth.OpRead, clusterName) {
+		return
+	}
 
 	// Obter informações básicas do cluster
 	clusterInfo, err := h.kubeManager.GetClusterInfo(clusterName)
@@ -158,6 +170,9 @@ func (h *ClusterHandler) GetClusterInfo(c *gin.Context) {
 // GetClusterConfig retorna configuração do cluster do arquivo clusters-config.json
 func (h *ClusterHandler) GetClusterConfig(c *gin.Context) {
 	clusterName := c.Param("name")
+	if !auth.Require(c, auth.OpRead, clusterName) {
+		return
+	}
 
 	// Buscar configuração no arquivo clusters-config.json
 	clusterConfig, err := h.kubeManager.GetClusterConfigFromFile(clusterName)
@@ -176,7 +191,12 @@ func (h *ClusterHandler) GetClusterConfig(c *gin.Context) {
 }
 
 // SwitchToClusterContext troca para o contexto de um cluster específico
+// Afeta as requisições de todos os usuários: exige permissão admin
 func (h *ClusterHandler) SwitchToClusterContext(c *gin.Context) {
+	if !auth.Require(c, auth.OpAdmin, "") {
+		return
+	}
+
 	clusterName := c.Param("name")
 
 	log.Printf("[ClusterHandler] Switching to cluster context: %s", clusterName)
@@ -12,10 +12,12 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/yaml"
 
+	"k8s-hpa-manager/internal/audit"
 	"k8s-hpa-manager/internal/config"
 	"k8s-hpa-manager/internal/history"
 	kubeclient "k8s-hpa-manager/internal/kubernetes"
 	"k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/web/auth"
 )
 
 // ConfigMapHandler gerencia as rotas de ConfigMaps (placeholder KISS)
@@ -45,6 +47,9 @@ func (h *ConfigMapHandler) List(c *gin.Context) {
 		})
 		return
 	}
+	if !auth.Require(c, auth.OpRead, cluster) {
+		return
+	}
 
 	namespaces := parseNamespaces(c.Query("namespaces"))
 	showSystem := c.Query("showSystem") == "true"
@@ -98,6 +103,9 @@ func (h *ConfigMapHandler) Get(c *gin.Context) {
 		})
 		return
 	}
+	if !auth.Require(c, auth.OpRead, cluster) {
+		return
+	}
 
 	clientset, err := h.kubeManager.GetClient(cluster)
 	if err != nil {
@@ -139,6 +147,7 @@ func (h *ConfigMapHandler) Get(c *gin.Context) {
 // Diff retornará o diff textual antes do apply
 // Diff gera diff texto simples entre YAMLs
 func (h *ConfigMapHandler) Diff(c *gin.Context) {
+	skipAudit(c)
 	var req configMapDiffRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, errorResponse("INVALID_REQUEST", fmt.Sprintf("Invalid body: %v", err)))
@@ -179,6 +188,7 @@ func (h *ConfigMapHandler) Diff(c *gin.Context) {
 
 // Validate executa server-side apply com dry-run
 func (h *ConfigMapHandler) Validate(c *gin.Context) {
+	skipAudit(c)
 	var req configMapValidateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, errorResponse("INVALID_REQUEST", fmt.Sprintf("Invalid body: %v", err)))
@@ -189,6 +199,9 @@ func (h *ConfigMapHandler) Validate(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, errorResponse("MISSING_PARAMETER", "cluster, namespace and yaml are required"))
 		return
 	}
+	if !auth.Require(c, auth.OpRead, req.Cluster) {
+		return
+	}
 
 	clientset, err := h.kubeManager.GetClient(req.Cluster)
 	if err != nil {
@@ -243,6 +256,15 @@ func (h *ConfigMapHandler) Apply(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, errorResponse("INVALID_REQUEST", "yaml is required"))
 		return
 	}
+	// Dry-run não altera o cluster: basta leitura
+	op := auth.OpConfigMapApply
+	if req.DryRun {
+		skipAudit(c)
+		op = auth.OpRead
+	}
+	if !auth.Require(c, op, cluster) {
+		return
+	}
 
 	clientset, err := h.kubeManager.GetClient(cluster)
 	if err != nil {
@@ -273,10 +295,29 @@ func (h *ConfigMapHandler) Apply(c *gin.Context) {
 		if apierrors.IsConflict(err) {
 			status = http.StatusConflict
 		}
+		if !req.DryRun {
+			auditRecorder(c).Record(audit.Event{
+				Action:   audit.ActionApplyConfigMap,
+				Cluster:  cluster,
+				Resource: fmt.Sprintf("%s/%s", namespace, name),
+				Before:   before,
+				Err:      err,
+			})
+		}
 		c.JSON(status, errorResponse(errorCode, err.Error()))
 		return
 	}
 
+	if !req.DryRun {
+		auditRecorder(c).Record(audit.Event{
+			Action:   audit.ActionApplyConfigMap,
+			Cluster:  cluster,
+			Resource: fmt.Sprintf("%s/%s", namespace, name),
+			Before:   before,
+			After:    configMapToHistoryMap(result),
+		})
+	}
+
 	if !req.DryRun && h.historyTracker != nil {
 		after := configMapToHistoryMap(result)
 		entry := history.HistoryEntry{
@@ -287,6 +328,7 @@ func (h *ConfigMapHandler) Apply(c *gin.Context) {
 			After:    after,
 			Status:   "success",
 			Duration: time.Since(start).Milliseconds(),
+			User:     auth.User(c),
 		}
 		if err := h.historyTracker.Log(entry); err != nil {
 			fmt.Printf("warning: failed to record history entry: %v\n", err)
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"k8s-hpa-manager/internal/config"
+	"k8s-hpa-manager/internal/web/auth"
 
 	"github.com/gin-gonic/gin"
 	batchv1 "k8s.io/api/batch/v1"
@@ -49,6 +50,9 @@ func (h *CronJobHandler) List(c *gin.Context) {
 		})
 		return
 	}
+	if !auth.Require(c, auth.OpRead, cluster) {
+		return
+	}
 
 	// Definir namespace para busca (vazio significa todos os namespaces)
 	namespaceFilter := namespace
@@ -111,6 +115,10 @@ func (h *CronJobHandler) Update(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
 
+	if !auth.Require(c, auth.OpWorkloadEdit, cluster) {
+		return
+	}
+
 	var req struct {
 		Suspend bool `json:"suspend"`
 	}
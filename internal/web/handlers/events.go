@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"k8s-hpa-manager/internal/web/auth"
+	"k8s-hpa-manager/internal/web/events"
+)
+
+// eventsKeepAlive intervalo dos comentários SSE que mantêm a conexão aberta em proxies
+const eventsKeepAlive = 15 * time.Second
+
+// EventsHandler stream de eventos (HPAs, node pools, anomalias, histórico e operações)
+type EventsHandler struct {
+	hub     *events.Hub
+	watcher *events.Watcher
+}
+
+// NewEventsHandler cria o handler do stream de eventos. Os streams abertos terminam
+// quando o hub é fechado (graceful shutdown).
+func NewEventsHandler(hub *events.Hub, watcher *events.Watcher) *EventsHandler {
+	return &EventsHandler{hub: hub, watcher: watcher}
+}
+
+// Stream envia eventos via Server-Sent Events. Os informers de HPAs e nodes são iniciados
+// para os clusters informados em cluster; sem cluster, o cliente recebe anomalias, histórico,
+// operações e os eventos dos clusters já observados por outros clientes (com permissão de leitura).
+// Reconexões enviam o header Last-Event-ID (ou last_event_id) para receber o que foi perdido;
+// se não for possível, o primeiro evento é "reset" e o cliente deve recarregar o estado.
+// GET /api/v1/events?cluster=a,b&namespace=x&types=hpa,anomaly&last_event_id=
+func (h *EventsHandler) Stream(c *gin.Context) {
+	filter := events.Filter{
+		Clusters:   queryList(c, "cluster"),
+		Namespaces: queryList(c, "namespace"),
+		Types:      queryList(c, "types"),
+	}
+	for _, cluster := range filter.Clusters {
+		if !auth.Require(c, auth.OpRead, cluster) {
+			return
+		}
+	}
+	id := auth.FromContext(c)
+	filter.Permit = func(cluster string) bool {
+		return id.Can(auth.OpRead, cluster)
+	}
+
+	flusher, ok := c.Writer.(http.Flusher)
+	if !ok {
+		c.JSON(500, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "STREAMING_UNSUPPORTED",
+				"message": "Streaming not supported",
+			},
+		})
+		return
+	}
+
+	var acquired []string
+	defer func() {
+		for _, cluster := range acquired {
+			h.watcher.Release(cluster)
+		}
+	}()
+	for _, cluster := range filter.Clusters {
+		if err := h.watcher.Acquire(cluster); err != nil {
+			c.JSON(500, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "CLIENT_ERROR",
+					"message": fmt.Sprintf("Failed to watch cluster %s: %v", cluster, err),
+				},
+			})
+			return
+		}
+		acquired = append(acquired, cluster)
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	sub, backlog, err := h.hub.Subscribe(filter, lastEventID)
+	if err != nil {
+		c.JSON(503, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "SHUTTING_DOWN",
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+	defer h.hub.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Desabilitar buffering no nginx
+	c.Status(http.StatusOK)
+
+	fmt.Printf("📡 Stream de eventos aberto por %s (clusters: %v)\n", id.Name(), filter.Clusters)
+
+	// retry: intervalo de reconexão sugerido ao EventSource
+	fmt.Fprintf(c.Writer, "retry: 3000\n\n")
+	for _, e := range backlog {
+		writeEvent(c.Writer, e)
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case e, ok := <-sub.Events:
+			if !ok {
+				// Cliente lento ou shutdown: o cliente reconecta com Last-Event-ID
+				return
+			}
+			writeEvent(c.Writer, e)
+			flusher.Flush()
+		case <-keepAlive.C:
+			fmt.Fprintf(c.Writer, ": keep-alive\n\n")
+			flusher.Flush()
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
+
+// writeEvent escreve o evento no formato SSE (id, event e data)
+func writeEvent(w http.ResponseWriter, e events.Event) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		fmt.Fprintf(w, "event: error\ndata: {\"error\":\"Failed to encode event\"}\n\n")
+		return
+	}
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+}
+
+// queryList lê um parâmetro repetido ou separado por vírgulas (?cluster=a,b ou ?cluster=a&cluster=b)
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(key) {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
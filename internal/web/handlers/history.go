@@ -7,6 +7,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"k8s-hpa-manager/internal/history"
+	"k8s-hpa-manager/internal/web/auth"
 )
 
 // HistoryHandler gerencia endpoints de histórico
@@ -24,6 +25,10 @@ func NewHistoryHandler(tracker *history.HistoryTracker) *HistoryHandler {
 // GetHistory retorna histórico com filtros opcionais
 // GET /api/v1/history?action=update_hpa&cluster=akspriv-prod&start_date=2025-01-01
 func (h *HistoryHandler) GetHistory(c *gin.Context) {
+	if !auth.Require(c, auth.OpRead, c.Query("cluster")) {
+		return
+	}
+
 	// Parse filtros da query string
 	filter := history.HistoryFilter{
 		Action:      c.Query("action"),
@@ -81,6 +86,10 @@ func (h *HistoryHandler) GetHistoryEntry(c *gin.Context) {
 // ClearHistory limpa todo o histórico
 // DELETE /api/v1/history
 func (h *HistoryHandler) ClearHistory(c *gin.Context) {
+	if !auth.Require(c, auth.OpAdmin, "") {
+		return
+	}
+
 	if err := h.tracker.Clear(); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"time"
 
+	"k8s-hpa-manager/internal/audit"
 	"k8s-hpa-manager/internal/config"
 	"k8s-hpa-manager/internal/history"
 	kubeclient "k8s-hpa-manager/internal/kubernetes"
 	"k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/web/auth"
 
 	"github.com/gin-gonic/gin"
 )
@@ -42,6 +44,9 @@ func (h *HPAHandler) List(c *gin.Context) {
 		})
 		return
 	}
+	if !auth.Require(c, auth.OpRead, cluster) {
+		return
+	}
 
 	// Parse showSystem parameter (default: false)
 	showSystem := false
@@ -119,6 +124,10 @@ func (h *HPAHandler) Get(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
 
+	if !auth.Require(c, auth.OpRead, cluster) {
+		return
+	}
+
 	// Obter client
 	client, err := h.kubeManager.GetClient(cluster)
 	if err != nil {
@@ -173,6 +182,10 @@ func (h *HPAHandler) Update(c *gin.Context) {
 	namespace := c.Param("namespace")
 	name := c.Param("name")
 
+	if !auth.Require(c, auth.OpHPAEdit, cluster) {
+		return
+	}
+
 	// Timestamp de início para medir duração
 	startTime := time.Now()
 
@@ -214,6 +227,28 @@ func (h *HPAHandler) Update(c *gin.Context) {
 		return
 	}
 
+	if err := kubeclient.ValidateMetrics(hpa.Metrics); err != nil {
+		c.JSON(400, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_VALUE",
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := kubeclient.ValidateBehavior(hpa.Behavior); err != nil {
+		c.JSON(400, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_VALUE",
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
 	// Obter client
 	client, err := h.kubeManager.GetClient(cluster)
 	if err != nil {
@@ -242,6 +277,8 @@ func (h *HPAHandler) Update(c *gin.Context) {
 			"memory_request":  beforeHPA.TargetMemoryRequest,  // Use Target* (configuração do deployment)
 			"cpu_limit":       beforeHPA.TargetCPULimit,       // Use Target* (configuração do deployment)
 			"memory_limit":    beforeHPA.TargetMemoryLimit,    // Use Target* (configuração do deployment)
+			"metrics":         beforeHPA.Metrics,
+			"behavior":        beforeHPA.Behavior,
 		}
 	}
 
@@ -251,6 +288,14 @@ func (h *HPAHandler) Update(c *gin.Context) {
 	hpa.Cluster = cluster
 
 	if err := kubeClient.UpdateHPA(c.Request.Context(), hpa); err != nil {
+		auditRecorder(c).Record(audit.Event{
+			Action:   audit.ActionUpdateHPA,
+			Cluster:  cluster,
+			Resource: fmt.Sprintf("%s/%s", namespace, name),
+			Before:   beforeState,
+			Err:      err,
+		})
+
 		// Log falha no history
 		if h.historyTracker != nil && beforeState != nil {
 			duration := time.Since(startTime).Milliseconds()
@@ -263,6 +308,7 @@ func (h *HPAHandler) Update(c *gin.Context) {
 				Status:   history.StatusFailed,
 				ErrorMsg: err.Error(),
 				Duration: duration,
+				User:     auth.User(c),
 			})
 		}
 
@@ -296,8 +342,18 @@ func (h *HPAHandler) Update(c *gin.Context) {
 		"memory_request": updatedHPA.TargetMemoryRequest,  // Use Target* (configuração do deployment)
 		"cpu_limit":      updatedHPA.TargetCPULimit,       // Use Target* (configuração do deployment)
 		"memory_limit":   updatedHPA.TargetMemoryLimit,    // Use Target* (configuração do deployment)
+		"metrics":        updatedHPA.Metrics,
+		"behavior":       updatedHPA.Behavior,
 	}
 
+	auditRecorder(c).Record(audit.Event{
+		Action:   audit.ActionUpdateHPA,
+		Cluster:  cluster,
+		Resource: fmt.Sprintf("%s/%s", namespace, name),
+		Before:   beforeState,
+		After:    afterState,
+	})
+
 	// Log sucesso no history
 	if h.historyTracker != nil {
 		duration := time.Since(startTime).Milliseconds()
@@ -309,6 +365,7 @@ func (h *HPAHandler) Update(c *gin.Context) {
 			After:    afterState,
 			Status:   history.StatusSuccess,
 			Duration: duration,
+			User:     auth.User(c),
 		})
 	}
 
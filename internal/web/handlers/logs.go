@@ -8,6 +8,8 @@ import (
 	"sync"
 	"time"
 
+	"k8s-hpa-manager/internal/web/auth"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -115,6 +117,10 @@ func (h *LogsHandler) GetLogs(c *gin.Context) {
 
 // ClearLogs limpa os logs da aplicação
 func (h *LogsHandler) ClearLogs(c *gin.Context) {
+	if !auth.Require(c, auth.OpAdmin, "") {
+		return
+	}
+
 	h.logBuffer.Clear()
 
 	c.JSON(200, gin.H{
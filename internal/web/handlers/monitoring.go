@@ -12,6 +12,7 @@ import (
 	"k8s-hpa-manager/internal/monitoring/models"
 	"k8s-hpa-manager/internal/monitoring/scanner"
 	"k8s-hpa-manager/internal/monitoring/storage"
+	"k8s-hpa-manager/internal/web/auth"
 )
 
 // MonitoringHandler gerencia endpoints de monitoramento
@@ -23,6 +24,14 @@ type MonitoringHandler struct {
 
 	// Cache de anomalias em memória (até implementar storage)
 	anomalies []analyzer.Anomaly
+
+	// Alertas externos (Alertmanager) mesclados em GetAnomalies
+	alertProvider AlertProvider
+}
+
+// AlertProvider fonte de alertas externos (ex: alertmanager.Collector)
+type AlertProvider interface {
+	Alerts() []models.UnifiedAlert
 }
 
 // NewMonitoringHandler cria novo handler de monitoramento
@@ -41,6 +50,11 @@ func NewMonitoringHandler(eng *engine.ScanEngine, persistence *storage.Persisten
 	return h
 }
 
+// SetAlertProvider configura a fonte de alertas externos mesclados às anomalias
+func (h *MonitoringHandler) SetAlertProvider(provider AlertProvider) {
+	h.alertProvider = provider
+}
+
 // collectAnomalies coleta anomalias do canal em background
 func (h *MonitoringHandler) collectAnomalies() {
 	for anomaly := range h.anomalyChan {
@@ -58,6 +72,9 @@ func (h *MonitoringHandler) GetMetrics(c *gin.Context) {
 	cluster := c.Param("cluster")
 	namespace := c.Param("namespace")
 	hpaName := c.Param("hpaName")
+	if !auth.Require(c, auth.OpRead, cluster) {
+		return
+	}
 	duration := c.DefaultQuery("duration", "1h") // Default 1h (mais útil que 5m)
 
 	// Remove sufixo -admin do cluster para buscar no SQLite
@@ -116,7 +133,7 @@ func (h *MonitoringHandler) GetMetrics(c *gin.Context) {
 	// Converter para formato API
 	apiSnapshots := make([]gin.H, 0, len(snapshots))
 	for _, snap := range snapshots {
-		apiSnapshots = append(apiSnapshots, gin.H{
+		apiSnap := gin.H{
 			"cluster":          snap.Cluster,
 			"namespace":        snap.Namespace,
 			"hpa_name":         snap.Name,
@@ -141,7 +158,12 @@ func (h *MonitoringHandler) GetMetrics(c *gin.Context) {
 			"p99_latency":      snap.P99Latency,
 			"network_rx_bytes": snap.NetworkRxBytes,
 			"network_tx_bytes": snap.NetworkTxBytes,
-		})
+		}
+		// Períodos longos vêm dos rollups: métricas acima são médias do bucket
+		if _, ok := snap.AdditionalMetrics["rollup_samples"]; ok {
+			apiSnap["aggregates"] = snap.AdditionalMetrics
+		}
+		apiSnapshots = append(apiSnapshots, apiSnap)
 	}
 
 	// Converter dados de ontem para formato API
@@ -190,6 +212,7 @@ func (h *MonitoringHandler) GetMetrics(c *gin.Context) {
 		"namespace":           namespace,
 		"hpa_name":            hpaName,
 		"duration":            duration,
+		"resolution":          h.persistence.ResolutionFor(since),
 		"snapshots":           apiSnapshots,
 		"snapshots_yesterday": apiSnapshotsYesterday,
 		"count":               len(apiSnapshots),
@@ -197,15 +220,44 @@ func (h *MonitoringHandler) GetMetrics(c *gin.Context) {
 	})
 }
 
-// GetAnomalies retorna anomalias detectadas
-// GET /api/v1/monitoring/anomalies?cluster=X&severity=critical
+// GetAnomalies retorna anomalias detectadas e alertas do Alertmanager
+// GET /api/v1/monitoring/anomalies?cluster=X&severity=critical&source=all|watchdog|alertmanager&state=active|all|open,acked
 func (h *MonitoringHandler) GetAnomalies(c *gin.Context) {
 	cluster := c.Query("cluster")
 	severityParam := c.DefaultQuery("severity", "all")
+	source := c.DefaultQuery("source", "all")
+	if !auth.Require(c, auth.OpRead, cluster) {
+		return
+	}
 
 	// Filtrar anomalias
 	filtered := make([]gin.H, 0)
+
+	// Alertas persistidos (com estado/ack); sem SQLite usa o cache em memória
+	persisted := false
+	if h.persistence != nil && (source == "all" || source == "watchdog") {
+		alerts, err := h.persistence.ListAlerts(storage.AlertFilter{
+			Cluster: strings.TrimSuffix(cluster, "-admin"),
+			States:  parseAlertStates(c.DefaultQuery("state", "active")),
+		})
+		if err != nil {
+			log.Warn().Err(err).Msg("Falha ao listar alertas persistidos, usando cache em memória")
+		} else {
+			persisted = true
+			for _, alert := range alerts {
+				if severityParam != "all" && severityToString(alert.Severity) != severityParam {
+					continue
+				}
+				filtered = append(filtered, persistedAlertToAPI(alert))
+			}
+		}
+	}
+
 	for _, anomaly := range h.anomalies {
+		if persisted || (source != "all" && source != "watchdog") {
+			break
+		}
+
 		// Filtro por cluster
 		if cluster != "" && anomaly.Cluster != cluster {
 			continue
@@ -219,21 +271,19 @@ func (h *MonitoringHandler) GetAnomalies(c *gin.Context) {
 			}
 		}
 
-		// Converter para formato API
-		filtered = append(filtered, gin.H{
-			"id":               generateAnomalyID(anomaly),
-			"cluster":          anomaly.Cluster,
-			"namespace":        anomaly.Namespace,
-			"hpa_name":         anomaly.HPAName,
-			"type":             string(anomaly.Type),
-			"severity":         severityToString(anomaly.Severity),
-			"detected_at":      anomaly.Timestamp.Format(time.RFC3339),
-			"duration_seconds": 0, // Não temos duração na estrutura atual
-			"message":          anomaly.Message,
-			"details":          gin.H{"description": anomaly.Description},
-			"resolved":         false, // Não temos flag de resolved
-			"resolved_at":      nil,
-		})
+		filtered = append(filtered, anomalyToAPI(anomaly))
+	}
+
+	if h.alertProvider != nil && (source == "all" || source == "alertmanager") {
+		for _, alert := range h.alertProvider.Alerts() {
+			if cluster != "" && alert.Cluster != strings.TrimSuffix(cluster, "-admin") {
+				continue
+			}
+			if severityParam != "all" && severityToString(alert.Severity) != severityParam {
+				continue
+			}
+			filtered = append(filtered, unifiedAlertToAPI(alert))
+		}
 	}
 
 	c.JSON(200, gin.H{
@@ -244,12 +294,48 @@ func (h *MonitoringHandler) GetAnomalies(c *gin.Context) {
 	})
 }
 
+// AnomalyToAPI converte uma anomalia para o formato de GET /monitoring/anomalies
+// (alerta persistido quando alert != nil). Usado no evento anomaly de GET /events.
+func AnomalyToAPI(anomaly analyzer.Anomaly, alert *storage.Alert) gin.H {
+	if alert != nil {
+		return persistedAlertToAPI(*alert)
+	}
+	return anomalyToAPI(anomaly)
+}
+
+// anomalyToAPI formato da API para anomalias do cache em memória (sem SQLite)
+func anomalyToAPI(anomaly analyzer.Anomaly) gin.H {
+	details := gin.H{"description": anomaly.Description}
+	if anomaly.Rule != "" {
+		details["rule"] = anomaly.Rule
+	}
+
+	return gin.H{
+		"id":               generateAnomalyID(anomaly),
+		"source":           "watchdog",
+		"cluster":          anomaly.Cluster,
+		"namespace":        anomaly.Namespace,
+		"hpa_name":         anomaly.HPAName,
+		"type":             string(anomaly.Type),
+		"severity":         severityToString(anomaly.Severity),
+		"detected_at":      anomaly.Timestamp.Format(time.RFC3339),
+		"duration_seconds": 0, // Não temos duração na estrutura atual
+		"message":          anomaly.Message,
+		"details":          details,
+		"resolved":         false, // Não temos flag de resolved
+		"resolved_at":      nil,
+	}
+}
+
 // GetHealth retorna status de saúde de um HPA
 // GET /api/v1/monitoring/health/:cluster/:namespace/:hpaName
 func (h *MonitoringHandler) GetHealth(c *gin.Context) {
 	cluster := c.Param("cluster")
 	namespace := c.Param("namespace")
 	hpaName := c.Param("hpaName")
+	if !auth.Require(c, auth.OpRead, cluster) {
+		return
+	}
 
 	// Remove sufixo -admin do cluster para buscar anomalias
 	cacheCluster := strings.TrimSuffix(cluster, "-admin")
@@ -384,11 +470,15 @@ func (h *MonitoringHandler) GetStatus(c *gin.Context) {
 		portMapping = priorityCollector.GetPortMapping()
 	}
 
+	monitoredHPAs, _ := h.engine.MonitoredHPAs()
+
 	c.JSON(200, gin.H{
 		"running":     running,
+		"paused":      paused,
 		"status":      status,
 		"mode":        "individual",
-		"interval":    "1m",
+		"interval":    h.engine.ScanInterval().String(),
+		"hpas":        len(monitoredHPAs),
 		"clusters":    len(clustersMap),
 		"last_scan":   formatTime(lastScan),
 		"total_scans": totalSnapshots,
@@ -399,6 +489,10 @@ func (h *MonitoringHandler) GetStatus(c *gin.Context) {
 // Start inicia o monitoring engine
 // POST /api/v1/monitoring/start
 func (h *MonitoringHandler) Start(c *gin.Context) {
+	if !auth.Require(c, auth.OpMonitoringManage, "") {
+		return
+	}
+
 	if h.engine.IsRunning() {
 		c.JSON(200, gin.H{
 			"status":  "already_running",
@@ -426,6 +520,10 @@ func (h *MonitoringHandler) Start(c *gin.Context) {
 // Stop para o monitoring engine
 // POST /api/v1/monitoring/stop
 func (h *MonitoringHandler) Stop(c *gin.Context) {
+	if !auth.Require(c, auth.OpMonitoringManage, "") {
+		return
+	}
+
 	if !h.engine.IsRunning() {
 		c.JSON(200, gin.H{
 			"status":  "already_stopped",
@@ -450,6 +548,89 @@ func (h *MonitoringHandler) Stop(c *gin.Context) {
 	})
 }
 
+// Pause suspende os scans mantendo os port-forwards (estado restaurado no próximo startup)
+// POST /api/v1/monitoring/pause
+func (h *MonitoringHandler) Pause(c *gin.Context) {
+	if !auth.Require(c, auth.OpMonitoringManage, "") {
+		return
+	}
+
+	if !h.engine.IsRunning() {
+		c.JSON(409, gin.H{
+			"status":  "error",
+			"message": "Monitoring engine is not running",
+		})
+		return
+	}
+
+	h.engine.Pause()
+	c.JSON(200, gin.H{
+		"status":  "paused",
+		"message": "Monitoring engine paused",
+	})
+}
+
+// Resume retoma os scans pausados
+// POST /api/v1/monitoring/resume
+func (h *MonitoringHandler) Resume(c *gin.Context) {
+	if !auth.Require(c, auth.OpMonitoringManage, "") {
+		return
+	}
+
+	if !h.engine.IsRunning() {
+		c.JSON(409, gin.H{
+			"status":  "error",
+			"message": "Monitoring engine is not running",
+		})
+		return
+	}
+
+	h.engine.Resume()
+	c.JSON(200, gin.H{
+		"status":  "running",
+		"message": "Monitoring engine resumed",
+	})
+}
+
+// SetInterval altera o intervalo entre scans dos HPAs monitorados
+// PUT /api/v1/monitoring/interval
+// Body: { "interval": "1m" }
+func (h *MonitoringHandler) SetInterval(c *gin.Context) {
+	if !auth.Require(c, auth.OpMonitoringManage, "") {
+		return
+	}
+
+	var req struct {
+		Interval string `json:"interval" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{
+			"status":  "error",
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	interval, err := time.ParseDuration(req.Interval)
+	if err == nil {
+		err = h.engine.SetScanInterval(interval)
+	}
+	if err != nil {
+		c.JSON(400, gin.H{
+			"status":  "error",
+			"message": "Invalid interval",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"status":   "success",
+		"interval": h.engine.ScanInterval().String(),
+	})
+}
+
 // Helper functions
 
 func formatTime(t *time.Time) interface{} {
@@ -472,6 +653,36 @@ func severityToString(s models.AlertSeverity) string {
 	}
 }
 
+// unifiedAlertToAPI converte um alerta do Alertmanager para o mesmo formato das anomalias
+func unifiedAlertToAPI(alert models.UnifiedAlert) gin.H {
+	alertType := alert.AlertName
+	if alertType == "" {
+		alertType = alert.Type.String()
+	}
+	return gin.H{
+		"id":               alert.ID,
+		"source":           "alertmanager",
+		"cluster":          alert.Cluster,
+		"namespace":        alert.Namespace,
+		"hpa_name":         alert.HPAName,
+		"type":             alertType,
+		"severity":         severityToString(alert.Severity),
+		"detected_at":      alert.Timestamp.Format(time.RFC3339),
+		"duration_seconds": int(time.Since(alert.Timestamp).Seconds()),
+		"message":          alert.Summary,
+		"details": gin.H{
+			"description":   alert.Description,
+			"status":        alert.Status,
+			"fingerprint":   alert.Fingerprint,
+			"generator_url": alert.GeneratorURL,
+			"silenced_by":   alert.SilencedBy,
+			"labels":        alert.Labels,
+		},
+		"resolved":    false,
+		"resolved_at": nil,
+	}
+}
+
 func generateAnomalyID(a analyzer.Anomaly) string {
 	// Gerar ID simples baseado em timestamp e HPA
 	return a.Cluster + "-" + a.Namespace + "-" + a.HPAName + "-" + a.Timestamp.Format("20060102150405")
@@ -547,6 +758,10 @@ func (h *MonitoringHandler) AddTarget(c *gin.Context) {
 		return
 	}
 
+	if !auth.Require(c, auth.OpMonitoringManage, req.Cluster) {
+		return
+	}
+
 	target := scanner.ScanTarget{
 		Cluster:     req.Cluster,
 		Namespaces:  req.Namespaces,
@@ -587,6 +802,10 @@ func (h *MonitoringHandler) AddHPA(c *gin.Context) {
 		return
 	}
 
+	if !auth.Require(c, auth.OpMonitoringManage, req.Cluster) {
+		return
+	}
+
 	// IMPORTANTE: Remove sufixo -admin do cluster para o scanner/portforward
 	// O frontend envia "akspriv-prod-admin", mas o scanner precisa de "akspriv-prod"
 	clusterName := strings.TrimSuffix(req.Cluster, "-admin")
@@ -598,17 +817,8 @@ func (h *MonitoringHandler) AddHPA(c *gin.Context) {
 		Str("hpa", req.HPA).
 		Msg("Adicionando HPA ao monitoramento")
 
-	// Adiciona HPA ao PriorityCollector (com port-forward dedicado)
-	priorityCollector := h.engine.GetPriorityCollector()
-	if priorityCollector == nil {
-		c.JSON(500, gin.H{
-			"status":  "error",
-			"message": "PriorityCollector not available",
-		})
-		return
-	}
-
-	if err := priorityCollector.AddPriorityHPA(clusterName, req.Namespace, req.HPA); err != nil {
+	// Adiciona HPA ao PriorityCollector (com port-forward dedicado) e ao estado salvo
+	if err := h.engine.AddMonitoredHPA(clusterName, req.Namespace, req.HPA, auth.User(c)); err != nil {
 		log.Error().
 			Err(err).
 			Str("cluster", clusterName).
@@ -654,6 +864,10 @@ func (h *MonitoringHandler) RemoveTarget(c *gin.Context) {
 		return
 	}
 
+	if !auth.Require(c, auth.OpMonitoringManage, cluster) {
+		return
+	}
+
 	h.engine.RemoveTarget(cluster)
 
 	c.JSON(200, gin.H{
@@ -663,9 +877,66 @@ func (h *MonitoringHandler) RemoveTarget(c *gin.Context) {
 	})
 }
 
-// SyncMonitoredHPAs sincroniza lista completa de HPAs monitorados (reconciliação)
+// ListHPAs lista os HPAs monitorados (os mesmos restaurados no startup do servidor)
+// GET /api/v1/monitoring/hpas
+func (h *MonitoringHandler) ListHPAs(c *gin.Context) {
+	hpas, err := h.engine.MonitoredHPAs()
+	if err != nil {
+		c.JSON(500, gin.H{
+			"status":  "error",
+			"message": "Failed to list monitored HPAs",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if hpas == nil {
+		hpas = []storage.MonitoredHPA{}
+	}
+
+	c.JSON(200, gin.H{
+		"status": "success",
+		"total":  len(hpas),
+		"hpas":   hpas,
+	})
+}
+
+// RemoveHPA retira um HPA do monitoramento (e do estado restaurado no startup)
+// DELETE /api/v1/monitoring/hpa/:cluster/:namespace/:name
+func (h *MonitoringHandler) RemoveHPA(c *gin.Context) {
+	cluster := c.Param("cluster")
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	if !auth.Require(c, auth.OpMonitoringManage, cluster) {
+		return
+	}
+
+	if err := h.engine.RemoveMonitoredHPA(cluster, namespace, name); err != nil {
+		c.JSON(500, gin.H{
+			"status":  "error",
+			"message": "Failed to remove HPA from monitoring",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"status":  "success",
+		"message": "HPA removed from monitoring",
+	})
+}
+
+// SyncMonitoredHPAs mescla a lista do navegador (localStorage) com os HPAs salvos no servidor.
+// O servidor é a fonte da verdade: HPAs que só o navegador conhece são adicionados, os que só
+// o servidor conhece (restaurados no startup ou incluídos por outro operador) são mantidos e
+// devolvidos em "hpas" para o navegador atualizar sua lista. Remoções usam DELETE /monitoring/hpa.
+// Entradas de clusters sem monitoring_manage para o usuário não são adicionadas e voltam em "denied".
 // POST /api/v1/monitoring/sync
 func (h *MonitoringHandler) SyncMonitoredHPAs(c *gin.Context) {
+	if !auth.Require(c, auth.OpMonitoringManage, "") {
+		return
+	}
+
 	var req struct {
 		HPAs []struct {
 			Cluster   string `json:"cluster"`
@@ -683,94 +954,80 @@ func (h *MonitoringHandler) SyncMonitoredHPAs(c *gin.Context) {
 		Int("hpas_count", len(req.HPAs)).
 		Msg("🔄 Iniciando reconciliação de HPAs monitorados")
 
-	// Construir mapa de HPAs desejados (frontend)
-	// Normalizar cluster name removendo sufixo -admin para comparação consistente
-	desiredHPAs := make(map[string]bool)
-	for _, hpa := range req.HPAs {
-		// Normalizar cluster name (remover -admin se presente)
-		clusterName := strings.TrimSuffix(hpa.Cluster, "-admin")
-		key := clusterName + "/" + hpa.Namespace + "/" + hpa.HPA
-		desiredHPAs[key] = true
+	current, err := h.engine.MonitoredHPAs()
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
 	}
-
-	// Obter lista atual de targets do engine
-	currentTargets := h.engine.GetTargets()
-
-	// Construir mapa de HPAs atuais (backend)
-	currentHPAs := make(map[string]bool)
-	for _, target := range currentTargets {
-		for _, ns := range target.Namespaces {
-			for _, hpaName := range target.HPAs {
-				key := target.Cluster + "/" + ns + "/" + hpaName
-				currentHPAs[key] = true
-			}
-		}
+	currentHPAs := make(map[string]bool, len(current))
+	for _, hpa := range current {
+		currentHPAs[hpa.Key()] = true
 	}
 
-	// Calcular diferenças
+	// Adicionar HPAs que estão no navegador mas não no servidor
+	id := auth.FromContext(c)
 	added := 0
-	removed := 0
-
-	// Adicionar HPAs que estão no frontend mas não no backend
+	denied := []string{}
 	for _, hpa := range req.HPAs {
-		// Normalizar cluster name (já normalizado no mapa desiredHPAs)
+		// Normalizar cluster name (remover -admin se presente)
 		clusterName := strings.TrimSuffix(hpa.Cluster, "-admin")
 		key := clusterName + "/" + hpa.Namespace + "/" + hpa.HPA
-		if !currentHPAs[key] {
+		if currentHPAs[key] || hpa.Namespace == "" || hpa.HPA == "" {
+			continue
+		}
+		currentHPAs[key] = true
 
-			log.Info().
+		if !id.Can(auth.OpMonitoringManage, clusterName) {
+			log.Warn().
+				Str("user", id.Name()).
 				Str("cluster", clusterName).
 				Str("namespace", hpa.Namespace).
 				Str("hpa", hpa.HPA).
-				Msg("➕ Adicionando HPA ao monitoramento (reconciliação)")
-
-			// CORREÇÃO: Adiciona diretamente ao PriorityCollector
-			// Cada HPA monitorado DEVE ter port-forward dedicado + baseline
-			if err := h.engine.GetPriorityCollector().AddPriorityHPA(clusterName, hpa.Namespace, hpa.HPA); err != nil {
-				log.Error().
-					Err(err).
-					Str("cluster", clusterName).
-					Str("namespace", hpa.Namespace).
-					Str("hpa", hpa.HPA).
-					Msg("❌ Erro ao adicionar HPA prioritário (reconciliação)")
-			} else {
-				added++
-			}
+				Msg("🚫 HPA ignorado na reconciliação: sem permissão no cluster")
+			denied = append(denied, key)
+			continue
 		}
-	}
 
-	// Remover HPAs que estão no backend mas não no frontend
-	for key := range currentHPAs {
-		if !desiredHPAs[key] {
-			parts := strings.Split(key, "/")
-			if len(parts) == 3 {
-				cluster := parts[0]
-
-				log.Info().
-					Str("cluster", cluster).
-					Str("key", key).
-					Msg("➖ Removendo HPA do monitoramento (reconciliação)")
-
-				// Remove todo o cluster se não há mais HPAs
-				// TODO: Implementar remoção granular de HPA individual
-				h.engine.RemoveTarget(cluster)
-				removed++
-			}
+		log.Info().
+			Str("cluster", clusterName).
+			Str("namespace", hpa.Namespace).
+			Str("hpa", hpa.HPA).
+			Msg("➕ Adicionando HPA ao monitoramento (reconciliação)")
+
+		// Cada HPA monitorado DEVE ter port-forward dedicado + baseline
+		if err := h.engine.AddMonitoredHPA(clusterName, hpa.Namespace, hpa.HPA, auth.User(c)); err != nil {
+			log.Error().
+				Err(err).
+				Str("cluster", clusterName).
+				Str("namespace", hpa.Namespace).
+				Str("hpa", hpa.HPA).
+				Msg("❌ Erro ao adicionar HPA prioritário (reconciliação)")
+		} else {
+			added++
 		}
 	}
 
-	total := len(desiredHPAs)
+	merged, err := h.engine.MonitoredHPAs()
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if merged == nil {
+		merged = []storage.MonitoredHPA{}
+	}
 
 	log.Info().
 		Int("added", added).
-		Int("removed", removed).
-		Int("total", total).
+		Int("denied", len(denied)).
+		Int("total", len(merged)).
 		Msg("✅ Reconciliação concluída")
 
 	c.JSON(200, gin.H{
 		"status":  "success",
 		"added":   added,
-		"removed": removed,
-		"total":   total,
+		"denied":  denied,
+		"removed": 0,
+		"total":   len(merged),
+		"hpas":    merged,
 	})
 }
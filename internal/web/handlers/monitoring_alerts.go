@@ -0,0 +1,412 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s-hpa-manager/internal/monitoring/storage"
+	"k8s-hpa-manager/internal/web/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AckAlertRequest corpo do ack de um alerta
+type AckAlertRequest struct {
+	By string `json:"by"` // Quem está cuidando (default: "web")
+}
+
+// SilenceAlertRequest corpo do silence criado a partir de um alerta (mesmo cluster/namespace/HPA/tipo)
+type SilenceAlertRequest struct {
+	Duration  string `json:"duration" binding:"required"` // Ex: "30m", "2h"
+	Comment   string `json:"comment"`
+	CreatedBy string `json:"created_by"`
+}
+
+// CreateSilenceRequest corpo da criação de silence
+type CreateSilenceRequest struct {
+	Matchers  storage.SilenceMatcher `json:"matchers"`
+	Duration  string                 `json:"duration"`   // Ex: "2h" (alternativa a expires_at)
+	StartsAt  *time.Time             `json:"starts_at"`  // Default: agora
+	ExpiresAt *time.Time             `json:"expires_at"` // RFC3339
+	Comment   string                 `json:"comment"`
+	CreatedBy string                 `json:"created_by"`
+}
+
+// GetAlerts lista a fila de alertas persistidos
+// GET /api/v1/monitoring/alerts?cluster=X&state=active|all|open,acked,silenced,resolved&limit=500
+func (h *MonitoringHandler) GetAlerts(c *gin.Context) {
+	if !h.requireAlertStore(c) || !auth.Require(c, auth.OpRead, c.Query("cluster")) {
+		return
+	}
+
+	limit := 0
+	fmt.Sscanf(c.Query("limit"), "%d", &limit)
+	alerts, err := h.persistence.ListAlerts(storage.AlertFilter{
+		Cluster: c.Query("cluster"),
+		States:  parseAlertStates(c.DefaultQuery("state", "active")),
+		Limit:   limit,
+	})
+	if err != nil {
+		alertStoreError(c, err)
+		return
+	}
+
+	data := make([]gin.H, 0, len(alerts))
+	counts := map[storage.AlertState]int{}
+	for _, alert := range alerts {
+		data = append(data, persistedAlertToAPI(alert))
+		counts[alert.State]++
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    data,
+		"count":   len(data),
+		"states":  counts,
+	})
+}
+
+// AckAlert reconhece um alerta
+// POST /api/v1/monitoring/alerts/:id/ack
+func (h *MonitoringHandler) AckAlert(c *gin.Context) {
+	if !auth.Require(c, auth.OpMonitoringManage, "") {
+		return
+	}
+
+	if !h.requireAlertStore(c) {
+		return
+	}
+
+	var req AckAlertRequest
+	_ = c.ShouldBindJSON(&req) // corpo opcional
+	req.By = actorName(c, req.By, "web")
+
+	alert, err := h.persistence.AckAlert(c.Param("id"), req.By, time.Now())
+	if err != nil {
+		alertStoreError(c, err)
+		return
+	}
+
+	fmt.Printf("✅ Alerta reconhecido por %s: %s %s/%s (%s)\n", req.By, alert.Type, alert.Namespace, alert.HPAName, alert.Cluster)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    persistedAlertToAPI(*alert),
+	})
+}
+
+// UnackAlert remove o reconhecimento de um alerta
+// POST /api/v1/monitoring/alerts/:id/unack
+func (h *MonitoringHandler) UnackAlert(c *gin.Context) {
+	if !auth.Require(c, auth.OpMonitoringManage, "") {
+		return
+	}
+
+	if !h.requireAlertStore(c) {
+		return
+	}
+
+	alert, err := h.persistence.UnackAlert(c.Param("id"))
+	if err != nil {
+		alertStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    persistedAlertToAPI(*alert),
+	})
+}
+
+// SilenceAlert cria um silence para o HPA/tipo do alerta
+// POST /api/v1/monitoring/alerts/:id/silence
+func (h *MonitoringHandler) SilenceAlert(c *gin.Context) {
+	if !h.requireAlertStore(c) {
+		return
+	}
+
+	var req SilenceAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidSilenceRequest(c, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+	duration, err := time.ParseDuration(req.Duration)
+	if err != nil || duration <= 0 {
+		invalidSilenceRequest(c, fmt.Errorf("invalid duration %q", req.Duration))
+		return
+	}
+
+	alert, err := h.persistence.GetAlert(c.Param("id"))
+	if err != nil {
+		alertStoreError(c, err)
+		return
+	}
+	if !auth.Require(c, auth.OpMonitoringManage, alert.Cluster) {
+		return
+	}
+
+	silence, err := h.persistence.CreateSilence(storage.Silence{
+		Matcher: storage.SilenceMatcher{
+			Cluster:   alert.Cluster,
+			Namespace: alert.Namespace,
+			HPA:       alert.HPAName,
+			Type:      alert.Type,
+			Rule:      alert.Rule,
+		},
+		Comment:   req.Comment,
+		CreatedBy: actorName(c, req.CreatedBy, ""),
+		ExpiresAt: time.Now().Add(duration),
+	})
+	if err != nil {
+		invalidSilenceRequest(c, err)
+		return
+	}
+
+	fmt.Printf("🔕 Silence criado até %s: %s %s/%s (%s)\n",
+		silence.ExpiresAt.Local().Format("15:04"), alert.Type, alert.Namespace, alert.HPAName, alert.Cluster)
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    silenceToAPI(*silence),
+	})
+}
+
+// GetSilences lista silences
+// GET /api/v1/monitoring/silences?expired=true
+func (h *MonitoringHandler) GetSilences(c *gin.Context) {
+	if !h.requireAlertStore(c) {
+		return
+	}
+
+	silences, err := h.persistence.ListSilences(c.Query("expired") == "true")
+	if err != nil {
+		alertStoreError(c, err)
+		return
+	}
+
+	data := make([]gin.H, 0, len(silences))
+	for _, silence := range silences {
+		data = append(data, silenceToAPI(silence))
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    data,
+		"count":   len(data),
+	})
+}
+
+// CreateSilence cria um silence com matchers (glob) e expiração
+// POST /api/v1/monitoring/silences
+func (h *MonitoringHandler) CreateSilence(c *gin.Context) {
+	if !h.requireAlertStore(c) {
+		return
+	}
+
+	var req CreateSilenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidSilenceRequest(c, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+
+	// Silence sem cluster (ou com glob) precisa de permissão em todos os clusters cobertos
+	silenceCluster := req.Matchers.Cluster
+	if silenceCluster == "" {
+		silenceCluster = auth.AllClusters
+	}
+	if !auth.Require(c, auth.OpMonitoringManage, silenceCluster) {
+		return
+	}
+
+	silence := storage.Silence{
+		Matcher:   req.Matchers,
+		Comment:   req.Comment,
+		CreatedBy: actorName(c, req.CreatedBy, ""),
+	}
+	if req.StartsAt != nil {
+		silence.StartsAt = *req.StartsAt
+	} else {
+		silence.StartsAt = time.Now()
+	}
+	switch {
+	case req.ExpiresAt != nil:
+		silence.ExpiresAt = *req.ExpiresAt
+	case req.Duration != "":
+		duration, err := time.ParseDuration(req.Duration)
+		if err != nil || duration <= 0 {
+			invalidSilenceRequest(c, fmt.Errorf("invalid duration %q", req.Duration))
+			return
+		}
+		silence.ExpiresAt = silence.StartsAt.Add(duration)
+	default:
+		invalidSilenceRequest(c, fmt.Errorf("duration or expires_at is required"))
+		return
+	}
+
+	created, err := h.persistence.CreateSilence(silence)
+	if err != nil {
+		invalidSilenceRequest(c, err)
+		return
+	}
+
+	fmt.Printf("🔕 Silence criado até %s por %s\n", created.ExpiresAt.Local().Format("02/01 15:04"), created.CreatedBy)
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    silenceToAPI(*created),
+	})
+}
+
+// DeleteSilence expira um silence imediatamente (alertas cobertos voltam para a fila)
+// DELETE /api/v1/monitoring/silences/:id
+func (h *MonitoringHandler) DeleteSilence(c *gin.Context) {
+	if !auth.Require(c, auth.OpMonitoringManage, "") {
+		return
+	}
+
+	if !h.requireAlertStore(c) {
+		return
+	}
+
+	if err := h.persistence.ExpireSilence(c.Param("id")); err != nil {
+		alertStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Silence expired",
+	})
+}
+
+// requireAlertStore responde 503 quando a persistência SQLite não está disponível
+// actorName quem executa a ação: o usuário OIDC autenticado ou, com o token compartilhado,
+// o nome informado pelo cliente
+func actorName(c *gin.Context, informed, fallback string) string {
+	if id := auth.FromContext(c); id != nil && id.Method == auth.MethodOIDC {
+		return id.Name()
+	}
+	if informed != "" {
+		return informed
+	}
+	return fallback
+}
+
+func (h *MonitoringHandler) requireAlertStore(c *gin.Context) bool {
+	if h.persistence != nil {
+		return true
+	}
+	c.JSON(http.StatusServiceUnavailable, gin.H{
+		"success": false,
+		"error": gin.H{
+			"code":    "PERSISTENCE_DISABLED",
+			"message": "Alert storage requires SQLite persistence",
+		},
+	})
+	return false
+}
+
+func alertStoreError(c *gin.Context, err error) {
+	if errors.Is(err, storage.ErrAlertNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "NOT_FOUND",
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"success": false,
+		"error": gin.H{
+			"code":    "ALERT_STORE_ERROR",
+			"message": err.Error(),
+		},
+	})
+}
+
+func invalidSilenceRequest(c *gin.Context, err error) {
+	if errors.Is(err, storage.ErrAlertNotFound) {
+		alertStoreError(c, err)
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"success": false,
+		"error": gin.H{
+			"code":    "INVALID_SILENCE",
+			"message": err.Error(),
+		},
+	})
+}
+
+// parseAlertStates converte o parâmetro state ("active", "all" ou lista separada por vírgula)
+func parseAlertStates(value string) []storage.AlertState {
+	switch value {
+	case "all":
+		return nil
+	case "", "active":
+		return []storage.AlertState{storage.AlertStateOpen, storage.AlertStateAcked, storage.AlertStateSilenced}
+	}
+
+	var states []storage.AlertState
+	for _, state := range strings.Split(value, ",") {
+		states = append(states, storage.AlertState(strings.TrimSpace(state)))
+	}
+	return states
+}
+
+// persistedAlertToAPI converte um alerta persistido para o formato das anomalias (com estado do ciclo de vida)
+func persistedAlertToAPI(alert storage.Alert) gin.H {
+	details := gin.H{"description": alert.Description, "occurrences": alert.Occurrences}
+	if alert.Rule != "" {
+		details["rule"] = alert.Rule
+	}
+
+	end := time.Now()
+	if alert.ResolvedAt != nil {
+		end = *alert.ResolvedAt
+	}
+
+	data := gin.H{
+		"id":               alert.ID,
+		"source":           "watchdog",
+		"cluster":          alert.Cluster,
+		"namespace":        alert.Namespace,
+		"hpa_name":         alert.HPAName,
+		"type":             alert.Type,
+		"severity":         severityToString(alert.Severity),
+		"state":            string(alert.State),
+		"detected_at":      alert.FirstSeen.Format(time.RFC3339),
+		"last_seen":        alert.LastSeen.Format(time.RFC3339),
+		"duration_seconds": int(end.Sub(alert.FirstSeen).Seconds()),
+		"message":          alert.Message,
+		"details":          details,
+		"acked":            alert.AckedAt != nil,
+		"acked_by":         alert.AckedBy,
+		"acked_at":         nil,
+		"silence_id":       alert.SilenceID,
+		"resolved":         !alert.Active(),
+		"resolved_at":      nil,
+		"resolve_reason":   alert.ResolveReason,
+	}
+	if alert.AckedAt != nil {
+		data["acked_at"] = alert.AckedAt.Format(time.RFC3339)
+	}
+	if alert.ResolvedAt != nil {
+		data["resolved_at"] = alert.ResolvedAt.Format(time.RFC3339)
+	}
+	return data
+}
+
+func silenceToAPI(silence storage.Silence) gin.H {
+	return gin.H{
+		"id":         silence.ID,
+		"matchers":   silence.Matcher,
+		"comment":    silence.Comment,
+		"created_by": silence.CreatedBy,
+		"created_at": silence.CreatedAt.Format(time.RFC3339),
+		"starts_at":  silence.StartsAt.Format(time.RFC3339),
+		"expires_at": silence.ExpiresAt.Format(time.RFC3339),
+		"active":     silence.ActiveAt(time.Now().UTC()),
+	}
+}
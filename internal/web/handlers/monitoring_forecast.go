@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/monitoring/analyzer"
+	"k8s-hpa-manager/internal/session"
+	"k8s-hpa-manager/internal/web/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ForecastSessionRequest corpo da geração de sessão de upscale pela previsão
+type ForecastSessionRequest struct {
+	Cluster   string           `json:"cluster" binding:"required"` // Contexto do kubeconfig (ex: aks-prd-admin)
+	Namespace string           `json:"namespace"`
+	HPAs      []string         `json:"hpas"`      // namespace/hpa (vazio = todos com histórico)
+	History   string           `json:"history"`   // Ex: "28d" (default: 28d)
+	Horizon   string           `json:"horizon"`   // Ex: "7d", "48h" (default: 7d)
+	NodePool  *models.NodePool `json:"node_pool"` // Node pool que recebe os pods (nil = só HPAs)
+	PreScale  bool             `json:"pre_scale"` // Eleva também minReplicas/minNodeCount ao pico
+	Name      string           `json:"name"`      // vazio = Forecast_<cluster>_<timestamp>
+}
+
+// GetForecast prevê o pico de réplicas por HPA e, com vm_size, os nodes necessários no node pool.
+// Todos os HPAs filtrados são considerados no mesmo node pool.
+// GET /api/v1/monitoring/forecast?cluster=X&namespace=Y&hpa=Z&history=28d&horizon=7d&vm_size=Standard_D8s_v3&node_count=3&max_nodes=10&autoscaling=true
+func (h *MonitoringHandler) GetForecast(c *gin.Context) {
+	if !h.requireRightSizingStore(c) || !auth.Require(c, auth.OpRead, c.Query("cluster")) {
+		return
+	}
+
+	config, ok := forecastConfig(c, c.Query("history"), c.Query("horizon"))
+	if !ok {
+		return
+	}
+
+	var hpas []string
+	if hpa := c.Query("hpa"); hpa != "" {
+		hpas = []string{c.Query("namespace") + "/" + hpa}
+	}
+	forecasts, ok := h.loadForecasts(c, c.Query("cluster"), c.Query("namespace"), hpas, config)
+	if !ok {
+		return
+	}
+
+	response := gin.H{
+		"success": true,
+		"data":    forecasts,
+		"count":   len(forecasts),
+		"history": config.History.String(),
+		"horizon": config.Horizon.String(),
+	}
+
+	if vmSize := c.Query("vm_size"); vmSize != "" {
+		nodeCount, _ := strconv.Atoi(c.Query("node_count"))
+		maxNodes, _ := strconv.Atoi(c.Query("max_nodes"))
+		pool := models.NodePool{
+			VMSize:             vmSize,
+			NodeCount:          int32(nodeCount),
+			MaxNodeCount:       int32(maxNodes),
+			AutoscalingEnabled: c.Query("autoscaling") == "true",
+		}
+		plan, ok := forecastPlan(c, forecasts, pool, config)
+		if !ok {
+			return
+		}
+		response["node_pool_plan"] = plan
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateForecastSession gera e salva a sessão de upscale para o pico previsto (HPA-Upscale ou Node-Upscale)
+// POST /api/v1/monitoring/forecast/session
+func (h *MonitoringHandler) CreateForecastSession(c *gin.Context) {
+	if !auth.Require(c, auth.OpSessionManage, "") {
+		return
+	}
+
+	if !h.requireRightSizingStore(c) {
+		return
+	}
+
+	var req ForecastSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_REQUEST",
+				"message": fmt.Sprintf("Invalid request: %v", err),
+			},
+		})
+		return
+	}
+	if !auth.Require(c, auth.OpRead, req.Cluster) {
+		return
+	}
+
+	config, ok := forecastConfig(c, req.History, req.Horizon)
+	if !ok {
+		return
+	}
+
+	forecasts, ok := h.loadForecasts(c, req.Cluster, req.Namespace, req.HPAs, config)
+	if !ok {
+		return
+	}
+
+	opts := analyzer.ForecastSessionOptions{
+		Name:           req.Name,
+		ClusterContext: func(string) string { return req.Cluster },
+		PreScale:       req.PreScale,
+		Headroom:       config.Headroom,
+	}
+	if req.NodePool != nil {
+		if req.NodePool.ClusterName == "" {
+			req.NodePool.ClusterName = req.Cluster
+		}
+		plan, ok := forecastPlan(c, forecasts, *req.NodePool, config)
+		if !ok {
+			return
+		}
+		opts.NodePool, opts.Plan = req.NodePool, plan
+	}
+
+	sess, folder, err := analyzer.BuildForecastSession(forecasts, opts)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "NO_CHANGES",
+				"message": err.Error(),
+			},
+			"forecasts": forecasts,
+		})
+		return
+	}
+
+	manager, err := session.NewManager()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "SESSION_MANAGER_ERROR",
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+	if _, err := manager.LoadSessionFromFolder(sess.Name, folder); err == nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "SESSION_EXISTS",
+				"message": fmt.Sprintf("Session %s already exists in folder %s", sess.Name, folder),
+			},
+		})
+		return
+	}
+	if err := manager.SaveSessionToFolder(sess, folder); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "SAVE_FAILED",
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	fmt.Printf("🔮 Sessão de previsão salva: %s/%s (%d HPAs, %d node pools)\n",
+		folder, sess.Name, len(sess.Changes), len(sess.NodePoolChanges))
+	c.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"data":           sess,
+		"folder":         folder,
+		"forecasts":      forecasts,
+		"node_pool_plan": opts.Plan,
+	})
+}
+
+// loadForecasts prevê os HPAs do cluster (contexto com ou sem -admin), filtrando por namespace/hpa
+func (h *MonitoringHandler) loadForecasts(c *gin.Context, cluster, namespace string, hpas []string, config analyzer.ForecastConfig) ([]*analyzer.ReplicaForecast, bool) {
+	forecasts, err := analyzer.ForecastFromPersistence(h.persistence, strings.TrimSuffix(cluster, "-admin"), namespace, config)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "FORECAST_FAILED",
+				"message": err.Error(),
+			},
+		})
+		return nil, false
+	}
+
+	if len(hpas) == 0 {
+		return forecasts, true
+	}
+	selected := make(map[string]bool, len(hpas))
+	for _, hpa := range hpas {
+		selected[hpa] = true
+	}
+	filtered := make([]*analyzer.ReplicaForecast, 0, len(hpas))
+	for _, forecast := range forecasts {
+		if selected[forecast.Namespace+"/"+forecast.HPAName] {
+			filtered = append(filtered, forecast)
+		}
+	}
+	return filtered, true
+}
+
+func forecastPlan(c *gin.Context, forecasts []*analyzer.ReplicaForecast, pool models.NodePool, config analyzer.ForecastConfig) (*analyzer.NodePoolPlan, bool) {
+	plan, err := analyzer.PlanNodePool(forecasts, pool, config)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "UNKNOWN_VM_SIZE",
+				"message": err.Error(),
+			},
+		})
+		return nil, false
+	}
+	return plan, true
+}
+
+// forecastConfig monta a configuração com histórico e horizonte informados ("28d", "48h"; vazio = padrão)
+func forecastConfig(c *gin.Context, history, horizon string) (analyzer.ForecastConfig, bool) {
+	config := analyzer.DefaultForecastConfig()
+	for _, param := range []struct {
+		name  string
+		value string
+		dest  *time.Duration
+	}{
+		{"history", history, &config.History},
+		{"horizon", horizon, &config.Horizon},
+	} {
+		if param.value == "" {
+			continue
+		}
+		dur, err := parseWindow(param.value)
+		if err != nil || dur < config.Step {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "INVALID_WINDOW",
+					"message": fmt.Sprintf("Invalid %s %q (use formats like 28d, 48h)", param.name, param.value),
+				},
+			})
+			return config, false
+		}
+		*param.dest = dur
+	}
+	return config, true
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"k8s-hpa-manager/internal/monitoring/report"
+	"k8s-hpa-manager/internal/monitoring/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListStressTests lista os stress tests salvos (últimos 50)
+// GET /api/v1/monitoring/stress-tests
+func (h *MonitoringHandler) ListStressTests(c *gin.Context) {
+	if !h.requireReportStore(c) {
+		return
+	}
+
+	tests, err := h.persistence.ListStressTests()
+	if err != nil {
+		reportError(c, http.StatusInternalServerError, "LIST_FAILED", err)
+		return
+	}
+	if tests == nil {
+		tests = []map[string]interface{}{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    tests,
+		"count":   len(tests),
+	})
+}
+
+// GetStressTestReport gera o relatório de um stress test concluído
+// GET /api/v1/monitoring/stress-tests/:id/report?format=html|markdown|json|csv&section=hpas&download=true
+func (h *MonitoringHandler) GetStressTestReport(c *gin.Context) {
+	if !h.requireReportStore(c) {
+		return
+	}
+
+	format, err := report.ParseFormat(c.Query("format"))
+	if err != nil {
+		reportError(c, http.StatusBadRequest, "INVALID_FORMAT", err)
+		return
+	}
+	section, err := report.ParseCSVSection(c.Query("section"))
+	if err != nil {
+		reportError(c, http.StatusBadRequest, "INVALID_SECTION", err)
+		return
+	}
+
+	rep, err := report.Load(h.persistence, c.Param("id"))
+	if errors.Is(err, storage.ErrStressTestNotFound) {
+		reportError(c, http.StatusNotFound, "NOT_FOUND", err)
+		return
+	}
+	if err != nil {
+		reportError(c, http.StatusInternalServerError, "REPORT_FAILED", err)
+		return
+	}
+
+	// Renderiza em buffer para responder erro em JSON se o template falhar
+	var buf bytes.Buffer
+	if format == report.FormatCSV {
+		err = report.RenderCSV(&buf, rep, section)
+	} else {
+		err = report.Render(&buf, rep, format)
+	}
+	if err != nil {
+		reportError(c, http.StatusInternalServerError, "RENDER_FAILED", err)
+		return
+	}
+
+	filename := rep.Filename(format)
+	if format == report.FormatCSV {
+		filename = rep.CSVFilename(section)
+	}
+	disposition := "inline"
+	if c.Query("download") == "true" {
+		disposition = "attachment"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, filename))
+	c.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
+
+func (h *MonitoringHandler) requireReportStore(c *gin.Context) bool {
+	if h.persistence != nil {
+		return true
+	}
+	reportError(c, http.StatusServiceUnavailable, "PERSISTENCE_DISABLED",
+		errors.New("stress test reports require SQLite persistence"))
+	return false
+}
+
+func reportError(c *gin.Context, status int, code string, err error) {
+	c.JSON(status, gin.H{
+		"success": false,
+		"error": gin.H{
+			"code":    code,
+			"message": err.Error(),
+		},
+	})
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s-hpa-manager/internal/monitoring/analyzer"
+	"k8s-hpa-manager/internal/session"
+	"k8s-hpa-manager/internal/web/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RightSizingSessionRequest corpo da geração de sessão de right-sizing
+type RightSizingSessionRequest struct {
+	Cluster              string   `json:"cluster" binding:"required"` // Contexto do kubeconfig (ex: aks-prd-admin)
+	Namespace            string   `json:"namespace"`
+	HPAs                 []string `json:"hpas"`   // namespace/hpa (vazio = todos com recomendação)
+	Window               string   `json:"window"` // Ex: "7d", "72h" (default: 7d)
+	Name                 string   `json:"name"`   // vazio = RightSizing_<cluster>_<timestamp>
+	IncludeLowConfidence bool     `json:"include_low_confidence"`
+}
+
+// GetRightSizing calcula recomendações de right-sizing a partir dos snapshots persistidos
+// GET /api/v1/monitoring/recommendations?cluster=X&namespace=Y&hpa=Z&window=7d&changes_only=true
+func (h *MonitoringHandler) GetRightSizing(c *gin.Context) {
+	if !h.requireRightSizingStore(c) || !auth.Require(c, auth.OpRead, c.Query("cluster")) {
+		return
+	}
+
+	config, ok := rightSizingConfig(c, c.Query("window"))
+	if !ok {
+		return
+	}
+
+	recs, err := analyzer.RecommendFromPersistence(h.persistence,
+		strings.TrimSuffix(c.Query("cluster"), "-admin"), c.Query("namespace"), c.Query("hpa"), config)
+	if err != nil {
+		rightSizingError(c, err)
+		return
+	}
+
+	changesOnly := c.Query("changes_only") == "true"
+	data := make([]*analyzer.RightSizing, 0, len(recs))
+	for _, rec := range recs {
+		if changesOnly && !rec.HasChanges() {
+			continue
+		}
+		data = append(data, rec)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    data,
+		"count":   len(data),
+		"window":  config.Window.String(),
+	})
+}
+
+// CreateRightSizingSession gera e salva a sessão com as recomendações (HPA-Upscale ou HPA-Downscale)
+// POST /api/v1/monitoring/recommendations/session
+func (h *MonitoringHandler) CreateRightSizingSession(c *gin.Context) {
+	if !auth.Require(c, auth.OpSessionManage, "") {
+		return
+	}
+
+	if !h.requireRightSizingStore(c) {
+		return
+	}
+
+	var req RightSizingSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_REQUEST",
+				"message": fmt.Sprintf("Invalid request: %v", err),
+			},
+		})
+		return
+	}
+	if !auth.Require(c, auth.OpRead, req.Cluster) {
+		return
+	}
+
+	config, ok := rightSizingConfig(c, req.Window)
+	if !ok {
+		return
+	}
+
+	recs, err := analyzer.RecommendFromPersistence(h.persistence,
+		strings.TrimSuffix(req.Cluster, "-admin"), req.Namespace, "", config)
+	if err != nil {
+		rightSizingError(c, err)
+		return
+	}
+
+	selected := make(map[string]bool, len(req.HPAs))
+	for _, hpa := range req.HPAs {
+		selected[hpa] = true
+	}
+	var included []*analyzer.RightSizing
+	skipped := 0
+	for _, rec := range recs {
+		if len(selected) > 0 && !selected[rec.Namespace+"/"+rec.HPAName] {
+			continue
+		}
+		if rec.Level == analyzer.ConfidenceLow && !req.IncludeLowConfidence {
+			skipped++
+			continue
+		}
+		included = append(included, rec)
+	}
+
+	sess, folder, err := analyzer.BuildRightSizingSession(included, analyzer.RightSizingSessionOptions{
+		Name:           req.Name,
+		ClusterContext: func(string) string { return req.Cluster },
+	})
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "NO_RECOMMENDATIONS",
+				"message": err.Error(),
+			},
+			"skipped_low_confidence": skipped,
+		})
+		return
+	}
+
+	manager, err := session.NewManager()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "SESSION_MANAGER_ERROR",
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+	if _, err := manager.LoadSessionFromFolder(sess.Name, folder); err == nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "SESSION_EXISTS",
+				"message": fmt.Sprintf("Session %s already exists in folder %s", sess.Name, folder),
+			},
+		})
+		return
+	}
+	if err := manager.SaveSessionToFolder(sess, folder); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "SAVE_FAILED",
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	fmt.Printf("📐 Sessão de right-sizing salva: %s/%s (%d HPAs)\n", folder, sess.Name, len(sess.Changes))
+	c.JSON(http.StatusOK, gin.H{
+		"success":                true,
+		"data":                   sess,
+		"folder":                 folder,
+		"recommendations":        included,
+		"skipped_low_confidence": skipped,
+	})
+}
+
+func (h *MonitoringHandler) requireRightSizingStore(c *gin.Context) bool {
+	if h.persistence != nil {
+		return true
+	}
+	c.JSON(http.StatusServiceUnavailable, gin.H{
+		"success": false,
+		"error": gin.H{
+			"code":    "PERSISTENCE_DISABLED",
+			"message": "Right-sizing requires SQLite persistence",
+		},
+	})
+	return false
+}
+
+// rightSizingConfig monta a configuração com a janela informada ("7d", "72h"; vazio = padrão)
+func rightSizingConfig(c *gin.Context, window string) (analyzer.RightSizingConfig, bool) {
+	config := analyzer.DefaultRightSizingConfig()
+	if window == "" {
+		return config, true
+	}
+
+	dur, err := parseWindow(window)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_WINDOW",
+				"message": fmt.Sprintf("Invalid window %q (use formats like 7d, 72h)", window),
+			},
+		})
+		return config, false
+	}
+
+	config.Window = dur
+	return config, true
+}
+
+// parseWindow converte janelas como "7d" ou "72h" em duração positiva
+func parseWindow(window string) (time.Duration, error) {
+	var dur time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(window, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		dur = time.Duration(n) * 24 * time.Hour
+	} else {
+		dur, err = time.ParseDuration(window)
+	}
+	if err == nil && dur <= 0 {
+		err = fmt.Errorf("window must be positive")
+	}
+	return dur, err
+}
+
+func rightSizingError(c *gin.Context, err error) {
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"success": false,
+		"error": gin.H{
+			"code":    "RIGHTSIZING_FAILED",
+			"message": err.Error(),
+		},
+	})
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s-hpa-manager/internal/monitoring/analyzer"
+	"k8s-hpa-manager/internal/web/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateRulesRequest corpo JSON da atualização de regras (alternativa a enviar o YAML direto)
+type UpdateRulesRequest struct {
+	Content string `json:"content"` // YAML completo do arquivo de regras
+}
+
+// GetRules retorna o arquivo de regras de anomalias ativo
+// GET /api/v1/monitoring/rules
+func (h *MonitoringHandler) GetRules(c *gin.Context) {
+	store := h.engine.GetRules()
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rulesToAPI(store, store.RuleSet()),
+	})
+}
+
+// UpdateRules valida e grava o arquivo de regras (Content-Type application/yaml ou JSON {content})
+// PUT /api/v1/monitoring/rules?dry_run=true
+func (h *MonitoringHandler) UpdateRules(c *gin.Context) {
+	if !auth.Require(c, auth.OpAdmin, "") {
+		return
+	}
+
+	content, err := readRulesBody(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_REQUEST",
+				"message": fmt.Sprintf("Invalid request: %v", err),
+			},
+		})
+		return
+	}
+
+	store := h.engine.GetRules()
+	var set *analyzer.RuleSet
+	if c.Query("dry_run") == "true" {
+		set, err = analyzer.ParseRules(content)
+	} else {
+		set, err = store.Save(content)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_RULES",
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	if c.Query("dry_run") == "true" {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"dry_run": true,
+			"data":    gin.H{"rules": set.File},
+		})
+		return
+	}
+
+	fmt.Printf("📐 Regras de anomalias atualizadas: %d overrides, %d regras customizadas\n",
+		len(set.File.Overrides), len(set.File.Rules))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rulesToAPI(store, set),
+	})
+}
+
+// ReloadRules relê o arquivo de regras do disco (edição manual)
+// POST /api/v1/monitoring/rules/reload
+func (h *MonitoringHandler) ReloadRules(c *gin.Context) {
+	if !auth.Require(c, auth.OpAdmin, "") {
+		return
+	}
+
+	store := h.engine.GetRules()
+	reloaded, err := store.Reload()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_RULES",
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"reloaded": reloaded,
+		"data":     rulesToAPI(store, store.RuleSet()),
+	})
+}
+
+func readRulesBody(c *gin.Context) ([]byte, error) {
+	contentType := c.ContentType()
+	if strings.Contains(contentType, "yaml") || contentType == "text/plain" {
+		return io.ReadAll(c.Request.Body)
+	}
+
+	var req UpdateRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+	return []byte(req.Content), nil
+}
+
+func rulesToAPI(store *analyzer.RuleStore, set *analyzer.RuleSet) gin.H {
+	data := gin.H{
+		"path":    store.Path(),
+		"content": store.Raw(),
+		"rules":   set.File,
+		"fields":  analyzer.RuleFields(),
+		"error":   nil,
+	}
+	if loadedAt := store.LoadedAt(); !loadedAt.IsZero() {
+		data["loaded_at"] = loadedAt.Format(time.RFC3339)
+	}
+	if err := store.LastError(); err != nil {
+		data["error"] = err.Error()
+	}
+	return data
+}
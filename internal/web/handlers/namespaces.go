@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"k8s-hpa-manager/internal/config"
 	kubeclient "k8s-hpa-manager/internal/kubernetes"
+	"k8s-hpa-manager/internal/web/auth"
 )
 
 // NamespaceHandler gerencia requisições relacionadas a namespaces
@@ -33,6 +34,9 @@ func (h *NamespaceHandler) List(c *gin.Context) {
 		})
 		return
 	}
+	if !auth.Require(c, auth.OpRead, cluster) {
+		return
+	}
 
 	// Obter client do cluster (reutilizar código existente)
 	client, err := h.kubeManager.GetClient(cluster)
@@ -1,13 +1,14 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
-	"os/exec"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"k8s-hpa-manager/internal/web/validators"
+	"k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/nodepool"
+	"k8s-hpa-manager/internal/web/auth"
 )
 
 // NodePoolSequentialRequest representa a requisição de execução sequencial
@@ -53,6 +54,10 @@ func (h *NodePoolHandler) ApplySequential(c *gin.Context) {
 		return
 	}
 
+	if !auth.Require(c, auth.OpNodePoolScale, req.Cluster) {
+		return
+	}
+
 	// Buscar configuração do cluster
 	clusterConfig, err := findClusterInConfig(req.Cluster)
 	if err != nil {
@@ -66,8 +71,8 @@ func (h *NodePoolHandler) ApplySequential(c *gin.Context) {
 		return
 	}
 
-	// Validar Azure AD
-	if err := validators.ValidateAzureAuth(); err != nil {
+	// Validar Azure AD para clusters AKS
+	if err := validateProviderAuth(clusterConfig); err != nil {
 		c.JSON(401, gin.H{
 			"success": false,
 			"error": gin.H{
@@ -78,21 +83,9 @@ func (h *NodePoolHandler) ApplySequential(c *gin.Context) {
 		return
 	}
 
-	// Configurar subscription
-	if err := setAzureSubscription(clusterConfig.Subscription); err != nil {
-		c.JSON(500, gin.H{
-			"success": false,
-			"error": gin.H{
-				"code":    "AZURE_SUBSCRIPTION_ERROR",
-				"message": fmt.Sprintf("Failed to set subscription: %v", err),
-			},
-		})
-		return
-	}
-
 	// Executar operações sequencialmente
 	results := make([]gin.H, 0)
-	clusterNameForAzure := strings.TrimSuffix(clusterConfig.ClusterName, "-admin")
+	clusterRef := nodepool.ClusterRefFromConfig(clusterConfig)
 
 	for i, poolOp := range req.NodePools {
 		stepNum := i + 1
@@ -106,11 +99,7 @@ func (h *NodePoolHandler) ApplySequential(c *gin.Context) {
 		fmt.Printf("\n🔄 [Step %d/%d] Aplicando node pool '%s' (*%d)...\n", stepNum, len(req.NodePools), poolOp.Name, poolOp.Order)
 
 		// Aplicar alterações no node pool
-		err := applyNodePoolChanges(
-			clusterNameForAzure,
-			clusterConfig.ResourceGroup,
-			poolOp,
-		)
+		err := h.applyNodePoolOperation(c.Request.Context(), clusterRef, poolOp)
 
 		if err != nil {
 			result["success"] = false
@@ -155,70 +144,14 @@ func (h *NodePoolHandler) ApplySequential(c *gin.Context) {
 	})
 }
 
-// applyNodePoolChanges aplica alterações em um node pool via Azure CLI
-func applyNodePoolChanges(clusterName, resourceGroup string, op NodePoolOperation) error {
-	// Construir comandos baseado nas mudanças
-	commands := make([][]string, 0)
-
-	// Cenário 1: Desabilitar autoscaling e fazer scale (ex: *1 - scale down para 0)
-	if !op.AutoscalingEnabled {
-		// Comando 1: Desabilitar autoscaling
-		commands = append(commands, []string{
-			"az", "aks", "nodepool", "update",
-			"--resource-group", resourceGroup,
-			"--cluster-name", clusterName,
-			"--name", op.Name,
-			"--disable-cluster-autoscaler",
-		})
-
-		// Comando 2: Fazer scale para node count especificado
-		commands = append(commands, []string{
-			"az", "aks", "nodepool", "scale",
-			"--resource-group", resourceGroup,
-			"--cluster-name", clusterName,
-			"--name", op.Name,
-			"--node-count", fmt.Sprintf("%d", op.NodeCount),
-		})
-	} else {
-		// Cenário 2: Habilitar autoscaling com min/max (ex: *2 - scale up com autoscaling)
-		commands = append(commands, []string{
-			"az", "aks", "nodepool", "update",
-			"--resource-group", resourceGroup,
-			"--cluster-name", clusterName,
-			"--name", op.Name,
-			"--enable-cluster-autoscaler",
-			"--min-count", fmt.Sprintf("%d", op.MinNodeCount),
-			"--max-count", fmt.Sprintf("%d", op.MaxNodeCount),
-		})
-	}
-
-	// Executar comandos sequencialmente
-	for cmdIdx, cmdArgs := range commands {
-		fmt.Printf("   🔧 Executando comando %d/%d: %s\n", cmdIdx+1, len(commands), strings.Join(cmdArgs, " "))
-
-		cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
-		output, err := cmd.CombinedOutput()
-
-		if err != nil {
-			return fmt.Errorf("comando falhou: %s - output: %s", err, string(output))
-		}
-
-		fmt.Printf("   ✅ Comando %d/%d executado com sucesso\n", cmdIdx+1, len(commands))
-
-		// Pequeno delay entre comandos
-		if cmdIdx < len(commands)-1 {
-			time.Sleep(2 * time.Second)
-		}
+// applyNodePoolOperation aplica uma operação sequencial a partir do estado atual do node pool
+func (h *NodePoolHandler) applyNodePoolOperation(ctx context.Context, clusterRef nodepool.ClusterRef, op NodePoolOperation) error {
+	target := models.NodePoolValues{
+		AutoscalingEnabled: op.AutoscalingEnabled,
+		NodeCount:          op.NodeCount,
+		MinNodeCount:       op.MinNodeCount,
+		MaxNodeCount:       op.MaxNodeCount,
 	}
 
-	return nil
-}
-
-// setAzureSubscription configura a subscription do Azure
-func setAzureSubscription(subscription string) error {
-	cmd := exec.Command("az", "account", "set", "--subscription", subscription)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to set subscription: %w", err)
-	}
-	return nil
+	return nodepool.ApplyFromLive(ctx, h.provider, clusterRef, op.Name, target, logNodePoolStep)
 }
@@ -6,16 +6,20 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"k8s-hpa-manager/internal/audit"
+	"k8s-hpa-manager/internal/azure"
 	"k8s-hpa-manager/internal/config"
 	"k8s-hpa-manager/internal/kubernetes"
 	"k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/nodepool"
+	"k8s-hpa-manager/internal/web/auth"
+	"k8s-hpa-manager/internal/web/events"
 	"k8s-hpa-manager/internal/web/validators"
 )
 
@@ -23,6 +27,8 @@ import (
 type NodePoolHandler struct {
 	kubeManager     *config.KubeConfigManager
 	progressManager *SequenceProgressManager
+	provider        nodepool.NodePoolProvider
+	eventHub        *events.Hub // stream de eventos (nil = só o SSE da sessão)
 }
 
 // NewNodePoolHandler cria um novo handler de Node Pools
@@ -30,9 +36,15 @@ func NewNodePoolHandler(km *config.KubeConfigManager) *NodePoolHandler {
 	return &NodePoolHandler{
 		kubeManager:     km,
 		progressManager: NewSequenceProgressManager(),
+		provider:        nodepool.NewDefaultRegistry(azure.NewAuthManager()),
 	}
 }
 
+// SetEventHub publica também o progresso do cordon/drain em GET /api/v1/events
+func (h *NodePoolHandler) SetEventHub(hub *events.Hub) {
+	h.eventHub = hub
+}
+
 // ProgressEvent representa um evento de progresso
 type ProgressEvent struct {
 	Phase      int     `json:"phase"`       // 1-5
@@ -124,6 +136,9 @@ func (h *NodePoolHandler) List(c *gin.Context) {
 		})
 		return
 	}
+	if !auth.Require(c, auth.OpRead, cluster) {
+		return
+	}
 
 	// Buscar configuração do cluster no clusters-config.json
 	clusterConfig, err := findClusterInConfig(cluster)
@@ -138,8 +153,8 @@ func (h *NodePoolHandler) List(c *gin.Context) {
 		return
 	}
 
-	// Validar Azure AD (faz login automático se necessário, igual ao TUI)
-	if err := validators.ValidateAzureAuth(); err != nil {
+	// Validar Azure AD para clusters AKS (faz login automático se necessário, igual ao TUI)
+	if err := validateProviderAuth(clusterConfig); err != nil {
 		c.JSON(401, gin.H{
 			"success": false,
 			"error": gin.H{
@@ -150,29 +165,14 @@ func (h *NodePoolHandler) List(c *gin.Context) {
 		return
 	}
 
-	// Configurar subscription
-	cmd := exec.Command("az", "account", "set", "--subscription", clusterConfig.Subscription)
-	if err := cmd.Run(); err != nil {
-		c.JSON(500, gin.H{
-			"success": false,
-			"error": gin.H{
-				"code":    "AZURE_SUBSCRIPTION_ERROR",
-				"message": fmt.Sprintf("Failed to set subscription: %v", err),
-			},
-		})
-		return
-	}
-
-	// Normalizar nome do cluster (remover -admin se existir)
-	clusterNameForAzure := strings.TrimSuffix(clusterConfig.ClusterName, "-admin")
-
-	// Listar node pools via Azure CLI
-	nodePools, err := loadNodePoolsFromAzure(clusterNameForAzure, clusterConfig.ResourceGroup)
+	// Listar node pools via API do provedor
+	nodePools, err := h.provider.List(c.Request.Context(), nodepool.ClusterRefFromConfig(clusterConfig))
 	if err != nil {
-		c.JSON(500, gin.H{
+		status, code := nodePoolErrorStatus(err)
+		c.JSON(status, gin.H{
 			"success": false,
 			"error": gin.H{
-				"code":    "AZURE_CLI_ERROR",
+				"code":    code,
 				"message": fmt.Sprintf("Failed to load node pools: %v", err),
 			},
 		})
@@ -186,7 +186,7 @@ func (h *NodePoolHandler) List(c *gin.Context) {
 	})
 }
 
-// Update atualiza um node pool específico via Azure CLI
+// Update atualiza um node pool específico via API do provedor
 func (h *NodePoolHandler) Update(c *gin.Context) {
 	cluster := c.Param("cluster")
 	resourceGroup := c.Param("resource_group")
@@ -203,6 +203,10 @@ func (h *NodePoolHandler) Update(c *gin.Context) {
 		return
 	}
 
+	if !auth.Require(c, auth.OpNodePoolScale, cluster) {
+		return
+	}
+
 	// Parse do body
 	var req NodePoolUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -216,6 +220,11 @@ func (h *NodePoolHandler) Update(c *gin.Context) {
 		return
 	}
 
+	// Cordon/drain removem pods dos nós: exigem permissão própria além do scale
+	if cfg := req.CordonDrainConfig; cfg != nil && (cfg.CordonEnabled || cfg.DrainEnabled) && !auth.Require(c, auth.OpDrain, cluster) {
+		return
+	}
+
 	// Buscar configuração do cluster no clusters-config.json
 	clusterConfig, err := findClusterInConfig(cluster)
 	if err != nil {
@@ -229,8 +238,8 @@ func (h *NodePoolHandler) Update(c *gin.Context) {
 		return
 	}
 
-	// Validar Azure AD
-	if err := validators.ValidateAzureAuth(); err != nil {
+	// Validar Azure AD para clusters AKS
+	if err := validateProviderAuth(clusterConfig); err != nil {
 		c.JSON(401, gin.H{
 			"success": false,
 			"error": gin.H{
@@ -241,39 +250,39 @@ func (h *NodePoolHandler) Update(c *gin.Context) {
 		return
 	}
 
-	// Configurar subscription
-	cmd := exec.Command("az", "account", "set", "--subscription", clusterConfig.Subscription)
-	if err := cmd.Run(); err != nil {
-		c.JSON(500, gin.H{
+	// Resource group da rota tem precedência sobre o clusters-config.json (apenas AKS;
+	// em EKS/GKE o segmento contém a região e é ignorado)
+	clusterRef := nodepool.ClusterRefFromConfig(clusterConfig)
+	if clusterRef.Provider == nodepool.ProviderAKS {
+		clusterRef.ResourceGroup = resourceGroup
+	}
+
+	// Estado atual do node pool (campos omitidos no request mantêm o valor atual)
+	currentPool, err := h.provider.Get(c.Request.Context(), clusterRef, nodePoolName)
+	if err != nil {
+		status, code := nodePoolErrorStatus(err)
+		c.JSON(status, gin.H{
 			"success": false,
 			"error": gin.H{
-				"code":    "AZURE_SUBSCRIPTION_ERROR",
-				"message": fmt.Sprintf("Failed to set subscription: %v", err),
+				"code":    code,
+				"message": fmt.Sprintf("Failed to get node pool: %v", err),
 			},
 		})
 		return
 	}
 
-	// Normalizar nome do cluster
-	clusterNameForAzure := strings.TrimSuffix(clusterConfig.ClusterName, "-admin")
-
-	// Converter request para NodePoolOperation
-	op := NodePoolOperation{
-		Name:               nodePoolName,
-		AutoscalingEnabled: req.AutoscalingEnabled != nil && *req.AutoscalingEnabled,
-		NodeCount:          0,
-		MinNodeCount:       0,
-		MaxNodeCount:       0,
+	target := nodepool.ValuesOf(currentPool)
+	if req.AutoscalingEnabled != nil {
+		target.AutoscalingEnabled = *req.AutoscalingEnabled
 	}
-
 	if req.NodeCount != nil {
-		op.NodeCount = *req.NodeCount
+		target.NodeCount = *req.NodeCount
 	}
 	if req.MinNodeCount != nil {
-		op.MinNodeCount = *req.MinNodeCount
+		target.MinNodeCount = *req.MinNodeCount
 	}
 	if req.MaxNodeCount != nil {
-		op.MaxNodeCount = *req.MaxNodeCount
+		target.MaxNodeCount = *req.MaxNodeCount
 	}
 
 	// Se configuração de Cordon/Drain foi fornecida, executar ANTES de aplicar mudanças
@@ -377,12 +386,23 @@ func (h *NodePoolHandler) Update(c *gin.Context) {
 		}
 	}
 
-	// Aplicar mudanças via Azure CLI (reutiliza função de sequential)
-	if err := applyNodePoolChanges(clusterNameForAzure, resourceGroup, op); err != nil {
-		c.JSON(500, gin.H{
+	// Aplicar mudanças via API do provedor
+	steps := nodepool.Plan(nodepool.ValuesOf(currentPool), target)
+	err = nodepool.Execute(c.Request.Context(), h.provider, clusterRef, nodePoolName, steps, logNodePoolStep)
+	auditRecorder(c).Record(audit.Event{
+		Action:   audit.ActionApplyNodePool,
+		Cluster:  cluster,
+		Resource: fmt.Sprintf("%s/%s", resourceGroup, nodePoolName),
+		Before:   nodepool.ValuesOf(currentPool),
+		After:    target,
+		Err:      err,
+	})
+	if err != nil {
+		status, code := nodePoolErrorStatus(err)
+		c.JSON(status, gin.H{
 			"success": false,
 			"error": gin.H{
-				"code":    "AZURE_OPERATION_FAILED",
+				"code":    code,
 				"message": fmt.Sprintf("Failed to update node pool: %v", err),
 			},
 		})
@@ -390,7 +410,7 @@ func (h *NodePoolHandler) Update(c *gin.Context) {
 	}
 
 	// Recarregar node pools para retornar o estado atualizado
-	nodePools, err := loadNodePoolsFromAzure(clusterNameForAzure, clusterConfig.ResourceGroup)
+	nodePools, err := h.provider.List(c.Request.Context(), clusterRef)
 	if err != nil {
 		c.JSON(500, gin.H{
 			"success": false,
@@ -507,80 +527,34 @@ func loadClusterConfig() ([]models.ClusterConfig, error) {
 	return clusters, nil
 }
 
-// loadNodePoolsFromAzure carrega node pools via Azure CLI
-func loadNodePoolsFromAzure(clusterName, resourceGroup string) ([]models.NodePool, error) {
-	// Executar comando Azure CLI
-	cmd := exec.Command("az", "aks", "nodepool", "list",
-		"--resource-group", resourceGroup,
-		"--cluster-name", clusterName,
-		"--output", "json")
-
-	output, err := cmd.Output()
-	if err != nil {
-		// Capturar stderr para melhor debugging
-		if exitError, ok := err.(*exec.ExitError); ok {
-			stderr := string(exitError.Stderr)
-
-			// Detectar erros de autenticação Azure AD
-			if strings.Contains(stderr, "AADSTS") ||
-				strings.Contains(stderr, "expired") ||
-				strings.Contains(stderr, "authentication") ||
-				strings.Contains(stderr, "az login") {
-				return nil, fmt.Errorf("Azure CLI not authenticated. Please run on server: az login")
-			}
-
-			return nil, fmt.Errorf("az command failed: %s", stderr)
-		}
-		return nil, fmt.Errorf("failed to execute az command: %w", err)
-	}
-
-	// Parse do JSON
-	var azureNodePools []AzureNodePool
-	if err := json.Unmarshal(output, &azureNodePools); err != nil {
-		return nil, fmt.Errorf("failed to parse Azure CLI output: %w", err)
+// nodePoolErrorStatus converte erros do provedor em status HTTP e código de erro da API
+func nodePoolErrorStatus(err error) (int, string) {
+	switch {
+	case nodepool.IsConfigError(err):
+		return 500, "PROVIDER_NOT_CONFIGURED"
+	case nodepool.IsAuthError(err):
+		return 401, "PROVIDER_AUTH_FAILED"
+	case nodepool.IsNotFound(err):
+		return 404, "NODE_POOL_NOT_FOUND"
+	case nodepool.IsConflict(err):
+		return 409, "NODE_POOL_BUSY"
+	default:
+		return 500, "PROVIDER_API_ERROR"
 	}
+}
 
-	// Converter para nosso modelo
-	var nodePools []models.NodePool
-	for _, azPool := range azureNodePools {
-		// Converter pointers para valores diretos
-		var minCount, maxCount int32
-		if azPool.MinCount != nil {
-			minCount = *azPool.MinCount
-		}
-		if azPool.MaxCount != nil {
-			maxCount = *azPool.MaxCount
-		}
-
-		nodePool := models.NodePool{
-			Name:               azPool.Name,
-			VMSize:             azPool.VmSize,
-			NodeCount:          azPool.Count,
-			MinNodeCount:       minCount,
-			MaxNodeCount:       maxCount,
-			AutoscalingEnabled: azPool.EnableAutoScaling,
-			Status:             azPool.ProvisioningState,
-			IsSystemPool:       azPool.Mode == "System",
-			ClusterName:        clusterName,
-			ResourceGroup:      resourceGroup,
-		}
-
-		nodePools = append(nodePools, nodePool)
+// validateProviderAuth valida a autenticação Azure CLI apenas para clusters AKS.
+// EKS e GKE usam as credenciais do aws/gcloud CLI e falhas aparecem como PROVIDER_AUTH_FAILED.
+func validateProviderAuth(clusterConfig *models.ClusterConfig) error {
+	if nodepool.ProviderName(clusterConfig) != nodepool.ProviderAKS {
+		return nil
 	}
-
-	return nodePools, nil
+	return validators.ValidateAzureAuth()
 }
 
-// AzureNodePool representa a estrutura retornada pela Azure CLI
-type AzureNodePool struct {
-	Name              string `json:"name"`
-	VmSize            string `json:"vmSize"`
-	Count             int32  `json:"count"`
-	MinCount          *int32 `json:"minCount"`          // Pointer pois pode ser null
-	MaxCount          *int32 `json:"maxCount"`          // Pointer pois pode ser null
-	EnableAutoScaling bool   `json:"enableAutoScaling"` // Campo correto do Azure
-	Mode              string `json:"mode"`              // "System" ou "User"
-	ProvisioningState string `json:"provisioningState"`
+// logNodePoolStep loga no console cada passo aplicado em um node pool
+func logNodePoolStep(step, total int, description string) {
+	fmt.Printf("   🔧 Executando passo %d/%d: %s\n", step, total, description)
 }
 
 // SequenceExecuteRequest representa a requisição para executar sequenciamento com cordon/drain
@@ -658,6 +632,14 @@ func (h *NodePoolHandler) ExecuteSequence(c *gin.Context) {
 		return
 	}
 
+	// Cordon/drain removem pods dos nós: exigem permissão própria além do scale
+	if !auth.Require(c, auth.OpNodePoolScale, req.Cluster) {
+		return
+	}
+	if (req.CordonEnabled || req.DrainEnabled) && !auth.Require(c, auth.OpDrain, req.Cluster) {
+		return
+	}
+
 	// Ordenar node pools por sequence_order
 	origin := req.NodePools[0]
 	dest := req.NodePools[1]
@@ -665,6 +647,20 @@ func (h *NodePoolHandler) ExecuteSequence(c *gin.Context) {
 		origin, dest = dest, origin
 	}
 
+	// Buscar configuração do cluster (nome real no provedor, resource group e subscription)
+	clusterConfig, err := findClusterInConfig(req.Cluster)
+	if err != nil {
+		c.JSON(404, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "CLUSTER_NOT_FOUND",
+				"message": fmt.Sprintf("Cluster not found in clusters-config.json: %v", err),
+			},
+		})
+		return
+	}
+	clusterRef := nodepool.ClusterRefFromConfig(clusterConfig)
+
 	// Obter cliente Kubernetes
 	client, err := h.kubeManager.GetClient(req.Cluster)
 	if err != nil {
@@ -685,7 +681,7 @@ func (h *NodePoolHandler) ExecuteSequence(c *gin.Context) {
 	progressCh := h.progressManager.CreateSession(sessionID)
 
 	// Executar sequenciamento em goroutine para não bloquear
-	go h.executeSequenceAsync(client, origin, dest, req, sessionID, progressCh)
+	go h.executeSequenceAsync(client, clusterRef, origin, dest, req, sessionID, progressCh)
 
 	// Retornar sucesso imediato (operação assíncrona)
 	c.JSON(202, gin.H{
@@ -781,7 +777,7 @@ func (h *NodePoolHandler) SequenceProgress(c *gin.Context) {
 }
 
 // executeSequenceAsync executa o sequenciamento de forma assíncrona
-func (h *NodePoolHandler) executeSequenceAsync(client interface{}, origin, dest NodePoolSequenceConfig, req SequenceExecuteRequest, sessionID string, progressCh chan ProgressEvent) {
+func (h *NodePoolHandler) executeSequenceAsync(client interface{}, clusterRef nodepool.ClusterRef, origin, dest NodePoolSequenceConfig, req SequenceExecuteRequest, sessionID string, progressCh chan ProgressEvent) {
 	ctx := context.Background()
 	startTime := time.Now()
 
@@ -804,6 +800,7 @@ func (h *NodePoolHandler) executeSequenceAsync(client interface{}, origin, dest
 		if err != nil {
 			event.Error = err.Error()
 		}
+		h.eventHub.PublishProgress(events.OperationNodePoolSequence, sessionID, req.Cluster, event)
 		progressCh <- event
 	}
 
@@ -833,8 +830,8 @@ func (h *NodePoolHandler) executeSequenceAsync(client interface{}, origin, dest
 			dest.PreDrainChanges.MinNodes,
 			dest.PreDrainChanges.MaxNodes)
 
-		// Aplicar mudanças via Azure CLI
-		if err := h.applyNodePoolChanges(dest.Name, dest.ResourceGroup, dest.Subscription, dest.PreDrainChanges); err != nil {
+		// Aplicar mudanças via API do provedor
+		if err := h.applyNodePoolChanges(ctx, clusterRef, dest, dest.PreDrainChanges); err != nil {
 			sendProgress(1, "PRE-DRAIN", "error", "Failed to apply PRE-DRAIN changes", 0, "", 0, 0, err)
 			fmt.Printf("❌ ERROR: Failed to apply PRE-DRAIN changes: %v\n", err)
 			return
@@ -990,8 +987,8 @@ func (h *NodePoolHandler) executeSequenceAsync(client interface{}, origin, dest
 			origin.PostDrainChanges.Autoscaling,
 			origin.PostDrainChanges.NodeCount)
 
-		// Aplicar mudanças via Azure CLI
-		if err := h.applyNodePoolChanges(origin.Name, origin.ResourceGroup, origin.Subscription, origin.PostDrainChanges); err != nil {
+		// Aplicar mudanças via API do provedor
+		if err := h.applyNodePoolChanges(ctx, clusterRef, origin, origin.PostDrainChanges); err != nil {
 			sendProgress(4, "POST-DRAIN", "error", "Failed to apply POST-DRAIN changes", 0, "", 0, 0, err)
 			fmt.Printf("❌ ERROR: Failed to apply POST-DRAIN changes: %v\n", err)
 			return
@@ -1020,60 +1017,24 @@ func (h *NodePoolHandler) executeSequenceAsync(client interface{}, origin, dest
 	fmt.Printf("\n")
 }
 
-// applyNodePoolChanges aplica mudanças em um node pool via Azure CLI
-func (h *NodePoolHandler) applyNodePoolChanges(poolName, resourceGroup, subscription string, changes *models.NodePoolChanges) error {
-	// Configurar subscription (se necessário)
-	if subscription != "" {
-		cmd := exec.Command("az", "account", "set", "--subscription", subscription)
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("failed to set subscription: %w", err)
-		}
+// applyNodePoolChanges aplica mudanças em um node pool via API do provedor
+func (h *NodePoolHandler) applyNodePoolChanges(ctx context.Context, clusterRef nodepool.ClusterRef, pool NodePoolSequenceConfig, changes *models.NodePoolChanges) error {
+	// Resource group e subscription do node pool têm precedência sobre o clusters-config.json
+	if pool.ResourceGroup != "" {
+		clusterRef.ResourceGroup = pool.ResourceGroup
 	}
-
-	// Construir comando baseado nas mudanças
-	var commands [][]string
-
-	if changes.Autoscaling {
-		// Habilitar autoscaling com min/max
-		commands = append(commands, []string{
-			"az", "aks", "nodepool", "update",
-			"--resource-group", resourceGroup,
-			"--cluster-name", poolName, // TODO: Obter cluster name correto
-			"--name", poolName,
-			"--enable-cluster-autoscaler",
-			"--min-count", fmt.Sprintf("%d", changes.MinNodes),
-			"--max-count", fmt.Sprintf("%d", changes.MaxNodes),
-		})
-	} else {
-		// Desabilitar autoscaling
-		commands = append(commands, []string{
-			"az", "aks", "nodepool", "update",
-			"--resource-group", resourceGroup,
-			"--cluster-name", poolName, // TODO: Obter cluster name correto
-			"--name", poolName,
-			"--disable-cluster-autoscaler",
-		})
-
-		// Scale para node count específico
-		commands = append(commands, []string{
-			"az", "aks", "nodepool", "scale",
-			"--resource-group", resourceGroup,
-			"--cluster-name", poolName, // TODO: Obter cluster name correto
-			"--name", poolName,
-			"--node-count", fmt.Sprintf("%d", changes.NodeCount),
-		})
+	if pool.Subscription != "" {
+		clusterRef.Subscription = pool.Subscription
 	}
 
-	// Executar comandos sequencialmente
-	for _, cmd := range commands {
-		execCmd := exec.Command(cmd[0], cmd[1:]...)
-		output, err := execCmd.CombinedOutput()
-		if err != nil {
-			return fmt.Errorf("command failed: %s\nOutput: %s", err, string(output))
-		}
+	target := models.NodePoolValues{
+		AutoscalingEnabled: changes.Autoscaling,
+		NodeCount:          changes.NodeCount,
+		MinNodeCount:       changes.MinNodes,
+		MaxNodeCount:       changes.MaxNodes,
 	}
 
-	return nil
+	return nodepool.ApplyFromLive(ctx, h.provider, clusterRef, pool.Name, target, logNodePoolStep)
 }
 
 // validateDrainOptions valida as opções de drain (placeholder)
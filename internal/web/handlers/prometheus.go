@@ -8,6 +8,7 @@ import (
 
 	"k8s-hpa-manager/internal/config"
 	"k8s-hpa-manager/internal/kubernetes"
+	"k8s-hpa-manager/internal/web/auth"
 
 	"github.com/gin-gonic/gin"
 	appsv1 "k8s.io/api/apps/v1"
@@ -56,6 +57,9 @@ func (h *PrometheusHandler) List(c *gin.Context) {
 		})
 		return
 	}
+	if !auth.Require(c, auth.OpRead, cluster) {
+		return
+	}
 
 	// Obter client do cluster
 	client, err := h.kubeManager.GetClient(cluster)
@@ -148,6 +152,10 @@ func (h *PrometheusHandler) Update(c *gin.Context) {
 	name := c.Param("name")
 	resourceType := c.Param("type") // deployment, statefulset, daemonset
 
+	if !auth.Require(c, auth.OpWorkloadEdit, cluster) {
+		return
+	}
+
 	var req struct {
 		CPURequest    string `json:"cpu_request"`
 		MemoryRequest string `json:"memory_request"`
@@ -519,6 +527,10 @@ func (h *PrometheusHandler) Rollout(c *gin.Context) {
 		return
 	}
 
+	if !auth.Require(c, auth.OpWorkloadEdit, cluster) {
+		return
+	}
+
 	// Obter client K8s padrão
 	clientSet, err := h.kubeManager.GetClient(cluster)
 	if err != nil {
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/scheduler"
+	"k8s-hpa-manager/internal/session"
+	"k8s-hpa-manager/internal/web/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SchedulesHandler gerencia os agendamentos de aplicação de sessões
+type SchedulesHandler struct {
+	scheduler *scheduler.Scheduler
+	authorize SessionAuthorizer // nil = sem verificação dos itens da sessão
+}
+
+// SessionAuthorizer verifica se o usuário da requisição pode aplicar a sessão salva,
+// respondendo o erro quando não pode
+type SessionAuthorizer func(c *gin.Context, name, folder string) bool
+
+// NewSchedulesHandler cria um novo handler de agendamentos
+func NewSchedulesHandler(s *scheduler.Scheduler) *SchedulesHandler {
+	return &SchedulesHandler{scheduler: s}
+}
+
+// SetSessionAuthorizer define a verificação das sessões agendadas: quem agenda precisa
+// poder aplicar a sessão, já que o scheduler a executa sem usuário
+func (h *SchedulesHandler) SetSessionAuthorizer(authorize SessionAuthorizer) {
+	h.authorize = authorize
+}
+
+// ScheduleAuthorizer reautoriza as sessões agendadas a cada execução com os bindings
+// vigentes do owner (implementa scheduler.Authorizer)
+type ScheduleAuthorizer struct {
+	auth *auth.Authenticator
+}
+
+// NewScheduleAuthorizer cria o autorizador do scheduler
+func NewScheduleAuthorizer(a *auth.Authenticator) *ScheduleAuthorizer {
+	return &ScheduleAuthorizer{auth: a}
+}
+
+// AuthorizeSession verifica os itens da sessão contra as permissões atuais do owner
+func (a *ScheduleAuthorizer) AuthorizeSession(owner scheduler.Owner, sess *models.Session) error {
+	return authorizeScheduledSession(sess, a.auth.IdentityFor(owner.Method, owner.Username, owner.Groups))
+}
+
+// ScheduleRequest corpo de criação/atualização de um agendamento
+type ScheduleRequest struct {
+	Name               string     `json:"name"`
+	SessionName        string     `json:"session_name" binding:"required"`
+	Folder             string     `json:"folder"`
+	RunAt              *time.Time `json:"run_at"`
+	Cron               string     `json:"cron"`
+	Timezone           string     `json:"timezone"`
+	RollbackAt         *time.Time `json:"rollback_at"`
+	RollbackAfter      string     `json:"rollback_after"`
+	RollbackOnFailure  bool       `json:"rollback_on_failure"`
+	ConflictResolution string     `json:"conflict_resolution"` // skip, force ou rebase
+	Enabled            *bool      `json:"enabled"`             // padrão: true
+}
+
+func (r ScheduleRequest) toSchedule() scheduler.Schedule {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+	return scheduler.Schedule{
+		Name:               r.Name,
+		SessionName:        r.SessionName,
+		Folder:             r.Folder,
+		RunAt:              r.RunAt,
+		Cron:               r.Cron,
+		Timezone:           r.Timezone,
+		RollbackAt:         r.RollbackAt,
+		RollbackAfter:      r.RollbackAfter,
+		RollbackOnFailure:  r.RollbackOnFailure,
+		ConflictResolution: session.ConflictResolution(r.ConflictResolution),
+		Enabled:            enabled,
+	}
+}
+
+// ListSchedules lista todos os agendamentos
+// GET /api/v1/schedules
+func (h *SchedulesHandler) ListSchedules(c *gin.Context) {
+	schedules := h.scheduler.List()
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    schedules,
+		"count":   len(schedules),
+	})
+}
+
+// GetSchedule retorna um agendamento
+// GET /api/v1/schedules/:id
+func (h *SchedulesHandler) GetSchedule(c *gin.Context) {
+	sched, ok := h.scheduler.Get(c.Param("id"))
+	if !ok {
+		h.respondError(c, scheduler.ErrNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    sched,
+	})
+}
+
+// CreateSchedule cria um agendamento
+// POST /api/v1/schedules
+func (h *SchedulesHandler) CreateSchedule(c *gin.Context) {
+	var req ScheduleRequest
+	if !bindScheduleRequest(c, &req) {
+		return
+	}
+
+	if !h.authorizeRequest(c, req) {
+		return
+	}
+
+	schedule := req.toSchedule()
+	schedule.CreatedBy = actorName(c, "", "")
+	schedule.Owner = scheduleOwner(c)
+	sched, err := h.scheduler.Create(schedule)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	fmt.Printf("⏰ Agendamento criado: %s (sessão %s, próxima execução %s)\n",
+		sched.ID, sched.SessionName, formatNextRun(sched.NextRunAt))
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    sched,
+	})
+}
+
+// UpdateSchedule atualiza a configuração de um agendamento
+// PUT /api/v1/schedules/:id
+func (h *SchedulesHandler) UpdateSchedule(c *gin.Context) {
+	var req ScheduleRequest
+	if !bindScheduleRequest(c, &req) {
+		return
+	}
+
+	if !h.authorizeRequest(c, req) {
+		return
+	}
+
+	schedule := req.toSchedule()
+	schedule.Owner = scheduleOwner(c)
+	sched, err := h.scheduler.Update(c.Param("id"), schedule)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    sched,
+	})
+}
+
+// DeleteSchedule remove um agendamento
+// DELETE /api/v1/schedules/:id
+func (h *SchedulesHandler) DeleteSchedule(c *gin.Context) {
+	if !auth.Require(c, auth.OpSessionManage, "") {
+		return
+	}
+
+	id := c.Param("id")
+	if err := h.scheduler.Delete(id); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": fmt.Sprintf("Schedule %s deleted", id),
+	})
+}
+
+// authorizeRequest exige session_manage e permissão para aplicar os itens da sessão agendada
+func (h *SchedulesHandler) authorizeRequest(c *gin.Context, req ScheduleRequest) bool {
+	if !auth.Require(c, auth.OpSessionManage, "") {
+		return false
+	}
+	return h.authorize == nil || h.authorize(c, req.SessionName, req.Folder)
+}
+
+// scheduleOwner usuário gravado no agendamento: o scheduler reautoriza a sessão com as
+// permissões atuais dele a cada execução (quem atualiza o agendamento passa a responder por ele)
+func scheduleOwner(c *gin.Context) *scheduler.Owner {
+	id := auth.FromContext(c)
+	if id == nil {
+		return nil
+	}
+	return &scheduler.Owner{Method: id.Method, Username: id.Username, Groups: id.Groups}
+}
+
+func bindScheduleRequest(c *gin.Context, req *ScheduleRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_REQUEST",
+				"message": fmt.Sprintf("Invalid request body: %v", err),
+			},
+		})
+		return false
+	}
+	return true
+}
+
+// respondError mapeia erros do scheduler para status HTTP
+func (h *SchedulesHandler) respondError(c *gin.Context, err error) {
+	status, code := http.StatusBadRequest, "INVALID_SCHEDULE"
+	switch {
+	case errors.Is(err, scheduler.ErrNotFound):
+		status, code = http.StatusNotFound, "SCHEDULE_NOT_FOUND"
+	case errors.Is(err, scheduler.ErrRunning):
+		status, code = http.StatusConflict, "SCHEDULE_RUNNING"
+	}
+
+	c.JSON(status, gin.H{
+		"success": false,
+		"error": gin.H{
+			"code":    code,
+			"message": err.Error(),
+		},
+	})
+}
+
+func formatNextRun(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04:05 MST")
+}
@@ -0,0 +1,459 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s-hpa-manager/internal/audit"
+	"k8s-hpa-manager/internal/history"
+	kubeclient "k8s-hpa-manager/internal/kubernetes"
+	"k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/nodepool"
+	"k8s-hpa-manager/internal/session"
+	"k8s-hpa-manager/internal/web/auth"
+	"k8s-hpa-manager/internal/web/events"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ApplySessionRequest opções de aplicação de uma sessão
+type ApplySessionRequest struct {
+	RollbackOnFailure bool `json:"rollback_on_failure"`
+	// Resolutions decisão por item em conflito (chave do item → skip, force ou rebase)
+	Resolutions map[string]string `json:"resolutions,omitempty"`
+	// DefaultResolution decisão para conflitos sem entrada em Resolutions
+	DefaultResolution string `json:"default_resolution,omitempty"`
+}
+
+// applyOptions converte o corpo da requisição em session.ApplyOptions
+func (r ApplySessionRequest) applyOptions() (session.ApplyOptions, error) {
+	opts := session.ApplyOptions{RollbackOnFailure: r.RollbackOnFailure}
+	if r.DefaultResolution != "" {
+		resolution, err := session.ParseConflictResolution(r.DefaultResolution)
+		if err != nil {
+			return opts, err
+		}
+		opts.DefaultResolution = resolution
+	}
+	if len(r.Resolutions) > 0 {
+		opts.Resolutions = make(map[string]session.ConflictResolution, len(r.Resolutions))
+		for key, value := range r.Resolutions {
+			resolution, err := session.ParseConflictResolution(value)
+			if err != nil {
+				return opts, fmt.Errorf("%s: %w", key, err)
+			}
+			opts.Resolutions[key] = resolution
+		}
+	}
+	return opts, nil
+}
+
+// bindApplyOptions lê as opções de aplicação do corpo (opcional), respondendo 400 se inválidas
+func bindApplyOptions(c *gin.Context) (session.ApplyOptions, bool) {
+	var req ApplySessionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "INVALID_REQUEST",
+					"message": fmt.Sprintf("Invalid request body: %v", err),
+				},
+			})
+			return session.ApplyOptions{}, false
+		}
+	}
+
+	opts, err := req.applyOptions()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_RESOLUTION",
+				"message": err.Error(),
+			},
+		})
+		return session.ApplyOptions{}, false
+	}
+	return opts, true
+}
+
+// respondSessionConflict responde 409 com os itens em conflito sem decisão
+func respondSessionConflict(c *gin.Context, result *session.Result) {
+	c.JSON(http.StatusConflict, gin.H{
+		"success": false,
+		"data":    result,
+		"error": gin.H{
+			"code":    "SESSION_CONFLICT",
+			"message": result.Err().Error(),
+		},
+	})
+}
+
+// PlanSession executa o dry-run de uma sessão (server-side dry-run para HPAs/workloads,
+// validação para node pools). Itens alterados no cluster desde que a sessão foi salva
+// trazem o conflito (original/live/target).
+// POST /api/v1/sessions/:name/plan?folder=
+func (h *SessionsHandler) PlanSession(c *gin.Context) {
+	sess, _, ok := h.loadSessionForApply(c)
+	if !ok {
+		return
+	}
+
+	skipAudit(c)
+	plan := h.newApplier(nil, "").Plan(c.Request.Context(), sess)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    plan,
+	})
+}
+
+// ApplySession aplica uma sessão na ordem node pools (upscale) → HPAs → recursos → node pools (downscale).
+// Uma sessão de rollback é salva automaticamente na pasta Rollback. Se algum item mudou no cluster
+// desde que a sessão foi salva e não há decisão em resolutions/default_resolution, nada é aplicado
+// e a resposta é 409 com os conflitos.
+// POST /api/v1/sessions/:name/apply?folder=
+func (h *SessionsHandler) ApplySession(c *gin.Context) {
+	sess, folder, ok := h.loadSessionForApply(c)
+	if !ok || !authorizeSessionChanges(c, sess) {
+		return
+	}
+
+	opts, ok := bindApplyOptions(c)
+	if !ok {
+		return
+	}
+
+	fmt.Printf("🚀 Aplicando sessão %s (%d HPAs, %d node pools, %d recursos)\n",
+		sess.Name, len(sess.Changes), len(sess.NodePoolChanges), len(sess.ResourceChanges))
+
+	result := h.newApplier(auditRecorder(c), sess.Name).Apply(c.Request.Context(), sess, opts)
+	if result.Conflicts > 0 {
+		fmt.Printf("⚠️  Sessão %s não aplicada: %d itens alterados no cluster desde que foi salva\n", sess.Name, result.Conflicts)
+		respondSessionConflict(c, result)
+		return
+	}
+
+	// Persistir estado de aplicação (Applied/AppliedAt/Error e RollbackData)
+	if err := h.sessionManager.SaveSessionToFolder(sess, folder); err != nil {
+		fmt.Printf("⚠️  Falha ao salvar estado da sessão %s: %v\n", sess.Name, err)
+	}
+
+	h.logSessionResult(history.ActionApplySession, sess, result, auth.User(c))
+	h.respondSessionResult(c, result)
+}
+
+// RollbackSession aplica os valores originais dos itens aplicados de uma sessão. Itens
+// alterados no cluster depois da aplicação exigem decisão (resolutions/default_resolution,
+// como no apply); sem ela nada é desfeito e a resposta é 409 com os conflitos.
+// POST /api/v1/sessions/:name/rollback?folder=
+func (h *SessionsHandler) RollbackSession(c *gin.Context) {
+	sess, folder, ok := h.loadSessionForApply(c)
+	if !ok || !authorizeSessionChanges(c, sess) {
+		return
+	}
+
+	opts, ok := bindApplyOptions(c)
+	if !ok {
+		return
+	}
+
+	fmt.Printf("↩️  Rollback da sessão %s\n", sess.Name)
+
+	result, err := h.newApplier(auditRecorder(c), sess.Name).Rollback(c.Request.Context(), sess, opts)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "ROLLBACK_NOT_ALLOWED",
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+	if result.Conflicts > 0 {
+		fmt.Printf("⚠️  Rollback da sessão %s não executado: %d itens alterados no cluster desde a aplicação\n", sess.Name, result.Conflicts)
+		respondSessionConflict(c, result)
+		return
+	}
+
+	if err := h.sessionManager.SaveSessionToFolder(sess, folder); err != nil {
+		fmt.Printf("⚠️  Falha ao salvar estado da sessão %s: %v\n", sess.Name, err)
+	}
+
+	h.logSessionResult(history.ActionRollbackSession, sess, result, auth.User(c))
+	h.respondSessionResult(c, result)
+}
+
+// ExecuteSavedSession planeja e aplica uma sessão salva fora de uma requisição HTTP
+// (usado pelo scheduler). Nada é aplicado se authorize recusar a sessão (permissões
+// atuais de quem agendou) ou se o plano for inválido.
+func (h *SessionsHandler) ExecuteSavedSession(ctx context.Context, name string, folder session.SessionFolder, opts session.ApplyOptions, authorize func(*models.Session) error) (*session.Result, error) {
+	if h.sessionManager == nil || h.kubeManager == nil {
+		return nil, fmt.Errorf("session manager not initialized")
+	}
+
+	sess, foundFolder, err := h.sessionManager.FindSession(name, folder)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(sess); err != nil {
+		return nil, err
+	}
+
+	applier := h.newApplier(audit.NewRecorder(h.auditLog, audit.SourceScheduler, "scheduler"), sess.Name)
+	if plan := applier.Plan(ctx, sess); !plan.Valid {
+		var problems []string
+		for _, item := range plan.Items {
+			if item.Status == session.StatusInvalid {
+				problems = append(problems, fmt.Sprintf("%s: %s", item.String(), item.Error))
+			}
+		}
+		return nil, fmt.Errorf("plan for session %s is invalid: %s", name, strings.Join(problems, "; "))
+	}
+
+	result := applier.Apply(ctx, sess, opts)
+	if err := h.sessionManager.SaveSessionToFolder(sess, foundFolder); err != nil {
+		fmt.Printf("⚠️  Falha ao salvar estado da sessão %s: %v\n", sess.Name, err)
+	}
+	h.logSessionResult(history.ActionApplySession, sess, result, "scheduler")
+	return result, nil
+}
+
+// SessionDir retorna o diretório de sessões (vazio se o manager não foi inicializado)
+func (h *SessionsHandler) SessionDir() string {
+	if h.sessionManager == nil {
+		return ""
+	}
+	return h.sessionManager.SessionDir()
+}
+
+// loadSessionForApply carrega a sessão de :name (opcionalmente de ?folder=) respondendo erros
+func (h *SessionsHandler) loadSessionForApply(c *gin.Context) (*models.Session, session.SessionFolder, bool) {
+	if h.sessionManager == nil || h.kubeManager == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "SESSION_MANAGER_ERROR",
+				"message": "Session manager not initialized",
+			},
+		})
+		return nil, "", false
+	}
+
+	sessionName := c.Param("name")
+	var sessionFolder session.SessionFolder
+	if folder := c.Query("folder"); folder != "" {
+		parsed, err := h.parseSessionFolder(folder)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "INVALID_FOLDER",
+					"message": fmt.Sprintf("Invalid folder name: %s", folder),
+				},
+			})
+			return nil, "", false
+		}
+		sessionFolder = parsed
+	}
+
+	sess, foundFolder, err := h.sessionManager.FindSession(sessionName, sessionFolder)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "SESSION_NOT_FOUND",
+				"message": fmt.Sprintf("Session not found: %s", sessionName),
+			},
+		})
+		return nil, "", false
+	}
+
+	return sess, foundFolder, true
+}
+
+// sessionPermission operação exigida por um item da sessão no seu cluster
+type sessionPermission struct {
+	op      auth.Operation
+	cluster string
+}
+
+// sessionPermissions lista as permissões exigidas pelos itens da sessão:
+// HPAs → hpa_edit, node pools → nodepool_scale, recursos → workload_edit
+func sessionPermissions(sess *models.Session) []sessionPermission {
+	var permissions []sessionPermission
+	for _, change := range sess.Changes {
+		permissions = append(permissions, sessionPermission{auth.OpHPAEdit, change.Cluster})
+	}
+	for _, change := range sess.NodePoolChanges {
+		permissions = append(permissions, sessionPermission{auth.OpNodePoolScale, change.Cluster})
+	}
+	for _, change := range sess.ResourceChanges {
+		permissions = append(permissions, sessionPermission{auth.OpWorkloadEdit, change.Cluster})
+	}
+	return permissions
+}
+
+// authorizeSessionChanges exige a permissão de cada item da sessão no seu cluster
+func authorizeSessionChanges(c *gin.Context, sess *models.Session) bool {
+	for _, p := range sessionPermissions(sess) {
+		if !auth.Require(c, p.op, p.cluster) {
+			return false
+		}
+	}
+	return true
+}
+
+// authorizeScheduledSession verifica os itens da sessão contra as permissões de quem a
+// agendou (execução sem requisição). Usuário sem bindings é recusado.
+func authorizeScheduledSession(sess *models.Session, id *auth.Identity) error {
+	if len(id.Grants) == 0 {
+		return fmt.Errorf("user %s has no role bindings: session %s not applied", id.Name(), sess.Name)
+	}
+	for _, p := range sessionPermissions(sess) {
+		if !id.Can(p.op, p.cluster) {
+			return fmt.Errorf("session %s requires %s on cluster %s, not granted to %s", sess.Name, p.op, p.cluster, id.Name())
+		}
+	}
+	return nil
+}
+
+// AuthorizeSavedSession verifica se o usuário pode aplicar a sessão salva (usado ao agendar).
+// A sessão precisa existir: sem ela não há como saber quais clusters serão alterados.
+func (h *SessionsHandler) AuthorizeSavedSession(c *gin.Context, name, folder string) bool {
+	if h.sessionManager == nil {
+		return true
+	}
+	var sessionFolder session.SessionFolder
+	if folder != "" {
+		parsed, err := h.parseSessionFolder(folder)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "INVALID_FOLDER",
+					"message": fmt.Sprintf("Invalid folder name: %s", folder),
+				},
+			})
+			return false
+		}
+		sessionFolder = parsed
+	}
+	sess, _, err := h.sessionManager.FindSession(name, sessionFolder)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "SESSION_NOT_FOUND",
+				"message": fmt.Sprintf("Session not found: %s", name),
+			},
+		})
+		return false
+	}
+	return authorizeSessionChanges(c, sess)
+}
+
+// newApplier cria o session.Applier usando os clients do KubeConfigManager; recorder
+// registra os itens aplicados no audit log (nil em planos) e operationID identifica a
+// aplicação nos eventos operation.progress (nome da sessão)
+func (h *SessionsHandler) newApplier(recorder *audit.Recorder, operationID string) *session.Applier {
+	return session.NewApplier(session.ApplierOptions{
+		Clients: func(cluster string) (session.KubeClient, error) {
+			client, err := h.kubeManager.GetClient(cluster)
+			if err != nil {
+				return nil, err
+			}
+			return kubeclient.NewClient(client, cluster), nil
+		},
+		NodePools: h.nodePoolProvider,
+		ResolveCluster: func(change *models.NodePoolChange) nodepool.ClusterRef {
+			if clusterConfig, err := findClusterInConfig(change.Cluster); err == nil {
+				return nodepool.ClusterRefFromConfig(clusterConfig)
+			}
+			return nodepool.ClusterRef{
+				Provider:      nodepool.ProviderAKS,
+				Name:          strings.TrimSuffix(change.Cluster, "-admin"),
+				ResourceGroup: change.ResourceGroup,
+				Subscription:  change.Subscription,
+			}
+		},
+		Manager: h.sessionManager,
+		Progress: func(item session.ItemResult) {
+			fmt.Printf("   %s %s\n", sessionItemIcon(item.Status), item.String())
+			h.eventHub.PublishProgress(events.OperationSessionApply, operationID, item.Cluster, item)
+		},
+		Audit: recorder,
+	})
+}
+
+// respondSessionResult responde 200 em sucesso total e 207 (Multi-Status) com falhas parciais
+func (h *SessionsHandler) respondSessionResult(c *gin.Context, result *session.Result) {
+	if err := result.Err(); err != nil {
+		c.JSON(http.StatusMultiStatus, gin.H{
+			"success": false,
+			"data":    result,
+			"error": gin.H{
+				"code":    "APPLY_FAILED",
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// logSessionResult registra cada item aplicado/falho no histórico
+func (h *SessionsHandler) logSessionResult(action string, sess *models.Session, result *session.Result, user string) {
+	if h.historyTracker == nil {
+		return
+	}
+
+	duration, _ := time.ParseDuration(result.Duration)
+	for _, item := range result.Items {
+		status := history.StatusSuccess
+		switch item.Status {
+		case session.StatusFailed, session.StatusRollbackFailed:
+			status = history.StatusFailed
+		case session.StatusSkipped, session.StatusConflict:
+			continue
+		}
+
+		resource := item.Name
+		if item.Namespace != "" {
+			resource = fmt.Sprintf("%s/%s", item.Namespace, item.Name)
+		}
+		h.historyTracker.Log(history.HistoryEntry{
+			Action:      action,
+			Resource:    fmt.Sprintf("%s:%s", item.Kind, resource),
+			Cluster:     item.Cluster,
+			After:       map[string]interface{}{"status": item.Status, "changes": item.Changes},
+			Status:      status,
+			ErrorMsg:    item.Error,
+			Duration:    duration.Milliseconds(),
+			SessionName: sess.Name,
+			User:        user,
+		})
+	}
+}
+
+func sessionItemIcon(status session.ItemStatus) string {
+	switch status {
+	case session.StatusApplied:
+		return "✅"
+	case session.StatusRolledBack:
+		return "↩️"
+	case session.StatusSkipped:
+		return "⏭️"
+	default:
+		return "❌"
+	}
+}
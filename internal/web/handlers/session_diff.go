@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/session"
+	"k8s-hpa-manager/internal/web/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MergeSessionsRequest corpo do merge de duas sessões salvas
+type MergeSessionsRequest struct {
+	SessionA    string            `json:"session_a" binding:"required"`
+	FolderA     string            `json:"folder_a"`
+	SessionB    string            `json:"session_b" binding:"required"`
+	FolderB     string            `json:"folder_b"`
+	Name        string            `json:"name" binding:"required"` // nome da sessão resultante
+	Folder      string            `json:"folder"`                  // pasta de destino (padrão: pasta de A)
+	Prefer      string            `json:"prefer"`                  // a ou b para conflitos sem decisão
+	Resolutions map[string]string `json:"resolutions"`             // chave do item → a ou b
+	DryRun      bool              `json:"dry_run"`                 // apenas retorna a sessão resultante
+}
+
+func (r MergeSessionsRequest) mergeOptions() (session.MergeOptions, error) {
+	opts := session.MergeOptions{Name: r.Name}
+	if r.Prefer != "" {
+		side, err := session.ParseMergeSide(r.Prefer)
+		if err != nil {
+			return opts, err
+		}
+		opts.Prefer = side
+	}
+	if len(r.Resolutions) > 0 {
+		opts.Resolutions = make(map[string]session.MergeSide, len(r.Resolutions))
+		for key, value := range r.Resolutions {
+			side, err := session.ParseMergeSide(value)
+			if err != nil {
+				return opts, fmt.Errorf("%s: %w", key, err)
+			}
+			opts.Resolutions[key] = side
+		}
+	}
+	return opts, nil
+}
+
+// DiffSessions compara os valores alvo de duas sessões salvas
+// GET /api/v1/sessions/:name/diff/:other?folder_a=&folder_b=
+func (h *SessionsHandler) DiffSessions(c *gin.Context) {
+	if !h.requireSessionManager(c) {
+		return
+	}
+
+	a, _, ok := h.findSessionForDiff(c, c.Param("name"), c.Query("folder_a"))
+	if !ok {
+		return
+	}
+	b, _, ok := h.findSessionForDiff(c, c.Param("other"), c.Query("folder_b"))
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    session.Diff(a, b),
+	})
+}
+
+// MergeSessions combina duas sessões salvas em uma nova sessão
+// POST /api/v1/sessions/merge
+func (h *SessionsHandler) MergeSessions(c *gin.Context) {
+	if !auth.Require(c, auth.OpSessionManage, "") {
+		return
+	}
+
+	if !h.requireSessionManager(c) {
+		return
+	}
+
+	var req MergeSessionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_REQUEST",
+				"message": fmt.Sprintf("Invalid request: %v", err),
+			},
+		})
+		return
+	}
+
+	opts, err := req.mergeOptions()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_MERGE_SIDE",
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	a, folderA, ok := h.findSessionForDiff(c, req.SessionA, req.FolderA)
+	if !ok {
+		return
+	}
+	b, _, ok := h.findSessionForDiff(c, req.SessionB, req.FolderB)
+	if !ok {
+		return
+	}
+
+	target := folderA
+	if req.Folder != "" {
+		if target, err = h.parseSessionFolder(req.Folder); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "INVALID_FOLDER",
+					"message": fmt.Sprintf("Invalid folder name: %s", req.Folder),
+				},
+			})
+			return
+		}
+	}
+
+	result, err := session.Merge(a, b, opts, time.Now())
+	if err != nil {
+		status, code := http.StatusBadRequest, "INVALID_MERGE"
+		if errors.Is(err, session.ErrMergeConflicts) {
+			status, code = http.StatusConflict, "MERGE_CONFLICT"
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"data":    result,
+			"error": gin.H{
+				"code":    code,
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	if req.DryRun {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"data":    result,
+		})
+		return
+	}
+
+	if _, err := h.sessionManager.LoadSessionFromFolder(req.Name, target); err == nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "SESSION_EXISTS",
+				"message": fmt.Sprintf("Session already exists: %s", req.Name),
+			},
+		})
+		return
+	}
+
+	if err := h.sessionManager.SaveSessionToFolder(result.Session, target); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "SAVE_ERROR",
+				"message": fmt.Sprintf("Failed to save session: %v", err),
+			},
+		})
+		return
+	}
+
+	fmt.Printf("🔀 Sessões %s e %s combinadas em %s (%d itens)\n",
+		a.Name, b.Name, result.Session.Name, result.Session.Metadata.TotalChanges)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    result,
+		"folder":  string(target),
+	})
+}
+
+// requireSessionManager responde 500 se o session manager não foi inicializado
+func (h *SessionsHandler) requireSessionManager(c *gin.Context) bool {
+	if h.sessionManager != nil {
+		return true
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"success": false,
+		"error": gin.H{
+			"code":    "SESSION_MANAGER_ERROR",
+			"message": "Session manager not initialized",
+		},
+	})
+	return false
+}
+
+// findSessionForDiff busca a sessão (opcionalmente em uma pasta) respondendo erros
+func (h *SessionsHandler) findSessionForDiff(c *gin.Context, name, folder string) (*models.Session, session.SessionFolder, bool) {
+	var sessionFolder session.SessionFolder
+	if folder != "" {
+		parsed, err := h.parseSessionFolder(folder)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "INVALID_FOLDER",
+					"message": fmt.Sprintf("Invalid folder name: %s", folder),
+				},
+			})
+			return nil, "", false
+		}
+		sessionFolder = parsed
+	}
+
+	sess, foundFolder, err := h.sessionManager.FindSession(name, sessionFolder)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "SESSION_NOT_FOUND",
+				"message": fmt.Sprintf("Session not found: %s", name),
+			},
+		})
+		return nil, "", false
+	}
+	return sess, foundFolder, true
+}
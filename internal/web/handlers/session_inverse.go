@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"k8s-hpa-manager/internal/session"
+	"k8s-hpa-manager/internal/web/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InverseSessionRequest corpo da geração de sessão inversa
+type InverseSessionRequest struct {
+	Name    string `json:"name"`    // vazio = Inverse_<origem>_<timestamp>
+	Folder  string `json:"folder"`  // vazio = pasta oposta à origem (ou Rollback)
+	Refresh bool   `json:"refresh"` // ler os valores atuais do cluster
+}
+
+// GenerateInverseSession gera e salva a sessão inversa de uma sessão salva
+// POST /api/v1/sessions/:name/inverse?folder=
+func (h *SessionsHandler) GenerateInverseSession(c *gin.Context) {
+	if !auth.Require(c, auth.OpSessionManage, "") {
+		return
+	}
+
+	if !h.requireSessionManager(c) {
+		return
+	}
+
+	var req InverseSessionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "INVALID_REQUEST",
+					"message": fmt.Sprintf("Invalid request: %v", err),
+				},
+			})
+			return
+		}
+	}
+
+	source, sourceFolder, ok := h.findSessionForDiff(c, c.Param("name"), c.Query("folder"))
+	if !ok {
+		return
+	}
+
+	opts := session.InverseOptions{Name: req.Name}
+	if req.Folder != "" {
+		folder, err := h.parseSessionFolder(req.Folder)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "INVALID_FOLDER",
+					"message": fmt.Sprintf("Invalid folder name: %s", req.Folder),
+				},
+			})
+			return
+		}
+		opts.Folder = folder
+	}
+	if req.Refresh {
+		if h.kubeManager == nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "KUBE_MANAGER_ERROR",
+					"message": "Kubernetes manager not initialized",
+				},
+			})
+			return
+		}
+		opts.Live = h.newApplier(nil, "")
+	}
+
+	inverse, folder, err := h.sessionManager.GenerateInverseSession(c.Request.Context(), source, sourceFolder, opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVERSE_FAILED",
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	fmt.Printf("🔄 Sessão inversa de %s salva em %s/%s\n", source.Name, folder, inverse.Name)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    inverse,
+		"folder":  string(folder),
+	})
+}
@@ -4,19 +4,31 @@ import (
 	"fmt"
 	"net/http"
 
+	"k8s-hpa-manager/internal/audit"
+	"k8s-hpa-manager/internal/azure"
+	"k8s-hpa-manager/internal/config"
+	"k8s-hpa-manager/internal/history"
 	"k8s-hpa-manager/internal/models"
+	"k8s-hpa-manager/internal/nodepool"
 	"k8s-hpa-manager/internal/session"
+	"k8s-hpa-manager/internal/web/auth"
+	"k8s-hpa-manager/internal/web/events"
 
 	"github.com/gin-gonic/gin"
 )
 
 // SessionsHandler handles session-related endpoints
 type SessionsHandler struct {
-	sessionManager *session.Manager
+	sessionManager   *session.Manager
+	kubeManager      *config.KubeConfigManager
+	historyTracker   *history.HistoryTracker
+	nodePoolProvider nodepool.NodePoolProvider
+	auditLog         *audit.Log
+	eventHub         *events.Hub // operation.progress dos itens aplicados (opcional)
 }
 
 // NewSessionsHandler creates a new sessions handler
-func NewSessionsHandler() *SessionsHandler {
+func NewSessionsHandler(km *config.KubeConfigManager, ht *history.HistoryTracker) *SessionsHandler {
 	// Reutilizar o SessionManager existente - EXATAMENTE como no TUI
 	sessionManager, err := session.NewManager()
 	if err != nil {
@@ -26,10 +38,23 @@ func NewSessionsHandler() *SessionsHandler {
 	}
 
 	return &SessionsHandler{
-		sessionManager: sessionManager,
+		sessionManager:   sessionManager,
+		kubeManager:      km,
+		historyTracker:   ht,
+		nodePoolProvider: nodepool.NewDefaultRegistry(azure.NewAuthManager()),
 	}
 }
 
+// SetAuditLog define o audit log das execuções fora de requisições HTTP (scheduler)
+func (h *SessionsHandler) SetAuditLog(log *audit.Log) {
+	h.auditLog = log
+}
+
+// SetEventHub publica o progresso das aplicações de sessão em GET /api/v1/events
+func (h *SessionsHandler) SetEventHub(hub *events.Hub) {
+	h.eventHub = hub
+}
+
 // SessionListResponse represents a list of sessions
 type SessionListResponse struct {
 	Sessions []SessionSummary `json:"sessions"`
@@ -316,6 +341,10 @@ func (h *SessionsHandler) GetSession(c *gin.Context) {
 
 // SaveSession saves a new session
 func (h *SessionsHandler) SaveSession(c *gin.Context) {
+	if !auth.Require(c, auth.OpSessionManage, "") {
+		return
+	}
+
 	if h.sessionManager == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -359,7 +388,8 @@ func (h *SessionsHandler) SaveSession(c *gin.Context) {
 		TemplateUsed:    req.Template,
 		Changes:         req.Changes,
 		NodePoolChanges: req.NodePools,
-		// CreatedAt e CreatedBy serão preenchidos pelo SessionManager
+		CreatedBy:       actorName(c, "", ""), // usuário OIDC; vazio = SessionManager usa $USER
+		// CreatedAt será preenchido pelo SessionManager
 		// Metadata será gerado automaticamente pelo SessionManager
 		RollbackData: &models.RollbackData{
 			OriginalStateCaptured:   true,
@@ -393,6 +423,10 @@ func (h *SessionsHandler) SaveSession(c *gin.Context) {
 
 // DeleteSession deletes a session
 func (h *SessionsHandler) DeleteSession(c *gin.Context) {
+	if !auth.Require(c, auth.OpSessionManage, "") {
+		return
+	}
+
 	if h.sessionManager == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -450,6 +484,10 @@ func (h *SessionsHandler) DeleteSession(c *gin.Context) {
 
 // RenameSession renames a session
 func (h *SessionsHandler) RenameSession(c *gin.Context) {
+	if !auth.Require(c, auth.OpSessionManage, "") {
+		return
+	}
+
 	if h.sessionManager == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -529,6 +567,10 @@ func (h *SessionsHandler) RenameSession(c *gin.Context) {
 
 // UpdateSession updates an existing session content
 func (h *SessionsHandler) UpdateSession(c *gin.Context) {
+	if !auth.Require(c, auth.OpSessionManage, "") {
+		return
+	}
+
 	if h.sessionManager == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
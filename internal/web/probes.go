@@ -0,0 +1,46 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// liveness responde enquanto o processo atende HTTP (livenessProbe)
+// GET /livez
+func (s *Server) liveness(c *gin.Context) {
+	c.JSON(200, gin.H{"status": "ok"})
+}
+
+// readiness indica se o pod deve receber tráfego (readinessProbe). Falha durante o
+// graceful shutdown e sem nenhum contexto de cluster carregado. O banco de monitoramento
+// é informativo: sem ele o resto da interface continua funcionando.
+// GET /readyz
+func (s *Server) readiness(c *gin.Context) {
+	ready := true
+	checks := gin.H{}
+
+	if s.draining.Load() {
+		ready = false
+		checks["shutdown"] = "draining"
+	} else {
+		checks["shutdown"] = "ok"
+	}
+
+	if contexts := len(s.kubeManager.ListContexts()); contexts == 0 {
+		ready = false
+		checks["kubeconfig"] = "no contexts loaded"
+	} else {
+		checks["kubeconfig"] = contexts
+	}
+
+	if s.monitoringEngine != nil && s.monitoringEngine.GetPersistence() != nil {
+		checks["monitoring_db"] = "ok"
+	} else {
+		checks["monitoring_db"] = "unavailable"
+	}
+
+	status, code := "ready", 200
+	if !ready {
+		status, code = "not_ready", 503
+	}
+	c.JSON(code, gin.H{"status": status, "checks": checks})
+}
@@ -3,26 +3,33 @@ package web
 import (
 	"context"
 	"embed"
-	"encoding/json"
 	"fmt"
 	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 
+	"k8s-hpa-manager/internal/audit"
 	"k8s-hpa-manager/internal/config"
 	"k8s-hpa-manager/internal/history"
+	"k8s-hpa-manager/internal/monitoring/alertmanager"
 	"k8s-hpa-manager/internal/monitoring/analyzer"
 	"k8s-hpa-manager/internal/monitoring/engine"
+	"k8s-hpa-manager/internal/monitoring/exporter"
 	"k8s-hpa-manager/internal/monitoring/models"
+	"k8s-hpa-manager/internal/monitoring/notifier"
 	"k8s-hpa-manager/internal/monitoring/scanner"
+	"k8s-hpa-manager/internal/monitoring/storage"
+	"k8s-hpa-manager/internal/scheduler"
+	"k8s-hpa-manager/internal/web/auth"
+	"k8s-hpa-manager/internal/web/events"
 	"k8s-hpa-manager/internal/web/handlers"
-	"k8s-hpa-manager/internal/web/middleware"
 )
 
 //go:embed all:static
@@ -34,12 +41,24 @@ type Server struct {
 	kubeManager    *config.KubeConfigManager
 	port           int
 	token          string
+	auth           *auth.Authenticator // OIDC, token estático e papéis (~/.k8s-hpa-manager/auth.yaml)
 	lastHeartbeat  time.Time
 	heartbeatMutex sync.RWMutex
 	shutdownTimer  *time.Timer
 	timerMutex     sync.Mutex // Protege operações no timer
 	logBuffer      *handlers.LogBuffer
 	historyTracker *history.HistoryTracker
+	auditLog       *audit.Log           // Audit log append-only (~/.k8s-hpa-manager/audit)
+	scheduler      *scheduler.Scheduler // Agendamentos de sessões (nil se não inicializado)
+	eventHub       *events.Hub          // Stream de eventos (GET /api/v1/events)
+	eventWatcher   *events.Watcher      // Informers por cluster com clientes no stream
+
+	// Modo servidor (Deployment no cluster): sem shutdown por inatividade, com probes e drain
+	inCluster       bool
+	httpServer      *http.Server
+	draining        atomic.Bool // readiness falha a partir do início do shutdown
+	shutdownDelay   time.Duration
+	shutdownTimeout time.Duration
 
 	// Monitoring engine (NOVO)
 	monitoringEngine *engine.ScanEngine
@@ -48,22 +67,57 @@ type Server struct {
 	stressResultChan chan *models.StressTestMetrics
 	monitoringCtx    context.Context
 	monitoringCancel context.CancelFunc
+	watchdogConfig   *models.WatchdogConfig // Alertas e Alertmanager (~/.k8s-hpa-manager/watchdog.yaml)
 }
 
-// NewServer cria uma nova instância do servidor web
+// Options opções do servidor web
+type Options struct {
+	Kubeconfig string
+	Port       int
+	Debug      bool
+
+	// InCluster ativa o modo servidor: clusters da ServiceAccount, Secrets e diretório montado
+	// no lugar do kubeconfig local (nil = estação de trabalho)
+	InCluster *config.InClusterOptions
+
+	ShutdownDelay   time.Duration // espera entre falhar a readiness e fechar o listener
+	ShutdownTimeout time.Duration // limite para requisições em andamento terminarem
+}
+
+// NewServer cria uma nova instância do servidor web (estação de trabalho)
 func NewServer(kubeconfig string, port int, debug bool) (*Server, error) {
-	// Reutilizar gerenciador de kube existente
-	kubeManager, err := config.NewKubeConfigManager(kubeconfig)
+	return NewServerWithOptions(Options{Kubeconfig: kubeconfig, Port: port, Debug: debug})
+}
+
+// NewServerWithOptions cria uma nova instância do servidor web
+func NewServerWithOptions(opts Options) (*Server, error) {
+	port, debug := opts.Port, opts.Debug
+
+	kubeManager, err := newKubeManager(opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kube manager: %w", err)
 	}
 
+	// Autenticação multiusuário (~/.k8s-hpa-manager/auth.yaml); sem arquivo, token único com papel admin
+	authConfig, err := auth.LoadConfig(auth.DefaultConfigPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load auth config: %w", err)
+	}
+
 	// Token de autenticação (opcional para POC)
 	token := os.Getenv("K8S_HPA_WEB_TOKEN")
-	if token == "" {
+	if token == "" && authConfig == nil {
 		token = "poc-token-123" // Token padrão para POC
 		fmt.Println("⚠️  Usando token padrão para POC: poc-token-123")
-		fmt.Println("💡 Para produção, defina K8S_HPA_WEB_TOKEN")
+		fmt.Println("💡 Para produção, defina K8S_HPA_WEB_TOKEN ou configure OIDC em " + auth.DefaultConfigPath())
+	}
+
+	authenticator, err := auth.New(context.Background(), authConfig, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize authentication: %w", err)
+	}
+	if authConfig != nil {
+		fmt.Printf("🔐 Autenticação multiusuário: OIDC=%v, %d binding(s)\n", authenticator.OIDCEnabled(), len(authConfig.Bindings))
 	}
 
 	// Setup Gin
@@ -87,6 +141,17 @@ func NewServer(kubeconfig string, port int, debug bool) (*Server, error) {
 		return nil, fmt.Errorf("failed to create history tracker: %w", err)
 	}
 
+	// Audit log (~/.k8s-hpa-manager/audit.yaml opcional): sem ele o servidor não sobe
+	auditConfig, err := audit.LoadConfig(audit.DefaultConfigPath())
+	if err != nil {
+		return nil, err
+	}
+	auditLog, err := audit.Open(auditConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	fmt.Printf("🧾 Audit log: %s\n", auditLog.Dir())
+
 	// Criar canais para monitoring engine
 	snapshotChan := make(chan *models.HPASnapshot, 100)
 	anomalyChan := make(chan analyzer.Anomaly, 100)
@@ -95,17 +160,14 @@ func NewServer(kubeconfig string, port int, debug bool) (*Server, error) {
 	// Criar contexto para monitoring
 	monitoringCtx, monitoringCancel := context.WithCancel(context.Background())
 
-	// ❌ REMOVIDO: Não carregar targets salvos do arquivo
-	// Source of truth: localStorage do frontend (escolha do operador)
-	// Backend inicia SEM HPAs e aguarda reconciliação do frontend
-
-	// Configuração do monitoring engine SEM targets
+	// HPAs monitorados, intervalo e running/paused ficam no monitoring.db e são
+	// restaurados abaixo (RestoreState); a lista do navegador é mesclada via /monitoring/sync
 	scanConfig := &scanner.ScanConfig{
 		Mode:        scanner.ScanModeIndividual,
-		Targets:     []scanner.ScanTarget{}, // Inicia VAZIO - HPAs adicionados via reconciliação
+		Targets:     []scanner.ScanTarget{},
 		Interval:    1 * time.Minute,
 		Duration:    0,
-		AutoStart:   false, // NÃO inicia automaticamente - aguarda frontend
+		AutoStart:   false, // Estado restaurado por RestoreState
 		Name:        "Web Monitoring",
 		Description: "Monitoring engine para interface web",
 		CreatedAt:   time.Now(),
@@ -114,52 +176,145 @@ func NewServer(kubeconfig string, port int, debug bool) (*Server, error) {
 	// Criar monitoring engine
 	monitoringEngine := engine.New(scanConfig, snapshotChan, anomalyChan, stressResultChan)
 
-	fmt.Println("⏳ Monitoring engine criado - aguardando escolha do operador (frontend)")
-	fmt.Println("💡 HPAs serão adicionados via reconciliação quando operador clicar 'Monitorar'")
+	// Ciclo de vida dos alertas e Alertmanager (~/.k8s-hpa-manager/watchdog.yaml; sem arquivo = defaults)
+	watchdogConfig, err := models.LoadWatchdogConfig(models.DefaultWatchdogConfigPath())
+	if err != nil {
+		fmt.Printf("⚠️  Configuração do watchdog ignorada: %v\n", err)
+		watchdogConfig = models.DefaultWatchdogConfig()
+	}
+	monitoringEngine.SetAlertPolicy(storage.AlertPolicyFromWatchdog(watchdogConfig))
+
+	// Notificações externas (~/.k8s-hpa-manager/notifications.yaml)
+	if notifierConfig, err := notifier.LoadConfig(notifier.DefaultConfigPath()); err != nil {
+		fmt.Printf("⚠️  Notificações desabilitadas: %v\n", err)
+	} else if notifierConfig != nil && len(notifierConfig.Sinks) > 0 {
+		anomalyNotifier, err := notifier.New(notifierConfig)
+		if err != nil {
+			fmt.Printf("⚠️  Notificações desabilitadas: %v\n", err)
+		} else {
+			monitoringEngine.SetNotifier(anomalyNotifier)
+			go anomalyNotifier.Run(monitoringCtx)
+			fmt.Printf("🔔 Notificações habilitadas: %d sink(s), %d rota(s)\n", len(notifierConfig.Sinks), len(notifierConfig.Routes))
+		}
+	}
+
+	// Stream de eventos (GET /api/v1/events): anomalias novas, histórico e, sob demanda,
+	// informers de HPAs e nodes dos clusters com clientes conectados
+	eventHub := events.NewHub(events.DefaultBufferSize)
+	monitoringEngine.SetAnomalyListener(func(anomaly analyzer.Anomaly, alert *storage.Alert) {
+		eventHub.Publish(events.Event{
+			Type:      events.TypeAnomaly,
+			Cluster:   anomaly.Cluster,
+			Namespace: anomaly.Namespace,
+			Data:      handlers.AnomalyToAPI(anomaly, alert),
+		})
+	})
+	historyTracker.SetListener(func(entry history.HistoryEntry) {
+		eventHub.Publish(events.Event{Type: events.TypeHistory, Cluster: entry.Cluster, Data: entry})
+	})
+
+	if restored, err := monitoringEngine.RestoreState(); err != nil {
+		fmt.Printf("⚠️  Estado do monitoramento não restaurado: %v\n", err)
+	} else if restored.HPAs+restored.Failed == 0 {
+		fmt.Println("⏳ Monitoring engine criado - nenhum HPA monitorado salvo")
+	} else {
+		fmt.Printf("📡 Monitoramento restaurado: %d HPA(s), intervalo %s, estado %s\n", restored.HPAs, restored.Interval, restored.Status)
+		if restored.Failed > 0 {
+			fmt.Printf("⚠️  %d HPA(s) não restaurados (serão tentados de novo na próxima sincronização)\n", restored.Failed)
+		}
+	}
 
 	server := &Server{
 		router:           router,
 		kubeManager:      kubeManager,
 		port:             port,
 		token:            token,
+		auth:             authenticator,
 		lastHeartbeat:    time.Now(),
 		logBuffer:        logBuffer,
 		historyTracker:   historyTracker,
+		auditLog:         auditLog,
+		eventHub:         eventHub,
+		eventWatcher:     events.NewWatcher(eventHub, kubeManager.GetWatchClient),
+		watchdogConfig:   watchdogConfig,
 		monitoringEngine: monitoringEngine,
 		snapshotChan:     snapshotChan,
 		anomalyChan:      anomalyChan,
 		stressResultChan: stressResultChan,
 		monitoringCtx:    monitoringCtx,
 		monitoringCancel: monitoringCancel,
+		inCluster:        opts.InCluster != nil,
+		httpServer:       &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: router},
+		shutdownDelay:    opts.ShutdownDelay,
+		shutdownTimeout:  opts.ShutdownTimeout,
+	}
+	if server.shutdownTimeout <= 0 {
+		server.shutdownTimeout = 30 * time.Second
 	}
 
 	server.setupMiddleware()
 	server.setupRoutes()
 	server.setupStatic()
-	server.startInactivityMonitor()
-
-	// ❌ REMOVIDO: Persistência de targets em arquivo
-	// Source of truth: localStorage do frontend (reconciliação)
+	if server.inCluster {
+		fmt.Println("♾️  Modo servidor: shutdown por inatividade desativado")
+	} else {
+		server.startInactivityMonitor()
+	}
+	go server.runAuditRetention(monitoringCtx)
 
 	return server, nil
 }
 
+// newKubeManager carrega o kubeconfig local ou, no modo servidor, mescla os clusters da
+// ServiceAccount, dos Secrets e do diretório montado
+func newKubeManager(opts Options) (*config.KubeConfigManager, error) {
+	if opts.InCluster == nil {
+		return config.NewKubeConfigManager(opts.Kubeconfig)
+	}
+
+	kubeManager, result, err := config.NewInClusterKubeConfigManager(context.Background(), *opts.InCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	// kubectl (port-forward, top) e o monitoring engine leem o kubeconfig de KUBECONFIG
+	if err := os.Setenv("KUBECONFIG", result.Path); err != nil {
+		return nil, fmt.Errorf("failed to set KUBECONFIG: %w", err)
+	}
+
+	fmt.Printf("☸️  Modo in-cluster: %d contexto(s) em %s\n", len(result.Contexts), result.Path)
+	for _, source := range result.Sources {
+		fmt.Printf("   - %s\n", source)
+	}
+	for _, warning := range result.Warnings {
+		fmt.Printf("⚠️  %s\n", warning)
+	}
+	if result.WorkloadIdentity > 0 {
+		fmt.Printf("🪪 Workload identity: %d usuário(s) kubelogin convertido(s)\n", result.WorkloadIdentity)
+	}
+	return kubeManager, nil
+}
+
 // setupMiddleware configura os middlewares do servidor
 func (s *Server) setupMiddleware() {
-	// CORS - permitir todas as origens para POC
+	// CORS - origens do auth.yaml ou o próprio servidor e o dev server do Vite
 	s.router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"},
+		AllowOrigins: s.auth.CORSOrigins([]string{
+			fmt.Sprintf("http://localhost:%d", s.port),
+			fmt.Sprintf("http://127.0.0.1:%d", s.port),
+			"http://localhost:5173",
+		}),
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Request-ID"},
+		ExposeHeaders:    []string{"Content-Length", "X-Request-ID"},
 		AllowCredentials: true,
 	}))
 
 	// Custom logging middleware que captura logs no buffer
 	s.router.Use(s.loggingMiddleware())
 
-	// Logging padrão do Gin (console)
-	s.router.Use(gin.Logger())
+	// Logging padrão do Gin (console), sem as probes do Kubernetes
+	s.router.Use(gin.LoggerWithConfig(gin.LoggerConfig{SkipPaths: []string{"/livez", "/readyz"}}))
 
 	// Recovery
 	s.router.Use(gin.Recovery())
@@ -188,9 +343,12 @@ func (s *Server) loggingMiddleware() gin.HandlerFunc {
 			statusCode,
 			latency,
 		)
+		if user := auth.User(c); user != "" {
+			logEntry += " | User: " + user
+		}
 
 		// Adicionar ao buffer (skip health checks para não encher o log)
-		if path != "/health" && path != "/heartbeat" {
+		if path != "/health" && path != "/heartbeat" && path != "/metrics" && path != "/livez" && path != "/readyz" {
 			s.logBuffer.Add(logEntry)
 		}
 	}
@@ -207,6 +365,10 @@ func (s *Server) setupRoutes() {
 		})
 	})
 
+	// Probes do Kubernetes (sem auth)
+	s.router.GET("/livez", s.liveness)
+	s.router.GET("/readyz", s.readiness)
+
 	// Heartbeat endpoint (sem auth) - frontend envia a cada 5 minutos
 	s.router.POST("/heartbeat", func(c *gin.Context) {
 		now := time.Now()
@@ -215,6 +377,15 @@ func (s *Server) setupRoutes() {
 		s.lastHeartbeat = now
 		s.heartbeatMutex.Unlock()
 
+		// Modo servidor: instância compartilhada, sem shutdown por inatividade
+		if s.inCluster {
+			c.JSON(200, gin.H{
+				"status":         "alive",
+				"last_heartbeat": now,
+			})
+			return
+		}
+
 		// Resetar timer de shutdown (thread-safe)
 		s.timerMutex.Lock()
 		if s.shutdownTimer != nil {
@@ -234,27 +405,52 @@ func (s *Server) setupRoutes() {
 		})
 	})
 
-	// Shutdown endpoint (com auth)
-	s.router.POST("/shutdown", middleware.AuthMiddleware(s.token), func(c *gin.Context) {
+	// Login OIDC (sem auth)
+	s.auth.RegisterRoutes(s.router)
+
+	// Shutdown endpoint (com auth, somente admin)
+	s.router.POST("/shutdown", s.auth.Middleware(), func(c *gin.Context) {
+		if !auth.Require(c, auth.OpAdmin, "") {
+			return
+		}
+		// Modo servidor: o ciclo de vida é do Deployment (kubectl rollout restart)
+		if s.inCluster {
+			c.JSON(409, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "SHUTDOWN_DISABLED",
+					"message": "Shutdown via API is disabled in in-cluster mode; restart the Deployment instead",
+				},
+			})
+			return
+		}
+		user := auth.User(c)
+		audit.NewRecorder(s.auditLog, audit.SourceWeb, user).Record(audit.Event{Action: "POST /shutdown"})
 		c.JSON(200, gin.H{
 			"message": "Servidor será desligado em 1 segundo...",
 		})
 
 		// Aguardar resposta ser enviada e então encerrar
 		go func() {
-			fmt.Println("\n🛑 Shutdown solicitado via API...")
+			fmt.Printf("\n🛑 Shutdown solicitado via API por %s...\n", user)
 			fmt.Println("✅ Servidor encerrado")
 			os.Exit(0)
 		}()
 	})
 
+	// Métricas Prometheus do monitoring engine (com auth: bearer_token no scrape config)
+	s.router.GET("/metrics", s.auth.Middleware(), gin.WrapH(exporter.Handler(s.monitoringEngine)))
+
 	// Version (sem auth - informação pública)
 	versionHandler := handlers.NewVersionHandler()
 	s.router.GET("/api/v1/version", versionHandler.GetVersion)
 
 	// API v1 (com auth)
 	api := s.router.Group("/api/v1")
-	api.Use(middleware.AuthMiddleware(s.token))
+	api.Use(s.auth.Middleware(), handlers.AuditMiddleware(s.auditLog))
+
+	// Usuário atual e permissões
+	api.GET("/auth/me", s.auth.Me)
 
 	// Clusters
 	clusterHandler := handlers.NewClusterHandler(s.kubeManager)
@@ -273,6 +469,10 @@ func (s *Server) setupRoutes() {
 	namespaceHandler := handlers.NewNamespaceHandler(s.kubeManager)
 	api.GET("/namespaces", namespaceHandler.List)
 
+	// Stream de eventos (SSE): HPAs, node pools, anomalias, histórico e operações longas
+	eventsHandler := handlers.NewEventsHandler(s.eventHub, s.eventWatcher)
+	api.GET("/events", eventsHandler.Stream)
+
 	// HPAs
 	hpaHandler := handlers.NewHPAHandler(s.kubeManager, s.historyTracker)
 	api.GET("/hpas", hpaHandler.List)
@@ -281,6 +481,7 @@ func (s *Server) setupRoutes() {
 
 	// Node Pools
 	nodePoolHandler := handlers.NewNodePoolHandler(s.kubeManager)
+	nodePoolHandler.SetEventHub(s.eventHub)
 	api.GET("/nodepools", nodePoolHandler.List)
 	api.PUT("/nodepools/:cluster/:resource_group/:name", nodePoolHandler.Update)
 	api.POST("/nodepools/apply-sequential", nodePoolHandler.ApplySequential)
@@ -317,7 +518,9 @@ func (s *Server) setupRoutes() {
 	s.router.GET("/api/v1/vpn/status", handlers.CheckVPNConnection)
 
 	// Sessions
-	sessionHandler := handlers.NewSessionsHandler()
+	sessionHandler := handlers.NewSessionsHandler(s.kubeManager, s.historyTracker)
+	sessionHandler.SetAuditLog(s.auditLog)
+	sessionHandler.SetEventHub(s.eventHub)
 	api.GET("/sessions", sessionHandler.ListAllSessions)
 	api.GET("/sessions/folders", sessionHandler.ListSessionFolders)
 	api.GET("/sessions/folders/:folder", sessionHandler.ListSessionsInFolder)
@@ -326,8 +529,34 @@ func (s *Server) setupRoutes() {
 	api.PUT("/sessions/:name", sessionHandler.UpdateSession)
 	api.DELETE("/sessions/:name", sessionHandler.DeleteSession)
 	api.PUT("/sessions/:name/rename", sessionHandler.RenameSession)
+	api.POST("/sessions/:name/plan", sessionHandler.PlanSession)
+	api.POST("/sessions/:name/apply", sessionHandler.ApplySession)
+	api.POST("/sessions/:name/rollback", sessionHandler.RollbackSession)
+	api.GET("/sessions/:name/diff/:other", sessionHandler.DiffSessions)
+	api.POST("/sessions/merge", sessionHandler.MergeSessions)
+	api.POST("/sessions/:name/inverse", sessionHandler.GenerateInverseSession)
 	api.GET("/sessions/templates", sessionHandler.GetSessionTemplates)
 
+	// Schedules (aplicação agendada de sessões salvas)
+	if dir := sessionHandler.SessionDir(); dir != "" {
+		sched, err := scheduler.New(scheduler.DefaultDir(dir), sessionHandler.ExecuteSavedSession, handlers.NewScheduleAuthorizer(s.auth), s.historyTracker)
+		if err != nil {
+			fmt.Printf("⚠️  Falha ao iniciar scheduler: %v\n", err)
+		} else {
+			s.scheduler = sched
+			go s.scheduler.Run(s.monitoringCtx)
+			fmt.Printf("⏰ Scheduler iniciado (%d agendamentos)\n", len(sched.List()))
+
+			schedulesHandler := handlers.NewSchedulesHandler(s.scheduler)
+			schedulesHandler.SetSessionAuthorizer(sessionHandler.AuthorizeSavedSession)
+			api.GET("/schedules", schedulesHandler.ListSchedules)
+			api.POST("/schedules", schedulesHandler.CreateSchedule)
+			api.GET("/schedules/:id", schedulesHandler.GetSchedule)
+			api.PUT("/schedules/:id", schedulesHandler.UpdateSchedule)
+			api.DELETE("/schedules/:id", schedulesHandler.DeleteSchedule)
+		}
+	}
+
 	// Logs
 	logsHandler := handlers.NewLogsHandler(s.logBuffer)
 	api.GET("/logs", logsHandler.GetLogs)
@@ -336,6 +565,13 @@ func (s *Server) setupRoutes() {
 	// Monitoring (NOVO - FASE 4: Passa persistence para query SQLite direto)
 	persistence := s.monitoringEngine.GetPersistence()
 	monitoringHandler := handlers.NewMonitoringHandler(s.monitoringEngine, persistence, s.anomalyChan, s.snapshotChan)
+	if s.watchdogConfig.AlertmanagerEnabled {
+		alertCollector := alertmanager.NewCollector(
+			alertmanager.ConfigFromWatchdog(s.watchdogConfig, s.monitoredClusters),
+			s.monitoringEngine.GetPortForwardManager())
+		monitoringHandler.SetAlertProvider(alertCollector)
+		go alertCollector.Run(s.monitoringCtx)
+	}
 	monitoring := api.Group("/monitoring")
 	{
 		monitoring.GET("/metrics/:cluster/:namespace/:hpaName", monitoringHandler.GetMetrics)
@@ -344,13 +580,44 @@ func (s *Server) setupRoutes() {
 		monitoring.GET("/status", monitoringHandler.GetStatus)
 		monitoring.POST("/start", monitoringHandler.Start)
 		monitoring.POST("/stop", monitoringHandler.Stop)
+		monitoring.POST("/pause", monitoringHandler.Pause)
+		monitoring.POST("/resume", monitoringHandler.Resume)
+		monitoring.PUT("/interval", monitoringHandler.SetInterval)
 
 		// Target management (NOVO)
 		monitoring.GET("/targets", monitoringHandler.GetTargets)
 		monitoring.POST("/targets", monitoringHandler.AddTarget)
-		monitoring.POST("/hpa", monitoringHandler.AddHPA)             // Adicionar HPA individual
-		monitoring.POST("/sync", monitoringHandler.SyncMonitoredHPAs) // Sincronizar lista completa (reconciliação)
+		monitoring.GET("/hpas", monitoringHandler.ListHPAs)                              // HPAs monitorados (salvos no monitoring.db)
+		monitoring.POST("/hpa", monitoringHandler.AddHPA)                                // Adicionar HPA individual
+		monitoring.DELETE("/hpa/:cluster/:namespace/:name", monitoringHandler.RemoveHPA) // Remover HPA individual
+		monitoring.POST("/sync", monitoringHandler.SyncMonitoredHPAs)                    // Mesclar lista do navegador com a do servidor
 		monitoring.DELETE("/targets/:cluster", monitoringHandler.RemoveTarget)
+
+		// Regras de anomalias (overrides de thresholds e regras customizadas)
+		monitoring.GET("/rules", monitoringHandler.GetRules)
+		monitoring.PUT("/rules", monitoringHandler.UpdateRules)
+		monitoring.POST("/rules/reload", monitoringHandler.ReloadRules)
+
+		// Fila de alertas (ack/silence, persistidos em SQLite)
+		monitoring.GET("/alerts", monitoringHandler.GetAlerts)
+		monitoring.POST("/alerts/:id/ack", monitoringHandler.AckAlert)
+		monitoring.POST("/alerts/:id/unack", monitoringHandler.UnackAlert)
+		monitoring.POST("/alerts/:id/silence", monitoringHandler.SilenceAlert)
+		monitoring.GET("/silences", monitoringHandler.GetSilences)
+		monitoring.POST("/silences", monitoringHandler.CreateSilence)
+		monitoring.DELETE("/silences/:id", monitoringHandler.DeleteSilence)
+
+		// Right-sizing a partir do histórico de snapshots
+		monitoring.GET("/recommendations", monitoringHandler.GetRightSizing)
+		monitoring.POST("/recommendations/session", monitoringHandler.CreateRightSizingSession)
+
+		// Previsão de réplicas e node pool para eventos
+		monitoring.GET("/forecast", monitoringHandler.GetForecast)
+		monitoring.POST("/forecast/session", monitoringHandler.CreateForecastSession)
+
+		// Relatórios de stress test
+		monitoring.GET("/stress-tests", monitoringHandler.ListStressTests)
+		monitoring.GET("/stress-tests/:id/report", monitoringHandler.GetStressTestReport)
 	}
 
 	// History
@@ -359,6 +626,31 @@ func (s *Server) setupRoutes() {
 	api.GET("/history/stats", historyHandler.GetHistoryStats)
 	api.GET("/history/:id", historyHandler.GetHistoryEntry)
 	api.DELETE("/history", historyHandler.ClearHistory)
+
+	// Audit log (somente admin; não há rota de remoção)
+	auditHandler := handlers.NewAuditHandler(s.auditLog)
+	api.GET("/audit", auditHandler.List)
+	api.GET("/audit/verify", auditHandler.Verify)
+	api.GET("/audit/export", auditHandler.Export)
+}
+
+// runAuditRetention aplica a retenção do audit log na inicialização e a cada 24h
+func (s *Server) runAuditRetention(ctx context.Context) {
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+	for {
+		if result, err := s.auditLog.Prune(time.Now()); err != nil {
+			fmt.Printf("⚠️  Falha na retenção do audit log: %v\n", err)
+		} else if len(result.Removed) > 0 {
+			fmt.Printf("🧾 Retenção do audit log: %d segmento(s) removido(s)\n", len(result.Removed))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
 }
 
 // setupStatic configura servir arquivos estáticos
@@ -387,8 +679,8 @@ func (s *Server) setupStatic() {
 	s.router.StaticFileFS("/robots.txt", "robots.txt", http.FS(staticFS))
 	s.router.StaticFileFS("/placeholder.svg", "placeholder.svg", http.FS(staticFS))
 
-	// Rota raiz serve index.html (sem cache)
-	s.router.GET("/", func(c *gin.Context) {
+	// Rota raiz serve index.html (sem cache); com OIDC, exige login
+	s.router.GET("/", s.auth.RequireLogin(), func(c *gin.Context) {
 		data, err := staticFiles.ReadFile("static/index.html")
 		if err != nil {
 			c.String(404, "Frontend not found - run 'make web-build' first")
@@ -413,6 +705,10 @@ func (s *Server) setupStatic() {
 			return
 		}
 
+		if s.auth.LoginRedirect(c) {
+			return
+		}
+
 		// SPA fallback para outras rotas (sem cache)
 		data, err := staticFiles.ReadFile("static/index.html")
 		if err != nil {
@@ -457,6 +753,15 @@ func (s *Server) autoShutdown() {
 		return
 	}
 
+	// Agendamentos pendentes mantêm o servidor ativo mesmo sem páginas conectadas
+	if s.scheduler != nil && s.scheduler.HasPending() {
+		fmt.Println("⏰ Agendamentos pendentes, shutdown por inatividade adiado")
+		s.timerMutex.Lock()
+		s.shutdownTimer = time.AfterFunc(20*time.Minute, s.autoShutdown)
+		s.timerMutex.Unlock()
+		return
+	}
+
 	fmt.Println("\n╔════════════════════════════════════════════════════════════╗")
 	fmt.Println("║             AUTO-SHUTDOWN POR INATIVIDADE                 ║")
 	fmt.Println("╚════════════════════════════════════════════════════════════╝")
@@ -479,17 +784,32 @@ func (s *Server) Start() error {
 	fmt.Printf("\n")
 	fmt.Printf("🌐 Server URL:    http://localhost%s\n", addr)
 	fmt.Printf("📍 API Endpoint:  http://localhost%s/api/v1\n", addr)
-	fmt.Printf("🔐 Auth Token:    %s\n", s.token)
+	if s.auth.OIDCEnabled() {
+		fmt.Printf("🔐 Login OIDC:    http://localhost%s/auth/login\n", addr)
+	}
+	if s.auth.StaticTokenEnabled() {
+		fmt.Printf("🔐 Auth Token:    %s\n", s.token)
+	}
 	fmt.Printf("❤️  Health Check: http://localhost%s/health\n", addr)
-	fmt.Printf("💓 Heartbeat:     POST http://localhost%s/heartbeat\n", addr)
-	fmt.Printf("\n")
-	fmt.Println("📝 Exemplo de uso:")
-	fmt.Printf("   curl -H 'Authorization: Bearer %s' http://localhost%s/api/v1/clusters\n", s.token, addr)
+	if s.inCluster {
+		fmt.Printf("☸️  Probes:       http://localhost%s/livez | /readyz\n", addr)
+	} else {
+		fmt.Printf("💓 Heartbeat:     POST http://localhost%s/heartbeat\n", addr)
+	}
+	fmt.Printf("📈 Metrics:       http://localhost%s/metrics\n", addr)
 	fmt.Printf("\n")
+	if s.auth.StaticTokenEnabled() {
+		fmt.Println("📝 Exemplo de uso:")
+		fmt.Printf("   curl -H 'Authorization: Bearer %s' http://localhost%s/api/v1/clusters\n", s.token, addr)
+		fmt.Printf("\n")
+	}
 	fmt.Println("🚀 Servidor iniciado! Pressione Ctrl+C para parar.")
 	fmt.Printf("\n")
 
-	return s.router.Run(addr)
+	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
 }
 
 // Shutdown encerra gracefully o servidor e componentes
@@ -498,7 +818,8 @@ func (s *Server) Shutdown() error {
 	fmt.Println("║              GRACEFUL SHUTDOWN INICIADO                   ║")
 	fmt.Println("╚════════════════════════════════════════════════════════════╝")
 
-	// 1. Parar timer de auto-shutdown
+	// 1. Readiness passa a falhar (o Service deixa de enviar tráfego) e timer de auto-shutdown para
+	s.draining.Store(true)
 	s.timerMutex.Lock()
 	if s.shutdownTimer != nil {
 		s.shutdownTimer.Stop()
@@ -506,35 +827,39 @@ func (s *Server) Shutdown() error {
 	}
 	s.timerMutex.Unlock()
 
-	// 2. Cancelar contexto de monitoring
+	// 2. Aguardar a remoção dos endpoints e drenar requisições em andamento
+	if s.shutdownDelay > 0 {
+		fmt.Printf("⏳ Aguardando %s para remoção dos endpoints...\n", s.shutdownDelay)
+		time.Sleep(s.shutdownDelay)
+	}
+	// Streams SSE nunca ficam ociosos: encerrá-los antes para o Shutdown não esperar o timeout
+	s.eventHub.Close()
+	s.eventWatcher.Close()
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer drainCancel()
+	if err := s.httpServer.Shutdown(drainCtx); err != nil {
+		fmt.Printf("⚠️  Requisições não concluídas em %s: %v\n", s.shutdownTimeout, err)
+	} else {
+		fmt.Println("✓ Servidor HTTP encerrado (requisições concluídas)")
+	}
+
+	// 3. Cancelar contexto de monitoring (scheduler, notificações, retenção do audit log)
 	if s.monitoringCancel != nil {
 		s.monitoringCancel()
 		fmt.Println("✓ Contexto de monitoring cancelado")
 	}
 
-	// 3. Parar monitoring engine (fecha port-forwards e SQLite)
+	// 4. Parar monitoring engine (fecha port-forwards e SQLite); o estado salvo
+	// (HPAs, intervalo, running/paused) é mantido para o próximo startup
 	if s.monitoringEngine != nil {
 		fmt.Println("⏳ Parando monitoring engine...")
-		if err := s.monitoringEngine.Stop(); err != nil {
+		if err := s.monitoringEngine.Close(); err != nil {
 			fmt.Printf("⚠️  Erro ao parar engine: %v\n", err)
 		} else {
 			fmt.Println("✓ Monitoring engine parado")
 		}
 	}
 
-	// 4. Salvar targets uma última vez
-	homeDir, _ := os.UserHomeDir()
-	baseDir := filepath.Join(homeDir, ".k8s-hpa-manager")
-	targetsFile := filepath.Join(baseDir, "monitoring-targets.json")
-	if s.monitoringEngine != nil {
-		targets := s.monitoringEngine.GetTargets()
-		if err := saveTargetsToFile(targetsFile, targets); err != nil {
-			fmt.Printf("⚠️  Erro ao salvar targets: %v\n", err)
-		} else {
-			fmt.Println("✓ Targets salvos")
-		}
-	}
-
 	// 5. Fechar canais
 	close(s.snapshotChan)
 	close(s.anomalyChan)
@@ -545,65 +870,19 @@ func (s *Server) Shutdown() error {
 	return nil
 }
 
-// saveTargetsToFile salva targets em arquivo JSON
-func saveTargetsToFile(filename string, targets []scanner.ScanTarget) error {
-	data, err := json.Marshal(targets)
-	if err != nil {
-		return fmt.Errorf("failed to marshal targets: %w", err)
-	}
-
-	// Criar diretório se não existir
-	dir := filepath.Dir(filename)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
-	if err := os.WriteFile(filename, data, 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
-	}
-
-	return nil
-}
-
-// loadTargetsFromFile carrega targets de arquivo JSON
-func loadTargetsFromFile(filename string) ([]scanner.ScanTarget, error) {
-	data, err := os.ReadFile(filename)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return []scanner.ScanTarget{}, nil // Arquivo não existe = sem targets
-		}
-		return nil, fmt.Errorf("failed to read file: %w", err)
-	}
-
-	var targets []scanner.ScanTarget
-	if err := json.Unmarshal(data, &targets); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal targets: %w", err)
+// monitoredClusters clusters com targets no monitoring engine (vazio com o engine parado ou pausado)
+func (s *Server) monitoredClusters() []string {
+	if s.monitoringEngine == nil || !s.monitoringEngine.IsRunning() || s.monitoringEngine.IsPaused() {
+		return nil
 	}
 
-	return targets, nil
-}
-
-// startTargetsPersistence persiste targets periodicamente em arquivo
-func (s *Server) startTargetsPersistence(filename string, interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			// Buscar targets atuais do engine
-			targets := s.monitoringEngine.GetTargets()
-
-			// Salvar em arquivo
-			if err := saveTargetsToFile(filename, targets); err != nil {
-				fmt.Printf("⚠️  Erro ao salvar targets: %v\n", err)
-			}
-
-		case <-s.monitoringCtx.Done():
-			// Servidor sendo encerrado, salvar uma última vez
-			targets := s.monitoringEngine.GetTargets()
-			saveTargetsToFile(filename, targets)
-			return
+	seen := make(map[string]bool)
+	var clusters []string
+	for _, target := range s.monitoringEngine.GetTargets() {
+		if !seen[target.Cluster] {
+			seen[target.Cluster] = true
+			clusters = append(clusters, target.Cluster)
 		}
 	}
+	return clusters
 }
@@ -7,10 +7,19 @@ import (
 	"os/exec"
 	"strings"
 	"time"
+
+	"k8s-hpa-manager/internal/azure"
 )
 
 // ValidateAzureAuth valida Azure CLI e faz login se necessário (igual ao TUI)
 func ValidateAzureAuth() error {
+	// Modo servidor com workload identity: valida a identidade do pod, sem Azure CLI
+	if azure.WorkloadIdentityEnabled() {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		return azure.ValidateWorkloadIdentity(ctx)
+	}
+
 	// Criar contexto com timeout de 5 segundos
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()